@@ -1,9 +1,27 @@
 package domain
 
-//ASNAllocationPool holds the ASN entries
-//that are available in the pool to be allocated
-type ASNAllocationPool struct {
+//Allocation pool types, a pool is identified by the fabric, its type and its name
+//(the device role for ASN pools and the IP type for IP and IP pair pools)
+const (
+	PoolTypeASN    = "ASN"
+	PoolTypeIP     = "IP"
+	PoolTypeIPPair = "IPPair"
+)
+
+//AllocationRange holds a range of values that are available in a pool
+//to be allocated. StartValue and EndValue are both inclusive.
+type AllocationRange struct {
 	ID         uint
+	FabricID   uint
+	PoolType   string
+	PoolName   string
+	StartValue uint64
+	EndValue   uint64
+}
+
+//ASNAllocationPool holds an ASN
+//that is available in the pool to be allocated
+type ASNAllocationPool struct {
 	FabricID   uint
 	ASN        uint64
 	DeviceRole string
@@ -19,10 +37,9 @@ type UsedASN struct {
 	DeviceRole string
 }
 
-//IPAllocationPool holds the IP Address
-//that are available for allocation
+//IPAllocationPool holds an IP Address
+//that is available for allocation
 type IPAllocationPool struct {
-	ID        uint
 	FabricID  uint
 	IPAddress string
 	IPType    string
@@ -39,10 +56,9 @@ type UsedIP struct {
 	InterfaceID uint
 }
 
-//IPPairAllocationPool holds the pair of IPAddress
-//that are available for allocation
+//IPPairAllocationPool holds a pair of IPAddress
+//that is available for allocation
 type IPPairAllocationPool struct {
-	ID           uint
 	FabricID     uint
 	IPAddressOne string
 	IPAddressTwo string
@@ -62,3 +78,25 @@ type UsedIPPair struct {
 	InterfaceOneID uint
 	InterfaceTwoID uint
 }
+
+//Kinds of values an operator can pin to a device ahead of its configuration
+const (
+	PinTypeASN      = "asn"
+	PinTypeLoopback = "loopback"
+	PinTypeP2P      = "p2p"
+)
+
+//AllocationPin holds a value of an allocation pool pinned by the operator to a device,
+//or to an interface of the device for the P2P pool. The device need not be registered yet,
+//so it is identified by its IP Address. PoolValue is the value as stored in the pool.
+type AllocationPin struct {
+	ID            uint
+	FabricID      uint
+	DeviceIP      string
+	PinType       string
+	InterfaceName string
+	PoolType      string
+	PoolName      string
+	Value         string
+	PoolValue     uint64
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+)
+
+//ErrCablingPlanInvalid implies a link of the cabling plan is incomplete or invalid, or a port is cabled twice
+var ErrCablingPlanInvalid = errors.New("Invalid cabling plan")
+
+//CablingLink is a link of the cabling plan of a fabric, from a port of a device to a port of another device.
+//The devices need not be registered yet, so they are identified by their IP Address.
+type CablingLink struct {
+	ID               uint
+	FabricID         uint
+	DeviceOneIP      string
+	InterfaceOneName string
+	DeviceTwoIP      string
+	InterfaceTwoName string
+}
+
+//CablingMismatch is the difference between the links discovered by LLDP and the cabling plan of a fabric
+type CablingMismatch struct {
+	//Miscabled lists the planned ports connected to another port than the planned one
+	Miscabled []string
+	//Missing lists the planned links neither of whose ports is connected
+	Missing []string
+	//Unexpected lists the discovered links between ports which are not in the plan
+	Unexpected []string
+}
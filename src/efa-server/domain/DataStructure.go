@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 //ExecutionLog represents the log w.r.t the REST API executions
 type ExecutionLog struct {
 	ID        uint
@@ -11,3 +13,26 @@ type ExecutionLog struct {
 	EndTime   string
 	Duration  string
 }
+
+//SchemaMigration represents a numbered migration of the database schema
+type SchemaMigration struct {
+	Version     uint
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+//SchemaMigrationStatus represents the version of the database schema and the migrations leading to it
+type SchemaMigrationStatus struct {
+	CurrentVersion uint
+	LatestVersion  uint
+	Migrations     []SchemaMigration
+}
+
+//DatabaseBackup represents a backup of the application database
+type DatabaseBackup struct {
+	Name          string
+	SchemaVersion uint
+	CreatedAt     time.Time
+	Size          int64
+}
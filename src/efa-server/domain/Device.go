@@ -17,6 +17,7 @@ type Device struct {
 	LLDPS               []LLDP
 	Interfaces          []Interface
 	IsPasswordEncrypted bool
+	MaintenanceMode     bool
 }
 
 //DeviceDetail holds the Device details
@@ -27,6 +28,30 @@ type DeviceDetail struct {
 	Model           string
 }
 
+//DeviceSettings holds the per-device overrides of the fabric settings.
+//An empty field means the fabric-wide value applies to the device.
+type DeviceSettings struct {
+	ID                            uint
+	FabricID                      uint
+	DeviceID                      uint
+	MTU                           string
+	IPMTU                         string
+	BFDEnable                     string
+	BFDTx                         string
+	BFDRx                         string
+	BFDMultiplier                 string
+	MaxPaths                      string
+	AllowASIn                     string
+	LeafPeerGroup                 string
+	SpinePeerGroup                string
+	ArpAgingTimeout               string
+	MacAgingTimeout               string
+	MacAgingConversationalTimeout string
+	MacMoveLimit                  string
+	DuplicateMacTimer             string
+	DuplicateMaxTimerMaxCount     string
+}
+
 //DeviceOperations represents
 /*type DeviceOperations interface {
 	AddDevice(FabricName string, IPAddress string, UserID string, Password string) (string, error)
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+)
+
+//Types of the execution events
+const (
+	//ExecutionEventExecution reports the start and the end of an execution
+	ExecutionEventExecution = "execution"
+
+	//ExecutionEventDevice reports the progress of a device through a stage of an execution
+	ExecutionEventDevice = "device"
+)
+
+//Statuses of the executions and of the stages of the devices
+const (
+	ExecutionEventRunning   = "running"
+	ExecutionEventSucceeded = "succeeded"
+	ExecutionEventFailed    = "failed"
+)
+
+//Stages of the devices reported by the execution events, in their order
+const (
+	//StageDiscoveryInterfaces fetches and enables the interfaces of the device
+	StageDiscoveryInterfaces = "discovery:interfaces"
+
+	//StageDiscoveryLLDP fetches the LLDP neighbors of the device
+	StageDiscoveryLLDP = "discovery:lldp"
+
+	//StageDiscoveryTopology builds the links of the device and generates its configuration
+	StageDiscoveryTopology = "discovery:topology"
+
+	//StageDiscoveryNeighbors generates the interface and the BGP neighbor configuration of the device
+	StageDiscoveryNeighbors = "discovery:neighbors"
+
+	//StageSwitchConfigure pushes the underlay and the overlay configuration to the device
+	StageSwitchConfigure = "switch configure"
+
+	//StageMctCluster configures the MCT cluster of the device
+	StageMctCluster = "mct cluster"
+
+	//StageOverlay configures the overlay gateway of the device
+	StageOverlay = "overlay"
+
+	//StagePersist saves the running configuration of the device
+	StagePersist = "persist"
+
+	//StageHealth checks the operational state of the device once the fabric is configured
+	StageHealth = "health"
+)
+
+//ExecutionEvent is a progress event of an execution, streamed while the execution runs
+type ExecutionEvent struct {
+	//Sequence orders the events of an execution, starting at 1
+	Sequence    uint64    `json:"sequence"`
+	ExecutionID string    `json:"execution_id"`
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	Command     string    `json:"command,omitempty"`
+	Device      string    `json:"device,omitempty"`
+	Stage       string    `json:"stage,omitempty"`
+	//Step is the action of the stage the device is running
+	Step    string        `json:"step,omitempty"`
+	Status  string        `json:"status"`
+	Message string        `json:"message,omitempty"`
+	Errors  []DeviceError `json:"errors,omitempty"`
+}
+
+//Finished returns whether the event ends its execution
+func (Event ExecutionEvent) Finished() bool {
+	return Event.Type == ExecutionEventExecution && Event.Status != ExecutionEventRunning
+}
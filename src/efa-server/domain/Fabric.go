@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"time"
 )
 
 const (
@@ -20,6 +21,14 @@ const (
 	NonCLOSFabricType = "non-clos"
 )
 
+const (
+	//BGPAuthTypeMD5 represents TCP MD5 signature authentication of BGP sessions
+	BGPAuthTypeMD5 = "md5"
+
+	//BGPPasswordNone is the value used to remove the authentication from a peer group
+	BGPPasswordNone = "none"
+)
+
 var (
 	//ErrFabricNotFound implies the input fabric is not found
 	ErrFabricNotFound = errors.New("A fabric with the specified name was not found")
@@ -32,6 +41,21 @@ var (
 
 	//ErrFabricInternalError implies an internal error
 	ErrFabricInternalError = errors.New("Internal error")
+
+	//ErrFabricDeviceInMaintenance implies a device to be configured is in maintenance mode
+	ErrFabricDeviceInMaintenance = errors.New("A device of the fabric is in maintenance mode")
+
+	//ErrDeviceNotFound implies the input device is not registered with the fabric
+	ErrDeviceNotFound = errors.New("A device with the specified IP Address was not found")
+
+	//ErrBackupNotFound implies the input database backup does not exist
+	ErrBackupNotFound = errors.New("A backup with the specified name was not found")
+
+	//ErrGenerationNotFound implies the input configuration generation does not exist
+	ErrGenerationNotFound = errors.New("A configuration generation with the specified number was not found")
+
+	//ErrSettingChangeNotFound implies the input fabric setting change does not exist
+	ErrSettingChangeNotFound = errors.New("A fabric setting change with the specified id was not found")
 )
 
 //Fabric represents DC Fabric table
@@ -99,6 +123,28 @@ type FabricProperties struct {
 	// NON ClOS Fields
 	RackPeerEBGPGroup string `json:"rack_peer_ebgp_group"`
 	RackPeerOvgGroup  string `json:"rack_peer_overlay_evpn_group"`
+
+	//BGP Authentication Fields, passwords are stored encrypted
+	BGPAuthType               string `json:"bgp_auth_type"`
+	PeerGroupPassword         string `json:"peer_group_password"`
+	MctL2EvpnPassword         string `json:"mct_l2evpn_password"`
+	RackPeerEBGPGroupPassword string `json:"rack_peer_ebgp_group_password"`
+	RackPeerOvgGroupPassword  string `json:"rack_peer_overlay_evpn_group_password"`
+
+	//PoolWarningThreshold is the utilization in percent above which a pool is reported by validate and configure
+	PoolWarningThreshold string `json:"pool_warning_threshold"`
+}
+
+//FabricSettingChange is a change of a fabric setting, recorded by the settings updates and rollbacks
+type FabricSettingChange struct {
+	ID          uint
+	FabricID    uint
+	Setting     string
+	OldValue    string
+	NewValue    string
+	UserName    string
+	ExecutionID string
+	ChangedAt   time.Time
 }
 
 /*type FabricOperations interface {
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+)
+
+//Health checks of the devices of a fabric
+const (
+	//HealthCheckBGP checks that the IPv4 BGP neighbours of the device are established
+	HealthCheckBGP = "bgp"
+
+	//HealthCheckEVPN checks that the L2VPN EVPN neighbours of the device are established
+	HealthCheckEVPN = "evpn"
+
+	//HealthCheckBFD checks that the BFD sessions towards the BGP neighbours of the device are up
+	HealthCheckBFD = "bfd"
+
+	//HealthCheckMCT checks the management cluster, the MCT cluster and the MCT peer of the device
+	HealthCheckMCT = "mct"
+
+	//HealthCheckOverlay checks that the overlay gateway has a tunnel up towards each VTEP of the fabric
+	HealthCheckOverlay = "overlay"
+
+	//HealthCheckRoutes checks that the routing table holds a route to each loopback of the fabric
+	HealthCheckRoutes = "routes"
+)
+
+//HealthChecks lists the health checks in the order they are reported
+var HealthChecks = []string{HealthCheckBGP, HealthCheckEVPN, HealthCheckBFD, HealthCheckMCT, HealthCheckOverlay,
+	HealthCheckRoutes}
+
+//Results of the health checks
+const (
+	HealthPass    = "pass"
+	HealthFail    = "fail"
+	HealthSkipped = "skipped"
+)
+
+//HealthCheck is the result of a health check of a device
+type HealthCheck struct {
+	Name   string
+	Status string
+	//Message describes the failure, or the state checked when the check passed
+	Message string
+}
+
+//DeviceHealth holds the results of the health checks of a device
+type DeviceHealth struct {
+	IPAddress string
+	Role      string
+	Healthy   bool
+	Checks    []HealthCheck
+}
+
+//FabricHealth is the operational health of the devices of a fabric compared with their expected state
+type FabricHealth struct {
+	FabricName string
+	CheckedAt  time.Time
+	Healthy    bool
+	Devices    []DeviceHealth
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+//Events notified to the subscriptions
+const (
+	//EventExecutionStarted is notified when a request recorded as an execution is received
+	EventExecutionStarted = "execution.started"
+
+	//EventExecutionCompleted is notified when an execution succeeds
+	EventExecutionCompleted = "execution.completed"
+
+	//EventExecutionFailed is notified when an execution fails, along with the errors of the devices
+	EventExecutionFailed = "execution.failed"
+
+	//EventValidationMissingLinks is notified when the validation of a fabric finds missing links
+	EventValidationMissingLinks = "validation.missing_links"
+
+	//EventDeviceCredentialsFailed is notified when the server fails to log into a device with its credentials
+	EventDeviceCredentialsFailed = "device.credentials_failed"
+
+	//EventNotificationTest is sent on request to check a subscription
+	EventNotificationTest = "notification.test"
+)
+
+//NotificationEvents lists the events a subscription can subscribe to
+var NotificationEvents = []string{EventExecutionStarted, EventExecutionCompleted, EventExecutionFailed,
+	EventValidationMissingLinks, EventDeviceCredentialsFailed}
+
+//MaxNotificationRetries is the maximum number of retries of the delivery of an event
+const MaxNotificationRetries = 10
+
+var (
+	//ErrSubscriptionNotFound implies the input notification subscription does not exist
+	ErrSubscriptionNotFound = errors.New("A notification subscription with the specified id was not found")
+
+	//ErrSubscriptionInvalid implies the URL, the events or the retries of a subscription are invalid
+	ErrSubscriptionInvalid = errors.New("Invalid notification subscription")
+
+	//ErrNotificationDeliveryFailed implies the endpoint of a subscription did not accept an event
+	ErrNotificationDeliveryFailed = errors.New("The delivery of the notification failed")
+)
+
+//NotificationSubscription is a webhook endpoint receiving the events of the server.
+//The events are signed with the secret when there is one.
+type NotificationSubscription struct {
+	ID        uint
+	URL       string
+	Secret    string
+	Events    []string
+	Retries   uint
+	CreatedAt time.Time
+}
+
+//Subscribes returns whether the subscription receives the event, a subscription without events receives all
+func (Subscription NotificationSubscription) Subscribes(Event string) bool {
+	if len(Subscription.Events) == 0 {
+		return true
+	}
+	for _, Subscribed := range Subscription.Events {
+		if Subscribed == Event {
+			return true
+		}
+	}
+	return false
+}
+
+//DeviceError is the failure of an operation on a device
+type DeviceError struct {
+	Device    string `json:"device"`
+	Operation string `json:"operation,omitempty"`
+	Message   string `json:"message"`
+}
+
+//NotificationEvent is the body posted to the endpoints of the subscriptions
+type NotificationEvent struct {
+	ID           string        `json:"id"`
+	Event        string        `json:"event"`
+	Time         time.Time     `json:"time"`
+	Fabric       string        `json:"fabric,omitempty"`
+	ExecutionID  string        `json:"execution_id,omitempty"`
+	Command      string        `json:"command,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Device       string        `json:"device,omitempty"`
+	Message      string        `json:"message,omitempty"`
+	MissingLinks []string      `json:"missing_links,omitempty"`
+	Errors       []DeviceError `json:"errors,omitempty"`
+}
+
+//DeviceCredentialsError is the failure to log into a device with its credentials, its message is the one of Err
+type DeviceCredentialsError struct {
+	Device string
+	Err    error
+}
+
+func (e *DeviceCredentialsError) Error() string {
+	return e.Err.Error()
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+)
+
+//ErrPreflightOverrideInvalid implies an overridden pre-flight check is unknown
+var ErrPreflightOverrideInvalid = errors.New("Invalid pre-flight check override")
+
+//Pre-flight checks of the devices of a fabric, run by validate before any configuration is pushed
+const (
+	//PreflightCheckConnection checks that the device can be reached to run the other checks
+	PreflightCheckConnection = "connection"
+
+	//PreflightCheckSpeed checks that both ends of a link run at the same speed
+	PreflightCheckSpeed = "speed"
+
+	//PreflightCheckFirmware checks that the spines run the same firmware and that the devices of an MCT pair are
+	//compatible
+	PreflightCheckFirmware = "firmware"
+
+	//PreflightCheckMTU checks that both ends of a link run the same MTU, and that no MTU configured on the
+	//interfaces of the links replaces the MTU setting of the device
+	PreflightCheckMTU = "mtu"
+
+	//PreflightCheckAdminDown checks that the interfaces of the links are not shut down
+	PreflightCheckAdminDown = "admin-down"
+
+	//PreflightCheckIPConfig checks that configure does not replace an IP address configured on an interface
+	PreflightCheckIPConfig = "ip-config"
+
+	//PreflightCheckLocalAS checks that configure does not replace the local AS of the router bgp of the device
+	PreflightCheckLocalAS = "local-as"
+)
+
+//PreflightChecks lists the pre-flight checks which can be overridden
+var PreflightChecks = []string{PreflightCheckConnection, PreflightCheckSpeed, PreflightCheckMTU,
+	PreflightCheckFirmware, PreflightCheckAdminDown, PreflightCheckIPConfig, PreflightCheckLocalAS}
+
+//Severities of the pre-flight findings, an error fails the validation unless its check is overridden
+const (
+	PreflightError   = "error"
+	PreflightWarning = "warning"
+)
+
+//PreflightFinding is an inconsistency found by a pre-flight check
+type PreflightFinding struct {
+	Check    string
+	Severity string
+	//Device and Interface are empty when the finding is not about a single device or interface
+	Device     string
+	Interface  string
+	Message    string
+	Overridden bool
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 const (
 	//ConfigCreate represents a create config operation
 	ConfigCreate = "CREATE CONFIG"
@@ -75,6 +77,59 @@ type RemoteNeighborSwitchConfig struct {
 	ConfigType        string
 }
 
+//Kinds and changes of the differences between two configuration generations, or two fabric settings
+const (
+	ChangeKindDevice    = "Device"
+	ChangeKindInterface = "Interface"
+	ChangeKindNeighbor  = "BGP Neighbor"
+	ChangeKindSetting   = "Setting"
+	ChangeKindCluster   = "MCT Cluster"
+	ChangeKindEVPN      = "EVPN Neighbor"
+
+	ChangeAdded   = "Added"
+	ChangeRemoved = "Removed"
+	ChangeUpdated = "Changed"
+)
+
+//ConfigGeneration is the intended configuration of the fabric stored by a successful configure.
+//A generation is never modified, the next configure stores a new generation.
+type ConfigGeneration struct {
+	ID          uint
+	FabricID    uint
+	Generation  uint
+	ExecutionID string
+	CreatedAt   time.Time
+	//Settings are the fabric settings of the generation, they are not recorded by the older generations
+	Settings *FabricProperties
+	Devices  []DeviceIntendedConfig
+}
+
+//DeviceIntendedConfig is the intended configuration of a device in a configuration generation
+type DeviceIntendedConfig struct {
+	DeviceID       uint
+	DeviceIP       string
+	Role           string
+	LocalAS        string
+	LoopbackIP     string
+	VTEPLoopbackIP string
+	Interfaces     []InterfaceSwitchConfig
+	Neighbors      []RemoteNeighborSwitchConfig
+	//Settings are the per-device overrides of the fabric settings
+	Settings      DeviceSettings
+	Clusters      []MctClusterConfig
+	EVPNNeighbors []RackEvpnNeighbors
+}
+
+//ConfigChange is a difference between the intended configurations of two generations
+type ConfigChange struct {
+	DeviceIP string
+	Kind     string
+	Name     string
+	Change   string
+	Old      string
+	New      string
+}
+
 //RackEvpnNeighbors represent evpn neighbor per rack
 type RackEvpnNeighbors struct {
 	ID             uint
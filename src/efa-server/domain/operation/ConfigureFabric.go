@@ -80,6 +80,18 @@ type ConfigSwitch struct {
 	// NON ClOS Fields
 	RackPeerEBGPGroup string
 	RackPeerOvgGroup  string
+
+	//BGP Authentication Fields
+	BGPAuthType           string
+	PeerGroupPassword     string
+	EvpnPeerGroupPassword string
+	MctPeerPassword       string
+	//MctPeerAddresses holds the BGP neighbours towards the MCT peer, used for password rotation
+	//and for keeping the MCT peer sessions up during maintenance mode
+	MctPeerAddresses []string
+
+	//MaintenanceMode is set when the switch is drained, its peer-groups are kept shut
+	MaintenanceMode bool
 }
 
 //ConfigBgpNeighbor is used by the device actions to configure BGP Neighbor
@@ -190,4 +202,6 @@ type DataPlaneClusterMemberNode struct {
 	NodePeerASN        string
 	NodePeerEncapType  string
 	NodePeerBFDEnabled string
+	NodePeerAuthType   string
+	NodePeerPassword   string
 }
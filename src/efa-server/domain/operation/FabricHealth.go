@@ -0,0 +1,55 @@
+package operation
+
+//FabricHealthRequest is a request Object for collecting the operational state of the switches of a Fabric
+type FabricHealthRequest struct {
+	FabricName string
+	Hosts      []SwitchIdentity
+	//Overlay is set when the overlay gateway is configured on the leaves
+	Overlay bool
+}
+
+//FabricHealthResponse holds the operational state of each switch of the Fabric
+type FabricHealthResponse struct {
+	FabricName     string
+	SwitchResponse []SwitchHealthResponse
+}
+
+//SwitchHealthResponse is the operational state collected from a switch
+type SwitchHealthResponse struct {
+	Host          string
+	Role          string
+	BgpNeighbors  []ConfigBgpNeighbor
+	EvpnNeighbors []ConfigBgpNeighbor
+	BfdSessions   []BfdSession
+	MgmtCluster   MgmtClusterStatus
+	Cluster       ClusterStatus
+	Tunnels       []TunnelStatus
+	RouteCount    int
+	//Errors holds the failure of the collection of each state, by the name of the health check
+	Errors map[string]string
+}
+
+//BfdSession is the operational state of a BFD session
+type BfdSession struct {
+	NeighborAddress string
+	Interface       string
+	State           string
+}
+
+//ClusterStatus is the operational state of the MCT data plane cluster
+type ClusterStatus struct {
+	Name      string
+	ID        string
+	PeerIP    string
+	PeerState string
+	State     string
+}
+
+//TunnelStatus is the operational state of a tunnel of the overlay gateway
+type TunnelStatus struct {
+	ID          string
+	Source      string
+	Destination string
+	AdminState  string
+	OperState   string
+}
@@ -6,7 +6,10 @@ import (
 	"efa-server/infra/constants"
 	"efa-server/infra/database"
 	"efa-server/infra/util"
+	"encoding/json"
+	"errors"
 	"github.com/jinzhu/gorm"
+	"strings"
 )
 
 //DatabaseRepository represents the Application Database Repository
@@ -28,6 +31,56 @@ func (dbRepo *DatabaseRepository) Backup() error {
 	return dbRepo.Database.BackupDB()
 }
 
+//GetSchemaMigrationStatus returns the version of the database schema and the migrations known to the application
+func (dbRepo *DatabaseRepository) GetSchemaMigrationStatus() (domain.SchemaMigrationStatus, error) {
+	Status := domain.SchemaMigrationStatus{LatestVersion: database.LatestSchemaVersion()}
+	var err error
+	if Status.CurrentVersion, err = dbRepo.Database.SchemaVersion(); err != nil {
+		return Status, err
+	}
+	Migrations, err := dbRepo.Database.MigrationStatus()
+	for _, DBMigration := range Migrations {
+		var Migration domain.SchemaMigration
+		Copy(&Migration, DBMigration)
+		Status.Migrations = append(Status.Migrations, Migration)
+	}
+	return Status, err
+}
+
+//CreateBackup saves the database to a new backup, the oldest backups past the retention count are deleted
+func (dbRepo *DatabaseRepository) CreateBackup() (domain.DatabaseBackup, error) {
+	var Backup domain.DatabaseBackup
+	Info, err := dbRepo.Database.CreateBackup()
+	Copy(&Backup, Info)
+	return Backup, err
+}
+
+//GetBackups returns the backups of the database, the most recent first
+func (dbRepo *DatabaseRepository) GetBackups() ([]domain.DatabaseBackup, error) {
+	Backups := make([]domain.DatabaseBackup, 0)
+	Infos, err := dbRepo.Database.ListBackups()
+	for _, Info := range Infos {
+		var Backup domain.DatabaseBackup
+		Copy(&Backup, Info)
+		Backups = append(Backups, Backup)
+	}
+	return Backups, err
+}
+
+//RestoreBackup replaces the database with the backup and reconnects to the restored database
+func (dbRepo *DatabaseRepository) RestoreBackup(Name string) (domain.DatabaseBackup, error) {
+	var Backup domain.DatabaseBackup
+	if dbRepo.Transaction != nil {
+		return Backup, errors.New("A transaction is in progress")
+	}
+	Info, err := dbRepo.Database.RestoreBackup(Name)
+	Copy(&Backup, Info)
+	if err == database.ErrBackupNotFound {
+		return Backup, domain.ErrBackupNotFound
+	}
+	return Backup, err
+}
+
 //OpenTransaction begins the database transaction
 func (dbRepo *DatabaseRepository) OpenTransaction() error {
 	dbRepo.Transaction = dbRepo.GetDBHandle().Begin()
@@ -85,14 +138,21 @@ func (dbRepo *DatabaseRepository) GetFabricProperties(FabricID uint) (domain.Fab
 
 	var DomainFabricProps domain.FabricProperties
 	Copy(&DomainFabricProps, DBFabricProps)
+	if err == nil {
+		err = cryptBGPPasswords(&DomainFabricProps, util.AesDecrypt)
+	}
 
 	return DomainFabricProps, err
 }
 
 //CreateFabricProperties creates the properties of the DC Fabric in the database
 func (dbRepo *DatabaseRepository) CreateFabricProperties(FabricProperties *domain.FabricProperties) error {
+	EncryptedFabricProps := *FabricProperties
+	if err := cryptBGPPasswords(&EncryptedFabricProps, util.AesEncrypt); err != nil {
+		return err
+	}
 	var DBFabricProps database.FabricProperties
-	Copy(&DBFabricProps, &FabricProperties)
+	Copy(&DBFabricProps, &EncryptedFabricProps)
 	err := dbRepo.GetDBHandle().Create(&DBFabricProps).Error
 	if err == nil {
 		FabricProperties.ID = DBFabricProps.ID
@@ -104,8 +164,12 @@ func (dbRepo *DatabaseRepository) CreateFabricProperties(FabricProperties *domai
 //UpdateFabricProperties updates the properties of the DC Fabric in the database
 func (dbRepo *DatabaseRepository) UpdateFabricProperties(FabricProperties *domain.FabricProperties) error {
 
+	EncryptedFabricProps := *FabricProperties
+	if err := cryptBGPPasswords(&EncryptedFabricProps, util.AesEncrypt); err != nil {
+		return err
+	}
 	var DBFabricProps database.FabricProperties
-	Copy(&DBFabricProps, &FabricProperties)
+	Copy(&DBFabricProps, &EncryptedFabricProps)
 	err := dbRepo.GetDBHandle().Save(&DBFabricProps).Error
 	if err == nil {
 		FabricProperties.ID = DBFabricProps.ID
@@ -113,65 +177,174 @@ func (dbRepo *DatabaseRepository) UpdateFabricProperties(FabricProperties *domai
 	return err
 }
 
-//CreateASN creates ASNAllocationPool instance in the database
-func (dbRepo *DatabaseRepository) CreateASN(ASNAllocationPool *domain.ASNAllocationPool) error {
-	var DBASNAllocationPool database.ASNAllocationPool
-	Copy(&DBASNAllocationPool, ASNAllocationPool)
-	err := dbRepo.GetDBHandle().Save(&DBASNAllocationPool).Error
+//cryptBGPPasswords applies the AES encrypt or decrypt function on the BGP passwords of the fabric properties
+func cryptBGPPasswords(FabricProperties *domain.FabricProperties, crypt func(key []byte, message string) (string, error)) error {
+	var err error
+	for _, Password := range []*string{&FabricProperties.PeerGroupPassword, &FabricProperties.MctL2EvpnPassword,
+		&FabricProperties.RackPeerEBGPGroupPassword, &FabricProperties.RackPeerOvgGroupPassword} {
+		if len(*Password) == 0 {
+			continue
+		}
+		if *Password, err = crypt(constants.AESEncryptionKey, *Password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//CreateAllocationRange creates an instance of AllocationRange in the database
+func (dbRepo *DatabaseRepository) CreateAllocationRange(Range *domain.AllocationRange) error {
+	var DBRange database.AllocationRange
+	Copy(&DBRange, Range)
+	err := dbRepo.GetDBHandle().Create(&DBRange).Error
 	if err == nil {
-		ASNAllocationPool.ID = DBASNAllocationPool.ID
+		Range.ID = DBRange.ID
 	}
 	return err
 }
 
-//DeleteASNPool deletes all ASNAllocationPool instances from the database
-func (dbRepo *DatabaseRepository) DeleteASNPool() error {
-	return dbRepo.GetDBHandle().Model(&database.ASNAllocationPool{}).Delete(&database.ASNAllocationPool{}).Error
+//UpdateAllocationRange updates the values of an instance of AllocationRange in the database
+func (dbRepo *DatabaseRepository) UpdateAllocationRange(Range *domain.AllocationRange) error {
+	var DBRange database.AllocationRange
+	Copy(&DBRange, Range)
+	return dbRepo.GetDBHandle().Save(&DBRange).Error
 }
 
-//DeleteASN deletes an input ASNAllocationPool instance from the database
-func (dbRepo *DatabaseRepository) DeleteASN(ASNAllocationPool *domain.ASNAllocationPool) error {
-	var DBASNAllocationPool database.ASNAllocationPool
-	Copy(&DBASNAllocationPool, &ASNAllocationPool)
-	err := dbRepo.GetDBHandle().Delete(&DBASNAllocationPool).Error
+//DeleteAllocationRange deletes an instance of AllocationRange from the database
+func (dbRepo *DatabaseRepository) DeleteAllocationRange(Range *domain.AllocationRange) error {
+	var DBRange database.AllocationRange
+	Copy(&DBRange, Range)
+	return dbRepo.GetDBHandle().Delete(&DBRange).Error
+}
+
+//GetAllocationRanges returns the AllocationRange instances of a pool, ordered by their values
+func (dbRepo *DatabaseRepository) GetAllocationRanges(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error) {
+	var DBRanges []database.AllocationRange
+	err := dbRepo.GetDBHandle().Order("start_value asc").
+		Where("fabric_id = ? AND pool_type = ? AND pool_name = ?", FabricID, PoolType, PoolName).Find(&DBRanges).Error
+
+	Ranges := make([]domain.AllocationRange, 0, len(DBRanges))
+	for _, DBRange := range DBRanges {
+		var Range domain.AllocationRange
+		Copy(&Range, DBRange)
+		Ranges = append(Ranges, Range)
+	}
+	return Ranges, err
+}
+
+//GetAllocationRangesOnWindow returns the AllocationRange instances of a pool which overlap with
+//the "StartValue, EndValue" window, ordered by their values
+func (dbRepo *DatabaseRepository) GetAllocationRangesOnWindow(FabricID uint, PoolType string, PoolName string,
+	StartValue uint64, EndValue uint64) ([]domain.AllocationRange, error) {
+	var DBRanges []database.AllocationRange
+	err := dbRepo.GetDBHandle().Order("start_value asc").
+		Where("fabric_id = ? AND pool_type = ? AND pool_name = ? AND start_value <= ? AND end_value >= ?",
+			FabricID, PoolType, PoolName, EndValue, StartValue).Find(&DBRanges).Error
+
+	Ranges := make([]domain.AllocationRange, 0, len(DBRanges))
+	for _, DBRange := range DBRanges {
+		var Range domain.AllocationRange
+		Copy(&Range, DBRange)
+		Ranges = append(Ranges, Range)
+	}
+	return Ranges, err
+}
+
+//GetAllocationRangeCountOnValue returns the count of AllocationRange instances containing the value, across the pools of a type
+func (dbRepo *DatabaseRepository) GetAllocationRangeCountOnValue(FabricID uint, PoolType string, Value uint64) (int64, error) {
+	var rangeCount int64
+	err := dbRepo.GetDBHandle().Model(database.AllocationRange{}).
+		Where("fabric_id = ? AND pool_type = ? AND start_value <= ? AND end_value >= ?", FabricID, PoolType, Value, Value).
+		Count(&rangeCount).Error
+	return rangeCount, err
+}
+
+//SaveAllocationPin creates or updates an instance of AllocationPin in the database
+func (dbRepo *DatabaseRepository) SaveAllocationPin(Pin *domain.AllocationPin) error {
+	var DBPin database.AllocationPin
+	Copy(&DBPin, Pin)
+	err := dbRepo.GetDBHandle().Save(&DBPin).Error
+	if err == nil {
+		Pin.ID = DBPin.ID
+	}
 	return err
 }
 
-//GetASNAndCountOnASNAndRole returns the number of instances of ASNAllocationPool and ASNAllocationPool from the database, for a given ASN and device role
-func (dbRepo *DatabaseRepository) GetASNAndCountOnASNAndRole(FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool) {
-	var asnCount int64
-	var asndb database.ASNAllocationPool
+//DeleteAllocationPin deletes an instance of AllocationPin from the database
+func (dbRepo *DatabaseRepository) DeleteAllocationPin(Pin *domain.AllocationPin) error {
+	var DBPin database.AllocationPin
+	Copy(&DBPin, Pin)
+	return dbRepo.GetDBHandle().Delete(&DBPin).Error
+}
+
+//GetAllocationPins returns the AllocationPin instances of a fabric, ordered by device and type
+func (dbRepo *DatabaseRepository) GetAllocationPins(FabricID uint) ([]domain.AllocationPin, error) {
+	var DBPins []database.AllocationPin
+	err := dbRepo.GetDBHandle().Order("device_ip asc, pin_type asc, interface_name asc").
+		Where("fabric_id = ?", FabricID).Find(&DBPins).Error
 
-	dbRepo.GetDBHandle().Model(database.ASNAllocationPool{}).
-		Where("fabric_id = ? AND asn = ? AND device_role = ?", FabricID, asn, role).Find(&asndb).Count(&asnCount)
-	var DomASN domain.ASNAllocationPool
-	Copy(&DomASN, asndb)
-	return asnCount, DomASN
+	Pins := make([]domain.AllocationPin, 0, len(DBPins))
+	for _, DBPin := range DBPins {
+		var Pin domain.AllocationPin
+		Copy(&Pin, DBPin)
+		Pins = append(Pins, Pin)
+	}
+	return Pins, err
 }
 
-//GetNextASNForRole returns the available ASNAllocationPool instance from the database
-func (dbRepo *DatabaseRepository) GetNextASNForRole(FabricID uint, role string) (domain.ASNAllocationPool, error) {
-	var dbasn database.ASNAllocationPool
-	err := dbRepo.GetDBHandle().Order("asn desc").Find(&dbasn, "device_role = ?", role).Error
-	var DomainASN domain.ASNAllocationPool
-	Copy(&DomainASN, &dbasn)
-	return DomainASN, err
+//GetAllocationPinsOnDevice returns the AllocationPin instances for a given "fabric and device IP Address" input
+func (dbRepo *DatabaseRepository) GetAllocationPinsOnDevice(FabricID uint, DeviceIP string) ([]domain.AllocationPin, error) {
+	var DBPins []database.AllocationPin
+	err := dbRepo.GetDBHandle().Order("pin_type asc, interface_name asc").
+		Where("fabric_id = ? AND device_ip = ?", FabricID, DeviceIP).Find(&DBPins).Error
+
+	Pins := make([]domain.AllocationPin, 0, len(DBPins))
+	for _, DBPin := range DBPins {
+		var Pin domain.AllocationPin
+		Copy(&Pin, DBPin)
+		Pins = append(Pins, Pin)
+	}
+	return Pins, err
 }
 
-//GetASNCountOnASN returns the count of available ASNAllocationPool instances from the database, for a given ASN
-func (dbRepo *DatabaseRepository) GetASNCountOnASN(FabricID uint, asn uint64) (int64, error) {
-	var asnCount int64
-	err := dbRepo.GetDBHandle().Model(domain.ASNAllocationPool{}).
-		Where("fabric_id = ? AND asn = ?", FabricID, asn).Count(&asnCount).Error
-	return asnCount, err
+//GetAllocationPinCountOnValue returns the count of AllocationPin instances holding the value of a pool
+func (dbRepo *DatabaseRepository) GetAllocationPinCountOnValue(FabricID uint, PoolType string, PoolName string, Value uint64) (int64, error) {
+	var pinCount int64
+	err := dbRepo.GetDBHandle().Model(database.AllocationPin{}).
+		Where("fabric_id = ? AND pool_type = ? AND pool_name = ? AND pool_value = ?", FabricID, PoolType, PoolName, Value).
+		Count(&pinCount).Error
+	return pinCount, err
 }
 
-//GetASNCountOnRole returns the count of available ASNAllocationPool instances from the database, for a given device role
-func (dbRepo *DatabaseRepository) GetASNCountOnRole(FabricID uint, role string) (int64, error) {
-	var asnCount int64
-	err := dbRepo.GetDBHandle().Model(domain.ASNAllocationPool{}).
-		Where("fabric_id = ? AND device_role = ?", FabricID, role).Count(&asnCount).Error
-	return asnCount, err
+//CreateCablingLink creates an instance of CablingLink in the database
+func (dbRepo *DatabaseRepository) CreateCablingLink(Link *domain.CablingLink) error {
+	var DBLink database.CablingLink
+	Copy(&DBLink, Link)
+	err := dbRepo.GetDBHandle().Create(&DBLink).Error
+	if err == nil {
+		Link.ID = DBLink.ID
+	}
+	return err
+}
+
+//DeleteCablingPlan deletes the CablingLink instances of a fabric
+func (dbRepo *DatabaseRepository) DeleteCablingPlan(FabricID uint) error {
+	return dbRepo.GetDBHandle().Where("fabric_id = ?", FabricID).Delete(database.CablingLink{}).Error
+}
+
+//GetCablingPlan returns the CablingLink instances of a fabric, ordered by device and interface
+func (dbRepo *DatabaseRepository) GetCablingPlan(FabricID uint) ([]domain.CablingLink, error) {
+	var DBLinks []database.CablingLink
+	err := dbRepo.GetDBHandle().Order("device_one_ip asc, interface_one_name asc, id asc").
+		Where("fabric_id = ?", FabricID).Find(&DBLinks).Error
+
+	Links := make([]domain.CablingLink, 0, len(DBLinks))
+	for _, DBLink := range DBLinks {
+		var Link domain.CablingLink
+		Copy(&Link, DBLink)
+		Links = append(Links, Link)
+	}
+	return Links, err
 }
 
 //CreateUsedASN creates an instance of UsedASN in the database
@@ -225,28 +398,19 @@ func (dbRepo *DatabaseRepository) GetUsedASNCountOnASNAndRole(FabricID uint, asn
 	return asnCount, err
 }
 
-//CreateIPEntry creates an instance of IPAllocationPool
-func (dbRepo *DatabaseRepository) CreateIPEntry(IPEntry *domain.IPAllocationPool) error {
-	var DBIPEntry database.IPAllocationPool
-	Copy(&DBIPEntry, IPEntry)
-	err := dbRepo.GetDBHandle().Save(&DBIPEntry).Error
-	if err == nil {
-		IPEntry.ID = DBIPEntry.ID
-	}
-	return err
-}
-
-//DeleteIPEntry deletes an instance of IPAllocationPool
-func (dbRepo *DatabaseRepository) DeleteIPEntry(IPEntry *domain.IPAllocationPool) error {
-	var DBIPEntry database.IPAllocationPool
-	Copy(&DBIPEntry, IPEntry)
-	err := dbRepo.GetDBHandle().Delete(&DBIPEntry).Error
-	return err
-}
+//GetUsedASNsOnRole returns the UsedASN instances for a given "fabric and device-role" input, ordered by ASN
+func (dbRepo *DatabaseRepository) GetUsedASNsOnRole(FabricID uint, role string) ([]domain.UsedASN, error) {
+	var DBUsedASNs []database.UsedASN
+	err := dbRepo.GetDBHandle().Order("asn asc").
+		Where("fabric_id = ? AND device_role = ?", FabricID, role).Find(&DBUsedASNs).Error
 
-//DeleteIPPool deletes all the instances of IPAllocationPool
-func (dbRepo *DatabaseRepository) DeleteIPPool() error {
-	return dbRepo.GetDBHandle().Model(&database.IPAllocationPool{}).Delete(&database.IPAllocationPool{}).Error
+	UsedASNs := make([]domain.UsedASN, 0, len(DBUsedASNs))
+	for _, DBUsedASN := range DBUsedASNs {
+		var UsedASN domain.UsedASN
+		Copy(&UsedASN, DBUsedASN)
+		UsedASNs = append(UsedASNs, UsedASN)
+	}
+	return UsedASNs, err
 }
 
 //DeleteUsedIPPool deletes all the instances of UsedIP
@@ -254,27 +418,18 @@ func (dbRepo *DatabaseRepository) DeleteUsedIPPool() error {
 	return dbRepo.GetDBHandle().Model(&database.UsedIP{}).Delete(&database.UsedIP{}).Error
 }
 
-//GetIPEntryAndCountOnIPAddressAndType returns an instance of IPAllocationPool for a given "FabricID, IPAddress, IPType" input
-func (dbRepo *DatabaseRepository) GetIPEntryAndCountOnIPAddressAndType(FabricID uint, ipaddress string, IPType string) (int64, domain.IPAllocationPool, error) {
-	var ipCount int64
-	var DBIPEntry database.IPAllocationPool
-
-	err := dbRepo.GetDBHandle().Model(database.IPAllocationPool{}).
-		Where("fabric_id = ? AND ip_address = ? AND ip_type = ?", FabricID, ipaddress, IPType).Find(&DBIPEntry).Count(&ipCount).Error
-
-	var IPEntry domain.IPAllocationPool
-	Copy(&IPEntry, &DBIPEntry)
-	return ipCount, IPEntry, err
-}
-
-//GetNextIPEntryOnType returns an instance of IPAllocationPool, for a given "FabricID, IPType" input
-func (dbRepo *DatabaseRepository) GetNextIPEntryOnType(FabricID uint, IPType string) (domain.IPAllocationPool, error) {
-	var IPEntry domain.IPAllocationPool
-	var DBIPEntry database.IPAllocationPool
+//GetUsedIPsOnType returns the UsedIP instances for a given "FabricID, IPType" input
+func (dbRepo *DatabaseRepository) GetUsedIPsOnType(FabricID uint, IPType string) ([]domain.UsedIP, error) {
+	var DBUsedIPs []database.UsedIP
+	err := dbRepo.GetDBHandle().Where("fabric_id = ? AND ip_type = ?", FabricID, IPType).Find(&DBUsedIPs).Error
 
-	err := dbRepo.GetDBHandle().Order("id desc").Find(&DBIPEntry, "ip_type = ?", IPType).Error
-	Copy(&IPEntry, &DBIPEntry)
-	return IPEntry, err
+	UsedIPs := make([]domain.UsedIP, 0, len(DBUsedIPs))
+	for _, DBUsedIP := range DBUsedIPs {
+		var UsedIP domain.UsedIP
+		Copy(&UsedIP, DBUsedIP)
+		UsedIPs = append(UsedIPs, UsedIP)
+	}
+	return UsedIPs, err
 }
 
 //GetUsedIPOnDeviceInterfaceIDIPAddresssAndType returns an instance of UsedIP for a given "FabricID, DeviceID, IPAddress, IPType, InterfaceID" input
@@ -339,66 +494,6 @@ func (dbRepo *DatabaseRepository) DeleteUsedIPEntry(UsedIPEntry *domain.UsedIP)
 	return err
 }
 
-//CreateIPPairEntry creates an instance of IPPairAllocationPool in the database
-func (dbRepo *DatabaseRepository) CreateIPPairEntry(IPPairEntry *domain.IPPairAllocationPool) error {
-	var DBIPPairEntry database.IPPairAllocationPool
-	Copy(&DBIPPairEntry, IPPairEntry)
-	err := dbRepo.GetDBHandle().Save(&DBIPPairEntry).Error
-	if err == nil {
-		IPPairEntry.ID = DBIPPairEntry.ID
-	}
-	return err
-}
-
-//DeleteIPPairEntry deletes an instance of IPPairAllocationPool from the database
-func (dbRepo *DatabaseRepository) DeleteIPPairEntry(IPPairEntry *domain.IPPairAllocationPool) error {
-	var DBIPEntry database.IPPairAllocationPool
-	Copy(&DBIPEntry, IPPairEntry)
-	err := dbRepo.GetDBHandle().Delete(&DBIPEntry).Error
-	return err
-}
-
-//DeleteIPPairPool deletes all the instances of IPPairAllocationPool from the database
-func (dbRepo *DatabaseRepository) DeleteIPPairPool() error {
-	return dbRepo.GetDBHandle().Model(&database.IPPairAllocationPool{}).Delete(&database.IPPairAllocationPool{}).Error
-}
-
-//GetIPPairEntryAndCountOnIPAddressAndType returns an instance of IPPairAllocationPool, for a given "FabricID, IPAddressOne, IPAddressTwo, IPType" input
-func (dbRepo *DatabaseRepository) GetIPPairEntryAndCountOnIPAddressAndType(FabricID uint, ipaddressOne string, ipaddressTwo string, IPType string) (int64, domain.IPPairAllocationPool, error) {
-	var ipCount int64
-	var DBIPPairEntry database.IPPairAllocationPool
-	IPList := []string{ipaddressOne, ipaddressTwo}
-	err := dbRepo.GetDBHandle().Model(database.IPPairAllocationPool{}).
-		Where("fabric_id = ? AND ip_address_one IN (?)  AND ip_address_two IN (?)  AND ip_type = ?", FabricID, IPList, IPList, IPType).Find(&DBIPPairEntry).Count(&ipCount).Error
-
-	var IPEntry domain.IPPairAllocationPool
-	Copy(&IPEntry, &DBIPPairEntry)
-	return ipCount, IPEntry, err
-}
-
-//GetIPPairEntryAndCountOnEitherIPAddressAndType returns an instance of IPPairAllocationPool for a given "FabricID, IPAddress, IPType" input
-func (dbRepo *DatabaseRepository) GetIPPairEntryAndCountOnEitherIPAddressAndType(FabricID uint, ipaddress string, IPType string) (int64, domain.IPPairAllocationPool, error) {
-	var ipCount int64
-	var DBIPPairEntry database.IPPairAllocationPool
-
-	err := dbRepo.GetDBHandle().Model(database.IPPairAllocationPool{}).
-		Where("fabric_id = ? AND (ip_address_one =  ?  OR ip_address_two = ? )  AND ip_type = ?", FabricID, ipaddress, ipaddress, IPType).Find(&DBIPPairEntry).Count(&ipCount).Error
-
-	var IPEntry domain.IPPairAllocationPool
-	Copy(&IPEntry, &DBIPPairEntry)
-	return ipCount, IPEntry, err
-}
-
-//GetNextIPPairEntryOnType returns an instance of next available IPPairAllocationPool for a given "FabricID, IPType" input
-func (dbRepo *DatabaseRepository) GetNextIPPairEntryOnType(FabricID uint, IPType string) (domain.IPPairAllocationPool, error) {
-	var IPPairEntry domain.IPPairAllocationPool
-	var DBIPPairEntry database.IPPairAllocationPool
-
-	err := dbRepo.GetDBHandle().Order("id desc").Find(&DBIPPairEntry, "ip_type = ?", IPType).Error
-	Copy(&IPPairEntry, &DBIPPairEntry)
-	return IPPairEntry, err
-}
-
 //GetUsedIPPairOnDeviceInterfaceIDIPAddresssAndType returns an instance of UsedIPPair,
 // for a given "FabricID, DeviceOneID, DeviceTwoID, IPAddressOne, IPAddressTwo, IPType, InterfaceOneId, InterfaceTwoId" input
 func (dbRepo *DatabaseRepository) GetUsedIPPairOnDeviceInterfaceIDIPAddresssAndType(FabricID uint, DeviceOneID uint, DeviceTwoID uint, ipaddressOne string, ipaddressTwo string, IPType string,
@@ -473,6 +568,20 @@ func (dbRepo *DatabaseRepository) DeleteUsedIPPairPool() error {
 	return dbRepo.GetDBHandle().Model(&database.UsedIPPair{}).Delete(&database.UsedIPPair{}).Error
 }
 
+//GetUsedIPPairsOnType returns the UsedIPPair instances for a given "FabricID, IPType" input
+func (dbRepo *DatabaseRepository) GetUsedIPPairsOnType(FabricID uint, IPType string) ([]domain.UsedIPPair, error) {
+	var DBUsedIPPairs []database.UsedIPPair
+	err := dbRepo.GetDBHandle().Where("fabric_id = ? AND ip_type = ?", FabricID, IPType).Find(&DBUsedIPPairs).Error
+
+	UsedIPPairs := make([]domain.UsedIPPair, 0, len(DBUsedIPPairs))
+	for _, DBUsedIPPair := range DBUsedIPPairs {
+		var UsedIPPair domain.UsedIPPair
+		Copy(&UsedIPPair, DBUsedIPPair)
+		UsedIPPairs = append(UsedIPPairs, UsedIPPair)
+	}
+	return UsedIPPairs, err
+}
+
 //GetDeviceUsingDeviceID returns an instance of Device for a given "FabricID, DeviceID" input
 func (dbRepo *DatabaseRepository) GetDeviceUsingDeviceID(FabricID uint, DeviceID uint) (domain.Device, error) {
 
@@ -726,6 +835,27 @@ func (dbRepo *DatabaseRepository) SaveDevice(Device *domain.Device) error {
 	return err
 }
 
+//GetDeviceSettings returns the instance of DeviceSettings for a given "FabricID, DeviceID" input
+func (dbRepo *DatabaseRepository) GetDeviceSettings(FabricID uint, DeviceID uint) (domain.DeviceSettings, error) {
+	var DBDeviceSettings database.DeviceSettings
+	err := dbRepo.GetDBHandle().First(&DBDeviceSettings, "fabric_id = ? AND device_id = ?", FabricID, DeviceID).Error
+
+	var DeviceSettings domain.DeviceSettings
+	Copy(&DeviceSettings, DBDeviceSettings)
+	return DeviceSettings, err
+}
+
+//SaveDeviceSettings creates or updates an instance of DeviceSettings in the database
+func (dbRepo *DatabaseRepository) SaveDeviceSettings(DeviceSettings *domain.DeviceSettings) error {
+	var DBDeviceSettings database.DeviceSettings
+	Copy(&DBDeviceSettings, DeviceSettings)
+	err := dbRepo.GetDBHandle().Save(&DBDeviceSettings).Error
+	if err == nil {
+		DeviceSettings.ID = DBDeviceSettings.ID
+	}
+	return err
+}
+
 //GetInterface returns an instance of Interface for a given "FabricId, DeviceID, InterfaceType, InterfaceName" input
 func (dbRepo *DatabaseRepository) GetInterface(FabricID uint, DeviceID uint,
 	InterfaceType string, InterfaceName string) (domain.Interface, error) {
@@ -1556,6 +1686,203 @@ func (dbRepo *DatabaseRepository) GetRackEvpnConfigOnRemoteDeviceID(DeviceID uin
 	return RackEvpnNeighbors, nil
 }
 
+//intendedConfig is the intended configuration of a generation as stored in the database,
+//the older generations stored the devices only
+type intendedConfig struct {
+	Settings *domain.FabricProperties
+	Devices  []domain.DeviceIntendedConfig
+}
+
+//CreateConfigGeneration stores the intended configuration of the fabric as the next generation of the fabric,
+//the BGP passwords of the settings are stored encrypted
+func (dbRepo *DatabaseRepository) CreateConfigGeneration(Generation *domain.ConfigGeneration) error {
+	Intended := intendedConfig{Devices: Generation.Devices}
+	if Generation.Settings != nil {
+		Settings := *Generation.Settings
+		if err := cryptBGPPasswords(&Settings, util.AesEncrypt); err != nil {
+			return err
+		}
+		Intended.Settings = &Settings
+	}
+	Config, err := json.Marshal(Intended)
+	if err != nil {
+		return err
+	}
+	var Last database.ConfigGeneration
+	err = dbRepo.GetDBHandle().Where("fabric_id = ?", Generation.FabricID).Order("generation desc").First(&Last).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	DBGeneration := database.ConfigGeneration{FabricID: Generation.FabricID, Generation: Last.Generation + 1,
+		ExecutionID: Generation.ExecutionID, Config: string(Config)}
+	if err = dbRepo.GetDBHandle().Create(&DBGeneration).Error; err == nil {
+		Generation.ID = DBGeneration.ID
+		Generation.Generation = DBGeneration.Generation
+		Generation.CreatedAt = DBGeneration.CreatedAt
+	}
+	return err
+}
+
+//GetConfigGenerations returns the configuration generations of the fabric, the most recent first
+func (dbRepo *DatabaseRepository) GetConfigGenerations(FabricID uint) ([]domain.ConfigGeneration, error) {
+	var DBGenerations []database.ConfigGeneration
+	err := dbRepo.GetDBHandle().Where("fabric_id = ?", FabricID).Order("generation desc").Find(&DBGenerations).Error
+
+	Generations := make([]domain.ConfigGeneration, 0, len(DBGenerations))
+	for _, DBGeneration := range DBGenerations {
+		Generation, jerr := toConfigGeneration(DBGeneration)
+		if jerr != nil {
+			return Generations, jerr
+		}
+		Generations = append(Generations, Generation)
+	}
+	return Generations, err
+}
+
+//GetConfigGeneration returns a configuration generation of the fabric
+func (dbRepo *DatabaseRepository) GetConfigGeneration(FabricID uint, Generation uint) (domain.ConfigGeneration, error) {
+	var DBGeneration database.ConfigGeneration
+	err := dbRepo.GetDBHandle().Where("fabric_id = ? AND generation = ?", FabricID, Generation).First(&DBGeneration).Error
+	if err != nil {
+		return domain.ConfigGeneration{}, err
+	}
+	return toConfigGeneration(DBGeneration)
+}
+
+func toConfigGeneration(DBGeneration database.ConfigGeneration) (domain.ConfigGeneration, error) {
+	Generation := domain.ConfigGeneration{ID: DBGeneration.ID, FabricID: DBGeneration.FabricID,
+		Generation: DBGeneration.Generation, ExecutionID: DBGeneration.ExecutionID, CreatedAt: DBGeneration.CreatedAt}
+	if strings.HasPrefix(DBGeneration.Config, "[") {
+		err := json.Unmarshal([]byte(DBGeneration.Config), &Generation.Devices)
+		return Generation, err
+	}
+	var Intended intendedConfig
+	if err := json.Unmarshal([]byte(DBGeneration.Config), &Intended); err != nil {
+		return Generation, err
+	}
+	Generation.Devices = Intended.Devices
+	if Intended.Settings != nil {
+		if err := cryptBGPPasswords(Intended.Settings, util.AesDecrypt); err != nil {
+			return Generation, err
+		}
+		Generation.Settings = Intended.Settings
+	}
+	return Generation, nil
+}
+
+//CreateFabricSettingChange records a change of a fabric setting in the database
+func (dbRepo *DatabaseRepository) CreateFabricSettingChange(Change *domain.FabricSettingChange) error {
+	var DBChange database.FabricSettingChange
+	Copy(&DBChange, Change)
+	if err := cryptSettingChange(&DBChange.Setting, &DBChange.OldValue, &DBChange.NewValue, util.AesEncrypt); err != nil {
+		return err
+	}
+	err := dbRepo.GetDBHandle().Create(&DBChange).Error
+	if err == nil {
+		Change.ID = DBChange.ID
+	}
+	return err
+}
+
+//GetFabricSettingChanges returns the changes of the fabric settings, the most recent first
+func (dbRepo *DatabaseRepository) GetFabricSettingChanges(FabricID uint) ([]domain.FabricSettingChange, error) {
+	var DBChanges []database.FabricSettingChange
+	err := dbRepo.GetDBHandle().Where("fabric_id = ?", FabricID).Order("id desc").Find(&DBChanges).Error
+
+	Changes := make([]domain.FabricSettingChange, 0, len(DBChanges))
+	for _, DBChange := range DBChanges {
+		var Change domain.FabricSettingChange
+		Copy(&Change, DBChange)
+		if cerr := cryptSettingChange(&Change.Setting, &Change.OldValue, &Change.NewValue, util.AesDecrypt); cerr != nil {
+			return Changes, cerr
+		}
+		Changes = append(Changes, Change)
+	}
+	return Changes, err
+}
+
+//cryptSettingChange applies the AES encrypt or decrypt function on the values of a change of a BGP password
+func cryptSettingChange(Setting *string, OldValue *string, NewValue *string,
+	crypt func(key []byte, message string) (string, error)) error {
+	if !strings.Contains(*Setting, "Password") {
+		return nil
+	}
+	var err error
+	for _, Value := range []*string{OldValue, NewValue} {
+		if len(*Value) == 0 {
+			continue
+		}
+		if *Value, err = crypt(constants.AESEncryptionKey, *Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//CreateNotificationSubscription creates a notification subscription in the database, the secret is stored encrypted
+func (dbRepo *DatabaseRepository) CreateNotificationSubscription(Subscription *domain.NotificationSubscription) error {
+	DBSubscription := database.NotificationSubscription{URL: Subscription.URL, Events: strings.Join(Subscription.Events, ","),
+		Retries: Subscription.Retries, CreatedAt: Subscription.CreatedAt}
+	if len(Subscription.Secret) != 0 {
+		Secret, err := util.AesEncrypt(constants.AESEncryptionKey, Subscription.Secret)
+		if err != nil {
+			return err
+		}
+		DBSubscription.Secret = Secret
+	}
+	err := dbRepo.GetDBHandle().Create(&DBSubscription).Error
+	if err == nil {
+		Subscription.ID = DBSubscription.ID
+	}
+	return err
+}
+
+//GetNotificationSubscriptions returns the notification subscriptions ordered by ID
+func (dbRepo *DatabaseRepository) GetNotificationSubscriptions() ([]domain.NotificationSubscription, error) {
+	var DBSubscriptions []database.NotificationSubscription
+	err := dbRepo.GetDBHandle().Order("id").Find(&DBSubscriptions).Error
+
+	Subscriptions := make([]domain.NotificationSubscription, 0, len(DBSubscriptions))
+	for _, DBSubscription := range DBSubscriptions {
+		Subscription, serr := toNotificationSubscription(DBSubscription)
+		if serr != nil {
+			return Subscriptions, serr
+		}
+		Subscriptions = append(Subscriptions, Subscription)
+	}
+	return Subscriptions, err
+}
+
+//GetNotificationSubscription returns the notification subscription of the given ID
+func (dbRepo *DatabaseRepository) GetNotificationSubscription(ID uint) (domain.NotificationSubscription, error) {
+	var DBSubscription database.NotificationSubscription
+	if err := dbRepo.GetDBHandle().Where("id = ?", ID).First(&DBSubscription).Error; err != nil {
+		return domain.NotificationSubscription{}, err
+	}
+	return toNotificationSubscription(DBSubscription)
+}
+
+//DeleteNotificationSubscription deletes the notification subscription of the given ID
+func (dbRepo *DatabaseRepository) DeleteNotificationSubscription(ID uint) error {
+	return dbRepo.GetDBHandle().Where("id = ?", ID).Delete(&database.NotificationSubscription{}).Error
+}
+
+func toNotificationSubscription(DBSubscription database.NotificationSubscription) (domain.NotificationSubscription, error) {
+	Subscription := domain.NotificationSubscription{ID: DBSubscription.ID, URL: DBSubscription.URL,
+		Retries: DBSubscription.Retries, CreatedAt: DBSubscription.CreatedAt}
+	if len(DBSubscription.Events) != 0 {
+		Subscription.Events = strings.Split(DBSubscription.Events, ",")
+	}
+	if len(DBSubscription.Secret) != 0 {
+		Secret, err := util.AesDecrypt(constants.AESEncryptionKey, DBSubscription.Secret)
+		if err != nil {
+			return Subscription, err
+		}
+		Subscription.Secret = Secret
+	}
+	return Subscription, nil
+}
+
 //CreateExecutionLog creates an instance of "ExecutionLog" in the database
 func (dbRepo *DatabaseRepository) CreateExecutionLog(ExecutionLog *domain.ExecutionLog) error {
 	var DBExecutionLog database.ExecutionLog
@@ -1698,6 +2025,36 @@ func (dbRepo *DatabaseRepository) DeleteLLDPMarkedForDelete(FabricID uint) error
 	return err
 }
 
+//UpdateLLDPConfigTypeOnLinks updates "config_type" of "lldp_data" of the given "DeviceIDs" and of "lldp_neighbors"
+//on the given "InterfaceIDs", for a given "FabricID"
+func (dbRepo *DatabaseRepository) UpdateLLDPConfigTypeOnLinks(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint,
+	QueryconfigTypes []string, configType string) error {
+	err := dbRepo.GetDBHandle().Table("lldp_data").Where(
+		"fabric_id = ? AND device_id IN (?) AND config_type IN (?)", FabricID, DeviceIDs, QueryconfigTypes).
+		UpdateColumn("config_type", configType).Error
+	if err != nil {
+		return err
+	}
+	return dbRepo.GetDBHandle().Table("lldp_neighbors").Where(
+		"fabric_id = ? AND (interface_one_id IN (?) OR interface_two_id IN (?)) AND config_type IN (?)",
+		FabricID, InterfaceIDs, InterfaceIDs, QueryconfigTypes).
+		UpdateColumn("config_type", configType).Error
+}
+
+//DeleteLLDPMarkedForDeleteOnLinks deletes instances of "lldp_data" of the given "DeviceIDs" and of "lldp_neighbors"
+//on the given "InterfaceIDs" which have been marked for deletion, for a given "FabricID"
+func (dbRepo *DatabaseRepository) DeleteLLDPMarkedForDeleteOnLinks(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint) error {
+	err := dbRepo.GetDBHandle().Table("lldp_data").Where(
+		"fabric_id = ? AND device_id IN (?)", FabricID, DeviceIDs).
+		Delete(database.LLDPData{}, "config_type IN (?)", domain.ConfigDelete).Error
+	if err != nil {
+		return err
+	}
+	return dbRepo.GetDBHandle().Table("lldp_neighbors").Where(
+		"fabric_id = ? AND (interface_one_id IN (?) OR interface_two_id IN (?))", FabricID, InterfaceIDs, InterfaceIDs).
+		Delete(database.LLDPNeighbor{}, "config_type IN (?)", domain.ConfigDelete).Error
+}
+
 //UpdateMctClusterConfigType updates "config_type" and "updated_attributes" attributes of "mct_cluster_configs" for a given input criteria
 func (dbRepo *DatabaseRepository) UpdateMctClusterConfigType(FabricID uint, QueryconfigTypes []string, configType string) error {
 	updatedAttr := 0
@@ -8,6 +8,7 @@ import (
 	"efa-server/infra/device/client"
 	Interactor "efa-server/usecase/interactorinterface"
 	nlog "github.com/sirupsen/logrus"
+	"strings"
 )
 
 var log *nlog.Entry
@@ -29,6 +30,10 @@ func DeviceAdapterFactory(ctx context.Context, IPAddress string, UserName string
 	client := &client.NetconfClient{Host: IPAddress, User: UserName, Password: Password}
 	err := client.Login()
 	if err != nil {
+		//The SSH handshake fails with "unable to authenticate" when the credentials are refused
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			err = &domain.DeviceCredentialsError{Device: IPAddress, Err: err}
+		}
 		return &DeviceAdapter{client: client}, err
 	}
 	detail, _ := ada.GetDeviceDetail(client)
@@ -112,6 +117,17 @@ func (ad *DeviceAdapter) GetInterfaceSpeed(InterfaceType string, InterfaceName s
 	return InterfaceSpeed, err
 }
 
+//GetInterfaceMtus fetches the MTU configured on the ethernet interfaces
+func (ad *DeviceAdapter) GetInterfaceMtus() (map[string]int, error) {
+
+	adapter := ada.GetAdapter(ad.detail.Model)
+	InterfaceMtus, err := adapter.GetInterfaceMtus(ad.client)
+	if err != nil {
+		log.Error("Fetching Interface MTU Failed")
+	}
+	return InterfaceMtus, err
+}
+
 //GetInterfaceVe fetches VE interface from Switch
 func (ad *DeviceAdapter) GetInterfaceVe(name string) (map[string]string, error) {
 	adapter := ada.GetAdapter(ad.detail.Model)
@@ -8,6 +8,7 @@ import (
 	ConfigureFabric "efa-server/infra/device/actions/configurefabric"
 	DeconfigureFabric "efa-server/infra/device/actions/deconfigurefabric"
 	NONCLOSDeconfigureFabric "efa-server/infra/device/actions/deconfigurefabric"
+	FabricHealth "efa-server/infra/device/actions/fabrichealth"
 	FetchFabric "efa-server/infra/device/actions/fetchfabric"
 	"efa-server/infra/device/adapter"
 	"strings"
@@ -30,12 +31,33 @@ func (ad *FabricAdapter) ConfigureFabric(ctx context.Context, config operation.C
 
 }
 
+//RotateBGPPasswords re-keys the BGP sessions of the fabric, one switch at a time
+func (ad *FabricAdapter) RotateBGPPasswords(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError {
+	return ConfigureFabric.RotateBGPPasswords(ctx, config)
+}
+
+//ConfigureMaintenanceMode drains or restores the traffic of the switches
+func (ad *FabricAdapter) ConfigureMaintenanceMode(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+	return ConfigureFabric.ConfigureMaintenanceMode(ctx, config, enable)
+}
+
+//ConfigureLinks configures only the interfaces and BGP neighbors of the changed links
+func (ad *FabricAdapter) ConfigureLinks(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError {
+	return ConfigureFabric.ConfigureLinks(ctx, config, persist)
+}
+
 //FetchFabricConfiguration fetches the Configurations from the Fabric
 func (ad *FabricAdapter) FetchFabricConfiguration(ctx context.Context, FabricRequest operation.FabricFetchRequest) (operation.FabricFetchResponse, error) {
 	return FetchFabric.FetchFabric(ctx, FabricRequest)
 
 }
 
+//FetchFabricHealth collects the operational state of the switches of the Fabric
+func (ad *FabricAdapter) FetchFabricHealth(ctx context.Context, FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse,
+	[]actions.OperationError) {
+	return FabricHealth.FetchFabricHealth(ctx, FabricRequest)
+}
+
 //CleanupDevicesInFabric cleans up IP Fabric configuration from a collection of devices used by Delete Fabric
 func (ad *FabricAdapter) CleanupDevicesInFabric(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError {
 	Errors := DeconfigureFabric.CleanupDevicesInFabric(ctx, config, force, persist)
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//Headers of the events posted to the endpoints of the subscriptions
+const (
+	//EventHeader names the event
+	EventHeader = "X-Efa-Event"
+	//DeliveryHeader carries the ID of the event, the same on each retry
+	DeliveryHeader = "X-Efa-Delivery"
+	//SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the body keyed by the secret of the subscription
+	SignatureHeader = "X-Efa-Signature"
+)
+
+//DefaultRetryInterval is the wait before the first retry of a delivery, doubled on each retry
+const DefaultRetryInterval = 2 * time.Second
+
+//WebhookNotifier posts the events as JSON to the endpoints of the subscriptions
+type WebhookNotifier struct {
+	Client        *http.Client
+	RetryInterval time.Duration
+}
+
+//NewWebhookNotifier returns a WebhookNotifier waiting 10 seconds for the endpoints
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{Client: &http.Client{Timeout: 10 * time.Second}, RetryInterval: DefaultRetryInterval}
+}
+
+//Deliver posts the event to the endpoint of the subscription. A delivery is retried when the endpoint
+//cannot be reached or answers with a 5xx or 429 status, the other statuses than 2xx are failures.
+func (n *WebhookNotifier) Deliver(ctx context.Context, Subscription domain.NotificationSubscription,
+	Event domain.NotificationEvent) error {
+	LOG := appcontext.Logger(ctx)
+	Body, err := json.Marshal(&Event)
+	if err != nil {
+		return err
+	}
+	Interval := n.RetryInterval
+	for Attempt := uint(0); ; Attempt++ {
+		var retry bool
+		if retry, err = n.post(Subscription, Event, Body); err == nil {
+			return nil
+		}
+		if !retry || Attempt >= Subscription.Retries {
+			return err
+		}
+		LOG.Infof("Delivery of %s event %s to %s failed, retrying in %s: %s", Event.Event, Event.ID,
+			Subscription.URL, Interval, err)
+		time.Sleep(Interval)
+		Interval *= 2
+	}
+}
+
+//post posts the body of the event once, it returns whether a failure can be retried
+func (n *WebhookNotifier) post(Subscription domain.NotificationSubscription, Event domain.NotificationEvent,
+	Body []byte) (bool, error) {
+	Request, err := http.NewRequest("POST", Subscription.URL, bytes.NewReader(Body))
+	if err != nil {
+		return false, err
+	}
+	Request.Header.Set("Content-Type", "application/json")
+	Request.Header.Set(EventHeader, Event.Event)
+	Request.Header.Set(DeliveryHeader, Event.ID)
+	if len(Subscription.Secret) != 0 {
+		Request.Header.Set(SignatureHeader, Signature(Subscription.Secret, Body))
+	}
+
+	Client := n.Client
+	if Client == nil {
+		Client = http.DefaultClient
+	}
+	Response, err := Client.Do(Request)
+	if err != nil {
+		return true, err
+	}
+	Response.Body.Close()
+	if Response.StatusCode >= 200 && Response.StatusCode < 300 {
+		return false, nil
+	}
+	return Response.StatusCode >= 500 || Response.StatusCode == http.StatusTooManyRequests,
+		fmt.Errorf("%s answered %s", Subscription.URL, Response.Status)
+}
+
+//Signature returns the value of the signature header of a body signed with the secret
+func Signature(Secret string, Body []byte) string {
+	Mac := hmac.New(sha256.New, []byte(Secret))
+	Mac.Write(Body)
+	return "sha256=" + hex.EncodeToString(Mac.Sum(nil))
+}
@@ -30,6 +30,12 @@ const (
 
 	//IPPair depicts the pair of IP Address used for NON-CLOS fabric
 	IPPair
+
+	//UserName represents the user who requested the operation
+	UserName
+
+	//DeferHealthCheck tells the configure that its caller checks the health of the fabric once the RestLock is released
+	DeferHealthCheck
 )
 
 func getContext(ctx context.Context, requestID string) context.Context {
@@ -76,6 +82,11 @@ func Logger(ctx context.Context) *nlog.Entry {
 				"FabricType": fabricType,
 			})
 		}
+		if userName, ok := ctx.Value(UserName).(string); ok {
+			newLogger = newLogger.WithFields(nlog.Fields{
+				"User": userName,
+			})
+		}
 	}
 	return newLogger
 }
@@ -5,6 +5,7 @@ import (
 	"efa-server/infra/database"
 	"efa-server/usecase"
 	"sync"
+	"time"
 )
 
 //DeviceInteractor provides reciever object for UseCases
@@ -19,9 +20,11 @@ func GetUseCaseInteractor() *usecase.DeviceInteractor {
 		DatabaseRepository := gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
 		FabricAdapter := gateway.FabricAdapter{}
 		DeviceInteractor = &usecase.DeviceInteractor{
-			Db:                   &DatabaseRepository,
-			DeviceAdapterFactory: gateway.DeviceAdapterFactory,
-			FabricAdapter:        &FabricAdapter}
+			Db:                  &DatabaseRepository,
+			FabricAdapter:       &FabricAdapter,
+			Notifier:            gateway.NewWebhookNotifier(),
+			HealthSettleTimeout: 60 * time.Second}
+		DeviceInteractor.DeviceAdapterFactory = DeviceInteractor.NotifyCredentialFailures(gateway.DeviceAdapterFactory)
 
 	})
 	return DeviceInteractor
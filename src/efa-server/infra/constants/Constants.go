@@ -1,7 +1,7 @@
 package constants
 
 import (
-	"sync"
+	"efa-server/infra/metrics"
 	"time"
 )
 
@@ -11,6 +11,8 @@ const (
 	ApplicationServerName = "efa-server"
 	DBLocation            = "/var/" + ApplicationName + "/" + ApplicationName + ".db"
 	TESTDBLocation        = "/var/" + ApplicationName + "/" + ApplicationName + "_test.db"
+	//DBConfigLocation selects the database backend, SQLite in DBLocation is used when the file is absent
+	DBConfigLocation = "/etc/" + ApplicationName + "/" + ApplicationName + "-db.yaml"
 	//InfoLogLocation   = "/var/log/" + ApplicationName + "_info.log"
 	//ErrorLogLocation  = "/var/log/" + ApplicationName + "_error.log"
 	LogLocation       = "/var/log/" + ApplicationName + "/" + ApplicationName + ".log"
@@ -20,10 +22,15 @@ const (
 	NumberOfLogFiles = 10
 	LLDPSleep        = 10
 	RackNameSuffix   = "Rack-"
+
+	//UserNameHeader carries the name of the user running the efa command
+	UserNameHeader = "X-Efa-User"
+	//ExecutionIDHeader carries the execution ID chosen by the client, to stream the events of the execution
+	ExecutionIDHeader = "X-Efa-Execution-Id"
 )
 
 //AESEncryptionKey  test
 var AESEncryptionKey = []byte("Why is it that always you three?")
 
-//RestLock locks all Rest API except execution list when API start
-var RestLock sync.Mutex
+//RestLock locks all Rest API except execution list when API start, the time waited for it is a metric
+var RestLock = metrics.TimedMutex{Wait: metrics.RestLockWait}
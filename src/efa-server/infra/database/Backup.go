@@ -0,0 +1,196 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//backupSuffix and metadataSuffix name the files of a backup, the data and its description
+const (
+	backupSuffix   = ".bk"
+	metadataSuffix = ".json"
+)
+
+//ErrBackupNotFound is returned when no backup has the given name
+var ErrBackupNotFound = errors.New("A backup with the specified name was not found")
+
+//ErrBackupIncompatible is returned when a backup can not be restored to the database
+var ErrBackupIncompatible = errors.New("The backup can not be restored to the database")
+
+//BackupInfo describes a backup of the database
+type BackupInfo struct {
+	Name          string    `json:"name"`
+	Dialect       string    `json:"dialect"`
+	SchemaVersion uint      `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Size          int64     `json:"-"`
+}
+
+//backupLock serializes the backups and the restores, which may come from the schedule and from the users
+var backupLock sync.Mutex
+
+//CreateBackup saves the database to a new backup while the database stays online,
+//the backups past the retention count are then deleted
+func (database *Database) CreateBackup() (BackupInfo, error) {
+	backupLock.Lock()
+	defer backupLock.Unlock()
+	Info, err := database.createBackup()
+	if err != nil {
+		return Info, err
+	}
+	return Info, database.pruneBackups()
+}
+
+func (database *Database) createBackup() (Info BackupInfo, err error) {
+	if err = os.MkdirAll(database.Config.BackupDir, os.ModePerm); err != nil {
+		return Info, err
+	}
+	Info = BackupInfo{Dialect: database.Config.Dialect, CreatedAt: time.Now().UTC()}
+	Base := filepath.Base(database.Name)
+	Info.Name = strings.TrimSuffix(Base, filepath.Ext(Base)) + "-" + Info.CreatedAt.Format("20060102T150405.000")
+	if Info.SchemaVersion, err = database.SchemaVersion(); err != nil {
+		return Info, err
+	}
+
+	File := database.backupFile(Info.Name)
+	if database.Config.Dialect == DialectSQLite {
+		//The single connection is held while the file is copied, no write can happen meanwhile
+		tx := database.Instance.Begin()
+		err = copyFile(database.Config.URL, File)
+		tx.Rollback()
+	} else {
+		err = database.backup(File)
+	}
+	if err != nil {
+		os.Remove(File)
+		return Info, err
+	}
+	if Stat, err := os.Stat(File); err == nil {
+		Info.Size = Stat.Size()
+	}
+	Metadata, _ := json.Marshal(&Info)
+	if err = ioutil.WriteFile(database.metadataFile(Info.Name), Metadata, 0644); err != nil {
+		os.Remove(File)
+		return Info, err
+	}
+	return Info, nil
+}
+
+//ListBackups returns the backups of the database, the most recent first
+func (database *Database) ListBackups() ([]BackupInfo, error) {
+	Backups := make([]BackupInfo, 0)
+	Files, err := ioutil.ReadDir(database.Config.BackupDir)
+	if os.IsNotExist(err) {
+		return Backups, nil
+	}
+	if err != nil {
+		return Backups, err
+	}
+	for _, File := range Files {
+		if !strings.HasSuffix(File.Name(), metadataSuffix) {
+			continue
+		}
+		if Info, err := database.backupInfo(strings.TrimSuffix(File.Name(), metadataSuffix)); err == nil {
+			Backups = append(Backups, Info)
+		}
+	}
+	sort.Slice(Backups, func(i, j int) bool { return Backups[i].CreatedAt.After(Backups[j].CreatedAt) })
+	return Backups, nil
+}
+
+//RestoreBackup replaces the database with the backup. The database is backed up first, the connections
+//are reopened on the restored data and the restored schema is migrated to the version of the application.
+//The callers are expected to stop the operations on the database during the restore.
+func (database *Database) RestoreBackup(Name string) (BackupInfo, error) {
+	backupLock.Lock()
+	defer backupLock.Unlock()
+
+	Info, err := database.backupInfo(Name)
+	if err != nil {
+		return Info, err
+	}
+	if Info.Dialect != database.Config.Dialect {
+		return Info, fmt.Errorf("%s: backup of a %s database", ErrBackupIncompatible, Info.Dialect)
+	}
+	if Info.SchemaVersion > LatestSchemaVersion() {
+		return Info, fmt.Errorf("%s: schema version %d, supported version %d", ErrBackupIncompatible,
+			Info.SchemaVersion, LatestSchemaVersion())
+	}
+	//The data replaced by the restore stays available, the backups are pruned on the next backup only
+	//as the backup being restored may be the oldest one
+	if _, err := database.createBackup(); err != nil {
+		return Info, fmt.Errorf("Backup before the restore failed: %s", err)
+	}
+
+	database.Close()
+	if database.Config.Dialect == DialectSQLite {
+		err = copyFile(database.backupFile(Name), database.Config.URL)
+	} else {
+		//psql exits with a failure on the first failing statement only with ON_ERROR_STOP
+		var Output []byte
+		if Output, err = exec.Command("psql", "--dbname="+database.Config.URL, "--single-transaction",
+			"--set=ON_ERROR_STOP=1", "--quiet", "--file="+database.backupFile(Name)).CombinedOutput(); err != nil {
+			err = fmt.Errorf("%s: %s", err, strings.TrimSpace(string(Output)))
+		}
+	}
+	//The connections are reopened on the restored data, or on the previous data when the restore failed
+	if OpenErr := database.open(); OpenErr != nil {
+		return Info, OpenErr
+	}
+	if err != nil {
+		return Info, err
+	}
+	return Info, database.Migrate()
+}
+
+func (database *Database) backupInfo(Name string) (Info BackupInfo, err error) {
+	//The name is a file of the backup directory, not a path
+	if Name == "" || filepath.Base(Name) != Name {
+		return Info, ErrBackupNotFound
+	}
+	Metadata, err := ioutil.ReadFile(database.metadataFile(Name))
+	if err != nil {
+		return Info, ErrBackupNotFound
+	}
+	if err = json.Unmarshal(Metadata, &Info); err != nil || Info.Name != Name {
+		return Info, ErrBackupNotFound
+	}
+	Stat, err := os.Stat(database.backupFile(Name))
+	if err != nil {
+		return Info, ErrBackupNotFound
+	}
+	Info.Size = Stat.Size()
+	return Info, nil
+}
+
+//pruneBackups deletes the oldest backups past the retention count
+func (database *Database) pruneBackups() error {
+	Backups, err := database.ListBackups()
+	if err != nil {
+		return err
+	}
+	for iter := database.Config.BackupRetention; iter < len(Backups); iter++ {
+		os.Remove(database.backupFile(Backups[iter].Name))
+		if err := os.Remove(database.metadataFile(Backups[iter].Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (database *Database) backupFile(Name string) string {
+	return filepath.Join(database.Config.BackupDir, Name+backupSuffix)
+}
+
+func (database *Database) metadataFile(Name string) string {
+	return filepath.Join(database.Config.BackupDir, Name+metadataSuffix)
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"fmt"
+	"github.com/ghodss/yaml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+//Supported database backends
+const (
+	DialectSQLite   = "sqlite3"
+	DialectPostgres = "postgres"
+)
+
+//Environment variables overriding the database configuration file
+const (
+	EnvDialect         = "EFA_DB_DIALECT"
+	EnvURL             = "EFA_DB_URL"
+	EnvMaxOpenConns    = "EFA_DB_MAX_OPEN_CONNS"
+	EnvMaxIdleConns    = "EFA_DB_MAX_IDLE_CONNS"
+	EnvConnMaxLifetime = "EFA_DB_CONN_MAX_LIFETIME"
+	EnvBackupDir       = "EFA_DB_BACKUP_DIR"
+	EnvBackupInterval  = "EFA_DB_BACKUP_INTERVAL"
+	EnvBackupRetention = "EFA_DB_BACKUP_RETENTION"
+)
+
+//Default connection pool sizing of the PostgreSQL backend
+const (
+	DefaultPostgresMaxOpenConns = 10
+	DefaultPostgresMaxIdleConns = 5
+)
+
+//Default scheduling of the database backups
+const (
+	DefaultBackupInterval  = 24 * time.Hour
+	DefaultBackupRetention = 7
+)
+
+//Config describes the database backend of the application
+type Config struct {
+	//Dialect is either sqlite3 or postgres
+	Dialect string `json:"dialect"`
+	//URL is the path of the database file for sqlite3 and the connection string for postgres,
+	//e.g. "host=localhost port=5432 user=efa dbname=efa password=efa sslmode=disable"
+	URL          string `json:"url"`
+	MaxOpenConns int    `json:"max_open_conns"`
+	MaxIdleConns int    `json:"max_idle_conns"`
+	//ConnMaxLifetime is the number of seconds a connection is reused, 0 reuses it forever
+	ConnMaxLifetime int `json:"conn_max_lifetime"`
+
+	//BackupDir holds the backups, "backups" next to the SQLite database by default
+	BackupDir string `json:"backup_dir"`
+	//BackupInterval is the duration between the scheduled backups, e.g. "12h", "0" disables them
+	BackupInterval string `json:"backup_interval"`
+	//BackupRetention is the number of backups kept, the oldest backups are deleted
+	BackupRetention int `json:"backup_retention"`
+
+	//BackupPeriod is the parsed BackupInterval
+	BackupPeriod time.Duration `json:"-"`
+}
+
+//DefaultConfig returns the configuration of the SQLite database stored in DBName
+func DefaultConfig(DBName string) Config {
+	return Config{Dialect: DialectSQLite, URL: DBName, BackupDir: filepath.Join(filepath.Dir(DBName), "backups"),
+		BackupInterval: DefaultBackupInterval.String(), BackupRetention: DefaultBackupRetention,
+		BackupPeriod: DefaultBackupInterval}
+}
+
+//LoadConfig reads the database configuration from the file, if present, and from the environment.
+//The SQLite database stored in DBName is used when neither of them selects a backend.
+func LoadConfig(ConfigFile string, DBName string) (Config, error) {
+	//The location of the SQLite database is only filled in once the dialect is known
+	Config := Config{Dialect: DialectSQLite}
+	if ConfigFile != "" {
+		data, err := ioutil.ReadFile(ConfigFile)
+		if err != nil && !os.IsNotExist(err) {
+			return Config, err
+		}
+		if err == nil {
+			if err = yaml.Unmarshal(data, &Config); err != nil {
+				return Config, fmt.Errorf("Invalid database configuration %s: %s", ConfigFile, err)
+			}
+		}
+	}
+	if err := Config.applyEnvironment(); err != nil {
+		return Config, err
+	}
+	return Config, Config.validate(DBName)
+}
+
+func (Config *Config) applyEnvironment() error {
+	if Dialect := os.Getenv(EnvDialect); Dialect != "" {
+		Config.Dialect = Dialect
+	}
+	if URL := os.Getenv(EnvURL); URL != "" {
+		Config.URL = URL
+	}
+	if BackupDir := os.Getenv(EnvBackupDir); BackupDir != "" {
+		Config.BackupDir = BackupDir
+	}
+	if BackupInterval := os.Getenv(EnvBackupInterval); BackupInterval != "" {
+		Config.BackupInterval = BackupInterval
+	}
+	for Env, Value := range map[string]*int{EnvMaxOpenConns: &Config.MaxOpenConns, EnvMaxIdleConns: &Config.MaxIdleConns,
+		EnvConnMaxLifetime: &Config.ConnMaxLifetime, EnvBackupRetention: &Config.BackupRetention} {
+		if os.Getenv(Env) == "" {
+			continue
+		}
+		number, err := strconv.Atoi(os.Getenv(Env))
+		if err != nil || number < 0 {
+			return fmt.Errorf("%s should be a positive number", Env)
+		}
+		*Value = number
+	}
+	return nil
+}
+
+//validate checks the dialect and fills the pool sizing and the backup scheduling left unset
+func (Config *Config) validate(DBName string) error {
+	if Config.BackupDir == "" {
+		Config.BackupDir = filepath.Join(filepath.Dir(DBName), "backups")
+	}
+	if Config.BackupInterval == "" {
+		Config.BackupInterval = DefaultBackupInterval.String()
+	}
+	var err error
+	if Config.BackupPeriod, err = time.ParseDuration(Config.BackupInterval); err != nil || Config.BackupPeriod < 0 {
+		return fmt.Errorf("Backup interval should be a duration such as 24h, %s is invalid", Config.BackupInterval)
+	}
+	if Config.BackupRetention < 0 {
+		return fmt.Errorf("Backup retention should be a positive number")
+	}
+	if Config.BackupRetention == 0 {
+		Config.BackupRetention = DefaultBackupRetention
+	}
+	switch Config.Dialect {
+	case DialectSQLite:
+		if Config.URL == "" {
+			Config.URL = DBName
+		}
+		//SQLite allows a single writer, all the accesses are serialized on one connection.
+		//The connection is kept open as the foreign keys are enabled per connection.
+		Config.MaxOpenConns, Config.MaxIdleConns = 1, 1
+	case DialectPostgres:
+		if Config.URL == "" {
+			return fmt.Errorf("A connection string is required for the %s database", DialectPostgres)
+		}
+		if Config.MaxOpenConns == 0 {
+			Config.MaxOpenConns = DefaultPostgresMaxOpenConns
+		}
+		if Config.MaxIdleConns == 0 {
+			Config.MaxIdleConns = DefaultPostgresMaxIdleConns
+		}
+		if Config.MaxIdleConns > Config.MaxOpenConns {
+			Config.MaxIdleConns = Config.MaxOpenConns
+		}
+	default:
+		return fmt.Errorf("Database dialect should be %s or %s", DialectSQLite, DialectPostgres)
+	}
+	return nil
+}
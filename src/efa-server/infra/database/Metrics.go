@@ -0,0 +1,39 @@
+package database
+
+import (
+	"efa-server/infra/metrics"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+const queryStartKey = "efa:query_start"
+
+//registerQueryMetrics observes the duration of the create, query, update and delete operations of the DB.
+//The raw statements run through Exec are not observed.
+func registerQueryMetrics(DB *gorm.DB) {
+	Callbacks := DB.Callback()
+	//A CallbackProcessor registers a single callback, a processor is created for each
+	Operations := []struct {
+		Name      string
+		Processor func() *gorm.CallbackProcessor
+	}{
+		{"create", Callbacks.Create},
+		{"query", Callbacks.Query},
+		{"update", Callbacks.Update},
+		{"delete", Callbacks.Delete},
+		{"row_query", Callbacks.RowQuery},
+	}
+	for _, Operation := range Operations {
+		Name := Operation.Name
+		Operation.Processor().Before("gorm:"+Name).Register("efa:before_"+Name, startQuery)
+		Operation.Processor().After("gorm:"+Name).Register("efa:after_"+Name, func(scope *gorm.Scope) {
+			if Start, found := scope.InstanceGet(queryStartKey); found {
+				metrics.ObserveDBQuery(Name, time.Since(Start.(time.Time)))
+			}
+		})
+	}
+}
+
+func startQuery(scope *gorm.Scope) {
+	scope.InstanceSet(queryStartKey, time.Now())
+}
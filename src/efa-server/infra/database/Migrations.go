@@ -0,0 +1,450 @@
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Migration is a numbered change of the schema, or of the data, of the database.
+//A released migration is never modified, a later migration is added instead. Migrations depend only on
+//the tables they change, the models of the application move on while the migrations stay the same:
+//a migration uses the models of its schema version, SchemaV<version>.go, or of the last version changing them.
+type Migration struct {
+	Version     uint
+	Description string
+	//Up moves the database from the previous version to this version
+	Up func(tx *gorm.DB) error
+	//Down moves the database back to the previous version, nil when the migration can not be reverted
+	Down func(tx *gorm.DB) error
+}
+
+//SchemaVersion records a migration applied to the database
+type SchemaVersion struct {
+	Version     uint `gorm:"primary_key;auto_increment:false"`
+	Description string
+	AppliedAt   time.Time
+}
+
+//MigrationStatus describes a migration known to the application and whether it is applied to the database
+type MigrationStatus struct {
+	Version     uint
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+//ErrNewerSchema is returned when the database was migrated by a newer release of the application
+var ErrNewerSchema = errors.New("Database schema is newer than the schema supported by the application")
+
+//migrations lists the migrations in the order of their versions
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Create the tables of the application",
+		Up: func(tx *gorm.DB) error {
+			//The tables of the databases created before the migrations are already present,
+			//only the columns they miss are added
+			for _, Model := range v1Models() {
+				if err := tx.AutoMigrate(Model).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			Models := v1Models()
+			for iter := len(Models) - 1; iter >= 0; iter-- {
+				if err := tx.DropTableIfExists(Models[iter]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     2,
+		Description: "Move the allocation pools stored as one row per value to ranges",
+		Up:          migrateLegacyPools,
+	},
+	{
+		Version:     3,
+		Description: "Fill the fabric properties added by the upgrades",
+		Up:          fillFabricProperties,
+		//The filled properties are valid on the previous version as well
+		Down: func(tx *gorm.DB) error { return nil },
+	},
+	{
+		Version:     4,
+		Description: "Populate the Rack ASN and the MCT L3 Loopback pools of the fabrics",
+		Up:          populateRackPools,
+		//The pools are left to the fabrics created on the previous version
+		Down: func(tx *gorm.DB) error { return nil },
+	},
+	{
+		Version:     5,
+		Description: "Create the configuration generations of the fabrics",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v5ConfigGeneration{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists(&v5ConfigGeneration{}).Error
+		},
+	},
+	{
+		Version:     6,
+		Description: "Create the change history of the fabric settings",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v6FabricSettingChange{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists(&v6FabricSettingChange{}).Error
+		},
+	},
+	{
+		Version:     7,
+		Description: "Create the notification subscriptions",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v7NotificationSubscription{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists(&v7NotificationSubscription{}).Error
+		},
+	},
+	{
+		Version:     8,
+		Description: "Create the cabling plans of the fabrics",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v8CablingLink{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists(&v8CablingLink{}).Error
+		},
+	},
+}
+
+//LatestSchemaVersion returns the version of the schema expected by the application
+func LatestSchemaVersion() uint {
+	return migrations[len(migrations)-1].Version
+}
+
+//SchemaVersion returns the version of the last migration applied to the database, 0 when none is applied
+func (database *Database) SchemaVersion() (uint, error) {
+	return schemaVersion(database.Instance)
+}
+
+func schemaVersion(db *gorm.DB) (uint, error) {
+	if !db.HasTable(&SchemaVersion{}) {
+		return 0, nil
+	}
+	var Versions []SchemaVersion
+	if err := db.Order("version desc").Limit(1).Find(&Versions).Error; err != nil {
+		return 0, err
+	}
+	if len(Versions) == 0 {
+		return 0, nil
+	}
+	return Versions[0].Version, nil
+}
+
+//Migrate applies the pending migrations in a single transaction.
+//A database holding data is backed up before it is migrated. ErrNewerSchema is returned when the
+//database was migrated past the latest version known to the application, the database is then left untouched.
+func (database *Database) Migrate() error {
+	Current, err := database.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if Current > LatestSchemaVersion() {
+		return fmt.Errorf("%s: version %d, supported version %d", ErrNewerSchema, Current, LatestSchemaVersion())
+	}
+	if Current == LatestSchemaVersion() {
+		return nil
+	}
+	if database.Instance.HasTable(&Fabric{}) {
+		if err := database.backup(fmt.Sprintf("%s.v%d.bk", database.Name, Current)); err != nil {
+			return fmt.Errorf("Backup before the database migration failed: %s", err)
+		}
+	}
+
+	tx := database.Instance.Begin()
+	if err := tx.AutoMigrate(&SchemaVersion{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, Migration := range migrations {
+		if Migration.Version <= Current {
+			continue
+		}
+		if err := Migration.Up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Database migration %d (%s) failed: %s", Migration.Version, Migration.Description, err)
+		}
+		Version := SchemaVersion{Version: Migration.Version, Description: Migration.Description, AppliedAt: time.Now()}
+		if err := tx.Create(&Version).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+//MigrateDown reverts, in a single transaction, the migrations applied past the target version
+func (database *Database) MigrateDown(Target uint) error {
+	Current, err := database.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	tx := database.Instance.Begin()
+	for iter := len(migrations) - 1; iter >= 0; iter-- {
+		Migration := migrations[iter]
+		if Migration.Version <= Target || Migration.Version > Current {
+			continue
+		}
+		if Migration.Down == nil {
+			tx.Rollback()
+			return fmt.Errorf("Database migration %d (%s) can not be reverted", Migration.Version, Migration.Description)
+		}
+		if err := Migration.Down(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Reverting database migration %d (%s) failed: %s", Migration.Version,
+				Migration.Description, err)
+		}
+		if err := tx.Where("version = ?", Migration.Version).Delete(&SchemaVersion{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+//MigrationStatus returns the migrations known to the application followed by the unknown migrations
+//applied to the database
+func (database *Database) MigrationStatus() ([]MigrationStatus, error) {
+	Applied := make(map[uint]SchemaVersion)
+	if database.Instance.HasTable(&SchemaVersion{}) {
+		var Versions []SchemaVersion
+		if err := database.Instance.Order("version").Find(&Versions).Error; err != nil {
+			return nil, err
+		}
+		for _, Version := range Versions {
+			Applied[Version.Version] = Version
+		}
+	}
+	Status := make([]MigrationStatus, 0, len(migrations))
+	for _, Migration := range migrations {
+		Version, ok := Applied[Migration.Version]
+		Status = append(Status, MigrationStatus{Version: Migration.Version, Description: Migration.Description,
+			Applied: ok, AppliedAt: Version.AppliedAt})
+		delete(Applied, Migration.Version)
+	}
+	Unknown := make([]MigrationStatus, 0, len(Applied))
+	for _, Version := range Applied {
+		Unknown = append(Unknown, MigrationStatus{Version: Version.Version, Description: Version.Description,
+			Applied: true, AppliedAt: Version.AppliedAt})
+	}
+	sort.Slice(Unknown, func(i, j int) bool { return Unknown[i].Version < Unknown[j].Version })
+	return append(Status, Unknown...), nil
+}
+
+//migrateLegacyPools moves the ASN, IP and IP pair pools stored as one row per value to ranges
+//and drops the tables which held them
+func migrateLegacyPools(tx *gorm.DB) error {
+	type poolKey struct {
+		FabricID uint
+		PoolType string
+		PoolName string
+	}
+	Values := make(map[poolKey][]uint64)
+
+	if tx.HasTable(&ASNAllocationPool{}) {
+		var ASNs []ASNAllocationPool
+		if err := tx.Find(&ASNs).Error; err != nil {
+			return err
+		}
+		for _, ASN := range ASNs {
+			key := poolKey{ASN.FabricID, "ASN", ASN.DeviceRole}
+			Values[key] = append(Values[key], ASN.ASN)
+		}
+	}
+	if tx.HasTable(&IPAllocationPool{}) {
+		var IPEntries []IPAllocationPool
+		if err := tx.Find(&IPEntries).Error; err != nil {
+			return err
+		}
+		for _, IPEntry := range IPEntries {
+			if Value, ok := ipv4Value(IPEntry.IPAddress); ok {
+				key := poolKey{IPEntry.FabricID, "IP", IPEntry.IPType}
+				Values[key] = append(Values[key], Value)
+			}
+		}
+	}
+	if tx.HasTable(&IPPairAllocationPool{}) {
+		var IPPairEntries []IPPairAllocationPool
+		if err := tx.Find(&IPPairEntries).Error; err != nil {
+			return err
+		}
+		for _, IPPairEntry := range IPPairEntries {
+			One, okOne := ipv4Value(IPPairEntry.IPAddressOne)
+			Two, okTwo := ipv4Value(IPPairEntry.IPAddressTwo)
+			if Two < One {
+				One, Two = Two, One
+			}
+			//The pair of the 2N and 2N+1 addresses is stored as N
+			if okOne && okTwo && One%2 == 0 && Two == One+1 {
+				key := poolKey{IPPairEntry.FabricID, "IPPair", IPPairEntry.IPType}
+				Values[key] = append(Values[key], One/2)
+			}
+		}
+	}
+
+	for key, PoolValues := range Values {
+		sort.Slice(PoolValues, func(i, j int) bool { return PoolValues[i] < PoolValues[j] })
+		for start := 0; start < len(PoolValues); {
+			end := start
+			for end+1 < len(PoolValues) && PoolValues[end+1] <= PoolValues[end]+1 {
+				end++
+			}
+			Range := v1AllocationRange{FabricID: key.FabricID, PoolType: key.PoolType, PoolName: key.PoolName,
+				StartValue: PoolValues[start], EndValue: PoolValues[end]}
+			if err := tx.Create(&Range).Error; err != nil {
+				return err
+			}
+			start = end + 1
+		}
+	}
+	return tx.DropTableIfExists(&ASNAllocationPool{}, &IPAllocationPool{}, &IPPairAllocationPool{}).Error
+}
+
+//fillFabricProperties sets the values of the fabric properties left empty by the releases preceding them
+func fillFabricProperties(tx *gorm.DB) error {
+	var Properties []v1FabricProperties
+	if err := tx.Find(&Properties).Error; err != nil {
+		return err
+	}
+	for _, Property := range Properties {
+		for Field, Value := range map[*string]string{
+			&Property.RackASNBlock:          "4200000000-4200065534",
+			&Property.BFDEnable:             "Yes", //Previous release BFD was enabled
+			&Property.MCTL3LBIPRange:        "10.30.30.0/24",
+			&Property.RoutingMctPortChannel: "64",
+			&Property.FabricType:            "clos",
+			&Property.RackPeerEBGPGroup:     "underlay-ebgp-group",
+			&Property.RackPeerOvgGroup:      "overlay-ebgp-group",
+			&Property.BGPAuthType:           "md5",
+			&Property.PoolWarningThreshold:  "80",
+		} {
+			if *Field == "" {
+				*Field = Value
+			}
+		}
+		if err := tx.Save(&Property).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//populateRackPools adds the Rack ASN pool and the MCT L3 Loopback IP pair pool to the fabrics
+//created before the non-CLOS fabrics were supported
+func populateRackPools(tx *gorm.DB) error {
+	var Properties []v1FabricProperties
+	if err := tx.Find(&Properties).Error; err != nil {
+		return err
+	}
+	for _, Property := range Properties {
+		var count int64
+		if err := tx.Model(&v1AllocationRange{}).Where("fabric_id = ? AND pool_type = ? AND pool_name = ?",
+			Property.FabricID, "ASN", "Rack").Count(&count).Error; err != nil {
+			return err
+		}
+		if count != 0 {
+			continue
+		}
+		Min, Max, err := asnBlock(Property.RackASNBlock)
+		if err != nil {
+			return err
+		}
+		Ranges := []v1AllocationRange{{FabricID: Property.FabricID, PoolType: "ASN", PoolName: "Rack",
+			StartValue: Min, EndValue: Max}}
+		Pairs, err := ipPairRanges(Property.MCTL3LBIPRange)
+		if err != nil {
+			return err
+		}
+		for _, Pair := range Pairs {
+			Pair.FabricID, Pair.PoolType, Pair.PoolName = Property.FabricID, "IPPair", "RACKL3LB"
+			Ranges = append(Ranges, Pair)
+		}
+		for _, Range := range Ranges {
+			if err := tx.Create(&Range).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func ipv4Value(IPAddress string) (uint64, bool) {
+	ip := net.ParseIP(IPAddress).To4()
+	if ip == nil {
+		return 0, false
+	}
+	return uint64(binary.BigEndian.Uint32(ip)), true
+}
+
+//asnBlock returns the bounds of an ASN block, either a single ASN or a "min-max" range
+func asnBlock(Block string) (uint64, uint64, error) {
+	Bounds := strings.SplitN(Block, "-", 2)
+	Min, err := strconv.ParseUint(strings.TrimSpace(Bounds[0]), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid ASN block %s", Block)
+	}
+	Max := Min
+	if len(Bounds) == 2 {
+		if Max, err = strconv.ParseUint(strings.TrimSpace(Bounds[1]), 10, 64); err != nil || Max < Min {
+			return 0, 0, fmt.Errorf("Invalid ASN block %s", Block)
+		}
+	}
+	return Min, Max, nil
+}
+
+//ipPairRanges returns the ranges of the IP pairs of the network, leaving out the .0, .1, .254 and .255
+//addresses of each /24
+func ipPairRanges(Network string) ([]v1AllocationRange, error) {
+	_, ipnet, err := net.ParseCIDR(Network)
+	if err != nil {
+		return nil, err
+	}
+	ip := ipnet.IP.To4()
+	if ip == nil {
+		return nil, fmt.Errorf("Invalid IPv4 network %s", Network)
+	}
+	ones, bits := ipnet.Mask.Size()
+	Start := uint64(binary.BigEndian.Uint32(ip))
+	End := Start + (uint64(1) << uint(bits-ones)) - 1
+
+	Ranges := make([]v1AllocationRange, 0)
+	for block := Start &^ 0xff; block <= End; block += 256 {
+		low, high := block+2, block+253
+		if low < Start {
+			low = Start
+		}
+		if high > End {
+			high = End
+		}
+		if low%2 != 0 {
+			low++
+		}
+		if high < low+1 {
+			continue
+		}
+		Ranges = append(Ranges, v1AllocationRange{StartValue: low / 2, EndValue: low/2 + (high-low+1)/2 - 1})
+	}
+	return Ranges, nil
+}
@@ -1,5 +1,7 @@
 package database
 
+import "time"
+
 //Fabric struct represents a DataCenter Fabric
 type Fabric struct {
 	ID               uint `gorm:"primary_key"`
@@ -17,13 +19,13 @@ type FabricProperties struct {
 	LoopBackPortNumber      string
 	SpineASNBlock           string
 	LeafASNBlock            string
-	RackASNBlock            string `gorm:"default:'4200000000-4200065534'"`
+	RackASNBlock            string
 	VTEPLoopBackPortNumber  string
 	AnyCastMac              string
 	IPV6AnyCastMac          string
 	ConfigureOverlayGateway string
 	VNIAutoMap              string
-	BFDEnable               string `gorm:"default:'Yes'"` //Previous release BFD was enabled
+	BFDEnable               string
 	BFDTx                   string
 	BFDRx                   string
 	BFDMultiplier           string
@@ -44,20 +46,29 @@ type FabricProperties struct {
 	DuplicateMaxTimerMaxCount     string
 
 	//MCT Related
-	MCTLinkIPRange string
-	MCTL3LBIPRange string `gorm:"default:'10.30.30.0/24'"`
-	ControlVlan    string
-	ControlVE      string
-	MctPortChannel string
-	//The default value is provided here so that value gets initialized in case of upgrade
-	RoutingMctPortChannel string `gorm:"default:'64'"`
+	MCTLinkIPRange        string
+	MCTL3LBIPRange        string
+	ControlVlan           string
+	ControlVE             string
+	MctPortChannel        string
+	RoutingMctPortChannel string
 
 	// Fabric Type
-	FabricType string `gorm:"default:'clos'"`
+	FabricType string
 
 	// NON ClOS Fields
-	RackPeerEBGPGroup string `gorm:"default:'underlay-ebgp-group'"`
-	RackPeerOvgGroup  string `gorm:"default:'overlay-ebgp-group'"`
+	RackPeerEBGPGroup string
+	RackPeerOvgGroup  string
+
+	//BGP Authentication, passwords are AES encrypted
+	BGPAuthType               string
+	PeerGroupPassword         string
+	MctL2EvpnPassword         string
+	RackPeerEBGPGroupPassword string
+	RackPeerOvgGroupPassword  string
+
+	//Utilization in percent above which the allocation pools are reported
+	PoolWarningThreshold string
 }
 
 //Device represents a switching device
@@ -74,10 +85,34 @@ type Device struct {
 	FirmwareVersion string
 	Model           string
 	DeviceType      string
+	MaintenanceMode bool
 	LLDPData        []LLDPData      `gorm:"ForeignKey:DeviceOneID;AssociationForeignKey:Refer"`
 	PhysInterface   []PhysInterface `gorm:"ForeignKey:DeviceOneID;AssociationForeignKey:Refer"`
 }
 
+//DeviceSettings represents the per-device overrides of the fabric settings
+type DeviceSettings struct {
+	ID                            uint `gorm:"primary_key"`
+	FabricID                      uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID                      uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	MTU                           string
+	IPMTU                         string
+	BFDEnable                     string
+	BFDTx                         string
+	BFDRx                         string
+	BFDMultiplier                 string
+	MaxPaths                      string
+	AllowASIn                     string
+	LeafPeerGroup                 string
+	SpinePeerGroup                string
+	ArpAgingTimeout               string
+	MacAgingTimeout               string
+	MacAgingConversationalTimeout string
+	MacMoveLimit                  string
+	DuplicateMacTimer             string
+	DuplicateMaxTimerMaxCount     string
+}
+
 //Rack represents a rack containing two Leaf nodes
 type Rack struct {
 	ID          uint `gorm:"primary_key"`
@@ -152,7 +187,41 @@ type PhysInterface struct {
 	ConfigType     string
 }
 
-//ASNAllocationPool represents the unallocated ASN of a switching device
+//AllocationRange represents a range of unallocated values of a pool
+type AllocationRange struct {
+	ID         uint `gorm:"primary_key"`
+	FabricID   uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	PoolType   string
+	PoolName   string
+	StartValue uint64
+	EndValue   uint64
+}
+
+//AllocationPin represents a value of a pool pinned by the operator to a device or to an interface of the device
+type AllocationPin struct {
+	ID            uint `gorm:"primary_key"`
+	FabricID      uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceIP      string
+	PinType       string
+	InterfaceName string
+	PoolType      string
+	PoolName      string
+	Value         string
+	PoolValue     uint64
+}
+
+//CablingLink represents a link of the cabling plan of a fabric
+type CablingLink struct {
+	ID               uint `gorm:"primary_key"`
+	FabricID         uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceOneIP      string
+	InterfaceOneName string
+	DeviceTwoIP      string
+	InterfaceTwoName string
+}
+
+//ASNAllocationPool represents the unallocated ASN of a switching device,
+//it is no longer created and is only read to migrate databases which stored one row per ASN
 type ASNAllocationPool struct {
 	ID         uint `gorm:"primary_key"`
 	FabricID   uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
@@ -169,7 +238,8 @@ type UsedASN struct {
 	DeviceRole string
 }
 
-//IPAllocationPool represents unallocated IP of a switching device
+//IPAllocationPool represents unallocated IP of a switching device,
+//it is no longer created and is only read to migrate databases which stored one row per IP
 type IPAllocationPool struct {
 	ID        uint `gorm:"primary_key"`
 	FabricID  uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
@@ -187,7 +257,8 @@ type UsedIP struct {
 	InterfaceID uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
 }
 
-//IPPairAllocationPool represents unallocated IP pair of a switching device
+//IPPairAllocationPool represents unallocated IP pair of a switching device,
+//it is no longer created and is only read to migrate databases which stored one row per IP pair
 type IPPairAllocationPool struct {
 	ID           uint `gorm:"primary_key"`
 	FabricID     uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
@@ -253,6 +324,40 @@ type RemoteNeighborSwitchConfig struct {
 	ConfigType        string
 }
 
+//ConfigGeneration represents the intended configuration of the fabric stored by a successful configure,
+//Config holds the configuration of the devices in JSON
+type ConfigGeneration struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE" gorm:"unique_index:idx_fabric_generation"`
+	Generation  uint `gorm:"unique_index:idx_fabric_generation"`
+	ExecutionID string
+	CreatedAt   time.Time
+	Config      string `sql:"type:text"`
+}
+
+//FabricSettingChange represents a change of a fabric setting, the BGP passwords are stored encrypted
+type FabricSettingChange struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE" gorm:"index"`
+	Setting     string
+	OldValue    string
+	NewValue    string
+	UserName    string
+	ExecutionID string
+	ChangedAt   time.Time
+}
+
+//NotificationSubscription represents a webhook endpoint receiving the events of the server,
+//the secret is stored encrypted and the events comma separated
+type NotificationSubscription struct {
+	ID        uint `gorm:"primary_key"`
+	URL       string
+	Secret    string
+	Events    string
+	Retries   uint
+	CreatedAt time.Time
+}
+
 //ExecutionLog represents detailed info of the executed operations w.r.t. the application
 type ExecutionLog struct {
 	ID        uint `gorm:"primary_key"`
@@ -3,16 +3,22 @@ package database
 import (
 	"fmt"
 	"github.com/jinzhu/gorm"
-	_ "github.com/jinzhu/gorm/dialects/sqlite" // Very Important: This import is needed for the init of DB driver.
+	_ "github.com/jinzhu/gorm/dialects/postgres" // Very Important: This import is needed for the init of DB driver.
+	_ "github.com/jinzhu/gorm/dialects/sqlite"   // Very Important: This import is needed for the init of DB driver.
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 //Database stores the application data.
 type Database struct {
+	//Name is the location of the SQLite database, and of the backups for every backend
 	Name     string
+	Config   Config
 	Instance *gorm.DB
 }
 
@@ -20,23 +26,36 @@ var instance *Database
 var once sync.Once
 
 //Setup instantiates DB, opens the DB connection and sets up DB schema.
+//The backend is selected through the environment, SQLite stored in DBName being the default.
 func Setup(DBName string) {
-	database := getInstance(DBName)
-	database.open()
-	database.migrateSchema()
+	Config, err := LoadConfig("", DBName)
+	if err != nil {
+		panic(err)
+	}
+	if err := SetupWithConfig(DBName, Config); err != nil {
+		panic(err)
+	}
+}
+
+//SetupWithConfig instantiates DB on the configured backend, opens the DB connection and migrates the DB schema.
+func SetupWithConfig(DBName string, Config Config) error {
+	database := getInstance(DBName, Config)
+	if err := database.open(); err != nil {
+		return err
+	}
+	return database.Migrate()
 }
 
 //Shut closes the input DB connection.
 func Shut(DBName string) {
-	database := getInstance(DBName)
+	database := getInstance(DBName, DefaultConfig(DBName))
 	database.Close()
 
 }
 
-func getInstance(DBName string) *Database {
+func getInstance(DBName string, Config Config) *Database {
 	once.Do(func() {
-		instance = &Database{DBName, nil}
-		//instance.Instance.DB().SetMaxOpenConns(1)
+		instance = &Database{Name: DBName, Config: Config}
 	})
 	return instance
 }
@@ -48,42 +67,54 @@ func GetWorkingInstance() *Database {
 }
 
 func (database *Database) open() (err error) {
-	dir := filepath.Dir(database.Name)
-	os.MkdirAll(dir, os.ModePerm)
-	database.Instance, err = gorm.Open("sqlite3", database.Name)
-	database.Instance.Exec("PRAGMA foreign_keys = ON")
-	database.Instance.DB().SetMaxOpenConns(1)
-	database.Instance.LogMode(false)
+	if database.Config.Dialect == DialectSQLite {
+		dir := filepath.Dir(database.Config.URL)
+		os.MkdirAll(dir, os.ModePerm)
+	}
+	database.Instance, err = gorm.Open(database.Config.Dialect, database.Config.URL)
 	if err != nil {
-		panic("failed to connect database")
+		return fmt.Errorf("failed to connect database: %s", err)
+	}
+	if database.Config.Dialect == DialectSQLite {
+		database.Instance.Exec("PRAGMA foreign_keys = ON")
 	}
-	return err
+	database.Instance.DB().SetMaxOpenConns(database.Config.MaxOpenConns)
+	database.Instance.DB().SetMaxIdleConns(database.Config.MaxIdleConns)
+	database.Instance.DB().SetConnMaxLifetime(time.Duration(database.Config.ConnMaxLifetime) * time.Second)
+	database.Instance.LogMode(false)
+	registerQueryMetrics(database.Instance)
+	return nil
 }
 
-func (database *Database) migrateSchema() (err error) {
-	database.Instance.AutoMigrate(&Fabric{})
-	database.Instance.AutoMigrate(&FabricProperties{})
-	database.Instance.AutoMigrate(&Device{})
-	database.Instance.AutoMigrate(&LLDPData{})
-	database.Instance.AutoMigrate(&PhysInterface{})
-	database.Instance.AutoMigrate(&ASNAllocationPool{})
-	database.Instance.AutoMigrate(&UsedASN{})
-	database.Instance.AutoMigrate(&IPAllocationPool{})
-	database.Instance.AutoMigrate(&UsedIP{})
-	database.Instance.AutoMigrate(&IPPairAllocationPool{})
-	database.Instance.AutoMigrate(&UsedIPPair{})
-	database.Instance.AutoMigrate(&LLDPNeighbor{})
-	database.Instance.AutoMigrate(&SwitchConfig{})
-	database.Instance.AutoMigrate(&InterfaceSwitchConfig{})
-	database.Instance.AutoMigrate(&RemoteNeighborSwitchConfig{})
-	database.Instance.AutoMigrate(&ExecutionLog{})
-	database.Instance.AutoMigrate(&MCTClusterDetail{})
-	database.Instance.AutoMigrate(&MctClusterConfig{})
-	database.Instance.AutoMigrate(&ClusterMember{})
-	database.Instance.AutoMigrate(&Rack{})
-	database.Instance.AutoMigrate(&RackEvpnNeighbors{})
-
-	return nil
+//schemaModels lists the tables of the schema, a table is listed after the tables it references
+func schemaModels() []interface{} {
+	return []interface{}{
+		&Fabric{},
+		&FabricProperties{},
+		&Device{},
+		&DeviceSettings{},
+		&LLDPData{},
+		&PhysInterface{},
+		&AllocationRange{},
+		&AllocationPin{},
+		&CablingLink{},
+		&UsedASN{},
+		&UsedIP{},
+		&UsedIPPair{},
+		&LLDPNeighbor{},
+		&SwitchConfig{},
+		&InterfaceSwitchConfig{},
+		&RemoteNeighborSwitchConfig{},
+		&ConfigGeneration{},
+		&FabricSettingChange{},
+		&NotificationSubscription{},
+		&ExecutionLog{},
+		&MCTClusterDetail{},
+		&MctClusterConfig{},
+		&ClusterMember{},
+		&Rack{},
+		&RackEvpnNeighbors{},
+	}
 }
 
 //Close closes the DB connection.
@@ -92,13 +123,37 @@ func (database *Database) Close() (err error) {
 
 }
 
-func (database *Database) delete() (err error) {
-	return os.Remove(database.Name)
+//Drop closes the DB connection and deletes all the application data, the SQLite file or the PostgreSQL tables.
+func (database *Database) Drop() (err error) {
+	if database.Config.Dialect == DialectPostgres {
+		Models := append([]interface{}{&SchemaVersion{}}, schemaModels()...)
+		for iter := len(Models) - 1; iter >= 0; iter-- {
+			if err = database.Instance.DropTableIfExists(Models[iter]).Error; err != nil {
+				database.Close()
+				return err
+			}
+		}
+		return database.Close()
+	}
+	database.Close()
+	return os.Remove(database.Config.URL)
 }
 
 //BackupDB represents Database backup
 func (database *Database) BackupDB() (err error) {
-	return copyFile(database.Name, database.Name+".autobk")
+	return database.backup(database.Name + ".autobk")
+}
+
+func (database *Database) backup(destination string) (err error) {
+	if database.Config.Dialect == DialectPostgres {
+		os.MkdirAll(filepath.Dir(destination), os.ModePerm)
+		if Output, err := exec.Command("pg_dump", "--dbname="+database.Config.URL, "--clean", "--if-exists",
+			"--file="+destination).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(Output)))
+		}
+		return nil
+	}
+	return copyFile(database.Config.URL, destination)
 }
 
 func copyFile(source, destination string) (err error) {
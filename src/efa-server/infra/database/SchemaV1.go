@@ -0,0 +1,472 @@
+package database
+
+//The models of the schema version 1, as they were when the migrations were introduced. The migration 1 creates
+//and drops these tables only, the later versions of the models are left to the migrations which changed them.
+
+//v1Fabric is the Fabric of the schema version 1
+type v1Fabric struct {
+	ID               uint `gorm:"primary_key"`
+	Name             string
+	FabricProperties v1FabricProperties `gorm:"ForeignKey:FabricID;AssociationForeignKey:Refer"`
+}
+
+func (v1Fabric) TableName() string {
+	return "fabrics"
+}
+
+//v1FabricProperties is the FabricProperties of the schema version 1
+type v1FabricProperties struct {
+	ID                      uint `gorm:"primary_key"`
+	FabricID                uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	P2PLinkRange            string
+	P2PIPType               string
+	LoopBackIPRange         string
+	LoopBackPortNumber      string
+	SpineASNBlock           string
+	LeafASNBlock            string
+	RackASNBlock            string
+	VTEPLoopBackPortNumber  string
+	AnyCastMac              string
+	IPV6AnyCastMac          string
+	ConfigureOverlayGateway string
+	VNIAutoMap              string
+	BFDEnable               string
+	BFDTx                   string
+	BFDRx                   string
+	BFDMultiplier           string
+	BGPMultiHop             string
+	MaxPaths                string
+	AllowASIn               string
+	MTU                     string
+	IPMTU                   string
+	LeafPeerGroup           string
+	SpinePeerGroup          string
+
+	//EVPN Fields
+	ArpAgingTimeout               string
+	MacAgingTimeout               string
+	MacAgingConversationalTimeout string
+	MacMoveLimit                  string
+	DuplicateMacTimer             string
+	DuplicateMaxTimerMaxCount     string
+
+	//MCT Related
+	MCTLinkIPRange        string
+	MCTL3LBIPRange        string
+	ControlVlan           string
+	ControlVE             string
+	MctPortChannel        string
+	RoutingMctPortChannel string
+
+	// Fabric Type
+	FabricType string
+
+	// NON ClOS Fields
+	RackPeerEBGPGroup string
+	RackPeerOvgGroup  string
+
+	//BGP Authentication, passwords are AES encrypted
+	BGPAuthType               string
+	PeerGroupPassword         string
+	MctL2EvpnPassword         string
+	RackPeerEBGPGroupPassword string
+	RackPeerOvgGroupPassword  string
+
+	//Utilization in percent above which the allocation pools are reported
+	PoolWarningThreshold string
+}
+
+func (v1FabricProperties) TableName() string {
+	return "fabric_properties"
+}
+
+//v1Device is the Device of the schema version 1
+type v1Device struct {
+	ID              uint `gorm:"primary_key"`
+	FabricID        uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	Name            string
+	IPAddress       string
+	RbridgeID       string
+	DeviceRole      string
+	LocalAs         string
+	UserName        string
+	Password        string
+	FirmwareVersion string
+	Model           string
+	DeviceType      string
+	MaintenanceMode bool
+	LLDPData        []v1LLDPData      `gorm:"ForeignKey:DeviceOneID;AssociationForeignKey:Refer"`
+	PhysInterface   []v1PhysInterface `gorm:"ForeignKey:DeviceOneID;AssociationForeignKey:Refer"`
+}
+
+func (v1Device) TableName() string {
+	return "devices"
+}
+
+//v1DeviceSettings is the DeviceSettings of the schema version 1
+type v1DeviceSettings struct {
+	ID                            uint `gorm:"primary_key"`
+	FabricID                      uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID                      uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	MTU                           string
+	IPMTU                         string
+	BFDEnable                     string
+	BFDTx                         string
+	BFDRx                         string
+	BFDMultiplier                 string
+	MaxPaths                      string
+	AllowASIn                     string
+	LeafPeerGroup                 string
+	SpinePeerGroup                string
+	ArpAgingTimeout               string
+	MacAgingTimeout               string
+	MacAgingConversationalTimeout string
+	MacMoveLimit                  string
+	DuplicateMacTimer             string
+	DuplicateMaxTimerMaxCount     string
+}
+
+func (v1DeviceSettings) TableName() string {
+	return "device_settings"
+}
+
+//v1LLDPData is the LLDPData of the schema version 1
+type v1LLDPData struct {
+	ID                      uint `gorm:"primary_key"`
+	DeviceID                uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	FabricID                uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	LocalIntName            string
+	LocalIntType            string
+	LocalIntMac             string
+	RemoteIntName           string
+	RemoteIntType           string
+	RemoteIntMac            string
+	RemoteChassisID         string
+	RemoteSystemName        string
+	RemoteManagementAddress string
+	ConfigType              string
+}
+
+func (v1LLDPData) TableName() string {
+	return "lldp_data"
+}
+
+//v1PhysInterface is the PhysInterface of the schema version 1
+type v1PhysInterface struct {
+	ID             uint `gorm:"primary_key"`
+	FabricID       uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID       uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	IntType        string
+	IntName        string
+	InterfaceSpeed string
+	IPAddress      string
+	Identifier     string
+	Mac            string
+	role           string
+	ConfigType     string
+}
+
+func (v1PhysInterface) TableName() string {
+	return "phys_interfaces"
+}
+
+//v1AllocationRange is the AllocationRange of the schema version 1
+type v1AllocationRange struct {
+	ID         uint `gorm:"primary_key"`
+	FabricID   uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	PoolType   string
+	PoolName   string
+	StartValue uint64
+	EndValue   uint64
+}
+
+func (v1AllocationRange) TableName() string {
+	return "allocation_ranges"
+}
+
+//v1AllocationPin is the AllocationPin of the schema version 1
+type v1AllocationPin struct {
+	ID            uint `gorm:"primary_key"`
+	FabricID      uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceIP      string
+	PinType       string
+	InterfaceName string
+	PoolType      string
+	PoolName      string
+	Value         string
+	PoolValue     uint64
+}
+
+func (v1AllocationPin) TableName() string {
+	return "allocation_pins"
+}
+
+//v1UsedASN is the UsedASN of the schema version 1
+type v1UsedASN struct {
+	ID         uint `gorm:"primary_key"`
+	FabricID   uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID   uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	ASN        uint64
+	DeviceRole string
+}
+
+func (v1UsedASN) TableName() string {
+	return "used_asns"
+}
+
+//v1UsedIP is the UsedIP of the schema version 1
+type v1UsedIP struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID    uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	IPAddress   string
+	IPType      string
+	InterfaceID uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+}
+
+func (v1UsedIP) TableName() string {
+	return "used_ips"
+}
+
+//v1UsedIPPair is the UsedIPPair of the schema version 1
+type v1UsedIPPair struct {
+	ID             uint `gorm:"primary_key"`
+	FabricID       uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceOneID    uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	DeviceTwoID    uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	IPAddressOne   string
+	IPAddressTwo   string
+	IPType         string
+	InterfaceOneID uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	InterfaceTwoID uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+}
+
+func (v1UsedIPPair) TableName() string {
+	return "used_ip_pairs"
+}
+
+//v1LLDPNeighbor is the LLDPNeighbor of the schema version 1
+type v1LLDPNeighbor struct {
+	ID               uint `gorm:"primary_key"`
+	FabricID         uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceOneID      uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	DeviceTwoID      uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	InterfaceOneID   uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	InterfaceTwoID   uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	DeviceOneRole    string
+	DeviceTwoRole    string
+	InterfaceOneName string
+	InterfaceOneType string
+	InterfaceOneIP   string
+	InterfaceTwoName string
+	InterfaceTwoType string
+	InterfaceTwoIP   string
+	ConfigType       string
+}
+
+func (v1LLDPNeighbor) TableName() string {
+	return "lldp_neighbors"
+}
+
+//v1SwitchConfig is the SwitchConfig of the schema version 1
+type v1SwitchConfig struct {
+	ID                       uint `gorm:"primary_key"`
+	DeviceID                 uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	FabricID                 uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceIP                 string
+	LocalAS                  string
+	LoopbackIP               string
+	VTEPLoopbackIP           string
+	Role                     string
+	ASConfigType             string
+	LoopbackIPConfigType     string
+	VTEPLoopbackIPConfigType string
+}
+
+func (v1SwitchConfig) TableName() string {
+	return "switch_configs"
+}
+
+//v1InterfaceSwitchConfig is the InterfaceSwitchConfig of the schema version 1
+type v1InterfaceSwitchConfig struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID    uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	InterfaceID uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	IntType     string
+	IntName     string
+	DonorType   string
+	DonorName   string
+	IPAddress   string
+	ConfigType  string
+	Description string
+}
+
+func (v1InterfaceSwitchConfig) TableName() string {
+	return "interface_switch_configs"
+}
+
+//v1RemoteNeighborSwitchConfig is the RemoteNeighborSwitchConfig of the schema version 1
+type v1RemoteNeighborSwitchConfig struct {
+	ID                uint `gorm:"primary_key"`
+	FabricID          uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID          uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	RemoteInterfaceID uint `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	RemoteDeviceID    uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	EncapsulationType string
+	RemoteIPAddress   string
+	RemoteAS          string
+	Type              string
+	ConfigType        string
+}
+
+func (v1RemoteNeighborSwitchConfig) TableName() string {
+	return "remote_neighbor_switch_configs"
+}
+
+//v1ExecutionLog is the ExecutionLog of the schema version 1
+type v1ExecutionLog struct {
+	ID        uint `gorm:"primary_key"`
+	UUID      string
+	Command   string
+	Params    string
+	Status    string
+	StartTime string
+	EndTime   string
+	Duration  string
+}
+
+func (v1ExecutionLog) TableName() string {
+	return "execution_logs"
+}
+
+//v1MCTClusterDetail is the MCTClusterDetail of the schema version 1
+type v1MCTClusterDetail struct {
+	ID                 uint64 `gorm:"primary_key" gorm:"type:bigint; DEFAULT:id_generator()"`
+	NodeID             uint8
+	DeviceID           uint   `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	PrincipalSwitchMac string `gorm:"size:64"` // set field size to 64
+	NodeInternalIP     string `gorm:"size:30"`
+	NodePublicIP       string `gorm:"size:30"`
+	NodePrincipal      string `gorm:"size:30"`
+	NodeIsLocal        string `gorm:"size:20"`
+	SerialNnum         string `gorm:"size:30"`
+	NodeCondition      string `gorm:"size:30"`
+	NodeStatus         string `gorm:"size:60"`
+	FirmwareVersion    string `gorm:"size:128"`
+	NodeMac            string `gorm:"size:30"`
+	NodeSwitchType     string `gorm:"size:30"`
+}
+
+func (v1MCTClusterDetail) TableName() string {
+	return "mct_cluster_details"
+}
+
+//v1MctClusterConfig is the MctClusterConfig of the schema version 1
+type v1MctClusterConfig struct {
+	ID                  uint16 `gorm:"primary_key" gorm:"type:bigint; DEFAULT:id_generator()"`
+	ClusterID           uint16
+	FabricID            uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceID            uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	MCTNeighborDeviceID uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	DeviceOneMgmtIP     string
+	DeviceTwoMgmtIP     string
+	PeerInterfaceName   string
+	PeerInterfacetype   string
+	PeerInterfaceSpeed  string
+	PeerOneIP           string
+	PeerTwoIP           string
+	ControlVlan         string
+	ControlVE           string
+	VEInterfaceOneID    uint
+	VEInterfaceTwoID    uint
+	ConfigType          string
+	LocalNodeID         string
+	RemoteNodeID        string
+	//Updated Fields 0x1 PeerIP 0x2 Speed
+	UpdatedAttributes uint64
+}
+
+func (v1MctClusterConfig) TableName() string {
+	return "mct_cluster_configs"
+}
+
+//v1ClusterMember is the ClusterMember of the schema version 1
+type v1ClusterMember struct {
+	ID                   uint64 `gorm:"primary_key"`
+	FabricID             uint   `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	ClusterID            uint16 `sql:"type:integer REFERENCES mct_cluster_configs(id) ON DELETE CASCADE"`
+	InterfaceID          uint   `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	DeviceID             uint   `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	RemoteDeviceID       uint   `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	InterfaceName        string `gorm:"size:255"`
+	InterfaceType        string `gorm:"size:64"`
+	InterfaceSpeed       int
+	RemoteInterfaceID    uint   `sql:"type:integer REFERENCES phys_interfaces(id) ON DELETE CASCADE"`
+	RemoteInterfaceName  string `gorm:"size:255"`
+	RemoteInterfaceType  string `gorm:"size:64"`
+	RemoteInterfaceSpeed int
+	ConfigType           string
+}
+
+func (v1ClusterMember) TableName() string {
+	return "cluster_members"
+}
+
+//v1Rack is the Rack of the schema version 1
+type v1Rack struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceOneID uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	DeviceTwoID uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	DeviceOneIP string
+	DeviceTwoIP string
+	RackName    string
+}
+
+func (v1Rack) TableName() string {
+	return "racks"
+}
+
+//v1RackEvpnNeighbors is the RackEvpnNeighbors of the schema version 1
+type v1RackEvpnNeighbors struct {
+	ID             uint `gorm:"primary_key"`
+	LocalRackID    uint `sql:"type:integer REFERENCES racks(id) ON DELETE CASCADE"`
+	LocalDeviceID  uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	RemoteRackID   uint `sql:"type:integer REFERENCES racks(id) ON DELETE CASCADE"`
+	RemoteDeviceID uint `sql:"type:integer REFERENCES devices(id) ON DELETE CASCADE"`
+	EVPNAddress    string
+	RemoteAS       string
+	ConfigType     string
+}
+
+func (v1RackEvpnNeighbors) TableName() string {
+	return "rack_evpn_neighbors"
+}
+
+//v1Models returns the models of the schema version 1, in the order of their creation
+func v1Models() []interface{} {
+	return []interface{}{
+		&v1Fabric{},
+		&v1FabricProperties{},
+		&v1Device{},
+		&v1DeviceSettings{},
+		&v1LLDPData{},
+		&v1PhysInterface{},
+		&v1AllocationRange{},
+		&v1AllocationPin{},
+		&v1UsedASN{},
+		&v1UsedIP{},
+		&v1UsedIPPair{},
+		&v1LLDPNeighbor{},
+		&v1SwitchConfig{},
+		&v1InterfaceSwitchConfig{},
+		&v1RemoteNeighborSwitchConfig{},
+		&v1ExecutionLog{},
+		&v1MCTClusterDetail{},
+		&v1MctClusterConfig{},
+		&v1ClusterMember{},
+		&v1Rack{},
+		&v1RackEvpnNeighbors{},
+	}
+}
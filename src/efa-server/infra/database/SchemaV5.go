@@ -0,0 +1,19 @@
+package database
+
+import "time"
+
+//The models created by the schema version 5, as they were when the migration was introduced.
+
+//v5ConfigGeneration is the ConfigGeneration of the schema version 5
+type v5ConfigGeneration struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE" gorm:"unique_index:idx_fabric_generation"`
+	Generation  uint `gorm:"unique_index:idx_fabric_generation"`
+	ExecutionID string
+	CreatedAt   time.Time
+	Config      string `sql:"type:text"`
+}
+
+func (v5ConfigGeneration) TableName() string {
+	return "config_generations"
+}
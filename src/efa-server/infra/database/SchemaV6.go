@@ -0,0 +1,21 @@
+package database
+
+import "time"
+
+//The models created by the schema version 6, as they were when the migration was introduced.
+
+//v6FabricSettingChange is the FabricSettingChange of the schema version 6
+type v6FabricSettingChange struct {
+	ID          uint `gorm:"primary_key"`
+	FabricID    uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE" gorm:"index"`
+	Setting     string
+	OldValue    string
+	NewValue    string
+	UserName    string
+	ExecutionID string
+	ChangedAt   time.Time
+}
+
+func (v6FabricSettingChange) TableName() string {
+	return "fabric_setting_changes"
+}
@@ -0,0 +1,19 @@
+package database
+
+import "time"
+
+//The models created by the schema version 7, as they were when the migration was introduced.
+
+//v7NotificationSubscription is the NotificationSubscription of the schema version 7
+type v7NotificationSubscription struct {
+	ID        uint `gorm:"primary_key"`
+	URL       string
+	Secret    string
+	Events    string
+	Retries   uint
+	CreatedAt time.Time
+}
+
+func (v7NotificationSubscription) TableName() string {
+	return "notification_subscriptions"
+}
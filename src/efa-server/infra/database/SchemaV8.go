@@ -0,0 +1,17 @@
+package database
+
+//The models created by the schema version 8, as they were when the migration was introduced.
+
+//v8CablingLink is the CablingLink of the schema version 8
+type v8CablingLink struct {
+	ID               uint `gorm:"primary_key"`
+	FabricID         uint `sql:"type:integer REFERENCES fabrics(id) ON DELETE CASCADE"`
+	DeviceOneIP      string
+	InterfaceOneName string
+	DeviceTwoIP      string
+	InterfaceTwoName string
+}
+
+func (v8CablingLink) TableName() string {
+	return "cabling_links"
+}
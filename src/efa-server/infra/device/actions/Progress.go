@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/infra/events"
+	"sync"
+)
+
+//DeviceError returns the error of the device as reported by the execution events and notifications
+func (b OperationError) DeviceError() domain.DeviceError {
+	DeviceError := domain.DeviceError{Device: b.Host, Operation: b.Operation, Message: "unknown reason"}
+	if b.Error != nil {
+		DeviceError.Message = b.Error.Error()
+	}
+	return DeviceError
+}
+
+//StageProgress publishes the progress of the devices through a stage of the execution of the context
+type StageProgress struct {
+	ctx      context.Context
+	stage    string
+	devices  []string
+	mutex    sync.Mutex
+	finished map[string]bool
+}
+
+//StartStage publishes that the devices start the stage
+func StartStage(ctx context.Context, Stage string, Devices []string) *StageProgress {
+	Progress := &StageProgress{ctx: ctx, stage: Stage, finished: make(map[string]bool)}
+	for _, Device := range Devices {
+		Progress.Start(Device)
+	}
+	return Progress
+}
+
+//Start publishes that a device starts the stage
+func (p *StageProgress) Start(Device string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.devices = append(p.devices, Device)
+	events.Publish(p.ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: Device, Stage: p.stage,
+		Status: domain.ExecutionEventRunning})
+}
+
+//Step publishes the action of the stage a device is running
+func Step(ctx context.Context, Device string, Stage string, Step string) {
+	events.Publish(ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: Device, Stage: Stage,
+		Step: Step, Status: domain.ExecutionEventRunning})
+}
+
+//Succeed publishes that the device completed the stage
+func (p *StageProgress) Succeed(Device string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.finished[Device] {
+		return
+	}
+	p.finished[Device] = true
+	events.Publish(p.ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: Device, Stage: p.stage,
+		Status: domain.ExecutionEventSucceeded})
+}
+
+//Fail publishes that the device failed the stage with the error, each error of the device is published
+func (p *StageProgress) Fail(Error OperationError) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.finished[Error.Host] = true
+	DeviceError := Error.DeviceError()
+	events.Publish(p.ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: Error.Host, Stage: p.stage,
+		Step: Error.Operation, Status: domain.ExecutionEventFailed, Message: DeviceError.Message,
+		Errors: []domain.DeviceError{DeviceError}})
+}
+
+//Failed publishes the errors of the stage and returns them
+func (p *StageProgress) Failed(Errors []OperationError) []OperationError {
+	for _, err := range Errors {
+		p.Fail(err)
+	}
+	return Errors
+}
+
+//Finish publishes that the devices which did not fail completed the stage
+func (p *StageProgress) Finish() {
+	p.mutex.Lock()
+	Devices := p.devices
+	p.mutex.Unlock()
+	for _, Device := range Devices {
+		p.Succeed(Device)
+	}
+}
+
+//Collect returns the errors of the stage once the channel is closed, the failures are published as they are
+//received and the success of the other devices at the end
+func (p *StageProgress) Collect(Errors chan OperationError) []OperationError {
+	Collected := make([]OperationError, 0)
+	for err := range Errors {
+		p.Fail(err)
+		Collected = append(Collected, err)
+	}
+	p.Finish()
+	return Collected
+}
@@ -2,23 +2,27 @@ package configurefabric
 
 import (
 	"context"
+	"efa-server/domain"
 	"efa-server/domain/operation"
 	"efa-server/gateway/appcontext"
 	"efa-server/infra/device/actions"
 	"efa-server/infra/device/actions/deconfigurefabric"
 	ad "efa-server/infra/device/adapter"
 	netconf "efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"efa-server/usecase"
 	"errors"
 	"fmt"
 	nlog "github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 //ConfigureSwitch is used to configure both underlay and overlay on a switch.
 func ConfigureSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw operation.ConfigSwitch,
 	force bool, persist bool, fabricError chan actions.OperationError) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("ConfigureSwitch", time.Now())
 	ctx = context.WithValue(ctx, appcontext.DeviceName, sw.Host)
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure Switch",
@@ -26,30 +30,36 @@ func ConfigureSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw operati
 
 	var wg sync.WaitGroup
 
+	actions.Step(ctx, sw.Host, domain.StageSwitchConfigure, "system properties")
 	wg.Add(1)
 	go ConfigureSystemwideProperties(ctx, &wg, &sw, force, fabricError)
 	wg.Wait()
 
+	actions.Step(ctx, sw.Host, domain.StageSwitchConfigure, "interfaces")
 	wg.Add(1)
 	go ConfigureInterfaces(ctx, &wg, &sw, force, fabricError)
 	wg.Wait()
 
+	actions.Step(ctx, sw.Host, domain.StageSwitchConfigure, "bgp")
 	wg.Add(1)
 	go ConfigureBGP(ctx, &wg, &sw, force, fabricError)
 	wg.Wait()
 
 	log.Infoln("MCT Data plane sending BGP unconfigure ", sw.UnconfigureMCTBGPNeighbors)
+	actions.Step(ctx, sw.Host, domain.StageSwitchConfigure, "mct bgp unconfigure")
 	wg.Add(1)
 	go deconfigurefabric.UnconfigureDataPlaneCluster(ctx, &wg, &sw.UnconfigureMCTBGPNeighbors, force, fabricError)
 	wg.Wait()
 
 	if sw.Role == usecase.LeafRole {
+		actions.Step(ctx, sw.Host, domain.StageSwitchConfigure, "evpn")
 		wg.Add(1)
 		go ConfigureEvpn(ctx, &wg, &sw, force, fabricError)
 	}
 	wg.Wait()
 
 	log.Infoln("MCT Data plane sending BGP configure ", sw.ConfigureMCTBGPNeighbors)
+	actions.Step(ctx, sw.Host, domain.StageSwitchConfigure, "mct bgp")
 	wg.Add(1)
 	go ConfigureDataPlaneCluster(ctx, &wg, &sw.ConfigureMCTBGPNeighbors, force, fabricError)
 	wg.Wait()
@@ -67,6 +77,7 @@ func ConfigureSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw operati
 
 func persistConfig(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("PersistConfig", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Persist Config",
 	})
@@ -13,12 +13,15 @@ import (
 
 	"efa-server/domain/operation"
 	"efa-server/infra/device/actions"
+	"efa-server/infra/metrics"
 	"efa-server/usecase"
+	"time"
 )
 
 //ConfigureBGP is used to configure "router bgp" config on the switch.
 func ConfigureBGP(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, force bool, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureBGP", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure BGP",
 	})
@@ -78,6 +81,20 @@ func ConfigureBGP(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigS
 		errs <- actions.OperationError{Operation: Operation, Error: err, Host: sw.Host}
 	}
 
+	//BGP Authentication
+	if err = configureBGPPeerGroupPasswords(adapter, netconfClient, sw, false); err != nil {
+		log.Errorf("BGP Peer Group Password Operation Failed: %s\n", err)
+		errs <- actions.OperationError{Operation: "BGP Peer Group Password", Error: err, Host: sw.Host}
+	}
+
+	//Keep a switch in maintenance mode drained
+	if sw.MaintenanceMode {
+		if err = configureBGPPeerGroupShutdown(adapter, netconfClient, sw, true); err != nil {
+			log.Errorf("BGP Peer Group Shutdown Operation Failed: %s\n", err)
+			errs <- actions.OperationError{Operation: "BGP Peer Group Shutdown", Error: err, Host: sw.Host}
+		}
+	}
+
 	//BGP Neighbor
 	Operation = "BGP Neighbor"
 	//First Delete Neighbors
@@ -0,0 +1,84 @@
+package configurefabric
+
+import (
+	"context"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/device/adapter/interface"
+	"efa-server/infra/device/client"
+
+	nlog "github.com/sirupsen/logrus"
+)
+
+//RotateBGPPasswords re-keys the BGP sessions of the fabric with the passwords in the switch details.
+//Switches are re-keyed one at a time in the order of config.Hosts, so that only one side of a session
+//is on the new password at any time. The rotation stops at the first switch that fails.
+func RotateBGPPasswords(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError {
+	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
+		"Operation": "Rotate BGP Passwords",
+	})
+	log.Info("Start")
+
+	for iter := range config.Hosts {
+		sw := config.Hosts[iter]
+		if err := rotateSwitchBGPPasswords(ctx, &sw); err != nil {
+			log.Errorf("Rotate BGP Passwords Failed on %s: %s", sw.Host, err.Error)
+			return []actions.OperationError{*err}
+		}
+		log.Infof("Rotate BGP Passwords Completed on %s", sw.Host)
+	}
+	return []actions.OperationError{}
+}
+
+func rotateSwitchBGPPasswords(ctx context.Context, sw *operation.ConfigSwitch) *actions.OperationError {
+	adapter := ad.GetAdapter(sw.Model)
+	netconfClient := &client.NetconfClient{Host: sw.Host, User: sw.UserName, Password: sw.Password}
+	if err := netconfClient.Login(); err != nil {
+		return &actions.OperationError{Operation: "Rotate BGP Passwords Login", Error: err, Host: sw.Host}
+	}
+	defer netconfClient.Close()
+
+	if err := configureBGPPeerGroupPasswords(adapter, netconfClient, sw, true); err != nil {
+		return &actions.OperationError{Operation: "BGP Peer Group Password", Error: err, Host: sw.Host}
+	}
+	if err := configureBGPMctNeighborPasswords(adapter, netconfClient, sw); err != nil {
+		return &actions.OperationError{Operation: "BGP MCT Neighbor Password", Error: err, Host: sw.Host}
+	}
+	if _, err := adapter.PersistConfig(netconfClient); err != nil {
+		return &actions.OperationError{Operation: "Persist Config", Error: err, Host: sw.Host}
+	}
+	return nil
+}
+
+//configureBGPPeerGroupPasswords configures the passwords of the underlay and overlay peer-groups of the switch.
+//Peer-groups without a password are left untouched, unless removeUnset is set.
+func configureBGPPeerGroupPasswords(adapter interfaces.Switch, netconfClient *client.NetconfClient,
+	sw *operation.ConfigSwitch, removeUnset bool) error {
+	if len(sw.PeerGroup) != 0 && (len(sw.PeerGroupPassword) != 0 || removeUnset) {
+		if _, err := adapter.ConfigureRouterBgpPeerGroupPassword(netconfClient, sw.PeerGroup,
+			sw.BGPAuthType, sw.PeerGroupPassword); err != nil {
+			return err
+		}
+	}
+	if len(sw.EvpnPeerGroup) != 0 && (len(sw.EvpnPeerGroupPassword) != 0 || removeUnset) {
+		if _, err := adapter.ConfigureRouterBgpPeerGroupPassword(netconfClient, sw.EvpnPeerGroup,
+			sw.BGPAuthType, sw.EvpnPeerGroupPassword); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//configureBGPMctNeighborPasswords configures the password of the BGP neighbours towards the MCT peer of the switch
+func configureBGPMctNeighborPasswords(adapter interfaces.Switch, netconfClient *client.NetconfClient,
+	sw *operation.ConfigSwitch) error {
+	for _, neighborAddress := range sw.MctPeerAddresses {
+		if _, err := adapter.ConfigureRouterBgpNeighborPassword(netconfClient, neighborAddress,
+			sw.BGPAuthType, sw.MctPeerPassword); err != nil {
+			return err
+		}
+	}
+	return nil
+}
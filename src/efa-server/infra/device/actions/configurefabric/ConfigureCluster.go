@@ -20,7 +20,9 @@ import (
 	netconf "efa-server/infra/device/client"
 
 	"efa-server/infra/device/adapter/interface"
+	"efa-server/infra/metrics"
 	"net"
+	"time"
 )
 
 //MgmtClusterControlVlanDesc implies the description of cluster control VLAN.
@@ -48,6 +50,7 @@ var MgmtClusterPeerIntfSpeedUpdate uint64 = 1 << 4
 func ConfigureManagementCluster(ctx context.Context, wg *sync.WaitGroup, cluster *operation.ConfigCluster,
 	force bool, clusterConfigErrors chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureManagementCluster", time.Now())
 	/* When the --force option is set,
 	all the cluster config needs to be cleaned up for the intended config to succeed. */
 	if force == true {
@@ -116,6 +119,7 @@ func isErrorPresent(sourceWaitGroup *sync.WaitGroup, sourceChannel, destinationC
 func UpdateManagementCluster(ctx context.Context, wg *sync.WaitGroup, cluster *operation.ConfigCluster,
 	force bool, clusterConfigErrors chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("UpdateManagementCluster", time.Now())
 
 	/* Config push to all the nodes of the management cluster */
 	var clusterConfigWaitGroup sync.WaitGroup
@@ -505,6 +509,7 @@ func isBitSet(operationBmp uint64, operationBit uint64) bool {
 func ConfigureDataPlaneCluster(ctx context.Context, wg *sync.WaitGroup, cluster *operation.ConfigDataPlaneCluster,
 	force bool, clusterConfigErrors chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureDataPlaneCluster", time.Now())
 
 	/* Config push to all the nodes of the management cluster */
 	var clusterConfigWaitGroup sync.WaitGroup
@@ -548,5 +553,13 @@ func configureDataPlaneClusterOnANode(ctx context.Context, clusterConfigWaitGrou
 		return
 	}
 
+	if len(mctNode.NodePeerPassword) != 0 {
+		if _, err = adapter.ConfigureRouterBgpNeighborPassword(client, mctNode.NodePeerIP, mctNode.NodePeerAuthType,
+			mctNode.NodePeerPassword); err != nil {
+			clusterConfigErrors <- actions.OperationError{Operation: "Configure Data Plane Cluster Password", Error: err, Host: mctNode.NodeMgmtIP}
+			return
+		}
+	}
+
 	return
 }
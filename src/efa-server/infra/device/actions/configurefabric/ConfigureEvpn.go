@@ -7,15 +7,18 @@ import (
 	"efa-server/infra/device/actions"
 	ad "efa-server/infra/device/adapter"
 	netconf "efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"errors"
 	"fmt"
 	nlog "github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 //ConfigureEvpn is used to configure "evpn <evpn-instance-name>" on the switch.
 func ConfigureEvpn(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, force bool, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureEvpn", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure EVPN",
 	})
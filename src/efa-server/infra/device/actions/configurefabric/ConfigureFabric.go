@@ -33,6 +33,7 @@ func ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest,
 	fabricErrors := make(chan actions.OperationError, 1)
 
 	//For each Switch Invoke Configure Switch
+	Progress := actions.StartStage(ctx, domain.StageSwitchConfigure, hostsOf(config.Hosts))
 	for iter := range config.Hosts {
 		configSwitch := config.Hosts[iter]
 		fabricGate.Add(1)
@@ -51,9 +52,7 @@ func ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest,
 	}()
 
 	//Check for errors in the sub-action
-	for err := range fabricErrors {
-		Errors = append(Errors, err)
-	}
+	Errors = append(Errors, Progress.Collect(fabricErrors)...)
 
 	if len(Errors) > 0 {
 		log.Error("Configure Fabric Failed")
@@ -69,12 +68,14 @@ func ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest,
 
 	var overlayGate sync.WaitGroup
 	overlayErrors := make(chan actions.OperationError, 1)
+	OverlayProgress := actions.StartStage(ctx, domain.StageOverlay, nil)
 	//For each Switch Invoke Configure Overlay
 	for iter := range config.Hosts {
 		sw := config.Hosts[iter]
 		markIfSwitchIsMCTSecondary(&sw, clusterStatus)
 		if sw.Role == "Leaf" && sw.ConfigureOverlayGateway == "Yes" && sw.MctSecondaryNode == false {
 			overlayGate.Add(1)
+			OverlayProgress.Start(sw.Host)
 			go ConfigureOverlayGateway(ctx, &overlayGate, &sw, force, overlayErrors)
 		}
 	}
@@ -87,14 +88,13 @@ func ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest,
 		close(overlayErrors)
 
 	}()
-	for err := range overlayErrors {
-		Errors = append(Errors, err)
-	}
+	Errors = append(Errors, OverlayProgress.Collect(overlayErrors)...)
 
 	// save the configs on all the devices
 	if persist {
 		var saveConfig sync.WaitGroup
 		saveConfigErrors := make(chan actions.OperationError, 1)
+		PersistProgress := actions.StartStage(ctx, domain.StagePersist, hostsOf(config.Hosts))
 		//For each Switch Invoke Configure Overlay
 		for iter := range config.Hosts {
 			sw := config.Hosts[iter]
@@ -109,9 +109,7 @@ func ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest,
 			close(saveConfigErrors)
 
 		}()
-		for err := range saveConfigErrors {
-			Errors = append(Errors, err)
-		}
+		Errors = append(Errors, PersistProgress.Collect(saveConfigErrors)...)
 	}
 
 	if len(Errors) > 0 {
@@ -125,20 +123,21 @@ func ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest,
 func configureMCTCluster(ctx context.Context, config operation.ConfigFabricRequest, force bool, clusterStatus map[string]bool) []actions.OperationError {
 	//Send the Config Object to Actions for configuring the switches
 	var Error []actions.OperationError
+	Progress := actions.StartStage(ctx, domain.StageMctCluster, clusterNodes(config.MctCluster))
 	if len(config.MctCluster) > 0 {
 		if len(config.MctCluster[domain.MctDelete]) > 0 {
 			if Errors := ConfigureDeConfigureMctClusters(ctx, domain.MctDelete, config.MctCluster[domain.MctDelete], force); len(Errors) != 0 {
-				return Errors
+				return Progress.Failed(Errors)
 			}
 		}
 		if len(config.MctCluster[domain.MctUpdate]) > 0 {
 			if Errors := ConfigureDeConfigureMctClusters(ctx, domain.MctUpdate, config.MctCluster[domain.MctUpdate], force); len(Errors) != 0 {
-				return Errors
+				return Progress.Failed(Errors)
 			}
 		}
 		if len(config.MctCluster[domain.MctCreate]) > 0 {
 			if Errors := ConfigureDeConfigureMctClusters(ctx, domain.MctCreate, config.MctCluster[domain.MctCreate], force); len(Errors) != 0 {
-				return Errors
+				return Progress.Failed(Errors)
 			}
 		}
 	}
@@ -156,9 +155,36 @@ func configureMCTCluster(ctx context.Context, config operation.ConfigFabricReque
 		}
 
 	}
+	Progress.Finish()
 	return Error
 }
 
+//hostsOf returns the hosts of the switches
+func hostsOf(Switches []operation.ConfigSwitch) []string {
+	Hosts := make([]string, 0, len(Switches))
+	for _, sw := range Switches {
+		Hosts = append(Hosts, sw.Host)
+	}
+	return Hosts
+}
+
+//clusterNodes returns the management addresses of the nodes of the MCT clusters
+func clusterNodes(MctCluster map[uint][]operation.ConfigCluster) []string {
+	Nodes := make([]string, 0)
+	Found := make(map[string]bool)
+	for _, opcode := range []uint{domain.MctDelete, domain.MctUpdate, domain.MctCreate} {
+		for _, cluster := range MctCluster[opcode] {
+			for _, node := range cluster.ClusterMemberNodes {
+				if !Found[node.NodeMgmtIP] {
+					Found[node.NodeMgmtIP] = true
+					Nodes = append(Nodes, node.NodeMgmtIP)
+				}
+			}
+		}
+	}
+	return Nodes
+}
+
 //ConfigureDeConfigureMctClusters "configures" and "deconfigures" MCT cluster on a collection of devices
 func ConfigureDeConfigureMctClusters(ctx context.Context, opcode uint, MctCluster []operation.ConfigCluster, force bool) []actions.OperationError {
 	MctOperation := [4]string{1: "configure", 2: "deconfigure", 3: "update"}
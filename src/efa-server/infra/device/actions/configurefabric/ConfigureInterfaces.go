@@ -9,15 +9,18 @@ import (
 	ad "efa-server/infra/device/adapter"
 	"efa-server/infra/device/adapter/interface"
 	"efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"errors"
 	"fmt"
 	nlog "github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 //ConfigureInterfaces is used to configure the physical and logical interfaces of the switch.
 func ConfigureInterfaces(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, force bool, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureInterfaces", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure Interfaces",
 	})
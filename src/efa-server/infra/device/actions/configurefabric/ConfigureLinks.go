@@ -0,0 +1,104 @@
+package configurefabric
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/device/adapter/interface"
+	"efa-server/infra/device/client"
+	"efa-server/usecase"
+	"errors"
+	"fmt"
+	"strconv"
+
+	nlog "github.com/sirupsen/logrus"
+)
+
+//ConfigureLinks configures only the interfaces and the BGP neighbors listed in the switch details, leaving
+//the rest of the switch configuration untouched. It is used for pushing the links which changed since the
+//last discovery of the fabric.
+func ConfigureLinks(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError {
+	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
+		"Operation": "Configure Links",
+	})
+	log.Info("Start")
+
+	Errors := make([]actions.OperationError, 0)
+	for iter := range config.Hosts {
+		sw := config.Hosts[iter]
+		if err := configureSwitchLinks(ctx, &sw, persist); err != nil {
+			log.Errorf("Configure Links Failed on %s: %s", sw.Host, err.Error)
+			Errors = append(Errors, *err)
+			continue
+		}
+		log.Infof("Configure Links Completed on %s", sw.Host)
+	}
+	return Errors
+}
+
+func configureSwitchLinks(ctx context.Context, sw *operation.ConfigSwitch, persist bool) *actions.OperationError {
+	adapter := ad.GetAdapter(sw.Model)
+	netconfClient := &client.NetconfClient{Host: sw.Host, User: sw.UserName, Password: sw.Password}
+	if err := netconfClient.Login(); err != nil {
+		return &actions.OperationError{Operation: "Configure Links Login", Error: err, Host: sw.Host}
+	}
+	defer netconfClient.Close()
+
+	//Neighbors are removed before their interfaces and added after them
+	if err := configureLinkBGPNeighbors(adapter, netconfClient, sw, domain.ConfigDelete); err != nil {
+		return &actions.OperationError{Operation: "BGP Neighbor", Error: err, Host: sw.Host}
+	}
+	for _, intf := range sw.Interfaces {
+		var err error
+		if intf.Donor == "" {
+			_, err = configuredInterface(adapter, netconfClient, intf.InterfaceName, intf.InterfaceType, intf.IP,
+				intf.ConfigType, intf.Description)
+		} else {
+			_, err = configureUnnumberedInterface(adapter, netconfClient, intf.InterfaceName, intf.InterfaceType,
+				intf.Donor, intf.DonorPort, intf.ConfigType)
+		}
+		if err != nil {
+			msg := fmt.Sprintf("Interface %s %s with IP address %s: %s", intf.InterfaceType, intf.InterfaceName, intf.IP, err)
+			return &actions.OperationError{Operation: "Configure Interface", Error: errors.New(msg), Host: sw.Host}
+		}
+	}
+	if err := configureLinkBGPNeighbors(adapter, netconfClient, sw, domain.ConfigCreate); err != nil {
+		return &actions.OperationError{Operation: "BGP Neighbor", Error: err, Host: sw.Host}
+	}
+
+	if persist {
+		if _, err := adapter.PersistConfig(netconfClient); err != nil {
+			return &actions.OperationError{Operation: "Persist Config", Error: err, Host: sw.Host}
+		}
+	}
+	return nil
+}
+
+//configureLinkBGPNeighbors removes the neighbors marked for delete when configType is ConfigDelete,
+//and configures the remaining neighbors otherwise
+func configureLinkBGPNeighbors(adapter interfaces.Switch, netconfClient *client.NetconfClient,
+	sw *operation.ConfigSwitch, configType string) error {
+	isLeaf := "Yes"
+	if sw.Role == usecase.SpineRole {
+		isLeaf = "No"
+	}
+	unnumberedInterface := (sw.P2PIPType == domain.P2PIpTypeUnnumbered)
+	for _, neigh := range sw.BgpNeighbors {
+		remoteAs := strconv.FormatInt(neigh.RemoteAs, 10)
+		var err error
+		if configType == domain.ConfigDelete && neigh.ConfigType == domain.ConfigDelete {
+			_, err = adapter.UnconfigureRouterBgpNeighbor(netconfClient, remoteAs, sw.PeerGroup, neigh.NeighborAddress)
+		}
+		if configType != domain.ConfigDelete && neigh.ConfigType != domain.ConfigDelete {
+			_, err = adapter.ConfigureRouterBgpNeighbor(netconfClient, remoteAs, sw.PeerGroup, neigh.NeighborAddress,
+				sw.BgpMultihop, unnumberedInterface, isLeaf, false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
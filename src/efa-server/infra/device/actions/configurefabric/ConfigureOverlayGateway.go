@@ -8,16 +8,19 @@ import (
 	ad "efa-server/infra/device/adapter"
 	"efa-server/infra/device/adapter/interface"
 	netconf "efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"errors"
 	"fmt"
 	nlog "github.com/sirupsen/logrus"
 	"strconv"
 	"sync"
+	"time"
 )
 
 //ConfigureOverlayGateway is used to configure "overlay-gateway" on the switch.
 func ConfigureOverlayGateway(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, force bool, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureOverlayGateway", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure Overlay Gateway",
 	})
@@ -7,14 +7,17 @@ import (
 	"efa-server/infra/device/actions"
 	ad "efa-server/infra/device/adapter"
 	netconf "efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"efa-server/usecase"
 	nlog "github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 //ConfigureSystemwideProperties is used to configure the systemwide properties of a switching device
 func ConfigureSystemwideProperties(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, force bool, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureSystemwideProperties", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure Switch Properties",
 	})
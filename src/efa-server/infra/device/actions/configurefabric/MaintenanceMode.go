@@ -0,0 +1,116 @@
+package configurefabric
+
+import (
+	"context"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/device/adapter/interface"
+	"efa-server/infra/device/client"
+	"errors"
+	"time"
+
+	nlog "github.com/sirupsen/logrus"
+)
+
+//MaintenanceConvergencePollingTimeOutInSec implies the timeout value for polling the BGP sessions to converge
+//after a switch is drained or restored
+var MaintenanceConvergencePollingTimeOutInSec = 180
+
+//MaintenanceConvergencePollingIntervalInSec implies the interval for polling the BGP sessions of the switch
+var MaintenanceConvergencePollingIntervalInSec = 10
+
+//ConfigureMaintenanceMode drains the traffic from the switches in config.Hosts, or restores it when enable is not set.
+//Draining shuts the overlay peer-group before the underlay peer-group, restoring opens them in the reverse order.
+//The sessions towards the MCT peer are never shut, so that the traffic of an MCT leaf moves to its peer.
+func ConfigureMaintenanceMode(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
+		"Operation": "Maintenance Mode",
+	})
+	log.Info("Start")
+
+	Errors := make([]actions.OperationError, 0)
+	for iter := range config.Hosts {
+		sw := config.Hosts[iter]
+		if err := configureSwitchMaintenanceMode(ctx, &sw, enable); err != nil {
+			log.Errorf("Maintenance Mode Failed on %s: %s", sw.Host, err.Error)
+			Errors = append(Errors, *err)
+			continue
+		}
+		log.Infof("Maintenance Mode %t Completed on %s", enable, sw.Host)
+	}
+	return Errors
+}
+
+func configureSwitchMaintenanceMode(ctx context.Context, sw *operation.ConfigSwitch, enable bool) *actions.OperationError {
+	adapter := ad.GetAdapter(sw.Model)
+	netconfClient := &client.NetconfClient{Host: sw.Host, User: sw.UserName, Password: sw.Password}
+	if err := netconfClient.Login(); err != nil {
+		return &actions.OperationError{Operation: "Maintenance Mode Login", Error: err, Host: sw.Host}
+	}
+	defer netconfClient.Close()
+
+	if err := configureBGPPeerGroupShutdown(adapter, netconfClient, sw, enable); err != nil {
+		return &actions.OperationError{Operation: "BGP Peer Group Shutdown", Error: err, Host: sw.Host}
+	}
+	if err := pollBGPConvergence(adapter, netconfClient, sw, enable); err != nil {
+		return &actions.OperationError{Operation: "Poll BGP Convergence", Error: err, Host: sw.Host}
+	}
+	if _, err := adapter.PersistConfig(netconfClient); err != nil {
+		return &actions.OperationError{Operation: "Persist Config", Error: err, Host: sw.Host}
+	}
+	return nil
+}
+
+//configureBGPPeerGroupShutdown shuts the overlay peer-group and then the underlay peer-group of the switch,
+//the reverse order is used for bringing them back up
+func configureBGPPeerGroupShutdown(adapter interfaces.Switch, netconfClient *client.NetconfClient,
+	sw *operation.ConfigSwitch, shutdown bool) error {
+	PeerGroups := []string{sw.EvpnPeerGroup, sw.PeerGroup}
+	if !shutdown {
+		PeerGroups = []string{sw.PeerGroup, sw.EvpnPeerGroup}
+	}
+	for _, PeerGroup := range PeerGroups {
+		if len(PeerGroup) == 0 {
+			continue
+		}
+		if _, err := adapter.ConfigureRouterBgpPeerGroupShutdown(netconfClient, PeerGroup, shutdown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//pollBGPConvergence waits till a drained switch has no sessions other than the ones towards its MCT peer,
+//or till a restored switch has at least one such session established
+func pollBGPConvergence(adapter interfaces.Switch, netconfClient *client.NetconfClient,
+	sw *operation.ConfigSwitch, drained bool) error {
+	MctPeers := make(map[string]bool)
+	for _, Address := range sw.MctPeerAddresses {
+		MctPeers[Address] = true
+	}
+
+	timeout := time.After(time.Duration(MaintenanceConvergencePollingTimeOutInSec) * (time.Second))
+	tick := time.Tick(time.Duration(MaintenanceConvergencePollingIntervalInSec) * (time.Second))
+	for {
+		select {
+		case <-timeout:
+			return errors.New("BGP sessions did not converge. Polling timed out")
+		case <-tick:
+			Neighbors, err := adapter.GetRouterBgpEstablishedNeighbors(netconfClient)
+			if err != nil {
+				return err
+			}
+			FabricSessions := 0
+			for _, Neighbor := range Neighbors {
+				if !MctPeers[Neighbor] {
+					FabricSessions++
+				}
+			}
+			if (drained && FabricSessions == 0) || (!drained && FabricSessions != 0) {
+				return nil
+			}
+		}
+	}
+}
@@ -12,12 +12,15 @@ import (
 	"strings"
 	"sync"
 
+	"efa-server/infra/metrics"
 	nlog "github.com/sirupsen/logrus"
+	"time"
 )
 
 //ConfigureNonClosBGP is used to configure "router bgp" config on the switch.
 func ConfigureNonClosBGP(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, force bool, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("ConfigureNonClosBGP", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Configure BGP",
 	})
@@ -54,6 +57,20 @@ func ConfigureNonClosBGP(ctx context.Context, wg *sync.WaitGroup, sw *operation.
 		errs <- actions.OperationError{Operation: Operation, Error: err, Host: sw.Host}
 	}
 
+	//BGP Authentication
+	if err = configureBGPPeerGroupPasswords(adapter, netconfClient, sw, false); err != nil {
+		log.Errorf("BGP Peer Group Password Operation Failed: %s\n", err)
+		errs <- actions.OperationError{Operation: "BGP Peer Group Password", Error: err, Host: sw.Host}
+	}
+
+	//Keep a switch in maintenance mode drained
+	if sw.MaintenanceMode {
+		if err = configureBGPPeerGroupShutdown(adapter, netconfClient, sw, true); err != nil {
+			log.Errorf("BGP Peer Group Shutdown Operation Failed: %s\n", err)
+			errs <- actions.OperationError{Operation: "BGP Peer Group Shutdown", Error: err, Host: sw.Host}
+		}
+	}
+
 	//BGP Neighbor
 	Operation = "BGP Neighbor"
 	//First Delete Neighbors
@@ -95,6 +112,10 @@ func ConfigureNonClosBGP(ctx context.Context, wg *sync.WaitGroup, sw *operation.
 			} else if neigh.NeighborType == domain.MCTL3LBType {
 				_, err = adapter.ConfigureNonClosRouterBgpNeighbor(netconfClient, remoteAs,
 					"", "", neigh.NeighborAddress, formatYesNo(sw.BFDEnable), true)
+				if err == nil && len(sw.MctPeerPassword) != 0 {
+					_, err = adapter.ConfigureRouterBgpNeighborPassword(netconfClient, neigh.NeighborAddress,
+						sw.BGPAuthType, sw.MctPeerPassword)
+				}
 			}
 			if err != nil {
 				errs <- actions.OperationError{Operation: Operation, Error: err, Host: sw.Host}
@@ -2,6 +2,7 @@ package configurefabric
 
 import (
 	"context"
+	"efa-server/domain"
 	"efa-server/domain/operation"
 	"efa-server/gateway/appcontext"
 	"efa-server/infra/device/actions"
@@ -23,6 +24,7 @@ func ConfigureNonClosFabric(ctx context.Context, config operation.ConfigFabricRe
 	fabricErrors := make(chan actions.OperationError, 1)
 
 	//For each Switch Invoke Configure Switch
+	Progress := actions.StartStage(ctx, domain.StageSwitchConfigure, hostsOf(config.Hosts))
 	for iter := range config.Hosts {
 		configSwitch := config.Hosts[iter]
 		fabricGate.Add(1)
@@ -41,9 +43,7 @@ func ConfigureNonClosFabric(ctx context.Context, config operation.ConfigFabricRe
 	}()
 
 	//Check for errors in the sub-action
-	for err := range fabricErrors {
-		Errors = append(Errors, err)
-	}
+	Errors = append(Errors, Progress.Collect(fabricErrors)...)
 
 	if len(Errors) > 0 {
 		log.Error("Configure Fabric Failed")
@@ -59,12 +59,14 @@ func ConfigureNonClosFabric(ctx context.Context, config operation.ConfigFabricRe
 
 	var overlayGate sync.WaitGroup
 	overlayErrors := make(chan actions.OperationError, 1)
+	OverlayProgress := actions.StartStage(ctx, domain.StageOverlay, nil)
 	//For each Switch Invoke Configure Overlay
 	for iter := range config.Hosts {
 		sw := config.Hosts[iter]
 		markIfSwitchIsMCTSecondary(&sw, clusterStatus)
 		if sw.Role == "Rack" && sw.ConfigureOverlayGateway == "Yes" && sw.MctSecondaryNode == false {
 			overlayGate.Add(1)
+			OverlayProgress.Start(sw.Host)
 			go ConfigureOverlayGateway(ctx, &overlayGate, &sw, force, overlayErrors)
 		}
 	}
@@ -77,14 +79,13 @@ func ConfigureNonClosFabric(ctx context.Context, config operation.ConfigFabricRe
 		close(overlayErrors)
 
 	}()
-	for err := range overlayErrors {
-		Errors = append(Errors, err)
-	}
+	Errors = append(Errors, OverlayProgress.Collect(overlayErrors)...)
 
 	// save the configs on all the devices
 	if persist {
 		var saveConfig sync.WaitGroup
 		saveConfigErrors := make(chan actions.OperationError, 1)
+		PersistProgress := actions.StartStage(ctx, domain.StagePersist, hostsOf(config.Hosts))
 		//For each Switch Invoke Configure Overlay
 		for iter := range config.Hosts {
 			sw := config.Hosts[iter]
@@ -99,9 +100,7 @@ func ConfigureNonClosFabric(ctx context.Context, config operation.ConfigFabricRe
 			close(saveConfigErrors)
 
 		}()
-		for err := range saveConfigErrors {
-			Errors = append(Errors, err)
-		}
+		Errors = append(Errors, PersistProgress.Collect(saveConfigErrors)...)
 	}
 
 	if len(Errors) > 0 {
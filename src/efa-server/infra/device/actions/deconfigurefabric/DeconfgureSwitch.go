@@ -7,16 +7,19 @@ import (
 	"efa-server/infra/device/actions"
 	ad "efa-server/infra/device/adapter"
 	netconf "efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"efa-server/usecase"
 	"errors"
 	"fmt"
 	nlog "github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 //UnconfigureSwitch is used to unconfigure both underlay and overlay on a switch.
 func UnconfigureSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw operation.ConfigSwitch, force bool, fabricError chan actions.OperationError, persist bool) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("UnconfigureSwitch", time.Now())
 
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"App":       "dcfabric",
@@ -65,6 +68,7 @@ func UnconfigureSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw opera
 
 func persistConfig(ctx context.Context, wg *sync.WaitGroup, sw *operation.ConfigSwitch, errs chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("PersistConfig", time.Now())
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"Operation": "Persist Config",
 	})
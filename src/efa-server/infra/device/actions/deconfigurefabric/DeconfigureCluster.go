@@ -10,13 +10,16 @@ import (
 	ad "efa-server/infra/device/adapter"
 	"efa-server/infra/device/adapter/interface"
 	netconf "efa-server/infra/device/client"
+	"efa-server/infra/metrics"
 	"sync"
+	"time"
 )
 
 //UnconfigureManagementCluster is used to unconfigure "cluster <name> <id>" and all its sub-config on the switch.
 func UnconfigureManagementCluster(ctx context.Context, wg *sync.WaitGroup, cluster *operation.ConfigCluster,
 	force bool, clusterConfigErrors chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("UnconfigureManagementCluster", time.Now())
 	var clusterConfigWaitGroup sync.WaitGroup
 	for iter := range cluster.ClusterMemberNodes {
 		mctNode := cluster.ClusterMemberNodes[iter]
@@ -142,6 +145,7 @@ func unconfigureClusterPeerInterface(log *nlog.Entry, adapter interfaces.Switch,
 func UnconfigureDataPlaneCluster(ctx context.Context, wg *sync.WaitGroup, cluster *operation.ConfigDataPlaneCluster,
 	force bool, clusterConfigErrors chan actions.OperationError) {
 	defer wg.Done()
+	defer metrics.ObserveDeviceAction("UnconfigureDataPlaneCluster", time.Now())
 
 	/* Config push to all the nodes of the management cluster */
 	var clusterConfigWaitGroup sync.WaitGroup
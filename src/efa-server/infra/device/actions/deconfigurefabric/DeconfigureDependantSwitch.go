@@ -14,12 +14,15 @@ import (
 	"sync"
 
 	"efa-server/infra/device/adapter/interface"
+	"efa-server/infra/metrics"
+	"time"
 )
 
 //UnconfigureDependantSwitch cleans up interfaces and bgp neighbors from dependant Device
 func UnconfigureDependantSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw operation.ConfigSwitch,
 	force bool, errs chan actions.OperationError, persist bool) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("UnconfigureDependantSwitch", time.Now())
 	ctx = context.WithValue(ctx, appcontext.DeviceName, sw.Host)
 	log := appcontext.Logger(ctx)
 	adapter := ad.GetAdapter(sw.Model)
@@ -5,14 +5,17 @@ import (
 	"efa-server/domain/operation"
 	"efa-server/gateway/appcontext"
 	"efa-server/infra/device/actions"
+	"efa-server/infra/metrics"
 	"efa-server/usecase"
 	nlog "github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 //UnconfigureNonCLOSSwitch is used to unconfigure both underlay and overlay on a switch.
 func UnconfigureNonCLOSSwitch(ctx context.Context, fabricGate *sync.WaitGroup, sw operation.ConfigSwitch, force bool, fabricError chan actions.OperationError, persist bool) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("UnconfigureNonCLOSSwitch", time.Now())
 
 	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
 		"App":       "dcfabric",
@@ -0,0 +1,102 @@
+package fabrichealth
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/device/client"
+	"efa-server/infra/metrics"
+	"efa-server/usecase"
+	nlog "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+//FetchFabricHealth collects the operational state of a set of switches of a given fabric.
+func FetchFabricHealth(ctx context.Context, FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse,
+	[]actions.OperationError) {
+	log := appcontext.Logger(ctx).WithFields(nlog.Fields{
+		"App":       "dcfabric",
+		"Fabric":    FabricRequest.FabricName,
+		"Operation": "Fabric Health",
+	})
+
+	log.Info("Start")
+
+	//List to hold errors from sub-actions
+	Errors := make([]actions.OperationError, 0)
+
+	//Concurrency gate for sub-actions
+	var fabricGate sync.WaitGroup
+	fabricErrors := make(chan actions.OperationError, len(FabricRequest.Hosts))
+	switchResponses := make(chan operation.SwitchHealthResponse, len(FabricRequest.Hosts))
+
+	//For each Switch Invoke Fetch Switch Health
+	for _, switchIdentity := range FabricRequest.Hosts {
+		fabricGate.Add(1)
+		go FetchSwitchHealth(ctx, &fabricGate, switchIdentity, FabricRequest.Overlay, switchResponses, fabricErrors)
+	}
+
+	log.Info("Waiting for Switch Operations")
+	fabricGate.Wait()
+	close(fabricErrors)
+	close(switchResponses)
+	log.Info("Wait Completed")
+
+	for err := range fabricErrors {
+		Errors = append(Errors, err)
+	}
+	Response := operation.FabricHealthResponse{FabricName: FabricRequest.FabricName}
+	Response.SwitchResponse = make([]operation.SwitchHealthResponse, 0, len(FabricRequest.Hosts))
+	for resp := range switchResponses {
+		Response.SwitchResponse = append(Response.SwitchResponse, resp)
+	}
+	return Response, Errors
+}
+
+//FetchSwitchHealth collects the operational state of the switch. The failure to collect a state is recorded
+//with the state, only the failure to login fails the switch.
+func FetchSwitchHealth(ctx context.Context, fabricGate *sync.WaitGroup, sw operation.SwitchIdentity, overlay bool,
+	switchResponses chan operation.SwitchHealthResponse, errs chan actions.OperationError) {
+	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("FetchSwitchHealth", time.Now())
+	adapter := ad.GetAdapter(sw.Model)
+	netconfClient := &client.NetconfClient{Host: sw.Host, User: sw.UserName, Password: sw.Password}
+	if err := netconfClient.Login(); err != nil {
+		errs <- actions.OperationError{Operation: "Fabric Health Login", Error: err, Host: sw.Host}
+		return
+	}
+	defer netconfClient.Close()
+
+	Response := operation.SwitchHealthResponse{Host: sw.Host, Role: sw.Role, Errors: make(map[string]string)}
+	record := func(Check string, err error) {
+		if err != nil {
+			Response.Errors[Check] = err.Error()
+		}
+	}
+	var err error
+	Response.BgpNeighbors, err = adapter.GetRouterBgpNeighbors(netconfClient)
+	record(domain.HealthCheckBGP, err)
+	Response.EvpnNeighbors, err = adapter.GetRouterBgpL2EVPNNeighbors(netconfClient)
+	record(domain.HealthCheckEVPN, err)
+	Response.BfdSessions, err = adapter.GetBfdSessions(netconfClient)
+	record(domain.HealthCheckBFD, err)
+	Response.RouteCount, err = adapter.GetIPRouteCount(netconfClient)
+	record(domain.HealthCheckRoutes, err)
+	if sw.Role == usecase.LeafRole || sw.Role == usecase.RackRole {
+		_, Response.MgmtCluster, _, err = adapter.GetManagementClusterStatus(netconfClient)
+		record(domain.HealthCheckMCT, err)
+		if err == nil {
+			Response.Cluster, err = adapter.GetClusterStatus(netconfClient)
+			record(domain.HealthCheckMCT, err)
+		}
+		if overlay {
+			Response.Tunnels, err = adapter.GetOverlayGatewayTunnels(netconfClient)
+			record(domain.HealthCheckOverlay, err)
+		}
+	}
+	switchResponses <- Response
+}
@@ -13,6 +13,20 @@ type BGP interface {
 		evpn string, allowasIn string, retainRouteTargetAll string, nextHopUnchanged string, bfdEnable string,
 		bfdMinTx string, bfdMinRx string, bfdMultiplier string, detrisibuteConnected string, detrisibuteConnectedWithRouteMap string) (string, error)
 
+	//ConfigureRouterBgpPeerGroupPassword is used to configure the authentication password of a "router bgp" peer-group,
+	//an empty password removes the authentication
+	ConfigureRouterBgpPeerGroupPassword(client *client.NetconfClient, peerGroupName string, authType string, password string) (string, error)
+
+	//ConfigureRouterBgpNeighborPassword is used to configure the authentication password of a "router bgp" neighbour,
+	//an empty password removes the authentication
+	ConfigureRouterBgpNeighborPassword(client *client.NetconfClient, neighborAddress string, authType string, password string) (string, error)
+
+	//ConfigureRouterBgpPeerGroupShutdown is used to shut or re-enable all the sessions of a "router bgp" peer-group
+	ConfigureRouterBgpPeerGroupShutdown(client *client.NetconfClient, peerGroupName string, shutdown bool) (string, error)
+
+	//GetRouterBgpEstablishedNeighbors is used to get the addresses of the BGP neighbours in established state
+	GetRouterBgpEstablishedNeighbors(client *client.NetconfClient) ([]string, error)
+
 	//UnconfigureRouterBgp is used to unconfigure "router bgp" from the switching device
 	UnconfigureRouterBgp(client *client.NetconfClient) (string, error)
 
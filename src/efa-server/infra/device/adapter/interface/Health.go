@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"efa-server/domain/operation"
+	"efa-server/infra/device/client"
+)
+
+//Health provides collection of methods fetching the operational state of the switching device
+type Health interface {
+	//GetRouterBgpNeighbors is used to get the IPv4 BGP neighbours with their state and up time
+	GetRouterBgpNeighbors(client *client.NetconfClient) ([]operation.ConfigBgpNeighbor, error)
+
+	//GetRouterBgpL2EVPNNeighbors is used to get the L2VPN EVPN BGP neighbours with their state and up time
+	GetRouterBgpL2EVPNNeighbors(client *client.NetconfClient) ([]operation.ConfigBgpNeighbor, error)
+
+	//GetBfdSessions is used to get the BFD sessions with their state
+	GetBfdSessions(client *client.NetconfClient) ([]operation.BfdSession, error)
+
+	//GetClusterStatus is used to get the operational state of the MCT data plane cluster and of its peer
+	GetClusterStatus(client *client.NetconfClient) (operation.ClusterStatus, error)
+
+	//GetOverlayGatewayTunnels is used to get the tunnels of the overlay gateway with their state
+	GetOverlayGatewayTunnels(client *client.NetconfClient) ([]operation.TunnelStatus, error)
+
+	//GetIPRouteCount is used to get the number of IPv4 routes in the routing table
+	GetIPRouteCount(client *client.NetconfClient) (int, error)
+}
@@ -10,6 +10,7 @@ import (
 type Interface interface {
 	GetInterfaces(client *client.NetconfClient, ControlVlan string) ([]models.InterfaceSwitchResponse, error)
 	GetInterfaceSpeed(client *client.NetconfClient, interfaceType string, interfaceName string) (int, error)
+	GetInterfaceMtus(client *client.NetconfClient) (map[string]int, error)
 	PersistConfig(client *client.NetconfClient) (map[string]string, error)
 	ConfigureInterfaceLoopback(client *client.NetconfClient, loopbackID string, ipAddress string) (string, error)
 	DeleteInterfaceLoopback(client *client.NetconfClient, loopbackID string) (string, error)
@@ -8,4 +8,5 @@ type Switch interface {
 	Interface
 	Cluster
 	System
+	Health
 }
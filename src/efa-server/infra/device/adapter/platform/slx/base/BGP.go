@@ -6,6 +6,7 @@ import (
 	"efa-server/infra/device/client"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/beevik/etree"
 )
@@ -40,6 +41,73 @@ func (base *SLXBase) ConfigureRouterBgp(client *client.NetconfClient, localAs st
 
 }
 
+//ConfigureRouterBgpPeerGroupPassword is used to configure the authentication password of a "router bgp" peer-group,
+//an empty password removes the authentication
+func (base *SLXBase) ConfigureRouterBgpPeerGroupPassword(client *client.NetconfClient, peerGroupName string,
+	authType string, password string) (string, error) {
+	var bgpMap = map[string]interface{}{"peer_group_name": peerGroupName, "password": password}
+
+	config, templateError := base.GetStringFromTemplate(routerBgpPeerGroupPassword, bgpMap)
+	if templateError != nil {
+		return "", templateError
+	}
+
+	resp, err := client.EditConfig(config)
+	return resp, err
+}
+
+//ConfigureRouterBgpNeighborPassword is used to configure the authentication password of a "router bgp" neighbour,
+//an empty password removes the authentication
+func (base *SLXBase) ConfigureRouterBgpNeighborPassword(client *client.NetconfClient, neighborAddress string,
+	authType string, password string) (string, error) {
+	var bgpMap = map[string]interface{}{"neighbor_address": neighborAddress, "password": password}
+
+	config, templateError := base.GetStringFromTemplate(routerBgpNeighborPassword, bgpMap)
+	if templateError != nil {
+		return "", templateError
+	}
+
+	resp, err := client.EditConfig(config)
+	return resp, err
+}
+
+//ConfigureRouterBgpPeerGroupShutdown is used to shut or re-enable all the sessions of a "router bgp" peer-group
+func (base *SLXBase) ConfigureRouterBgpPeerGroupShutdown(client *client.NetconfClient, peerGroupName string,
+	shutdown bool) (string, error) {
+	var bgpMap = map[string]interface{}{"peer_group_name": peerGroupName, "shutdown": fmt.Sprint(shutdown)}
+
+	config, templateError := base.GetStringFromTemplate(routerBgpPeerGroupShutdown, bgpMap)
+	if templateError != nil {
+		return "", templateError
+	}
+
+	resp, err := client.EditConfig(config)
+	return resp, err
+}
+
+//GetRouterBgpEstablishedNeighbors is used to get the addresses of the BGP neighbours in established state
+func (base *SLXBase) GetRouterBgpEstablishedNeighbors(client *client.NetconfClient) ([]string, error) {
+	Neighbors := make([]string, 0)
+	request := `<get-ip-bgp-neighbor-brief xmlns="urn:brocade.com:mgmt:brocade-bgp-operational"></get-ip-bgp-neighbor-brief>`
+	resp, err := client.ExecuteRPC(request)
+	if err != nil {
+		return Neighbors, err
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes([]byte(resp)); err != nil {
+		return Neighbors, err
+	}
+
+	for _, elem := range doc.FindElements("//neighbor-summary") {
+		address := elem.FindElement(".//neighbor-ip-addr")
+		state := elem.FindElement(".//neighbor-state")
+		if address != nil && state != nil && strings.HasPrefix(strings.ToUpper(state.Text()), "ESTAB") {
+			Neighbors = append(Neighbors, address.Text())
+		}
+	}
+	return Neighbors, nil
+}
+
 //UnconfigureRouterBgp is used to unconfigure "router bgp" from the switching device
 func (base *SLXBase) UnconfigureRouterBgp(client *client.NetconfClient) (string, error) {
 	var bgpMap = map[string]interface{}{}
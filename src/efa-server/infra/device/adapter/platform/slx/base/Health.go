@@ -0,0 +1,119 @@
+package base
+
+import (
+	"efa-server/domain/operation"
+	"efa-server/infra/device/client"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+//executeOperationalRPC executes an RPC fetching operational state and returns its response as a document
+func executeOperationalRPC(client *client.NetconfClient, request string) (*etree.Document, error) {
+	resp, err := client.ExecuteRPC(request)
+	if err != nil {
+		return nil, err
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes([]byte(resp)); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+//elementText returns the text of the element at the path, empty when the element is absent
+func elementText(elem *etree.Element, path string) string {
+	if found := elem.FindElement(path); found != nil {
+		return strings.TrimSpace(found.Text())
+	}
+	return ""
+}
+
+//bgpNeighbors returns the neighbours of a BGP neighbor brief RPC
+func bgpNeighbors(client *client.NetconfClient, request string, neighborType string) ([]operation.ConfigBgpNeighbor, error) {
+	Neighbors := make([]operation.ConfigBgpNeighbor, 0)
+	doc, err := executeOperationalRPC(client, request)
+	if err != nil {
+		return Neighbors, err
+	}
+	for _, elem := range doc.FindElements("//neighbor-summary") {
+		Neighbor := operation.ConfigBgpNeighbor{NeighborAddress: elementText(elem, ".//neighbor-ip-addr"),
+			State: elementText(elem, ".//neighbor-state"), NeighborUpTime: elementText(elem, ".//neighbor-up-time"),
+			NeighborType: neighborType}
+		Neighbor.RemoteAs, _ = strconv.ParseInt(elementText(elem, ".//neighbor-as"), 10, 64)
+		if len(Neighbor.NeighborAddress) != 0 {
+			Neighbors = append(Neighbors, Neighbor)
+		}
+	}
+	return Neighbors, nil
+}
+
+//GetRouterBgpNeighbors is used to get the IPv4 BGP neighbours with their state and up time
+func (base *SLXBase) GetRouterBgpNeighbors(client *client.NetconfClient) ([]operation.ConfigBgpNeighbor, error) {
+	request := `<get-ip-bgp-neighbor-brief xmlns="urn:brocade.com:mgmt:brocade-bgp-operational"></get-ip-bgp-neighbor-brief>`
+	return bgpNeighbors(client, request, "ipv4")
+}
+
+//GetRouterBgpL2EVPNNeighbors is used to get the L2VPN EVPN BGP neighbours with their state and up time
+func (base *SLXBase) GetRouterBgpL2EVPNNeighbors(client *client.NetconfClient) ([]operation.ConfigBgpNeighbor, error) {
+	request := `<get-bgp-evpn-neighbor-brief xmlns="urn:brocade.com:mgmt:brocade-bgp-operational"></get-bgp-evpn-neighbor-brief>`
+	return bgpNeighbors(client, request, "evpn")
+}
+
+//GetBfdSessions is used to get the BFD sessions with their state
+func (base *SLXBase) GetBfdSessions(client *client.NetconfClient) ([]operation.BfdSession, error) {
+	Sessions := make([]operation.BfdSession, 0)
+	request := `<get-bfd-session-brief xmlns="urn:brocade.com:mgmt:brocade-bfd-operational"></get-bfd-session-brief>`
+	doc, err := executeOperationalRPC(client, request)
+	if err != nil {
+		return Sessions, err
+	}
+	for _, elem := range doc.FindElements("//bfd-session") {
+		Sessions = append(Sessions, operation.BfdSession{NeighborAddress: elementText(elem, ".//neighbor-ip-addr"),
+			Interface: elementText(elem, ".//interface-name"), State: elementText(elem, ".//session-state")})
+	}
+	return Sessions, nil
+}
+
+//GetClusterStatus is used to get the operational state of the MCT data plane cluster and of its peer
+func (base *SLXBase) GetClusterStatus(client *client.NetconfClient) (operation.ClusterStatus, error) {
+	var Status operation.ClusterStatus
+	request := `<show-cluster xmlns="urn:brocade.com:mgmt:brocade-mct-operational"></show-cluster>`
+	doc, err := executeOperationalRPC(client, request)
+	if err != nil {
+		return Status, err
+	}
+	if elem := doc.FindElement("//cluster"); elem != nil {
+		Status = operation.ClusterStatus{Name: elementText(elem, ".//cluster-name"), ID: elementText(elem, ".//cluster-id"),
+			PeerIP: elementText(elem, ".//peer-ip"), PeerState: elementText(elem, ".//peer-state"),
+			State: elementText(elem, ".//cluster-state")}
+	}
+	return Status, nil
+}
+
+//GetOverlayGatewayTunnels is used to get the tunnels of the overlay gateway with their state
+func (base *SLXBase) GetOverlayGatewayTunnels(client *client.NetconfClient) ([]operation.TunnelStatus, error) {
+	Tunnels := make([]operation.TunnelStatus, 0)
+	request := `<get-tunnel-info xmlns="urn:brocade.com:mgmt:brocade-tunnels-ext"></get-tunnel-info>`
+	doc, err := executeOperationalRPC(client, request)
+	if err != nil {
+		return Tunnels, err
+	}
+	for _, elem := range doc.FindElements("//tunnel") {
+		Tunnels = append(Tunnels, operation.TunnelStatus{ID: elementText(elem, "id"), Source: elementText(elem, ".//src-ip"),
+			Destination: elementText(elem, ".//dest-ip"), AdminState: elementText(elem, ".//admin-state"),
+			OperState: elementText(elem, ".//oper-state")})
+	}
+	return Tunnels, nil
+}
+
+//GetIPRouteCount is used to get the number of IPv4 routes in the routing table
+func (base *SLXBase) GetIPRouteCount(client *client.NetconfClient) (int, error) {
+	request := `<get-ip-route-summary xmlns="urn:brocade.com:mgmt:brocade-rtm-operational"></get-ip-route-summary>`
+	doc, err := executeOperationalRPC(client, request)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(elementText(&doc.Element, "//total-routes"))
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/beevik/etree"
+	"strconv"
 	"strings"
 )
 
@@ -74,10 +75,10 @@ func (base *SLXBase) getInterfacesMac(client *client.NetconfClient) (map[string]
 				}
 
 			}
-			if de := doc.FindElement("//has-more"); de != nil {
-
-				hasMore = de.Text()
-			}
+		}
+		hasMore = "false"
+		if de := doc.FindElement("//has-more"); de != nil {
+			hasMore = de.Text()
 		}
 	}
 
@@ -178,6 +179,33 @@ func (base *SLXBase) GetInterfaceSpeed(client *client.NetconfClient, interfaceTy
 	return 0, nil
 }
 
+//GetInterfaceMtus is used to get the MTU configured on the ethernet interfaces, keyed by the name of the interface.
+//The interfaces running the system MTU are not returned.
+func (base *SLXBase) GetInterfaceMtus(client *client.NetconfClient) (map[string]int, error) {
+	Mtus := make(map[string]int)
+	resp, err := client.GetConfig("/interface/ethernet/mtu")
+	if err != nil {
+		return Mtus, err
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes([]byte(resp)); err != nil {
+		return Mtus, err
+	}
+	for _, Ethernet := range doc.FindElements("//ethernet") {
+		Name, Mtu := Ethernet.SelectElement("name"), Ethernet.SelectElement("mtu")
+		if Name == nil || Mtu == nil {
+			continue
+		}
+		Value, err := strconv.Atoi(strings.TrimSpace(Mtu.Text()))
+		if err != nil {
+			return Mtus, err
+		}
+		Mtus[strings.TrimSpace(Name.Text())] = Value
+	}
+	return Mtus, nil
+}
+
 func (base *SLXBase) getMinInterfaceSpeed(client *client.NetconfClient, inputInterfaces []operation.InterNodeLinkPort) (int, error) {
 	if len(inputInterfaces) == 0 {
 		return 0, errors.New("Input interface array is empty")
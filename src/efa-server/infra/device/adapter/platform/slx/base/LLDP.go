@@ -91,11 +91,11 @@ func (base *SLXBase) GetLLDPNeighbors(client *client.NetconfClient) ([]models.In
 				}
 				LLLDPS = append(LLLDPS, lldp)
 			}
-
-			if de := doc.FindElement("//has-more"); de != nil {
-
-				hasMore = de.Text()
-			}
+		}
+		//A switch without neighbours has no more to return
+		hasMore = "false"
+		if de := doc.FindElement("//has-more"); de != nil {
+			hasMore = de.Text()
 		}
 	}
 
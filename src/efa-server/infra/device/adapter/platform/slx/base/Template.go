@@ -526,6 +526,81 @@ var routerBgpNeighborDelete = `
 </config>
 `
 
+var routerBgpPeerGroupPassword = `
+<config>
+       <routing-system xmlns="urn:brocade.com:mgmt:brocade-common-def">
+         <router>
+            <router-bgp xmlns="urn:brocade.com:mgmt:brocade-bgp">
+               <router-bgp-attributes>
+                      <neighbor>
+                        <peer-grps>
+                          <neighbor-peer-grp>
+                           <router-bgp-neighbor-peer-grp>{{.peer_group_name}}</router-bgp-neighbor-peer-grp>
+                           {{if ne .password ""}}
+                           <password>{{html .password}}</password>
+                           {{else}}
+                           <password operation="remove"/>
+                           {{end}}
+                          </neighbor-peer-grp>
+                        </peer-grps>
+                     </neighbor>
+                  </router-bgp-attributes>
+            </router-bgp>
+         </router>
+      </routing-system>
+</config>
+`
+
+var routerBgpNeighborPassword = `
+<config>
+       <routing-system xmlns="urn:brocade.com:mgmt:brocade-common-def">
+         <router>
+            <router-bgp xmlns="urn:brocade.com:mgmt:brocade-bgp">
+               <router-bgp-attributes>
+                      <neighbor>
+                        <neighbor-ips>
+                           <neighbor-addr>
+                              <router-bgp-neighbor-address>{{.neighbor_address}}</router-bgp-neighbor-address>
+                              {{if ne .password ""}}
+                              <password>{{html .password}}</password>
+                              {{else}}
+                              <password operation="remove"/>
+                              {{end}}
+                           </neighbor-addr>
+                        </neighbor-ips>
+                     </neighbor>
+                  </router-bgp-attributes>
+            </router-bgp>
+         </router>
+      </routing-system>
+</config>
+`
+
+var routerBgpPeerGroupShutdown = `
+<config>
+       <routing-system xmlns="urn:brocade.com:mgmt:brocade-common-def">
+         <router>
+            <router-bgp xmlns="urn:brocade.com:mgmt:brocade-bgp">
+               <router-bgp-attributes>
+                      <neighbor>
+                        <peer-grps>
+                          <neighbor-peer-grp>
+                           <router-bgp-neighbor-peer-grp>{{.peer_group_name}}</router-bgp-neighbor-peer-grp>
+                           {{if eq .shutdown "true"}}
+                           <shutdown/>
+                           {{else}}
+                           <shutdown operation="remove"/>
+                           {{end}}
+                          </neighbor-peer-grp>
+                        </peer-grps>
+                     </neighbor>
+                  </router-bgp-attributes>
+            </router-bgp>
+         </router>
+      </routing-system>
+</config>
+`
+
 var routerBgpDelete = `
 <config>	      
      <routing-system xmlns="urn:brocade.com:mgmt:brocade-common-def">
@@ -1,10 +1,12 @@
 package client
 
 import (
+	"efa-server/infra/metrics"
 	"errors"
 	"fmt"
 	"log"
 	"net"
+	"runtime"
 
 	"github.com/beevik/etree"
 	"github.com/svatantra/go-netconf/netconf"
@@ -17,6 +19,9 @@ const (
 	NetConfError = "netconf rpc [error] "
 )
 
+//Dial opens the connections to the switches, replaced to reach simulated switches
+var Dial = net.Dial
+
 //NetconfClient contains the info needed to establish, maintain and close the Netconf session to the switch.
 type NetconfClient struct {
 	Host     string
@@ -38,9 +43,19 @@ func (n *NetconfClient) Login() error {
 		},
 	}
 
-	s, err := netconf.DialSSH(n.Host, sshConfig)
+	Address := n.Host
+	if !strings.Contains(Address, ":") {
+		Address += ":830"
+	}
+	conn, err := Dial("tcp", Address)
+	if err != nil {
+		log.Println("Failed to Login", err)
+		return err
+	}
+	s, err := netconf.NewSSHSession(conn, sshConfig)
 
 	if err != nil {
+		conn.Close()
 		log.Println("Failed to Login", err)
 	}
 	n.Session = s
@@ -59,6 +74,7 @@ func (n *NetconfClient) GetConfig(data string) (string, error) {
 	//fmt.Println(request)
 
 	reply, err := n.Session.Exec(netconf.RawMethod(request))
+	metrics.CountNetconfRPC(adapterMethod(), err)
 	//t.Println(reply)
 	if err != nil {
 		return "", err
@@ -80,6 +96,7 @@ func (n *NetconfClient) EditConfig(data string) (string, error) {
 
 	request := preConfig + data + postConfig
 	reply, respErr := n.Session.Exec(netconf.RawMethod(request))
+	metrics.CountNetconfRPC(adapterMethod(), respErr)
 
 	if respErr != nil {
 		respMessage := respErr.Error()
@@ -126,6 +143,7 @@ func extractMessage(respMessage string, subString string) string {
 func (n *NetconfClient) ExecuteRPC(data string) (string, error) {
 
 	reply, err := n.Session.Exec(netconf.RawMethod(data))
+	metrics.CountNetconfRPC(adapterMethod(), err)
 
 	if err != nil {
 		return "", err
@@ -135,6 +153,24 @@ func (n *NetconfClient) ExecuteRPC(data string) (string, error) {
 
 }
 
+//adapterMethod returns the name of the adapter method sending a RPC, the first caller outside of this package
+func adapterMethod() string {
+	pc := make([]uintptr, 8)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, clientPackage) {
+			return frame.Function[strings.LastIndex(frame.Function, ".")+1:]
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+//clientPackage prefixes the names of the functions of this package
+const clientPackage = "efa-server/infra/device/client."
+
 //Close will be used to close the Netconf session to the switch.
 func (n *NetconfClient) Close() error {
 	return n.Session.Close()
@@ -33,12 +33,19 @@ func (n *SSHClient) Login() error {
 	}
 
 	// Connect to the remote server and perform the SSH handshake.
-	client, err := ssh.Dial("tcp", n.Host+":22", config)
+	conn, err := Dial("tcp", n.Host+":22")
+	if err != nil {
+		log.Fatal("Failed to Dial: ", err)
+		return err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, n.Host+":22", config)
 
 	if err != nil {
+		conn.Close()
 		log.Fatal("Failed to Dial: ", err)
 		return err
 	}
+	client := ssh.NewClient(c, chans, reqs)
 	n.Client = client
 
 	session, err := client.NewSession()
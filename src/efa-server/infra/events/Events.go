@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"sync"
+	"time"
+)
+
+//Retention is how long the events of an ended execution remain available to the streams
+var Retention = 10 * time.Minute
+
+//stream holds the events of an execution
+type stream struct {
+	events []domain.ExecutionEvent
+	ended  time.Time
+	//changed is closed and replaced on each event
+	changed chan struct{}
+}
+
+//broker keeps the events of the running and the recently ended executions
+var broker = struct {
+	sync.Mutex
+	streams map[string]*stream
+	//begun is closed and replaced on the start of each execution
+	begun chan struct{}
+}{streams: make(map[string]*stream), begun: make(chan struct{})}
+
+//Begin starts the stream of the events of an execution. The streams ended for longer than the Retention
+//are dropped.
+func Begin(ExecutionID string, Command string) {
+	broker.Lock()
+	defer broker.Unlock()
+	for ID, Stream := range broker.streams {
+		if !Stream.ended.IsZero() && time.Since(Stream.ended) > Retention {
+			delete(broker.streams, ID)
+		}
+	}
+	if _, found := broker.streams[ExecutionID]; found {
+		return
+	}
+	broker.streams[ExecutionID] = &stream{changed: make(chan struct{})}
+	publish(domain.ExecutionEvent{ExecutionID: ExecutionID, Type: domain.ExecutionEventExecution, Command: Command,
+		Status: domain.ExecutionEventRunning})
+	close(broker.begun)
+	broker.begun = make(chan struct{})
+}
+
+//End ends the stream of an execution with its status and the errors of its devices
+func End(ExecutionID string, Command string, Status string, Message string, Errors []domain.DeviceError) {
+	broker.Lock()
+	defer broker.Unlock()
+	if Stream, found := broker.streams[ExecutionID]; found && Stream.ended.IsZero() {
+		publish(domain.ExecutionEvent{ExecutionID: ExecutionID, Type: domain.ExecutionEventExecution, Command: Command,
+			Status: Status, Message: Message, Errors: Errors})
+		Stream.ended = time.Now()
+	}
+}
+
+//Publish adds an event to the stream of the execution of the context, the event is dropped when the request
+//of the context is not a running execution
+func Publish(ctx context.Context, Event domain.ExecutionEvent) {
+	ExecutionID, _ := ctx.Value(appcontext.RequestIDKey).(string)
+	broker.Lock()
+	defer broker.Unlock()
+	if Stream, found := broker.streams[ExecutionID]; found && Stream.ended.IsZero() {
+		Event.ExecutionID = ExecutionID
+		publish(Event)
+	}
+}
+
+//publish numbers and records the event and wakes up the subscriptions, the broker is locked
+func publish(Event domain.ExecutionEvent) {
+	Stream := broker.streams[Event.ExecutionID]
+	Event.Sequence = uint64(len(Stream.events) + 1)
+	if Event.Time.IsZero() {
+		Event.Time = time.Now()
+	}
+	Stream.events = append(Stream.events, Event)
+	close(Stream.changed)
+	Stream.changed = make(chan struct{})
+}
+
+//Subscription reads the events of an execution from the first one
+type Subscription struct {
+	stream *stream
+	next   int
+}
+
+//Subscribe returns the subscription to the events of an execution, waiting for the execution to start until
+//the context is done. It returns false when the execution did not start.
+func Subscribe(ctx context.Context, ExecutionID string) (*Subscription, bool) {
+	for {
+		broker.Lock()
+		Stream, found := broker.streams[ExecutionID]
+		begun := broker.begun
+		broker.Unlock()
+		if found {
+			return &Subscription{stream: Stream}, true
+		}
+		select {
+		case <-begun:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+//Next returns the next event, waiting for it until the context is done. It returns false once the event
+//ending the execution was returned, or when the context is done.
+func (Sub *Subscription) Next(ctx context.Context) (domain.ExecutionEvent, bool) {
+	for {
+		broker.Lock()
+		Events, ended, changed := Sub.stream.events, !Sub.stream.ended.IsZero(), Sub.stream.changed
+		broker.Unlock()
+		if Sub.next < len(Events) {
+			Sub.next++
+			return Events[Sub.next-1], true
+		}
+		if ended {
+			return domain.ExecutionEvent{}, false
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return domain.ExecutionEvent{}, false
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"efa-server/gateway/appcontext"
 	"efa-server/infra"
 	"efa-server/infra/constants"
+	"efa-server/infra/events"
+	"efa-server/infra/metrics"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
@@ -33,11 +35,16 @@ type AuditLog struct {
 	Logger    *logrus.Entry
 	ReqID     string
 	Log       *domain.ExecutionLog
+	//DeviceErrors lists the failures of the devices, notified along with the failure of the request
+	DeviceErrors []domain.DeviceError
 }
 
-//LogMessageInit initializes the AuditLog and setups the logger with the Request
+//LogMessageInit initializes the AuditLog and setups the logger with the Request. A ReqID set beforehand, chosen
+//by the client, is kept.
 func (alog *AuditLog) LogMessageInit() context.Context {
-	alog.ReqID = uuid.New().String()
+	if alog.ReqID == "" {
+		alog.ReqID = uuid.New().String()
+	}
 	Logger, ctx := appcontext.LoggerAndContext(alog.ReqID)
 	alog.Logger = Logger.WithFields(logrus.Fields{
 		"request": alog.Request,
@@ -58,15 +65,43 @@ func (alog *AuditLog) logStartAudit(status string) {
 	alog.Log = &domain.ExecutionLog{UUID: alog.ReqID, StartTime: alog.StartTime.Format(constants.DefaultTimeFormat),
 		Status: status, Command: alog.Request.Command, Params: string(RequestBytes)}
 
+	events.Begin(alog.ReqID, alog.Request.Command)
 	infra.GetUseCaseInteractor().Db.CreateExecutionLog(alog.Log)
-
+	alog.notify(domain.EventExecutionStarted)
 }
 func (alog *AuditLog) logEndAudit(status string) {
 
 	alog.Log.EndTime = time.Now().Format(constants.DefaultTimeFormat)
 	duration := time.Since(alog.StartTime)
 	alog.Log.Status = fmt.Sprintf("%s(%s)", status, duration.String())
+	metrics.ObserveOperation(alog.Request.Command, status, duration)
 	infra.GetUseCaseInteractor().Db.UpdateExecutionLog(alog.Log)
+	if status == COMPLETED {
+		events.End(alog.ReqID, alog.Request.Command, domain.ExecutionEventSucceeded, alog.Log.Status, nil)
+	} else {
+		events.End(alog.ReqID, alog.Request.Command, domain.ExecutionEventFailed, alog.Log.Status, alog.DeviceErrors)
+	}
+	if status == COMPLETED {
+		alog.notify(domain.EventExecutionCompleted)
+	} else {
+		alog.notify(domain.EventExecutionFailed)
+	}
+}
+
+//notify notifies the event of the execution to the subscriptions
+func (alog *AuditLog) notify(Event string) {
+	_, ctx := appcontext.LoggerAndContext(alog.ReqID)
+	FabricName, _ := alog.Request.Params["FabricName"].(string)
+	infra.GetUseCaseInteractor().NotifyExecution(ctx, Event, *alog.Log, FabricName, alog.DeviceErrors)
+}
+
+//AddDeviceError records the failure of an operation on a device, notified with the failure of the request
+func (alog *AuditLog) AddDeviceError(Device string, Operation string, err error) {
+	DeviceError := domain.DeviceError{Device: Device, Operation: Operation, Message: "unknown reason"}
+	if err != nil {
+		DeviceError.Message = err.Error()
+	}
+	alog.DeviceErrors = append(alog.DeviceErrors, DeviceError)
 }
 
 //LogMessageEnd to indicate the completion of command.
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+//deviceBuckets spans the durations of the device actions, from 100ms to about 7 minutes
+var deviceBuckets = prometheus.ExponentialBuckets(0.1, 2, 13)
+
+//httpBuckets spans the durations of the REST requests, from 5ms to about 5 minutes
+var httpBuckets = prometheus.ExponentialBuckets(0.005, 2, 17)
+
+//dbBuckets spans the durations of the database queries, from 500µs to about 4s
+var dbBuckets = prometheus.ExponentialBuckets(0.0005, 2, 14)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "efa_http_requests_total",
+		Help: "Number of REST requests by route, method and HTTP status.",
+	}, []string{"route", "method", "code"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "efa_http_request_duration_seconds",
+		Help:    "Duration of the REST requests by route, method and HTTP status.",
+		Buckets: httpBuckets,
+	}, []string{"route", "method", "code"})
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "efa_operation_duration_seconds",
+		Help:    "Duration of the operations recorded as executions by operation and status.",
+		Buckets: deviceBuckets,
+	}, []string{"operation", "status"})
+
+	deviceActionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "efa_device_action_duration_seconds",
+		Help:    "Duration of the actions run on each device by action.",
+		Buckets: deviceBuckets,
+	}, []string{"action"})
+
+	netconfRPCs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "efa_netconf_rpcs_total",
+		Help: "Number of NETCONF RPCs sent to the devices by adapter method.",
+	}, []string{"method"})
+
+	netconfRPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "efa_netconf_rpc_errors_total",
+		Help: "Number of failed NETCONF RPCs by adapter method.",
+	}, []string{"method"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "efa_db_query_duration_seconds",
+		Help:    "Duration of the database queries by operation.",
+		Buckets: dbBuckets,
+	}, []string{"operation"})
+
+	//RestLockWait observes how long the REST requests wait for the RestLock
+	RestLockWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "efa_rest_lock_wait_seconds",
+		Help:    "Time spent by the REST requests waiting for the lock serializing them.",
+		Buckets: httpBuckets,
+	})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpRequestDuration, operationDuration, deviceActionDuration,
+		netconfRPCs, netconfRPCErrors, dbQueryDuration, RestLockWait)
+}
+
+//Handler returns the REST handler exposing the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+//InstrumentRoute counts the requests of a route and observes their duration
+func InstrumentRoute(inner http.Handler, route string) http.Handler {
+	Labels := prometheus.Labels{"route": route}
+	return promhttp.InstrumentHandlerDuration(httpRequestDuration.MustCurryWith(Labels),
+		promhttp.InstrumentHandlerCounter(httpRequests.MustCurryWith(Labels), inner))
+}
+
+//ObserveOperation observes the duration of an operation ended with the given status
+func ObserveOperation(Operation string, Status string, Duration time.Duration) {
+	operationDuration.WithLabelValues(Operation, Status).Observe(Duration.Seconds())
+}
+
+//ObserveDeviceAction observes the duration of an action on a device started at Start,
+//it is deferred at the start of the action
+func ObserveDeviceAction(Action string, Start time.Time) {
+	deviceActionDuration.WithLabelValues(Action).Observe(time.Since(Start).Seconds())
+}
+
+//CountNetconfRPC counts a NETCONF RPC sent by an adapter method, and its failure
+func CountNetconfRPC(Method string, err error) {
+	netconfRPCs.WithLabelValues(Method).Inc()
+	if err != nil {
+		netconfRPCErrors.WithLabelValues(Method).Inc()
+	}
+}
+
+//ObserveDBQuery observes the duration of a database query
+func ObserveDBQuery(Operation string, Duration time.Duration) {
+	dbQueryDuration.WithLabelValues(Operation).Observe(Duration.Seconds())
+}
+
+//TimedMutex is a mutex observing in Wait how long Lock waited for it
+type TimedMutex struct {
+	sync.Mutex
+	Wait prometheus.Observer
+}
+
+//Lock locks the mutex and observes the time spent waiting for it
+func (m *TimedMutex) Lock() {
+	Start := time.Now()
+	m.Mutex.Lock()
+	if m.Wait != nil {
+		m.Wait.Observe(time.Since(Start).Seconds())
+	}
+}
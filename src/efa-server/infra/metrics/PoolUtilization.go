@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//PoolUtilization is the utilization of an allocation pool of a fabric
+type PoolUtilization struct {
+	Fabric   string
+	PoolType string
+	PoolName string
+	Used     uint64
+	Free     uint64
+	//Utilization in percent of the values of the pool
+	Utilization uint64
+}
+
+var poolLabels = []string{"fabric", "pool_type", "pool_name"}
+
+var (
+	poolUtilizationDesc = prometheus.NewDesc("efa_pool_utilization_percent",
+		"Utilization in percent of the allocation pools.", poolLabels, nil)
+	poolUsedDesc = prometheus.NewDesc("efa_pool_used",
+		"Number of values of the allocation pools held by the devices.", poolLabels, nil)
+	poolFreeDesc = prometheus.NewDesc("efa_pool_free",
+		"Number of values of the allocation pools left.", poolLabels, nil)
+)
+
+//poolCollector computes the utilization of the pools at each scrape, the pools being stored in the database
+type poolCollector struct {
+	pools func() []PoolUtilization
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolUtilizationDesc
+	ch <- poolUsedDesc
+	ch <- poolFreeDesc
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, Pool := range c.pools() {
+		ch <- prometheus.MustNewConstMetric(poolUtilizationDesc, prometheus.GaugeValue, float64(Pool.Utilization),
+			Pool.Fabric, Pool.PoolType, Pool.PoolName)
+		ch <- prometheus.MustNewConstMetric(poolUsedDesc, prometheus.GaugeValue, float64(Pool.Used),
+			Pool.Fabric, Pool.PoolType, Pool.PoolName)
+		ch <- prometheus.MustNewConstMetric(poolFreeDesc, prometheus.GaugeValue, float64(Pool.Free),
+			Pool.Fabric, Pool.PoolType, Pool.PoolName)
+	}
+}
+
+//RegisterPoolUtilization exposes the utilization of the pools returned by Pools at each scrape.
+//It is called once, when the database is set up.
+func RegisterPoolUtilization(Pools func() []PoolUtilization) error {
+	return prometheus.Register(poolCollector{pools: Pools})
+}
@@ -17,6 +17,7 @@ import (
 	"efa-server/infra"
 	"efa-server/infra/constants"
 	"efa-server/infra/database"
+	"efa-server/infra/metrics"
 	"github.com/google/uuid"
 	"sync"
 )
@@ -107,12 +108,28 @@ func (s *OpenAPIServer) RunOpenAPIServer() {
 	server := s
 	//TODO move Database initialization out to the main When Server and Client are seperated
 	//Initialize the DB
-	database.Setup(constants.DBLocation)
+	DBConfig, err := database.LoadConfig(constants.DBConfigLocation, constants.DBLocation)
+	if err != nil {
+		log.Fatalln("Invalid database configuration", err)
+	}
+	//The database is migrated to the schema of the application, a newer schema is not touched
+	if err = database.SetupWithConfig(constants.DBLocation, DBConfig); err != nil {
+		log.Fatalln("Failed to setup the database", err)
+	}
+	log.Infof("Using the %s database", DBConfig.Dialect)
 	//Add Default Fabric from here
 	rqID := uuid.New().String()
 	_, ctx := appcontext.LoggerAndContext(rqID)
 	infra.GetUseCaseInteractor().AddFabric(ctx, constants.DefaultFabric)
-	infra.GetUseCaseInteractor().DatabaseUpgrade(ctx, constants.DefaultFabric)
+	if err = metrics.RegisterPoolUtilization(poolUtilization); err != nil {
+		log.Errorln("Failed to expose the pool utilization metrics", err)
+	}
+
+	stopBackups := make(chan bool)
+	if DBConfig.BackupPeriod > 0 {
+		log.Infof("Backup the database every %s, keeping %d backups", DBConfig.BackupPeriod, DBConfig.BackupRetention)
+		go scheduleBackups(DBConfig.BackupPeriod, stopBackups)
+	}
 
 	done := make(chan bool)
 	go func() {
@@ -127,5 +144,39 @@ func (s *OpenAPIServer) RunOpenAPIServer() {
 	server.waitShutdown()
 
 	<-done
+	close(stopBackups)
 	log.Printf("DONE!")
 }
+
+//poolUtilization returns the utilization of the pools of the fabric for the metrics, without the RestLock for
+//the scrapes to be answered during a long configure
+func poolUtilization() []metrics.PoolUtilization {
+	_, ctx := appcontext.LoggerAndContext(uuid.New().String())
+	Response, err := infra.GetUseCaseInteractor().GetPoolUtilizationMetrics(ctx, constants.DefaultFabric)
+	if err != nil {
+		return nil
+	}
+	Pools := make([]metrics.PoolUtilization, 0, len(Response.Pools))
+	for _, Pool := range Response.Pools {
+		Pools = append(Pools, metrics.PoolUtilization{Fabric: Response.FabricName, PoolType: Pool.PoolType,
+			PoolName: Pool.PoolName, Used: Pool.Used, Free: Pool.Free, Utilization: Pool.Utilization})
+	}
+	return Pools
+}
+
+//scheduleBackups backs up the database at every period until stop is closed
+func scheduleBackups(Period time.Duration, stop chan bool) {
+	ticker := time.NewTicker(Period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			_, ctx := appcontext.LoggerAndContext(uuid.New().String())
+			if _, statusMsg, err := infra.GetUseCaseInteractor().BackupDatabase(ctx); err != nil {
+				log.Errorln("Scheduled database backup failed", statusMsg)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type CablingLink struct {
+
+	// Management IP Address of the first device
+	DeviceOne string `json:"device_one,omitempty"`
+
+	// Ethernet interface of the first device
+	InterfaceOne string `json:"interface_one,omitempty"`
+
+	// Management IP Address of the second device
+	DeviceTwo string `json:"device_two,omitempty"`
+
+	// Ethernet interface of the second device
+	InterfaceTwo string `json:"interface_two,omitempty"`
+}
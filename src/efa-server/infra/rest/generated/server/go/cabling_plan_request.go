@@ -0,0 +1,19 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type CablingPlanRequest struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name"`
+
+	Links []CablingLink `json:"links,omitempty"`
+}
@@ -0,0 +1,30 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type ConfigGeneration struct {
+
+	// Number of the generation in the fabric
+	Generation int32 `json:"generation,omitempty"`
+
+	// ID of the execution of the configure which stored the generation
+	ExecutionId string `json:"execution_id,omitempty"`
+
+	// Time the generation was stored
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Number of devices in the generation
+	Devices int32 `json:"devices,omitempty"`
+}
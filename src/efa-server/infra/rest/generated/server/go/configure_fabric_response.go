@@ -20,4 +20,12 @@ type ConfigureFabricResponse struct {
 
 	// Database ID of the fabric
 	FabricId int32 `json:"fabric_id,omitempty"`
+
+	// Allocation pools whose utilization reached the warning threshold
+	PoolWarnings []string `json:"pool_warnings,omitempty"`
+
+	// Configuration generation stored by the configure
+	Generation int32 `json:"generation,omitempty"`
+
+	Health *FabricHealthResponse `json:"health,omitempty"`
 }
@@ -0,0 +1,30 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type DatabaseMigration struct {
+
+	// Version of the schema after the migration
+	Version int32 `json:"version,omitempty"`
+
+	// Change made by the migration
+	Description string `json:"description,omitempty"`
+
+	// Whether the migration is applied to the database
+	Applied bool `json:"applied,omitempty"`
+
+	// Time the migration was applied
+	AppliedAt time.Time `json:"applied_at,omitempty"`
+}
@@ -0,0 +1,25 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type DeviceHealth struct {
+
+	// IP address of the device
+	IpAddress string `json:"ip_address,omitempty"`
+
+	// Role of the device
+	Role string `json:"role,omitempty"`
+
+	// Whether every health check of the device passed
+	Healthy bool `json:"healthy,omitempty"`
+
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
@@ -0,0 +1,26 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type DeviceMaintenanceResponse struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name,omitempty"`
+
+	// Management IP Address of the device
+	DeviceIp string `json:"device_ip,omitempty"`
+
+	// Maintenance mode of the device
+	MaintenanceMode bool `json:"maintenance_mode,omitempty"`
+
+	// Result of the maintenance mode update
+	Message string `json:"message,omitempty"`
+}
@@ -14,7 +14,26 @@ type ExtendedErrorModel struct {
 
 	Message string `json:"message"`
 
+	// HTTP status of the failure
 	Code int32 `json:"code"`
 
-	RootCause string `json:"rootCause"`
+	// Machine-readable code of the failure
+	ErrorCode string `json:"error_code,omitempty"`
+
+	// Request parameter or setting which failed
+	Field string `json:"field,omitempty"`
+
+	// IP address of the device which failed
+	Device string `json:"device,omitempty"`
+
+	// ID of the execution of the request
+	ExecutionId string `json:"execution_id,omitempty"`
+
+	RootCause string `json:"rootCause,omitempty"`
+
+	// Failures of the settings or the devices of the request
+	Errors []ErrorModel `json:"errors,omitempty"`
+
+	// Status of each device of the request
+	Devices []DeviceStatusModel `json:"devices,omitempty"`
 }
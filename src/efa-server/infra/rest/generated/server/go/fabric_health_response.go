@@ -0,0 +1,29 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type FabricHealthResponse struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name,omitempty"`
+
+	// Whether every health check of every device passed
+	Healthy bool `json:"healthy,omitempty"`
+
+	// Time the operational state was collected
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+
+	Devices []DeviceHealth `json:"devices,omitempty"`
+}
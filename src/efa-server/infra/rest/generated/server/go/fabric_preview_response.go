@@ -0,0 +1,29 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type FabricPreviewResponse struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name,omitempty"`
+
+	// Fabric settings changed by the update
+	Settings []ConfigChange `json:"settings,omitempty"`
+
+	// Changes of the configuration pushed to the devices by the next configure
+	Changes []ConfigChange `json:"changes,omitempty"`
+
+	// Values held by the devices which are outside the updated ranges
+	Reallocations []PoolReallocation `json:"reallocations,omitempty"`
+
+	// Reason the settings update would be refused, empty when it is allowed
+	Blocked string `json:"blocked,omitempty"`
+}
@@ -0,0 +1,23 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type NotificationDeviceError struct {
+
+	// IP address of the device
+	Device string `json:"device,omitempty"`
+
+	// Operation which failed on the device
+	Operation string `json:"operation,omitempty"`
+
+	// Failure of the operation
+	Message string `json:"message,omitempty"`
+}
@@ -77,6 +77,90 @@ var routes = Routes{
 		ConfigureFabric,
 	},
 
+	Route{
+		"CreateDatabaseBackup",
+		strings.ToUpper("Post"),
+		"/v1/db/backup",
+		CreateDatabaseBackup,
+	},
+
+	Route{
+		"GetDatabaseBackups",
+		strings.ToUpper("Get"),
+		"/v1/db/backups",
+		GetDatabaseBackups,
+	},
+
+	Route{
+		"GetDatabaseMigrations",
+		strings.ToUpper("Get"),
+		"/v1/db/migrations",
+		GetDatabaseMigrations,
+	},
+
+	Route{
+		"RestoreDatabaseBackup",
+		strings.ToUpper("Post"),
+		"/v1/db/restore",
+		RestoreDatabaseBackup,
+	},
+
+	Route{
+		"UpdateDeviceMaintenance",
+		strings.ToUpper("Put"),
+		"/v1/device/maintenance",
+		UpdateDeviceMaintenance,
+	},
+
+	Route{
+		"ReplaceDevice",
+		strings.ToUpper("Post"),
+		"/v1/device/replace",
+		ReplaceDevice,
+	},
+
+	Route{
+		"DeleteDeviceAllocation",
+		strings.ToUpper("Delete"),
+		"/v1/device/allocation",
+		DeleteDeviceAllocation,
+	},
+
+	Route{
+		"GetDeviceAllocation",
+		strings.ToUpper("Get"),
+		"/v1/device/allocation",
+		GetDeviceAllocation,
+	},
+
+	Route{
+		"UpdateDeviceAllocation",
+		strings.ToUpper("Put"),
+		"/v1/device/allocation",
+		UpdateDeviceAllocation,
+	},
+
+	Route{
+		"GetDeviceSettings",
+		strings.ToUpper("Get"),
+		"/v1/device/settings",
+		GetDeviceSettings,
+	},
+
+	Route{
+		"UpdateDeviceSettings",
+		strings.ToUpper("Put"),
+		"/v1/device/settings",
+		UpdateDeviceSettings,
+	},
+
+	Route{
+		"ExecutionEvents",
+		strings.ToUpper("Get"),
+		"/v1/execution/{id}/events",
+		ExecutionEvents,
+	},
+
 	Route{
 		"ExecutionGet",
 		strings.ToUpper("Get"),
@@ -119,6 +203,97 @@ var routes = Routes{
 		GetFabrics,
 	},
 
+	Route{
+		"PreviewFabric",
+		strings.ToUpper("Post"),
+		"/v1/fabric/preview",
+		PreviewFabric,
+	},
+
+	Route{
+		"RotateFabricBgpAuth",
+		strings.ToUpper("Put"),
+		"/v1/fabric/bgp-auth",
+		RotateFabricBgpAuth,
+	},
+
+	Route{
+		"DeleteCablingPlan",
+		strings.ToUpper("Delete"),
+		"/v1/fabric/cabling-plan",
+		DeleteCablingPlan,
+	},
+
+	Route{
+		"GetCablingPlan",
+		strings.ToUpper("Get"),
+		"/v1/fabric/cabling-plan",
+		GetCablingPlan,
+	},
+
+	Route{
+		"UpdateCablingPlan",
+		strings.ToUpper("Put"),
+		"/v1/fabric/cabling-plan",
+		UpdateCablingPlan,
+	},
+
+	Route{
+		"GetFabricHealth",
+		strings.ToUpper("Get"),
+		"/v1/fabric/health",
+		GetFabricHealth,
+	},
+
+	Route{
+		"GetFabricDiff",
+		strings.ToUpper("Get"),
+		"/v1/fabric/diff",
+		GetFabricDiff,
+	},
+
+	Route{
+		"GetFabricHistory",
+		strings.ToUpper("Get"),
+		"/v1/fabric/history",
+		GetFabricHistory,
+	},
+
+	Route{
+		"RevertFabric",
+		strings.ToUpper("Post"),
+		"/v1/fabric/revert",
+		RevertFabric,
+	},
+
+	Route{
+		"GetFabricPools",
+		strings.ToUpper("Get"),
+		"/v1/fabric/pools",
+		GetFabricPools,
+	},
+
+	Route{
+		"RefreshFabric",
+		strings.ToUpper("Post"),
+		"/v1/fabric/refresh",
+		RefreshFabric,
+	},
+
+	Route{
+		"GetFabricSettingsHistory",
+		strings.ToUpper("Get"),
+		"/v1/fabric/settings/history",
+		GetFabricSettingsHistory,
+	},
+
+	Route{
+		"RollbackFabricSettings",
+		strings.ToUpper("Post"),
+		"/v1/fabric/settings/rollback",
+		RollbackFabricSettings,
+	},
+
 	Route{
 		"UpdateFabric",
 		strings.ToUpper("Put"),
@@ -133,6 +308,34 @@ var routes = Routes{
 		ValidateFabric,
 	},
 
+	Route{
+		"CreateNotificationSubscription",
+		strings.ToUpper("Post"),
+		"/v1/notifications/subscriptions",
+		CreateNotificationSubscription,
+	},
+
+	Route{
+		"DeleteNotificationSubscription",
+		strings.ToUpper("Delete"),
+		"/v1/notifications/subscription",
+		DeleteNotificationSubscription,
+	},
+
+	Route{
+		"GetNotificationSubscriptions",
+		strings.ToUpper("Get"),
+		"/v1/notifications/subscriptions",
+		GetNotificationSubscriptions,
+	},
+
+	Route{
+		"TestNotificationSubscription",
+		strings.ToUpper("Post"),
+		"/v1/notifications/test",
+		TestNotificationSubscription,
+	},
+
 	Route{
 		"SupportSave",
 		strings.ToUpper("Get"),
@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"efa-server/infra/metrics"
 	ohandler "efa-server/infra/rest/openapi/handler"
 	"fmt"
 	"github.com/gorilla/mux"
@@ -23,10 +24,13 @@ type Routes []Route
 //NewRouter returns a new Router which routes the REST request to the unique Handler
 func NewRouter() *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
+	router.NotFoundHandler = http.HandlerFunc(ohandler.NotFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(ohandler.MethodNotAllowed)
 	for _, route := range routes {
 		var handler http.Handler
 		handler = route.HandlerFunc
 		handler = ohandler.Logger(handler, route.Name)
+		handler = metrics.InstrumentRoute(handler, route.Name)
 
 		router.
 			Methods(route.Method).
@@ -36,6 +40,8 @@ func NewRouter() *mux.Router {
 			Queries(route.QueryPairs...)
 
 	}
+	//The metrics are scraped by Prometheus, the scrapes are neither logged nor counted
+	router.Methods("GET").Path("/metrics").Name("Metrics").Handler(metrics.Handler())
 
 	return router
 }
@@ -68,6 +74,13 @@ var routes = Routes{
 		HandlerFunc: ohandler.ExecutionListHandler,
 		QueryPairs:  []string{"limit", "{limit}", "status", "{status}"},
 	},
+
+	Route{
+		Name:        "ExecutionEvents",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/execution/{id}/events",
+		HandlerFunc: ohandler.StreamExecutionEvents,
+	},
 	Route{
 		Name:        "UpdateSwitches",
 		Method:      strings.ToUpper("Put"),
@@ -128,12 +141,145 @@ var routes = Routes{
 		HandlerFunc: ohandler.ShowDevicesInFabric,
 		QueryPairs:  []string{"name", "{name}"},
 	},
+	Route{
+		Name:        "updateDeviceMaintenance",
+		Method:      strings.ToUpper("Put"),
+		Pattern:     "/v1/device/maintenance",
+		HandlerFunc: ohandler.UpdateMaintenanceMode,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}", "ip_address", "{ip_address}", "enable", "{enable}"},
+	},
+	Route{
+		Name:        "replaceDevice",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/device/replace",
+		HandlerFunc: ohandler.ReplaceDevice,
+	},
+	Route{
+		Name:        "getDeviceAllocation",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/device/allocation",
+		HandlerFunc: ohandler.ShowDeviceAllocation,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}"},
+	},
+	Route{
+		Name:        "updateDeviceAllocation",
+		Method:      strings.ToUpper("Put"),
+		Pattern:     "/v1/device/allocation",
+		HandlerFunc: ohandler.UpdateDeviceAllocation,
+	},
+	Route{
+		Name:        "deleteDeviceAllocation",
+		Method:      strings.ToUpper("Delete"),
+		Pattern:     "/v1/device/allocation",
+		HandlerFunc: ohandler.DeleteDeviceAllocation,
+	},
+	Route{
+		Name:        "updateDeviceSettings",
+		Method:      strings.ToUpper("Put"),
+		Pattern:     "/v1/device/settings",
+		HandlerFunc: ohandler.UpdateDeviceSettings,
+	},
+	Route{
+		Name:        "getDeviceSettings",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/device/settings",
+		HandlerFunc: ohandler.ShowDeviceSettings,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}", "ip_address", "{ip_address}"},
+	},
 	Route{
 		Name:        "updateFabric",
 		Method:      strings.ToUpper("Put"),
 		Pattern:     "/v1/fabric",
 		HandlerFunc: ohandler.UpdateFabricSettings,
 	},
+	Route{
+		Name:        "previewFabric",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/fabric/preview",
+		HandlerFunc: ohandler.PreviewFabricSettings,
+	},
+	Route{
+		Name:        "rotateFabricBgpAuth",
+		Method:      strings.ToUpper("Put"),
+		Pattern:     "/v1/fabric/bgp-auth",
+		HandlerFunc: ohandler.RotateFabricBGPAuth,
+	},
+	Route{
+		Name:        "getFabricSettingsHistory",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/fabric/settings/history",
+		HandlerFunc: ohandler.ShowFabricSettingsHistory,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}"},
+	},
+	Route{
+		Name:        "rollbackFabricSettings",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/fabric/settings/rollback",
+		HandlerFunc: ohandler.RollbackFabricSettings,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}", "to", "{to}"},
+	},
+	Route{
+		Name:        "getCablingPlan",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/fabric/cabling-plan",
+		HandlerFunc: ohandler.ShowCablingPlan,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}"},
+	},
+	Route{
+		Name:        "updateCablingPlan",
+		Method:      strings.ToUpper("Put"),
+		Pattern:     "/v1/fabric/cabling-plan",
+		HandlerFunc: ohandler.UpdateCablingPlan,
+	},
+	Route{
+		Name:        "deleteCablingPlan",
+		Method:      strings.ToUpper("Delete"),
+		Pattern:     "/v1/fabric/cabling-plan",
+		HandlerFunc: ohandler.DeleteCablingPlan,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}"},
+	},
+	Route{
+		Name:        "getFabricHealth",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/fabric/health",
+		HandlerFunc: ohandler.ShowFabricHealth,
+		QueryPairs:  []string{"name", "{name}"},
+	},
+	Route{
+		Name:        "getFabricPools",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/fabric/pools",
+		HandlerFunc: ohandler.ShowFabricPools,
+		QueryPairs:  []string{"name", "{name}"},
+	},
+	Route{
+		Name:        "refreshFabric",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/fabric/refresh",
+		HandlerFunc: ohandler.RefreshFabric,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}", "ip_address", "{ip_address}"},
+	},
+	Route{
+		Name:        "getFabricHistory",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/fabric/history",
+		HandlerFunc: ohandler.ShowFabricHistory,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}"},
+	},
+	Route{
+		Name:        "getFabricDiff",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/fabric/diff",
+		HandlerFunc: ohandler.ShowFabricDiff,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}", "from", "{from}", "to", "{to}"},
+	},
+	Route{
+		Name:        "revertFabric",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/fabric/revert",
+		HandlerFunc: ohandler.RevertFabric,
+		QueryPairs:  []string{"fabric_name", "{fabric_name}", "generation", "{generation}", "persist", "{persist}"},
+	},
 	Route{
 		Name:        "getFabric",
 		Method:      strings.ToUpper("Get"),
@@ -141,4 +287,55 @@ var routes = Routes{
 		HandlerFunc: ohandler.ShowFabricSettings,
 		QueryPairs:  []string{"name", "{name}"},
 	},
+	Route{
+		Name:        "getNotificationSubscriptions",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/notifications/subscriptions",
+		HandlerFunc: ohandler.ShowNotificationSubscriptions,
+	},
+	Route{
+		Name:        "createNotificationSubscription",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/notifications/subscriptions",
+		HandlerFunc: ohandler.CreateNotificationSubscription,
+	},
+	Route{
+		Name:        "deleteNotificationSubscription",
+		Method:      strings.ToUpper("Delete"),
+		Pattern:     "/v1/notifications/subscription",
+		HandlerFunc: ohandler.DeleteNotificationSubscription,
+		QueryPairs:  []string{"id", "{id}"},
+	},
+	Route{
+		Name:        "testNotificationSubscription",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/notifications/test",
+		HandlerFunc: ohandler.TestNotificationSubscription,
+		QueryPairs:  []string{"id", "{id}"},
+	},
+	Route{
+		Name:        "getDatabaseMigrations",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/db/migrations",
+		HandlerFunc: ohandler.ShowDatabaseMigrations,
+	},
+	Route{
+		Name:        "createDatabaseBackup",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/db/backup",
+		HandlerFunc: ohandler.CreateDatabaseBackup,
+	},
+	Route{
+		Name:        "getDatabaseBackups",
+		Method:      strings.ToUpper("Get"),
+		Pattern:     "/v1/db/backups",
+		HandlerFunc: ohandler.ShowDatabaseBackups,
+	},
+	Route{
+		Name:        "restoreDatabaseBackup",
+		Method:      strings.ToUpper("Post"),
+		Pattern:     "/v1/db/restore",
+		HandlerFunc: ohandler.RestoreDatabaseBackup,
+		QueryPairs:  []string{"name", "{name}"},
+	},
 }
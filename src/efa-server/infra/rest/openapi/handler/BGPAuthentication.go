@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+//RotateFabricBGPAuth is a REST handler which handles the BGP password rotation REST request
+func RotateFabricBGPAuth(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var FabricSettings Restmodel.FabricSettings
+	var BGPAuthUpdate domain.FabricProperties
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Rotate Fabric BGP Passwords"}}
+	ctx := withRequestUser(alog.LogMessageInit(), r)
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	err := json.Unmarshal(b, &FabricSettings)
+	if err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+	errMap := make(map[string]string, 0)
+	FabricName := FabricSettings.Name
+	for _, FabricParameter := range FabricSettings.Keyval {
+		switch FabricParameter.Key {
+		case "BGPAuthType":
+			BGPAuthUpdate.BGPAuthType = FabricParameter.Value
+		case "PeerGroupPassword":
+			BGPAuthUpdate.PeerGroupPassword = FabricParameter.Value
+		case "MctL2EvpnPassword":
+			BGPAuthUpdate.MctL2EvpnPassword = FabricParameter.Value
+		case "RackPeerEBGPGroupPassword":
+			BGPAuthUpdate.RackPeerEBGPGroupPassword = FabricParameter.Value
+		case "RackPeerOvgGroupPassword":
+			BGPAuthUpdate.RackPeerOvgGroupPassword = FabricParameter.Value
+		default:
+			errMap[FabricParameter.Key] = fmt.Sprintf("Invalid Parameter: %s", FabricParameter.Key)
+		}
+	}
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName":                FabricName,
+		"BGPAuthType":               BGPAuthUpdate.BGPAuthType,
+		"PeerGroupPassword":         maskPassword(BGPAuthUpdate.PeerGroupPassword),
+		"MctL2EvpnPassword":         maskPassword(BGPAuthUpdate.MctL2EvpnPassword),
+		"RackPeerEBGPGroupPassword": maskPassword(BGPAuthUpdate.RackPeerEBGPGroupPassword),
+		"RackPeerOvgGroupPassword":  maskPassword(BGPAuthUpdate.RackPeerOvgGroupPassword),
+	}
+	alog.LogMessageReceived()
+
+	if len(errMap) == 0 {
+		errMap = validateBGPAuthentication(&BGPAuthUpdate)
+	}
+	if len(errMap) == 0 && BGPAuthUpdate == (domain.FabricProperties{}) {
+		errMap["bgp-auth"] = "No BGP Authentication Update is Requested"
+	}
+	if len(errMap) != 0 {
+		success = false
+		statusMsg = "BGP Authentication Parameter Validation Failed"
+		writeSettingErrors(w, statusMsg, errMap, alog.ReqID)
+		return
+	}
+
+	UseCaseInteractor := infra.GetUseCaseInteractor()
+	ret, err := UseCaseInteractor.RotateBGPPasswords(ctx, FabricName, &BGPAuthUpdate)
+	if err != nil {
+		success = false
+		statusMsg = ret
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	statusMsg = "BGP Authentication Update Succeeded."
+	OpenAPIResp := Restmodel.FabricdataResponse{
+		FabricName: FabricName,
+		FabricId:   int32(UseCaseInteractor.FabricID),
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
@@ -11,7 +11,6 @@ import (
 	"efa-server/infra/constants"
 	"efa-server/infra/logging"
 	"efa-server/infra/rest/generated/server/go"
-	Restmodel "efa-server/infra/rest/generated/server/go"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/mux"
@@ -21,13 +20,14 @@ import (
 //ConfigureFabric provides REST handler for handling
 //POST Request for configuring the Fabric
 func ConfigureFabric(w http.ResponseWriter, r *http.Request) {
-	constants.RestLock.Lock()
-	defer constants.RestLock.Unlock()
+	Unlock := lockRest()
+	defer Unlock()
 	success := true
 	statusMsg := ""
 	var Message string
 
-	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:ConfigureFabric"}}
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:ConfigureFabric"},
+		ReqID: executionIDOf(r)}
 	ctx := alog.LogMessageInit()
 	defer alog.LogMessageEnd(&success, &statusMsg)
 	//Extract the Parameters
@@ -53,23 +53,20 @@ func ConfigureFabric(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	ctx = context.WithValue(ctx, appcontext.FabricType, fabricType)
+	ctx = context.WithValue(ctx, appcontext.DeferHealthCheck, true)
 	//Make a call to Configure the Fabric
 	response, err := infra.GetUseCaseInteractor().ConfigureFabric(ctx, FabricName, false, PersistBool)
 
 	//Indicating there is an overall Failure
 	if err != nil {
-		http.Error(w, statusMsg,
-			http.StatusInternalServerError)
-
 		//Buffer for writing messages to the Log
 		var buffer bytes.Buffer
-		StatusModelList := make([]Restmodel.DeviceStatusModel, 0, len(response.Errors))
+		OpenAPIError := extendErrorModel(errorModelOf(err, http.StatusInternalServerError, ""))
+		OpenAPIError.ExecutionId = alog.ReqID
 		for _, ConfigureError := range response.Errors {
 			buffer.WriteString(fmt.Sprintf("Configuration of device with ip-address = %s [Failed]\n", ConfigureError.Host))
 
-			StatusModel := Restmodel.DeviceStatusModel{IpAddress: ConfigureError.Host, Status: "Failed"}
 			//Format the error Message
-
 			if ConfigureError.Error != nil {
 				Message = fmt.Sprintf("Operation[%s] has failed with the reason:%s\n",
 					ConfigureError.Operation, ConfigureError.Error.Error())
@@ -77,15 +74,15 @@ func ConfigureFabric(w http.ResponseWriter, r *http.Request) {
 				Message = fmt.Sprintf("Operation[%s] has failed, with unknown reason", ConfigureError.Operation)
 			}
 			buffer.WriteString(Message)
-			StatusModel.Error_ = []Restmodel.ErrorModel{Restmodel.ErrorModel{Message: Message}}
-
-			StatusModelList = append(StatusModelList, StatusModel)
-
+			alog.AddDeviceError(ConfigureError.Host, ConfigureError.Operation, ConfigureError.Error)
+			OpenAPIError.Errors = append(OpenAPIError.Errors, deviceError(ConfigureError.Host, Message, alog.ReqID))
+		}
+		if len(OpenAPIError.Errors) != 0 {
+			OpenAPIError.Message, OpenAPIError.ErrorCode = "Configure Fabric Failed", ErrorCodeDeviceFailure
 		}
 
-		//Write StatusModel to the Body
-		bytess, _ := json.Marshal(&StatusModelList)
-		w.Write(bytess)
+		//Write the errors of the devices to the Body
+		writeExtendedErrorModel(w, OpenAPIError)
 
 		success = false
 		buffer.WriteString("Configure Fabric Failed\n")
@@ -93,7 +90,14 @@ func ConfigureFabric(w http.ResponseWriter, r *http.Request) {
 	} else {
 		//Send Configure Fabric Response
 		statusMsg = "Configure Fabric Succeeded"
-		OpenAPIResp := swagger.ConfigureFabricResponse{FabricName: FabricName, Status: "Successful"}
+		OpenAPIResp := swagger.ConfigureFabricResponse{FabricName: FabricName, Status: "Successful",
+			PoolWarnings: response.PoolWarnings, Generation: int32(response.Generation)}
+		//The other requests are served while the fabric settles
+		Unlock()
+		response.Health = infra.GetUseCaseInteractor().VerifyFabricHealth(ctx, FabricName)
+		if response.Health != nil {
+			OpenAPIResp.Health = fabricHealthModel(*response.Health)
+		}
 
 		//Write Success Structure to the Body
 		bytess, _ := json.Marshal(&OpenAPIResp)
@@ -101,3 +105,15 @@ func ConfigureFabric(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
+
+//lockRest takes the RestLock and returns the function releasing it, which does nothing once the lock is released
+func lockRest() func() {
+	constants.RestLock.Lock()
+	Locked := true
+	return func() {
+		if Locked {
+			Locked = false
+			constants.RestLock.Unlock()
+		}
+	}
+}
@@ -26,7 +26,8 @@ func CreateSwitches(w http.ResponseWriter, r *http.Request) {
 
 	var NewSwitchesRequest Restmodel.NewSwitches
 
-	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:Add Device"}}
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:Add Device"},
+		ReqID: executionIDOf(r)}
 	ctx := alog.LogMessageInit()
 	defer alog.LogMessageEnd(&success, &statusMsg)
 
@@ -34,6 +35,9 @@ func CreateSwitches(w http.ResponseWriter, r *http.Request) {
 	err := json.Unmarshal(b, &NewSwitchesRequest)
 	if err != nil {
 		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
 		return
 	}
 	//update Request object after all parameters are received
@@ -72,16 +76,14 @@ func CreateSwitches(w http.ResponseWriter, r *http.Request) {
 			}
 			//Populate each error from the device
 			for _, er := range AddDeviceResponse.Errors {
-				fmt.Println(er)
-				StatusModel.Error_ = append(StatusModel.Error_, Restmodel.ErrorModel{Message: fmt.Sprint(er)})
+				StatusModel.Error_ = append(StatusModel.Error_, deviceError(AddDeviceResponse.IPAddress, fmt.Sprint(er), alog.ReqID))
+				alog.AddDeviceError(AddDeviceResponse.IPAddress, "Add Device", er)
 			}
 			StatusModelList = append(StatusModelList, StatusModel)
 		}
 
-		http.Error(w, statusMsg,
-			http.StatusInternalServerError)
-		bytess, _ := json.Marshal(&StatusModelList)
-		w.Write(bytess)
+		statusMsg = err.Error()
+		writeDeviceErrors(w, "Add Devices Failed", StatusModelList, alog.ReqID)
 	} else {
 		//Top level structure for Switches Response
 		SwitchesExtendedDataResponse := Restmodel.SwitchesdataResponse{}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+)
+
+//CreateDatabaseBackup is a REST handler which saves the database to a new backup
+func CreateDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Backup Database"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+	alog.LogMessageReceived()
+
+	Backup, ret, err := infra.GetUseCaseInteractor().BackupDatabase(ctx)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	OpenAPIResp := prepareDatabaseBackup(Backup)
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//ShowDatabaseBackups is a REST handler to handle
+// GET request for the backups of the database
+func ShowDatabaseBackups(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	Backups, err := infra.GetUseCaseInteractor().GetDatabaseBackups(r.Context())
+	if err != nil {
+		writeUseCaseError(w, err, "", "")
+		return
+	}
+
+	OpenAPIResp := Restmodel.DatabaseBackupsResponse{Backups: make([]Restmodel.DatabaseBackup, 0, len(Backups))}
+	for _, Backup := range Backups {
+		OpenAPIResp.Backups = append(OpenAPIResp.Backups, prepareDatabaseBackup(Backup))
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//RestoreDatabaseBackup is a REST handler which replaces the database with a backup.
+//The REST lock is held during the restore, no other operation runs on the database.
+func RestoreDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	Name := r.URL.Query().Get("name")
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Restore Database"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"Name": Name,
+	}
+	alog.LogMessageReceived()
+
+	Backup, ret, err := infra.GetUseCaseInteractor().RestoreDatabase(ctx, Name)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	RestoredBackup := prepareDatabaseBackup(Backup)
+	OpenAPIResp := Restmodel.DatabaseRestoreResponse{Backup: &RestoredBackup, Message: ret}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+func prepareDatabaseBackup(Backup domain.DatabaseBackup) Restmodel.DatabaseBackup {
+	return Restmodel.DatabaseBackup{Name: Backup.Name, SchemaVersion: int32(Backup.SchemaVersion),
+		CreatedAt: Backup.CreatedAt, Size: Backup.Size}
+}
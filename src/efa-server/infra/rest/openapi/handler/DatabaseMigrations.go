@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+)
+
+//ShowDatabaseMigrations is a REST handler to handle
+// GET request for the version of the database schema and the state of the schema migrations
+func ShowDatabaseMigrations(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	Status, err := infra.GetUseCaseInteractor().GetSchemaMigrationStatus(r.Context())
+	if err != nil {
+		writeUseCaseError(w, err, "", "")
+		return
+	}
+
+	response := Restmodel.DatabaseMigrationsResponse{CurrentVersion: int32(Status.CurrentVersion),
+		LatestVersion: int32(Status.LatestVersion)}
+	response.Migrations = make([]Restmodel.DatabaseMigration, 0, len(Status.Migrations))
+	for _, Migration := range Status.Migrations {
+		response.Migrations = append(response.Migrations, Restmodel.DatabaseMigration{Version: int32(Migration.Version),
+			Description: Migration.Description, Applied: Migration.Applied, AppliedAt: Migration.AppliedAt})
+	}
+	bytess, _ := json.Marshal(&response)
+	w.Write(bytess)
+}
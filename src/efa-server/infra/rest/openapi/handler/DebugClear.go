@@ -39,6 +39,9 @@ func DebugClear(w http.ResponseWriter, r *http.Request) {
 	err := json.Unmarshal(b, &DebugClearRequest)
 	if err != nil {
 		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
 		return
 	}
 	//update Request object after all parameters are received
@@ -67,13 +70,7 @@ func DebugClear(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		statusMsg = fmt.Sprintf("%s Failed.", CommandName)
 		success = false
-		http.Error(w, "",
-			http.StatusInternalServerError)
-
-		OpenAPIError := swagger.ErrorModel{Message: err.Error()}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
-
+		writeUseCaseError(w, err, "", alog.ReqID)
 	} else {
 		//Send  Fabric config Show Response
 		statusMsg = fmt.Sprintf("%s Succeeded.", CommandName)
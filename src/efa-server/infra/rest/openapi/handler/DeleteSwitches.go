@@ -28,7 +28,8 @@ func DeleteSwitches(w http.ResponseWriter, r *http.Request) {
 	success := true
 	statusMsg := ""
 
-	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:Delete Device"}}
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:Delete Device"},
+		ReqID: executionIDOf(r)}
 	ctx := alog.LogMessageInit()
 	defer alog.LogMessageEnd(&success, &statusMsg)
 
@@ -36,6 +37,9 @@ func DeleteSwitches(w http.ResponseWriter, r *http.Request) {
 	err := json.Unmarshal(b, &DelSwitchReq)
 	if err != nil {
 		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
 		return
 	}
 
@@ -77,15 +81,14 @@ func DeleteSwitches(w http.ResponseWriter, r *http.Request) {
 			}
 			//Populate each error from the device
 			for _, er := range AddDeviceResponse.Errors {
-				fmt.Println(er)
-				StatusModel.Error_ = append(StatusModel.Error_, Restmodel.ErrorModel{Message: fmt.Sprint(er)})
+				StatusModel.Error_ = append(StatusModel.Error_, deviceError(AddDeviceResponse.IPAddress, fmt.Sprint(er), alog.ReqID))
+				alog.AddDeviceError(AddDeviceResponse.IPAddress, "Delete Device", er)
 			}
 			StatusModelList = append(StatusModelList, StatusModel)
 		}
 
-		http.Error(w, statusMsg, http.StatusInternalServerError)
-		bytess, _ := json.Marshal(&StatusModelList)
-		w.Write(bytess)
+		statusMsg = err.Error()
+		writeDeviceErrors(w, "Delete Devices Failed", StatusModelList, alog.ReqID)
 	} else {
 		//Top level structure for Switches Response
 		SwitchesExtendedDataResponse := Restmodel.SwitchesdataResponse{}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+)
+
+//UpdateDeviceAllocation is a REST handler which pins an ASN, a Loopback IP or a P2P IP to a device
+func UpdateDeviceAllocation(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var PinRequest Restmodel.AllocationPinRequest
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Set Device Allocation"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(b, &PinRequest); err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName":    PinRequest.FabricName,
+		"IPAddress":     PinRequest.IpAddress,
+		"Type":          PinRequest.Type_,
+		"Value":         PinRequest.Value,
+		"InterfaceName": PinRequest.InterfaceName,
+	}
+	alog.LogMessageReceived()
+
+	Pin, ret, err := infra.GetUseCaseInteractor().SetAllocationPin(ctx, PinRequest.FabricName, PinRequest.IpAddress,
+		PinRequest.Type_, PinRequest.Value, PinRequest.InterfaceName)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	OpenAPIResp := prepareAllocationPinsResponse(PinRequest.FabricName, []domain.AllocationPin{Pin})
+	OpenAPIResp.Message = ret
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//DeleteDeviceAllocation is a REST handler which clears the values pinned to a device
+func DeleteDeviceAllocation(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var PinRequest Restmodel.AllocationPinRequest
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Clear Device Allocation"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(b, &PinRequest); err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName":    PinRequest.FabricName,
+		"IPAddress":     PinRequest.IpAddress,
+		"Type":          PinRequest.Type_,
+		"InterfaceName": PinRequest.InterfaceName,
+	}
+	alog.LogMessageReceived()
+
+	Pins, ret, err := infra.GetUseCaseInteractor().ClearAllocationPins(ctx, PinRequest.FabricName, PinRequest.IpAddress,
+		PinRequest.Type_, PinRequest.InterfaceName)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	OpenAPIResp := prepareAllocationPinsResponse(PinRequest.FabricName, Pins)
+	OpenAPIResp.Message = ret
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//ShowDeviceAllocation is a REST handler to handle
+// GET request for the values pinned to a device, or to all the devices of the fabric
+func ShowDeviceAllocation(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	//ip_address is optional and hence not part of the route
+	DeviceIP := r.URL.Query().Get("ip_address")
+
+	Pins, err := infra.GetUseCaseInteractor().GetAllocationPins(r.Context(), FabricName, DeviceIP)
+	if err != nil {
+		writeUseCaseError(w, err, "", "")
+		return
+	}
+
+	OpenAPIResp := prepareAllocationPinsResponse(FabricName, Pins)
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+func prepareAllocationPinsResponse(FabricName string, Pins []domain.AllocationPin) Restmodel.AllocationPinsResponse {
+	Response := Restmodel.AllocationPinsResponse{FabricName: FabricName}
+	Response.Pins = make([]Restmodel.AllocationPin, 0, len(Pins))
+	for _, Pin := range Pins {
+		Response.Pins = append(Response.Pins, Restmodel.AllocationPin{IpAddress: Pin.DeviceIP, Type_: Pin.PinType,
+			InterfaceName: Pin.InterfaceName, PoolName: Pin.PoolName, Value: Pin.Value})
+	}
+	return Response
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"efa-server/usecase"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+)
+
+//UpdateDeviceSettings is a REST handler which handles the per-device settings Update REST request
+func UpdateDeviceSettings(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var DeviceSettings Restmodel.DeviceSettings
+	var DeviceUpdate domain.DeviceSettings
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Update Device Settings"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	err := json.Unmarshal(b, &DeviceSettings)
+	if err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+	errMap := make(map[string]string, 0)
+	FabricName := DeviceSettings.FabricName
+	for _, DeviceParameter := range DeviceSettings.Keyval {
+		switch DeviceParameter.Key {
+		case "MTU":
+			DeviceUpdate.MTU = DeviceParameter.Value
+		case "IPMTU":
+			DeviceUpdate.IPMTU = DeviceParameter.Value
+		case "BFDEnable":
+			DeviceUpdate.BFDEnable = DeviceParameter.Value
+		case "BFDTx":
+			DeviceUpdate.BFDTx = DeviceParameter.Value
+		case "BFDRx":
+			DeviceUpdate.BFDRx = DeviceParameter.Value
+		case "BFDMultiplier":
+			DeviceUpdate.BFDMultiplier = DeviceParameter.Value
+		case "MaxPaths":
+			DeviceUpdate.MaxPaths = DeviceParameter.Value
+		case "AllowASIn":
+			DeviceUpdate.AllowASIn = DeviceParameter.Value
+		case "LeafPeerGroup":
+			DeviceUpdate.LeafPeerGroup = DeviceParameter.Value
+		case "SpinePeerGroup":
+			DeviceUpdate.SpinePeerGroup = DeviceParameter.Value
+		case "ArpAgingTimeout":
+			DeviceUpdate.ArpAgingTimeout = DeviceParameter.Value
+		case "MacAgingTimeout":
+			DeviceUpdate.MacAgingTimeout = DeviceParameter.Value
+		case "MacAgingConversationalTimeout":
+			DeviceUpdate.MacAgingConversationalTimeout = DeviceParameter.Value
+		case "MacMoveLimit":
+			DeviceUpdate.MacMoveLimit = DeviceParameter.Value
+		case "DuplicateMacTimer":
+			DeviceUpdate.DuplicateMacTimer = DeviceParameter.Value
+		case "DuplicateMaxTimerMaxCount":
+			DeviceUpdate.DuplicateMaxTimerMaxCount = DeviceParameter.Value
+		default:
+			errMap[DeviceParameter.Key] = fmt.Sprintf("Invalid Parameter: %s", DeviceParameter.Key)
+		}
+	}
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName":                    FabricName,
+		"DeviceIP":                      DeviceSettings.DeviceIp,
+		"MTU":                           DeviceUpdate.MTU,
+		"IPMTU":                         DeviceUpdate.IPMTU,
+		"BFDEnable":                     DeviceUpdate.BFDEnable,
+		"BFDTx":                         DeviceUpdate.BFDTx,
+		"BFDRx":                         DeviceUpdate.BFDRx,
+		"BFDMultiplier":                 DeviceUpdate.BFDMultiplier,
+		"MaxPaths":                      DeviceUpdate.MaxPaths,
+		"AllowASIn":                     DeviceUpdate.AllowASIn,
+		"LeafPeerGroup":                 DeviceUpdate.LeafPeerGroup,
+		"SpinePeerGroup":                DeviceUpdate.SpinePeerGroup,
+		"ArpAgingTimeout":               DeviceUpdate.ArpAgingTimeout,
+		"MacAgingTimeout":               DeviceUpdate.MacAgingTimeout,
+		"MacAgingConversationalTimeout": DeviceUpdate.MacAgingConversationalTimeout,
+		"MacMoveLimit":                  DeviceUpdate.MacMoveLimit,
+		"DuplicateMacTimer":             DeviceUpdate.DuplicateMacTimer,
+		"DuplicateMaxTimerMaxCount":     DeviceUpdate.DuplicateMaxTimerMaxCount,
+	}
+	alog.LogMessageReceived()
+
+	if len(errMap) == 0 {
+		errMap = validateDeviceSettings(FabricName, &DeviceUpdate)
+	}
+	if len(errMap) != 0 {
+		success = false
+		statusMsg = "Device Settings Parameter Validation Failed"
+		writeSettingErrors(w, statusMsg, errMap, alog.ReqID)
+		return
+	}
+
+	UseCaseInteractor := infra.GetUseCaseInteractor()
+	ret, err := UseCaseInteractor.UpdateDeviceSettings(ctx, FabricName, DeviceSettings.DeviceIp, &DeviceUpdate)
+	if err != nil {
+		success = false
+		statusMsg = ret
+		ErrorModel := errorModelOf(err, http.StatusInternalServerError, ret)
+		ErrorModel.Device, ErrorModel.ExecutionId = DeviceSettings.DeviceIp, alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	statusMsg = "Device Settings Update Succeeded."
+	OpenAPIResp := Restmodel.DeviceSettingsResponse{
+		FabricName: FabricName,
+		DeviceIp:   DeviceSettings.DeviceIp,
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//ShowDeviceSettings is a REST handler to handle GET request for per-device settings
+func ShowDeviceSettings(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	IPAddress := vars["ip_address"]
+
+	DeviceSettings, FabricProperties, err := infra.GetUseCaseInteractor().GetDeviceSettings(r.Context(), FabricName, IPAddress)
+	if err != nil {
+		ErrorModel := errorModelOf(err, http.StatusInternalServerError, "")
+		ErrorModel.Device = IPAddress
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	var response Restmodel.DeviceSettingsResponse
+	response.FabricName = FabricName
+	response.DeviceIp = IPAddress
+	response.DeviceSettings = prepareDeviceSettingsResponse(&DeviceSettings)
+	response.FabricSettings = make(map[string]string, 0)
+	//Only the fabric settings which can be overridden are of interest
+	FabricSetting := make(map[string]string, 0)
+	prepareFabricResponse(&FabricProperties, FabricSetting)
+	for key := range response.DeviceSettings {
+		response.FabricSettings[key] = FabricSetting[key]
+	}
+	bytess, _ := json.Marshal(&response)
+	w.Write(bytess)
+}
+
+//prepareDeviceSettingsResponse returns the overridable settings of a device keyed by name.
+//Settings without an override are returned with an empty value
+func prepareDeviceSettingsResponse(DeviceSettings *domain.DeviceSettings) map[string]string {
+	return map[string]string{
+		"MTU":                           DeviceSettings.MTU,
+		"IPMTU":                         DeviceSettings.IPMTU,
+		"BFDEnable":                     DeviceSettings.BFDEnable,
+		"BFDTx":                         DeviceSettings.BFDTx,
+		"BFDRx":                         DeviceSettings.BFDRx,
+		"BFDMultiplier":                 DeviceSettings.BFDMultiplier,
+		"MaxPaths":                      DeviceSettings.MaxPaths,
+		"AllowASIn":                     DeviceSettings.AllowASIn,
+		"LeafPeerGroup":                 DeviceSettings.LeafPeerGroup,
+		"SpinePeerGroup":                DeviceSettings.SpinePeerGroup,
+		"ArpAgingTimeout":               DeviceSettings.ArpAgingTimeout,
+		"MacAgingTimeout":               DeviceSettings.MacAgingTimeout,
+		"MacAgingConversationalTimeout": DeviceSettings.MacAgingConversationalTimeout,
+		"MacMoveLimit":                  DeviceSettings.MacMoveLimit,
+		"DuplicateMacTimer":             DeviceSettings.DuplicateMacTimer,
+		"DuplicateMaxTimerMaxCount":     DeviceSettings.DuplicateMaxTimerMaxCount,
+	}
+}
+
+//validateDeviceSettings validates the per-device settings using the fabric-setting rules.
+//The "default" value which clears an override is exempt from validation
+func validateDeviceSettings(FabricName string, DeviceUpdate *domain.DeviceSettings) map[string]string {
+	value := func(s string) string {
+		if s == usecase.DeviceSettingsDefault {
+			return ""
+		}
+		return s
+	}
+	FabricProperties := domain.FabricProperties{
+		MTU:                           value(DeviceUpdate.MTU),
+		IPMTU:                         value(DeviceUpdate.IPMTU),
+		BFDEnable:                     value(DeviceUpdate.BFDEnable),
+		BFDTx:                         value(DeviceUpdate.BFDTx),
+		BFDRx:                         value(DeviceUpdate.BFDRx),
+		BFDMultiplier:                 value(DeviceUpdate.BFDMultiplier),
+		MaxPaths:                      value(DeviceUpdate.MaxPaths),
+		AllowASIn:                     value(DeviceUpdate.AllowASIn),
+		LeafPeerGroup:                 value(DeviceUpdate.LeafPeerGroup),
+		SpinePeerGroup:                value(DeviceUpdate.SpinePeerGroup),
+		ArpAgingTimeout:               value(DeviceUpdate.ArpAgingTimeout),
+		MacAgingTimeout:               value(DeviceUpdate.MacAgingTimeout),
+		MacAgingConversationalTimeout: value(DeviceUpdate.MacAgingConversationalTimeout),
+		MacMoveLimit:                  value(DeviceUpdate.MacMoveLimit),
+		DuplicateMacTimer:             value(DeviceUpdate.DuplicateMacTimer),
+		DuplicateMaxTimerMaxCount:     value(DeviceUpdate.DuplicateMaxTimerMaxCount),
+	}
+	errMap := ValidateFabricProperties(FabricName, &FabricProperties)
+
+	//Pick up the normalized Yes/No value
+	if len(FabricProperties.BFDEnable) != 0 {
+		DeviceUpdate.BFDEnable = FabricProperties.BFDEnable
+	}
+	return errMap
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"efa-server/domain"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+)
+
+//Error codes of the ErrorModel, the clients match on them rather than on the messages
+const (
+	ErrorCodeInvalidRequest        = "INVALID_REQUEST"
+	ErrorCodeInvalidSetting        = "INVALID_SETTING"
+	ErrorCodeNotFound              = "NOT_FOUND"
+	ErrorCodeFabricNotFound        = "FABRIC_NOT_FOUND"
+	ErrorCodeDeviceNotFound        = "DEVICE_NOT_FOUND"
+	ErrorCodeBackupNotFound        = "BACKUP_NOT_FOUND"
+	ErrorCodeGenerationNotFound    = "GENERATION_NOT_FOUND"
+	ErrorCodeSettingChangeNotFound = "SETTING_CHANGE_NOT_FOUND"
+	ErrorCodeSubscriptionNotFound  = "SUBSCRIPTION_NOT_FOUND"
+	ErrorCodeFabricActive          = "FABRIC_ACTIVE"
+	ErrorCodeDeviceInMaintenance   = "DEVICE_IN_MAINTENANCE"
+	ErrorCodeDeviceFailure         = "DEVICE_FAILURE"
+	ErrorCodeDeliveryFailed        = "DELIVERY_FAILED"
+	ErrorCodeInternal              = "INTERNAL_ERROR"
+)
+
+//domainErrors maps the errors of the use cases to their HTTP status and error code
+var domainErrors = map[error]struct {
+	Status    int
+	ErrorCode string
+}{
+	domain.ErrFabricNotFound:             {http.StatusNotFound, ErrorCodeFabricNotFound},
+	domain.ErrFabricActive:               {http.StatusConflict, ErrorCodeFabricActive},
+	domain.ErrFabricIncorrectValues:      {http.StatusBadRequest, ErrorCodeInvalidSetting},
+	domain.ErrFabricInternalError:        {http.StatusInternalServerError, ErrorCodeInternal},
+	domain.ErrFabricDeviceInMaintenance:  {http.StatusConflict, ErrorCodeDeviceInMaintenance},
+	domain.ErrDeviceNotFound:             {http.StatusNotFound, ErrorCodeDeviceNotFound},
+	domain.ErrBackupNotFound:             {http.StatusNotFound, ErrorCodeBackupNotFound},
+	domain.ErrGenerationNotFound:         {http.StatusNotFound, ErrorCodeGenerationNotFound},
+	domain.ErrSettingChangeNotFound:      {http.StatusNotFound, ErrorCodeSettingChangeNotFound},
+	domain.ErrSubscriptionNotFound:       {http.StatusNotFound, ErrorCodeSubscriptionNotFound},
+	domain.ErrSubscriptionInvalid:        {http.StatusBadRequest, ErrorCodeInvalidRequest},
+	domain.ErrCablingPlanInvalid:         {http.StatusBadRequest, ErrorCodeInvalidRequest},
+	domain.ErrPreflightOverrideInvalid:   {http.StatusBadRequest, ErrorCodeInvalidRequest},
+	domain.ErrNotificationDeliveryFailed: {http.StatusBadGateway, ErrorCodeDeliveryFailed},
+}
+
+//errorCodeOfStatus returns the error code of a failure without a more specific code
+func errorCodeOfStatus(Status int) string {
+	switch Status {
+	case http.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeFabricActive
+	}
+	return ErrorCodeInternal
+}
+
+//newErrorModel returns the ErrorModel of a failure of the given status
+func newErrorModel(Status int, Message string) Restmodel.ErrorModel {
+	return Restmodel.ErrorModel{Message: Message, Code: int32(Status), ErrorCode: errorCodeOfStatus(Status)}
+}
+
+//errorModelOf returns the ErrorModel of an error of the use cases. The status and the error code are those
+//of the domain error, else Status. The message is the one returned by the use case when there is one.
+func errorModelOf(err error, Status int, Message string) Restmodel.ErrorModel {
+	ErrorModel := newErrorModel(Status, Message)
+	if Known, found := domainErrors[err]; found {
+		ErrorModel.Code, ErrorModel.ErrorCode = int32(Known.Status), Known.ErrorCode
+	}
+	if len(ErrorModel.Message) == 0 {
+		ErrorModel.Message = err.Error()
+	}
+	return ErrorModel
+}
+
+//extendErrorModel returns the ExtendedErrorModel holding the ErrorModel
+func extendErrorModel(ErrorModel Restmodel.ErrorModel) Restmodel.ExtendedErrorModel {
+	return Restmodel.ExtendedErrorModel{Message: ErrorModel.Message, Code: ErrorModel.Code,
+		ErrorCode: ErrorModel.ErrorCode, Field: ErrorModel.Field, Device: ErrorModel.Device,
+		ExecutionId: ErrorModel.ExecutionId}
+}
+
+//settingErrors returns an ErrorModel per failing setting, ordered by setting
+func settingErrors(errMap map[string]string) []Restmodel.ErrorModel {
+	Settings := make([]string, 0, len(errMap))
+	for Setting := range errMap {
+		Settings = append(Settings, Setting)
+	}
+	sort.Strings(Settings)
+	Errors := make([]Restmodel.ErrorModel, 0, len(Settings))
+	for _, Setting := range Settings {
+		Errors = append(Errors, Restmodel.ErrorModel{Message: errMap[Setting], Code: http.StatusBadRequest,
+			ErrorCode: ErrorCodeInvalidSetting, Field: Setting})
+	}
+	return Errors
+}
+
+//writeErrorModel writes a failure as an ErrorModel with its status. It replaces http.Error, which writes a
+//text/plain body the clients cannot decode.
+func writeErrorModel(w http.ResponseWriter, ErrorModel Restmodel.ErrorModel) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(int(ErrorModel.Code))
+	json.NewEncoder(w).Encode(&ErrorModel)
+}
+
+//writeExtendedErrorModel writes a failure with the errors of each setting or device as an ExtendedErrorModel
+func writeExtendedErrorModel(w http.ResponseWriter, ErrorModel Restmodel.ExtendedErrorModel) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(int(ErrorModel.Code))
+	json.NewEncoder(w).Encode(&ErrorModel)
+}
+
+//writeSettingErrors writes the failures of the validation of the settings of a request, one error per setting
+func writeSettingErrors(w http.ResponseWriter, Message string, errMap map[string]string, ExecutionID string) {
+	writeExtendedErrorModel(w, Restmodel.ExtendedErrorModel{Message: Message, Code: http.StatusBadRequest,
+		ErrorCode: ErrorCodeInvalidSetting, ExecutionId: ExecutionID, Errors: settingErrors(errMap)})
+}
+
+//writeRequestBodyError writes the failure to decode the body of a request
+func writeRequestBodyError(w http.ResponseWriter, err error, ExecutionID string) {
+	ErrorModel := newErrorModel(http.StatusBadRequest, "Invalid request body: "+err.Error())
+	ErrorModel.ExecutionId = ExecutionID
+	writeErrorModel(w, ErrorModel)
+}
+
+//writeUseCaseError writes the failure of a use case, Message being the status returned by the use case
+func writeUseCaseError(w http.ResponseWriter, err error, Message string, ExecutionID string) {
+	ErrorModel := errorModelOf(err, http.StatusInternalServerError, Message)
+	ErrorModel.ExecutionId = ExecutionID
+	writeErrorModel(w, ErrorModel)
+}
+
+//deviceError returns the ErrorModel of a failure on a device, a failure without device being internal
+func deviceError(IPAddress string, Message string, ExecutionID string) Restmodel.ErrorModel {
+	ErrorModel := Restmodel.ErrorModel{Message: Message, Code: http.StatusInternalServerError,
+		ErrorCode: ErrorCodeDeviceFailure, Device: IPAddress, ExecutionId: ExecutionID}
+	if len(IPAddress) == 0 {
+		ErrorModel.ErrorCode = ErrorCodeInternal
+	}
+	return ErrorModel
+}
+
+//writeDeviceErrors writes the failure of a request on several devices along with the status of each device
+func writeDeviceErrors(w http.ResponseWriter, Message string, Devices []Restmodel.DeviceStatusModel, ExecutionID string) {
+	OpenAPIError := Restmodel.ExtendedErrorModel{Message: Message, Code: http.StatusInternalServerError,
+		ErrorCode: ErrorCodeDeviceFailure, ExecutionId: ExecutionID, Devices: Devices}
+	for _, Device := range Devices {
+		OpenAPIError.Errors = append(OpenAPIError.Errors, Device.Error_...)
+	}
+	writeExtendedErrorModel(w, OpenAPIError)
+}
+
+//NotFound is a REST handler for the requests matching no route
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeErrorModel(w, newErrorModel(http.StatusNotFound, fmt.Sprintf("%s is not a REST resource of the server", r.URL.Path)))
+}
+
+//MethodNotAllowed is a REST handler for the requests of a route with an unsupported method
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	ErrorModel := newErrorModel(http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+	ErrorModel.ErrorCode = ErrorCodeInvalidRequest
+	writeErrorModel(w, ErrorModel)
+}
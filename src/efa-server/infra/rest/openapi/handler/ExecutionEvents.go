@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/events"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"time"
+)
+
+//ExecutionEventsWait is how long a stream waits for its execution to start, the client opens the stream
+//along with the request running the execution
+var ExecutionEventsWait = 60 * time.Second
+
+//StreamExecutionEvents provides REST handler streaming the progress events of an execution as Server-Sent
+//Events, from the start of the execution until its end
+func StreamExecutionEvents(w http.ResponseWriter, r *http.Request) {
+	ExecutionID := mux.Vars(r)["id"]
+
+	Flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorModel(w, newErrorModel(http.StatusInternalServerError, "Streaming is not supported"))
+		return
+	}
+
+	//An execution already recorded is not waited for, its events are available only while it runs and
+	//for the retention after its end
+	Wait := ExecutionEventsWait
+	if _, err := infra.GetUseCaseInteractor().Db.GetExecutionLogByUUID(ExecutionID); err == nil {
+		Wait = 0
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), Wait)
+	Subscription, found := events.Subscribe(ctx, ExecutionID)
+	cancel()
+	if !found {
+		writeErrorModel(w, newErrorModel(http.StatusNotFound,
+			fmt.Sprintf("Events of the execution %s are not available", ExecutionID)))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	Flusher.Flush()
+	for {
+		Event, ok := Subscription.Next(r.Context())
+		if !ok {
+			return
+		}
+		Data, _ := json.Marshal(Event)
+		fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", Event.Sequence, Event.Type, Data)
+		Flusher.Flush()
+	}
+}
+
+//executionIDOf returns the execution ID chosen by the client for the request, empty when the client did not
+//choose one or chose an ID which is not a UUID or was already used
+func executionIDOf(r *http.Request) string {
+	ExecutionID := r.Header.Get(constants.ExecutionIDHeader)
+	if _, err := uuid.Parse(ExecutionID); err != nil {
+		return ""
+	}
+	if _, err := infra.GetUseCaseInteractor().Db.GetExecutionLogByUUID(ExecutionID); err == nil {
+		return ""
+	}
+	return ExecutionID
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+)
+
+//UpdateCablingPlan is a REST handler which replaces the cabling plan of the fabric
+func UpdateCablingPlan(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var PlanRequest Restmodel.CablingPlanRequest
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Update Cabling Plan"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(b, &PlanRequest); err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName": PlanRequest.FabricName,
+		"Links":      len(PlanRequest.Links),
+	}
+	alog.LogMessageReceived()
+
+	Links := make([]domain.CablingLink, 0, len(PlanRequest.Links))
+	for _, Link := range PlanRequest.Links {
+		Links = append(Links, domain.CablingLink{DeviceOneIP: Link.DeviceOne, InterfaceOneName: Link.InterfaceOne,
+			DeviceTwoIP: Link.DeviceTwo, InterfaceTwoName: Link.InterfaceTwo})
+	}
+	Links, ret, err := infra.GetUseCaseInteractor().UpdateCablingPlan(ctx, PlanRequest.FabricName, Links)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	OpenAPIResp := prepareCablingPlanResponse(PlanRequest.FabricName, Links)
+	OpenAPIResp.Message = ret
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//DeleteCablingPlan is a REST handler which deletes the cabling plan of the fabric
+func DeleteCablingPlan(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Delete Cabling Plan"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName": FabricName,
+	}
+	alog.LogMessageReceived()
+
+	Links, ret, err := infra.GetUseCaseInteractor().UpdateCablingPlan(ctx, FabricName, []domain.CablingLink{})
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	OpenAPIResp := prepareCablingPlanResponse(FabricName, Links)
+	OpenAPIResp.Message = ret
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//ShowCablingPlan is a REST handler to handle
+// GET request for the cabling plan of the fabric
+func ShowCablingPlan(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+
+	Links, err := infra.GetUseCaseInteractor().GetCablingPlan(r.Context(), FabricName)
+	if err != nil {
+		writeUseCaseError(w, err, "", "")
+		return
+	}
+
+	OpenAPIResp := prepareCablingPlanResponse(FabricName, Links)
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+func prepareCablingPlanResponse(FabricName string, Links []domain.CablingLink) Restmodel.CablingPlanResponse {
+	Response := Restmodel.CablingPlanResponse{FabricName: FabricName}
+	Response.Links = make([]Restmodel.CablingLink, 0, len(Links))
+	for _, Link := range Links {
+		Response.Links = append(Response.Links, Restmodel.CablingLink{DeviceOne: Link.DeviceOneIP,
+			InterfaceOne: Link.InterfaceOneName, DeviceTwo: Link.DeviceTwoIP, InterfaceTwo: Link.InterfaceTwoName})
+	}
+	return Response
+}
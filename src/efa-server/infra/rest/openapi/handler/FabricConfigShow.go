@@ -42,12 +42,7 @@ func FabricConfigShow(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		statusMsg = fmt.Sprintf("%s Failed.", CommandName)
 		success = false
-		http.Error(w, "",
-			http.StatusInternalServerError)
-		OpenAPIError := swagger.ErrorModel{Message: err.Error()}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
-
+		writeUseCaseError(w, err, "", alog.ReqID)
 	} else {
 		//Send  Fabric config Show Response
 		statusMsg = fmt.Sprintf("%s Succeeded.", CommandName)
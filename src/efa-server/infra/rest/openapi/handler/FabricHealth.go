@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"github.com/gorilla/mux"
+)
+
+//ShowFabricHealth is a REST handler to handle
+// GET request for the health of the devices of the fabric
+func ShowFabricHealth(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	vars := mux.Vars(r)
+	FabricName := vars["name"]
+
+	Health, err := infra.GetUseCaseInteractor().FabricHealth(r.Context(), FabricName)
+	if err != nil {
+		writeUseCaseError(w, err, "", "")
+		return
+	}
+	bytess, _ := json.Marshal(fabricHealthModel(Health))
+	w.Write(bytess)
+}
+
+//fabricHealthModel returns the REST model of the health of a fabric
+func fabricHealthModel(Health domain.FabricHealth) *Restmodel.FabricHealthResponse {
+	response := Restmodel.FabricHealthResponse{FabricName: Health.FabricName, Healthy: Health.Healthy,
+		CheckedAt: Health.CheckedAt}
+	response.Devices = make([]Restmodel.DeviceHealth, 0, len(Health.Devices))
+	for _, Device := range Health.Devices {
+		DeviceHealth := Restmodel.DeviceHealth{IpAddress: Device.IPAddress, Role: Device.Role, Healthy: Device.Healthy}
+		DeviceHealth.Checks = make([]Restmodel.HealthCheck, 0, len(Device.Checks))
+		for _, Check := range Device.Checks {
+			DeviceHealth.Checks = append(DeviceHealth.Checks, Restmodel.HealthCheck{Name: Check.Name,
+				Status: Check.Status, Message: Check.Message})
+		}
+		response.Devices = append(response.Devices, DeviceHealth)
+	}
+	return &response
+}
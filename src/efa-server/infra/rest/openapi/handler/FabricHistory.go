@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"strconv"
+)
+
+//ShowFabricHistory is a REST handler to handle
+// GET request for the configuration generations of a fabric
+func ShowFabricHistory(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+
+	Generations, ret, err := infra.GetUseCaseInteractor().GetConfigGenerations(r.Context(), FabricName)
+	if err != nil {
+		writeUseCaseError(w, err, ret, "")
+		return
+	}
+
+	OpenAPIResp := Restmodel.FabricHistoryResponse{FabricName: FabricName,
+		Generations: make([]Restmodel.ConfigGeneration, 0, len(Generations))}
+	for _, Generation := range Generations {
+		OpenAPIResp.Generations = append(OpenAPIResp.Generations, Restmodel.ConfigGeneration{
+			Generation:  int32(Generation.Generation),
+			ExecutionId: Generation.ExecutionID,
+			CreatedAt:   Generation.CreatedAt,
+			Devices:     int32(len(Generation.Devices)),
+		})
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//ShowFabricDiff is a REST handler to handle
+// GET request for the changes between two configuration generations of a fabric
+func ShowFabricDiff(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	From, errFrom := strconv.ParseUint(vars["from"], 10, 32)
+	To, errTo := strconv.ParseUint(vars["to"], 10, 32)
+	if errFrom != nil || errTo != nil {
+		writeErrorModel(w, newErrorModel(http.StatusBadRequest, "from and to should be generation numbers"))
+		return
+	}
+
+	Changes, ret, err := infra.GetUseCaseInteractor().DiffConfigGenerations(r.Context(), FabricName, uint(From), uint(To))
+	if err != nil {
+		writeUseCaseError(w, err, ret, "")
+		return
+	}
+
+	OpenAPIResp := Restmodel.FabricDiffResponse{FabricName: FabricName, From: int32(From), To: int32(To),
+		Changes: prepareConfigChanges(Changes)}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//RevertFabric is a REST handler which restores the intended configuration of a generation and configures the fabric
+func RevertFabric(w http.ResponseWriter, r *http.Request) {
+	Unlock := lockRest()
+	defer Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric revert"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	Generation := vars["generation"]
+	Persist := vars["persist"]
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName": FabricName,
+		"Generation": Generation,
+		"Persist":    Persist,
+	}
+	alog.LogMessageReceived()
+
+	Number, err := strconv.ParseUint(Generation, 10, 32)
+	if err != nil {
+		success = false
+		statusMsg = "Fabric Revert Parameter Validation Failed"
+		ErrorModel := newErrorModel(http.StatusBadRequest, "generation should be a generation number")
+		ErrorModel.ExecutionId = alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+	PersistBool, _ := strconv.ParseBool(Persist)
+
+	ctx = context.WithValue(ctx, appcontext.DeferHealthCheck, true)
+	response, ret, err := infra.GetUseCaseInteractor().RevertFabric(ctx, FabricName, uint(Number), PersistBool)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	OpenAPIResp := Restmodel.FabricRevertResponse{
+		FabricName: FabricName,
+		Generation: int32(response.Generation),
+		Changes:    prepareConfigChanges(response.Changes),
+		Configure: &Restmodel.ConfigureFabricResponse{FabricName: FabricName, Status: "Successful",
+			PoolWarnings: response.Configure.PoolWarnings, Generation: int32(response.Configure.Generation)},
+	}
+	//The other requests are served while the fabric settles, a fabric already at the generation is not configured
+	Unlock()
+	if len(response.Changes) != 0 {
+		if Health := infra.GetUseCaseInteractor().VerifyFabricHealth(ctx, FabricName); Health != nil {
+			OpenAPIResp.Configure.Health = fabricHealthModel(*Health)
+		}
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+func prepareConfigChanges(Changes []domain.ConfigChange) []Restmodel.ConfigChange {
+	OpenAPIChanges := make([]Restmodel.ConfigChange, 0, len(Changes))
+	for _, Change := range Changes {
+		OpenAPIChanges = append(OpenAPIChanges, Restmodel.ConfigChange{Device: Change.DeviceIP, Kind: Change.Kind,
+			Name: Change.Name, Change: Change.Change, Old: Change.Old, New: Change.New})
+	}
+	return OpenAPIChanges
+}
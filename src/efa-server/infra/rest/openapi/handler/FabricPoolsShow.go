@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"github.com/gorilla/mux"
+)
+
+//ShowFabricPools is a REST handler to handle
+// GET request for the utilization of the fabric allocation pools
+func ShowFabricPools(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	vars := mux.Vars(r)
+	FabricName := vars["name"]
+
+	PoolResponse, err := infra.GetUseCaseInteractor().GetPoolUtilization(r.Context(), FabricName)
+	if err != nil {
+		writeUseCaseError(w, err, "", "")
+		return
+	}
+
+	response := Restmodel.FabricPoolsResponse{FabricName: PoolResponse.FabricName,
+		Threshold: int32(PoolResponse.Threshold), PoolWarnings: PoolResponse.Warnings}
+	response.Pools = make([]Restmodel.PoolUtilization, 0, len(PoolResponse.Pools))
+	for _, Pool := range PoolResponse.Pools {
+		Utilization := Restmodel.PoolUtilization{PoolType: Pool.PoolType, PoolName: Pool.PoolName, Range: Pool.Range,
+			Used: int64(Pool.Used), Free: int64(Pool.Free), Utilization: int32(Pool.Utilization)}
+		Utilization.Allocations = make([]Restmodel.PoolAllocation, 0, len(Pool.Allocations))
+		for _, Allocation := range Pool.Allocations {
+			Utilization.Allocations = append(Utilization.Allocations, Restmodel.PoolAllocation{Value: Allocation.Value,
+				DeviceIp: Allocation.IPAddress, InterfaceName: Allocation.InterfaceName})
+		}
+		response.Pools = append(response.Pools, Utilization)
+	}
+	bytess, _ := json.Marshal(&response)
+	w.Write(bytess)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"efa-server/usecase"
+	"encoding/json"
+	"io/ioutil"
+)
+
+//PreviewFabricSettings is a REST handler which computes the impact of a Fabric Settings Update without saving the settings
+func PreviewFabricSettings(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	var FabricSettings Restmodel.FabricSettings
+	b, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(b, &FabricSettings); err != nil {
+		writeRequestBodyError(w, err, "")
+		return
+	}
+	FabricName := FabricSettings.Name
+	FabricUpdate, errMap := parseFabricSettings(FabricSettings)
+	if len(errMap) == 0 {
+		errMap = ValidateFabricProperties(FabricName, &FabricUpdate)
+	}
+	if len(errMap) != 0 {
+		writeSettingErrors(w, "Fabric Settings Preview Parameter Validation Failed", errMap, "")
+		return
+	}
+
+	Preview, ret, err := infra.GetUseCaseInteractor().PreviewFabricProperties(r.Context(), FabricName, &FabricUpdate)
+	if err != nil {
+		writeUseCaseError(w, err, ret, "")
+		return
+	}
+
+	OpenAPIResp := Restmodel.FabricPreviewResponse{
+		FabricName:    FabricName,
+		Settings:      prepareConfigChanges(Preview.Settings),
+		Changes:       prepareConfigChanges(Preview.Changes),
+		Reallocations: preparePoolReallocations(Preview.Reallocations),
+		Blocked:       Preview.Blocked,
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+func preparePoolReallocations(Reallocations []usecase.PoolReallocation) []Restmodel.PoolReallocation {
+	OpenAPIReallocations := make([]Restmodel.PoolReallocation, 0, len(Reallocations))
+	for _, Reallocation := range Reallocations {
+		OpenAPIReallocations = append(OpenAPIReallocations, Restmodel.PoolReallocation{PoolType: Reallocation.PoolType,
+			PoolName: Reallocation.PoolName, Range: Reallocation.Range, Value: Reallocation.Value,
+			DeviceIp: Reallocation.IPAddress, InterfaceName: Reallocation.InterfaceName})
+	}
+	return OpenAPIReallocations
+}
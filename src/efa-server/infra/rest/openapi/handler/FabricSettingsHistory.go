@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"efa-server/gateway/appcontext"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"strconv"
+	"strings"
+)
+
+//withRequestUser returns the context carrying the user of the request, named by the efa client or else the
+//address the request came from
+func withRequestUser(ctx context.Context, r *http.Request) context.Context {
+	UserName := r.Header.Get(constants.UserNameHeader)
+	if len(UserName) == 0 {
+		UserName = r.RemoteAddr
+		if Host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			UserName = Host
+		}
+	}
+	return context.WithValue(ctx, appcontext.UserName, UserName)
+}
+
+//ShowFabricSettingsHistory is a REST handler to handle
+// GET request for the changes of the fabric settings
+func ShowFabricSettingsHistory(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+
+	Changes, ret, err := infra.GetUseCaseInteractor().GetFabricSettingsHistory(r.Context(), FabricName)
+	if err != nil {
+		writeUseCaseError(w, err, ret, "")
+		return
+	}
+
+	OpenAPIResp := Restmodel.FabricSettingsHistoryResponse{FabricName: FabricName,
+		Changes: make([]Restmodel.FabricSettingChange, 0, len(Changes))}
+	for _, Change := range Changes {
+		OpenAPIResp.Changes = append(OpenAPIResp.Changes, Restmodel.FabricSettingChange{
+			Id:          int32(Change.ID),
+			Setting:     Change.Setting,
+			OldValue:    maskSettingValue(Change.Setting, Change.OldValue),
+			NewValue:    maskSettingValue(Change.Setting, Change.NewValue),
+			User:        Change.UserName,
+			ExecutionId: Change.ExecutionID,
+			ChangedAt:   Change.ChangedAt,
+		})
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//RollbackFabricSettings is a REST handler which restores the fabric settings as of a change of the settings history
+func RollbackFabricSettings(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric setting rollback"}}
+	ctx := withRequestUser(alog.LogMessageInit(), r)
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	To := vars["to"]
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName": FabricName,
+		"To":         To,
+	}
+	alog.LogMessageReceived()
+
+	ToID, err := strconv.ParseUint(To, 10, 32)
+	if err != nil {
+		success = false
+		statusMsg = "Fabric Setting Rollback Parameter Validation Failed"
+		writeSettingErrors(w, statusMsg, map[string]string{"to": "to should be the id of a settings change"}, alog.ReqID)
+		return
+	}
+
+	UseCaseInteractor := infra.GetUseCaseInteractor()
+	Rollback, Settings, ret, err := UseCaseInteractor.PrepareFabricSettingsRollback(ctx, FabricName, uint(ToID))
+	if err == nil {
+		//The restored settings are checked as any settings update
+		if errMap := ValidateFabricProperties(FabricName, &Rollback); len(errMap) != 0 {
+			success = false
+			statusMsg = "Fabric Setting Rollback Parameter Validation Failed"
+			writeSettingErrors(w, statusMsg, errMap, alog.ReqID)
+			return
+		}
+		ret, _, err = UseCaseInteractor.UpdateFabricProperties(ctx, FabricName, &Rollback)
+	}
+	if err != nil {
+		success = false
+		statusMsg = ret
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	statusMsg = fmt.Sprintf("Fabric %s settings rolled back to change %d", FabricName, ToID)
+	for iter := range Settings {
+		Settings[iter].Old = maskSettingValue(Settings[iter].Name, Settings[iter].Old)
+		Settings[iter].New = maskSettingValue(Settings[iter].Name, Settings[iter].New)
+	}
+	OpenAPIResp := Restmodel.FabricSettingsRollbackResponse{FabricName: FabricName, To: int32(ToID),
+		Settings: prepareConfigChanges(Settings)}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//maskSettingValue hides the value of the password settings
+func maskSettingValue(Setting string, Value string) string {
+	if strings.Contains(Setting, "Password") {
+		return maskPassword(Value)
+	}
+	return Value
+}
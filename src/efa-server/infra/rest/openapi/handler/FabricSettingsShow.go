@@ -7,7 +7,6 @@ import (
 	"efa-server/infra"
 	"efa-server/infra/constants"
 	Restmodel "efa-server/infra/rest/generated/server/go"
-	"efa/infra/rest/generated/client"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/mux"
@@ -21,6 +20,10 @@ func prepareFabricResponse(FabricProperties *domain.FabricProperties, FabricSett
 		typeField := val.Type().Field(i)
 		FabricSetting[typeField.Name] = valueField.String()
 	}
+	//Never send the BGP passwords back to the client
+	for _, key := range []string{"PeerGroupPassword", "MctL2EvpnPassword", "RackPeerEBGPGroupPassword", "RackPeerOvgGroupPassword"} {
+		FabricSetting[key] = maskPassword(FabricSetting[key])
+	}
 }
 
 //ShowFabricSettings is a REST handler to handle
@@ -57,18 +60,10 @@ func ShowFabricSettings(w http.ResponseWriter, r *http.Request) {
 
 		} else {
 			statusMsg = fmt.Sprintf("Unable to retrieve Fabric Properties for %s\n", FabricName)
-			http.Error(w, "",
-				http.StatusNotFound)
-			OpenAPIError := swagger.ErrorModel{Message: statusMsg}
-			bytess, _ := json.Marshal(&OpenAPIError)
-			w.Write(bytess)
+			writeErrorModel(w, newErrorModel(http.StatusNotFound, statusMsg))
 		}
 	} else {
 		statusMsg = fmt.Sprintf("Unable to retrieve Fabric for %s\n", FabricName)
-		http.Error(w, "",
-			http.StatusNotFound)
-		OpenAPIError := swagger.ErrorModel{Message: statusMsg}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
+		writeErrorModel(w, errorModelOf(domain.ErrFabricNotFound, http.StatusNotFound, statusMsg))
 	}
 }
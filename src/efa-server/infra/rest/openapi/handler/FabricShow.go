@@ -3,12 +3,12 @@ package handler
 import (
 	"net/http"
 
+	"efa-server/domain"
 	"efa-server/infra"
 	"efa-server/infra/constants"
 	"efa-server/infra/device/adapter"
 	"efa-server/infra/logging"
 	Restmodel "efa-server/infra/rest/generated/server/go"
-	"efa/infra/rest/generated/client"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/mux"
@@ -45,6 +45,9 @@ func ShowDevicesInFabric(w http.ResponseWriter, r *http.Request) {
 				switchData := Restmodel.SwitchdataResponse{IpAddress: device.IPAddress, Role: device.DeviceRole,
 					Firmware: device.FirmwareVersion, Model: adapter.TranslateModelString(device.Model), Rack: rackName, Name: device.Name,
 					Fabric: &Restmodel.SwitchdataResponseFabric{FabricName: FabricName, FabricId: int32(Fabric.ID)}}
+				if device.MaintenanceMode {
+					switchData.State = "Maintenance"
+				}
 				response.Items = append(response.Items, switchData)
 
 			}
@@ -56,18 +59,10 @@ func ShowDevicesInFabric(w http.ResponseWriter, r *http.Request) {
 
 		} else {
 			statusMsg = fmt.Sprintf("Unable to retrieve Devices for %s\n", FabricName)
-			http.Error(w, "",
-				http.StatusNotFound)
-			OpenAPIError := swagger.ErrorModel{Message: statusMsg}
-			bytess, _ := json.Marshal(&OpenAPIError)
-			w.Write(bytess)
+			writeErrorModel(w, newErrorModel(http.StatusNotFound, statusMsg))
 		}
 	} else {
 		statusMsg = fmt.Sprintf("Unable to retrieve Fabric for %s\n", FabricName)
-		http.Error(w, "",
-			http.StatusNotFound)
-		OpenAPIError := swagger.ErrorModel{Message: statusMsg}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
+		writeErrorModel(w, errorModelOf(domain.ErrFabricNotFound, http.StatusNotFound, statusMsg))
 	}
 }
@@ -10,6 +10,8 @@ import (
 	"efa-server/infra/constants"
 	"efa-server/infra/rest/generated/server/go"
 	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -26,17 +28,17 @@ func ExecutionListHandler(w http.ResponseWriter, r *http.Request) {
 	status := vars["status"]
 
 	if status != "all" && status != "failed" && status != "succeeded" {
-		fmt.Println("Unsupported value for flag \"status\". It should be either \"succeeded/failed/all\".")
+		writeErrorModel(w, newErrorModel(http.StatusBadRequest,
+			"Unsupported value for flag \"status\". It should be either \"succeeded/failed/all\"."))
 		return
 	}
 
 	limitInt, err := strconv.Atoi(limit)
 	if err != nil {
-		fmt.Println("Execution list : Limit flag value could not be converted from string to integer. Limit : ", limit)
+		writeErrorModel(w, newErrorModel(http.StatusBadRequest,
+			"Execution list : Limit flag value could not be converted from string to integer. Limit : "+limit))
 		return
 	}
-	fmt.Println("Called Execution list with limit : " + limit + ", status : " + status)
-
 	if ExecutionList, properr := infra.GetUseCaseInteractor().Db.GetExecutionLogList(limitInt, status); properr == nil {
 		//Prepare the OpenAPI model
 		var OpenAPIExecutionsResponse swagger.ExecutionsResponse
@@ -55,8 +57,8 @@ func ExecutionListHandler(w http.ResponseWriter, r *http.Request) {
 		w.Write(jsonResponse)
 
 	} else {
-		statusMsg = fmt.Sprintf("Unable to retrieve Execution List\n")
-		http.Error(w, statusMsg, http.StatusInternalServerError)
+		statusMsg = fmt.Sprintf("Unable to retrieve Execution List: %s", properr)
+		writeErrorModel(w, newErrorModel(http.StatusInternalServerError, statusMsg))
 	}
 
 }
@@ -69,8 +71,6 @@ func ExecutionGetHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	execID := vars["id"]
 
-	fmt.Println("Called Execution get with id : ", execID)
-
 	if ExecutionLog, properr := infra.GetUseCaseInteractor().Db.GetExecutionLogByUUID(execID); properr == nil {
 		logs := getLogsForExecutionID(execID)
 
@@ -93,9 +93,11 @@ func ExecutionGetHandler(w http.ResponseWriter, r *http.Request) {
 		jsonResponse, _ = json.Marshal(OpenAPIDetailedExecutionResponse)
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(jsonResponse)
+	} else if properr == gorm.ErrRecordNotFound {
+		writeErrorModel(w, newErrorModel(http.StatusNotFound, fmt.Sprintf("Execution %s does not exist", execID)))
 	} else {
-		statusMsg = fmt.Sprintf("Unable to retrieve Execution Log\n")
-		http.Error(w, statusMsg, http.StatusInternalServerError)
+		statusMsg = fmt.Sprintf("Unable to retrieve Execution Log of %s: %s", execID, properr)
+		writeErrorModel(w, newErrorModel(http.StatusInternalServerError, statusMsg))
 	}
 }
 
@@ -103,7 +105,8 @@ func getLogsForExecutionID(execID string) string {
 	var logs string
 	logFile, err := os.Open(constants.LogLocation)
 	if err != nil {
-		fmt.Println("Error while opening the file : ", err.Error())
+		log.Printf("Unable to open the log file %s: %s", constants.LogLocation, err)
+		return logs
 	}
 	defer logFile.Close()
 	scanner := bufio.NewScanner(logFile)
@@ -114,7 +117,7 @@ func getLogsForExecutionID(execID string) string {
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
+		log.Printf("Unable to read the log file %s: %s", constants.LogLocation, err)
 	}
 	return logs
 }
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"strconv"
+)
+
+//UpdateMaintenanceMode is a REST handler which drains a device into maintenance mode or restores it
+func UpdateMaintenanceMode(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Update Maintenance Mode"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	IPAddress := vars["ip_address"]
+	Enable := vars["enable"]
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName": FabricName,
+		"DeviceIP":   IPAddress,
+		"Enable":     Enable,
+	}
+	alog.LogMessageReceived()
+
+	EnableBool, err := strconv.ParseBool(Enable)
+	if err != nil {
+		success = false
+		statusMsg = "Maintenance Mode Parameter Validation Failed"
+		ErrorModel := newErrorModel(http.StatusBadRequest, "enable should be true or false")
+		ErrorModel.Field, ErrorModel.ExecutionId = "enable", alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	ret, err := infra.GetUseCaseInteractor().UpdateMaintenanceMode(ctx, FabricName, IPAddress, EnableBool)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		ErrorModel := errorModelOf(err, http.StatusInternalServerError, ret)
+		ErrorModel.Device, ErrorModel.ExecutionId = IPAddress, alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	OpenAPIResp := Restmodel.DeviceMaintenanceResponse{
+		FabricName:      FabricName,
+		DeviceIp:        IPAddress,
+		MaintenanceMode: EnableBool,
+		Message:         ret,
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
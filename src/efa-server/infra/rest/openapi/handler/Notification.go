@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/domain"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"strconv"
+)
+
+//CreateNotificationSubscription is a REST handler which subscribes a webhook endpoint to the events of the server
+func CreateNotificationSubscription(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var SubscriptionRequest Restmodel.NotificationSubscriptionRequest
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "notification subscribe"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(b, &SubscriptionRequest); err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+
+	//update Request object after all parameters are received, the secret is not recorded
+	alog.Request.Params = map[string]interface{}{
+		"URL":     SubscriptionRequest.Url,
+		"Events":  SubscriptionRequest.Events,
+		"Retries": SubscriptionRequest.Retries,
+		"Signed":  len(SubscriptionRequest.Secret) != 0,
+	}
+	alog.LogMessageReceived()
+
+	if SubscriptionRequest.Retries < 0 {
+		success = false
+		statusMsg = fmt.Sprintf("Invalid retries %d", SubscriptionRequest.Retries)
+		writeUseCaseError(w, domain.ErrSubscriptionInvalid, statusMsg, alog.ReqID)
+		return
+	}
+	Subscription, ret, err := infra.GetUseCaseInteractor().AddNotificationSubscription(ctx,
+		domain.NotificationSubscription{URL: SubscriptionRequest.Url, Secret: SubscriptionRequest.Secret,
+			Events: SubscriptionRequest.Events, Retries: uint(SubscriptionRequest.Retries)})
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	statusMsg = fmt.Sprintf("Notification subscription %d created", Subscription.ID)
+	OpenAPIResp := prepareNotificationSubscription(Subscription)
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//ShowNotificationSubscriptions is a REST handler to handle
+// GET request for the notification subscriptions
+func ShowNotificationSubscriptions(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+
+	Subscriptions, ret, err := infra.GetUseCaseInteractor().GetNotificationSubscriptions(r.Context())
+	if err != nil {
+		writeUseCaseError(w, err, ret, "")
+		return
+	}
+
+	OpenAPIResp := Restmodel.NotificationSubscriptionsResponse{
+		Subscriptions: make([]Restmodel.NotificationSubscription, 0, len(Subscriptions))}
+	for _, Subscription := range Subscriptions {
+		OpenAPIResp.Subscriptions = append(OpenAPIResp.Subscriptions, prepareNotificationSubscription(Subscription))
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//DeleteNotificationSubscription is a REST handler which deletes a notification subscription
+func DeleteNotificationSubscription(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "notification delete"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	ID := mux.Vars(r)["id"]
+	alog.Request.Params = map[string]interface{}{
+		"ID": ID,
+	}
+	alog.LogMessageReceived()
+
+	SubscriptionID, err := strconv.ParseUint(ID, 10, 32)
+	if err != nil {
+		success = false
+		statusMsg = fmt.Sprintf("Invalid notification subscription id %q", ID)
+		writeErrorModel(w, newErrorModel(http.StatusBadRequest, statusMsg))
+		return
+	}
+
+	Subscription, ret, err := infra.GetUseCaseInteractor().DeleteNotificationSubscription(ctx, uint(SubscriptionID))
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	statusMsg = fmt.Sprintf("Notification subscription %d deleted", Subscription.ID)
+	OpenAPIResp := prepareNotificationSubscription(Subscription)
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//TestNotificationSubscription is a REST handler which posts a test event to the endpoint of a subscription
+func TestNotificationSubscription(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "notification test"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	ID := mux.Vars(r)["id"]
+	alog.Request.Params = map[string]interface{}{
+		"ID": ID,
+	}
+	alog.LogMessageReceived()
+
+	SubscriptionID, err := strconv.ParseUint(ID, 10, 32)
+	if err != nil {
+		success = false
+		statusMsg = fmt.Sprintf("Invalid notification subscription id %q", ID)
+		writeErrorModel(w, newErrorModel(http.StatusBadRequest, statusMsg))
+		return
+	}
+
+	Event, ret, err := infra.GetUseCaseInteractor().TestNotificationSubscription(ctx, uint(SubscriptionID))
+	statusMsg = ret
+	if err != nil {
+		success = false
+		writeUseCaseError(w, err, ret, alog.ReqID)
+		return
+	}
+
+	statusMsg = fmt.Sprintf("Test event %s delivered to notification subscription %d", Event.ID, SubscriptionID)
+	OpenAPIResp := prepareNotificationEvent(Event)
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
+
+//prepareNotificationSubscription returns the subscription without its secret
+func prepareNotificationSubscription(Subscription domain.NotificationSubscription) Restmodel.NotificationSubscription {
+	return Restmodel.NotificationSubscription{Id: int32(Subscription.ID), Url: Subscription.URL,
+		Events: Subscription.Events, Signed: len(Subscription.Secret) != 0, Retries: int32(Subscription.Retries),
+		CreatedAt: Subscription.CreatedAt}
+}
+
+func prepareNotificationEvent(Event domain.NotificationEvent) Restmodel.NotificationEvent {
+	OpenAPIEvent := Restmodel.NotificationEvent{Id: Event.ID, Event: Event.Event, Time: Event.Time,
+		Fabric: Event.Fabric, ExecutionId: Event.ExecutionID, Command: Event.Command, Status: Event.Status,
+		Device: Event.Device, Message: Event.Message, MissingLinks: Event.MissingLinks}
+	for _, Error := range Event.Errors {
+		OpenAPIEvent.Errors = append(OpenAPIEvent.Errors, Restmodel.NotificationDeviceError{Device: Error.Device,
+			Operation: Error.Operation, Message: Error.Message})
+	}
+	return OpenAPIEvent
+}
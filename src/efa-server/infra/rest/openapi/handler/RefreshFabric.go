@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"bytes"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+)
+
+//RefreshFabric is a REST handler which re-discovers the links of the fabric and configures the changed links
+func RefreshFabric(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric refresh"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	vars := mux.Vars(r)
+	FabricName := vars["fabric_name"]
+	IPAddress := vars["ip_address"]
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName": FabricName,
+		"DeviceIP":   IPAddress,
+	}
+	alog.LogMessageReceived()
+
+	response, err := infra.GetUseCaseInteractor().RefreshFabric(ctx, FabricName, IPAddress)
+	if err != nil {
+		success = false
+
+		//Buffer for writing messages to the Log
+		var buffer bytes.Buffer
+		for _, RefreshError := range response.Errors {
+			alog.AddDeviceError(RefreshError.Host, RefreshError.Operation, RefreshError.Error)
+			if len(RefreshError.Host) != 0 {
+				buffer.WriteString(fmt.Sprintf("%s: ", RefreshError.Host))
+			}
+			if RefreshError.Error != nil {
+				buffer.WriteString(fmt.Sprintf("Operation[%s] has failed with the reason:%s\n",
+					RefreshError.Operation, RefreshError.Error.Error()))
+			} else {
+				buffer.WriteString(fmt.Sprintf("Operation[%s] has failed, with unknown reason\n", RefreshError.Operation))
+			}
+		}
+		statusMsg = buffer.String()
+		ErrorModel := errorModelOf(err, http.StatusInternalServerError, statusMsg)
+		ErrorModel.Device, ErrorModel.ExecutionId = IPAddress, alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	statusMsg = fmt.Sprintf("Refresh Fabric Succeeded: %d added, %d removed, %d moved links", len(response.AddedLinks),
+		len(response.RemovedLinks), len(response.MovedLinks))
+	OpenAPIResp := Restmodel.FabricRefreshResponse{
+		FabricName:   FabricName,
+		AddedLinks:   response.AddedLinks,
+		RemovedLinks: response.RemovedLinks,
+		MovedLinks:   response.MovedLinks,
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
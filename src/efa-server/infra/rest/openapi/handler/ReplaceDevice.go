@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"encoding/json"
+	"io/ioutil"
+)
+
+//ReplaceDevice is a REST handler which replaces a device of the fabric with a new unit
+func ReplaceDevice(w http.ResponseWriter, r *http.Request) {
+	constants.RestLock.Lock()
+	defer constants.RestLock.Unlock()
+	success := true
+	statusMsg := ""
+
+	var ReplaceRequest Restmodel.DeviceReplaceRequest
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "Replace Device"}}
+	ctx := alog.LogMessageInit()
+	defer alog.LogMessageEnd(&success, &statusMsg)
+
+	b, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(b, &ReplaceRequest); err != nil {
+		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
+		return
+	}
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName":   ReplaceRequest.FabricName,
+		"OldIPAddress": ReplaceRequest.OldIpAddress,
+		"NewIPAddress": ReplaceRequest.NewIpAddress,
+		"UserName":     ReplaceRequest.Username,
+	}
+	alog.LogMessageReceived()
+
+	if len(ReplaceRequest.OldIpAddress) == 0 || len(ReplaceRequest.NewIpAddress) == 0 {
+		success = false
+		statusMsg = "Old and New IP Address of the device are required"
+		ErrorModel := newErrorModel(http.StatusBadRequest, statusMsg)
+		ErrorModel.ExecutionId = alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	ret, err := infra.GetUseCaseInteractor().ReplaceDevice(ctx, ReplaceRequest.FabricName, ReplaceRequest.OldIpAddress,
+		ReplaceRequest.NewIpAddress, ReplaceRequest.Username, ReplaceRequest.Password)
+	statusMsg = ret
+	if err != nil {
+		success = false
+		ErrorModel := errorModelOf(err, http.StatusInternalServerError, ret)
+		ErrorModel.Device, ErrorModel.ExecutionId = ReplaceRequest.OldIpAddress, alog.ReqID
+		writeErrorModel(w, ErrorModel)
+		return
+	}
+
+	OpenAPIResp := Restmodel.DeviceReplaceResponse{
+		FabricName:   ReplaceRequest.FabricName,
+		OldIpAddress: ReplaceRequest.OldIpAddress,
+		NewIpAddress: ReplaceRequest.NewIpAddress,
+		Message:      ret,
+	}
+	bytess, _ := json.Marshal(&OpenAPIResp)
+	w.Write(bytess)
+}
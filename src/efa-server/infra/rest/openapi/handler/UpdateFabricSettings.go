@@ -26,20 +26,115 @@ func UpdateFabricSettings(w http.ResponseWriter, r *http.Request) {
 	statusMsg := ""
 
 	var FabricSettings Restmodel.FabricSettings
-	var FabricUpdate domain.FabricProperties
 
 	alog := logging.AuditLog{Request: &logging.Request{Command: "Update Fabric Settings"}}
-	ctx := alog.LogMessageInit()
+	ctx := withRequestUser(alog.LogMessageInit(), r)
 	defer alog.LogMessageEnd(&success, &statusMsg)
 
 	b, _ := ioutil.ReadAll(r.Body)
 	err := json.Unmarshal(b, &FabricSettings)
 	if err != nil {
 		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
 		return
 	}
-	errMap := make(map[string]string, 0)
 	FabricName := FabricSettings.Name
+	FabricUpdate, errMap := parseFabricSettings(FabricSettings)
+	alog.LogMessageReceived()
+
+	//update Request object after all parameters are received
+	alog.Request.Params = map[string]interface{}{
+		"FabricName":                    FabricName,
+		"P2PLinkRange":                  FabricUpdate.P2PLinkRange,
+		"P2PIPType":                     FabricUpdate.P2PIPType,
+		"LoopBackIPRange":               FabricUpdate.LoopBackIPRange,
+		"MCTLinkIPRange":                FabricUpdate.MCTLinkIPRange,
+		"MCTL3LBIPRange":                FabricUpdate.MCTL3LBIPRange,
+		"LoopBackPortNumber":            FabricUpdate.LoopBackPortNumber,
+		"LeafAsnBlock":                  FabricUpdate.LeafASNBlock,
+		"SpineAsnBlock":                 FabricUpdate.SpineASNBlock,
+		"VTEPLoopBackPortNumber":        FabricUpdate.VTEPLoopBackPortNumber,
+		"AnyCastMac":                    FabricUpdate.AnyCastMac,
+		"IPV6AnyCastMac":                FabricUpdate.IPV6AnyCastMac,
+		"ConfigureOverlayGateway":       FabricUpdate.ConfigureOverlayGateway,
+		"VNIAutoMap":                    FabricUpdate.VNIAutoMap,
+		"BFDEnable":                     FabricUpdate.BFDEnable,
+		"BFDTx":                         FabricUpdate.BFDTx,
+		"BFDRx":                         FabricUpdate.BFDRx,
+		"BFDMultiplier":                 FabricUpdate.BFDMultiplier,
+		"BGPMultiHop":                   FabricUpdate.BGPMultiHop,
+		"MaxPaths":                      FabricUpdate.MaxPaths,
+		"AllowASIn":                     FabricUpdate.AllowASIn,
+		"MTU":                           FabricUpdate.MTU,
+		"IPMTU":                         FabricUpdate.IPMTU,
+		"LeafPeerGroup":                 FabricUpdate.LeafPeerGroup,
+		"SpinePeerGroup":                FabricUpdate.SpinePeerGroup,
+		"ArpAgingTimeout":               FabricUpdate.ArpAgingTimeout,
+		"MacAgingTimeout":               FabricUpdate.MacAgingTimeout,
+		"MacAgingConversationaltimeout": FabricUpdate.MacAgingConversationalTimeout,
+		"MacMoveLimit":                  FabricUpdate.MacMoveLimit,
+		"DuplicateMacTimer":             FabricUpdate.DuplicateMacTimer,
+		"DuplicateMaxTimerMaxCount":     FabricUpdate.DuplicateMaxTimerMaxCount,
+		"ControlVlan":                   FabricUpdate.ControlVlan,
+		"ControlVE":                     FabricUpdate.ControlVE,
+		"MctPortChannel":                FabricUpdate.MctPortChannel,
+		"RoutingMctPortChannel":         FabricUpdate.RoutingMctPortChannel,
+		"FabricType":                    FabricUpdate.FabricType,
+		"RackPeerEBGPGroup":             FabricUpdate.RackPeerEBGPGroup,
+		"RackPeerOvgGroup":              FabricUpdate.RackPeerOvgGroup,
+		"BGPAuthType":                   FabricUpdate.BGPAuthType,
+		"PeerGroupPassword":             maskPassword(FabricUpdate.PeerGroupPassword),
+		"MctL2EvpnPassword":             maskPassword(FabricUpdate.MctL2EvpnPassword),
+		"RackPeerEBGPGroupPassword":     maskPassword(FabricUpdate.RackPeerEBGPGroupPassword),
+		"RackPeerOvgGroupPassword":      maskPassword(FabricUpdate.RackPeerOvgGroupPassword),
+		"PoolWarningThreshold":          FabricUpdate.PoolWarningThreshold,
+	}
+
+	alog.LogMessageReceived()
+	if len(errMap) > 0 {
+		success = false
+		statusMsg = "Fabric Update Invalid Parameter"
+		writeSettingErrors(w, statusMsg, errMap, alog.ReqID)
+		return
+	}
+
+	errMap = ValidateFabricProperties(FabricName, &FabricUpdate)
+	if len(errMap) != 0 {
+		success = false
+		statusMsg = "Fabric Update Parameter Validation Failed"
+		writeSettingErrors(w, statusMsg, errMap, alog.ReqID)
+		return
+	}
+
+	UseCaseInteractor := infra.GetUseCaseInteractor()
+	ret, FabricID, err := UseCaseInteractor.UpdateFabricProperties(ctx, FabricName, &FabricUpdate)
+
+	//Indicating there is an overall Failure
+	if err != nil {
+		success = false
+		statusMsg = ret
+		writeUseCaseError(w, err, ret, alog.ReqID)
+	} else {
+		//Send  Fabric config Show Response
+		success = true
+		statusMsg = fmt.Sprint("Fabric Update Succeeded.")
+		OpenAPIResp := Restmodel.FabricdataResponse{
+			FabricName: FabricName,
+			FabricId:   int32(FabricID),
+		}
+		//Write Success Structure to the Body
+		bytess, _ := json.Marshal(&OpenAPIResp)
+		w.Write(bytess)
+	}
+
+}
+
+//parseFabricSettings maps the keys of the settings update to the fabric settings, unknown keys are returned as errors
+func parseFabricSettings(FabricSettings Restmodel.FabricSettings) (domain.FabricProperties, map[string]string) {
+	var FabricUpdate domain.FabricProperties
+	errMap := make(map[string]string, 0)
 	for _, FabricParameter := range FabricSettings.Keyval {
 		switch FabricParameter.Key {
 		case "ConfigureOverlayGateway":
@@ -127,107 +222,23 @@ func UpdateFabricSettings(w http.ResponseWriter, r *http.Request) {
 			FabricUpdate.RackPeerEBGPGroup = FabricParameter.Value
 		case "RackPeerOvgGroup":
 			FabricUpdate.RackPeerOvgGroup = FabricParameter.Value
+		case "BGPAuthType":
+			FabricUpdate.BGPAuthType = FabricParameter.Value
+		case "PeerGroupPassword":
+			FabricUpdate.PeerGroupPassword = FabricParameter.Value
+		case "MctL2EvpnPassword":
+			FabricUpdate.MctL2EvpnPassword = FabricParameter.Value
+		case "RackPeerEBGPGroupPassword":
+			FabricUpdate.RackPeerEBGPGroupPassword = FabricParameter.Value
+		case "RackPeerOvgGroupPassword":
+			FabricUpdate.RackPeerOvgGroupPassword = FabricParameter.Value
+		case "PoolWarningThreshold":
+			FabricUpdate.PoolWarningThreshold = FabricParameter.Value
 		default:
 			errMap[FabricParameter.Key] = fmt.Sprintf("Invalid Parameter: %s", FabricParameter.Key)
 		}
 	}
-	alog.LogMessageReceived()
-
-	//update Request object after all parameters are received
-	alog.Request.Params = map[string]interface{}{
-		"FabricName":                    FabricName,
-		"P2PLinkRange":                  FabricUpdate.P2PLinkRange,
-		"P2PIPType":                     FabricUpdate.P2PIPType,
-		"LoopBackIPRange":               FabricUpdate.LoopBackIPRange,
-		"MCTLinkIPRange":                FabricUpdate.MCTLinkIPRange,
-		"MCTL3LBIPRange":                FabricUpdate.MCTL3LBIPRange,
-		"LoopBackPortNumber":            FabricUpdate.LoopBackPortNumber,
-		"LeafAsnBlock":                  FabricUpdate.LeafASNBlock,
-		"SpineAsnBlock":                 FabricUpdate.SpineASNBlock,
-		"VTEPLoopBackPortNumber":        FabricUpdate.VTEPLoopBackPortNumber,
-		"AnyCastMac":                    FabricUpdate.AnyCastMac,
-		"IPV6AnyCastMac":                FabricUpdate.IPV6AnyCastMac,
-		"ConfigureOverlayGateway":       FabricUpdate.ConfigureOverlayGateway,
-		"VNIAutoMap":                    FabricUpdate.VNIAutoMap,
-		"BFDEnable":                     FabricUpdate.BFDEnable,
-		"BFDTx":                         FabricUpdate.BFDTx,
-		"BFDRx":                         FabricUpdate.BFDRx,
-		"BFDMultiplier":                 FabricUpdate.BFDMultiplier,
-		"BGPMultiHop":                   FabricUpdate.BGPMultiHop,
-		"MaxPaths":                      FabricUpdate.MaxPaths,
-		"AllowASIn":                     FabricUpdate.AllowASIn,
-		"MTU":                           FabricUpdate.MTU,
-		"IPMTU":                         FabricUpdate.IPMTU,
-		"LeafPeerGroup":                 FabricUpdate.LeafPeerGroup,
-		"SpinePeerGroup":                FabricUpdate.SpinePeerGroup,
-		"ArpAgingTimeout":               FabricUpdate.ArpAgingTimeout,
-		"MacAgingTimeout":               FabricUpdate.MacAgingTimeout,
-		"MacAgingConversationaltimeout": FabricUpdate.MacAgingConversationalTimeout,
-		"MacMoveLimit":                  FabricUpdate.MacMoveLimit,
-		"DuplicateMacTimer":             FabricUpdate.DuplicateMacTimer,
-		"DuplicateMaxTimerMaxCount":     FabricUpdate.DuplicateMaxTimerMaxCount,
-		"ControlVlan":                   FabricUpdate.ControlVlan,
-		"ControlVE":                     FabricUpdate.ControlVE,
-		"MctPortChannel":                FabricUpdate.MctPortChannel,
-		"RoutingMctPortChannel":         FabricUpdate.RoutingMctPortChannel,
-		"FabricType":                    FabricUpdate.FabricType,
-		"RackPeerEBGPGroup":             FabricUpdate.RackPeerEBGPGroup,
-		"RackPeerOvgGroup":              FabricUpdate.RackPeerOvgGroup,
-	}
-
-	alog.LogMessageReceived()
-	if len(errMap) > 0 {
-		fmt.Println("Fabric Update  Invalild Parameter")
-		http.Error(w, "", http.StatusBadRequest)
-		OpenAPIError := Restmodel.FabricdataErrorResponse{FabricName: FabricName, FabricSettings: errMap}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
-		return
-	}
-
-	errMap = ValidateFabricProperties(FabricName, &FabricUpdate)
-	if len(errMap) != 0 {
-		fmt.Println("Fabric Update Parameter Validation Failed")
-		//ret, _ := json.MarshalIndent(errMap, "", "\n")
-		http.Error(w, "", http.StatusBadRequest)
-		OpenAPIError := Restmodel.FabricdataErrorResponse{FabricName: FabricName, FabricSettings: errMap}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
-		return
-	}
-
-	UseCaseInteractor := infra.GetUseCaseInteractor()
-	ret, FabricID, err := UseCaseInteractor.UpdateFabricProperties(ctx, FabricName, &FabricUpdate)
-
-	//Indicating there is an overall Failure
-	if err != nil {
-		switch err {
-		case domain.ErrFabricActive:
-			http.Error(w, "", http.StatusConflict)
-		case domain.ErrFabricNotFound:
-			http.Error(w, "", http.StatusNotFound)
-		case domain.ErrFabricIncorrectValues:
-			http.Error(w, "", http.StatusBadRequest)
-		default:
-			http.Error(w, "", http.StatusInternalServerError)
-		}
-		errMap[err.Error()] = ret
-		OpenAPIError := Restmodel.FabricdataErrorResponse{FabricName: FabricName, FabricSettings: errMap}
-		bytess, _ := json.Marshal(&OpenAPIError)
-		w.Write(bytess)
-	} else {
-		//Send  Fabric config Show Response
-		success = true
-		statusMsg = fmt.Sprint("Fabric Update Succeeded.")
-		OpenAPIResp := Restmodel.FabricdataResponse{
-			FabricName: FabricName,
-			FabricId:   int32(FabricID),
-		}
-		//Write Success Structure to the Body
-		bytess, _ := json.Marshal(&OpenAPIResp)
-		w.Write(bytess)
-	}
-
+	return FabricUpdate, errMap
 }
 
 func transformYesAndNo(data string) string {
@@ -285,6 +296,10 @@ func ValidateFabricProperties(FabricName string, FabricUpdateRequest *domain.Fab
 	if e != nil {
 		err["ip-mtu"] = e.Error()
 	}
+	_, e = validatePoolWarningThreshold(FabricUpdateRequest.PoolWarningThreshold)
+	if e != nil {
+		err["pool-warning-threshold"] = e.Error()
+	}
 
 	_, e = validateASN(FabricUpdateRequest.LeafASNBlock)
 	if e != nil {
@@ -455,10 +470,45 @@ func ValidateFabricProperties(FabricName string, FabricUpdateRequest *domain.Fab
 		err["leaf-peer-overlay-evpn-group"] = e.Error()
 	}
 
+	for key, e := range validateBGPAuthentication(FabricUpdateRequest) {
+		err[key] = e
+	}
+
 	//TODO CALL VALIDATION FOR DuplicateMacTimer and DuplicateMaxTimerMaxCount
 	return err
 }
 
+//validateBGPAuthentication validates the BGP authentication type and the peer group passwords
+func validateBGPAuthentication(FabricUpdateRequest *domain.FabricProperties) map[string]string {
+	err := make(map[string]string)
+
+	FabricUpdateRequest.BGPAuthType = cleanupString(FabricUpdateRequest.BGPAuthType)
+	if _, e := validateBGPAuthType(FabricUpdateRequest.BGPAuthType); e != nil {
+		err["bgp-auth-type"] = e.Error()
+	}
+	if _, e := validateBGPPassword(FabricUpdateRequest.PeerGroupPassword); e != nil {
+		err["peer-group-password"] = e.Error()
+	}
+	if _, e := validateBGPPassword(FabricUpdateRequest.MctL2EvpnPassword); e != nil {
+		err["mct-l2evpn-password"] = e.Error()
+	}
+	if _, e := validateBGPPassword(FabricUpdateRequest.RackPeerEBGPGroupPassword); e != nil {
+		err["rack-peer-ebgp-group-password"] = e.Error()
+	}
+	if _, e := validateBGPPassword(FabricUpdateRequest.RackPeerOvgGroupPassword); e != nil {
+		err["rack-peer-overlay-evpn-group-password"] = e.Error()
+	}
+	return err
+}
+
+//maskPassword hides the password from the audit logs and the REST responses
+func maskPassword(Password string) string {
+	if len(Password) == 0 || Password == domain.BGPPasswordNone {
+		return Password
+	}
+	return "******"
+}
+
 func isValidPeerGroupName(str string) bool {
 	if len(str) > 63 {
 		return false
@@ -571,6 +621,17 @@ func validateIPMTU(IPMTU string) (bool, error) {
 	return true, nil
 }
 
+func validatePoolWarningThreshold(Threshold string) (bool, error) {
+	if len(Threshold) == 0 {
+		return true, nil
+	}
+	if !isValidRange(Threshold, 1, 100) {
+		ret := fmt.Sprintf("%s is not Valid Pool Warning Threshold , Valid Pool Warning Threshold is 1-100", Threshold)
+		return false, errors.New(ret)
+	}
+	return true, nil
+}
+
 func getASNMinMax(asnRange string) (asnMin, asnMax uint64) {
 	asn := strings.Split(asnRange, "-")
 	asnMin, _ = strconv.ParseUint(asn[0], 10, 64)
@@ -883,6 +944,28 @@ func validatePeerGroupName(Peer string) (bool, error) {
 	return false, errors.New(ret)
 }
 
+func validateBGPAuthType(AuthType string) (bool, error) {
+	if len(AuthType) == 0 {
+		return true, nil
+	}
+	if AuthType == domain.BGPAuthTypeMD5 {
+		return true, nil
+	}
+	ret := fmt.Sprintf("%s is not Valid BGP authentication type, Valid bgp-auth-type is <md5>", AuthType)
+	return false, errors.New(ret)
+}
+
+func validateBGPPassword(Password string) (bool, error) {
+	if len(Password) == 0 {
+		return true, nil
+	}
+	//password is sent as a single token to the switch
+	if len(Password) > 63 || strings.ContainsAny(Password, " \t\n") {
+		return false, errors.New("BGP password is not Valid, Valid BGP password is <WORD: 1-63> without spaces")
+	}
+	return true, nil
+}
+
 func validateFabricType(FabricType string) (bool, error) {
 	if len(FabricType) == 0 {
 		return true, nil
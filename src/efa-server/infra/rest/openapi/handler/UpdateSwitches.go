@@ -28,6 +28,9 @@ func UpdateSwitches(w http.ResponseWriter, r *http.Request) {
 	err := json.Unmarshal(b, &UpdateSwitchParams)
 	if err != nil {
 		success = false
+		statusMsg = err.Error()
+		alog.LogMessageReceived()
+		writeRequestBodyError(w, err, alog.ReqID)
 		return
 	}
 	//update Request object after all parameters are received
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"github.com/gorilla/mux"
 	"net/http"
+	"strings"
 )
 
 //ValidateFabric is a REST handler to handle
@@ -27,10 +28,18 @@ func ValidateFabric(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	FabricName := vars["fabric_name"]
+	//preflight_override is optional, a comma separated list of the pre-flight checks whose errors are overridden
+	Overrides := make([]string, 0)
+	for _, Check := range strings.Split(r.URL.Query().Get("preflight_override"), ",") {
+		if Check = strings.TrimSpace(Check); len(Check) != 0 {
+			Overrides = append(Overrides, Check)
+		}
+	}
 
 	//update Request object after all parameters are received
 	alog.Request.Params = map[string]interface{}{
-		"FabricName": FabricName,
+		"FabricName":        FabricName,
+		"PreflightOverride": Overrides,
 	}
 	alog.LogMessageReceived()
 
@@ -42,17 +51,26 @@ func ValidateFabric(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx = context.WithValue(ctx, appcontext.FabricType, fabricType)
 
-	ValidateResponse, err := infra.GetUseCaseInteractor().ValidateFabricTopology(ctx, FabricName)
+	ValidateResponse, err := infra.GetUseCaseInteractor().ValidateFabric(ctx, FabricName, Overrides)
 	//Indicating there is generic Failure
 	if err != nil {
 		success = false
-		http.Error(w, statusMsg,
-			http.StatusInternalServerError)
+		statusMsg = err.Error()
+		writeUseCaseError(w, err, "", alog.ReqID)
+		return
 	}
 	//Send Fabric Validate Response
 	OpenAPIResp := swagger.FabricValidateResponse{FabricName: ValidateResponse.FabricName, MissingLinks: ValidateResponse.MissingLinks,
 		MissingLeaves: ValidateResponse.NoLeaves, MissingSpines: ValidateResponse.NoSpines, SpineSpineLinks: ValidateResponse.SpineSpineLinks,
-		LeafLeafLinks: ValidateResponse.LeafLeafLinks}
+		LeafLeafLinks: ValidateResponse.LeafLeafLinks, PoolWarnings: ValidateResponse.PoolWarnings,
+		PinConflicts: ValidateResponse.PinConflicts, MiscabledLinks: ValidateResponse.Cabling.Miscabled,
+		MissingPlannedLinks: ValidateResponse.Cabling.Missing, UnexpectedLinks: ValidateResponse.Cabling.Unexpected,
+		PreflightFindings: make([]swagger.PreflightFinding, 0, len(ValidateResponse.Preflight))}
+	for _, Finding := range ValidateResponse.Preflight {
+		OpenAPIResp.PreflightFindings = append(OpenAPIResp.PreflightFindings, swagger.PreflightFinding{
+			Check: Finding.Check, Severity: Finding.Severity, Device: Finding.Device, Interface: Finding.Interface,
+			Message: Finding.Message, Overridden: Finding.Overridden})
+	}
 	bytess, _ := json.Marshal(&OpenAPIResp)
 
 	//Set the status Messages so that it is audit logged
@@ -9,6 +9,7 @@ import (
 	"efa-server/infra/constants"
 	"efa-server/test/functional"
 	_ "efa-server/test/functional"
+	"efa-server/test/functional/simulator"
 	"efa-server/test/testutils"
 	"strings"
 )
@@ -28,6 +29,9 @@ var Leaf2IP = functional.IntegrationTestLeaf2IP
 func TestMain(m *testing.M) {
 	testutils.TestMutex.Lock()
 	defer testutils.TestMutex.Unlock()
+	//With SIMULATOR set to 1, the efa CLI reaches the simulated switches through an efa-server run by the tests
+	stopLab := simulator.StartLabServer()
+	defer stopLab()
 	func() {
 		c1 := testcli.Command(constants.ApplicationName, "fabric", "setting", "update", "--fabric-type", "clos")
 		c1.Run()
@@ -0,0 +1,15 @@
+package apiintegration
+
+import (
+	"efa-server/test/functional/simulator"
+	"os"
+	"testing"
+)
+
+//TestMain runs the tests against the simulated switches when SIMULATOR is set to 1
+func TestMain(m *testing.M) {
+	stopLab := simulator.StartLab()
+	code := m.Run()
+	stopLab()
+	os.Exit(code)
+}
@@ -9,6 +9,7 @@ import (
 	"efa-server/infra/constants"
 	"efa-server/test/functional"
 	_ "efa-server/test/functional"
+	"efa-server/test/functional/simulator"
 	"efa-server/test/testutils"
 )
 
@@ -29,6 +30,9 @@ var Rack5IP2 = "2.2.2.2"
 func TestMain(m *testing.M) {
 	testutils.TestMutex.Lock()
 	defer testutils.TestMutex.Unlock()
+	//With SIMULATOR set to 1, the efa CLI reaches the simulated switches through an efa-server run by the tests
+	stopLab := simulator.StartLabServer()
+	defer stopLab()
 	func() {
 
 		c := testcli.Command(constants.ApplicationName, "debug", "clear-config", "--device",
@@ -96,7 +100,8 @@ func TNonClosConfigure5Racks(t *testing.T) {
 	c.Run()
 	output := c.Stderr()
 
-	assert.Nil(t, c.Error())
+	//The rejected command exits with a failure
+	assert.Error(t, c.Error())
 	assert.Contains(t, output, "Error: Only 4 Rack Pairs are supported")
 }
 
@@ -112,7 +117,8 @@ func TNonClosDeconfigure5Racks(t *testing.T) {
 	c.Run()
 	output := c.Stderr()
 
-	assert.Nil(t, c.Error())
+	//The rejected command exits with a failure
+	assert.Error(t, c.Error())
 	assert.Contains(t, output, "Error: Only 4 Rack Pairs are supported")
 }
 
@@ -15,6 +15,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"text/template"
@@ -56,6 +57,11 @@ var MCTNode1PeerIP2 = "21.0.1.177"
 var MCTNode2PeerIP = "21.0.0.136"
 var MCTNode2PeerIP2 = "21.0.1.136"
 
+//The Avalanche devices peer on the loopback of the other member, over the update-source loopback
+var MCTNode1PeerLoopbackIP = "21.0.2.136"
+var MCTNode2PeerLoopbackIP = "21.0.2.177"
+var MCTLoopbackNumber = "1"
+
 var MCTNode1RemotePeerIP = "21.0.0.136/24"
 var MCTNode1RemotePeerIP2 = "21.0.1.136/24"
 var MCTNode2RemotePeerIP = "21.0.0.177/24"
@@ -123,6 +129,8 @@ type ClusterMember struct {
 	LocalASN           string
 	NodePeerASN        string
 	NodePeerIP         string
+	NodePeerLoopbackIP string
+	NodeLoopBackNumber string
 	NodePeerEncapType  string
 	NodePeerBFDEnabled string
 	//Client                    *netconf.NetconfClient
@@ -149,7 +157,8 @@ func configureDataPlaneCluster(t *testing.T, c *TestConfigCluster) {
 	cluster.DataPlaneClusterMemberNodes = make([]operation.DataPlaneClusterMemberNode, 0)
 	for _, m := range c.ClusterMembers {
 		mctMember := operation.DataPlaneClusterMemberNode{NodeMgmtIP: m.Host, NodeMgmtUserName: m.UserName, NodeModel: m.Model, NodeMgmtPassword: m.Password,
-			NodePeerIP: m.NodePeerIP, NodePeerASN: m.NodePeerASN, NodePeerEncapType: m.NodePeerEncapType, NodePeerBFDEnabled: m.NodePeerBFDEnabled}
+			NodePeerIP: m.NodePeerIP, NodePeerLoopbackIP: m.NodePeerLoopbackIP, NodeLoopBackNumber: m.NodeLoopBackNumber,
+			NodePeerASN: m.NodePeerASN, NodePeerEncapType: m.NodePeerEncapType, NodePeerBFDEnabled: m.NodePeerBFDEnabled}
 		cluster.DataPlaneClusterMemberNodes = append(cluster.DataPlaneClusterMemberNodes, mctMember)
 	}
 
@@ -173,17 +182,17 @@ func configureDataPlaneCluster(t *testing.T, c *TestConfigCluster) {
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(bgpResponse.Neighbors))
 		if 1 == len(bgpResponse.Neighbors) {
-			assert.Equal(t, member.NodePeerIP, bgpResponse.Neighbors[0].RemoteIP)
+			assert.Equal(t, peerAddress(member), bgpResponse.Neighbors[0].RemoteIP)
 			assert.Equal(t, member.NodePeerASN, bgpResponse.Neighbors[0].RemoteAS)
 		}
 
 		assert.NotNil(t, bgpResponse.L2VPN)
 		assert.Equal(t, 1, len(bgpResponse.L2VPN.Neighbors))
 		if 1 == len(bgpResponse.L2VPN.Neighbors) {
-			assert.Equal(t, member.NodePeerIP, bgpResponse.L2VPN.Neighbors[0].IPAddress)
-			if member.Model == adapter.AvalancheType {
+			assert.Equal(t, peerAddress(member), bgpResponse.L2VPN.Neighbors[0].IPAddress)
+			if modelType(member.Model) == adapter.AvalancheType {
 				assert.Equal(t, slx.BGPEncapTypeForRoutingAvalanche, bgpResponse.L2VPN.Neighbors[0].Encapsulation)
-			} else if member.Model == adapter.OrcaType || member.Model == adapter.OrcaTType {
+			} else if modelType(member.Model) == adapter.OrcaType || modelType(member.Model) == adapter.OrcaTType {
 				assert.Equal(t, slx.BGPEncapTypeForRoutingOrca, bgpResponse.L2VPN.Neighbors[0].Encapsulation)
 			}
 			assert.Equal(t, "true", bgpResponse.L2VPN.Neighbors[0].Activate)
@@ -191,6 +200,20 @@ func configureDataPlaneCluster(t *testing.T, c *TestConfigCluster) {
 	}
 }
 
+//modelType returns the type of the model of the device detail, which is followed by the firmware version
+func modelType(Model string) string {
+	return strings.Split(Model, "_")[0]
+}
+
+//peerAddress returns the address of the MCT BGP neighbor of the member, the loopback of the other member for
+//the Avalanche devices
+func peerAddress(m *ClusterMember) string {
+	if modelType(m.Model) == adapter.AvalancheType {
+		return m.NodePeerLoopbackIP
+	}
+	return m.NodePeerIP
+}
+
 func configureEvpn(t *testing.T, wg *sync.WaitGroup, sw operation.ConfigSwitch, client *netconf.NetconfClient) {
 	ctx, fabricGate, fabricErrors, Errors := initializeTestWithoutNetconfClient(MCTClusterMemberCount)
 	assert.Empty(t, Errors)
@@ -364,7 +387,7 @@ func configureManagementCluster(t *testing.T, c *TestConfigCluster) {
 	for _, m := range c.ClusterMembers {
 		clusterMember := operation.ClusterMemberNode{NodeMgmtIP: m.Host, NodeMgmtUserName: m.UserName,
 			NodeMgmtPassword: m.Password, NodeID: m.NodeID, NodeModel: m.Model,
-			NodePeerIP: m.NodePeerIP, RemoteNodePeerIP: m.RemoteNodePeerIP,
+			NodePeerIP: m.NodePeerIP, NodePeerLoopbackIP: m.NodePeerLoopbackIP, RemoteNodePeerIP: m.RemoteNodePeerIP,
 			NodePeerIntfType: m.NodePeerIfType, NodePeerIntfName: m.NodePeerIfName,
 			NodePeerIntfSpeed: m.NodePeerIfSpeed, NodePrincipalPriority: m.NodePrincipalPriority}
 		clusterMember.RemoteNodeConnectingPorts = m.RemoteNodeConnectingPorts
@@ -412,11 +435,13 @@ func configureClusterMop(MCTNode1Ip string, Model string, MCTNode2Ip string, t *
 		ClusterName: MCTClusterName, ClusterID: MCTClusterID, ClusterControlVlan: MCTClusterControlVlan, ClusterControlVe: MCTClusterControlVe}
 	configCluster.ClusterMembers = make([]*ClusterMember, 0)
 	member1 := &ClusterMember{Host: MCTNode1Ip, UserName: UserName, Password: Password, Model: detail.Model,
-		LocalASN: MCTBgpASN, NodePeerASN: MCTBgpASN, NodePeerIP: MCTNode1PeerIP, NodePeerEncapType: domain.BGPEncapTypeForCluster,
+		LocalASN: MCTBgpASN, NodePeerASN: MCTBgpASN, NodePeerIP: MCTNode1PeerIP, NodePeerLoopbackIP: MCTNode1PeerLoopbackIP,
+		NodeLoopBackNumber: MCTLoopbackNumber, NodePeerEncapType: domain.BGPEncapTypeForCluster,
 		NodePeerBFDEnabled: "false", NodeID: MCTNode1Id, RemoteNodePeerIP: MCTNode1RemotePeerIP, NodePeerIfType: MCTPeerIntfType,
 		NodePeerIfName: MCTPeerIntfName, NodePeerIfSpeed: MCTPeerIntfSpeed, NodePrincipalPriority: "1"}
 	member2 := &ClusterMember{Host: MCTNode2Ip, UserName: UserName, Password: Password, Model: detail.Model,
-		LocalASN: MCTBgpASN, NodePeerASN: MCTBgpASN, NodePeerIP: MCTNode2PeerIP, NodePeerEncapType: domain.BGPEncapTypeForCluster,
+		LocalASN: MCTBgpASN, NodePeerASN: MCTBgpASN, NodePeerIP: MCTNode2PeerIP, NodePeerLoopbackIP: MCTNode2PeerLoopbackIP,
+		NodeLoopBackNumber: MCTLoopbackNumber, NodePeerEncapType: domain.BGPEncapTypeForCluster,
 		NodePeerBFDEnabled: "false", NodeID: MCTNode2Id, RemoteNodePeerIP: MCTNode2RemotePeerIP, NodePeerIfType: MCTPeerIntfType,
 		NodePeerIfName: MCTPeerIntfName, NodePeerIfSpeed: MCTPeerIntfSpeed, NodePrincipalPriority: "1"}
 	configCluster.ClusterMembers = append(configCluster.ClusterMembers, member1)
@@ -7,6 +7,7 @@ import (
 	"efa-server/infra/device/adapter"
 	netconf "efa-server/infra/device/client"
 	"efa-server/test/functional"
+	"efa-server/test/functional/simulator"
 	"github.com/rifflock/lfshook"
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
@@ -54,6 +55,7 @@ func init() {
 
 func TestMain(m *testing.M) {
 	//fmt.Println("starting")
+	stopLab := simulator.StartLab()
 	//Iniitalize the client Once
 	client = &netconf.NetconfClient{Host: Host, User: UserName, Password: Password}
 	client.Login()
@@ -61,6 +63,7 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 	//fmt.Println("Stopping")
 	client.Close()
+	stopLab()
 	os.Exit(code)
 }
 
@@ -7,6 +7,7 @@ import (
 	"efa-server/infra/device/adapter"
 	netconf "efa-server/infra/device/client"
 	"efa-server/test/functional"
+	"efa-server/test/functional/simulator"
 	"github.com/rifflock/lfshook"
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
@@ -56,6 +57,7 @@ func init() {
 
 func TestMain(m *testing.M) {
 	//fmt.Println("starting")
+	stopLab := simulator.StartLab()
 	//Iniitalize the client Once
 	client = &netconf.NetconfClient{Host: Host, User: UserName, Password: Password}
 	client.Login()
@@ -63,6 +65,7 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 	//fmt.Println("Stopping")
 	client.Close()
+	stopLab()
 	os.Exit(code)
 }
 
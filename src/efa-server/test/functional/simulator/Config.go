@@ -0,0 +1,269 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+//listKeys are the leaves identifying the entries of the lists edited by the adapters. An element whose name
+//is not listed, or which has none of its keys, is a container matched on its name.
+var listKeys = map[string][]string{
+	"ethernet":                   {"name"},
+	"port-channel":               {"name"},
+	"ve":                         {"name"},
+	"vlan":                       {"name"},
+	"overlay-gateway":            {"name"},
+	"loopback":                   {"id"},
+	"address":                    {"address"},
+	"prefix-list":                {"name", "seq-keyword", "instance"},
+	"route-map":                  {"name", "action-rm", "instance"},
+	"cluster":                    {"cluster-name"},
+	"peer":                       {"peer-ip"},
+	"evpn-instance":              {"instance-name"},
+	"neighbor-addr":              {"router-bgp-neighbor-address"},
+	"neighbor-peer-grp":          {"router-bgp-neighbor-peer-grp"},
+	"evpn-neighbor-ipv4":         {"evpn-neighbor-ipv4-address"},
+	"evpn-peer-group":            {"evpn-neighbor-peergroup-name"},
+	"af-ipv4-neighbor-address":   {"af-ipv4-neighbor-address"},
+	"af-ipv4-neighbor-peergroup": {"af-ipv4-neighbor-peergroup-name"},
+	"network":                    {"network-ipv4-address"},
+	"static-route-nh":            {"static-route-dest", "static-route-next-hop"},
+}
+
+//fixedLeaves are the leaves the switches refuse to change once set, the edit-config has to remove them first
+var fixedLeaves = map[string]bool{
+	"ip-anycast-gateway-mac":   true,
+	"ipv6-anycast-gateway-mac": true,
+}
+
+//errMalformedConfig is returned for an edit-config whose payload is not a config element
+var errMalformedConfig = errors.New("malformed config")
+
+//editConfig merges the config element of an edit-config into the running-config. The elements carrying
+//an operation attribute of remove or delete are removed with their descendants, removing an absent
+//element is not an error. An edit changing a fixed leaf or sending an empty list key fails leaving the
+//running-config unchanged.
+func editConfig(Running *etree.Document, Config *etree.Element) error {
+	if Config == nil || Config.Tag != "config" {
+		return errMalformedConfig
+	}
+	if err := checkKeys(Config); err != nil {
+		return err
+	}
+	if err := checkEdit(&Running.Element, Config); err != nil {
+		return err
+	}
+	mergeElement(&Running.Element, Config)
+	return nil
+}
+
+//checkKeys returns an error when a list entry of the edit has an empty key, the switches reject such an
+//entry instead of creating it
+func checkKeys(Edit *etree.Element) error {
+	for _, Child := range Edit.ChildElements() {
+		for _, Key := range listKeys[Child.Tag] {
+			if KeyElement := Child.SelectElement(Key); KeyElement != nil && strings.TrimSpace(KeyElement.Text()) == "" {
+				return fmt.Errorf("%%Error: %s %s is invalid", Child.Tag, Key)
+			}
+		}
+		if err := checkKeys(Child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//checkEdit returns an error when the edit changes a fixed leaf of the running-config
+func checkEdit(Target *etree.Element, Edit *etree.Element) error {
+	for _, Child := range Edit.ChildElements() {
+		Match := matchingChild(Target, Child)
+		Operation := strings.TrimSpace(Child.SelectAttrValue("operation", ""))
+		if Match == nil || Operation == "remove" || Operation == "delete" {
+			continue
+		}
+		if len(Child.ChildElements()) != 0 {
+			if err := checkEdit(Match, Child); err != nil {
+				return err
+			}
+			continue
+		}
+		Set, Edited := strings.TrimSpace(Match.Text()), strings.TrimSpace(Child.Text())
+		if fixedLeaves[Child.Tag] && Set != "" && Set != Edited {
+			return fmt.Errorf("%%Error: %s %s is already configured", Child.Tag, Set)
+		}
+	}
+	return nil
+}
+
+func mergeElement(Target *etree.Element, Edit *etree.Element) {
+	for _, Child := range Edit.ChildElements() {
+		Match := matchingChild(Target, Child)
+		switch Operation := strings.TrimSpace(Child.SelectAttrValue("operation", "")); {
+		case Operation == "remove" || Operation == "delete":
+			if Match != nil {
+				Target.RemoveChild(Match)
+			}
+		case len(Child.ChildElements()) == 0:
+			if Match == nil {
+				Match = createChild(Target, Child)
+			}
+			Match.SetText(Child.Text())
+		default:
+			if Match == nil {
+				Match = createChild(Target, Child)
+			}
+			mergeElement(Match, Child)
+		}
+	}
+}
+
+//createChild creates the edited element in the running-config with its attributes. The entries of a list are
+//inserted in the order of their keys, the order in which the switches return them.
+func createChild(Parent *etree.Element, Edit *etree.Element) *etree.Element {
+	Child := etree.NewElement(Edit.Tag)
+	copyAttributes(Child, Edit)
+	var Next *etree.Element
+	if len(listKeys[Edit.Tag]) != 0 {
+		for _, Sibling := range Parent.SelectElements(Edit.Tag) {
+			if compareKeys(Edit, Sibling) < 0 {
+				Next = Sibling
+				break
+			}
+		}
+	}
+	Parent.InsertChild(Next, Child)
+	return Child
+}
+
+//compareKeys compares the keys of two entries of a list, in the order of the keys of the list
+func compareKeys(One *etree.Element, Two *etree.Element) int {
+	for _, Key := range listKeys[One.Tag] {
+		if Order := compareKey(keyText(One, Key), keyText(Two, Key)); Order != 0 {
+			return Order
+		}
+	}
+	return 0
+}
+
+func keyText(Entry *etree.Element, Key string) string {
+	if Element := Entry.SelectElement(Key); Element != nil {
+		return strings.TrimSpace(Element.Text())
+	}
+	return ""
+}
+
+//keyChunks splits a key in its numbers and the text between them
+var keyChunks = regexp.MustCompile(`[0-9]+|[^0-9]+`)
+
+//compareKey compares two keys, their numbers compared by value so that 0/2 comes before 0/10 and 9.0.0.1
+//before 10.0.0.1
+func compareKey(One string, Two string) int {
+	OneChunks, TwoChunks := keyChunks.FindAllString(One, -1), keyChunks.FindAllString(Two, -1)
+	for iter := 0; iter < len(OneChunks) && iter < len(TwoChunks); iter++ {
+		OneNumber, OneErr := strconv.Atoi(OneChunks[iter])
+		TwoNumber, TwoErr := strconv.Atoi(TwoChunks[iter])
+		switch {
+		case OneErr == nil && TwoErr == nil && OneNumber < TwoNumber:
+			return -1
+		case OneErr == nil && TwoErr == nil && OneNumber > TwoNumber:
+			return 1
+		case OneChunks[iter] != TwoChunks[iter]:
+			return strings.Compare(OneChunks[iter], TwoChunks[iter])
+		}
+	}
+	return len(OneChunks) - len(TwoChunks)
+}
+
+//copyAttributes copies the attributes of an edited element, such as its namespace, but its operation
+func copyAttributes(Target *etree.Element, Edit *etree.Element) {
+	for _, Attr := range Edit.Attr {
+		switch {
+		case Attr.Key == "operation":
+		case Attr.Space != "":
+			Target.CreateAttr(Attr.Space+":"+Attr.Key, Attr.Value)
+		default:
+			Target.CreateAttr(Attr.Key, Attr.Value)
+		}
+	}
+}
+
+//matchingChild returns the child of the element having the name of the edited element and its keys
+func matchingChild(Parent *etree.Element, Edit *etree.Element) *etree.Element {
+	for _, Child := range Parent.SelectElements(Edit.Tag) {
+		if sameKeys(Child, Edit) {
+			return Child
+		}
+	}
+	return nil
+}
+
+func sameKeys(Element *etree.Element, Edit *etree.Element) bool {
+	for _, Key := range listKeys[Edit.Tag] {
+		EditKey := Edit.SelectElement(Key)
+		if EditKey == nil {
+			continue
+		}
+		ElementKey := Element.SelectElement(Key)
+		if ElementKey == nil || strings.TrimSpace(ElementKey.Text()) != strings.TrimSpace(EditKey.Text()) {
+			return false
+		}
+	}
+	return true
+}
+
+//getConfig returns the elements of the running-config selected by the xpath filter of a get-config in a
+//data element, each with its ancestors and the keys of the list entries among them
+func getConfig(Running *etree.Document, Select string) (*etree.Element, error) {
+	Path, err := etree.CompilePath(Select)
+	if err != nil {
+		return nil, err
+	}
+	Data := etree.NewElement("data")
+	Copies := make(map[*etree.Element]*etree.Element)
+	Selected := make(map[*etree.Element]bool)
+	for _, Found := range Running.FindElementsPath(Path) {
+		if selectedAncestor(Selected, Found) {
+			continue
+		}
+		Selected[Found] = true
+		ancestorCopy(Copies, Data, Found.Parent()).AddChild(Found.Copy())
+	}
+	return Data, nil
+}
+
+//selectedAncestor tells whether the element is already returned with a selected ancestor
+func selectedAncestor(Selected map[*etree.Element]bool, Element *etree.Element) bool {
+	for Ancestor := Element.Parent(); Ancestor != nil; Ancestor = Ancestor.Parent() {
+		if Selected[Ancestor] {
+			return true
+		}
+	}
+	return false
+}
+
+//ancestorCopy returns the copy of an ancestor of a selected element, created with its attributes and keys
+//in the data element. The document, which has no parent, is copied as the data element.
+func ancestorCopy(Copies map[*etree.Element]*etree.Element, Data *etree.Element, Ancestor *etree.Element) *etree.Element {
+	if Ancestor.Parent() == nil {
+		return Data
+	}
+	if Copy, found := Copies[Ancestor]; found {
+		return Copy
+	}
+	Parent := ancestorCopy(Copies, Data, Ancestor.Parent())
+	Copy := Parent.CreateElement(Ancestor.Tag)
+	Copy.Space = Ancestor.Space
+	Copy.Attr = append(Copy.Attr, Ancestor.Attr...)
+	for _, Key := range listKeys[Ancestor.Tag] {
+		if KeyElement := Ancestor.SelectElement(Key); KeyElement != nil {
+			Copy.AddChild(KeyElement.Copy())
+		}
+	}
+	Copies[Ancestor] = Copy
+	return Copy
+}
@@ -0,0 +1,159 @@
+package simulator
+
+import (
+	"efa-server/infra/device/actions"
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/rest"
+	"efa-server/test/functional"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+//LabServerAddress is the address of the REST API of the efa-server started with the lab, the default server
+//of the efa CLI
+const LabServerAddress = "localhost:8081"
+
+//LabPortCount is the number of ethernet ports of the switches of the lab
+const LabPortCount = 56
+
+//Firmwares of the switches of the lab by model
+var Firmwares = map[string]string{
+	ad.AvalancheType: "18r.1.00b",
+	ad.FusionType:    "18r.1.00b",
+	ad.CedarType:     "18s.1.01b",
+	ad.FreedomType:   "18s.1.01b",
+	ad.OrcaType:      "18x.1.00a",
+	ad.OrcaTType:     "18x.1.00a",
+}
+
+//RackPorts are the MCT ports and the L3 backup port linking the switches of a non-CLOS rack
+var RackPorts = []string{"0/46", "0/47", "0/48"}
+
+//labPair is an MCT pair of the lab with the ports linking its switches and the ports of each switch cabled
+//to the spines in order, free ports when there are none
+type labPair struct {
+	One     string
+	Two     string
+	Model   string
+	Ports   []string
+	Uplinks []string
+}
+
+//NewLab returns a network simulating the switches with the IP addresses of the functional tests: a CLOS
+//of the Cedar spines with the Freedom, Avalanche and Orca MCT pairs as leaves, cabled on the ports the
+//cluster and integration tests use, and the racks of the non-CLOS tests as MCT pairs of Freedom switches.
+//The racks are paired on the MCT and L3 backup ports of the non-CLOS fabrics and cabled in a chain, the
+//third rack being the Freedom leaves.
+func NewLab() (*Network, error) {
+	Lab := NewNetwork()
+	newSwitch := func(IP string, Model string) *Switch {
+		Switch := NewSwitch(IP, Model, Firmwares[Model], LabPortCount)
+		Lab.Add(Switch)
+		return Switch
+	}
+	Spines := []*Switch{
+		newSwitch(functional.IntegrationTestSpine1IP, ad.CedarType),
+		newSwitch(functional.NetConfTestSpineIP, ad.CedarType),
+	}
+	Leaves := []labPair{
+		{functional.ActionsMCTNode1Ip, functional.ActionsMCTNode2Ip, ad.FreedomType, RackPorts,
+			[]string{functional.IntegrationTestLeaf2IPLink1}},
+		{functional.ActionsAVAMCTNode1Ip, functional.ActionsAVAMCTNode2Ip, ad.AvalancheType, []string{"0/19", "0/20"}, nil},
+		{functional.ActionsORCAMCTNode1Ip, functional.ActionsORCAMCTNode2Ip, ad.OrcaType, []string{"0/50"}, nil},
+	}
+	Racks := []labPair{
+		{functional.IntegrationNonClosTestRack1IP1, functional.IntegrationNonClosTestRack1IP2, ad.FreedomType, RackPorts, nil},
+		{functional.IntegrationNonClosTestRack2IP1, functional.IntegrationNonClosTestRack2IP2, ad.FreedomType, RackPorts, nil},
+		{functional.IntegrationNonClosTestRack4IP1, functional.IntegrationNonClosTestRack4IP2, ad.FreedomType, RackPorts, nil},
+	}
+	for _, Pair := range append(Leaves, Racks...) {
+		One, Two := newSwitch(Pair.One, Pair.Model), newSwitch(Pair.Two, Pair.Model)
+		if err := Lab.Pair(One, Two, Pair.Ports...); err != nil {
+			return Lab, err
+		}
+	}
+	for _, Pair := range Leaves {
+		for _, Leaf := range []*Switch{Lab.Switch(Pair.One), Lab.Switch(Pair.Two)} {
+			for iter, Spine := range Spines {
+				var err error
+				if iter < len(Pair.Uplinks) {
+					err = Lab.Cable(Leaf, Pair.Uplinks[iter], Spine, Lab.freePort(Spine))
+				} else {
+					err = Lab.Connect(Leaf, Spine)
+				}
+				if err != nil {
+					return Lab, err
+				}
+			}
+		}
+	}
+	Chain := []string{functional.IntegrationNonClosTestRack1IP1, functional.IntegrationNonClosTestRack2IP1,
+		functional.IntegrationNonClosTestRack3IP1, functional.IntegrationNonClosTestRack4IP1}
+	for iter := 1; iter < len(Chain); iter++ {
+		if err := Lab.Connect(Lab.Switch(Chain[iter-1]), Lab.Switch(Chain[iter])); err != nil {
+			return Lab, err
+		}
+	}
+	newSwitch(functional.ActionsTestLeafIP, ad.FreedomType)
+	return Lab, nil
+}
+
+//StartLab starts the lab when the SIMULATOR environment variable is set to 1, for the functional tests to
+//run without switches, and returns the function stopping it. The management cluster is polled every
+//second, the simulated cluster being formed at once.
+func StartLab() func() {
+	if os.Getenv("SIMULATOR") != "1" {
+		return func() {}
+	}
+	Lab, err := NewLab()
+	if err == nil {
+		err = Lab.Start()
+	}
+	if err != nil {
+		fmt.Println("Failed to start the simulated switches:", err)
+		os.Exit(1)
+	}
+	PollingInterval := actions.MgmtClusterStatePollingIntervalInSec
+	actions.MgmtClusterStatePollingIntervalInSec = 1
+	return func() {
+		actions.MgmtClusterStatePollingIntervalInSec = PollingInterval
+		Lab.Stop()
+	}
+}
+
+//StartLabServer starts the lab, along with an efa-server serving the REST API on LabServerAddress, when the
+//SIMULATOR environment variable is set to 1. The integration tests run the efa CLI, which reaches the simulated
+//switches through this efa-server. It returns the function stopping both.
+func StartLabServer() func() {
+	stopLab := StartLab()
+	if os.Getenv("SIMULATOR") != "1" {
+		return stopLab
+	}
+	var ServerGate sync.WaitGroup
+	Server := rest.NewOpenAPIServer(&ServerGate)
+	ServerGate.Add(1)
+	go Server.RunOpenAPIServer()
+	for Started := time.Now(); ; time.Sleep(100 * time.Millisecond) {
+		Conn, err := net.Dial("tcp", LabServerAddress)
+		if err == nil {
+			Conn.Close()
+			break
+		}
+		if time.Since(Started) > 30*time.Second {
+			fmt.Println("Failed to start the efa-server of the simulated switches:", err)
+			stopLab()
+			os.Exit(1)
+		}
+	}
+	return func() {
+		if Response, err := http.Get("http://" + LabServerAddress + "/shutdown"); err == nil {
+			Response.Body.Close()
+			ServerGate.Wait()
+		}
+		stopLab()
+	}
+}
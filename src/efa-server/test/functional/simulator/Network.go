@@ -0,0 +1,283 @@
+package simulator
+
+import (
+	"efa-server/infra/device/client"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+//End is the port of a switch at one end of a link
+type End struct {
+	Switch *Switch
+	Port   *Port
+}
+
+//Link is a cable between the ports of two switches, discovered by LLDP on both of them
+type Link struct {
+	One End
+	Two End
+}
+
+//end returns the end of the link on the switch and the remote end
+func (Link Link) end(s *Switch) (End, End) {
+	if Link.One.Switch == s {
+		return Link.One, Link.Two
+	}
+	return Link.Two, Link.One
+}
+
+//Network is a set of cabled switches reached by the NETCONF and SSH clients of the efa-server instead of the
+//switches with their IP addresses, once started
+type Network struct {
+	mutex     sync.Mutex
+	devices   map[string]*Switch
+	cabling   []Link
+	pairs     map[*Switch]*Switch
+	listeners map[string]net.Listener
+	conns     map[net.Conn]bool
+	dialer    func(network, address string) (net.Conn, error)
+}
+
+//NewNetwork returns a network of the switches, not cabled
+func NewNetwork(Switches ...*Switch) *Network {
+	Network := &Network{devices: make(map[string]*Switch), pairs: make(map[*Switch]*Switch),
+		listeners: make(map[string]net.Listener), conns: make(map[net.Conn]bool)}
+	Network.Add(Switches...)
+	return Network
+}
+
+//NewCLOS returns a network cabling each leaf to each spine with the first free ports, and the two
+//leaves of each MCT pair together with two ports
+func NewCLOS(Spines []*Switch, Leaves []*Switch, MCTPairs [][2]*Switch) (*Network, error) {
+	Network := NewNetwork(Spines...)
+	Network.Add(Leaves...)
+	for _, Leaf := range Leaves {
+		for _, Spine := range Spines {
+			if err := Network.Connect(Leaf, Spine); err != nil {
+				return Network, err
+			}
+		}
+	}
+	for _, Pair := range MCTPairs {
+		if err := Network.Pair(Pair[0], Pair[1]); err != nil {
+			return Network, err
+		}
+	}
+	return Network, nil
+}
+
+//Add adds the switches to the network
+func (n *Network) Add(Switches ...*Switch) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, Switch := range Switches {
+		Switch.network = n
+		n.devices[Switch.IP] = Switch
+	}
+}
+
+//Switch returns the switch of the network with the IP address, nil when there is none
+func (n *Network) Switch(IP string) *Switch {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.devices[IP]
+}
+
+//Cable links the ports of the two switches
+func (n *Network) Cable(One *Switch, OnePort string, Two *Switch, TwoPort string) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if One.Port(OnePort) == nil {
+		return fmt.Errorf("%s has no port %s", One.IP, OnePort)
+	}
+	if Two.Port(TwoPort) == nil {
+		return fmt.Errorf("%s has no port %s", Two.IP, TwoPort)
+	}
+	if One == Two {
+		return fmt.Errorf("%s cannot be cabled to itself", One.IP)
+	}
+	Link := Link{One: End{One, One.Port(OnePort)}, Two: End{Two, Two.Port(TwoPort)}}
+	for _, End := range []End{Link.One, Link.Two} {
+		if n.cabled(End) {
+			return fmt.Errorf("%s %s is already cabled", End.Switch.IP, End.Port.Name)
+		}
+	}
+	n.cabling = append(n.cabling, Link)
+	return nil
+}
+
+//Connect links the first free ports of the two switches
+func (n *Network) Connect(One *Switch, Two *Switch) error {
+	OnePort, TwoPort := n.freePort(One), n.freePort(Two)
+	if OnePort == "" || TwoPort == "" {
+		return fmt.Errorf("no free port to cable %s to %s", One.IP, Two.IP)
+	}
+	return n.Cable(One, OnePort, Two, TwoPort)
+}
+
+//Pair links the ports of the two switches with the same names, or two free ports of each when none are
+//given, and makes them the MCT peers of each other
+func (n *Network) Pair(One *Switch, Two *Switch, Ports ...string) error {
+	for _, Port := range Ports {
+		if err := n.Cable(One, Port, Two, Port); err != nil {
+			return err
+		}
+	}
+	for iter := 0; len(Ports) == 0 && iter < 2; iter++ {
+		if err := n.Connect(One, Two); err != nil {
+			return err
+		}
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.pairs[One], n.pairs[Two] = Two, One
+	return nil
+}
+
+func (n *Network) cabled(End End) bool {
+	for _, Link := range n.cabling {
+		if Link.One == End || Link.Two == End {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Network) freePort(Switch *Switch) string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for iter := range Switch.Ports {
+		if !n.cabled(End{Switch, &Switch.Ports[iter]}) {
+			return Switch.Ports[iter].Name
+		}
+	}
+	return ""
+}
+
+//links returns the links of the switch
+func (n *Network) links(Switch *Switch) []Link {
+	if n == nil {
+		return nil
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	Links := make([]Link, 0)
+	for _, Link := range n.cabling {
+		if Link.One.Switch == Switch || Link.Two.Switch == Switch {
+			Links = append(Links, Link)
+		}
+	}
+	return Links
+}
+
+//peer returns the MCT peer of the switch, nil when it has none
+func (n *Network) peer(Switch *Switch) *Switch {
+	if n == nil {
+		return nil
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.pairs[Switch]
+}
+
+//switches returns the switches of the network ordered by IP address
+func (n *Network) switches() []*Switch {
+	if n == nil {
+		return nil
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	Switches := make([]*Switch, 0, len(n.devices))
+	for _, Switch := range n.devices {
+		Switches = append(Switches, Switch)
+	}
+	sort.Slice(Switches, func(i, j int) bool { return Switches[i].IP < Switches[j].IP })
+	return Switches
+}
+
+//owner returns the switch of the network with the address configured on one of its interfaces
+func (n *Network) owner(Address string) *Switch {
+	for _, Switch := range n.switches() {
+		if Switch.owns(Address) {
+			return Switch
+		}
+	}
+	return nil
+}
+
+//Start listens for the connections to each switch on a local port and has the clients of the
+//efa-server dial them instead of the switches with their IP addresses. The other addresses are dialed
+//as before.
+func (n *Network) Start() error {
+	for _, Switch := range n.switches() {
+		Listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			n.Stop()
+			return err
+		}
+		n.mutex.Lock()
+		n.listeners[Switch.IP] = Listener
+		n.mutex.Unlock()
+		go n.serve(Listener, Switch)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.dialer = client.Dial
+	client.Dial = n.dial
+	return nil
+}
+
+//Stop closes the listeners and the connections of the switches and restores the dialer of the clients
+func (n *Network) Stop() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.dialer != nil {
+		client.Dial = n.dialer
+		n.dialer = nil
+	}
+	for IP, Listener := range n.listeners {
+		Listener.Close()
+		delete(n.listeners, IP)
+	}
+	for Conn := range n.conns {
+		Conn.Close()
+		delete(n.conns, Conn)
+	}
+}
+
+func (n *Network) dial(network string, address string) (net.Conn, error) {
+	n.mutex.Lock()
+	Dialer := n.dialer
+	var Listener net.Listener
+	if Host, _, err := net.SplitHostPort(address); err == nil {
+		Listener = n.listeners[Host]
+	}
+	n.mutex.Unlock()
+	if Listener == nil && Dialer != nil {
+		return Dialer(network, address)
+	}
+	if Listener == nil {
+		return net.Dial(network, address)
+	}
+	return net.Dial(network, Listener.Addr().String())
+}
+
+func (n *Network) serve(Listener net.Listener, Switch *Switch) {
+	for {
+		Conn, err := Listener.Accept()
+		if err != nil {
+			return
+		}
+		n.mutex.Lock()
+		n.conns[Conn] = true
+		n.mutex.Unlock()
+		go func() {
+			Switch.serveConn(Conn)
+			n.mutex.Lock()
+			delete(n.conns, Conn)
+			n.mutex.Unlock()
+		}()
+	}
+}
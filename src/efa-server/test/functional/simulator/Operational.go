@@ -0,0 +1,495 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+//rpcError is the error returned in the rpc-reply of a NETCONF RPC the switch fails
+type rpcError struct {
+	Tag     string
+	Message string
+}
+
+//execute runs the operation of a NETCONF RPC and returns the content of its rpc-reply
+func (s *Switch) execute(Operation *etree.Element) (string, *rpcError) {
+	switch Operation.Tag {
+	case "get-config":
+		return s.getConfig(Operation)
+	case "edit-config":
+		Config := Operation.SelectElement("config")
+		s.mutex.Lock()
+		err := editConfig(s.running, Config)
+		s.mutex.Unlock()
+		if err == errMalformedConfig {
+			return "", &rpcError{Tag: "malformed-message", Message: err.Error()}
+		}
+		if err != nil {
+			return "", &rpcError{Tag: "operation-failed", Message: err.Error()}
+		}
+		s.syncCluster(Config)
+		return "<ok/>", nil
+	case "bna-config-cmd":
+		return s.persistConfig(), nil
+	case "action":
+		if Operation.FindElement(".//chassis") != nil {
+			return s.chassis(), nil
+		}
+	case "show-firmware-version":
+		return s.firmwareVersion(), nil
+	case "get-lldp-neighbor-detail":
+		return s.lldpNeighbors(), nil
+	case "get-interface-detail":
+		return s.interfaceDetail(Operation)
+	case "get-ip-interface":
+		return s.ipInterfaces(), nil
+	case "get-port-channel-detail":
+		return s.portChannelDetail(Operation), nil
+	case "show-cluster-management":
+		return s.managementCluster(), nil
+	case "show-cluster":
+		return s.cluster(), nil
+	case "get-ip-bgp-neighbor-brief", "get-bgp-evpn-neighbor-brief":
+		return s.bgpNeighbors(), nil
+	case "get-bfd-session-brief":
+		return s.bfdSessions(), nil
+	case "get-tunnel-info":
+		return s.tunnels(), nil
+	case "get-ip-route-summary":
+		return s.routeSummary(), nil
+	}
+	return "", &rpcError{Tag: "operation-not-supported", Message: Operation.Tag + " is not simulated"}
+}
+
+//serialize returns the XML of the elements, the empty ones closed by an end tag as the switches do
+func serialize(Elements ...*etree.Element) string {
+	Document := etree.NewDocument()
+	Document.WriteSettings.CanonicalEndTags = true
+	for _, Element := range Elements {
+		Document.AddChild(Element)
+	}
+	XML, _ := Document.WriteToString()
+	return XML
+}
+
+//leaf adds a child element with the text to the element
+func leaf(Parent *etree.Element, Tag string, Text string) {
+	Parent.CreateElement(Tag).SetText(Text)
+}
+
+func (s *Switch) getConfig(Operation *etree.Element) (string, *rpcError) {
+	Select := "/*"
+	if Filter := Operation.SelectElement("filter"); Filter != nil {
+		Select = Filter.SelectAttrValue("select", Select)
+	}
+	Data, err := getConfig(s.snapshot(), Select)
+	if err != nil {
+		return "", &rpcError{Tag: "invalid-value", Message: err.Error()}
+	}
+	return serialize(Data), nil
+}
+
+//persistConfig copies the running-config to the startup-config
+func (s *Switch) persistConfig() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.startup = s.running.Copy()
+	s.persists++
+	return fmt.Sprintf("<session-id>%d</session-id><status>completed</status>", s.persists)
+}
+
+func (s *Switch) chassis() string {
+	Chassis := etree.NewElement("chassis")
+	leaf(Chassis, "switch-type", s.Model)
+	leaf(Chassis, "chassis-name", "SLX-"+s.Model)
+	return serialize(Chassis)
+}
+
+func (s *Switch) firmwareVersion() string {
+	Firmware := etree.NewElement("show-firmware-version")
+	leaf(Firmware, "os-name", "SLX-OS")
+	leaf(Firmware, "os-version", osVersion.FindString(s.Firmware))
+	leaf(Firmware, "firmware-full-version", s.Firmware)
+	return serialize(Firmware)
+}
+
+//ethernetConfigs returns the running-config of the ethernet interfaces by name
+func ethernetConfigs(Running *etree.Document) map[string]*etree.Element {
+	Configs := make(map[string]*etree.Element)
+	for _, Ethernet := range Running.FindElements("/interface/ethernet") {
+		if Name := Ethernet.SelectElement("name"); Name != nil {
+			Configs[strings.TrimSpace(Name.Text())] = Ethernet
+		}
+	}
+	return Configs
+}
+
+//lldpNeighbors returns the ports cabled to a port of the switch, but the ports shut down, in a single page
+func (s *Switch) lldpNeighbors() string {
+	Ethernets := ethernetConfigs(s.snapshot())
+	Neighbors := make([]*etree.Element, 0)
+	for _, Link := range s.network.links(s) {
+		Local, Remote := Link.end(s)
+		if Config := Ethernets[Local.Port.Name]; Config == nil || shutdown(Config) {
+			continue
+		}
+		Neighbor := etree.NewElement("lldp-neighbor-detail")
+		leaf(Neighbor, "local-interface-name", "Eth "+Local.Port.Name)
+		leaf(Neighbor, "local-interface-ifindex", strconv.Itoa(s.ifindex(Local.Port.Name)))
+		leaf(Neighbor, "local-interface-mac", Local.Port.Mac)
+		leaf(Neighbor, "remote-interface-name", "Eth "+Remote.Port.Name)
+		leaf(Neighbor, "remote-interface-mac", Remote.Port.Mac)
+		leaf(Neighbor, "remote-management-address", Remote.Switch.IP)
+		leaf(Neighbor, "remote-system-name", "SLX-"+Remote.Switch.IP)
+		Neighbors = append(Neighbors, Neighbor)
+	}
+	HasMore := etree.NewElement("has-more")
+	HasMore.SetText("false")
+	return serialize(append(Neighbors, HasMore)...)
+}
+
+//ifindex returns the interface index of the port
+func (s *Switch) ifindex(Name string) int {
+	for iter, Port := range s.Ports {
+		if Port.Name == Name {
+			return 201326592 + iter
+		}
+	}
+	return 0
+}
+
+func portDetail(Port *Port) *etree.Element {
+	Interface := etree.NewElement("interface")
+	leaf(Interface, "interface-type", "ethernet")
+	leaf(Interface, "interface-name", Port.Name)
+	leaf(Interface, "current-hardware-address", Port.Mac)
+	leaf(Interface, "actual-line-speed", Port.Speed)
+	return Interface
+}
+
+//interfaceDetail returns the detail of the ethernet interface requested, or of all of them in a single page
+func (s *Switch) interfaceDetail(Operation *etree.Element) (string, *rpcError) {
+	HasMore := etree.NewElement("has-more")
+	HasMore.SetText("false")
+	if Name := Operation.SelectElement("interface-name"); Name != nil {
+		Port := s.Port(strings.TrimSpace(Name.Text()))
+		if Port == nil || strings.TrimSpace(Operation.SelectElement("interface-type").Text()) != "ethernet" {
+			return "", &rpcError{Tag: "invalid-value", Message: "%Error: Interface " + Name.Text() + " does not exist"}
+		}
+		return serialize(portDetail(Port), HasMore), nil
+	}
+	Interfaces := make([]*etree.Element, 0, len(s.Ports)+1)
+	for iter := range s.Ports {
+		Interfaces = append(Interfaces, portDetail(&s.Ports[iter]))
+	}
+	return serialize(append(Interfaces, HasMore)...), nil
+}
+
+func ipInterface(Type string, Name string, Config *etree.Element) *etree.Element {
+	Interface := etree.NewElement("interface")
+	leaf(Interface, "interface-type", Type)
+	leaf(Interface, "interface-name", Name)
+	State, Address := "up", "unassigned"
+	if Config == nil || shutdown(Config) {
+		State = "down"
+	}
+	if Config != nil && interfaceAddress(Config) != "" {
+		Address = interfaceAddress(Config)
+	}
+	leaf(Interface, "if-state", State)
+	leaf(Interface, "ipv4", Address)
+	return Interface
+}
+
+//ipInterfaces returns the state and the address of the ethernet, loopback and ve interfaces
+func (s *Switch) ipInterfaces() string {
+	Running := s.snapshot()
+	Ethernets := ethernetConfigs(Running)
+	Interfaces := make([]*etree.Element, 0)
+	for _, Port := range s.Ports {
+		Interfaces = append(Interfaces, ipInterface("ethernet", Port.Name, Ethernets[Port.Name]))
+	}
+	for _, Loopback := range Running.FindElements("/routing-system/interface/loopback[id]") {
+		Interfaces = append(Interfaces, ipInterface("loopback", Loopback.SelectElement("id").Text(), Loopback))
+	}
+	for _, Ve := range Running.FindElements("/routing-system/interface/ve[name]") {
+		Interfaces = append(Interfaces, ipInterface("ve", Ve.SelectElement("name").Text(), Ve))
+	}
+	return serialize(Interfaces...)
+}
+
+//portChannelDetail returns the member ports of the port channel requested
+func (s *Switch) portChannelDetail(Operation *etree.Element) string {
+	Name := ""
+	if ID := Operation.SelectElement("aggregator-id"); ID != nil {
+		Name = strings.TrimSpace(ID.Text())
+	}
+	Lacp := etree.NewElement("lacp")
+	leaf(Lacp, "aggregator-id", Name)
+	Mode, Type := "", ""
+	for _, Ethernet := range s.snapshot().FindElements("/interface/ethernet") {
+		Group := Ethernet.SelectElement("channel-group")
+		if Group == nil || Group.SelectElement("port-int") == nil ||
+			strings.TrimSpace(Group.SelectElement("port-int").Text()) != Name {
+			continue
+		}
+		if Element := Group.SelectElement("mode"); Element != nil {
+			Mode = Element.Text()
+		}
+		if Element := Group.SelectElement("type"); Element != nil {
+			Type = Element.Text()
+		}
+		Member := Lacp.CreateElement("aggr-member")
+		leaf(Member, "interface-type", "ethernet")
+		leaf(Member, "interface-name", Ethernet.SelectElement("name").Text())
+	}
+	leaf(Lacp, "aggregator-mode", Mode)
+	leaf(Lacp, "aggregator-type", Type)
+	return serialize(Lacp)
+}
+
+//node returns the node-id of the switch configured by the CLI
+func (s *Switch) node() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.nodeID
+}
+
+//clusterPeer returns the MCT peer of the switch when they form a management cluster, which they do once the
+//ports of a link between them are members of a port-channel on both switches
+func (s *Switch) clusterPeer() *Switch {
+	Peer := s.network.peer(s)
+	if Peer == nil {
+		return nil
+	}
+	Local, Remote := ethernetConfigs(s.snapshot()), ethernetConfigs(Peer.snapshot())
+	for _, Link := range s.network.links(s) {
+		This, Other := Link.end(s)
+		if Other.Switch == Peer && bundled(Local[This.Port.Name]) && bundled(Remote[Other.Port.Name]) {
+			return Peer
+		}
+	}
+	return nil
+}
+
+//bundled tells whether the ethernet interface of the running-config is an active member of a port-channel,
+//deleting the port-channel releasing its members
+func bundled(Ethernet *etree.Element) bool {
+	if Ethernet == nil || shutdown(Ethernet) {
+		return false
+	}
+	PortInt := Ethernet.FindElement("channel-group/port-int")
+	if PortInt == nil {
+		return false
+	}
+	PortChannel := Ethernet.Parent().FindElement(fmt.Sprintf("port-channel[name='%s']", strings.TrimSpace(PortInt.Text())))
+	return PortChannel != nil && !shutdown(PortChannel)
+}
+
+//clusterMembers returns the switch and the peer it forms a management cluster with, ordered by IP address
+func (s *Switch) clusterMembers() []*Switch {
+	Members := []*Switch{s}
+	if Peer := s.clusterPeer(); Peer != nil {
+		Members = append(Members, Peer)
+	}
+	sort.Slice(Members, func(i, j int) bool { return Members[i].IP < Members[j].IP })
+	return Members
+}
+
+//clusterSynced are the elements of the running-config the management cluster synchronizes between its nodes,
+//which the efa-server configures on one of the MCT peers only
+var clusterSynced = []string{"overlay-gateway"}
+
+//syncCluster applies the synchronized elements of an edited config to the peer of the management cluster
+func (s *Switch) syncCluster(Config *etree.Element) {
+	Synced := etree.NewElement("config")
+	for _, Tag := range clusterSynced {
+		for _, Element := range Config.SelectElements(Tag) {
+			Synced.AddChild(Element.Copy())
+		}
+	}
+	if len(Synced.ChildElements()) == 0 {
+		return
+	}
+	if Peer := s.clusterPeer(); Peer != nil {
+		Peer.mutex.Lock()
+		defer Peer.mutex.Unlock()
+		editConfig(Peer.running, Synced)
+	}
+}
+
+//managementCluster returns the management cluster of the switch, the member with the lowest IP address
+//being the principal
+func (s *Switch) managementCluster() string {
+	Members := s.clusterMembers()
+	Elements := make([]*etree.Element, 0)
+	Principal := etree.NewElement("principal-switch-mac")
+	Principal.SetText(Members[0].mac(0))
+	Total := etree.NewElement("total-nodes-in-cluster")
+	Total.SetText(strconv.Itoa(len(Members)))
+	Disconnected := etree.NewElement("nodes-disconnected-from-cluster")
+	Disconnected.SetText("0")
+	Elements = append(Elements, Principal, Total, Disconnected)
+	for iter, Member := range Members {
+		Node := etree.NewElement("cluster-node-info")
+		leaf(Node, "node-serial-num", "SIM"+strings.Replace(Member.IP, ".", "", -1))
+		leaf(Node, "node-switch-mac", Member.mac(0))
+		leaf(Node, "node-public-ip-address", Member.IP)
+		leaf(Node, "node-internal-ip-address", fmt.Sprintf("127.2.0.%d", iter+1))
+		leaf(Node, "node-id", Member.node())
+		leaf(Node, "node-condition", "Good")
+		leaf(Node, "node-status", "Connected to Cluster")
+		leaf(Node, "node-is-principal", strconv.FormatBool(iter == 0))
+		leaf(Node, "node-is-local", strconv.FormatBool(Member == s))
+		leaf(Node, "node-switchtype", "SLX-"+Member.Model)
+		leaf(Node, "firmware-version", Member.Firmware)
+		Elements = append(Elements, Node)
+	}
+	return serialize(Elements...)
+}
+
+//deployedCluster returns the MCT cluster of the running-config when it is deployed
+func deployedCluster(Running *etree.Document) *etree.Element {
+	if Cluster := Running.FindElement("/cluster[cluster-name]"); Cluster != nil && Cluster.SelectElement("deploy") != nil {
+		return Cluster
+	}
+	return nil
+}
+
+//cluster returns the state of the MCT cluster, its peer is up when the switch forms a management cluster with
+//its MCT peer, which owns the peer IP address and has deployed its cluster
+func (s *Switch) cluster() string {
+	Running := s.snapshot()
+	Cluster := Running.FindElement("/cluster[cluster-name]")
+	if Cluster == nil {
+		return ""
+	}
+	Status := etree.NewElement("cluster")
+	leaf(Status, "cluster-name", Cluster.SelectElement("cluster-name").Text())
+	if ID := Cluster.SelectElement("cluster-id"); ID != nil {
+		leaf(Status, "cluster-id", ID.Text())
+	}
+	State, PeerIP, PeerState := "Not Deployed", "", "Down"
+	if PeerElement := Cluster.FindElement("peer/peer-ip"); PeerElement != nil {
+		PeerIP = strings.TrimSpace(PeerElement.Text())
+	}
+	if deployedCluster(Running) != nil {
+		State = "Deployed"
+		if Peer := s.clusterPeer(); Peer != nil && Peer.owns(PeerIP) && deployedCluster(Peer.snapshot()) != nil {
+			PeerState = "Up"
+		}
+	}
+	leaf(Status, "cluster-state", State)
+	leaf(Status, "peer-ip", PeerIP)
+	leaf(Status, "peer-state", PeerState)
+	return serialize(Status)
+}
+
+//establishedNeighbors returns the BGP neighbours of the running-config with whether the session is
+//established, which it is when a switch of the network running BGP owns the neighbour address
+func (s *Switch) establishedNeighbors() ([]*etree.Element, []bool) {
+	Neighbors := s.snapshot().FindElements("//neighbor-addr[router-bgp-neighbor-address]")
+	Established := make([]bool, len(Neighbors))
+	for iter, Neighbor := range Neighbors {
+		Address := strings.TrimSpace(Neighbor.SelectElement("router-bgp-neighbor-address").Text())
+		if Owner := s.network.owner(Address); Owner != nil && Owner != s &&
+			Owner.snapshot().FindElement("/routing-system/router/router-bgp") != nil {
+			Established[iter] = true
+		}
+	}
+	return Neighbors, Established
+}
+
+func (s *Switch) bgpNeighbors() string {
+	Summaries := make([]*etree.Element, 0)
+	Neighbors, Established := s.establishedNeighbors()
+	for iter, Neighbor := range Neighbors {
+		Summary := etree.NewElement("neighbor-summary")
+		leaf(Summary, "neighbor-ip-addr", strings.TrimSpace(Neighbor.SelectElement("router-bgp-neighbor-address").Text()))
+		if RemoteAS := Neighbor.SelectElement("remote-as"); RemoteAS != nil {
+			leaf(Summary, "neighbor-as", strings.TrimSpace(RemoteAS.Text()))
+		}
+		if Established[iter] {
+			leaf(Summary, "neighbor-state", "ESTABLISHED")
+			leaf(Summary, "neighbor-up-time", "0d00h01m")
+		} else {
+			leaf(Summary, "neighbor-state", "IDLE")
+			leaf(Summary, "neighbor-up-time", "0d00h00m")
+		}
+		Summaries = append(Summaries, Summary)
+	}
+	return serialize(Summaries...)
+}
+
+//bfdSessions returns a session up to each established BGP neighbour when BFD is enabled on router bgp
+func (s *Switch) bfdSessions() string {
+	Sessions := make([]*etree.Element, 0)
+	if s.snapshot().FindElement("/routing-system/router/router-bgp/router-bgp-attributes/bfd") == nil {
+		return ""
+	}
+	Neighbors, Established := s.establishedNeighbors()
+	for iter, Neighbor := range Neighbors {
+		if !Established[iter] {
+			continue
+		}
+		Session := etree.NewElement("bfd-session")
+		leaf(Session, "neighbor-ip-addr", strings.TrimSpace(Neighbor.SelectElement("router-bgp-neighbor-address").Text()))
+		leaf(Session, "interface-name", "")
+		leaf(Session, "session-state", "Up")
+		Sessions = append(Sessions, Session)
+	}
+	return serialize(Sessions...)
+}
+
+//tunnels returns a tunnel up to each switch of the network with an activated overlay gateway
+func (s *Switch) tunnels() string {
+	Tunnels := make([]*etree.Element, 0)
+	Source := vtep(s.snapshot())
+	if Source == "" {
+		return ""
+	}
+	for _, Other := range s.network.switches() {
+		Destination := ""
+		if Other != s {
+			Destination = vtep(Other.snapshot())
+		}
+		if Destination == "" || Destination == Source {
+			continue
+		}
+		Tunnel := etree.NewElement("tunnel")
+		leaf(Tunnel, "id", strconv.Itoa(61441+len(Tunnels)))
+		leaf(Tunnel, "src-ip", Source)
+		leaf(Tunnel, "dest-ip", Destination)
+		leaf(Tunnel, "admin-state", "up")
+		leaf(Tunnel, "oper-state", "up")
+		Tunnels = append(Tunnels, Tunnel)
+	}
+	return serialize(Tunnels...)
+}
+
+//routeSummary counts a route to each address configured on the switch and on the switches of the network
+//running BGP when the switch runs BGP
+func (s *Switch) routeSummary() string {
+	Routes := make(map[string]bool)
+	for _, Address := range addresses(s.snapshot()) {
+		Routes[Address] = true
+	}
+	if s.snapshot().FindElement("/routing-system/router/router-bgp") != nil {
+		for _, Other := range s.network.switches() {
+			Running := Other.snapshot()
+			if Other == s || Running.FindElement("/routing-system/router/router-bgp") == nil {
+				continue
+			}
+			for _, Address := range addresses(Running) {
+				Routes[Address] = true
+			}
+		}
+	}
+	Summary := etree.NewElement("total-routes")
+	Summary.SetText(strconv.Itoa(len(Routes)))
+	return serialize(Summary)
+}
@@ -0,0 +1,202 @@
+package simulator
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/beevik/etree"
+	"golang.org/x/crypto/ssh"
+)
+
+//netconfSeparator ends the NETCONF 1.0 messages
+const netconfSeparator = "]]>]]>"
+
+//netconfHello is the hello of the switch, with the id of the NETCONF session
+const netconfHello = `<?xml version="1.0" encoding="UTF-8"?>` +
+	`<hello xmlns="urn:ietf:params:xml:ns:netconf:base:1.0"><capabilities>` +
+	`<capability>urn:ietf:params:netconf:base:1.0</capability>` +
+	`<capability>urn:ietf:params:netconf:capability:xpath:1.0</capability>` +
+	`</capabilities><session-id>%d</session-id></hello>` + netconfSeparator
+
+//cliMarker matches a command of the SSH client followed by the echo of the marker ending its output
+var cliMarker = regexp.MustCompile(`^(.*?)\s*;\s*(do\s+)?oscmd echo "([^"]*)"\s*$`)
+
+var hostKey ssh.Signer
+var hostKeyErr error
+var hostKeyOnce sync.Once
+
+//sshHostKey returns the host key of the switches, generated once
+func sshHostKey() (ssh.Signer, error) {
+	hostKeyOnce.Do(func() {
+		Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			hostKeyErr = err
+			return
+		}
+		hostKey, hostKeyErr = ssh.NewSignerFromKey(Key)
+	})
+	return hostKey, hostKeyErr
+}
+
+//serveConn runs the SSH server of the switch on the connection, serving the netconf subsystem and the CLI
+//shell to the user with the password of the switch. The aes128-cbc cipher of the NETCONF client is offered.
+func (s *Switch) serveConn(Conn net.Conn) {
+	defer Conn.Close()
+	HostKey, err := sshHostKey()
+	if err != nil {
+		return
+	}
+	Config := &ssh.ServerConfig{
+		Config: ssh.Config{Ciphers: []string{"aes128-ctr", "aes256-ctr", "aes128-cbc"}},
+		PasswordCallback: func(Meta ssh.ConnMetadata, Password []byte) (*ssh.Permissions, error) {
+			if Meta.User() == s.User && string(Password) == s.Password {
+				return nil, nil
+			}
+			return nil, errors.New("password rejected for " + Meta.User())
+		},
+	}
+	Config.AddHostKey(HostKey)
+	ServerConn, Channels, Requests, err := ssh.NewServerConn(Conn, Config)
+	if err != nil {
+		return
+	}
+	defer ServerConn.Close()
+	go ssh.DiscardRequests(Requests)
+	for NewChannel := range Channels {
+		if NewChannel.ChannelType() != "session" {
+			NewChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		Channel, ChannelRequests, err := NewChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.serveSession(Channel, ChannelRequests)
+	}
+}
+
+//serveSession starts the netconf subsystem or the CLI shell requested on the session
+func (s *Switch) serveSession(Channel ssh.Channel, Requests <-chan *ssh.Request) {
+	for Request := range Requests {
+		switch Request.Type {
+		case "subsystem":
+			var Subsystem struct{ Name string }
+			if err := ssh.Unmarshal(Request.Payload, &Subsystem); err != nil || Subsystem.Name != "netconf" {
+				Request.Reply(false, nil)
+				continue
+			}
+			Request.Reply(true, nil)
+			go s.serveNetconf(Channel)
+		case "shell":
+			Request.Reply(true, nil)
+			go s.serveShell(Channel)
+		case "pty-req", "env":
+			Request.Reply(true, nil)
+		default:
+			Request.Reply(false, nil)
+		}
+	}
+}
+
+//readMessage reads a NETCONF message up to its separator
+func readMessage(Reader *bufio.Reader) (string, error) {
+	var Message strings.Builder
+	for !strings.HasSuffix(Message.String(), netconfSeparator) {
+		Chunk, err := Reader.ReadString('>')
+		if err != nil {
+			return "", err
+		}
+		Message.WriteString(Chunk)
+	}
+	return strings.TrimSpace(strings.TrimSuffix(Message.String(), netconfSeparator)), nil
+}
+
+//serveNetconf sends the hello of the switch and replies to the RPCs of the session until it is closed
+func (s *Switch) serveNetconf(Channel ssh.Channel) {
+	defer Channel.Close()
+	s.mutex.Lock()
+	s.sessions++
+	SessionID := s.sessions
+	s.mutex.Unlock()
+	if _, err := fmt.Fprintf(Channel, netconfHello, SessionID); err != nil {
+		return
+	}
+
+	Reader := bufio.NewReader(Channel)
+	for {
+		Message, err := readMessage(Reader)
+		if err != nil {
+			return
+		}
+		Document := etree.NewDocument()
+		if err := Document.ReadFromString(Message); err != nil {
+			continue
+		}
+		//The hello of the client has no reply
+		RPC := Document.SelectElement("rpc")
+		if RPC == nil {
+			continue
+		}
+		Reply, Closed := "<ok/>", false
+		Operations := RPC.ChildElements()
+		switch {
+		case len(Operations) == 0:
+			Reply = rpcErrorReply(&rpcError{Tag: "missing-element", Message: "rpc has no operation"})
+		case Operations[0].Tag == "close-session":
+			Closed = true
+		default:
+			var Error *rpcError
+			if Reply, Error = s.execute(Operations[0]); Error != nil {
+				Reply = rpcErrorReply(Error)
+			}
+		}
+		if _, err := io.WriteString(Channel, `<?xml version="1.0" encoding="UTF-8"?><rpc-reply message-id="`+
+			RPC.SelectAttrValue("message-id", "")+`" xmlns="urn:ietf:params:xml:ns:netconf:base:1.0">`+
+			Reply+"</rpc-reply>"+netconfSeparator); err != nil || Closed {
+			return
+		}
+	}
+}
+
+func rpcErrorReply(Error *rpcError) string {
+	Element := etree.NewElement("rpc-error")
+	leaf(Element, "error-type", "application")
+	leaf(Element, "error-tag", Error.Tag)
+	leaf(Element, "error-severity", "error")
+	leaf(Element, "error-message", Error.Message)
+	return serialize(Element)
+}
+
+//serveShell runs the commands of the SSH client, writing their output followed by the marker they echo
+func (s *Switch) serveShell(Channel ssh.Channel) {
+	defer Channel.Close()
+	Scanner := bufio.NewScanner(Channel)
+	ConfigMode := false
+	for Scanner.Scan() {
+		Line := strings.TrimSpace(Scanner.Text())
+		Match := cliMarker.FindStringSubmatch(Line)
+		switch {
+		case Match != nil:
+			if _, err := io.WriteString(Channel, s.cli(strings.TrimSpace(Match[1]), ConfigMode)+Match[3]+"\n"); err != nil {
+				return
+			}
+		case Line == "configure terminal":
+			ConfigMode = true
+		case Line == "end":
+			ConfigMode = false
+		case Line == "exit" && ConfigMode:
+			ConfigMode = false
+		case Line == "exit":
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//invalidInput is the output of the SLX CLI for the commands it does not know
+const invalidInput = "% Invalid input detected at '^' marker.\n"
+
+//cli runs a command of the SLX CLI, in the configuration mode or not, and returns its output
+func (s *Switch) cli(Command string, ConfigMode bool) string {
+	Fields := strings.Fields(Command)
+	if ConfigMode {
+		if len(Fields) == 2 && Fields[0] == "vlan" {
+			return s.configureVlan(Fields[1])
+		}
+		return invalidInput
+	}
+	switch {
+	case Command == "show version":
+		return fmt.Sprintf("SLX-OS Operating System Software\nFirmware name:      %s\nSwitch type:        %s\n",
+			s.Firmware, s.Model)
+	case Command == "show cluster management":
+		return s.showManagementCluster()
+	case len(Fields) == 4 && strings.Join(Fields[:3], " ") == "cluster management node-id":
+		if _, err := strconv.ParseUint(Fields[3], 10, 32); err != nil {
+			return invalidInput
+		}
+		s.mutex.Lock()
+		s.nodeID = Fields[3]
+		s.mutex.Unlock()
+		return ""
+	case len(Fields) == 5 && strings.Join(Fields[:4], " ") == "clear bgp evpn neighbor":
+		return ""
+	case Command == "show running-config vlan":
+		return s.showVlans()
+	}
+	return invalidInput
+}
+
+func (s *Switch) configureVlan(Name string) string {
+	if _, err := strconv.ParseUint(Name, 10, 16); err != nil {
+		return invalidInput
+	}
+	if err := s.EditConfig(`<config><interface-vlan xmlns="urn:brocade.com:mgmt:brocade-interface"><interface>` +
+		`<vlan><name>` + Name + `</name></vlan></interface></interface-vlan></config>`); err != nil {
+		return err.Error() + "\n"
+	}
+	return ""
+}
+
+func (s *Switch) showVlans() string {
+	Vlans := make([]int, 0)
+	for _, Name := range s.snapshot().FindElements("/interface-vlan/interface/vlan/name") {
+		if ID, err := strconv.Atoi(strings.TrimSpace(Name.Text())); err == nil {
+			Vlans = append(Vlans, ID)
+		}
+	}
+	sort.Ints(Vlans)
+	var Output strings.Builder
+	for _, ID := range Vlans {
+		fmt.Fprintf(&Output, "vlan %d\n!\n", ID)
+	}
+	return Output.String()
+}
+
+//showManagementCluster prints the nodes of the management cluster, the local node marked with a star
+func (s *Switch) showManagementCluster() string {
+	Members := s.clusterMembers()
+	var Output strings.Builder
+	fmt.Fprintf(&Output, "Total Number of Nodes: %d\n\n", len(Members))
+	Output.WriteString("Node ID  Switch MAC         Public IP        Status\n")
+	for _, Member := range Members {
+		Local := ""
+		if Member == s {
+			Local = "*"
+		}
+		fmt.Fprintf(&Output, "%s %s %s Connected to Cluster%s\n", Member.node(), Member.mac(0), Member.IP, Local)
+	}
+	return Output.String()
+}
@@ -0,0 +1,283 @@
+package simulator
+
+import (
+	"context"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/device/client"
+	"efa-server/test/functional"
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+var Password = functional.DeviceAdminPassword
+
+func startNetwork(t *testing.T, Network *Network) {
+	if err := Network.Start(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func login(t *testing.T, Host string) *client.NetconfClient {
+	Client := &client.NetconfClient{Host: Host, User: "admin", Password: Password}
+	if err := Client.Login(); err != nil {
+		t.Fatal(err)
+	}
+	return Client
+}
+
+func parse(XML string) *etree.Element {
+	Document := etree.NewDocument()
+	Document.ReadFromString(XML)
+	return Document.Root()
+}
+
+func TestEditConfig_MergeAndRemove(t *testing.T) {
+	Running := etree.NewDocument()
+	assert.NoError(t, editConfig(Running, parse(`<config><interface-vlan xmlns="urn:brocade.com:mgmt:brocade-interface">`+
+		`<interface><vlan><name>10</name><description>ten</description></vlan><vlan><name>20</name></vlan>`+
+		`</interface></interface-vlan></config>`)))
+
+	assert.NoError(t, editConfig(Running, parse(`<config><interface-vlan><interface><vlan><name>10</name>`+
+		`<description>TEN</description></vlan><vlan operation="delete"><name>20</name></vlan>`+
+		`<vlan operation="remove"><name>30</name></vlan></interface></interface-vlan></config>`)))
+
+	Vlans := Running.FindElements("/interface-vlan/interface/vlan")
+	if assert.Equal(t, 1, len(Vlans)) {
+		assert.Equal(t, "TEN", Vlans[0].SelectElement("description").Text())
+	}
+	assert.Equal(t, "urn:brocade.com:mgmt:brocade-interface", Running.Root().SelectAttrValue("xmlns", ""))
+
+	assert.Equal(t, errMalformedConfig, editConfig(Running, parse(`<interface-vlan/>`)))
+}
+
+func TestEditConfig_KeyOrder(t *testing.T) {
+	Running := etree.NewDocument()
+	for _, Name := range []string{"100", "9", "20"} {
+		assert.NoError(t, editConfig(Running, parse(`<config><interface-vlan><interface><vlan><name>`+Name+
+			`</name></vlan></interface></interface-vlan></config>`)))
+	}
+	Names := make([]string, 0)
+	for _, Name := range Running.FindElements("/interface-vlan/interface/vlan/name") {
+		Names = append(Names, Name.Text())
+	}
+	assert.Equal(t, []string{"9", "20", "100"}, Names)
+
+	assert.True(t, compareKey("4.4.3.4/32", "4.4.4.4/32") < 0)
+	assert.True(t, compareKey("10.0.0.1", "9.0.0.1") > 0)
+	assert.True(t, compareKey("0/2", "0/10") < 0)
+	assert.Equal(t, 0, compareKey("0/2", "0/2"))
+}
+
+func TestEditConfig_FixedLeaf(t *testing.T) {
+	AnycastGateway := func(Mac string, Operation string) *etree.Element {
+		return parse(`<config><routing-system><ip><static-ag-ip-config><anycast-gateway-mac>` +
+			`<ip-anycast-gateway-mac` + Operation + `>` + Mac + `</ip-anycast-gateway-mac>` +
+			`</anycast-gateway-mac></static-ag-ip-config></ip></routing-system></config>`)
+	}
+	Running := etree.NewDocument()
+	assert.NoError(t, editConfig(Running, AnycastGateway("0201.0101.0101", "")))
+	assert.NoError(t, editConfig(Running, AnycastGateway("0201.0101.0101", "")))
+	assert.Error(t, editConfig(Running, AnycastGateway("0201.0101.0104", "")))
+	assert.Equal(t, "0201.0101.0101", Running.FindElement("//ip-anycast-gateway-mac").Text())
+
+	assert.NoError(t, editConfig(Running, AnycastGateway("", ` operation="remove"`)))
+	assert.NoError(t, editConfig(Running, AnycastGateway("0201.0101.0104", "")))
+	assert.Equal(t, "0201.0101.0104", Running.FindElement("//ip-anycast-gateway-mac").Text())
+}
+
+func TestEditConfig_EmptyKey(t *testing.T) {
+	Running := etree.NewDocument()
+	assert.Error(t, editConfig(Running, parse(`<config><router><router-bgp><neighbor><neighbor-ips><neighbor-addr>`+
+		`<router-bgp-neighbor-address></router-bgp-neighbor-address><remote-as>65000</remote-as></neighbor-addr>`+
+		`</neighbor-ips></neighbor></router-bgp></router></config>`)))
+	assert.Nil(t, Running.FindElement("//neighbor-addr"))
+}
+
+func TestGetConfig_Filter(t *testing.T) {
+	Running := etree.NewDocument()
+	assert.NoError(t, editConfig(Running, parse(`<config><routing-system xmlns="urn:brocade.com:mgmt:brocade-common-def">`+
+		`<interface><loopback><id>1</id><ip><ip-config><address><address>1.1.1.1/32</address></address></ip-config>`+
+		`</ip></loopback><loopback><id>2</id></loopback></interface></routing-system></config>`)))
+
+	Data, err := getConfig(Running, "/routing-system/interface/loopback/ip")
+	assert.NoError(t, err)
+	assert.Equal(t, `<data><routing-system xmlns="urn:brocade.com:mgmt:brocade-common-def"><interface><loopback>`+
+		`<id>1</id><ip><ip-config><address><address>1.1.1.1/32</address></address></ip-config></ip></loopback>`+
+		`</interface></routing-system></data>`, serialize(Data))
+
+	Data, err = getConfig(Running, "/routing-system/interface/ve")
+	assert.NoError(t, err)
+	assert.Equal(t, "<data></data>", serialize(Data))
+}
+
+func TestDeviceDetail(t *testing.T) {
+	Switches := map[string]*Switch{
+		"Avalanche": NewSwitch("192.0.2.1", ad.AvalancheType, Firmwares[ad.AvalancheType], 4),
+		"Freedom":   NewSwitch("192.0.2.2", ad.FreedomType, Firmwares[ad.FreedomType], 4),
+		"Cedar":     NewSwitch("192.0.2.3", ad.CedarType, Firmwares[ad.CedarType], 4),
+		"Orca":      NewSwitch("192.0.2.4", ad.OrcaType, Firmwares[ad.OrcaType], 4),
+	}
+	Network := NewNetwork()
+	for _, Switch := range Switches {
+		Network.Add(Switch)
+	}
+	startNetwork(t, Network)
+	defer Network.Stop()
+
+	for name, Switch := range Switches {
+		t.Run(name, func(t *testing.T) {
+			Client := login(t, Switch.IP)
+			defer Client.Close()
+			Detail, err := ad.GetDeviceDetail(Client)
+			assert.NoError(t, err)
+			assert.Equal(t, Switch.Firmware, Detail.FirmwareVersion)
+			assert.Equal(t, Switch.Model+"_"+osVersion.FindString(Switch.Firmware), Detail.Model)
+			assert.NoError(t, ad.CheckSupportedVersion(Detail.Model))
+		})
+	}
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	Network := NewNetwork(NewSwitch("192.0.2.1", ad.FreedomType, Firmwares[ad.FreedomType], 4))
+	startNetwork(t, Network)
+	defer Network.Stop()
+
+	Client := &client.NetconfClient{Host: "192.0.2.1", User: "admin", Password: "wrong"}
+	assert.Error(t, Client.Login())
+}
+
+func TestLLDPAndInterfaces(t *testing.T) {
+	One := NewSwitch("192.0.2.1", ad.FreedomType, Firmwares[ad.FreedomType], 4)
+	Two := NewSwitch("192.0.2.2", ad.CedarType, Firmwares[ad.CedarType], 4)
+	Network := NewNetwork(One, Two)
+	assert.NoError(t, Network.Cable(One, "0/2", Two, "0/3"))
+	assert.Error(t, Network.Cable(One, "0/2", Two, "0/4"))
+	assert.Error(t, Network.Cable(One, "0/9", Two, "0/4"))
+	startNetwork(t, Network)
+	defer Network.Stop()
+
+	Client := login(t, One.IP)
+	defer Client.Close()
+	Detail, _ := ad.GetDeviceDetail(Client)
+	Adapter := ad.GetAdapter(Detail.Model)
+
+	Neighbors, err := Adapter.GetLLDPNeighbors(Client)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(Neighbors)) {
+		assert.Equal(t, "0/2", Neighbors[0].LocalInterfaceName)
+		assert.Equal(t, "0/3", Neighbors[0].RemoteInterfaceName)
+		assert.Equal(t, One.Port("0/2").Mac, Neighbors[0].LocalInterfaceMac)
+		assert.Equal(t, Two.Port("0/3").Mac, Neighbors[0].RemoteInterfaceMac)
+	}
+
+	Interfaces, err := Adapter.GetInterfaces(Client, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(Interfaces))
+
+	//A port shut down has no neighbour
+	assert.NoError(t, One.EditConfig(`<config><interface><ethernet><name>0/2</name><shutdown/></ethernet></interface></config>`))
+	Neighbors, err = Adapter.GetLLDPNeighbors(Client)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(Neighbors))
+}
+
+func TestUnsupportedRPC(t *testing.T) {
+	Network := NewNetwork(NewSwitch("192.0.2.1", ad.FreedomType, Firmwares[ad.FreedomType], 4))
+	startNetwork(t, Network)
+	defer Network.Stop()
+
+	Client := login(t, "192.0.2.1")
+	defer Client.Close()
+	_, err := Client.ExecuteRPC(`<get-system-uptime xmlns="urn:brocade.com:mgmt:brocade-system"/>`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not simulated")
+}
+
+func TestCLI(t *testing.T) {
+	One := NewSwitch("192.0.2.1", ad.FreedomType, Firmwares[ad.FreedomType], 4)
+	Two := NewSwitch("192.0.2.2", ad.FreedomType, Firmwares[ad.FreedomType], 4)
+	Network := NewNetwork(One, Two)
+	assert.NoError(t, Network.Pair(One, Two))
+	startNetwork(t, Network)
+	defer Network.Stop()
+
+	SSHClient := &client.SSHClient{Host: One.IP, User: "admin", Password: Password}
+	assert.NoError(t, SSHClient.Login())
+	defer SSHClient.Close()
+
+	assert.Contains(t, SSHClient.ExecuteOperationalCommand("show version "), "Firmware name:      18s.1.01b")
+
+	SSHClient.ExecuteConfigCommand("vlan 100")
+	assert.Contains(t, SSHClient.ExecuteOperationalCommand("show running-config vlan "), "vlan 100")
+
+	assert.Equal(t, "", SSHClient.ExecuteOperationalCommand("cluster management node-id 123"))
+	Output := SSHClient.ExecuteOperationalCommand("show cluster management")
+	assert.Contains(t, Output, "123 "+One.mac(0)+" 192.0.2.1 Connected to Cluster*")
+	assert.NotContains(t, Output, "192.0.2.2")
+
+	//The management cluster forms over the port-channel between the MCT peers
+	for _, Switch := range []*Switch{One, Two} {
+		assert.NoError(t, Switch.EditConfig(`<config><interface><port-channel><name>1</name></port-channel>`+
+			`<ethernet><name>0/1</name><channel-group><port-int>1</port-int></channel-group></ethernet></interface></config>`))
+	}
+	Output = SSHClient.ExecuteOperationalCommand("show cluster management")
+	assert.Contains(t, Output, "1 "+Two.mac(0)+" 192.0.2.2 Connected to Cluster\n")
+
+	assert.NoError(t, Two.EditConfig(`<config><interface><port-channel operation="delete"><name>1</name>`+
+		`</port-channel></interface></config>`))
+	assert.NotContains(t, SSHClient.ExecuteOperationalCommand("show cluster management"), "192.0.2.2")
+
+	assert.True(t, strings.HasPrefix(SSHClient.ExecuteOperationalCommand("show bogus"), "% Invalid input"))
+}
+
+func TestConfigureFabric_CLOS(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + "Simulator")
+	defer func() {
+		database.GetWorkingInstance().Close()
+		os.Remove(constants.TESTDBLocation + "Simulator")
+	}()
+
+	Spine := NewSwitch("192.0.2.1", ad.CedarType, Firmwares[ad.CedarType], 8)
+	Leaf1 := NewSwitch("192.0.2.11", ad.FreedomType, Firmwares[ad.FreedomType], 8)
+	Leaf2 := NewSwitch("192.0.2.12", ad.FreedomType, Firmwares[ad.FreedomType], 8)
+	Network, err := NewCLOS([]*Switch{Spine}, []*Switch{Leaf1, Leaf2}, [][2]*Switch{{Leaf1, Leaf2}})
+	assert.NoError(t, err)
+	startNetwork(t, Network)
+	defer Network.Stop()
+	PollingInterval := actions.MgmtClusterStatePollingIntervalInSec
+	actions.MgmtClusterStatePollingIntervalInSec = 1
+	defer func() { actions.MgmtClusterStatePollingIntervalInSec = PollingInterval }()
+
+	devUC := infra.GetUseCaseInteractor()
+	devUC.AddFabric(context.Background(), constants.DefaultFabric)
+
+	_, err = devUC.AddDevices(context.Background(), constants.DefaultFabric, []string{Leaf1.IP, Leaf2.IP},
+		[]string{Spine.IP}, "admin", Password, false)
+	assert.NoError(t, err)
+
+	_, err = devUC.ValidateFabricTopology(context.Background(), constants.DefaultFabric)
+	assert.NoError(t, err)
+
+	_, err = devUC.ConfigureFabric(context.Background(), constants.DefaultFabric, false, true)
+	assert.NoError(t, err)
+
+	for _, Switch := range []*Switch{Spine, Leaf1, Leaf2} {
+		assert.Contains(t, Switch.RunningConfig(), "<router-bgp", Switch.IP)
+		assert.Equal(t, Switch.RunningConfig(), Switch.StartupConfig(), Switch.IP)
+	}
+	for _, Leaf := range []*Switch{Leaf1, Leaf2} {
+		assert.Contains(t, Leaf.RunningConfig(), "<cluster-name>", Leaf.IP)
+		assert.Contains(t, Leaf.RunningConfig(), "<overlay-gateway", Leaf.IP)
+	}
+
+	Health, err := devUC.FabricHealth(context.Background(), constants.DefaultFabric)
+	assert.NoError(t, err)
+	assert.True(t, Health.Healthy, "%+v", Health)
+}
@@ -0,0 +1,177 @@
+package simulator
+
+import (
+	ad "efa-server/infra/device/adapter"
+	"efa-server/test/functional"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/beevik/etree"
+)
+
+//osVersion matches the os-version of a firmware-full-version, without its patch letters
+var osVersion = regexp.MustCompile(`^\d+[rsx]?\.[0-9]+\.[0-9]+`)
+
+//modelDefaults are the leaves of the running-config a model reports without being configured
+var modelDefaults = map[string]string{
+	ad.AvalancheType: `<config><mac-address-table xmlns="urn:brocade.com:mgmt:brocade-mac-address-table"><aging-time>` +
+		`<conversational-time-out>300</conversational-time-out></aging-time></mac-address-table></config>`,
+}
+
+//Port is a physical ethernet port of a simulated switch
+type Port struct {
+	Name  string
+	Speed string //the actual-line-speed, such as "100Gbps"
+	Mac   string
+}
+
+//Switch is a simulated SLX device answering NETCONF and the SLX CLI over SSH on its management IP address.
+//Its running-config is updated by the edit-config RPCs and its operational state is derived from the
+//running-config and from the cabling of its network.
+type Switch struct {
+	IP       string
+	Model    string //the switch-type, such as adapter.FreedomType
+	Firmware string //the firmware-full-version, such as "18s.1.01b"
+	User     string
+	Password string
+	Ports    []Port
+
+	mutex    sync.Mutex
+	running  *etree.Document
+	startup  *etree.Document
+	nodeID   string
+	sessions int
+	persists int
+	network  *Network
+}
+
+//NewSwitch returns a switch of the model running the firmware, with the ports 0/1 to 0/PortCount running at
+//100Gbps. Its credentials are the ones of the functional tests.
+func NewSwitch(IP string, Model string, Firmware string, PortCount int) *Switch {
+	Switch := &Switch{IP: IP, Model: Model, Firmware: Firmware, User: "admin",
+		Password: functional.DeviceAdminPassword, nodeID: "1", running: etree.NewDocument()}
+	Interfaces := Switch.running.CreateElement("interface")
+	Interfaces.CreateAttr("xmlns", "urn:brocade.com:mgmt:brocade-interface")
+	for iter := 1; iter <= PortCount; iter++ {
+		Port := Port{Name: fmt.Sprintf("0/%d", iter), Speed: "100Gbps", Mac: Switch.mac(iter)}
+		Switch.Ports = append(Switch.Ports, Port)
+		Interfaces.CreateElement("ethernet").CreateElement("name").SetText(Port.Name)
+	}
+	if Defaults, found := modelDefaults[Model]; found {
+		Switch.EditConfig(Defaults)
+	}
+	Switch.startup = Switch.running.Copy()
+	return Switch
+}
+
+//mac returns the MAC address of a port, the switch MAC address for the port 0
+func (s *Switch) mac(Port int) string {
+	IP := net.ParseIP(s.IP).To4()
+	if IP == nil {
+		IP = net.IPv4zero.To4()
+	}
+	return net.HardwareAddr{0x02, IP[0], IP[1], IP[2], IP[3], byte(Port)}.String()
+}
+
+//Port returns the port of the switch with the name, nil when there is none
+func (s *Switch) Port(Name string) *Port {
+	for iter := range s.Ports {
+		if s.Ports[iter].Name == Name {
+			return &s.Ports[iter]
+		}
+	}
+	return nil
+}
+
+//EditConfig merges the config element into the running-config as an edit-config RPC would, to prepare
+//the switch for a test
+func (s *Switch) EditConfig(Config string) error {
+	Document := etree.NewDocument()
+	if err := Document.ReadFromString(Config); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return editConfig(s.running, Document.Root())
+}
+
+//RunningConfig returns the running-config of the switch
+func (s *Switch) RunningConfig() string {
+	Config, _ := s.snapshot().WriteToString()
+	return Config
+}
+
+//StartupConfig returns the startup-config of the switch, the running-config when it was last persisted
+func (s *Switch) StartupConfig() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	Config, _ := s.startup.WriteToString()
+	return Config
+}
+
+//snapshot returns a copy of the running-config, read without holding the lock of the switch while the
+//operational state of the other switches of the network is looked up
+func (s *Switch) snapshot() *etree.Document {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.running.Copy()
+}
+
+//shutdown tells whether the interface of the running-config is shut down
+func shutdown(Interface *etree.Element) bool {
+	return Interface.SelectElement("shutdown") != nil
+}
+
+//interfaceAddress returns the IPv4 address configured on the interface of the running-config, empty if
+//there is none
+func interfaceAddress(Interface *etree.Element) string {
+	if Address := Interface.FindElement("ip/ip-config/address/address"); Address != nil {
+		return strings.TrimSpace(Address.Text())
+	}
+	return ""
+}
+
+//addresses returns the IPv4 addresses configured on the interfaces of the running-config, without their mask
+func addresses(Running *etree.Document) []string {
+	Addresses := make([]string, 0)
+	for _, Path := range []string{"/interface/ethernet", "/routing-system/interface/loopback",
+		"/routing-system/interface/ve"} {
+		for _, Interface := range Running.FindElements(Path) {
+			if Address := interfaceAddress(Interface); Address != "" {
+				Addresses = append(Addresses, strings.Split(Address, "/")[0])
+			}
+		}
+	}
+	return Addresses
+}
+
+//owns tells whether the address is configured on an interface of the switch
+func (s *Switch) owns(Address string) bool {
+	for _, Owned := range addresses(s.snapshot()) {
+		if Owned == Address {
+			return true
+		}
+	}
+	return false
+}
+
+//vtep returns the address of the VTEP loopback of the activated overlay gateway, empty if there is none
+func vtep(Running *etree.Document) string {
+	Gateway := Running.FindElement("/overlay-gateway")
+	if Gateway == nil || Gateway.SelectElement("activate") == nil {
+		return ""
+	}
+	LoopbackID := Gateway.FindElement("ip/interface/loopback/loopback-id")
+	if LoopbackID == nil {
+		return ""
+	}
+	Loopback := Running.FindElement(fmt.Sprintf("/routing-system/interface/loopback[id='%s']",
+		strings.TrimSpace(LoopbackID.Text())))
+	if Loopback == nil {
+		return ""
+	}
+	return strings.Split(interfaceAddress(Loopback), "/")[0]
+}
@@ -26,7 +26,7 @@ func TestASNPool_FailedPopulateASN(t *testing.T) {
 		MockGetFabric: func(FabricName string) (domain.Fabric, error) {
 			return domain.Fabric{Name: FabricName, ID: 1}, nil
 		},
-		MockCreateASN: func(ASN *domain.ASNAllocationPool) error {
+		MockCreateAllocationRange: func(Range *domain.AllocationRange) error {
 			return errors.New("Unable to fetch ASN")
 		},
 	}
@@ -40,8 +40,8 @@ func TestASNPool_GetASN(t *testing.T) {
 		MockGetFabric: func(FabricName string) (domain.Fabric, error) {
 			return domain.Fabric{Name: FabricName, ID: 1}, nil
 		},
-		MockGetNextASNForRole: func(FabricID uint, role string) (domain.ASNAllocationPool, error) {
-			return domain.ASNAllocationPool{ASN: uint64(120)}, nil
+		MockGetAllocationRanges: func(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error) {
+			return []domain.AllocationRange{{StartValue: 120, EndValue: 130}}, nil
 		},
 	}
 
@@ -56,8 +56,8 @@ func TestASNPool_GetASNExhausted(t *testing.T) {
 		MockGetFabric: func(FabricName string) (domain.Fabric, error) {
 			return domain.Fabric{Name: FabricName, ID: 1}, nil
 		},
-		MockGetNextASNForRole: func(FabricID uint, role string) (domain.ASNAllocationPool, error) {
-			return domain.ASNAllocationPool{}, errors.New("Exhausted")
+		MockGetAllocationRanges: func(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error) {
+			return []domain.AllocationRange{}, nil
 		},
 	}
 
@@ -43,6 +43,10 @@ func TestDeviceAdd_SwitchAlreadyExist(t *testing.T) {
 		MockGetDevice: func(FabricName string, IPAddress string) (domain.Device, error) {
 			return domain.Device{IPAddress: IPAddress, UserName: UserName}, nil
 		},
+		MockGetAllocationRanges: func(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error) {
+			return []domain.AllocationRange{{FabricID: FabricID, PoolType: PoolType, PoolName: PoolName,
+				StartValue: 64512, EndValue: 64512}}, nil
+		},
 	}
 
 	devUC := usecase.DeviceInteractor{Db: &MockDatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
@@ -7,7 +7,6 @@ import (
 	"efa-server/infra/database"
 	"efa-server/infra/logging"
 	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
 )
 
@@ -66,6 +65,5 @@ func TestLogMessageReceivedAndFailed(t *testing.T) {
 }
 
 func cleanupDB(Database *database.Database) {
-	Database.Close()
-	os.Remove(AuditDBName)
+	Database.Drop()
 }
@@ -15,7 +15,6 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"net"
-	"os"
 	"sort"
 )
 
@@ -94,7 +93,10 @@ func TestConfigure_OneSpine_OneLeaf(t *testing.T) {
 
 	//Configure Fabric Should return no error
 	cresp, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, true)
-	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName}, cresp)
+	//The health of the fabric once configured is covered by the fabrichealth tests
+	assert.NotNil(t, cresp.Health)
+	cresp.Health = nil
+	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName, Generation: 1}, cresp)
 	assert.NoError(t, err)
 	fmt.Println(cresp, err)
 
@@ -514,7 +516,10 @@ func TestConfigureSpine_And_Two_Node_Leaf_Cluster(t *testing.T) {
 
 	//Configure Fabric Should return no error
 	cresp, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, true)
-	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName}, cresp)
+	//The health of the fabric once configured is covered by the fabrichealth tests
+	assert.NotNil(t, cresp.Health)
+	cresp.Health = nil
+	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName, Generation: 1}, cresp)
 	assert.NoError(t, err)
 }
 
@@ -862,7 +867,10 @@ func TestConfigureSpine_One_Leaf_And_Two_Node_Leaf_Cluster(t *testing.T) {
 
 	//Configure Fabric Should return no error
 	cresp, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, true)
-	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName}, cresp)
+	//The health of the fabric once configured is covered by the fabrichealth tests
+	assert.NotNil(t, cresp.Health)
+	cresp.Health = nil
+	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName, Generation: 1}, cresp)
 	assert.NoError(t, err)
 }
 
@@ -2135,6 +2143,5 @@ func intersectIP(ip1, ip2 string) bool {
 }
 
 func cleanupDB(Database *database.Database) {
-	Database.Close()
-	os.Remove(constants.TESTDBLocation)
+	Database.Drop()
 }
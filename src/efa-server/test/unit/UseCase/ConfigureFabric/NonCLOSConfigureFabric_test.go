@@ -1055,7 +1055,10 @@ func TestConfigure_OneRack_Followed_By_Other(t *testing.T) {
 
 	//Configure Fabric Should return no error
 	cresp, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, true)
-	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName}, cresp)
+	//The health of the fabric once configured is covered by the fabrichealth tests
+	assert.NotNil(t, cresp.Health)
+	cresp.Health = nil
+	assert.Equal(t, usecase.ConfigureFabricResponse{FabricName: MockFabricName, Generation: 1}, cresp)
 	assert.NoError(t, err)
 	fmt.Println(cresp, err)
 
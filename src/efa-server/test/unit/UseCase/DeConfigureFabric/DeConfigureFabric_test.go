@@ -14,7 +14,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	//"os"
 	"fmt"
-	"os"
 	"strconv"
 )
 
@@ -197,29 +196,28 @@ func validateAfterDelete(t *testing.T, interactor *usecase.DeviceInteractor, Fab
 
 	//ASN should be sent back to the Pool
 	asn, _ := strconv.ParseUint(switchconfigAfterAdd.LocalAS, 10, 64)
-	asnCount, _ := DatabaseRepository.GetASNAndCountOnASNAndRole(interactor.FabricID, asn, switchconfigAfterAdd.Role)
+	asnCount, _ := interactor.GetASNCountInPool(context.Background(), interactor.FabricID, asn, switchconfigAfterAdd.Role)
 	assert.Equal(t, int64(1), asnCount)
 
 	//Loopback Interface should be sent back to the Pool
-	loopbackCount, _, _ := DatabaseRepository.GetIPEntryAndCountOnIPAddressAndType(interactor.FabricID, switchconfigAfterAdd.LoopbackIP, "Loopback")
+	loopbackCount, _ := interactor.GetIPCountInPool(context.Background(), interactor.FabricID, switchconfigAfterAdd.LoopbackIP, "Loopback")
 	assert.Equal(t, int64(1), loopbackCount)
 
 	if switchconfigAfterAdd.Role == usecase.LeafRole {
 		//VTEP Loopback Interface should be sent back to the Pool
-		vteploopbackCount, _, _ := DatabaseRepository.GetIPEntryAndCountOnIPAddressAndType(interactor.FabricID, switchconfigAfterAdd.VTEPLoopbackIP, "Loopback")
+		vteploopbackCount, _ := interactor.GetIPCountInPool(context.Background(), interactor.FabricID, switchconfigAfterAdd.VTEPLoopbackIP, "Loopback")
 		assert.Equal(t, int64(1), vteploopbackCount)
 	}
 
 	//Check that all interface IP address has gone back to IP Pair Pool
 	for _, interfaceConfig := range interfaceSwitchConfigs {
 		fmt.Println(interfaceConfig.IPAddress)
-		count, _, _ := DatabaseRepository.GetIPPairEntryAndCountOnEitherIPAddressAndType(interactor.FabricID, interfaceConfig.IPAddress, "P2P")
+		count, _ := interactor.GetIPPairCountInPoolOnEitherIP(context.Background(), interactor.FabricID, interfaceConfig.IPAddress, "P2P")
 		assert.Equal(t, int64(1), count)
 	}
 
 }
 
 func cleanupDB(Database *database.Database) {
-	Database.Close()
-	os.Remove(DeConfigureDBName)
+	Database.Drop()
 }
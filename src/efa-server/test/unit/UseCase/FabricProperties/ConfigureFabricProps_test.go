@@ -10,7 +10,6 @@ import (
 	"efa-server/test/unit/mock"
 	"fmt"
 	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
 )
 
@@ -45,6 +44,7 @@ var FabricUpdateRequestNegative = domain.FabricProperties{
 	DuplicateMacTimer:             "30",
 	DuplicateMaxTimerMaxCount:     "40",
 	FabricType:                    "non-closs",
+	BGPAuthType:                   "tcp-ao",
 }
 
 var FabricUpdateRequestPositive = domain.FabricProperties{
@@ -86,6 +86,8 @@ var FabricUpdateRequestPositive = domain.FabricProperties{
 	FabricType:                    domain.CLOSFabricType,
 	RackPeerEBGPGroup:             "underlay-ebgp-group",
 	RackPeerOvgGroup:              "overlay-ebgp-group",
+	BGPAuthType:                   domain.BGPAuthTypeMD5,
+	PoolWarningThreshold:          "80",
 }
 
 func TestConfigureInvalid(t *testing.T) {
@@ -117,6 +119,7 @@ func TestConfigureInvalid(t *testing.T) {
 		"p2p-link-range":                        "2.2.2/23 is not Valid IP Address , Valid IP in the format w.x.y.z/m",
 		"duplicate-mac-timer-max-count-timeout": "40 is not Valid DuplicateMacTimerMaxCount, Valid ValidateDuplicateMacTimerMaxCount is 3-10",
 		"fabric-type":                           "non-closs is not Valid fabric type, Valid  fabric-type is <clos/non-clos>",
+		"bgp-auth-type":                         "tcp-ao is not Valid BGP authentication type, Valid bgp-auth-type is <md5>",
 	}
 
 	database.Setup(constants.TESTDBLocation + dbExtension)
@@ -151,7 +154,5 @@ func TestConfigureValid(t *testing.T) {
 }
 
 func cleanupDB(Database *database.Database) {
-	Database.Close()
-	err := os.Remove(constants.TESTDBLocation + dbExtension)
-	fmt.Println(err)
+	Database.Drop()
 }
@@ -0,0 +1,171 @@
+package allocationpin
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	MockFabricName      = "efa-test"
+	AllocationPinDBName = constants.TESTDBLocation + "allocation-pin"
+)
+
+func poolFree(t *testing.T, devUC *usecase.DeviceInteractor, PoolType string, PoolName string) uint64 {
+	Response, err := devUC.GetPoolUtilization(context.Background(), MockFabricName)
+	assert.Nil(t, err)
+	for _, Pool := range Response.Pools {
+		if Pool.PoolType == PoolType && Pool.PoolName == PoolName {
+			return Pool.Free
+		}
+	}
+	return 0
+}
+
+func TestAllocationPin_ReservesValues(t *testing.T) {
+	database.Setup(AllocationPinDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	LeafFree := poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole)
+	LoopbackFree := poolFree(t, devUC, domain.PoolTypeIP, "Loopback")
+
+	//The device is pinned ahead of its discovery
+	Pin, _, err := devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeASN, "65000", "")
+	assert.Nil(t, err)
+	assert.Equal(t, usecase.LeafRole, Pin.PoolName)
+	Pin, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeLoopback, "172.31.254.10/32", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "172.31.254.10", Pin.Value)
+	assert.Equal(t, "Loopback", Pin.PoolName)
+
+	assert.Equal(t, LeafFree-1, poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole))
+	assert.Equal(t, LoopbackFree-1, poolFree(t, devUC, domain.PoolTypeIP, "Loopback"))
+
+	//Another leaf is not given the pinned ASN
+	Device := domain.Device{IPAddress: "10.24.80.2", FabricID: Fabric.ID}
+	devUC.Db.CreateDevice(&Device)
+	asn, err := devUC.GetASN(ctx, Fabric.ID, Device.ID, usecase.LeafRole)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(65001), asn)
+
+	Pins, err := devUC.GetAllocationPins(ctx, MockFabricName, "10.24.80.1")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(Pins))
+	Pins, _ = devUC.GetAllocationPins(ctx, MockFabricName, "")
+	assert.Equal(t, 2, len(Pins))
+
+	//A new value replaces the pinned one, which is returned to the pool
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeASN, "65010", "")
+	assert.Nil(t, err)
+	Pins, _ = devUC.GetAllocationPins(ctx, MockFabricName, "10.24.80.1")
+	assert.Equal(t, 2, len(Pins))
+	assert.Equal(t, LeafFree-2, poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole))
+}
+
+func TestAllocationPin_Conflicts(t *testing.T) {
+	database.Setup(AllocationPinDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+
+	_, _, err := devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeASN, "65000", "")
+	assert.Nil(t, err)
+	_, statusMsg, err := devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.2", domain.PinTypeASN, "65000", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	assert.Equal(t, "asn 65000 of 10.24.80.2 is already pinned to 10.24.80.1", statusMsg)
+
+	//The spines share their ASN
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.3", domain.PinTypeASN, "64512", "")
+	assert.Nil(t, err)
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.4", domain.PinTypeASN, "64512", "")
+	assert.Nil(t, err)
+
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.2", domain.PinTypeASN, "70000", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.2", domain.PinTypeLoopback, "10.0.0.1", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.2", "vtep", "10.0.0.1", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.2", domain.PinTypeP2P, "10.10.10.0", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+
+	//The two addresses of a pair are pinned to the two ends of a link
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeP2P, "10.10.10.0", "0/1")
+	assert.Nil(t, err)
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.3", domain.PinTypeP2P, "10.10.10.1", "0/1")
+	assert.Nil(t, err)
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.4", domain.PinTypeP2P, "10.10.10.0", "0/2")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+
+	_, _, err = devUC.SetAllocationPin(ctx, "unknown", "10.24.80.1", domain.PinTypeASN, "65000", "")
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+func TestAllocationPin_Clear(t *testing.T) {
+	database.Setup(AllocationPinDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+	LeafFree := poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole)
+	LoopbackFree := poolFree(t, devUC, domain.PoolTypeIP, "Loopback")
+
+	devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeASN, "65000", "")
+	devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeLoopback, "172.31.254.10", "")
+
+	Cleared, _, err := devUC.ClearAllocationPins(ctx, MockFabricName, "10.24.80.1", domain.PinTypeASN, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(Cleared))
+	assert.Equal(t, LeafFree, poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole))
+	assert.Equal(t, LoopbackFree-1, poolFree(t, devUC, domain.PoolTypeIP, "Loopback"))
+
+	Cleared, _, err = devUC.ClearAllocationPins(ctx, MockFabricName, "10.24.80.1", "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(Cleared))
+	assert.Equal(t, LoopbackFree, poolFree(t, devUC, domain.PoolTypeIP, "Loopback"))
+
+	_, _, err = devUC.ClearAllocationPins(ctx, MockFabricName, "10.24.80.1", "", "")
+	assert.Equal(t, domain.ErrDeviceNotFound, err)
+}
+
+func TestAllocationPin_ValidateReportsConflicts(t *testing.T) {
+	database.Setup(AllocationPinDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+
+	_, _, err := devUC.SetAllocationPin(ctx, MockFabricName, "10.24.80.1", domain.PinTypeLoopback, "172.31.254.10", "")
+	assert.Nil(t, err)
+	Response, err := devUC.ValidateFabricTopology(ctx, MockFabricName)
+	assert.Nil(t, err)
+	assert.Nil(t, Response.PinConflicts)
+
+	//The pinned value is no longer in the Loopback range
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	FabricProperties.LoopBackIPRange = "172.31.253.0/24"
+	DatabaseRepository.UpdateFabricProperties(&FabricProperties)
+	Response, err = devUC.ValidateFabricTopology(ctx, MockFabricName)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"loopback 172.31.254.10 of 10.24.80.1: 172.31.254.10 is not in the Loopback range 172.31.253.0/24"},
+		Response.PinConflicts)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
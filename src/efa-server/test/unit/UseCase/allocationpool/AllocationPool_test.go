@@ -0,0 +1,151 @@
+package allocationpool
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	MockFabricName       = "efa-test"
+	AllocationPoolDBName = constants.TESTDBLocation + "allocation-pool"
+)
+
+func setupInteractor() (*gateway.DatabaseRepository, *usecase.DeviceInteractor, domain.Fabric) {
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	Fabric := domain.Fabric{Name: MockFabricName}
+	devUC.Db.CreateFabric(&Fabric)
+	return DatabaseRepository, devUC, Fabric
+}
+
+//The default rack ASN block is stored as a single range, which is split and merged back on Get and Release
+func TestAllocationPool_ASNRanges(t *testing.T) {
+	database.Setup(AllocationPoolDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository, devUC, Fabric := setupInteractor()
+	ctx := context.Background()
+
+	Devices := make([]domain.Device, 3)
+	for iter := range Devices {
+		Devices[iter] = domain.Device{IPAddress: fmt.Sprintf("IPAddress%d", iter), FabricID: Fabric.ID}
+		devUC.Db.CreateDevice(&Devices[iter])
+	}
+
+	err := devUC.PopulateASN(ctx, MockFabricName, 4200000000, 4200065534, Fabric.ID, usecase.RackRole)
+	assert.Nil(t, err)
+	Ranges, _ := DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeASN, usecase.RackRole)
+	assert.Equal(t, 1, len(Ranges))
+
+	//Populating the same block again does not add any range
+	err = devUC.PopulateASN(ctx, MockFabricName, 4200000000, 4200065534, Fabric.ID, usecase.RackRole)
+	assert.Nil(t, err)
+	Ranges, _ = DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeASN, usecase.RackRole)
+	assert.Equal(t, 1, len(Ranges))
+
+	asnOne, err := devUC.GetASN(ctx, Fabric.ID, Devices[0].ID, usecase.RackRole)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4200000000), asnOne)
+	asnTwo, err := devUC.GetASN(ctx, Fabric.ID, Devices[1].ID, usecase.RackRole)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4200000001), asnTwo)
+
+	//Reserving an ASN in the middle of the block splits the range
+	err = devUC.ReserveASN(ctx, Fabric.ID, Devices[2].ID, usecase.RackRole, 4200000100)
+	assert.Nil(t, err)
+	count, _ := devUC.GetASNCountInPool(ctx, Fabric.ID, 4200000100, usecase.RackRole)
+	assert.Equal(t, int64(0), count)
+	Ranges, _ = DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeASN, usecase.RackRole)
+	assert.Equal(t, 2, len(Ranges))
+
+	//Releasing the ASNs merges the ranges back
+	assert.Nil(t, devUC.ReleaseASN(ctx, Fabric.ID, Devices[0].ID, usecase.RackRole, asnOne))
+	assert.Nil(t, devUC.ReleaseASN(ctx, Fabric.ID, Devices[2].ID, usecase.RackRole, 4200000100))
+	Ranges, _ = DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeASN, usecase.RackRole)
+	assert.Equal(t, 2, len(Ranges))
+	assert.Nil(t, devUC.ReleaseASN(ctx, Fabric.ID, Devices[1].ID, usecase.RackRole, asnTwo))
+	Ranges, _ = DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeASN, usecase.RackRole)
+	assert.Equal(t, 1, len(Ranges))
+	assert.Equal(t, uint64(4200000000), Ranges[0].StartValue)
+	assert.Equal(t, uint64(4200065534), Ranges[0].EndValue)
+}
+
+//The network, broadcast and .254 addresses are left out of each /24 of the IP and IP pair pools
+func TestAllocationPool_IPRanges(t *testing.T) {
+	database.Setup(AllocationPoolDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository, devUC, Fabric := setupInteractor()
+	ctx := context.Background()
+
+	err := devUC.PopulateIP(ctx, MockFabricName, Fabric.ID, "172.31.254.0/23", "Loopback", true)
+	assert.Nil(t, err)
+	Ranges, _ := DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeIP, "Loopback")
+	assert.Equal(t, 2, len(Ranges))
+	for _, ip := range []string{"172.31.254.0", "172.31.254.254", "172.31.254.255", "172.31.255.0"} {
+		count, _ := devUC.GetIPCountInPool(ctx, Fabric.ID, ip, "Loopback")
+		assert.Equal(t, int64(0), count, ip)
+	}
+	ip, err := devUC.GetIP(ctx, Fabric.ID, 1, "Loopback", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "172.31.254.1", ip)
+
+	err = devUC.PopulateIPPairs(ctx, MockFabricName, Fabric.ID, "10.20.0.0/24", domain.MCTPoolName, true)
+	assert.Nil(t, err)
+	count, _ := devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.20.0.0", "10.20.0.1", domain.MCTPoolName)
+	assert.Equal(t, int64(0), count)
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.20.0.253", "10.20.0.252", domain.MCTPoolName)
+	assert.Equal(t, int64(1), count)
+	//Addresses which are not consecutive are not a pair of the pool
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.20.0.3", "10.20.0.4", domain.MCTPoolName)
+	assert.Equal(t, int64(0), count)
+	one, two, err := devUC.GetIPPair(ctx, Fabric.ID, 1, 2, domain.MCTPoolName, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.20.0.2", one)
+	assert.Equal(t, "10.20.0.3", two)
+}
+
+//Resizing a pool keeps the allocated values out of it and only adds or removes the values which changed
+func TestAllocationPool_Resize(t *testing.T) {
+	database.Setup(AllocationPoolDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository, devUC, Fabric := setupInteractor()
+	ctx := context.Background()
+
+	assert.Nil(t, devUC.PopulateASN(ctx, MockFabricName, 65000, 65534, Fabric.ID, usecase.LeafRole))
+	asn, _ := devUC.GetASN(ctx, Fabric.ID, 1, usecase.LeafRole)
+	assert.Equal(t, uint64(65000), asn)
+
+	assert.Nil(t, devUC.ResizeASN(ctx, MockFabricName, "65000-65534", "64900-65100", Fabric.ID, usecase.LeafRole))
+	Ranges, _ := DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeASN, usecase.LeafRole)
+	assert.Equal(t, 2, len(Ranges))
+	assert.Equal(t, uint64(64900), Ranges[0].StartValue)
+	assert.Equal(t, uint64(64999), Ranges[0].EndValue)
+	assert.Equal(t, uint64(65001), Ranges[1].StartValue)
+	assert.Equal(t, uint64(65100), Ranges[1].EndValue)
+
+	assert.Nil(t, devUC.PopulateIPPairs(ctx, MockFabricName, Fabric.ID, "10.10.10.0/23", "P2P", false))
+	one, two, _ := devUC.GetIPPair(ctx, Fabric.ID, 1, 2, "P2P", 1, 2)
+	assert.Nil(t, devUC.ResizeIPPairs(ctx, MockFabricName, Fabric.ID, "10.10.10.0/23", "10.10.10.0/24", "P2P", false))
+	count, _ := devUC.GetIPPairCountInPool(ctx, Fabric.ID, one, two, "P2P")
+	assert.Equal(t, int64(0), count)
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.10.10.254", "10.10.10.255", "P2P")
+	assert.Equal(t, int64(1), count)
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.10.11.0", "10.10.11.1", "P2P")
+	assert.Equal(t, int64(0), count)
+
+	//An empty network empties the pool
+	assert.Nil(t, devUC.ResizeIPPairs(ctx, MockFabricName, Fabric.ID, "10.10.10.0/24", "", "P2P", false))
+	Ranges, _ = DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeIPPair, "P2P")
+	assert.Equal(t, 0, len(Ranges))
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
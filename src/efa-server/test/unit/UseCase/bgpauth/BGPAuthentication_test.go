@@ -0,0 +1,135 @@
+package bgpauth
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "ipaddress_spine1"
+var MockLeaf1IP = "ipaddress_leaf1"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "ba"
+
+func setupInteractor(FabricAdapter *mock.FabricAdapter) (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1", ConfigState: "up"}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			}
+			return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+				LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+				RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: FabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+//Passwords are stored encrypted and returned in clear text, "none" removes a password
+func TestBGPAuthentication_NoDevices(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository, devUC := setupInteractor(&mock.FabricAdapter{})
+
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Equal(t, domain.BGPAuthTypeMD5, FabricProperties.BGPAuthType)
+	assert.Equal(t, "", FabricProperties.PeerGroupPassword)
+
+	ret, err := devUC.RotateBGPPasswords(context.Background(), MockFabricName,
+		&domain.FabricProperties{PeerGroupPassword: "secret1", MctL2EvpnPassword: "secret2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "BGP Authentication updated", ret)
+
+	FabricProperties, _ = DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Equal(t, "secret1", FabricProperties.PeerGroupPassword)
+	assert.Equal(t, "secret2", FabricProperties.MctL2EvpnPassword)
+
+	var StoredProperties database.FabricProperties
+	database.GetWorkingInstance().Instance.Where(&database.FabricProperties{FabricID: Fabric.ID}).First(&StoredProperties)
+	assert.NotEqual(t, "secret1", StoredProperties.PeerGroupPassword)
+	assert.NotEmpty(t, StoredProperties.PeerGroupPassword)
+
+	_, err = devUC.RotateBGPPasswords(context.Background(), MockFabricName,
+		&domain.FabricProperties{PeerGroupPassword: domain.BGPPasswordNone})
+	assert.NoError(t, err)
+	FabricProperties, _ = DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Equal(t, "", FabricProperties.PeerGroupPassword)
+	assert.Equal(t, "secret2", FabricProperties.MctL2EvpnPassword)
+
+	_, err = devUC.RotateBGPPasswords(context.Background(), MockFabricName,
+		&domain.FabricProperties{MctL2EvpnPassword: "secret2"})
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+
+	_, err = devUC.RotateBGPPasswords(context.Background(), "unknown_fabric",
+		&domain.FabricProperties{MctL2EvpnPassword: "secret2"})
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+//Rotation re-keys the spines before the leaves with the new passwords
+func TestBGPAuthentication_Rotation(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var RotatedHosts []operation.ConfigSwitch
+	FabricAdapter := &mock.FabricAdapter{
+		MockRotateBGPPasswords: func(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError {
+			RotatedHosts = config.Hosts
+			return []actions.OperationError{}
+		},
+	}
+	_, devUC := setupInteractor(FabricAdapter)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	_, err = devUC.RotateBGPPasswords(context.Background(), MockFabricName,
+		&domain.FabricProperties{PeerGroupPassword: "secret1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(RotatedHosts))
+	assert.Equal(t, MockSpine1IP, RotatedHosts[0].Host)
+	assert.Equal(t, MockLeaf1IP, RotatedHosts[1].Host)
+	for _, host := range RotatedHosts {
+		assert.Equal(t, domain.BGPAuthTypeMD5, host.BGPAuthType)
+		assert.Equal(t, "secret1", host.PeerGroupPassword)
+	}
+
+	//A failed rotation reports the device it stopped at
+	FabricAdapter.MockRotateBGPPasswords = func(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError {
+		return []actions.OperationError{actions.OperationError{Host: MockSpine1IP, Error: errors.New("unreachable")}}
+	}
+	ret, err := devUC.RotateBGPPasswords(context.Background(), MockFabricName,
+		&domain.FabricProperties{PeerGroupPassword: "secret3"})
+	assert.Equal(t, domain.ErrFabricInternalError, err)
+	assert.Contains(t, ret, MockSpine1IP)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
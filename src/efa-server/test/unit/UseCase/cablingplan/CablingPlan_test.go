@@ -0,0 +1,177 @@
+package cablingplan
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "10.24.39.224"
+var MockLeaf1IP = "10.24.39.225"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "cp"
+
+//setupInteractor adds a fabric whose spine port 1/11 is discovered by LLDP to be connected to the leaf port 1/22
+func setupInteractor(t *testing.T) *usecase.DeviceInteractor {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1", ConfigState: "up"}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			}
+			return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+				LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+				RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter)}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+	return devUC
+}
+
+//An invalid cabling plan is refused and the saved plan is kept
+func TestCablingPlan_Invalid(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	devUC := setupInteractor(t)
+
+	_, _, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "1/11", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/22"}})
+	assert.NoError(t, err)
+
+	for _, Link := range []domain.CablingLink{
+		{DeviceOneIP: "spine1", InterfaceOneName: "1/11", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/22"},
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/22"},
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "1/11", DeviceTwoIP: MockSpine1IP, InterfaceTwoName: "1/12"},
+	} {
+		_, _, err = devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{Link})
+		assert.Equal(t, domain.ErrCablingPlanInvalid, err)
+	}
+
+	//The port 1/11 of the spine is cabled twice
+	_, statusMsg, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "1/11", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/22"},
+		{DeviceOneIP: MockLeaf1IP, InterfaceOneName: "1/23", DeviceTwoIP: MockSpine1IP, InterfaceTwoName: "Ethernet 1/11"}})
+	assert.Equal(t, domain.ErrCablingPlanInvalid, err)
+	assert.Equal(t, "Link 2: 10.24.39.224 1/11 is cabled more than once", statusMsg)
+
+	_, _, err = devUC.UpdateCablingPlan(context.Background(), "unknown_fabric", []domain.CablingLink{})
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+
+	Plan, err := devUC.GetCablingPlan(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(Plan))
+}
+
+//The links discovered by LLDP match the cabling plan, whatever the interface type written in the plan
+func TestCablingPlan_Matched(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	devUC := setupInteractor(t)
+
+	Links, statusMsg, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{
+		{DeviceOneIP: MockLeaf1IP, InterfaceOneName: "eth1/22", DeviceTwoIP: MockSpine1IP, InterfaceTwoName: "Ethernet 1/11"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Cabling plan of Fabric test_fabric updated with 1 links", statusMsg)
+	assert.Equal(t, "1/22", Links[0].InterfaceOneName)
+	assert.Equal(t, "1/11", Links[0].InterfaceTwoName)
+
+	Plan, err := devUC.GetCablingPlan(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(Plan))
+	assert.Equal(t, MockLeaf1IP, Plan[0].DeviceOneIP)
+	assert.Equal(t, "1/11", Plan[0].InterfaceTwoName)
+
+	Response, err := devUC.ValidateFabricTopology(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Empty(t, Response.Cabling.Miscabled)
+	assert.Empty(t, Response.Cabling.Missing)
+	assert.Empty(t, Response.Cabling.Unexpected)
+}
+
+//A port connected to another port than the planned one is miscabled
+func TestCablingPlan_Miscabled(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	devUC := setupInteractor(t)
+
+	_, _, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "1/11", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/23"}})
+	assert.NoError(t, err)
+
+	Response, err := devUC.ValidateFabricTopology(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.24.39.224 1/11 is connected to 10.24.39.225 1/22, planned to 10.24.39.225 1/23"},
+		Response.Cabling.Miscabled)
+	assert.Empty(t, Response.Cabling.Missing)
+	assert.Empty(t, Response.Cabling.Unexpected)
+}
+
+//A planned link neither of whose ports is connected is missing, a discovered link which is not planned is unexpected
+func TestCablingPlan_MissingAndUnexpected(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	devUC := setupInteractor(t)
+
+	_, _, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "1/12", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/24"}})
+	assert.NoError(t, err)
+
+	Response, err := devUC.ValidateFabricTopology(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Empty(t, Response.Cabling.Miscabled)
+	assert.Equal(t, []string{"10.24.39.224 1/12 to 10.24.39.225 1/24 is not connected"}, Response.Cabling.Missing)
+	assert.Equal(t, 1, len(Response.Cabling.Unexpected))
+	assert.Contains(t, Response.Cabling.Unexpected[0], "10.24.39.224 1/11")
+	assert.Contains(t, Response.Cabling.Unexpected[0], "10.24.39.225 1/22")
+}
+
+//A fabric whose cabling plan is deleted has no cabling mismatch
+func TestCablingPlan_Delete(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	devUC := setupInteractor(t)
+
+	_, _, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{
+		{DeviceOneIP: MockSpine1IP, InterfaceOneName: "1/12", DeviceTwoIP: MockLeaf1IP, InterfaceTwoName: "1/24"}})
+	assert.NoError(t, err)
+
+	_, statusMsg, err := devUC.UpdateCablingPlan(context.Background(), MockFabricName, []domain.CablingLink{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Cabling plan of Fabric test_fabric deleted", statusMsg)
+
+	Plan, err := devUC.GetCablingPlan(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Empty(t, Plan)
+
+	Response, err := devUC.ValidateFabricTopology(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Empty(t, Response.Cabling.Missing)
+	assert.Empty(t, Response.Cabling.Unexpected)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
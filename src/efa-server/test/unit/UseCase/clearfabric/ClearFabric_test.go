@@ -13,7 +13,6 @@ import (
 	"efa-server/infra/constants"
 	"fmt"
 	"github.com/stretchr/testify/assert"
-	"os"
 )
 
 var MockSpine1IP = "SPINE1_IP"
@@ -165,6 +164,5 @@ func TestClearSpine_And_Leaf_TestRequestObjects(t *testing.T) {
 }
 
 func cleanupDB(Database *database.Database) {
-	Database.Close()
-	os.Remove(DBName)
+	Database.Drop()
 }
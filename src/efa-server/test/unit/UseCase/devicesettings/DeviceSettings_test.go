@@ -0,0 +1,137 @@
+package devicesettings
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "ipaddress_spine1"
+var MockLeaf1IP = "ipaddress_leaf1"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "ds"
+
+func setupFabric(t *testing.T) (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1", ConfigState: "up"}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			}
+			return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+				LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+				RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: &mock.FabricAdapter{}}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+	return DatabaseRepository, devUC
+}
+
+func getHost(config []operation.ConfigSwitch, IPAddress string) operation.ConfigSwitch {
+	for _, host := range config {
+		if host.Host == IPAddress {
+			return host
+		}
+	}
+	return operation.ConfigSwitch{}
+}
+
+//Overrides of a leaf should only be applied to the leaf, the spine keeps the fabric settings
+func TestDeviceSettings_OverrideActionRequest(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository, devUC := setupFabric(t)
+
+	ret, err := devUC.UpdateDeviceSettings(context.Background(), MockFabricName, MockLeaf1IP,
+		&domain.DeviceSettings{MTU: "9100", BFDTx: "500", BFDEnable: "Yes"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Device Settings updated", ret)
+
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+
+	config, err := devUC.GetActionRequestObject(context.Background(), MockFabricName, false)
+	assert.NoError(t, err)
+	leafConfig := getHost(config.Hosts, MockLeaf1IP)
+	spineConfig := getHost(config.Hosts, MockSpine1IP)
+
+	assert.Equal(t, "9100", leafConfig.Mtu)
+	assert.Equal(t, "500", leafConfig.BfdTx)
+	assert.Equal(t, FabricProperties.BFDRx, leafConfig.BfdRx)
+	assert.Equal(t, FabricProperties.IPMTU, leafConfig.IPMtu)
+	assert.Equal(t, FabricProperties.MTU, spineConfig.Mtu)
+	assert.Equal(t, FabricProperties.BFDTx, spineConfig.BfdTx)
+
+	//"default" removes the override
+	_, err = devUC.UpdateDeviceSettings(context.Background(), MockFabricName, MockLeaf1IP,
+		&domain.DeviceSettings{MTU: usecase.DeviceSettingsDefault})
+	assert.NoError(t, err)
+
+	DeviceSettings, _, err := devUC.GetDeviceSettings(context.Background(), MockFabricName, MockLeaf1IP)
+	assert.NoError(t, err)
+	assert.Equal(t, "", DeviceSettings.MTU)
+	assert.Equal(t, "500", DeviceSettings.BFDTx)
+
+	config, err = devUC.GetActionRequestObject(context.Background(), MockFabricName, false)
+	assert.NoError(t, err)
+	leafConfig = getHost(config.Hosts, MockLeaf1IP)
+	assert.Equal(t, FabricProperties.MTU, leafConfig.Mtu)
+	assert.Equal(t, "500", leafConfig.BfdTx)
+}
+
+func TestDeviceSettings_UnknownDevice(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	_, devUC := setupFabric(t)
+
+	_, err := devUC.UpdateDeviceSettings(context.Background(), MockFabricName, "unknown_ip",
+		&domain.DeviceSettings{MTU: "9100"})
+	assert.Equal(t, domain.ErrDeviceNotFound, err)
+
+	_, _, err = devUC.GetDeviceSettings(context.Background(), MockFabricName, "unknown_ip")
+	assert.Equal(t, domain.ErrDeviceNotFound, err)
+}
+
+func TestDeviceSettings_NoUpdate(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	_, devUC := setupFabric(t)
+
+	_, err := devUC.UpdateDeviceSettings(context.Background(), MockFabricName, MockLeaf1IP, &domain.DeviceSettings{})
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+}
+
+func TestMergeDeviceSettings(t *testing.T) {
+	FabricSettings := domain.FabricProperties{MTU: "9216", MaxPaths: "8", LeafPeerGroup: "spine-group"}
+	Merged := usecase.MergeDeviceSettings(FabricSettings, domain.DeviceSettings{MaxPaths: "16"})
+	assert.Equal(t, domain.FabricProperties{MTU: "9216", MaxPaths: "16", LeafPeerGroup: "spine-group"}, Merged)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -0,0 +1,102 @@
+package errormodel
+
+import (
+	"bytes"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"efa-server/infra/rest/openapi"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var MockFabricName = "default"
+var dbExtension = "errormodel"
+
+func serve(Method string, URL string, Body []byte) *httptest.ResponseRecorder {
+	Recorder := httptest.NewRecorder()
+	openapi.NewRouter().ServeHTTP(Recorder, httptest.NewRequest(Method, URL, bytes.NewReader(Body)))
+	return Recorder
+}
+
+func decodeErrorModel(t *testing.T, Recorder *httptest.ResponseRecorder) Restmodel.ExtendedErrorModel {
+	var ErrorModel Restmodel.ExtendedErrorModel
+	assert.Contains(t, Recorder.Header().Get("Content-Type"), "application/json")
+	assert.NoError(t, json.Unmarshal(Recorder.Body.Bytes(), &ErrorModel))
+	assert.Equal(t, int32(Recorder.Code), ErrorModel.Code)
+	return ErrorModel
+}
+
+//An invalid query parameter is rejected with an ErrorModel instead of an empty success
+func TestErrorModel_InvalidQuery(t *testing.T) {
+	Recorder := serve("GET", "/v1/executions?limit=10&status=unknown", nil)
+	assert.Equal(t, http.StatusBadRequest, Recorder.Code)
+	ErrorModel := decodeErrorModel(t, Recorder)
+	assert.Equal(t, "INVALID_REQUEST", ErrorModel.ErrorCode)
+	assert.Contains(t, ErrorModel.Message, "status")
+}
+
+//The requests matching no route are answered with an ErrorModel
+func TestErrorModel_NotFound(t *testing.T) {
+	Recorder := serve("GET", "/v1/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, Recorder.Code)
+	assert.Equal(t, "NOT_FOUND", decodeErrorModel(t, Recorder).ErrorCode)
+
+	Recorder = serve("DELETE", "/v1/fabric", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, Recorder.Code)
+	assert.Equal(t, "INVALID_REQUEST", decodeErrorModel(t, Recorder).ErrorCode)
+}
+
+//A missing fabric has its own error code
+func TestErrorModel_FabricNotFound(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Recorder := serve("GET", "/v1/fabric?name=missing", nil)
+	assert.Equal(t, http.StatusNotFound, Recorder.Code)
+	assert.Equal(t, "FABRIC_NOT_FOUND", decodeErrorModel(t, Recorder).ErrorCode)
+}
+
+//An unknown execution is not found rather than a failure
+func TestErrorModel_ExecutionNotFound(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Recorder := serve("GET", "/v1/execution?id=missing", nil)
+	assert.Equal(t, http.StatusNotFound, Recorder.Code)
+	ErrorModel := decodeErrorModel(t, Recorder)
+	assert.Equal(t, "NOT_FOUND", ErrorModel.ErrorCode)
+	assert.Contains(t, ErrorModel.Message, "missing")
+}
+
+//The failures of an audited request carry the execution ID, each invalid setting is an error of its own
+func TestErrorModel_InvalidSettings(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Recorder := serve("PUT", "/v1/fabric", []byte("{"))
+	assert.Equal(t, http.StatusBadRequest, Recorder.Code)
+	ErrorModel := decodeErrorModel(t, Recorder)
+	assert.Equal(t, "INVALID_REQUEST", ErrorModel.ErrorCode)
+	assert.NotEmpty(t, ErrorModel.ExecutionId)
+
+	Body, _ := json.Marshal(Restmodel.FabricSettings{Name: MockFabricName,
+		Keyval: []Restmodel.FabricParameter{{Key: "NoSuchSetting", Value: "1"}, {Key: "AnotherSetting", Value: "2"}}})
+	Recorder = serve("PUT", "/v1/fabric", Body)
+	assert.Equal(t, http.StatusBadRequest, Recorder.Code)
+	ErrorModel = decodeErrorModel(t, Recorder)
+	assert.Equal(t, "INVALID_SETTING", ErrorModel.ErrorCode)
+	assert.NotEmpty(t, ErrorModel.ExecutionId)
+	if assert.Len(t, ErrorModel.Errors, 2) {
+		assert.Equal(t, "AnotherSetting", ErrorModel.Errors[0].Field)
+		assert.Equal(t, "NoSuchSetting", ErrorModel.Errors[1].Field)
+		assert.Equal(t, "INVALID_SETTING", ErrorModel.Errors[1].ErrorCode)
+	}
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -0,0 +1,200 @@
+package executionevents
+
+import (
+	"bufio"
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/infra/events"
+	"efa-server/infra/logging"
+	"efa-server/infra/rest/openapi"
+	"efa-server/infra/rest/openapi/handler"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var dbExtension = "executionevents"
+
+//next returns the next event of the subscription
+func next(t *testing.T, Subscription *events.Subscription) domain.ExecutionEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	Event, ok := Subscription.Next(ctx)
+	if !ok {
+		t.Fatal("No event was published")
+	}
+	return Event
+}
+
+//The events of an execution are numbered and replayed to the subscriptions, until the end of the execution
+func TestExecutionEvents_Broker(t *testing.T) {
+	ExecutionID := uuid.New().String()
+	_, ctx := appcontext.LoggerAndContext(ExecutionID)
+
+	//Before the start of the execution the events are dropped
+	events.Publish(ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: "10.24.39.1"})
+	events.Begin(ExecutionID, "fabric configure:ConfigureFabric")
+	events.Publish(ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: "10.24.39.1",
+		Stage: domain.StageSwitchConfigure, Status: domain.ExecutionEventRunning})
+	_, other := appcontext.LoggerAndContext(uuid.New().String())
+	events.Publish(other, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: "10.24.39.2"})
+	events.End(ExecutionID, "fabric configure:ConfigureFabric", domain.ExecutionEventSucceeded, "", nil)
+	events.Publish(ctx, domain.ExecutionEvent{Type: domain.ExecutionEventDevice, Device: "10.24.39.1"})
+
+	Subscription, found := events.Subscribe(context.Background(), ExecutionID)
+	assert.True(t, found)
+	Started := next(t, Subscription)
+	assert.Equal(t, uint64(1), Started.Sequence)
+	assert.Equal(t, domain.ExecutionEventExecution, Started.Type)
+	assert.Equal(t, domain.ExecutionEventRunning, Started.Status)
+	assert.False(t, Started.Finished())
+
+	Device := next(t, Subscription)
+	assert.Equal(t, uint64(2), Device.Sequence)
+	assert.Equal(t, ExecutionID, Device.ExecutionID)
+	assert.Equal(t, "10.24.39.1", Device.Device)
+	assert.Equal(t, domain.StageSwitchConfigure, Device.Stage)
+
+	Ended := next(t, Subscription)
+	assert.Equal(t, uint64(3), Ended.Sequence)
+	assert.True(t, Ended.Finished())
+	_, ok := Subscription.Next(context.Background())
+	assert.False(t, ok)
+}
+
+//A subscription waits for the start of its execution
+func TestExecutionEvents_Subscribe(t *testing.T) {
+	ExecutionID := uuid.New().String()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, found := events.Subscribe(ctx, ExecutionID)
+	assert.False(t, found)
+
+	Subscribed := make(chan *events.Subscription)
+	go func() {
+		Subscription, _ := events.Subscribe(context.Background(), ExecutionID)
+		Subscribed <- Subscription
+	}()
+	time.Sleep(50 * time.Millisecond)
+	events.Begin(ExecutionID, "fabric configure:Add Device")
+	select {
+	case Subscription := <-Subscribed:
+		assert.Equal(t, "fabric configure:Add Device", next(t, Subscription).Command)
+	case <-time.After(5 * time.Second):
+		t.Fatal("The subscription did not start")
+	}
+	events.End(ExecutionID, "fabric configure:Add Device", domain.ExecutionEventSucceeded, "", nil)
+}
+
+//The failures of a stage are published as they are collected and the other devices succeed the stage
+func TestExecutionEvents_StageProgress(t *testing.T) {
+	ExecutionID := uuid.New().String()
+	_, ctx := appcontext.LoggerAndContext(ExecutionID)
+	events.Begin(ExecutionID, "fabric configure:ConfigureFabric")
+
+	Progress := actions.StartStage(ctx, domain.StageOverlay, []string{"10.24.39.1", "10.24.39.2"})
+	actions.Step(ctx, "10.24.39.2", domain.StageOverlay, "overlay gateway")
+	Errors := make(chan actions.OperationError, 1)
+	go func() {
+		Errors <- actions.OperationError{Operation: "Configure Overlay Gateway", Error: errors.New("Operation Failed"),
+			Host: "10.24.39.2"}
+		close(Errors)
+	}()
+	assert.Equal(t, 1, len(Progress.Collect(Errors)))
+	events.End(ExecutionID, "fabric configure:ConfigureFabric", domain.ExecutionEventFailed, "", nil)
+
+	Subscription, _ := events.Subscribe(context.Background(), ExecutionID)
+	next(t, Subscription)
+	Statuses := make([]string, 0)
+	for Event := next(t, Subscription); !Event.Finished(); Event = next(t, Subscription) {
+		assert.Equal(t, domain.StageOverlay, Event.Stage)
+		Statuses = append(Statuses, Event.Device+" "+Event.Step+" "+Event.Status)
+		if Event.Status == domain.ExecutionEventFailed {
+			assert.Equal(t, "Operation Failed", Event.Message)
+			assert.Equal(t, []domain.DeviceError{{Device: "10.24.39.2", Operation: "Configure Overlay Gateway",
+				Message: "Operation Failed"}}, Event.Errors)
+		}
+	}
+	assert.Equal(t, []string{"10.24.39.1  running", "10.24.39.2  running", "10.24.39.2 overlay gateway running",
+		"10.24.39.2 Configure Overlay Gateway failed", "10.24.39.1  succeeded"}, Statuses)
+}
+
+//The events of an execution chosen by the client are streamed as Server-Sent Events
+func TestExecutionEvents_REST(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	Server := httptest.NewServer(openapi.NewRouter())
+	defer Server.Close()
+
+	Wait := handler.ExecutionEventsWait
+	handler.ExecutionEventsWait = 100 * time.Millisecond
+	defer func() { handler.ExecutionEventsWait = Wait }()
+	Response, err := http.Get(Server.URL + "/v1/execution/" + uuid.New().String() + "/events")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, Response.StatusCode)
+	Response.Body.Close()
+	handler.ExecutionEventsWait = 5 * time.Second
+
+	ExecutionID := uuid.New().String()
+	Streamed := make(chan *http.Response)
+	go func() {
+		Response, _ := http.Get(Server.URL + "/v1/execution/" + ExecutionID + "/events")
+		Streamed <- Response
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:ConfigureFabric"}, ReqID: ExecutionID}
+	ctx := alog.LogMessageInit()
+	assert.Equal(t, ExecutionID, alog.ReqID)
+	alog.LogMessageReceived()
+	Progress := actions.StartStage(ctx, domain.StagePersist, []string{"10.24.39.1"})
+	Progress.Finish()
+	success := true
+	statusMsg := ""
+	alog.LogMessageEnd(&success, &statusMsg)
+
+	var Stream *http.Response
+	select {
+	case Stream = <-Streamed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The stream did not start")
+	}
+	defer Stream.Body.Close()
+	assert.Equal(t, http.StatusOK, Stream.StatusCode)
+	assert.Equal(t, "text/event-stream", Stream.Header.Get("Content-Type"))
+
+	Types := make([]string, 0)
+	Received := make([]domain.ExecutionEvent, 0)
+	Scanner := bufio.NewScanner(Stream.Body)
+	for Scanner.Scan() {
+		Line := Scanner.Text()
+		if strings.HasPrefix(Line, "event: ") {
+			Types = append(Types, strings.TrimPrefix(Line, "event: "))
+		}
+		if strings.HasPrefix(Line, "data: ") {
+			var Event domain.ExecutionEvent
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(Line, "data: ")), &Event))
+			Received = append(Received, Event)
+		}
+	}
+	assert.Equal(t, []string{"execution", "device", "device", "execution"}, Types)
+	assert.Equal(t, 4, len(Received))
+	assert.Equal(t, domain.StagePersist, Received[2].Stage)
+	assert.Equal(t, domain.ExecutionEventSucceeded, Received[2].Status)
+	assert.Equal(t, domain.ExecutionEventSucceeded, Received[3].Status)
+	assert.Equal(t, ExecutionID, Received[3].ExecutionID)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
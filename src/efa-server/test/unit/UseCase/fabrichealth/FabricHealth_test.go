@@ -0,0 +1,256 @@
+package fabrichealth
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "ipaddress_spine1"
+var MockLeaf1IP = "ipaddress_leaf1"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "fh"
+
+func setupInteractor(FabricAdapter *mock.FabricAdapter) (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1", ConfigState: "up"}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			}
+			return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+				LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+				RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: FabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+//healthyState returns the state of the devices with every BGP neighbor configured in the DB established
+func healthyState(DatabaseRepository *gateway.DatabaseRepository) func(ctx context.Context,
+	FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse, []actions.OperationError) {
+	return func(ctx context.Context, FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse,
+		[]actions.OperationError) {
+		Fabric, _ := DatabaseRepository.GetFabric(FabricRequest.FabricName)
+		Response := operation.FabricHealthResponse{FabricName: FabricRequest.FabricName}
+		for _, Host := range FabricRequest.Hosts {
+			Device, _ := DatabaseRepository.GetDevice(FabricRequest.FabricName, Host.Host)
+			State := operation.SwitchHealthResponse{Host: Host.Host, Role: Host.Role, RouteCount: 100,
+				Errors: map[string]string{}}
+			Neighbors, _ := DatabaseRepository.GetBGPSwitchConfigsOnDeviceID(Fabric.ID, Device.ID)
+			for _, Neighbor := range Neighbors {
+				State.BgpNeighbors = append(State.BgpNeighbors, operation.ConfigBgpNeighbor{
+					NeighborAddress: Neighbor.RemoteIPAddress, State: "ESTAB"})
+				State.EvpnNeighbors = append(State.EvpnNeighbors, operation.ConfigBgpNeighbor{
+					NeighborAddress: Neighbor.RemoteIPAddress, State: "ESTAB"})
+				State.BfdSessions = append(State.BfdSessions, operation.BfdSession{
+					NeighborAddress: Neighbor.RemoteIPAddress, State: "Up"})
+			}
+			Response.SwitchResponse = append(Response.SwitchResponse, State)
+		}
+		return Response, []actions.OperationError{}
+	}
+}
+
+func checkOf(Health domain.FabricHealth, IPAddress string, Name string) domain.HealthCheck {
+	for _, Device := range Health.Devices {
+		if Device.IPAddress != IPAddress {
+			continue
+		}
+		for _, Check := range Device.Checks {
+			if Check.Name == Name {
+				return Check
+			}
+		}
+	}
+	return domain.HealthCheck{}
+}
+
+//The fabric is healthy when every neighbor configured in the DB is established
+func TestFabricHealth_Healthy(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	FabricAdapter := &mock.FabricAdapter{}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter)
+	FabricAdapter.MockFetchFabricHealth = healthyState(DatabaseRepository)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	Health, err := devUC.FabricHealth(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.True(t, Health.Healthy)
+	assert.Equal(t, 2, len(Health.Devices))
+	for _, Device := range Health.Devices {
+		assert.True(t, Device.Healthy)
+		assert.Equal(t, len(domain.HealthChecks), len(Device.Checks))
+	}
+	assert.Equal(t, domain.HealthPass, checkOf(Health, MockLeaf1IP, domain.HealthCheckBGP).Status)
+	assert.Equal(t, domain.HealthPass, checkOf(Health, MockSpine1IP, domain.HealthCheckEVPN).Status)
+	assert.Equal(t, domain.HealthPass, checkOf(Health, MockLeaf1IP, domain.HealthCheckRoutes).Status)
+	assert.Equal(t, domain.HealthSkipped, checkOf(Health, MockLeaf1IP, domain.HealthCheckMCT).Status)
+	assert.Equal(t, domain.HealthSkipped, checkOf(Health, MockSpine1IP, domain.HealthCheckOverlay).Status)
+}
+
+//A neighbor that is not established, or missing, fails the check of the device
+func TestFabricHealth_NeighborDown(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	FabricAdapter := &mock.FabricAdapter{}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter)
+	Healthy := healthyState(DatabaseRepository)
+	FabricAdapter.MockFetchFabricHealth = func(ctx context.Context, FabricRequest operation.FabricHealthRequest) (
+		operation.FabricHealthResponse, []actions.OperationError) {
+		Response, Errors := Healthy(ctx, FabricRequest)
+		for i := range Response.SwitchResponse {
+			if Response.SwitchResponse[i].Host == MockLeaf1IP {
+				Response.SwitchResponse[i].BgpNeighbors[0].State = "Idle"
+				Response.SwitchResponse[i].EvpnNeighbors = nil
+				Response.SwitchResponse[i].Errors[domain.HealthCheckRoutes] = "rpc timed out"
+			}
+		}
+		return Response, Errors
+	}
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	Health, err := devUC.FabricHealth(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.False(t, Health.Healthy)
+	BGP := checkOf(Health, MockLeaf1IP, domain.HealthCheckBGP)
+	assert.Equal(t, domain.HealthFail, BGP.Status)
+	assert.Contains(t, BGP.Message, "Idle")
+	EVPN := checkOf(Health, MockLeaf1IP, domain.HealthCheckEVPN)
+	assert.Equal(t, domain.HealthFail, EVPN.Status)
+	assert.Contains(t, EVPN.Message, "missing")
+	Routes := checkOf(Health, MockLeaf1IP, domain.HealthCheckRoutes)
+	assert.Equal(t, domain.HealthFail, Routes.Status)
+	assert.Equal(t, "rpc timed out", Routes.Message)
+	assert.Equal(t, domain.HealthPass, checkOf(Health, MockSpine1IP, domain.HealthCheckBGP).Status)
+}
+
+//A device that can not be reached fails every check, an unknown fabric is an error
+func TestFabricHealth_LoginFailure(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	FabricAdapter := &mock.FabricAdapter{}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter)
+	Healthy := healthyState(DatabaseRepository)
+	FabricAdapter.MockFetchFabricHealth = func(ctx context.Context, FabricRequest operation.FabricHealthRequest) (
+		operation.FabricHealthResponse, []actions.OperationError) {
+		Response, _ := Healthy(ctx, FabricRequest)
+		return Response, []actions.OperationError{actions.OperationError{Operation: "Fabric Health Login",
+			Host: MockSpine1IP, Error: errors.New("connection refused")}}
+	}
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	Health, err := devUC.FabricHealth(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.False(t, Health.Healthy)
+	for _, Name := range domain.HealthChecks {
+		Check := checkOf(Health, MockSpine1IP, Name)
+		assert.Equal(t, domain.HealthFail, Check.Status)
+		assert.Equal(t, "connection refused", Check.Message)
+	}
+	assert.Equal(t, domain.HealthPass, checkOf(Health, MockLeaf1IP, domain.HealthCheckBGP).Status)
+
+	_, err = devUC.FabricHealth(context.Background(), "unknown_fabric")
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+//The configure reports the health of the fabric without failing when it is not healthy
+func TestFabricHealth_AfterConfigure(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Checked := 0
+	FabricAdapter := &mock.FabricAdapter{
+		MockFetchFabricHealth: func(ctx context.Context, FabricRequest operation.FabricHealthRequest) (
+			operation.FabricHealthResponse, []actions.OperationError) {
+			Checked++
+			return operation.FabricHealthResponse{FabricName: FabricRequest.FabricName}, []actions.OperationError{}
+		},
+	}
+	_, devUC := setupInteractor(FabricAdapter)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	Response, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, Checked)
+	if assert.NotNil(t, Response.Health) {
+		assert.False(t, Response.Health.Healthy)
+		assert.Equal(t, 2, len(Response.Health.Devices))
+		assert.Equal(t, "No operational state was collected from the device",
+			checkOf(*Response.Health, MockLeaf1IP, domain.HealthCheckBGP).Message)
+	}
+}
+
+//The health check is left to the caller of the configure when it defers it, the REST handlers check it once
+//the RestLock is released
+func TestFabricHealth_DeferredAfterConfigure(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Checked := 0
+	FabricAdapter := &mock.FabricAdapter{
+		MockFetchFabricHealth: func(ctx context.Context, FabricRequest operation.FabricHealthRequest) (
+			operation.FabricHealthResponse, []actions.OperationError) {
+			Checked++
+			return operation.FabricHealthResponse{FabricName: FabricRequest.FabricName}, []actions.OperationError{}
+		},
+	}
+	_, devUC := setupInteractor(FabricAdapter)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), appcontext.DeferHealthCheck, true)
+	Response, err := devUC.ConfigureFabric(ctx, MockFabricName, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, Checked)
+	assert.Nil(t, Response.Health)
+
+	Health := devUC.VerifyFabricHealth(ctx, MockFabricName)
+	assert.Equal(t, 1, Checked)
+	if assert.NotNil(t, Health) {
+		assert.False(t, Health.Healthy)
+	}
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -13,7 +13,6 @@ import (
 	"efa-server/infra/constants"
 	"fmt"
 	"github.com/stretchr/testify/assert"
-	"os"
 )
 
 var MockSpine1IP = "SPINE1_IP"
@@ -80,6 +79,5 @@ func TestFetchFabricConfigSpine_And_Leaf_Test(t *testing.T) {
 }
 
 func cleanupDB(Database *database.Database) {
-	Database.Close()
-	os.Remove(DBName)
+	Database.Drop()
 }
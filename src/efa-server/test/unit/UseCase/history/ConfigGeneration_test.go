@@ -0,0 +1,263 @@
+package history
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "10.24.80.1"
+var MockSpine2IP = "10.24.80.2"
+var MockLeaf1IP = "10.24.80.3"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "history"
+
+type port struct {
+	Device string
+	Name   string
+	Mac    string
+}
+
+var Ports = []port{
+	{MockSpine1IP, "1/11", "S11"}, {MockSpine1IP, "1/12", "S12"}, {MockSpine2IP, "1/21", "S21"},
+	{MockLeaf1IP, "1/1", "L1"}, {MockLeaf1IP, "1/2", "L2"},
+}
+
+//setupInteractor sets up devices whose LLDP follows Cabling, a map of the cabled Macs in both directions
+func setupInteractor(FabricAdapter *mock.FabricAdapter, Cabling *map[string]string) (*gateway.DatabaseRepository,
+	*usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			Interfaces := make([]domain.Interface, 0)
+			for _, p := range Ports {
+				if p.Device == DeviceIP {
+					Interfaces = append(Interfaces, domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+						IntType: domain.IntfTypeEthernet, IntName: p.Name, Mac: p.Mac, ConfigState: "up"})
+				}
+			}
+			return Interfaces, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			LLDPs := make([]domain.LLDP, 0)
+			for _, p := range Ports {
+				RemoteMac, ok := (*Cabling)[p.Mac]
+				if p.Device != DeviceIP || !ok {
+					continue
+				}
+				for _, r := range Ports {
+					if r.Mac == RemoteMac {
+						LLDPs = append(LLDPs, domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+							LocalIntType: domain.IntfTypeEthernet, LocalIntName: p.Name, LocalIntMac: p.Mac,
+							RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: r.Name, RemoteIntMac: r.Mac})
+					}
+				}
+			}
+			return LLDPs, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: FabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+func configuredFabricAdapter() *mock.FabricAdapter {
+	return &mock.FabricAdapter{
+		MockConfigureLinks: func(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError {
+			return []actions.OperationError{}
+		},
+	}
+}
+
+func poolFree(t *testing.T, devUC *usecase.DeviceInteractor, PoolType string, PoolName string) uint64 {
+	Response, err := devUC.GetPoolUtilization(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	for _, Pool := range Response.Pools {
+		if Pool.PoolType == PoolType && Pool.PoolName == PoolName {
+			return Pool.Free
+		}
+	}
+	return 0
+}
+
+//Each configure stores a generation of the intended configuration
+func TestConfigGeneration_History(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Cabling := map[string]string{"L1": "S11", "S11": "L1", "L2": "S21", "S21": "L2"}
+	_, devUC := setupInteractor(configuredFabricAdapter(), &Cabling)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+
+	Generations, _, err := devUC.GetConfigGenerations(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Empty(t, Generations)
+
+	response, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), response.Generation)
+	response, err = devUC.ConfigureFabric(context.Background(), MockFabricName, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), response.Generation)
+
+	Generations, _, err = devUC.GetConfigGenerations(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(Generations))
+	assert.Equal(t, uint(2), Generations[0].Generation)
+	assert.Equal(t, 3, len(Generations[1].Devices))
+	assert.NotNil(t, Generations[1].Settings)
+	assert.NotEmpty(t, Generations[1].Settings.LeafASNBlock)
+	for _, Device := range Generations[1].Devices {
+		assert.NotEmpty(t, Device.LocalAS)
+		assert.NotEmpty(t, Device.LoopbackIP)
+		if Device.DeviceIP == MockLeaf1IP {
+			assert.Equal(t, 2, len(Device.Interfaces))
+			assert.Equal(t, 2, len(Device.Neighbors))
+		}
+	}
+
+	//Nothing changed between the two configures
+	Changes, _, err := devUC.DiffConfigGenerations(context.Background(), MockFabricName, 1, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, Changes)
+
+	_, _, err = devUC.DiffConfigGenerations(context.Background(), MockFabricName, 1, 3)
+	assert.Equal(t, domain.ErrGenerationNotFound, err)
+	_, _, err = devUC.GetConfigGenerations(context.Background(), "unknown_fabric")
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+//A revert takes the ASN of the generation back and configures the fabric with it
+func TestConfigGeneration_Revert(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	Cabling := map[string]string{"L1": "S11", "S11": "L1", "L2": "S21", "S21": "L2"}
+	DatabaseRepository, devUC := setupInteractor(configuredFabricAdapter(), &Cabling)
+	_, err := devUC.AddDevices(ctx, MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.ConfigureFabric(ctx, MockFabricName, false, false)
+	assert.NoError(t, err)
+	SwitchConfig, _ := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockLeaf1IP)
+	OriginalASN := SwitchConfig.LocalAS
+	LeafFree := poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole)
+
+	//The leaf is moved to a pinned ASN
+	_, _, err = devUC.SetAllocationPin(ctx, MockFabricName, MockLeaf1IP, domain.PinTypeASN, "65010", "")
+	assert.NoError(t, err)
+	_, err = devUC.AddDevices(ctx, MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.ConfigureFabric(ctx, MockFabricName, false, false)
+	assert.NoError(t, err)
+	_, _, err = devUC.ClearAllocationPins(ctx, MockFabricName, MockLeaf1IP, domain.PinTypeASN, "")
+	assert.NoError(t, err)
+
+	Changes, _, err := devUC.DiffConfigGenerations(ctx, MockFabricName, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(Changes))
+	for _, Change := range Changes {
+		assert.Equal(t, domain.ChangeUpdated, Change.Change)
+		if Change.DeviceIP == MockLeaf1IP {
+			assert.Equal(t, domain.ChangeKindDevice, Change.Kind)
+			assert.Equal(t, OriginalASN, Change.Old)
+			assert.Equal(t, "65010", Change.New)
+		} else {
+			assert.Equal(t, domain.ChangeKindNeighbor, Change.Kind)
+		}
+	}
+
+	response, _, err := devUC.RevertFabric(ctx, MockFabricName, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(response.Changes))
+	assert.Equal(t, uint(3), response.Configure.Generation)
+	SwitchConfig, _ = DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockLeaf1IP)
+	assert.Equal(t, OriginalASN, SwitchConfig.LocalAS)
+	assert.Equal(t, LeafFree, poolFree(t, devUC, domain.PoolTypeASN, usecase.LeafRole))
+
+	Changes, _, err = devUC.DiffConfigGenerations(ctx, MockFabricName, 1, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, Changes)
+
+	//The fabric is already at the generation
+	response, statusMsg, err := devUC.RevertFabric(ctx, MockFabricName, 3, false)
+	assert.NoError(t, err)
+	assert.Empty(t, response.Changes)
+	assert.Equal(t, "Fabric test_fabric is already at generation 3", statusMsg)
+
+	_, _, err = devUC.RevertFabric(ctx, MockFabricName, 4, false)
+	assert.Equal(t, domain.ErrGenerationNotFound, err)
+}
+
+//The links added since the generation are kept by a revert, the device settings are reverted
+func TestConfigGeneration_RevertTopology(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	Cabling := map[string]string{"L1": "S11", "S11": "L1"}
+	DatabaseRepository, devUC := setupInteractor(configuredFabricAdapter(), &Cabling)
+	_, err := devUC.AddDevices(ctx, MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.ConfigureFabric(ctx, MockFabricName, false, false)
+	assert.NoError(t, err)
+
+	//A link to spine2 is cabled and the MTU of the leaf is overridden
+	Cabling = map[string]string{"L1": "S11", "S11": "L1", "L2": "S21", "S21": "L2"}
+	_, err = devUC.RefreshFabric(ctx, MockFabricName, "")
+	assert.NoError(t, err)
+	_, err = devUC.UpdateDeviceSettings(ctx, MockFabricName, MockLeaf1IP, &domain.DeviceSettings{MTU: "9000"})
+	assert.NoError(t, err)
+	_, err = devUC.ConfigureFabric(ctx, MockFabricName, false, false)
+	assert.NoError(t, err)
+
+	Changes, _, err := devUC.DiffConfigGenerations(ctx, MockFabricName, 1, 2)
+	assert.NoError(t, err)
+	Added := 0
+	for _, Change := range Changes {
+		if Change.Change == domain.ChangeAdded {
+			Added++
+		}
+	}
+	assert.NotZero(t, Added)
+	assert.Contains(t, Changes, domain.ConfigChange{DeviceIP: MockLeaf1IP, Kind: domain.ChangeKindSetting, Name: "MTU",
+		Change: domain.ChangeUpdated, Old: usecase.DeviceSettingsDefault, New: "9000"})
+
+	response, _, err := devUC.RevertFabric(ctx, MockFabricName, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ConfigChange{{DeviceIP: MockLeaf1IP, Kind: domain.ChangeKindSetting, Name: "MTU",
+		Change: domain.ChangeUpdated, Old: "9000", New: usecase.DeviceSettingsDefault}}, response.Changes)
+	Device, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	DeviceSettings, _ := DatabaseRepository.GetDeviceSettings(Device.FabricID, Device.ID)
+	assert.Empty(t, DeviceSettings.MTU)
+
+	//The link to spine2 is still configured
+	Generations, _, err := devUC.GetConfigGenerations(ctx, MockFabricName)
+	assert.NoError(t, err)
+	for _, Device := range Generations[0].Devices {
+		if Device.DeviceIP == MockLeaf1IP {
+			assert.Equal(t, 2, len(Device.Interfaces))
+		}
+	}
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -11,7 +11,6 @@ import (
 	"efa-server/domain"
 	"efa-server/infra/constants"
 	"github.com/stretchr/testify/assert"
-	"os"
 )
 
 var (
@@ -285,6 +284,5 @@ func TestIPPairPool_ReserveIPPairSuccessOutOfOrder(t *testing.T) {
 }
 
 func cleanupDB(Database *database.Database, DBName string) {
-	Database.Close()
-	os.Remove(DBName)
+	Database.Drop()
 }
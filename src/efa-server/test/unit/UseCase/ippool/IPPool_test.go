@@ -11,7 +11,6 @@ import (
 	"efa-server/domain"
 	"efa-server/infra/constants"
 	"github.com/stretchr/testify/assert"
-	"os"
 )
 
 var (
@@ -181,6 +180,5 @@ func TestPool_ReserveIPSuccess(t *testing.T) {
 	assert.Equal(t, int64(1), count)
 }
 func cleanupDB(Database *database.Database, DBName string) {
-	Database.Close()
-	os.Remove(DBName)
+	Database.Drop()
 }
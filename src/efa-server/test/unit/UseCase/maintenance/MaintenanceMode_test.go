@@ -0,0 +1,171 @@
+package maintenance
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "ipaddress_spine1"
+var MockLeaf1IP = "ipaddress_leaf1"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "mm"
+
+func setupInteractor(FabricAdapter *mock.FabricAdapter) (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1", ConfigState: "up"}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			if DeviceIP == MockSpine1IP {
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			}
+			return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+				LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+				RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: FabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+//Enabling maintenance mode drains only the requested device and records the mode
+func TestMaintenanceMode_EnableDisable(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var DrainedHosts []operation.ConfigSwitch
+	var DrainEnable bool
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureMaintenanceMode: func(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+			DrainedHosts = config.Hosts
+			DrainEnable = enable
+			return []actions.OperationError{}
+		},
+	}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	ret, err := devUC.UpdateMaintenanceMode(context.Background(), MockFabricName, MockLeaf1IP, true)
+	assert.NoError(t, err)
+	assert.Contains(t, ret, "enabled")
+	assert.True(t, DrainEnable)
+	assert.Equal(t, 1, len(DrainedHosts))
+	assert.Equal(t, MockLeaf1IP, DrainedHosts[0].Host)
+
+	Device, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.True(t, Device.MaintenanceMode)
+
+	ret, err = devUC.UpdateMaintenanceMode(context.Background(), MockFabricName, MockLeaf1IP, false)
+	assert.NoError(t, err)
+	assert.Contains(t, ret, "disabled")
+	assert.False(t, DrainEnable)
+
+	Device, _ = DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.False(t, Device.MaintenanceMode)
+}
+
+//Maintenance mode is not recorded when the drain fails on the device
+func TestMaintenanceMode_DrainFailure(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureMaintenanceMode: func(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+			return []actions.OperationError{actions.OperationError{Operation: "BGP Convergence", Host: MockLeaf1IP,
+				Error: errors.New("timed out")}}
+		},
+	}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	ret, err := devUC.UpdateMaintenanceMode(context.Background(), MockFabricName, MockLeaf1IP, true)
+	assert.Equal(t, domain.ErrFabricInternalError, err)
+	assert.Contains(t, ret, MockLeaf1IP)
+
+	Device, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.False(t, Device.MaintenanceMode)
+
+	_, err = devUC.UpdateMaintenanceMode(context.Background(), MockFabricName, "unknown_device", true)
+	assert.Equal(t, domain.ErrDeviceNotFound, err)
+	_, err = devUC.UpdateMaintenanceMode(context.Background(), "unknown_fabric", MockLeaf1IP, true)
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+//Configuring a device in maintenance mode is refused unless forced, forcing takes it out of maintenance mode
+func TestMaintenanceMode_ConfigureRequiresForce(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var ConfiguredHosts []operation.ConfigSwitch
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureMaintenanceMode: func(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+			return []actions.OperationError{}
+		},
+		MockConfigureFabric: func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError {
+			ConfiguredHosts = config.Hosts
+			return []actions.OperationError{}
+		},
+	}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.UpdateMaintenanceMode(context.Background(), MockFabricName, MockLeaf1IP, true)
+	assert.NoError(t, err)
+
+	Response, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.Equal(t, domain.ErrFabricDeviceInMaintenance, err)
+	if assert.Equal(t, 1, len(Response)) {
+		assert.Equal(t, MockLeaf1IP, Response[0].IPAddress)
+		assert.NotEqual(t, 0, len(Response[0].Errors))
+	}
+
+	ConfigureResponse, err := devUC.ConfigureFabric(context.Background(), MockFabricName, false, false)
+	assert.Equal(t, domain.ErrFabricDeviceInMaintenance, err)
+	assert.Nil(t, ConfiguredHosts)
+	if assert.Equal(t, 1, len(ConfigureResponse.Errors)) {
+		assert.Equal(t, MockLeaf1IP, ConfigureResponse.Errors[0].Host)
+	}
+
+	_, err = devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, true)
+	assert.NoError(t, err)
+	Device, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.False(t, Device.MaintenanceMode)
+
+	_, err = devUC.ConfigureFabric(context.Background(), MockFabricName, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ConfiguredHosts))
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/logging"
+	"efa-server/infra/metrics"
+	"efa-server/infra/rest/openapi"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var dbExtension = "metrics"
+
+//scrape returns the metrics exposed by the server in the Prometheus text format
+func scrape(t *testing.T) string {
+	Recorder := httptest.NewRecorder()
+	openapi.NewRouter().ServeHTTP(Recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, Recorder.Code)
+	return Recorder.Body.String()
+}
+
+//The REST requests are counted and timed by route, method and status
+func TestMetrics_Requests(t *testing.T) {
+	Recorder := httptest.NewRecorder()
+	openapi.NewRouter().ServeHTTP(Recorder, httptest.NewRequest("GET", "/v1/executions?limit=10&status=unknown", nil))
+	assert.Equal(t, http.StatusBadRequest, Recorder.Code)
+
+	Metrics := scrape(t)
+	assert.Contains(t, Metrics, `efa_http_requests_total{code="400",method="get",route="ExecutionList"} 1`)
+	assert.Contains(t, Metrics, `efa_http_request_duration_seconds_count{code="400",method="get",route="ExecutionList"} 1`)
+}
+
+//The operations recorded as executions and the DB queries they run are timed
+func TestMetrics_Operations(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:ConfigureFabric"}}
+	alog.LogMessageInit()
+	alog.LogMessageReceived()
+	success := false
+	statusMsg := "test status Message"
+	alog.LogMessageEnd(&success, &statusMsg)
+
+	Metrics := scrape(t)
+	assert.Contains(t, Metrics, `efa_operation_duration_seconds_count{operation="fabric configure:ConfigureFabric",status="Failed"} 1`)
+	assert.Contains(t, Metrics, `efa_db_query_duration_seconds_count{operation="create"}`)
+	assert.Contains(t, Metrics, `efa_db_query_duration_seconds_count{operation="update"}`)
+}
+
+//The device actions are timed by action and the wait for the RestLock is observed
+func TestMetrics_DeviceActionsAndRestLock(t *testing.T) {
+	func() {
+		defer metrics.ObserveDeviceAction("ConfigureSwitch", time.Now())
+	}()
+	metrics.CountNetconfRPC("ConfigureInterfaceNumbered", nil)
+	metrics.CountNetconfRPC("ConfigureInterfaceNumbered", assert.AnError)
+
+	constants.RestLock.Lock()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		constants.RestLock.Unlock()
+	}()
+	constants.RestLock.Lock()
+	constants.RestLock.Unlock()
+
+	Metrics := scrape(t)
+	assert.Contains(t, Metrics, `efa_device_action_duration_seconds_count{action="ConfigureSwitch"} 1`)
+	assert.Contains(t, Metrics, `efa_netconf_rpcs_total{method="ConfigureInterfaceNumbered"} 2`)
+	assert.Contains(t, Metrics, `efa_netconf_rpc_errors_total{method="ConfigureInterfaceNumbered"} 1`)
+	assert.Contains(t, Metrics, "efa_rest_lock_wait_seconds_count 2")
+	assert.NotContains(t, Metrics, "efa_rest_lock_wait_seconds_sum 0\n")
+}
+
+//The utilization of the pools is computed at each scrape
+func TestMetrics_PoolUtilization(t *testing.T) {
+	Used := uint64(3)
+	assert.NoError(t, metrics.RegisterPoolUtilization(func() []metrics.PoolUtilization {
+		return []metrics.PoolUtilization{{Fabric: "default", PoolType: "ASN", PoolName: "Leaf",
+			Used: Used, Free: 97, Utilization: Used}}
+	}))
+
+	assert.Contains(t, scrape(t), `efa_pool_utilization_percent{fabric="default",pool_name="Leaf",pool_type="ASN"} 3`)
+	Used = 4
+	Metrics := scrape(t)
+	assert.Contains(t, Metrics, `efa_pool_used{fabric="default",pool_name="Leaf",pool_type="ASN"} 4`)
+	assert.Contains(t, Metrics, `efa_pool_free{fabric="default",pool_name="Leaf",pool_type="ASN"} 97`)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
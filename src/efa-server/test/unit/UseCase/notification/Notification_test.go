@@ -0,0 +1,331 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/logging"
+	Restmodel "efa-server/infra/rest/generated/server/go"
+	"efa-server/infra/rest/openapi"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	Interactor "efa-server/usecase/interactorinterface"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var MockFabricName = "test_fabric"
+var dbExtension = "notification"
+
+//delivery is a request received by the listener
+type delivery struct {
+	Header http.Header
+	Body   []byte
+	Event  domain.NotificationEvent
+}
+
+//newListener returns a local webhook endpoint answering with the statuses in turn, then with 200
+func newListener(Statuses ...int) (*httptest.Server, chan delivery) {
+	Deliveries := make(chan delivery, 10)
+	Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Body, _ := ioutil.ReadAll(r.Body)
+		Delivery := delivery{Header: r.Header, Body: Body}
+		json.Unmarshal(Body, &Delivery.Event)
+		Deliveries <- Delivery
+		if len(Statuses) != 0 {
+			w.WriteHeader(Statuses[0])
+			Statuses = Statuses[1:]
+		}
+	}))
+	return Server, Deliveries
+}
+
+//receive returns the next delivery of the listener
+func receive(t *testing.T, Deliveries chan delivery) delivery {
+	select {
+	case Delivery := <-Deliveries:
+		return Delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("No event was delivered")
+	}
+	return delivery{}
+}
+
+func setupInteractor() (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(mock.DeviceAdapter{}),
+		FabricAdapter: &mock.FabricAdapter{}, Notifier: &gateway.WebhookNotifier{RetryInterval: 10 * time.Millisecond}}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+//The URL, the events and the retries of a subscription are validated and the secret is stored encrypted
+func TestNotification_Subscriptions(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	DatabaseRepository, devUC := setupInteractor()
+	for _, Invalid := range []domain.NotificationSubscription{
+		{URL: "ftp://example.com/hook"},
+		{URL: "http:///hook"},
+		{URL: "http://example.com/hook", Events: []string{"execution.unknown"}},
+		{URL: "http://example.com/hook", Retries: domain.MaxNotificationRetries + 1},
+	} {
+		_, statusMsg, err := devUC.AddNotificationSubscription(ctx, Invalid)
+		assert.Equal(t, domain.ErrSubscriptionInvalid, err)
+		assert.NotEmpty(t, statusMsg)
+	}
+
+	Subscription, _, err := devUC.AddNotificationSubscription(ctx, domain.NotificationSubscription{
+		URL: "https://example.com/hook", Secret: "secret", Retries: 3,
+		Events: []string{domain.EventExecutionFailed, domain.EventValidationMissingLinks}})
+	assert.NoError(t, err)
+	assert.NotZero(t, Subscription.ID)
+
+	Subscriptions, _, err := devUC.GetNotificationSubscriptions(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(Subscriptions))
+	assert.Equal(t, "secret", Subscriptions[0].Secret)
+	assert.Equal(t, []string{domain.EventExecutionFailed, domain.EventValidationMissingLinks}, Subscriptions[0].Events)
+	assert.Equal(t, uint(3), Subscriptions[0].Retries)
+	assert.True(t, Subscriptions[0].Subscribes(domain.EventExecutionFailed))
+	assert.False(t, Subscriptions[0].Subscribes(domain.EventExecutionStarted))
+
+	var Stored database.NotificationSubscription
+	DatabaseRepository.GetDBHandle().First(&Stored)
+	assert.NotEmpty(t, Stored.Secret)
+	assert.NotEqual(t, "secret", Stored.Secret)
+
+	_, _, err = devUC.DeleteNotificationSubscription(ctx, Subscription.ID)
+	assert.NoError(t, err)
+	_, _, err = devUC.DeleteNotificationSubscription(ctx, Subscription.ID)
+	assert.Equal(t, domain.ErrSubscriptionNotFound, err)
+	Subscriptions, _, _ = devUC.GetNotificationSubscriptions(ctx)
+	assert.Equal(t, 0, len(Subscriptions))
+}
+
+//The events are signed with the secret of the subscription
+func TestNotification_SignedDelivery(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.WithValue(context.Background(), appcontext.RequestIDKey, "exec-1")
+
+	Listener, Deliveries := newListener()
+	defer Listener.Close()
+	_, devUC := setupInteractor()
+	Subscription, _, err := devUC.AddNotificationSubscription(ctx, domain.NotificationSubscription{URL: Listener.URL,
+		Secret: "secret"})
+	assert.NoError(t, err)
+
+	Event, _, err := devUC.TestNotificationSubscription(ctx, Subscription.ID)
+	assert.NoError(t, err)
+	Delivery := receive(t, Deliveries)
+	assert.Equal(t, domain.EventNotificationTest, Delivery.Header.Get(gateway.EventHeader))
+	assert.Equal(t, Event.ID, Delivery.Header.Get(gateway.DeliveryHeader))
+	assert.Equal(t, gateway.Signature("secret", Delivery.Body), Delivery.Header.Get(gateway.SignatureHeader))
+	assert.NotEqual(t, gateway.Signature("other", Delivery.Body), Delivery.Header.Get(gateway.SignatureHeader))
+	assert.Equal(t, Event.ID, Delivery.Event.ID)
+	assert.Equal(t, "exec-1", Delivery.Event.ExecutionID)
+
+	_, _, err = devUC.TestNotificationSubscription(ctx, Subscription.ID+1)
+	assert.Equal(t, domain.ErrSubscriptionNotFound, err)
+}
+
+//A delivery is retried on 5xx statuses until the endpoint accepts it, not on 4xx statuses
+func TestNotification_Retries(t *testing.T) {
+	Notifier := &gateway.WebhookNotifier{RetryInterval: 10 * time.Millisecond}
+	Event := domain.NotificationEvent{ID: "event-1", Event: domain.EventExecutionFailed}
+
+	Listener, Deliveries := newListener(http.StatusInternalServerError, http.StatusServiceUnavailable)
+	defer Listener.Close()
+	assert.NoError(t, Notifier.Deliver(context.Background(),
+		domain.NotificationSubscription{URL: Listener.URL, Retries: 2}, Event))
+	assert.Equal(t, 3, len(Deliveries))
+	for iter := 0; iter < 3; iter++ {
+		assert.Equal(t, "event-1", (<-Deliveries).Header.Get(gateway.DeliveryHeader))
+	}
+
+	Failing, FailingDeliveries := newListener(http.StatusInternalServerError, http.StatusInternalServerError)
+	defer Failing.Close()
+	assert.Error(t, Notifier.Deliver(context.Background(),
+		domain.NotificationSubscription{URL: Failing.URL, Retries: 1}, Event))
+	assert.Equal(t, 2, len(FailingDeliveries))
+
+	Rejecting, RejectingDeliveries := newListener(http.StatusBadRequest)
+	defer Rejecting.Close()
+	assert.Error(t, Notifier.Deliver(context.Background(),
+		domain.NotificationSubscription{URL: Rejecting.URL, Retries: 3}, Event))
+	assert.Equal(t, 1, len(RejectingDeliveries))
+}
+
+//The start and the failure of an execution are notified, the failure with the errors of the devices
+func TestNotification_Execution(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Listener, Deliveries := newListener()
+	defer Listener.Close()
+	_, _, err := infra.GetUseCaseInteractor().AddNotificationSubscription(context.Background(),
+		domain.NotificationSubscription{URL: Listener.URL,
+			Events: []string{domain.EventExecutionStarted, domain.EventExecutionFailed}})
+	assert.NoError(t, err)
+
+	alog := logging.AuditLog{Request: &logging.Request{Command: "fabric configure:ConfigureFabric"}}
+	alog.LogMessageInit()
+	alog.Request.Params = map[string]interface{}{"FabricName": MockFabricName}
+	alog.LogMessageReceived()
+	Started := receive(t, Deliveries).Event
+	assert.Equal(t, domain.EventExecutionStarted, Started.Event)
+	assert.Equal(t, alog.ReqID, Started.ExecutionID)
+	assert.Equal(t, MockFabricName, Started.Fabric)
+	assert.Equal(t, "fabric configure:ConfigureFabric", Started.Command)
+
+	alog.AddDeviceError("10.24.39.1", "Configure Switch", errors.New("Operation Failed"))
+	success := false
+	statusMsg := "Configure Fabric Failed"
+	alog.LogMessageEnd(&success, &statusMsg)
+	Failed := receive(t, Deliveries).Event
+	assert.Equal(t, domain.EventExecutionFailed, Failed.Event)
+	assert.Equal(t, alog.ReqID, Failed.ExecutionID)
+	assert.Contains(t, Failed.Status, logging.FAILED)
+	assert.Equal(t, []domain.DeviceError{{Device: "10.24.39.1", Operation: "Configure Switch",
+		Message: "Operation Failed"}}, Failed.Errors)
+
+	//The completion is not subscribed
+	alog = logging.AuditLog{Request: &logging.Request{Command: "fabric show"}}
+	alog.LogMessageInit()
+	alog.LogMessageReceived()
+	assert.Equal(t, domain.EventExecutionStarted, receive(t, Deliveries).Event.Event)
+	success = true
+	alog.LogMessageEnd(&success, &statusMsg)
+	select {
+	case Delivery := <-Deliveries:
+		t.Errorf("Unexpected %s event", Delivery.Event.Event)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+//The links found missing by the validation are notified
+func TestNotification_MissingLinks(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	Listener, Deliveries := newListener()
+	defer Listener.Close()
+	_, devUC := setupInteractor()
+	devUC.AddNotificationSubscription(ctx, domain.NotificationSubscription{URL: Listener.URL,
+		Events: []string{domain.EventValidationMissingLinks}})
+
+	NoLLDP := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			return []domain.Interface{{FabricID: FabricID, DeviceID: DeviceID, IntType: "ethernet",
+				IntName: "1/11", Mac: DeviceIP, ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			return []domain.LLDP{}, nil
+		},
+	}
+	devUC.DeviceAdapterFactory = mock.GetDeviceAdapterFactory(NoLLDP)
+	_, err := devUC.AddDevices(ctx, MockFabricName, []string{"LEAF1_IP"}, []string{"SPINE1_IP"}, "admin", "password", false)
+	assert.NoError(t, err)
+
+	Response, err := devUC.ValidateFabricTopology(ctx, MockFabricName)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, Response.MissingLinks)
+	Event := receive(t, Deliveries).Event
+	assert.Equal(t, domain.EventValidationMissingLinks, Event.Event)
+	assert.Equal(t, MockFabricName, Event.Fabric)
+	assert.Equal(t, Response.MissingLinks, Event.MissingLinks)
+}
+
+//The failures to log into a device with its credentials are notified
+func TestNotification_CredentialsFailed(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	Listener, Deliveries := newListener()
+	defer Listener.Close()
+	_, devUC := setupInteractor()
+	devUC.AddNotificationSubscription(ctx, domain.NotificationSubscription{URL: Listener.URL,
+		Events: []string{domain.EventDeviceCredentialsFailed}})
+
+	Factory := devUC.NotifyCredentialFailures(func(ctx context.Context, IPAddress string, UserName string,
+		Password string) (Interactor.DeviceAdapter, error) {
+		if IPAddress == "10.24.39.2" {
+			return nil, errors.New("dial tcp 10.24.39.2:22: i/o timeout")
+		}
+		return nil, &domain.DeviceCredentialsError{Device: IPAddress,
+			Err: fmt.Errorf("ssh: handshake failed: ssh: unable to authenticate")}
+	})
+	_, err := Factory(ctx, "10.24.39.2", "admin", "password")
+	assert.Error(t, err)
+	_, err = Factory(ctx, "10.24.39.1", "admin", "password")
+	assert.Contains(t, err.Error(), "unable to authenticate")
+
+	Event := receive(t, Deliveries).Event
+	assert.Equal(t, domain.EventDeviceCredentialsFailed, Event.Event)
+	assert.Equal(t, "10.24.39.1", Event.Device)
+	assert.Equal(t, 1, len(Event.Errors))
+	assert.Contains(t, Event.Errors[0].Message, "admin")
+	assert.Equal(t, 0, len(Deliveries))
+}
+
+//The subscriptions are managed through the REST API, the secret is never returned
+func TestNotification_REST(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Recorder := serve("POST", "/v1/notifications/subscriptions", []byte(`{"url": "file:///tmp/hook"}`))
+	assert.Equal(t, http.StatusBadRequest, Recorder.Code)
+	assert.Contains(t, Recorder.Body.String(), `"error_code":"INVALID_REQUEST"`)
+
+	Recorder = serve("POST", "/v1/notifications/subscriptions",
+		[]byte(`{"url": "http://127.0.0.1:1/hook", "secret": "secret", "events": ["execution.failed"], "retries": 2}`))
+	assert.Equal(t, http.StatusOK, Recorder.Code)
+	assert.NotContains(t, Recorder.Body.String(), "secret\"")
+	var Subscription Restmodel.NotificationSubscription
+	assert.NoError(t, json.Unmarshal(Recorder.Body.Bytes(), &Subscription))
+	assert.True(t, Subscription.Signed)
+	assert.Equal(t, int32(2), Subscription.Retries)
+
+	Recorder = serve("GET", "/v1/notifications/subscriptions", nil)
+	var Subscriptions Restmodel.NotificationSubscriptionsResponse
+	assert.NoError(t, json.Unmarshal(Recorder.Body.Bytes(), &Subscriptions))
+	assert.Equal(t, 1, len(Subscriptions.Subscriptions))
+	assert.Equal(t, []string{domain.EventExecutionFailed}, Subscriptions.Subscriptions[0].Events)
+
+	Recorder = serve("POST", fmt.Sprintf("/v1/notifications/test?id=%d", Subscription.Id), nil)
+	assert.Equal(t, http.StatusBadGateway, Recorder.Code)
+	assert.Contains(t, Recorder.Body.String(), `"error_code":"DELIVERY_FAILED"`)
+
+	Recorder = serve("DELETE", fmt.Sprintf("/v1/notifications/subscription?id=%d", Subscription.Id), nil)
+	assert.Equal(t, http.StatusOK, Recorder.Code)
+	Recorder = serve("DELETE", fmt.Sprintf("/v1/notifications/subscription?id=%d", Subscription.Id), nil)
+	assert.Equal(t, http.StatusNotFound, Recorder.Code)
+	assert.Contains(t, Recorder.Body.String(), `"error_code":"SUBSCRIPTION_NOT_FOUND"`)
+}
+
+func serve(Method string, URL string, Body []byte) *httptest.ResponseRecorder {
+	Recorder := httptest.NewRecorder()
+	openapi.NewRouter().ServeHTTP(Recorder, httptest.NewRequest(Method, URL, bytes.NewReader(Body)))
+	return Recorder
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
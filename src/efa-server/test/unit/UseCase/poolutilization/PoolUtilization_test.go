@@ -0,0 +1,155 @@
+package poolutilization
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var (
+	MockFabricName        = "efa-test"
+	PoolUtilizationDBName = constants.TESTDBLocation + "pool-utilization"
+)
+
+func findPool(Response usecase.PoolUtilizationResponse, PoolType string, PoolName string) usecase.PoolUtilization {
+	for _, Pool := range Response.Pools {
+		if Pool.PoolType == PoolType && Pool.PoolName == PoolName {
+			return Pool
+		}
+	}
+	return usecase.PoolUtilization{}
+}
+
+func TestPoolUtilization_UsedAndFree(t *testing.T) {
+	database.Setup(PoolUtilizationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+
+	Devices := make([]domain.Device, 4)
+	for iter := range Devices {
+		Devices[iter] = domain.Device{IPAddress: fmt.Sprintf("10.24.80.%d", iter+1), FabricID: Fabric.ID}
+		devUC.Db.CreateDevice(&Devices[iter])
+	}
+	Interface := domain.Interface{FabricID: Fabric.ID, DeviceID: Devices[0].ID, IntType: domain.IntfTypeLoopback, IntName: "1"}
+	devUC.Db.CreateInterface(&Interface)
+
+	//Both spines share the single ASN of the Spine pool
+	devUC.GetASN(ctx, Fabric.ID, Devices[0].ID, usecase.SpineRole)
+	devUC.GetASN(ctx, Fabric.ID, Devices[1].ID, usecase.SpineRole)
+	ip, err := devUC.GetIP(ctx, Fabric.ID, Devices[0].ID, "Loopback", Interface.ID)
+	assert.Nil(t, err)
+
+	Response, err := devUC.GetPoolUtilization(ctx, MockFabricName)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(usecase.DefaultPoolWarningThreshold), Response.Threshold)
+
+	Spine := findPool(Response, domain.PoolTypeASN, usecase.SpineRole)
+	assert.Equal(t, uint64(1), Spine.Used)
+	assert.Equal(t, uint64(0), Spine.Free)
+	assert.Equal(t, 2, len(Spine.Allocations))
+
+	Loopback := findPool(Response, domain.PoolTypeIP, "Loopback")
+	assert.Equal(t, "172.31.254.0/24", Loopback.Range)
+	assert.Equal(t, uint64(1), Loopback.Used)
+	assert.Equal(t, uint64(252), Loopback.Free)
+	assert.Equal(t, []usecase.PoolAllocation{{Value: ip, IPAddress: Devices[0].IPAddress,
+		InterfaceName: domain.IntfTypeLoopback + " 1"}}, Loopback.Allocations)
+
+	//A single value pool is shared and is not reported
+	assert.Equal(t, 0, len(Response.Warnings))
+}
+
+func TestPoolUtilization_Warnings(t *testing.T) {
+	database.Setup(PoolUtilizationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	assert.Nil(t, devUC.ResizeASN(ctx, MockFabricName, "65000-65534", "65000-65003", Fabric.ID, usecase.LeafRole))
+
+	Devices := make([]domain.Device, 4)
+	for iter := range Devices {
+		Devices[iter] = domain.Device{IPAddress: fmt.Sprintf("10.24.80.%d", iter+1), FabricID: Fabric.ID}
+		devUC.Db.CreateDevice(&Devices[iter])
+	}
+	for iter := 0; iter < 3; iter++ {
+		devUC.GetASN(ctx, Fabric.ID, Devices[iter].ID, usecase.LeafRole)
+	}
+
+	//75% is below the default threshold
+	Response, _ := devUC.GetPoolUtilization(ctx, MockFabricName)
+	Leaf := findPool(Response, domain.PoolTypeASN, usecase.LeafRole)
+	assert.Equal(t, uint64(3), Leaf.Used)
+	assert.Equal(t, uint64(1), Leaf.Free)
+	assert.Equal(t, uint64(75), Leaf.Utilization)
+	assert.Equal(t, 0, len(Response.Warnings))
+
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	FabricProperties.PoolWarningThreshold = "70"
+	DatabaseRepository.UpdateFabricProperties(&FabricProperties)
+	Response, _ = devUC.GetPoolUtilization(ctx, MockFabricName)
+	assert.Equal(t, []string{"ASN pool Leaf is 75% used, 1 of 4 values are free"}, Response.Warnings)
+
+	//The last ASN is shared once allocated, the pool is exhausted
+	devUC.GetASN(ctx, Fabric.ID, Devices[3].ID, usecase.LeafRole)
+	Response, _ = devUC.GetPoolUtilization(ctx, MockFabricName)
+	assert.Equal(t, []string{"ASN pool Leaf is exhausted, all 4 values are used"}, Response.Warnings)
+}
+
+//The metrics do not wait for the RestLock held by a configure, only for the transaction in progress
+func TestPoolUtilization_Metrics(t *testing.T) {
+	database.Setup(PoolUtilizationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	ctx := context.Background()
+	assert.Nil(t, devUC.AddFabric(ctx, MockFabricName))
+
+	constants.RestLock.Lock()
+	Response, err := devUC.GetPoolUtilizationMetrics(ctx, MockFabricName)
+	constants.RestLock.Unlock()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, Response.Pools)
+
+	devUC.DBMutex.Lock()
+	Done := make(chan bool)
+	go func() {
+		devUC.GetPoolUtilizationMetrics(ctx, MockFabricName)
+		close(Done)
+	}()
+	select {
+	case <-Done:
+		t.Error("The pools are read in the middle of a transaction")
+	case <-time.After(100 * time.Millisecond):
+	}
+	devUC.DBMutex.Unlock()
+	<-Done
+}
+
+func TestPoolUtilization_FabricNotFound(t *testing.T) {
+	database.Setup(PoolUtilizationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+
+	_, err := devUC.GetPoolUtilization(context.Background(), MockFabricName)
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -0,0 +1,297 @@
+package preflight
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "10.24.39.224"
+var MockLeaf1IP = "10.24.39.225"
+var MockSpine2IP = "10.24.39.226"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "pf"
+
+//switchState is the live state of the devices returned by the mock device adapter
+type switchState struct {
+	Firmware  map[string]string
+	Model     map[string]string
+	Speed     map[string]int
+	Mtu       map[string]map[string]int
+	LeafState string
+	LeafIP    string
+	LeafASN   string
+}
+
+//setupInteractor adds a fabric whose spine port 1/11 is discovered by LLDP to be connected to the leaf port 1/22,
+//the second spine is not connected
+func setupInteractor(t *testing.T, State *switchState) *usecase.DeviceInteractor {
+	*State = switchState{Firmware: map[string]string{}, Model: map[string]string{}, Speed: map[string]int{},
+		Mtu: map[string]map[string]int{}, LeafState: "up"}
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			if DeviceIP == MockLeaf1IP {
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: State.LeafState,
+					IPAddress: State.LeafIP}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1" + DeviceIP, ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			switch DeviceIP {
+			case MockSpine1IP:
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1" + MockSpine1IP,
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			case MockLeaf1IP:
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1" + MockSpine1IP}}, nil
+			}
+			return []domain.LLDP{}, nil
+		},
+		MockGetDeviceDetail: func(FabricID uint, DeviceID uint, DeviceIP string) (domain.DeviceDetail, error) {
+			Firmware, found := State.Firmware[DeviceIP]
+			if !found {
+				Firmware = "18r.1.00a"
+			}
+			Model, found := State.Model[DeviceIP]
+			if !found {
+				Model = "3001"
+			}
+			return domain.DeviceDetail{FabricID: FabricID, DeviceID: DeviceID, Model: Model, FirmwareVersion: Firmware}, nil
+		},
+		MockGetASN: func(FabricID uint, DeviceID uint, DeviceIP string) (string, error) {
+			if DeviceIP == MockLeaf1IP {
+				return State.LeafASN, nil
+			}
+			return "", nil
+		},
+		//The speeds are keyed by the interface name, unique across the devices of the fabric
+		MockGetInterfaceSpeed: func(InterfaceType string, InterfaceName string) (int, error) {
+			return State.Speed[InterfaceName], nil
+		},
+		MockGetInterfaceMtus: func(DeviceIP string) (map[string]int, error) {
+			if Mtus, found := State.Mtu[DeviceIP]; found {
+				return Mtus, nil
+			}
+			return map[string]int{}, nil
+		},
+	}
+	MockFabricAdapter := mock.FabricAdapter{
+		MockIsMCTLeavesCompatible: func(ctx context.Context, DeviceModel string, RemoteDeviceModel string) bool {
+			return DeviceModel == RemoteDeviceModel
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: &MockFabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	return devUC
+}
+
+//A fabric whose devices are consistent has no finding
+func TestPreflight_NoFinding(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+	State.Speed = map[string]int{"1/11": 100000, "1/22": 100000}
+
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Empty(t, Response.Preflight)
+}
+
+//The ends of a link running at different speeds are an error, a shut down interface a warning
+func TestPreflight_SpeedAndAdminDown(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+	State.Speed = map[string]int{"1/11": 100000, "1/22": 40000}
+	State.LeafState = "down"
+
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PreflightFinding{
+		{Check: domain.PreflightCheckSpeed, Severity: domain.PreflightError, Device: MockSpine1IP, Interface: "1/11",
+			Message: "10.24.39.224 1/11 runs at 100000, 10.24.39.225 1/22 at 40000"},
+		{Check: domain.PreflightCheckAdminDown, Severity: domain.PreflightWarning, Device: MockLeaf1IP, Interface: "1/22",
+			Message: "10.24.39.225 1/22 is shut down"},
+	}, Response.Preflight)
+}
+
+//The ends of a link running different MTUs are an error, the MTU configured on both ends which configure
+//keeps in place of the MTU setting a warning
+func TestPreflight_MTU(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+
+	State.Mtu[MockLeaf1IP] = map[string]int{"1/22": 1500}
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PreflightFinding{
+		{Check: domain.PreflightCheckMTU, Severity: domain.PreflightError, Device: MockSpine1IP, Interface: "1/11",
+			Message: "10.24.39.224 1/11 has MTU 9216, 10.24.39.225 1/22 MTU 1500"},
+	}, Response.Preflight)
+
+	State.Mtu[MockSpine1IP] = map[string]int{"1/11": 1500}
+	Response, err = devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PreflightFinding{
+		{Check: domain.PreflightCheckMTU, Severity: domain.PreflightWarning, Device: MockSpine1IP, Interface: "1/11",
+			Message: "10.24.39.224 1/11 has MTU 1500 configured, the MTU setting is 9216"},
+		{Check: domain.PreflightCheckMTU, Severity: domain.PreflightWarning, Device: MockLeaf1IP, Interface: "1/22",
+			Message: "10.24.39.225 1/22 has MTU 1500 configured, the MTU setting is 9216"},
+	}, Response.Preflight)
+
+	//The MTU setting of the leaf overridden to the MTU configured on its interface differs from the one of the spine
+	State.Mtu[MockSpine1IP] = map[string]int{}
+	_, err = devUC.UpdateDeviceSettings(context.Background(), MockFabricName, MockLeaf1IP,
+		&domain.DeviceSettings{MTU: "1500"})
+	assert.NoError(t, err)
+	Response, err = devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PreflightFinding{
+		{Check: domain.PreflightCheckMTU, Severity: domain.PreflightError, Device: MockSpine1IP, Interface: "1/11",
+			Message: "10.24.39.224 1/11 has MTU 9216, 10.24.39.225 1/22 MTU 1500"},
+	}, Response.Preflight)
+}
+
+//The devices of an MCT pair whose models are not compatible are an error, whatever their firmware
+func TestPreflight_MctCompatibility(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+
+	Fabric, err := devUC.Db.GetFabric(MockFabricName)
+	assert.NoError(t, err)
+	Spine1, err := devUC.Db.GetDevice(MockFabricName, MockSpine1IP)
+	assert.NoError(t, err)
+	Spine2, err := devUC.Db.GetDevice(MockFabricName, MockSpine2IP)
+	assert.NoError(t, err)
+	assert.NoError(t, devUC.Db.CreateMctClusters(&domain.MctClusterConfig{ClusterID: 1, FabricID: Fabric.ID,
+		DeviceID: Spine1.ID, MCTNeighborDeviceID: Spine2.ID, ConfigType: domain.ConfigCreate}))
+
+	State.Firmware[MockSpine2IP] = "18r.1.00b"
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	for _, Finding := range Response.Preflight {
+		assert.NotEqual(t, domain.PreflightError, Finding.Severity)
+	}
+
+	State.Model[MockSpine2IP] = "4000"
+	Response, err = devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Contains(t, Response.Preflight, domain.PreflightFinding{Check: domain.PreflightCheckFirmware,
+		Severity: domain.PreflightError, Device: MockSpine1IP,
+		Message: "The MCT pair 10.24.39.224 and 10.24.39.226 is not compatible, the models are 3001 and 4000"})
+}
+
+//The spines running different firmware are a warning
+func TestPreflight_SpineFirmware(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+	State.Firmware[MockSpine2IP] = "20.1.1"
+
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PreflightFinding{
+		{Check: domain.PreflightCheckFirmware, Severity: domain.PreflightWarning,
+			Message: "The spines run different firmware: 10.24.39.224 runs 18r.1.00a, 10.24.39.226 runs 20.1.1"},
+	}, Response.Preflight)
+}
+
+//The IP address of an interface and the local AS of the device which configure would replace are errors,
+//the configuration generated when the devices are added is compared with the one of the switch
+func TestPreflight_ConfigReplaced(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+
+	Fabric, err := devUC.Db.GetFabric(MockFabricName)
+	assert.NoError(t, err)
+	Leaf, err := devUC.Db.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.NoError(t, err)
+	SwitchConfig, err := devUC.Db.GetSwitchConfigOnFabricIDAndDeviceID(Fabric.ID, Leaf.ID)
+	assert.NoError(t, err)
+	InterfaceConfigs, err := devUC.Db.GetInterfaceSwitchConfigsOnDeviceID(Fabric.ID, Leaf.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(InterfaceConfigs))
+	assert.Equal(t, "1/22", InterfaceConfigs[0].IntName)
+
+	//The planned configuration is already on the leaf
+	State.LeafIP, State.LeafASN = InterfaceConfigs[0].IPAddress+"/31", SwitchConfig.LocalAS
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Empty(t, Response.Preflight)
+
+	State.LeafIP, State.LeafASN = "192.168.1.1/24", "64512"
+	Response, err = devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PreflightFinding{
+		{Check: domain.PreflightCheckIPConfig, Severity: domain.PreflightError, Device: MockLeaf1IP, Interface: "1/22",
+			Message: "10.24.39.225 1/22 has IP address 192.168.1.1/24, configure replaces it with " +
+				InterfaceConfigs[0].IPAddress + "/31"},
+		{Check: domain.PreflightCheckLocalAS, Severity: domain.PreflightError, Device: MockLeaf1IP,
+			Message: "10.24.39.225 router bgp has local-as 64512, configure replaces it with " + SwitchConfig.LocalAS},
+	}, Response.Preflight)
+}
+
+//The findings of the overridden checks are reported as overridden, an unknown check cannot be overridden
+func TestPreflight_Override(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+	State.Speed = map[string]int{"1/11": 100000, "1/22": 40000}
+
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{domain.PreflightCheckSpeed})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(Response.Preflight))
+	assert.True(t, Response.Preflight[0].Overridden)
+
+	_, err = devUC.ValidateFabric(context.Background(), MockFabricName, []string{"cabling"})
+	assert.Equal(t, domain.ErrPreflightOverrideInvalid, err)
+}
+
+//The devices which cannot be reached are reported, without failing the validation
+func TestPreflight_ConnectionFailed(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	var State switchState
+	devUC := setupInteractor(t, &State)
+	devUC.DeviceAdapterFactory = mock.DeviceAdapterFactoryFailed
+
+	Response, err := devUC.ValidateFabric(context.Background(), MockFabricName, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(Response.Preflight))
+	for _, Finding := range Response.Preflight {
+		assert.Equal(t, domain.PreflightCheckConnection, Finding.Check)
+		assert.Equal(t, domain.PreflightError, Finding.Severity)
+	}
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
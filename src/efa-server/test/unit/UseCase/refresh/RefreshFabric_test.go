@@ -0,0 +1,206 @@
+package refresh
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "ipaddress_spine1"
+var MockSpine2IP = "ipaddress_spine2"
+var MockLeaf1IP = "ipaddress_leaf1"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "rf"
+
+type port struct {
+	Device string
+	Name   string
+	Mac    string
+}
+
+var Ports = []port{
+	{MockSpine1IP, "1/11", "S11"}, {MockSpine1IP, "1/12", "S12"}, {MockSpine2IP, "1/21", "S21"},
+	{MockLeaf1IP, "1/1", "L1"}, {MockLeaf1IP, "1/2", "L2"},
+}
+
+//setupInteractor sets up devices whose LLDP follows Cabling, a map of the cabled Macs in both directions
+func setupInteractor(FabricAdapter *mock.FabricAdapter, Cabling *map[string]string) (*gateway.DatabaseRepository,
+	*usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			Interfaces := make([]domain.Interface, 0)
+			for _, p := range Ports {
+				if p.Device == DeviceIP {
+					Interfaces = append(Interfaces, domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+						IntType: domain.IntfTypeEthernet, IntName: p.Name, Mac: p.Mac, ConfigState: "up"})
+				}
+			}
+			return Interfaces, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			LLDPs := make([]domain.LLDP, 0)
+			for _, p := range Ports {
+				RemoteMac, ok := (*Cabling)[p.Mac]
+				if p.Device != DeviceIP || !ok {
+					continue
+				}
+				for _, r := range Ports {
+					if r.Mac == RemoteMac {
+						LLDPs = append(LLDPs, domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+							LocalIntType: domain.IntfTypeEthernet, LocalIntName: p.Name, LocalIntMac: p.Mac,
+							RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: r.Name, RemoteIntMac: r.Mac})
+					}
+				}
+			}
+			return LLDPs, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: FabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+func getHost(Hosts []operation.ConfigSwitch, IPAddress string) (operation.ConfigSwitch, bool) {
+	for _, host := range Hosts {
+		if host.Host == IPAddress {
+			return host, true
+		}
+	}
+	return operation.ConfigSwitch{}, false
+}
+
+//A moved and an added link are pushed on their ends only
+func TestRefreshFabric(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var ConfiguredHosts []operation.ConfigSwitch
+	Configured := false
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureLinks: func(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError {
+			Configured = true
+			ConfiguredHosts = config.Hosts
+			return []actions.OperationError{}
+		},
+	}
+	Cabling := map[string]string{"L1": "S11", "S11": "L1"}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter, &Cabling)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.ConfigureFabric(context.Background(), MockFabricName, false, true)
+	assert.NoError(t, err)
+
+	//Nothing changed
+	response, err := devUC.RefreshFabric(context.Background(), MockFabricName, "")
+	assert.NoError(t, err)
+	assert.Empty(t, response.AddedLinks)
+	assert.Empty(t, response.RemovedLinks)
+	assert.Empty(t, response.MovedLinks)
+	assert.False(t, Configured)
+
+	//1/1 moves from spine1 to spine2 and 1/2 is cabled to spine1
+	Cabling = map[string]string{"L1": "S21", "S21": "L1", "L2": "S12", "S12": "L2"}
+	response, err = devUC.RefreshFabric(context.Background(), MockFabricName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(response.MovedLinks))
+	assert.Contains(t, response.MovedLinks[0], "1/11")
+	assert.Contains(t, response.MovedLinks[0], "1/21")
+	assert.Equal(t, 1, len(response.AddedLinks))
+	assert.Contains(t, response.AddedLinks[0], "1/12")
+	assert.Empty(t, response.RemovedLinks)
+
+	assert.True(t, Configured)
+	assert.Equal(t, 3, len(ConfiguredHosts))
+	Spine1, ok := getHost(ConfiguredHosts, MockSpine1IP)
+	assert.True(t, ok)
+	ConfigTypes := make(map[string]string)
+	for _, intf := range Spine1.Interfaces {
+		ConfigTypes[intf.InterfaceName] = intf.ConfigType
+	}
+	assert.Equal(t, domain.ConfigDelete, ConfigTypes["1/11"])
+	assert.Contains(t, []string{domain.ConfigCreate, domain.ConfigUpdate}, ConfigTypes["1/12"])
+	Spine2, ok := getHost(ConfiguredHosts, MockSpine2IP)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(Spine2.Interfaces))
+	assert.Equal(t, 1, len(Spine2.BgpNeighbors))
+
+	//The pushed links are configured and the removed link is dropped
+	Leaf, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	Neighbors, _ := DatabaseRepository.GetLLDPNeighborsOnEitherDevice(Leaf.FabricID, Leaf.ID)
+	assert.Equal(t, 4, len(Neighbors))
+	Spine, _ := DatabaseRepository.GetDevice(MockFabricName, MockSpine2IP)
+	Interface, _ := DatabaseRepository.GetInterface(Spine.FabricID, Spine.ID, domain.IntfTypeEthernet, "1/21")
+	Config, err := DatabaseRepository.GetInterfaceSwitchConfigOnFabricIDAndInterfaceID(Spine.FabricID, Interface.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ConfigNone, Config.ConfigType)
+}
+
+//A refresh of a device re-discovers the device and its peers only
+func TestRefreshFabric_Device(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var ConfiguredHosts []operation.ConfigSwitch
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureLinks: func(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError {
+			ConfiguredHosts = config.Hosts
+			return []actions.OperationError{}
+		},
+	}
+	Cabling := map[string]string{"L1": "S11", "S11": "L1", "L2": "S21", "S21": "L2"}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter, &Cabling)
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.ConfigureFabric(context.Background(), MockFabricName, false, true)
+	assert.NoError(t, err)
+	//The links are pending a configure
+	Spine1, _ := DatabaseRepository.GetDevice(MockFabricName, MockSpine1IP)
+	assert.NoError(t, DatabaseRepository.UpdateLLDPConfigType(Spine1.FabricID, []string{domain.ConfigNone},
+		domain.ConfigUpdate))
+
+	//The link to spine2 is unplugged
+	Cabling = map[string]string{"L1": "S11", "S11": "L1"}
+	response, err := devUC.RefreshFabric(context.Background(), MockFabricName, MockSpine2IP)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(response.RemovedLinks))
+	assert.Contains(t, response.RemovedLinks[0], "1/21")
+	assert.Empty(t, response.AddedLinks)
+	assert.Empty(t, response.MovedLinks)
+	assert.Equal(t, 2, len(ConfiguredHosts))
+	_, ok := getHost(ConfiguredHosts, MockSpine1IP)
+	assert.False(t, ok)
+
+	//Only the LLDP of the refreshed links is settled, the link to spine1 stays pending
+	Neighbors, _ := DatabaseRepository.GetLLDPNeighborsOnEitherDevice(Spine1.FabricID, Spine1.ID)
+	assert.NotEqual(t, 0, len(Neighbors))
+	for _, Neighbor := range Neighbors {
+		assert.Equal(t, domain.ConfigUpdate, Neighbor.ConfigType)
+	}
+	Spine2, _ := DatabaseRepository.GetDevice(MockFabricName, MockSpine2IP)
+	Neighbors, _ = DatabaseRepository.GetLLDPNeighborsOnEitherDevice(Spine2.FabricID, Spine2.ID)
+	assert.Equal(t, 0, len(Neighbors))
+
+	_, err = devUC.RefreshFabric(context.Background(), MockFabricName, "unknown_device")
+	assert.Equal(t, domain.ErrDeviceNotFound, err)
+	_, err = devUC.RefreshFabric(context.Background(), "unknown_fabric", "")
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
@@ -0,0 +1,201 @@
+package replacedevice
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/infra/device/actions"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "ipaddress_spine1"
+var MockLeaf1IP = "ipaddress_leaf1"
+var MockLeaf2IP = "ipaddress_leaf2"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "rd"
+
+//setupInteractor sets up a spine and a leaf connected on 1/11 - 1/22. The replacement of the leaf
+//is reachable on MockLeaf2IP and is cabled to the spine on ReplacementPort
+func setupInteractor(FabricAdapter *mock.FabricAdapter, ReplacementModel string,
+	ReplacementPort string) (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			switch DeviceIP {
+			case MockSpine1IP:
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/11", Mac: "M1", ConfigState: "up"}}, nil
+			case MockLeaf2IP:
+				return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+					IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M3", ConfigState: "up"}}, nil
+			}
+			return []domain.Interface{domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+				IntType: domain.IntfTypeEthernet, IntName: "1/22", Mac: "M2", ConfigState: "up"}}, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			switch DeviceIP {
+			case MockSpine1IP:
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/11", LocalIntMac: "M1",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/22", RemoteIntMac: "M2"}}, nil
+			case MockLeaf2IP:
+				return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+					LocalIntType: domain.IntfTypeEthernet, LocalIntName: ReplacementPort, LocalIntMac: "M3",
+					RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+			}
+			return []domain.LLDP{domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+				LocalIntType: domain.IntfTypeEthernet, LocalIntName: "1/22", LocalIntMac: "M2",
+				RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: "1/11", RemoteIntMac: "M1"}}, nil
+		},
+		MockGetDeviceDetail: func(FabricID uint, DeviceID uint, DeviceIP string) (domain.DeviceDetail, error) {
+			if DeviceIP == MockLeaf2IP {
+				return domain.DeviceDetail{Model: ReplacementModel, FirmwareVersion: "18r.1.00b"}, nil
+			}
+			return domain.DeviceDetail{Model: "3001", FirmwareVersion: "18r.1.00a"}, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: FabricAdapter}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+//The replacement takes over the device, its allocations and its configs
+func TestReplaceDevice(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var ConfiguredHosts []operation.ConfigSwitch
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureFabric: func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError {
+			ConfiguredHosts = config.Hosts
+			return []actions.OperationError{}
+		},
+	}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter, "3001", "1/22")
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+
+	OldDevice, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	OldSwitchConfig, _ := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockLeaf1IP)
+	SpineSwitchConfig, _ := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockSpine1IP)
+
+	ret, err := devUC.ReplaceDevice(context.Background(), MockFabricName, MockLeaf1IP, MockLeaf2IP, "", "")
+	assert.NoError(t, err)
+	assert.Contains(t, ret, MockLeaf2IP)
+
+	//Only the replacement is configured
+	assert.Equal(t, 1, len(ConfiguredHosts))
+	assert.Equal(t, MockLeaf2IP, ConfiguredHosts[0].Host)
+
+	_, err = DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.Error(t, err)
+	NewDevice, err := DatabaseRepository.GetDevice(MockFabricName, MockLeaf2IP)
+	assert.NoError(t, err)
+	assert.Equal(t, OldDevice.ID, NewDevice.ID)
+	assert.Equal(t, "18r.1.00b", NewDevice.FirmwareVersion)
+	assert.Equal(t, OldDevice.UserName, NewDevice.UserName)
+	assert.Equal(t, OldDevice.Password, NewDevice.Password)
+
+	//ASN and Loopback allocations are kept
+	NewSwitchConfig, err := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockLeaf2IP)
+	assert.NoError(t, err)
+	assert.Equal(t, OldSwitchConfig.ID, NewSwitchConfig.ID)
+	assert.Equal(t, OldSwitchConfig.LocalAS, NewSwitchConfig.LocalAS)
+	assert.Equal(t, OldSwitchConfig.LoopbackIP, NewSwitchConfig.LoopbackIP)
+	NewSpineSwitchConfig, _ := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockSpine1IP)
+	assert.Equal(t, SpineSwitchConfig, NewSpineSwitchConfig)
+
+	//The neighbour now sees the interface of the replacement
+	SpineLLDP, err := DatabaseRepository.GetLLDPOnRemoteMacExcludingMarkedForDeletion("M3", OldDevice.FabricID)
+	assert.NoError(t, err)
+	assert.Equal(t, "1/11", SpineLLDP.LocalIntName)
+	Interface, _ := DatabaseRepository.GetInterface(OldDevice.FabricID, OldDevice.ID, domain.IntfTypeEthernet, "1/22")
+	assert.Equal(t, "M3", Interface.Mac)
+}
+
+//The replacement of a device in maintenance mode is drained once configured
+func TestReplaceDevice_MaintenanceMode(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	var DrainedHosts []operation.ConfigSwitch
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureFabric: func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError {
+			return []actions.OperationError{}
+		},
+		MockConfigureMaintenanceMode: func(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+			DrainedHosts = config.Hosts
+			return []actions.OperationError{}
+		},
+	}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter, "3001", "1/22")
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+	_, err = devUC.UpdateMaintenanceMode(context.Background(), MockFabricName, MockLeaf1IP, true)
+	assert.NoError(t, err)
+
+	_, err = devUC.ReplaceDevice(context.Background(), MockFabricName, MockLeaf1IP, MockLeaf2IP, "", "")
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(DrainedHosts)) {
+		assert.Equal(t, MockLeaf2IP, DrainedHosts[0].Host)
+	}
+	NewDevice, _ := DatabaseRepository.GetDevice(MockFabricName, MockLeaf2IP)
+	assert.True(t, NewDevice.MaintenanceMode)
+}
+
+//The replacement is refused when it does not match the replaced device
+func TestReplaceDevice_Mismatch(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Configured := false
+	FabricAdapter := &mock.FabricAdapter{
+		MockConfigureFabric: func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError {
+			Configured = true
+			return []actions.OperationError{}
+		},
+	}
+	DatabaseRepository, devUC := setupInteractor(FabricAdapter, "3001", "1/23")
+	_, err := devUC.AddDevices(context.Background(), MockFabricName, []string{MockLeaf1IP}, []string{MockSpine1IP},
+		UserName, Password, false)
+	assert.NoError(t, err)
+	Configured = false
+
+	//Cabled on a different port
+	ret, err := devUC.ReplaceDevice(context.Background(), MockFabricName, MockLeaf1IP, MockLeaf2IP, "", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	assert.Contains(t, ret, "1/22")
+	assert.False(t, Configured)
+	_, err = DatabaseRepository.GetDevice(MockFabricName, MockLeaf1IP)
+	assert.NoError(t, err)
+
+	//Different model
+	_, devUC = setupInteractor(FabricAdapter, "4000", "1/22")
+	ret, err = devUC.ReplaceDevice(context.Background(), MockFabricName, MockLeaf1IP, MockLeaf2IP, "", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	assert.Contains(t, ret, "Model")
+	assert.False(t, Configured)
+
+	_, err = devUC.ReplaceDevice(context.Background(), MockFabricName, MockLeaf1IP, MockSpine1IP, "", "")
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	_, err = devUC.ReplaceDevice(context.Background(), MockFabricName, "unknown_device", MockLeaf2IP, "", "")
+	assert.Equal(t, domain.ErrDeviceNotFound, err)
+	_, err = devUC.ReplaceDevice(context.Background(), "unknown_fabric", MockLeaf1IP, MockLeaf2IP, "", "")
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
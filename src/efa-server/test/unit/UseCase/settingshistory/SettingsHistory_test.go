@@ -0,0 +1,160 @@
+package settingshistory
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var dbExtension = "settingshistory"
+
+func setupInteractor() (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(mock.DeviceAdapter{}),
+		FabricAdapter: &mock.FabricAdapter{}}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+func userContext(UserName string, ExecutionID string) context.Context {
+	ctx := context.WithValue(context.Background(), appcontext.RequestIDKey, ExecutionID)
+	return context.WithValue(ctx, appcontext.UserName, UserName)
+}
+
+//Each changed setting is recorded with the user and the execution of the update, the most recent first
+func TestSettingsHistory_Record(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	_, devUC := setupInteractor()
+	_, _, err := devUC.UpdateFabricProperties(userContext("alice", "exec-1"), MockFabricName,
+		&domain.FabricProperties{MTU: "9000"})
+	assert.NoError(t, err)
+	_, _, err = devUC.UpdateFabricProperties(context.Background(), MockFabricName,
+		&domain.FabricProperties{MTU: "8000", LeafASNBlock: "65100-65200"})
+	assert.NoError(t, err)
+
+	Changes, _, err := devUC.GetFabricSettingsHistory(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(Changes))
+	Last := map[string]domain.FabricSettingChange{}
+	for _, Change := range Changes[:2] {
+		assert.Equal(t, usecase.UnknownUserName, Change.UserName)
+		Last[Change.Setting] = Change
+	}
+	assert.Equal(t, "9000", Last["MTU"].OldValue)
+	assert.Equal(t, "8000", Last["MTU"].NewValue)
+	assert.Equal(t, "65000-65534", Last["LeafASNBlock"].OldValue)
+	assert.Equal(t, "65100-65200", Last["LeafASNBlock"].NewValue)
+
+	First := Changes[2]
+	assert.True(t, First.ID < Changes[1].ID)
+	assert.Equal(t, domain.FabricSettingChange{ID: First.ID, FabricID: First.FabricID, Setting: "MTU", OldValue: "9216",
+		NewValue: "9000", UserName: "alice", ExecutionID: "exec-1", ChangedAt: First.ChangedAt}, First)
+	assert.False(t, First.ChangedAt.IsZero())
+
+	_, _, err = devUC.GetFabricSettingsHistory(context.Background(), "unknown_fabric")
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+//The passwords are stored encrypted in the history
+func TestSettingsHistory_Password(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	DatabaseRepository, devUC := setupInteractor()
+	_, _, err := devUC.UpdateFabricProperties(context.Background(), MockFabricName,
+		&domain.FabricProperties{BGPAuthType: domain.BGPAuthTypeMD5, PeerGroupPassword: "secret"})
+	assert.NoError(t, err)
+
+	Changes, _, err := devUC.GetFabricSettingsHistory(context.Background(), MockFabricName)
+	assert.NoError(t, err)
+	Values := map[string]string{}
+	for _, Change := range Changes {
+		Values[Change.Setting] = Change.NewValue
+	}
+	assert.Equal(t, "secret", Values["PeerGroupPassword"])
+
+	var Stored database.FabricSettingChange
+	DatabaseRepository.GetDBHandle().Where("setting = ?", "PeerGroupPassword").First(&Stored)
+	assert.NotEmpty(t, Stored.NewValue)
+	assert.NotEqual(t, "secret", Stored.NewValue)
+}
+
+//A rollback restores the settings changed after the change it rolls back to
+func TestSettingsHistory_Rollback(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	DatabaseRepository, devUC := setupInteractor()
+	devUC.UpdateFabricProperties(ctx, MockFabricName, &domain.FabricProperties{MTU: "9000"})
+	devUC.UpdateFabricProperties(ctx, MockFabricName, &domain.FabricProperties{MTU: "8000"})
+	devUC.UpdateFabricProperties(ctx, MockFabricName, &domain.FabricProperties{LeafASNBlock: "65100-65200"})
+	Changes, _, _ := devUC.GetFabricSettingsHistory(ctx, MockFabricName)
+	assert.Equal(t, 3, len(Changes))
+	ToID := Changes[2].ID
+
+	Rollback, Settings, _, err := devUC.PrepareFabricSettingsRollback(ctx, MockFabricName, ToID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FabricProperties{MTU: "9000", LeafASNBlock: "65000-65534"}, Rollback)
+	assert.Equal(t, 2, len(Settings))
+	for _, Setting := range Settings {
+		assert.Equal(t, domain.ChangeKindSetting, Setting.Kind)
+	}
+
+	_, _, err = devUC.UpdateFabricProperties(userContext("bob", "exec-2"), MockFabricName, &Rollback)
+	assert.NoError(t, err)
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Equal(t, "9000", FabricProperties.MTU)
+	assert.Equal(t, "65000-65534", FabricProperties.LeafASNBlock)
+
+	//The rollback is recorded as any update
+	Changes, _, _ = devUC.GetFabricSettingsHistory(ctx, MockFabricName)
+	assert.Equal(t, 5, len(Changes))
+	assert.Equal(t, "bob", Changes[0].UserName)
+
+	//The settings are already as of the change
+	_, _, _, err = devUC.PrepareFabricSettingsRollback(ctx, MockFabricName, ToID)
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	_, _, _, err = devUC.PrepareFabricSettingsRollback(ctx, MockFabricName, 1000)
+	assert.Equal(t, domain.ErrSettingChangeNotFound, err)
+	_, _, _, err = devUC.PrepareFabricSettingsRollback(ctx, "unknown_fabric", ToID)
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+}
+
+//A password set after the change is removed by the rollback
+func TestSettingsHistory_RollbackPassword(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	DatabaseRepository, devUC := setupInteractor()
+	devUC.UpdateFabricProperties(ctx, MockFabricName, &domain.FabricProperties{MTU: "9000"})
+	devUC.UpdateFabricProperties(ctx, MockFabricName,
+		&domain.FabricProperties{BGPAuthType: domain.BGPAuthTypeMD5, PeerGroupPassword: "secret"})
+	Changes, _, _ := devUC.GetFabricSettingsHistory(ctx, MockFabricName)
+	ToID := Changes[len(Changes)-1].ID
+
+	Rollback, _, _, err := devUC.PrepareFabricSettingsRollback(ctx, MockFabricName, ToID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BGPPasswordNone, Rollback.PeerGroupPassword)
+	_, _, err = devUC.UpdateFabricProperties(ctx, MockFabricName, &Rollback)
+	assert.NoError(t, err)
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Empty(t, FabricProperties.PeerGroupPassword)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
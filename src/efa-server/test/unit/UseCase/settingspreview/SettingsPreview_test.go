@@ -0,0 +1,179 @@
+package settingspreview
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var MockFabricName = "test_fabric"
+var MockSpine1IP = "10.24.80.1"
+var MockSpine2IP = "10.24.80.2"
+var MockLeaf1IP = "10.24.80.3"
+var UserName = "admin"
+var Password = "password"
+var dbExtension = "settingspreview"
+
+type port struct {
+	Device string
+	Name   string
+	Mac    string
+	Remote string
+}
+
+//The leaf is cabled to both spines
+var Ports = []port{
+	{MockSpine1IP, "1/11", "S11", "L1"}, {MockSpine2IP, "1/21", "S21", "L2"},
+	{MockLeaf1IP, "1/1", "L1", "S11"}, {MockLeaf1IP, "1/2", "L2", "S21"},
+}
+
+func setupInteractor() (*gateway.DatabaseRepository, *usecase.DeviceInteractor) {
+	MockDeviceAdapter := mock.DeviceAdapter{
+		MockGetInterfaces: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.Interface, error) {
+			Interfaces := make([]domain.Interface, 0)
+			for _, p := range Ports {
+				if p.Device == DeviceIP {
+					Interfaces = append(Interfaces, domain.Interface{FabricID: FabricID, DeviceID: DeviceID,
+						IntType: domain.IntfTypeEthernet, IntName: p.Name, Mac: p.Mac, ConfigState: "up"})
+				}
+			}
+			return Interfaces, nil
+		},
+		MockGetLLDPs: func(FabricID uint, DeviceID uint, DeviceIP string) ([]domain.LLDP, error) {
+			LLDPs := make([]domain.LLDP, 0)
+			for _, p := range Ports {
+				if p.Device != DeviceIP {
+					continue
+				}
+				for _, r := range Ports {
+					if r.Mac == p.Remote {
+						LLDPs = append(LLDPs, domain.LLDP{FabricID: FabricID, DeviceID: DeviceID,
+							LocalIntType: domain.IntfTypeEthernet, LocalIntName: p.Name, LocalIntMac: p.Mac,
+							RemoteIntType: domain.IntfTypeEthernet, RemoteIntName: r.Name, RemoteIntMac: r.Mac})
+					}
+				}
+			}
+			return LLDPs, nil
+		},
+	}
+
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.GetDeviceAdapterFactory(MockDeviceAdapter),
+		FabricAdapter: &mock.FabricAdapter{}}
+	devUC.AddFabric(context.Background(), MockFabricName)
+	return DatabaseRepository, devUC
+}
+
+//The preview lists the changes of each device and the values outside the new ranges, and saves nothing
+func TestSettingsPreview_ActiveFabric(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	DatabaseRepository, devUC := setupInteractor()
+	_, err := devUC.AddDevices(ctx, MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+	LeafConfig, _ := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockLeaf1IP)
+
+	Preview, _, err := devUC.PreviewFabricProperties(ctx, MockFabricName, &domain.FabricProperties{MTU: "9000",
+		BFDEnable: "Yes", LeafASNBlock: "65100-65200", PeerGroupPassword: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test_fabric: fabric is already active and cannot be updated", Preview.Blocked)
+
+	Settings := make(map[string]domain.ConfigChange)
+	for _, Setting := range Preview.Settings {
+		assert.Equal(t, domain.ChangeKindSetting, Setting.Kind)
+		Settings[Setting.Name] = Setting
+	}
+	assert.Equal(t, 4, len(Settings))
+	assert.Equal(t, "9216", Settings["MTU"].Old)
+	assert.Equal(t, "9000", Settings["MTU"].New)
+	assert.Equal(t, "******", Settings["PeerGroupPassword"].New)
+
+	//Every device changes its MTU and BFD, the peer group password is masked
+	Changes := make(map[string]map[string]domain.ConfigChange)
+	for _, Change := range Preview.Changes {
+		if Changes[Change.DeviceIP] == nil {
+			Changes[Change.DeviceIP] = make(map[string]domain.ConfigChange)
+		}
+		Changes[Change.DeviceIP][Change.Name] = Change
+	}
+	assert.Equal(t, 3, len(Changes))
+	for _, DeviceIP := range []string{MockSpine1IP, MockSpine2IP, MockLeaf1IP} {
+		assert.Equal(t, domain.ChangeKindDevice, Changes[DeviceIP]["Mtu"].Kind)
+		assert.Equal(t, "9216", Changes[DeviceIP]["Mtu"].Old)
+		assert.Equal(t, "9000", Changes[DeviceIP]["Mtu"].New)
+		assert.Equal(t, "Yes", Changes[DeviceIP]["BFDEnable"].New)
+		assert.Equal(t, "******", Changes[DeviceIP]["PeerGroupPassword"].New)
+	}
+
+	//Only the leaf ASN is outside the new leaf block
+	assert.Equal(t, 1, len(Preview.Reallocations))
+	assert.Equal(t, usecase.PoolReallocation{PoolType: domain.PoolTypeASN, PoolName: usecase.LeafRole,
+		Range: "65100-65200", Value: LeafConfig.LocalAS, IPAddress: MockLeaf1IP}, Preview.Reallocations[0])
+
+	//Nothing is saved
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Equal(t, "9216", FabricProperties.MTU)
+	assert.Equal(t, "65000-65534", FabricProperties.LeafASNBlock)
+	SwitchConfig, _ := DatabaseRepository.GetSwitchConfigOnDeviceIP(MockFabricName, MockLeaf1IP)
+	assert.Equal(t, LeafConfig.LocalAS, SwitchConfig.LocalAS)
+}
+
+//A loopback range change reallocates the loopbacks of all the devices
+func TestSettingsPreview_LoopbackRange(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	_, devUC := setupInteractor()
+	_, err := devUC.AddDevices(ctx, MockFabricName, []string{MockLeaf1IP},
+		[]string{MockSpine1IP, MockSpine2IP}, UserName, Password, false)
+	assert.NoError(t, err)
+
+	Preview, _, err := devUC.PreviewFabricProperties(ctx, MockFabricName,
+		&domain.FabricProperties{LoopBackIPRange: "172.31.250.0/24"})
+	assert.NoError(t, err)
+	Devices := make(map[string]bool)
+	for _, Reallocation := range Preview.Reallocations {
+		assert.Equal(t, domain.PoolTypeIP, Reallocation.PoolType)
+		assert.Equal(t, "172.31.250.0/24", Reallocation.Range)
+		Devices[Reallocation.IPAddress] = true
+	}
+	assert.Equal(t, 3, len(Devices))
+}
+
+func TestSettingsPreview_Errors(t *testing.T) {
+	database.Setup(constants.TESTDBLocation + dbExtension)
+	defer cleanupDB(database.GetWorkingInstance())
+	ctx := context.Background()
+
+	_, devUC := setupInteractor()
+
+	//A fabric without devices can be updated, no device is changed
+	Preview, _, err := devUC.PreviewFabricProperties(ctx, MockFabricName, &domain.FabricProperties{MTU: "9000"})
+	assert.NoError(t, err)
+	assert.Empty(t, Preview.Blocked)
+	assert.Empty(t, Preview.Changes)
+	assert.Empty(t, Preview.Reallocations)
+	assert.Equal(t, 1, len(Preview.Settings))
+
+	_, _, err = devUC.PreviewFabricProperties(ctx, "unknown_fabric", &domain.FabricProperties{MTU: "9000"})
+	assert.Equal(t, domain.ErrFabricNotFound, err)
+	_, _, err = devUC.PreviewFabricProperties(ctx, MockFabricName, &domain.FabricProperties{MTU: "9216"})
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	_, _, err = devUC.PreviewFabricProperties(ctx, MockFabricName, &domain.FabricProperties{LoopBackIPRange: "10.10.10.0/24"})
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
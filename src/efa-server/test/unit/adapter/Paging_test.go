@@ -0,0 +1,127 @@
+package adapter
+
+import (
+	ad "efa-server/infra/device/adapter"
+	"efa-server/infra/device/client"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/svatantra/go-netconf/netconf"
+)
+
+//maxRequests bounds the RPCs of a test, an adapter paging forever failing instead of hanging
+const maxRequests = 10
+
+//scriptedSwitch is a NETCONF transport replying to each RPC with the next of the pages scripted for it
+type scriptedSwitch struct {
+	pages    map[string][]string
+	requests []string
+}
+
+func (s *scriptedSwitch) Send(Request []byte) error {
+	if len(s.requests) == maxRequests {
+		return errors.New("too many requests")
+	}
+	for RPC := range s.pages {
+		if strings.Contains(string(Request), "<"+RPC) {
+			s.requests = append(s.requests, RPC)
+			return nil
+		}
+	}
+	s.requests = append(s.requests, "")
+	return nil
+}
+
+func (s *scriptedSwitch) Receive() ([]byte, error) {
+	Page := ""
+	if RPC := s.requests[len(s.requests)-1]; len(s.pages[RPC]) != 0 {
+		Page, s.pages[RPC] = s.pages[RPC][0], s.pages[RPC][1:]
+	}
+	return []byte(`<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="1">` + Page +
+		`</rpc-reply>`), nil
+}
+
+func (s *scriptedSwitch) Close() error {
+	return nil
+}
+
+func (s *scriptedSwitch) ReceiveHello() (*netconf.HelloMessage, error) {
+	return &netconf.HelloMessage{}, nil
+}
+
+func (s *scriptedSwitch) SendHello(*netconf.HelloMessage) error {
+	return nil
+}
+
+func connect(Switch *scriptedSwitch) *client.NetconfClient {
+	return &client.NetconfClient{Host: "ipaddress_leaf1", Session: netconf.NewSession(Switch)}
+}
+
+func lldpNeighbor(Ifindex string, Local string, Remote string) string {
+	return `<lldp-neighbor-detail><local-interface-name>Eth ` + Local + `</local-interface-name>` +
+		`<local-interface-ifindex>` + Ifindex + `</local-interface-ifindex>` +
+		`<local-interface-mac>02:00:00:00:00:01</local-interface-mac>` +
+		`<remote-interface-name>Eth ` + Remote + `</remote-interface-name>` +
+		`<remote-interface-mac>02:00:00:00:00:02</remote-interface-mac></lldp-neighbor-detail>`
+}
+
+func TestGetLLDPNeighbors_NoNeighbors(t *testing.T) {
+	Switch := &scriptedSwitch{pages: map[string][]string{"get-lldp-neighbor-detail": {""}}}
+	Neighbors, err := ad.GetAdapter(ad.FreedomType).GetLLDPNeighbors(connect(Switch))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(Neighbors))
+	assert.Equal(t, []string{"get-lldp-neighbor-detail"}, Switch.requests)
+}
+
+func TestGetLLDPNeighbors_Pages(t *testing.T) {
+	Switch := &scriptedSwitch{pages: map[string][]string{"get-lldp-neighbor-detail": {
+		lldpNeighbor("1", "0/1", "0/3") + `<has-more>true</has-more>`,
+		lldpNeighbor("2", "0/2", "0/4") + `<has-more>false</has-more>`,
+	}}}
+	Neighbors, err := ad.GetAdapter(ad.FreedomType).GetLLDPNeighbors(connect(Switch))
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(Neighbors)) {
+		assert.Equal(t, "0/1", Neighbors[0].LocalInterfaceName)
+		assert.Equal(t, "0/4", Neighbors[1].RemoteInterfaceName)
+	}
+	assert.Equal(t, 2, len(Switch.requests))
+}
+
+func TestGetLLDPNeighbors_NoHasMore(t *testing.T) {
+	Switch := &scriptedSwitch{pages: map[string][]string{"get-lldp-neighbor-detail": {
+		lldpNeighbor("1", "0/1", "0/3"),
+	}}}
+	Neighbors, err := ad.GetAdapter(ad.FreedomType).GetLLDPNeighbors(connect(Switch))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(Neighbors))
+	assert.Equal(t, 1, len(Switch.requests))
+}
+
+func TestGetInterfaces_MacPages(t *testing.T) {
+	Interface := func(Name string, Mac string) string {
+		return `<interface><interface-type>ethernet</interface-type><interface-name>` + Name +
+			`</interface-name><current-hardware-address>` + Mac + `</current-hardware-address></interface>`
+	}
+	Switch := &scriptedSwitch{pages: map[string][]string{
+		"get-interface-detail": {
+			Interface("0/1", "02:00:00:00:00:01") + `<has-more>true</has-more>`,
+			Interface("0/2", "02:00:00:00:00:02"),
+		},
+		"get-ip-interface": {
+			`<interface><interface-type>ethernet</interface-type><interface-name>0/1</interface-name>` +
+				`<if-state>up</if-state><ipv4>unassigned</ipv4></interface>` +
+				`<interface><interface-type>ethernet</interface-type><interface-name>0/2</interface-name>` +
+				`<if-state>up</if-state><ipv4>10.0.0.1/31</ipv4></interface>`,
+		},
+	}}
+	Interfaces, err := ad.GetAdapter(ad.FreedomType).GetInterfaces(connect(Switch), "")
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(Interfaces)) {
+		assert.Equal(t, "02:00:00:00:00:01", Interfaces[0].InterfaceMac)
+		assert.Equal(t, "02:00:00:00:00:02", Interfaces[1].InterfaceMac)
+		assert.Equal(t, "10.0.0.1/31", Interfaces[1].IPAddress)
+	}
+	assert.Equal(t, []string{"get-interface-detail", "get-interface-detail", "get-ip-interface"}, Switch.requests)
+}
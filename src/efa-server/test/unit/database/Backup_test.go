@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/infra/database"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+//setupBackups keeps the backups of the test in a directory of its own
+func setupBackups(t *testing.T, Retention int) func() {
+	BackupDir, err := ioutil.TempDir("", "efa-backups")
+	assert.Nil(t, err)
+	database.GetWorkingInstance().Config.BackupDir = BackupDir
+	database.GetWorkingInstance().Config.BackupRetention = Retention
+	return func() { os.RemoveAll(BackupDir) }
+}
+
+func TestBackup_Retention(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	defer setupBackups(t, 2)()
+	_, devUC, _ := setupInteractor(t)
+	ctx := context.Background()
+
+	Names := make([]string, 0)
+	for iter := 0; iter < 3; iter++ {
+		Backup, _, err := devUC.BackupDatabase(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, database.LatestSchemaVersion(), Backup.SchemaVersion)
+		assert.True(t, Backup.Size > 0)
+		Names = append(Names, Backup.Name)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	//The oldest backup is deleted, the most recent is listed first
+	Backups, err := devUC.GetDatabaseBackups(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(Backups))
+	assert.Equal(t, Names[2], Backups[0].Name)
+	assert.Equal(t, Names[1], Backups[1].Name)
+}
+
+func TestBackup_Restore(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	defer setupBackups(t, 5)()
+	DatabaseRepository, devUC, Fabric := setupInteractor(t)
+	ctx := context.Background()
+
+	Backup, _, err := devUC.BackupDatabase(ctx)
+	assert.Nil(t, err)
+	Device := domain.Device{IPAddress: "10.24.80.1", FabricID: Fabric.ID}
+	assert.Nil(t, DatabaseRepository.CreateDevice(&Device))
+
+	Restored, statusMsg, err := devUC.RestoreDatabase(ctx, Backup.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("Database restored from backup %s", Backup.Name), statusMsg)
+	assert.Equal(t, Backup.Name, Restored.Name)
+
+	//The repository works on the restored data
+	_, err = DatabaseRepository.GetDevice(MockFabricName, "10.24.80.1")
+	assert.NotNil(t, err)
+	_, err = DatabaseRepository.GetFabric(MockFabricName)
+	assert.Nil(t, err)
+
+	//The replaced data was backed up before the restore
+	Backups, _ := devUC.GetDatabaseBackups(ctx)
+	assert.Equal(t, 2, len(Backups))
+	assert.Nil(t, database.GetWorkingInstance().Instance.DB().Ping())
+}
+
+func TestBackup_RestoreInvalid(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	defer setupBackups(t, 5)()
+	_, devUC, _ := setupInteractor(t)
+	ctx := context.Background()
+
+	_, statusMsg, err := devUC.RestoreDatabase(ctx, "unknown")
+	assert.Equal(t, domain.ErrBackupNotFound, err)
+	assert.Equal(t, "Backup unknown does not exist", statusMsg)
+	_, err = database.GetWorkingInstance().RestoreBackup("../efa")
+	assert.Equal(t, database.ErrBackupNotFound, err)
+
+	//A backup of a newer release is refused
+	Backup, _, err := devUC.BackupDatabase(ctx)
+	assert.Nil(t, err)
+	Newer := database.LatestSchemaVersion() + 1
+	Metadata := fmt.Sprintf(`{"name":"%s","dialect":"%s","schema_version":%d}`, Backup.Name,
+		database.GetWorkingInstance().Config.Dialect, Newer)
+	ioutil.WriteFile(filepath.Join(database.GetWorkingInstance().Config.BackupDir, Backup.Name+".json"),
+		[]byte(Metadata), 0644)
+
+	_, statusMsg, err = devUC.RestoreDatabase(ctx, Backup.Name)
+	assert.Equal(t, domain.ErrFabricIncorrectValues, err)
+	assert.Equal(t, fmt.Sprintf("Backup %s has the schema version %d, newer than the version %d supported",
+		Backup.Name, Newer, database.LatestSchemaVersion()), statusMsg)
+	_, err = database.GetWorkingInstance().RestoreBackup(Backup.Name)
+	assert.EqualError(t, err, fmt.Sprintf("%s: schema version %d, supported version %d",
+		database.ErrBackupIncompatible, Newer, database.LatestSchemaVersion()))
+}
+
+//The backup of another backend is refused
+func TestBackup_RestoreOtherDialect(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	defer setupBackups(t, 5)()
+	_, devUC, _ := setupInteractor(t)
+
+	Backup, _, err := devUC.BackupDatabase(context.Background())
+	assert.Nil(t, err)
+	Other := database.DialectPostgres
+	if database.GetWorkingInstance().Config.Dialect == database.DialectPostgres {
+		Other = database.DialectSQLite
+	}
+	Metadata := fmt.Sprintf(`{"name":"%s","dialect":"%s","schema_version":%d}`, Backup.Name, Other,
+		database.LatestSchemaVersion())
+	ioutil.WriteFile(filepath.Join(database.GetWorkingInstance().Config.BackupDir, Backup.Name+".json"),
+		[]byte(Metadata), 0644)
+
+	_, err = database.GetWorkingInstance().RestoreBackup(Backup.Name)
+	assert.EqualError(t, err, fmt.Sprintf("%s: backup of a %s database", database.ErrBackupIncompatible, Other))
+}
+
+//pg_dump and psql report their failure, the database stays online
+func TestBackup_PostgresFailure(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	if database.GetWorkingInstance().Config.Dialect != database.DialectPostgres {
+		t.Skip("The database is not PostgreSQL")
+	}
+	defer setupBackups(t, 5)()
+	_, devUC, _ := setupInteractor(t)
+
+	Backup, _, err := devUC.BackupDatabase(context.Background())
+	assert.Nil(t, err)
+	//A dump which does not apply leaves the data untouched, psql runs it in a single transaction
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(database.GetWorkingInstance().Config.BackupDir,
+		Backup.Name+".bk"), []byte("DROP TABLE fabrics;\nSELECT unknown_function();\n"), 0644))
+	_, err = database.GetWorkingInstance().RestoreBackup(Backup.Name)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown_function")
+	assert.True(t, database.GetWorkingInstance().Instance.HasTable(&database.Fabric{}))
+
+	URL := database.GetWorkingInstance().Config.URL
+	defer func() { database.GetWorkingInstance().Config.URL = URL }()
+	database.GetWorkingInstance().Config.URL = URL + " dbname=efa_unknown"
+	_, err = database.GetWorkingInstance().CreateBackup()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "efa_unknown")
+	Backups, _ := database.GetWorkingInstance().ListBackups()
+	assert.Equal(t, 2, len(Backups))
+}
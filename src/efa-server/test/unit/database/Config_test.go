@@ -0,0 +1,110 @@
+package database
+
+import (
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var ConfigFileName = constants.TESTDBLocation + "-db.yaml"
+
+//clearEnvironment unsets the database environment and returns the function restoring it, the other tests
+//of the package may run against the backend selected by the environment
+func clearEnvironment() func() {
+	Saved := make(map[string]string)
+	for _, Env := range []string{database.EnvDialect, database.EnvURL, database.EnvMaxOpenConns,
+		database.EnvMaxIdleConns, database.EnvConnMaxLifetime, database.EnvBackupDir, database.EnvBackupInterval,
+		database.EnvBackupRetention} {
+		if Value, found := os.LookupEnv(Env); found {
+			Saved[Env] = Value
+		}
+		os.Unsetenv(Env)
+	}
+	return func() {
+		clearEnvironment()
+		for Env, Value := range Saved {
+			os.Setenv(Env, Value)
+		}
+	}
+}
+
+func TestConfig_DefaultsToSQLite(t *testing.T) {
+	defer clearEnvironment()()
+	Config, err := database.LoadConfig(ConfigFileName, constants.TESTDBLocation)
+	assert.Nil(t, err)
+	assert.Equal(t, database.DialectSQLite, Config.Dialect)
+	assert.Equal(t, constants.TESTDBLocation, Config.URL)
+	assert.Equal(t, 1, Config.MaxOpenConns)
+	assert.Equal(t, 1, Config.MaxIdleConns)
+	assert.Equal(t, filepath.Join(filepath.Dir(constants.TESTDBLocation), "backups"), Config.BackupDir)
+	assert.Equal(t, database.DefaultBackupInterval, Config.BackupPeriod)
+	assert.Equal(t, database.DefaultBackupRetention, Config.BackupRetention)
+}
+
+func TestConfig_Backups(t *testing.T) {
+	defer clearEnvironment()()
+	defer os.Remove(ConfigFileName)
+	ioutil.WriteFile(ConfigFileName, []byte("backup_dir: /var/backups/efa\nbackup_interval: 12h\n"), 0644)
+
+	os.Setenv(database.EnvBackupRetention, "3")
+	Config, err := database.LoadConfig(ConfigFileName, constants.TESTDBLocation)
+	assert.Nil(t, err)
+	assert.Equal(t, "/var/backups/efa", Config.BackupDir)
+	assert.Equal(t, 12*time.Hour, Config.BackupPeriod)
+	assert.Equal(t, 3, Config.BackupRetention)
+
+	//The scheduled backups are disabled
+	os.Setenv(database.EnvBackupInterval, "0")
+	Config, err = database.LoadConfig(ConfigFileName, constants.TESTDBLocation)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), Config.BackupPeriod)
+}
+
+func TestConfig_PostgresFromFile(t *testing.T) {
+	defer clearEnvironment()()
+	defer os.Remove(ConfigFileName)
+	ioutil.WriteFile(ConfigFileName, []byte("dialect: postgres\nurl: host=localhost dbname=efa sslmode=disable\n"+
+		"max_idle_conns: 20\n"), 0644)
+
+	Config, err := database.LoadConfig(ConfigFileName, constants.TESTDBLocation)
+	assert.Nil(t, err)
+	assert.Equal(t, database.DialectPostgres, Config.Dialect)
+	assert.Equal(t, "host=localhost dbname=efa sslmode=disable", Config.URL)
+	assert.Equal(t, database.DefaultPostgresMaxOpenConns, Config.MaxOpenConns)
+	//Idle connections are bounded by the open connections
+	assert.Equal(t, database.DefaultPostgresMaxOpenConns, Config.MaxIdleConns)
+
+	//The environment overrides the file
+	os.Setenv(database.EnvMaxOpenConns, "30")
+	Config, err = database.LoadConfig(ConfigFileName, constants.TESTDBLocation)
+	assert.Nil(t, err)
+	assert.Equal(t, 30, Config.MaxOpenConns)
+	assert.Equal(t, 20, Config.MaxIdleConns)
+}
+
+func TestConfig_Invalid(t *testing.T) {
+	defer clearEnvironment()()
+
+	os.Setenv(database.EnvDialect, database.DialectPostgres)
+	_, err := database.LoadConfig("", constants.TESTDBLocation)
+	assert.EqualError(t, err, "A connection string is required for the postgres database")
+
+	os.Setenv(database.EnvDialect, "mysql")
+	_, err = database.LoadConfig("", constants.TESTDBLocation)
+	assert.EqualError(t, err, "Database dialect should be sqlite3 or postgres")
+
+	os.Setenv(database.EnvDialect, database.DialectSQLite)
+	os.Setenv(database.EnvMaxOpenConns, "many")
+	_, err = database.LoadConfig("", constants.TESTDBLocation)
+	assert.EqualError(t, err, "EFA_DB_MAX_OPEN_CONNS should be a positive number")
+
+	os.Unsetenv(database.EnvMaxOpenConns)
+	os.Setenv(database.EnvBackupInterval, "daily")
+	_, err = database.LoadConfig("", constants.TESTDBLocation)
+	assert.EqualError(t, err, "Backup interval should be a duration such as 24h, daily is invalid")
+}
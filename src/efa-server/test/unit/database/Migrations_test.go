@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway"
+	"efa-server/infra/constants"
+	"efa-server/infra/database"
+	"efa-server/test/unit/mock"
+	"efa-server/usecase"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+var (
+	MockFabricName  = "efa-test"
+	MigrationDBName = constants.TESTDBLocation + "migration"
+)
+
+func setupInteractor(t *testing.T) (*gateway.DatabaseRepository, *usecase.DeviceInteractor, domain.Fabric) {
+	DatabaseRepository := &gateway.DatabaseRepository{Database: database.GetWorkingInstance()}
+	devUC := &usecase.DeviceInteractor{Db: DatabaseRepository, DeviceAdapterFactory: mock.DeviceAdapterFactory}
+	assert.Nil(t, devUC.AddFabric(context.Background(), MockFabricName))
+	Fabric, _ := DatabaseRepository.GetFabric(MockFabricName)
+	return DatabaseRepository, devUC, Fabric
+}
+
+//rollBackTo forgets the migrations applied past the version, as on a database of an older release
+func rollBackTo(Version uint) {
+	database.GetWorkingInstance().Instance.Where("version > ?", Version).Delete(&database.SchemaVersion{})
+}
+
+func TestMigrations_NewDatabase(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Version, err := database.GetWorkingInstance().SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, database.LatestSchemaVersion(), Version)
+
+	Status, err := database.GetWorkingInstance().MigrationStatus()
+	assert.Nil(t, err)
+	assert.Equal(t, int(database.LatestSchemaVersion()), len(Status))
+	for _, Migration := range Status {
+		assert.True(t, Migration.Applied)
+	}
+	//Nothing is pending, the database is not backed up again
+	assert.Nil(t, database.GetWorkingInstance().Migrate())
+}
+
+//The migrations, which use the models of their schema version, create the columns of the models of the application
+func TestMigrations_LatestModels(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Instance := database.GetWorkingInstance().Instance
+	for _, Model := range []interface{}{&database.Fabric{}, &database.FabricProperties{}, &database.Device{},
+		&database.AllocationRange{}, &database.ConfigGeneration{}, &database.FabricSettingChange{},
+		&database.NotificationSubscription{}, &database.CablingLink{}} {
+		Scope := Instance.NewScope(Model)
+		for _, Field := range Scope.GetModelStruct().StructFields {
+			if Field.IsNormal && !Field.IsIgnored {
+				assert.True(t, Instance.Dialect().HasColumn(Scope.TableName(), Field.DBName),
+					"%s.%s", Scope.TableName(), Field.DBName)
+			}
+		}
+	}
+}
+
+//A database which stored one row per value is moved to ranges on upgrade
+func TestMigrations_LegacyPools(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	_, devUC, Fabric := setupInteractor(t)
+	ctx := context.Background()
+
+	Instance := database.GetWorkingInstance().Instance
+	Instance.AutoMigrate(&database.ASNAllocationPool{}, &database.IPAllocationPool{}, &database.IPPairAllocationPool{})
+	for asn := uint64(70000); asn < 70010; asn++ {
+		if asn != 70005 {
+			Instance.Create(&database.ASNAllocationPool{FabricID: Fabric.ID, ASN: asn, DeviceRole: usecase.LeafRole})
+		}
+	}
+	Instance.Create(&database.IPAllocationPool{FabricID: Fabric.ID, IPAddress: "192.168.0.7", IPType: "Loopback"})
+	Instance.Create(&database.IPPairAllocationPool{FabricID: Fabric.ID, IPAddressOne: "192.168.1.5",
+		IPAddressTwo: "192.168.1.4", IPType: "P2P"})
+	rollBackTo(1)
+	defer os.Remove(MigrationDBName + ".v1.bk")
+
+	assert.Nil(t, database.GetWorkingInstance().Migrate())
+
+	count, _ := devUC.GetASNCountInPool(ctx, Fabric.ID, 70004, usecase.LeafRole)
+	assert.Equal(t, int64(1), count)
+	count, _ = devUC.GetASNCountInPool(ctx, Fabric.ID, 70005, usecase.LeafRole)
+	assert.Equal(t, int64(0), count)
+	count, _ = devUC.GetIPCountInPool(ctx, Fabric.ID, "192.168.0.7", "Loopback")
+	assert.Equal(t, int64(1), count)
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "192.168.1.4", "192.168.1.5", "P2P")
+	assert.Equal(t, int64(1), count)
+	assert.False(t, Instance.HasTable(&database.ASNAllocationPool{}))
+	assert.False(t, Instance.HasTable(&database.IPAllocationPool{}))
+	assert.False(t, Instance.HasTable(&database.IPPairAllocationPool{}))
+}
+
+//A fabric created before the non-CLOS fabrics gets the Rack properties and pools on upgrade
+func TestMigrations_FabricUpgrade(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	DatabaseRepository, devUC, Fabric := setupInteractor(t)
+	ctx := context.Background()
+
+	Instance := database.GetWorkingInstance().Instance
+	Instance.Model(&database.FabricProperties{}).Where("fabric_id = ?", Fabric.ID).
+		Updates(map[string]interface{}{"rack_asn_block": "", "routing_mct_port_channel": "", "bfd_enable": ""})
+	Instance.Where("fabric_id = ? AND pool_name IN (?)", Fabric.ID, []string{usecase.RackRole, domain.RackL3LoopBackPoolName}).
+		Delete(&database.AllocationRange{})
+	rollBackTo(2)
+	defer os.Remove(MigrationDBName + ".v2.bk")
+
+	assert.Nil(t, database.GetWorkingInstance().Migrate())
+	_, err := os.Stat(MigrationDBName + ".v2.bk")
+	assert.Nil(t, err)
+
+	FabricProperties, _ := DatabaseRepository.GetFabricProperties(Fabric.ID)
+	assert.Equal(t, "4200000000-4200065534", FabricProperties.RackASNBlock)
+	assert.Equal(t, "64", FabricProperties.RoutingMctPortChannel)
+	assert.Equal(t, "Yes", FabricProperties.BFDEnable)
+	assert.Equal(t, domain.CLOSFabricType, FabricProperties.FabricType)
+
+	count, _ := devUC.GetASNCountInPool(ctx, Fabric.ID, 4200000000, usecase.RackRole)
+	assert.Equal(t, int64(1), count)
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.30.30.2", "10.30.30.3", domain.RackL3LoopBackPoolName)
+	assert.Equal(t, int64(1), count)
+	count, _ = devUC.GetIPPairCountInPool(ctx, Fabric.ID, "10.30.30.0", "10.30.30.1", domain.RackL3LoopBackPoolName)
+	assert.Equal(t, int64(0), count)
+	Ranges, _ := DatabaseRepository.GetAllocationRanges(Fabric.ID, domain.PoolTypeIPPair, domain.RackL3LoopBackPoolName)
+	assert.Equal(t, 1, len(Ranges))
+}
+
+func TestMigrations_NewerSchema(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	Newer := database.LatestSchemaVersion() + 1
+	database.GetWorkingInstance().Instance.Create(&database.SchemaVersion{Version: Newer, Description: "Unknown"})
+
+	err := database.GetWorkingInstance().Migrate()
+	assert.EqualError(t, err, fmt.Sprintf("%s: version %d, supported version %d", database.ErrNewerSchema,
+		Newer, database.LatestSchemaVersion()))
+
+	Status, err := database.GetWorkingInstance().MigrationStatus()
+	assert.Nil(t, err)
+	assert.Equal(t, int(Newer), len(Status))
+	assert.Equal(t, "Unknown", Status[Newer-1].Description)
+	assert.True(t, Status[Newer-1].Applied)
+}
+
+func TestMigrations_Down(t *testing.T) {
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+
+	assert.Nil(t, database.GetWorkingInstance().MigrateDown(2))
+	Version, _ := database.GetWorkingInstance().SchemaVersion()
+	assert.Equal(t, uint(2), Version)
+
+	//The pools moved to ranges can not be moved back, nothing is reverted
+	assert.EqualError(t, database.GetWorkingInstance().MigrateDown(0),
+		"Database migration 2 (Move the allocation pools stored as one row per value to ranges) can not be reverted")
+	Version, _ = database.GetWorkingInstance().SchemaVersion()
+	assert.Equal(t, uint(2), Version)
+	assert.True(t, database.GetWorkingInstance().Instance.HasTable(&database.Fabric{}))
+
+	defer os.Remove(MigrationDBName + ".v2.bk")
+	assert.Nil(t, database.GetWorkingInstance().Migrate())
+	Version, _ = database.GetWorkingInstance().SchemaVersion()
+	assert.Equal(t, database.LatestSchemaVersion(), Version)
+}
+
+func cleanupDB(Database *database.Database) {
+	Database.Drop()
+}
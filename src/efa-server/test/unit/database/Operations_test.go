@@ -0,0 +1,40 @@
+package database
+
+import (
+	"efa-server/infra/database"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+//Drop deletes all the data of the backend, the database is then set up again from the first migration
+func TestOperations_Drop(t *testing.T) {
+	database.Setup(MigrationDBName)
+	setupInteractor(t)
+	Config := database.GetWorkingInstance().Config
+	assert.Nil(t, database.GetWorkingInstance().Drop())
+
+	if Config.Dialect == database.DialectSQLite {
+		_, err := os.Stat(Config.URL)
+		assert.True(t, os.IsNotExist(err))
+	} else {
+		Instance, err := gorm.Open(Config.Dialect, Config.URL)
+		if assert.Nil(t, err) {
+			for _, Model := range []interface{}{&database.SchemaVersion{}, &database.Fabric{}, &database.Device{},
+				&database.RackEvpnNeighbors{}} {
+				assert.False(t, Instance.HasTable(Model))
+			}
+			Instance.Close()
+		}
+	}
+
+	database.Setup(MigrationDBName)
+	defer cleanupDB(database.GetWorkingInstance())
+	Version, err := database.GetWorkingInstance().SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, database.LatestSchemaVersion(), Version)
+	var Fabrics []database.Fabric
+	assert.Nil(t, database.GetWorkingInstance().Instance.Find(&Fabrics).Error)
+	assert.Equal(t, 0, len(Fabrics))
+}
@@ -8,10 +8,14 @@ var userName = "admin"
 
 //DatabaseRepository represents a mock DatabaseRepository
 type DatabaseRepository struct {
-	MockOpenTransaction     func() error
-	MockCommitTransaction   func() error
-	MockRollBackTransaction func() error
-	MockBackup              func() error
+	MockOpenTransaction          func() error
+	MockCommitTransaction        func() error
+	MockRollBackTransaction      func() error
+	MockBackup                   func() error
+	MockGetSchemaMigrationStatus func() (domain.SchemaMigrationStatus, error)
+	MockCreateBackup             func() (domain.DatabaseBackup, error)
+	MockGetBackups               func() ([]domain.DatabaseBackup, error)
+	MockRestoreBackup            func(Name string) (domain.DatabaseBackup, error)
 
 	MockGetFabric                     func(FabricName string) (domain.Fabric, error)
 	MockCreateFabric                  func(Fabric *domain.Fabric) error
@@ -30,6 +34,9 @@ type DatabaseRepository struct {
 	MockDeleteDevice func(DeviceID []uint) error
 	MockSaveDevice   func(Device *domain.Device) error
 
+	MockGetDeviceSettings  func(FabricID uint, DeviceID uint) (domain.DeviceSettings, error)
+	MockSaveDeviceSettings func(DeviceSettings *domain.DeviceSettings) error
+
 	MockGetRack                           func(FabricName string, IP1 string, IP2 string) (domain.Rack, error)
 	MockGetRackbyIP                       func(FabricName string, IP string) (domain.Rack, error)
 	MockGetRackAll                        func(FabricName string) ([]domain.Rack, error)
@@ -60,44 +67,46 @@ type DatabaseRepository struct {
 	MockGetLLDPNeighborsBetweenTwoDevices            func(FabricID uint, DeviceOneID uint, DeviceTwoID uint) ([]domain.LLDPNeighbor, error)
 	MockGetLLDPNeighborsOnRemoteDeviceID             func(FabricID uint, DeviceID uint, RemoteDeviceIDs []uint) ([]domain.LLDPNeighbor, error)
 
-	MockCreateASN                  func(ASN *domain.ASNAllocationPool) error
-	MockDeleteASNPool              func() error
-	MockDeleteUsedASNPool          func() error
-	MockDeleteASN                  func(ASN *domain.ASNAllocationPool) error
-	MockGetNextASNForRole          func(FabricID uint, role string) (domain.ASNAllocationPool, error)
-	MockGetASNCountOnRole          func(FabricID uint, role string) (int64, error)
-	MockGetASNCountOnASN           func(FabricID uint, asn uint64) (int64, error)
-	MockGetASNAndCountOnASNAndRole func(FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool)
+	MockCreateAllocationRange          func(Range *domain.AllocationRange) error
+	MockUpdateAllocationRange          func(Range *domain.AllocationRange) error
+	MockDeleteAllocationRange          func(Range *domain.AllocationRange) error
+	MockGetAllocationRanges            func(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error)
+	MockGetAllocationRangesOnWindow    func(FabricID uint, PoolType string, PoolName string, StartValue uint64, EndValue uint64) ([]domain.AllocationRange, error)
+	MockGetAllocationRangeCountOnValue func(FabricID uint, PoolType string, Value uint64) (int64, error)
+
+	MockSaveAllocationPin            func(Pin *domain.AllocationPin) error
+	MockDeleteAllocationPin          func(Pin *domain.AllocationPin) error
+	MockGetAllocationPins            func(FabricID uint) ([]domain.AllocationPin, error)
+	MockGetAllocationPinsOnDevice    func(FabricID uint, DeviceIP string) ([]domain.AllocationPin, error)
+	MockGetAllocationPinCountOnValue func(FabricID uint, PoolType string, PoolName string, Value uint64) (int64, error)
+
+	MockCreateCablingLink func(Link *domain.CablingLink) error
+	MockDeleteCablingPlan func(FabricID uint) error
+	MockGetCablingPlan    func(FabricID uint) ([]domain.CablingLink, error)
+
+	MockDeleteUsedASNPool func() error
 
 	MockCreateUsedASN                   func(UsedASN *domain.UsedASN) error
 	MockDeleteUsedASN                   func(UsedASN *domain.UsedASN) error
 	MockGetUsedASNOnASNAndDeviceAndRole func(FabricID uint, DeviceID uint, asn uint64, role string) (domain.UsedASN, error)
 	MockGetUsedASNCountOnASNAndDevice   func(FabricID uint, asn uint64, DeviceID uint) (int64, error)
 	MockGetUsedASNCountOnASNAndRole     func(FabricID uint, asn uint64, role string) (int64, error)
+	MockGetUsedASNsOnRole               func(FabricID uint, role string) ([]domain.UsedASN, error)
 
-	MockCreateIPEntry                                 func(IPEntry *domain.IPAllocationPool) error
-	MockDeleteIPEntry                                 func(IPEntry *domain.IPAllocationPool) error
-	MockDeleteIPPool                                  func() error
-	MockGetIPEntryAndCountOnIPAddressAndType          func(FabricID uint, ipaddress string, IPType string) (int64, domain.IPAllocationPool, error)
-	MockGetNextIPEntryOnType                          func(FabricID uint, IPType string) (domain.IPAllocationPool, error)
 	MockGetUsedIPOnDeviceInterfaceIDIPAddresssAndType func(FabricID uint, DeviceID uint, ipaddress string, IPType string, InterfaceID uint) (domain.UsedIP, error)
 	MockGetUsedIPOnDeviceInterfaceIDAndType           func(FabricID uint, DeviceID uint, IPType string, InterfaceId uint) (domain.UsedIP, error)
 	MockCreateUsedIPEntry                             func(UsedIPEntry *domain.UsedIP) error
 	MockDeleteUsedIPEntry                             func(UsedIPEntry *domain.UsedIP) error
 	MockDeleteUsedIPPool                              func() error
+	MockGetUsedIPsOnType                              func(FabricID uint, IPType string) ([]domain.UsedIP, error)
 
-	MockCreateIPPairEntry                                 func(IPEntry *domain.IPPairAllocationPool) error
-	MockDeleteIPPairEntry                                 func(IPEntry *domain.IPPairAllocationPool) error
-	MockDeleteIPPairPool                                  func() error
-	MockGetIPPairEntryAndCountOnEitherIPAddressAndType    func(FabricID uint, ipaddress string, IPType string) (int64, domain.IPPairAllocationPool, error)
-	MockGetIPPairEntryAndCountOnIPAddressAndType          func(FabricID uint, ipaddressOne string, ipaddressTwo string, IPType string) (int64, domain.IPPairAllocationPool, error)
-	MockGetNextIPPairEntryOnType                          func(FabricID uint, IPType string) (domain.IPPairAllocationPool, error)
 	MockGetUsedIPPairOnDeviceInterfaceIDIPAddresssAndType func(FabricID uint, DeviceOneID uint, DeviceTwoID uint, ipaddressOne string, ipaddressTwo string, IPType string,
 		InterfaceOneId uint, InterfaceTwoId uint) (domain.UsedIPPair, error)
 	MockGetUsedIPPairOnDeviceInterfaceIDAndType func(FabricID uint, DeviceOneID uint, DeviceTwoID uint, IPType string, InterfaceOneId uint, InterfaceTwoId uint) (domain.UsedIPPair, error)
 	MockCreateUsedIPPairEntry                   func(UsedIPEntry *domain.UsedIPPair) error
 	MockDeleteUsedIPPairEntry                   func(UsedIPEntry *domain.UsedIPPair) error
 	MockDeleteUsedIPPairPool                    func() error
+	MockGetUsedIPPairsOnType                    func(FabricID uint, IPType string) ([]domain.UsedIPPair, error)
 
 	MockGetLLDPNeighbor func(FabricID uint, DeviceOneID uint,
 		DeviceTwoID uint, InterfaceOneID uint, InterfaceTwoID uint) (domain.LLDPNeighbor, error)
@@ -136,6 +145,15 @@ type DatabaseRepository struct {
 	MockGetBGPSwitchConfigs                                   func(FabricID uint, InterfaceIDs []uint) ([]domain.RemoteNeighborSwitchConfig, error)
 	MockGetBGPSwitchConfigsExcludingMarkedForDeletion         func(FabricID uint, InterfaceIDs []uint) ([]domain.RemoteNeighborSwitchConfig, error)
 	MockUpdateConfigTypeForBGPSwitchConfigsOnIntefaceID       func(FabricID uint, InterfaceIDs []uint, configType string) error
+	MockCreateConfigGeneration                                func(Generation *domain.ConfigGeneration) error
+	MockGetConfigGenerations                                  func(FabricID uint) ([]domain.ConfigGeneration, error)
+	MockGetConfigGeneration                                   func(FabricID uint, Generation uint) (domain.ConfigGeneration, error)
+	MockCreateFabricSettingChange                             func(Change *domain.FabricSettingChange) error
+	MockGetFabricSettingChanges                               func(FabricID uint) ([]domain.FabricSettingChange, error)
+	MockCreateNotificationSubscription                        func(Subscription *domain.NotificationSubscription) error
+	MockGetNotificationSubscriptions                          func() ([]domain.NotificationSubscription, error)
+	MockGetNotificationSubscription                           func(ID uint) (domain.NotificationSubscription, error)
+	MockDeleteNotificationSubscription                        func(ID uint) error
 	MockCreateExecutionLog                                    func(ExecutionLog *domain.ExecutionLog) error
 	MockGetExecutionLogList                                   func(limit int, status string) ([]domain.ExecutionLog, error)
 	MockGetExecutionLogByUUID                                 func(string) (domain.ExecutionLog, error)
@@ -165,6 +183,8 @@ type DatabaseRepository struct {
 	MockDeleteMctClustersUsingClusterObject               func(OldMct domain.MctClusterConfig) error
 	MockUpdateLLDPConfigType                              func(FabricID uint, QueryconfigTypes []string, configType string) error
 	MockDeleteLLDPMarkedForDelete                         func(FabricID uint) error
+	MockUpdateLLDPConfigTypeOnLinks                       func(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint, QueryconfigTypes []string, configType string) error
+	MockDeleteLLDPMarkedForDeleteOnLinks                  func(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint) error
 	MockDeleteMctClustersWithMgmtIP                       func(IPAddress string) error
 	MockMarkMctClusterMemberPortsForCreate                func(FabricID uint, DeviceID uint, RemoteDeviceID uint) error
 	MockGetMctClustersWithBothDevices                     func(FabricID uint, DeviceID uint, NeighborDeviceID uint, ConfigType []string) ([]domain.MctClusterConfig, error)
@@ -412,6 +432,38 @@ func (db *DatabaseRepository) Backup() error {
 	return nil
 }
 
+//GetSchemaMigrationStatus represents a mock GetSchemaMigrationStatus
+func (db *DatabaseRepository) GetSchemaMigrationStatus() (domain.SchemaMigrationStatus, error) {
+	if db.MockGetSchemaMigrationStatus != nil {
+		return db.MockGetSchemaMigrationStatus()
+	}
+	return domain.SchemaMigrationStatus{}, nil
+}
+
+//CreateBackup represents a mock CreateBackup
+func (db *DatabaseRepository) CreateBackup() (domain.DatabaseBackup, error) {
+	if db.MockCreateBackup != nil {
+		return db.MockCreateBackup()
+	}
+	return domain.DatabaseBackup{}, nil
+}
+
+//GetBackups represents a mock GetBackups
+func (db *DatabaseRepository) GetBackups() ([]domain.DatabaseBackup, error) {
+	if db.MockGetBackups != nil {
+		return db.MockGetBackups()
+	}
+	return []domain.DatabaseBackup{}, nil
+}
+
+//RestoreBackup represents a mock RestoreBackup
+func (db *DatabaseRepository) RestoreBackup(Name string) (domain.DatabaseBackup, error) {
+	if db.MockRestoreBackup != nil {
+		return db.MockRestoreBackup(Name)
+	}
+	return domain.DatabaseBackup{}, nil
+}
+
 //CreateDevice represents a mock CreateDevice
 func (db *DatabaseRepository) CreateDevice(Device *domain.Device) error {
 	if db.MockCreateDevice != nil {
@@ -436,6 +488,22 @@ func (db *DatabaseRepository) SaveDevice(Device *domain.Device) error {
 	return nil
 }
 
+//GetDeviceSettings represents a mock GetDeviceSettings
+func (db *DatabaseRepository) GetDeviceSettings(FabricID uint, DeviceID uint) (domain.DeviceSettings, error) {
+	if db.MockGetDeviceSettings != nil {
+		return db.MockGetDeviceSettings(FabricID, DeviceID)
+	}
+	return domain.DeviceSettings{}, nil
+}
+
+//SaveDeviceSettings represents a mock SaveDeviceSettings
+func (db *DatabaseRepository) SaveDeviceSettings(DeviceSettings *domain.DeviceSettings) error {
+	if db.MockSaveDeviceSettings != nil {
+		return db.MockSaveDeviceSettings(DeviceSettings)
+	}
+	return nil
+}
+
 //GetRack returns an instance of Rack for a given "FabricName, Rack Mgmt IPAddress" i/p
 func (db *DatabaseRepository) GetRack(FabricName string, IP1 string, IP2 string) (domain.Rack, error) {
 
@@ -658,84 +726,141 @@ func (db *DatabaseRepository) GetLLDPOnRemoteMacExcludingMarkedForDeletion(Remot
 	return domain.LLDP{}, nil
 }
 
-//CreateASN represents a mock CreateASN
-func (db *DatabaseRepository) CreateASN(ASN *domain.ASNAllocationPool) error {
-	if db.MockCreateASN != nil {
-		return db.MockCreateASN(ASN)
+//CreateAllocationRange represents a mock CreateAllocationRange
+func (db *DatabaseRepository) CreateAllocationRange(Range *domain.AllocationRange) error {
+	if db.MockCreateAllocationRange != nil {
+		return db.MockCreateAllocationRange(Range)
 	}
 	return nil
 }
 
-//DeleteASNPool represents a mock DeleteASNPool
-func (db *DatabaseRepository) DeleteASNPool() error {
-	if db.MockDeleteASNPool != nil {
-		return db.MockDeleteASNPool()
+//UpdateAllocationRange represents a mock UpdateAllocationRange
+func (db *DatabaseRepository) UpdateAllocationRange(Range *domain.AllocationRange) error {
+	if db.MockUpdateAllocationRange != nil {
+		return db.MockUpdateAllocationRange(Range)
 	}
 	return nil
 }
 
-//DeleteUsedASNPool represents a mock DeleteUsedASNPool
-func (db *DatabaseRepository) DeleteUsedASNPool() error {
-	if db.MockDeleteUsedASNPool != nil {
-		return db.MockDeleteUsedASNPool()
+//DeleteAllocationRange represents a mock DeleteAllocationRange
+func (db *DatabaseRepository) DeleteAllocationRange(Range *domain.AllocationRange) error {
+	if db.MockDeleteAllocationRange != nil {
+		return db.MockDeleteAllocationRange(Range)
 	}
 	return nil
 }
 
-//DeleteASN represents a mock DeleteASN
-func (db *DatabaseRepository) DeleteASN(ASN *domain.ASNAllocationPool) error {
-	if db.MockDeleteASN != nil {
-		return db.MockDeleteASN(ASN)
+//GetAllocationRanges represents a mock GetAllocationRanges
+func (db *DatabaseRepository) GetAllocationRanges(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error) {
+	if db.MockGetAllocationRanges != nil {
+		return db.MockGetAllocationRanges(FabricID, PoolType, PoolName)
 	}
-	return nil
+	return []domain.AllocationRange{}, nil
 }
 
-//CreateUsedASN represents a mock CreateUsedASN
-func (db *DatabaseRepository) CreateUsedASN(UsedASN *domain.UsedASN) error {
-	if db.MockCreateUsedASN != nil {
-		return db.MockCreateUsedASN(UsedASN)
+//GetAllocationRangesOnWindow represents a mock GetAllocationRangesOnWindow
+func (db *DatabaseRepository) GetAllocationRangesOnWindow(FabricID uint, PoolType string, PoolName string,
+	StartValue uint64, EndValue uint64) ([]domain.AllocationRange, error) {
+	if db.MockGetAllocationRangesOnWindow != nil {
+		return db.MockGetAllocationRangesOnWindow(FabricID, PoolType, PoolName, StartValue, EndValue)
+	}
+	return []domain.AllocationRange{}, nil
+}
+
+//GetAllocationRangeCountOnValue represents a mock GetAllocationRangeCountOnValue
+func (db *DatabaseRepository) GetAllocationRangeCountOnValue(FabricID uint, PoolType string, Value uint64) (int64, error) {
+	if db.MockGetAllocationRangeCountOnValue != nil {
+		return db.MockGetAllocationRangeCountOnValue(FabricID, PoolType, Value)
+	}
+	return 0, nil
+}
+
+//SaveAllocationPin represents a mock SaveAllocationPin
+func (db *DatabaseRepository) SaveAllocationPin(Pin *domain.AllocationPin) error {
+	if db.MockSaveAllocationPin != nil {
+		return db.MockSaveAllocationPin(Pin)
 	}
 	return nil
 }
 
-//DeleteUsedASN represents a mock DeleteUsedASN
-func (db *DatabaseRepository) DeleteUsedASN(UsedASN *domain.UsedASN) error {
-	if db.MockDeleteUsedASN != nil {
-		return db.MockDeleteUsedASN(UsedASN)
+//DeleteAllocationPin represents a mock DeleteAllocationPin
+func (db *DatabaseRepository) DeleteAllocationPin(Pin *domain.AllocationPin) error {
+	if db.MockDeleteAllocationPin != nil {
+		return db.MockDeleteAllocationPin(Pin)
 	}
 	return nil
 }
 
-//GetASNCountOnASN represents a mock GetASNCountOnASN
-func (db *DatabaseRepository) GetASNCountOnASN(FabricID uint, asn uint64) (int64, error) {
-	if db.MockGetASNCountOnASN != nil {
-		return db.MockGetASNCountOnASN(FabricID, asn)
+//GetAllocationPins represents a mock GetAllocationPins
+func (db *DatabaseRepository) GetAllocationPins(FabricID uint) ([]domain.AllocationPin, error) {
+	if db.MockGetAllocationPins != nil {
+		return db.MockGetAllocationPins(FabricID)
 	}
-	return 0, nil
+	return []domain.AllocationPin{}, nil
 }
 
-//GetASNCountOnRole represents a mock GetASNCountOnRole
-func (db *DatabaseRepository) GetASNCountOnRole(FabricID uint, role string) (int64, error) {
-	if db.MockGetASNCountOnRole != nil {
-		return db.MockGetASNCountOnRole(FabricID, role)
+//GetAllocationPinsOnDevice represents a mock GetAllocationPinsOnDevice
+func (db *DatabaseRepository) GetAllocationPinsOnDevice(FabricID uint, DeviceIP string) ([]domain.AllocationPin, error) {
+	if db.MockGetAllocationPinsOnDevice != nil {
+		return db.MockGetAllocationPinsOnDevice(FabricID, DeviceIP)
+	}
+	return []domain.AllocationPin{}, nil
+}
+
+//GetAllocationPinCountOnValue represents a mock GetAllocationPinCountOnValue
+func (db *DatabaseRepository) GetAllocationPinCountOnValue(FabricID uint, PoolType string, PoolName string, Value uint64) (int64, error) {
+	if db.MockGetAllocationPinCountOnValue != nil {
+		return db.MockGetAllocationPinCountOnValue(FabricID, PoolType, PoolName, Value)
 	}
 	return 0, nil
 }
 
-//GetNextASNForRole represents a mock GetNextASNForRole
-func (db *DatabaseRepository) GetNextASNForRole(FabricID uint, role string) (domain.ASNAllocationPool, error) {
-	if db.MockGetNextASNForRole != nil {
-		return db.MockGetNextASNForRole(FabricID, role)
+//CreateCablingLink represents a mock CreateCablingLink
+func (db *DatabaseRepository) CreateCablingLink(Link *domain.CablingLink) error {
+	if db.MockCreateCablingLink != nil {
+		return db.MockCreateCablingLink(Link)
+	}
+	return nil
+}
+
+//DeleteCablingPlan represents a mock DeleteCablingPlan
+func (db *DatabaseRepository) DeleteCablingPlan(FabricID uint) error {
+	if db.MockDeleteCablingPlan != nil {
+		return db.MockDeleteCablingPlan(FabricID)
+	}
+	return nil
+}
+
+//GetCablingPlan represents a mock GetCablingPlan
+func (db *DatabaseRepository) GetCablingPlan(FabricID uint) ([]domain.CablingLink, error) {
+	if db.MockGetCablingPlan != nil {
+		return db.MockGetCablingPlan(FabricID)
+	}
+	return []domain.CablingLink{}, nil
+}
+
+//DeleteUsedASNPool represents a mock DeleteUsedASNPool
+func (db *DatabaseRepository) DeleteUsedASNPool() error {
+	if db.MockDeleteUsedASNPool != nil {
+		return db.MockDeleteUsedASNPool()
 	}
-	return domain.ASNAllocationPool{}, nil
+	return nil
 }
 
-//GetASNAndCountOnASNAndRole represents a mock GetASNAndCountOnASNAndRole
-func (db *DatabaseRepository) GetASNAndCountOnASNAndRole(FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool) {
-	if db.MockGetASNAndCountOnASNAndRole != nil {
-		return db.MockGetASNAndCountOnASNAndRole(FabricID, asn, role)
+//CreateUsedASN represents a mock CreateUsedASN
+func (db *DatabaseRepository) CreateUsedASN(UsedASN *domain.UsedASN) error {
+	if db.MockCreateUsedASN != nil {
+		return db.MockCreateUsedASN(UsedASN)
 	}
-	return 0, domain.ASNAllocationPool{}
+	return nil
+}
+
+//DeleteUsedASN represents a mock DeleteUsedASN
+func (db *DatabaseRepository) DeleteUsedASN(UsedASN *domain.UsedASN) error {
+	if db.MockDeleteUsedASN != nil {
+		return db.MockDeleteUsedASN(UsedASN)
+	}
+	return nil
 }
 
 //GetUsedASNOnASNAndDeviceAndRole represents a mock GetUsedASNOnASNAndDeviceAndRole
@@ -762,44 +887,12 @@ func (db *DatabaseRepository) GetUsedASNCountOnASNAndRole(FabricID uint, asn uin
 	return 0, nil
 }
 
-//CreateIPEntry represents a mock CreateIPEntry
-func (db *DatabaseRepository) CreateIPEntry(IPEntry *domain.IPAllocationPool) error {
-	if db.MockCreateIPEntry != nil {
-		return db.MockCreateIPEntry(IPEntry)
+//GetUsedASNsOnRole represents a mock GetUsedASNsOnRole
+func (db *DatabaseRepository) GetUsedASNsOnRole(FabricID uint, role string) ([]domain.UsedASN, error) {
+	if db.MockGetUsedASNsOnRole != nil {
+		return db.MockGetUsedASNsOnRole(FabricID, role)
 	}
-	return nil
-}
-
-//DeleteIPEntry represents a mock DeleteIPEntry
-func (db *DatabaseRepository) DeleteIPEntry(IPEntry *domain.IPAllocationPool) error {
-	if db.MockDeleteIPEntry != nil {
-		return db.MockDeleteIPEntry(IPEntry)
-	}
-	return nil
-}
-
-//DeleteIPPool represents a mock DeleteIPPool
-func (db *DatabaseRepository) DeleteIPPool() error {
-	if db.MockDeleteIPPool != nil {
-		return db.MockDeleteIPPool()
-	}
-	return nil
-}
-
-//GetIPEntryAndCountOnIPAddressAndType represents a mock GetIPEntryAndCountOnIPAddressAndType
-func (db *DatabaseRepository) GetIPEntryAndCountOnIPAddressAndType(FabricID uint, ipaddress string, IPType string) (int64, domain.IPAllocationPool, error) {
-	if db.MockGetIPEntryAndCountOnIPAddressAndType != nil {
-		return db.MockGetIPEntryAndCountOnIPAddressAndType(FabricID, ipaddress, IPType)
-	}
-	return 0, domain.IPAllocationPool{}, nil
-}
-
-//GetNextIPEntryOnType represents a mock GetNextIPEntryOnType
-func (db *DatabaseRepository) GetNextIPEntryOnType(FabricID uint, IPType string) (domain.IPAllocationPool, error) {
-	if db.MockGetNextIPEntryOnType != nil {
-		return db.MockGetNextIPEntryOnType(FabricID, IPType)
-	}
-	return domain.IPAllocationPool{}, nil
+	return []domain.UsedASN{}, nil
 }
 
 //GetUsedIPOnDeviceInterfaceIDIPAddresssAndType represents a mock GetUsedIPOnDeviceInterfaceIDIPAddresssAndType
@@ -842,52 +935,12 @@ func (db *DatabaseRepository) DeleteUsedIPPool() error {
 	return nil
 }
 
-//CreateIPPairEntry represents a mock CreateIPPairEntry
-func (db *DatabaseRepository) CreateIPPairEntry(IPEntry *domain.IPPairAllocationPool) error {
-	if db.MockCreateIPPairEntry != nil {
-		return db.MockCreateIPPairEntry(IPEntry)
-	}
-	return nil
-}
-
-//DeleteIPPairEntry represents a mock DeleteIPPairEntry
-func (db *DatabaseRepository) DeleteIPPairEntry(IPEntry *domain.IPPairAllocationPool) error {
-	if db.MockDeleteIPPairEntry != nil {
-		return db.MockDeleteIPPairEntry(IPEntry)
+//GetUsedIPsOnType represents a mock GetUsedIPsOnType
+func (db *DatabaseRepository) GetUsedIPsOnType(FabricID uint, IPType string) ([]domain.UsedIP, error) {
+	if db.MockGetUsedIPsOnType != nil {
+		return db.MockGetUsedIPsOnType(FabricID, IPType)
 	}
-	return nil
-}
-
-//DeleteIPPairPool represents a mock DeleteIPPairPool
-func (db *DatabaseRepository) DeleteIPPairPool() error {
-	if db.MockDeleteIPPairPool != nil {
-		return db.MockDeleteIPPairPool()
-	}
-	return nil
-}
-
-//GetIPPairEntryAndCountOnIPAddressAndType represents a mock GetIPPairEntryAndCountOnIPAddressAndType
-func (db *DatabaseRepository) GetIPPairEntryAndCountOnIPAddressAndType(FabricID uint, ipaddressOne string, ipaddressTwo string, IPType string) (int64, domain.IPPairAllocationPool, error) {
-	if db.MockGetIPPairEntryAndCountOnIPAddressAndType != nil {
-		return db.MockGetIPPairEntryAndCountOnIPAddressAndType(FabricID, ipaddressOne, ipaddressTwo, IPType)
-	}
-	return 0, domain.IPPairAllocationPool{}, nil
-}
-
-//GetIPPairEntryAndCountOnEitherIPAddressAndType represents a mock GetIPPairEntryAndCountOnEitherIPAddressAndType
-func (db *DatabaseRepository) GetIPPairEntryAndCountOnEitherIPAddressAndType(FabricID uint, ipaddress string, IPType string) (int64, domain.IPPairAllocationPool, error) {
-	if db.MockGetIPPairEntryAndCountOnEitherIPAddressAndType != nil {
-		return db.MockGetIPPairEntryAndCountOnEitherIPAddressAndType(FabricID, ipaddress, IPType)
-	}
-	return 0, domain.IPPairAllocationPool{}, nil
-}
-
-//GetNextIPPairEntryOnType represents a mock GetNextIPPairEntryOnType
-func (db *DatabaseRepository) GetNextIPPairEntryOnType(FabricID uint, IPType string) (domain.IPPairAllocationPool, error) {
-	if db.MockGetNextIPPairEntryOnType != nil {
-		return db.MockGetNextIPPairEntryOnType(FabricID, IPType)
-	}
-	return domain.IPPairAllocationPool{}, nil
+	return []domain.UsedIP{}, nil
 }
 
 //GetUsedIPPairOnDeviceInterfaceIDIPAddresssAndType represents a mock GetUsedIPPairOnDeviceInterfaceIDIPAddresssAndType
@@ -931,6 +984,14 @@ func (db *DatabaseRepository) DeleteUsedIPPairPool() error {
 	return nil
 }
 
+//GetUsedIPPairsOnType represents a mock GetUsedIPPairsOnType
+func (db *DatabaseRepository) GetUsedIPPairsOnType(FabricID uint, IPType string) ([]domain.UsedIPPair, error) {
+	if db.MockGetUsedIPPairsOnType != nil {
+		return db.MockGetUsedIPPairsOnType(FabricID, IPType)
+	}
+	return []domain.UsedIPPair{}, nil
+}
+
 //GetLLDPNeighbor represents a mock GetLLDPNeighbor
 func (db *DatabaseRepository) GetLLDPNeighbor(FabricID uint, DeviceOneID uint,
 	DeviceTwoID uint, InterfaceOneID uint, InterfaceTwoID uint) (domain.LLDPNeighbor, error) {
@@ -1198,6 +1259,78 @@ func (db *DatabaseRepository) UpdateBGPSwitchConfigsConfigType(FabricID uint, Qu
 	return nil
 }
 
+//CreateConfigGeneration represents a mock CreateConfigGeneration
+func (db *DatabaseRepository) CreateConfigGeneration(Generation *domain.ConfigGeneration) error {
+	if db.MockCreateConfigGeneration != nil {
+		return db.MockCreateConfigGeneration(Generation)
+	}
+	return nil
+}
+
+//GetConfigGenerations represents a mock GetConfigGenerations
+func (db *DatabaseRepository) GetConfigGenerations(FabricID uint) ([]domain.ConfigGeneration, error) {
+	if db.MockGetConfigGenerations != nil {
+		return db.MockGetConfigGenerations(FabricID)
+	}
+	return []domain.ConfigGeneration{}, nil
+}
+
+//GetConfigGeneration represents a mock GetConfigGeneration
+func (db *DatabaseRepository) GetConfigGeneration(FabricID uint, Generation uint) (domain.ConfigGeneration, error) {
+	if db.MockGetConfigGeneration != nil {
+		return db.MockGetConfigGeneration(FabricID, Generation)
+	}
+	return domain.ConfigGeneration{}, nil
+}
+
+//CreateFabricSettingChange represents a mock CreateFabricSettingChange
+func (db *DatabaseRepository) CreateFabricSettingChange(Change *domain.FabricSettingChange) error {
+	if db.MockCreateFabricSettingChange != nil {
+		return db.MockCreateFabricSettingChange(Change)
+	}
+	return nil
+}
+
+//GetFabricSettingChanges represents a mock GetFabricSettingChanges
+func (db *DatabaseRepository) GetFabricSettingChanges(FabricID uint) ([]domain.FabricSettingChange, error) {
+	if db.MockGetFabricSettingChanges != nil {
+		return db.MockGetFabricSettingChanges(FabricID)
+	}
+	return []domain.FabricSettingChange{}, nil
+}
+
+//CreateNotificationSubscription represents a mock CreateNotificationSubscription
+func (db *DatabaseRepository) CreateNotificationSubscription(Subscription *domain.NotificationSubscription) error {
+	if db.MockCreateNotificationSubscription != nil {
+		return db.MockCreateNotificationSubscription(Subscription)
+	}
+	return nil
+}
+
+//GetNotificationSubscriptions represents a mock GetNotificationSubscriptions
+func (db *DatabaseRepository) GetNotificationSubscriptions() ([]domain.NotificationSubscription, error) {
+	if db.MockGetNotificationSubscriptions != nil {
+		return db.MockGetNotificationSubscriptions()
+	}
+	return []domain.NotificationSubscription{}, nil
+}
+
+//GetNotificationSubscription represents a mock GetNotificationSubscription
+func (db *DatabaseRepository) GetNotificationSubscription(ID uint) (domain.NotificationSubscription, error) {
+	if db.MockGetNotificationSubscription != nil {
+		return db.MockGetNotificationSubscription(ID)
+	}
+	return domain.NotificationSubscription{}, nil
+}
+
+//DeleteNotificationSubscription represents a mock DeleteNotificationSubscription
+func (db *DatabaseRepository) DeleteNotificationSubscription(ID uint) error {
+	if db.MockDeleteNotificationSubscription != nil {
+		return db.MockDeleteNotificationSubscription(ID)
+	}
+	return nil
+}
+
 //CreateExecutionLog represents a mock CreateExecutionLog
 func (db *DatabaseRepository) CreateExecutionLog(ExecutionLog *domain.ExecutionLog) error {
 	if db.MockCreateExecutionLog != nil {
@@ -1311,6 +1444,23 @@ func (db *DatabaseRepository) DeleteLLDPMarkedForDelete(FabricID uint) error {
 
 }
 
+//UpdateLLDPConfigTypeOnLinks represents a mock UpdateLLDPConfigTypeOnLinks
+func (db *DatabaseRepository) UpdateLLDPConfigTypeOnLinks(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint,
+	QueryconfigTypes []string, configType string) error {
+	if db.MockUpdateLLDPConfigTypeOnLinks != nil {
+		return db.MockUpdateLLDPConfigTypeOnLinks(FabricID, DeviceIDs, InterfaceIDs, QueryconfigTypes, configType)
+	}
+	return nil
+}
+
+//DeleteLLDPMarkedForDeleteOnLinks represents a mock DeleteLLDPMarkedForDeleteOnLinks
+func (db *DatabaseRepository) DeleteLLDPMarkedForDeleteOnLinks(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint) error {
+	if db.MockDeleteLLDPMarkedForDeleteOnLinks != nil {
+		return db.MockDeleteLLDPMarkedForDeleteOnLinks(FabricID, DeviceIDs, InterfaceIDs)
+	}
+	return nil
+}
+
 //DeleteMctClustersWithMgmtIP represents a mock DeleteMctClustersWithMgmtIP
 func (db *DatabaseRepository) DeleteMctClustersWithMgmtIP(IPAddress string) error {
 	if db.MockDeleteMctClustersWithMgmtIP != nil {
@@ -18,6 +18,7 @@ type DeviceAdapter struct {
 	MockGetASN                 func(FabricID uint, Device uint, DeviceIP string) (string, error)
 	MockEnableInterfaces       func(InterfaceNames []string) (string, error)
 	MockGetInterfaceSpeed      func(InterfaceType string, InterfaceName string) (int, error)
+	MockGetInterfaceMtus       func(DeviceIP string) (map[string]int, error)
 	MockGetInterfaceVe         func(name string) (map[string]string, error)
 	MockGetClusterByName       func(name string) (map[string]string, error)
 	MockGetInterfacePoMember   func(name string) (map[string]string, error)
@@ -41,7 +42,10 @@ func GetDeviceAdapterFactory(deviceAdapter DeviceAdapter) func(ctx context.Conte
 			MockGetLLDPs: deviceAdapter.MockGetLLDPs, MockConfigureFabric: deviceAdapter.MockConfigureFabric,
 			MockGetASN: deviceAdapter.MockGetASN, MockEnableInterfaces: deviceAdapter.MockEnableInterfaces,
 			MockGetInterfaceSpeed: deviceAdapter.MockGetInterfaceSpeed, MockGetInterfaceVe: deviceAdapter.MockGetInterfaceVe,
+			MockGetInterfaceMtus: deviceAdapter.MockGetInterfaceMtus,
 			MockGetClusterByName: deviceAdapter.MockGetClusterByName, MockGetInterfacePoMember: deviceAdapter.MockGetInterfacePoMember,
+			MockGetDeviceDetail: deviceAdapter.MockGetDeviceDetail, MockGetSwitchHostName: deviceAdapter.MockGetSwitchHostName,
+			MockCheckSupportedFirmware: deviceAdapter.MockCheckSupportedFirmware,
 		}
 		return &CloneDeviceAdapter, nil
 	}
@@ -118,6 +122,14 @@ func (ad *DeviceAdapter) GetInterfaceSpeed(InterfaceType string, InterfaceName s
 	return 0, nil
 }
 
+//GetInterfaceMtus represents a mock GetInterfaceMtus
+func (ad *DeviceAdapter) GetInterfaceMtus() (map[string]int, error) {
+	if ad.MockGetInterfaceMtus != nil {
+		return ad.MockGetInterfaceMtus(ad.IPAddress)
+	}
+	return map[string]int{}, nil
+}
+
 //GetInterfaceVe represents a mock GetInterfaceVe
 func (ad *DeviceAdapter) GetInterfaceVe(name string) (map[string]string, error) {
 	var m map[string]string
@@ -10,7 +10,11 @@ import (
 type FabricAdapter struct {
 	MockConfigureDeConfigureMctClusters func(ctx context.Context, operation uint, config []operation.ConfigCluster, force bool) []actions.OperationError
 	MockConfigureFabric                 func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError
+	MockRotateBGPPasswords              func(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError
+	MockConfigureMaintenanceMode        func(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError
+	MockConfigureLinks                  func(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError
 	MockFetchFabricConfiguration        func(ctx context.Context, FabricRequest operation.FabricFetchRequest) (operation.FabricFetchResponse, error)
+	MockFetchFabricHealth               func(ctx context.Context, FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse, []actions.OperationError)
 	MockClearConfig                     func(ctx context.Context, ClearFabricEquest operation.ClearFabricRequest) error
 	MockCleanupDevicesInFabric          func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError
 	MockCleanupDevicesInNonCLOSFabric   func(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError
@@ -36,6 +40,30 @@ func (fa *FabricAdapter) ConfigureFabric(ctx context.Context, config operation.C
 	return []actions.OperationError{}
 }
 
+//RotateBGPPasswords returns mock of RotateBGPPasswords
+func (fa *FabricAdapter) RotateBGPPasswords(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError {
+	if fa.MockRotateBGPPasswords != nil {
+		return fa.MockRotateBGPPasswords(ctx, config)
+	}
+	return []actions.OperationError{}
+}
+
+//ConfigureMaintenanceMode returns mock of ConfigureMaintenanceMode
+func (fa *FabricAdapter) ConfigureMaintenanceMode(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError {
+	if fa.MockConfigureMaintenanceMode != nil {
+		return fa.MockConfigureMaintenanceMode(ctx, config, enable)
+	}
+	return []actions.OperationError{}
+}
+
+//ConfigureLinks returns mock of ConfigureLinks
+func (fa *FabricAdapter) ConfigureLinks(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError {
+	if fa.MockConfigureLinks != nil {
+		return fa.MockConfigureLinks(ctx, config, persist)
+	}
+	return []actions.OperationError{}
+}
+
 //FetchFabricConfiguration returns mock of FetchFabricConfiguration
 func (fa *FabricAdapter) FetchFabricConfiguration(ctx context.Context, FabricRequest operation.FabricFetchRequest) (operation.FabricFetchResponse, error) {
 	if fa.MockFetchFabricConfiguration != nil {
@@ -44,6 +72,15 @@ func (fa *FabricAdapter) FetchFabricConfiguration(ctx context.Context, FabricReq
 	return operation.FabricFetchResponse{}, nil
 }
 
+//FetchFabricHealth returns mock of FetchFabricHealth
+func (fa *FabricAdapter) FetchFabricHealth(ctx context.Context, FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse,
+	[]actions.OperationError) {
+	if fa.MockFetchFabricHealth != nil {
+		return fa.MockFetchFabricHealth(ctx, FabricRequest)
+	}
+	return operation.FabricHealthResponse{FabricName: FabricRequest.FabricName}, []actions.OperationError{}
+}
+
 //ClearConfig returns mock of ClearConfig
 func (fa *FabricAdapter) ClearConfig(ctx context.Context, ClearFabricEquest operation.ClearFabricRequest) error {
 	if fa.MockClearConfig != nil {
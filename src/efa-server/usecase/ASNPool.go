@@ -13,19 +13,33 @@ func init() {
 
 }
 
-//PopulateASN creates the ASNAllocationPool for a given fabric, device role
+//PopulateASN adds the ASN range to the ASNAllocationPool for a given fabric, device role
 func (sh *DeviceInteractor) PopulateASN(ctx context.Context, FabricName string, ASNMin uint64, ASNMax uint64, fabricID uint, role string) error {
 	LOG := appcontext.Logger(ctx)
 	LOG.Infof("Populate ASN Pool(%d-%d) for role %s", ASNMin, ASNMax, role)
-	for i := ASNMin; i <= ASNMax; i++ {
-		asn := domain.ASNAllocationPool{FabricID: fabricID,
-			ASN: i, DeviceRole: role}
-		if err := sh.Db.CreateASN(&asn); err != nil {
-			DEC(LOG).Println(err)
-			statusMsg := fmt.Sprintf("ASN Pool Initialization Failed for Role %s", role)
-			DEC(LOG).Infoln(statusMsg)
-			return errors.New(statusMsg)
-		}
+	if err := sh.addToPool(fabricID, domain.PoolTypeASN, role, ASNMin, ASNMax); err != nil {
+		DEC(LOG).Println(err)
+		statusMsg := fmt.Sprintf("ASN Pool Initialization Failed for Role %s", role)
+		DEC(LOG).Infoln(statusMsg)
+		return errors.New(statusMsg)
+	}
+	return nil
+}
+
+//ResizeASN moves the ASNAllocationPool of a device role from the old to the new ASN range,
+//keeping the allocated ASNs and the ASNs available in both ranges
+func (sh *DeviceInteractor) ResizeASN(ctx context.Context, FabricName string, OldASNRange string, NewASNRange string,
+	fabricID uint, role string) error {
+	LOG := appcontext.Logger(ctx)
+	LOG.Infof("Resize ASN Pool(%s to %s) for role %s", OldASNRange, NewASNRange, role)
+	OldMin, OldMax := GetASNMinMax(OldASNRange)
+	NewMin, NewMax := GetASNMinMax(NewASNRange)
+	if err := sh.resizePool(fabricID, domain.PoolTypeASN, role, []valueRange{{OldMin, OldMax}},
+		[]valueRange{{NewMin, NewMax}}); err != nil {
+		DEC(LOG).Println(err)
+		statusMsg := fmt.Sprintf("ASN Pool Resize Failed for Role %s", role)
+		DEC(LOG).Infoln(statusMsg)
+		return errors.New(statusMsg)
 	}
 	return nil
 }
@@ -104,9 +118,18 @@ func (sh *DeviceInteractor) moveFromASNPoolToUsedASN(LOG *nLOG.Entry, FabricID u
 	sh.makeUsedASNEntryForDevice(LOG, FabricID, DeviceID, asn.ASN, role)
 }
 
-func (sh *DeviceInteractor) getASNCountInPool(LOG *nLOG.Entry, FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool) {
+//GetASNCountInPool returns the count of the ASN in the ASNAllocationPool of the device role,
+//Count of Zero indicates that the ASN is not in the Pool
+func (sh *DeviceInteractor) GetASNCountInPool(ctx context.Context, FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool) {
+	return sh.getASNCountInPool(appcontext.Logger(ctx), FabricID, asn, role)
+}
 
-	asnCount, asndb := sh.Db.GetASNAndCountOnASNAndRole(FabricID, asn, role)
+func (sh *DeviceInteractor) getASNCountInPool(LOG *nLOG.Entry, FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool) {
+	var asnCount int64
+	if sh.isInPool(FabricID, domain.PoolTypeASN, role, asn) {
+		asnCount = 1
+	}
+	asndb := domain.ASNAllocationPool{FabricID: FabricID, ASN: asn, DeviceRole: role}
 
 	LOG.Infof("ASN Pool: Count of  ASN %d role  %s %d", asn, role, asnCount)
 	return asnCount, asndb
@@ -156,26 +179,28 @@ func (sh *DeviceInteractor) makeUsedASNEntryForDevice(LOG *nLOG.Entry, FabricID
 func (sh *DeviceInteractor) getASNCountForRole(LOG *nLOG.Entry, FabricID uint, role string) (int64, error) {
 
 	LOG.Infof("Get ASN Count in Allocation Table for role %s", role)
-	asnCount, err := sh.Db.GetASNCountOnRole(FabricID, role)
+	asnCount, err := sh.poolSize(FabricID, domain.PoolTypeASN, role)
 	if err != nil {
 		return 0, nil
 	}
 	LOG.Infof("Value of ASN Count in Allocation Table for role %s Count %d", role, asnCount)
-	return asnCount, nil
+	return int64(asnCount), nil
 
 }
 
 func (sh *DeviceInteractor) returnASNToPool(LOG *nLOG.Entry, FabricID uint, role string, asn uint64) {
-	asnCount, err := sh.Db.GetASNCountOnASN(FabricID, asn)
+	asnCount, err := sh.Db.GetAllocationRangeCountOnValue(FabricID, domain.PoolTypeASN, asn)
 	if err != nil {
 		LOG.Infof("No Entry for asn %d", asn)
 	}
+	//A pinned ASN stays reserved for the device it is pinned to
+	if asnCount == 0 && sh.isPinned(FabricID, domain.PoolTypeASN, role, asn) {
+		LOG.Infof("ASN %d is pinned, keep it out of the Allocation Table for role %s", asn, role)
+		return
+	}
 	if asnCount == 0 {
 		LOG.Infof("Add ASN back in Allocation Table for role %s ASN %d", role, asn)
-		asndb := domain.ASNAllocationPool{FabricID: FabricID,
-			ASN: asn, DeviceRole: role}
-
-		if err := sh.Db.CreateASN(&asndb); err != nil {
+		if err := sh.addToPool(FabricID, domain.PoolTypeASN, role, asn, asn); err != nil {
 			DEC(LOG).Infoln(err)
 		}
 
@@ -185,8 +210,9 @@ func (sh *DeviceInteractor) returnASNToPool(LOG *nLOG.Entry, FabricID uint, role
 
 func (sh *DeviceInteractor) getNextASNFromPool(LOG *nLOG.Entry, FabricID uint, role string) (domain.ASNAllocationPool, error) {
 	LOG.Infof("Fetch next ASN from Allocation Table for role %s", role)
-	asn, err := sh.Db.GetNextASNForRole(FabricID, role)
-	if err != nil {
+	asn := domain.ASNAllocationPool{FabricID: FabricID, DeviceRole: role}
+	var err error
+	if asn.ASN, err = sh.nextInPool(FabricID, domain.PoolTypeASN, role); err != nil {
 		statusMsg := fmt.Sprintf("ASN Exhausted for %d", FabricID)
 		DEC(LOG).Println(statusMsg)
 		return asn, errors.New(statusMsg)
@@ -197,5 +223,5 @@ func (sh *DeviceInteractor) getNextASNFromPool(LOG *nLOG.Entry, FabricID uint, r
 
 func (sh *DeviceInteractor) deleteASN(LOG *nLOG.Entry, asn *domain.ASNAllocationPool) error {
 	LOG.Infof("Delete  ASN from Allocation Table for Fabric %d Role %s", asn.FabricID, asn.DeviceRole)
-	return sh.Db.DeleteASN(asn)
+	return sh.removeFromPool(asn.FabricID, domain.PoolTypeASN, asn.DeviceRole, asn.ASN, asn.ASN)
 }
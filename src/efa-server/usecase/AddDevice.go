@@ -52,12 +52,15 @@ type DeviceInteractor struct {
 	Db                   Interactor.DatabaseRepository
 	FabricAdapter        Interactor.FabricAdapter
 	DeviceAdapterFactory func(ctx context.Context, IPAddress string, UserName string, Password string) (Interactor.DeviceAdapter, error)
+	Notifier             Interactor.Notifier
 	FabricID             uint
 	FabricName           string
 	FabricProperties     domain.FabricProperties
 	AppContext           context.Context
 	DBMutex              sync.Mutex
 	Refresh              bool
+	//HealthSettleTimeout is how long the health of a configured fabric is checked again until it is healthy
+	HealthSettleTimeout time.Duration
 }
 
 //AddDeviceFirstStage does the following
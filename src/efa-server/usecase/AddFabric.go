@@ -23,53 +23,6 @@ func (sh *DeviceInteractor) CloseTransaction(ctx context.Context, RollBack *bool
 	sh.Db.CommitTransaction()
 }
 
-//DatabaseUpgrade add routines for database upgrade
-func (sh *DeviceInteractor) DatabaseUpgrade(ctx context.Context, FabricName string) error {
-	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Database Upgrade")
-	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
-	LOG := appcontext.Logger(ctx)
-	var Fabric domain.Fabric
-	var err error
-	RollBack := true
-	//Check if Fabric Already exists
-	if Fabric, err = sh.Db.GetFabric(FabricName); err != nil {
-		statusMsg := fmt.Sprintf("Fabric %s does not exist", FabricName)
-		DEC(LOG).Errorln(statusMsg)
-		return errors.New(statusMsg)
-	}
-	//Start Transaction
-	sh.DBMutex.Lock()
-	defer sh.DBMutex.Unlock()
-	err = sh.Db.OpenTransaction()
-	if err != nil {
-		return err
-	}
-	defer sh.CloseTransaction(ctx, &RollBack)
-
-	//Check Non-CLOS ASN Pool
-	FabricProp, err := sh.Db.GetFabricProperties(Fabric.ID)
-	asnCount, err := sh.Db.GetASNCountOnRole(Fabric.ID, RackRole)
-	LOG.Println("Number of ROle ASN", asnCount)
-	if asnCount == 0 {
-		LOG.Println("Creating RACk ASN Block")
-		asnMin, asnMax := GetASNMinMax(FabricProp.RackASNBlock)
-		if err := sh.PopulateASN(ctx, FabricName, asnMin, asnMax, Fabric.ID, RackRole); err != nil {
-			LOG.Infoln(err)
-			return err
-		}
-		LOG.Println("Creating MCT L3 Loopback IP Range")
-		//Populate IPPair Pool with MCT L3 Loopback IP Range
-		if err := sh.PopulateIPPairs(ctx, FabricName, Fabric.ID, FabricProp.MCTL3LBIPRange, domain.RackL3LoopBackPoolName, true); err != nil {
-			LOG.Infoln(err)
-			return err
-		}
-	}
-
-	//Operation is Success, Set RollBack to False
-	RollBack = false
-	return nil
-}
-
 //AddFabric adds a given fabric to the application database and initializes the necessary IP Pools
 func (sh *DeviceInteractor) AddFabric(ctx context.Context, FabricName string) error {
 	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Add Fabric")
@@ -134,8 +87,6 @@ func (sh *DeviceInteractor) AddFabric(ctx context.Context, FabricName string) er
 }
 
 func updateFabricPropertiesDB(ctx context.Context, sh *DeviceInteractor, FabricID uint, FabricName string, NewFabricProp domain.FabricProperties, OldFabricProp domain.FabricProperties) error {
-	var UpdateASNPool bool
-	var UpdateIPPool bool
 	var err error
 	RollBack := true
 
@@ -147,42 +98,25 @@ func updateFabricPropertiesDB(ctx context.Context, sh *DeviceInteractor, FabricI
 		return err
 	}
 	defer sh.CloseTransaction(ctx, &RollBack)
-	if NewFabricProp.P2PLinkRange != OldFabricProp.P2PLinkRange ||
-		NewFabricProp.LoopBackIPRange != OldFabricProp.LoopBackIPRange ||
-		NewFabricProp.MCTLinkIPRange != OldFabricProp.MCTLinkIPRange ||
-		NewFabricProp.MCTL3LBIPRange != OldFabricProp.MCTL3LBIPRange {
-		UpdateIPPool = true
-		sh.Db.DeleteIPPool()
-		sh.Db.DeleteIPPairPool()
-		sh.Db.DeleteUsedIPPool()
-		sh.Db.DeleteUsedIPPairPool()
-	}
-	if NewFabricProp.LeafASNBlock != OldFabricProp.LeafASNBlock ||
-		NewFabricProp.SpineASNBlock != OldFabricProp.SpineASNBlock ||
-		NewFabricProp.RackASNBlock != OldFabricProp.RackASNBlock {
-		UpdateASNPool = true
-		sh.Db.DeleteASNPool()
-		sh.Db.DeleteUsedASNPool()
-	}
 	if err := sh.createAndUpdateFabricProperites(ctx, FabricName, FabricID, NewFabricProp); err != nil {
 		statusMsg := fmt.Sprintf("Fabric %s update Fabric Property failed", FabricName)
 		return errors.New(statusMsg)
 	}
+	if err := sh.recordSettingChanges(ctx, FabricID, OldFabricProp, NewFabricProp); err != nil {
+		statusMsg := fmt.Sprintf("Fabric %s recording the settings history failed", FabricName)
+		return errors.New(statusMsg)
+	}
 
-	//Populate ASN Block
-	if UpdateASNPool {
-		if err := sh.populateASNPool(ctx, FabricName, FabricID, NewFabricProp); err != nil {
-			statusMsg := fmt.Sprintf("Error Populating ASN Pool: %s", err.Error())
-			return errors.New(statusMsg)
-		}
+	//Resize ASN Block
+	if err := sh.resizeASNPool(ctx, FabricName, FabricID, OldFabricProp, NewFabricProp); err != nil {
+		statusMsg := fmt.Sprintf("Error Resizing ASN Pool: %s", err.Error())
+		return errors.New(statusMsg)
 	}
 
-	//Populate IP Pool
-	if UpdateIPPool {
-		if err := sh.populateIPPool(ctx, FabricName, FabricID, NewFabricProp); err != nil {
-			statusMsg := fmt.Sprintf("Error populating IP Pool: %s for %s", err.Error(), FabricName)
-			return errors.New(statusMsg)
-		}
+	//Resize IP Pool
+	if err := sh.resizeIPPool(ctx, FabricName, FabricID, OldFabricProp, NewFabricProp); err != nil {
+		statusMsg := fmt.Sprintf("Error resizing IP Pool: %s for %s", err.Error(), FabricName)
+		return errors.New(statusMsg)
 	}
 	//Operation is Success, Set RollBack to False
 	RollBack = false
@@ -250,9 +184,15 @@ func (sh *DeviceInteractor) getDefaultProperties() domain.FabricProperties {
 	FabricProp.DuplicateMaxTimerMaxCount = "3"
 
 	//Non Clos Fields
+	FabricProp.FabricType = domain.CLOSFabricType
 	FabricProp.RackPeerEBGPGroup = "underlay-ebgp-group"
 	FabricProp.RackPeerOvgGroup = "overlay-ebgp-group"
 
+	//BGP Authentication Fields, no passwords are configured by default
+	FabricProp.BGPAuthType = domain.BGPAuthTypeMD5
+
+	FabricProp.PoolWarningThreshold = "80" // <NUMBER:1-100>   Pool utilization in percent
+
 	return FabricProp
 }
 
@@ -281,6 +221,42 @@ func (sh *DeviceInteractor) populateIPPool(ctx context.Context, FabricName strin
 	return err
 }
 
+//resizeIPPool resizes the IP and IP pair pools whose range changed
+func (sh *DeviceInteractor) resizeIPPool(ctx context.Context, FabricName string,
+	fabricID uint, old domain.FabricProperties, prop domain.FabricProperties) error {
+	if prop.LoopBackIPRange != old.LoopBackIPRange {
+		if err := sh.ResizeIP(ctx, FabricName, fabricID, old.LoopBackIPRange, prop.LoopBackIPRange, "Loopback", true); err != nil {
+			return err
+		}
+	}
+	if prop.MCTL3LBIPRange != old.MCTL3LBIPRange {
+		if err := sh.ResizeIPPairs(ctx, FabricName, fabricID, old.MCTL3LBIPRange, prop.MCTL3LBIPRange,
+			domain.RackL3LoopBackPoolName, true); err != nil {
+			return err
+		}
+	}
+	//P2P Pool holds IP Pairs only if the type is Numbered
+	if prop.P2PLinkRange != old.P2PLinkRange || prop.P2PIPType != old.P2PIPType {
+		OldP2PLinkRange, P2PLinkRange := old.P2PLinkRange, prop.P2PLinkRange
+		if old.P2PIPType != domain.P2PIpTypeNumbered {
+			OldP2PLinkRange = ""
+		}
+		if prop.P2PIPType != domain.P2PIpTypeNumbered {
+			P2PLinkRange = ""
+		}
+		if err := sh.ResizeIPPairs(ctx, FabricName, fabricID, OldP2PLinkRange, P2PLinkRange, "P2P", false); err != nil {
+			return err
+		}
+	}
+	if prop.MCTLinkIPRange != old.MCTLinkIPRange {
+		if err := sh.ResizeIPPairs(ctx, FabricName, fabricID, old.MCTLinkIPRange, prop.MCTLinkIPRange,
+			domain.MCTPoolName, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //GetASNMinMax returns the min and max ASN value based on the input ASNRange
 func GetASNMinMax(asnRange string) (asnMin, asnMax uint64) {
 	asn := strings.Split(asnRange, "-")
@@ -314,6 +290,27 @@ func (sh *DeviceInteractor) populateASNPool(ctx context.Context, FabricName stri
 	return nil
 }
 
+//resizeASNPool resizes the ASN pools whose block changed
+func (sh *DeviceInteractor) resizeASNPool(ctx context.Context, FabricName string, fabricID uint,
+	old domain.FabricProperties, prop domain.FabricProperties) error {
+	if prop.LeafASNBlock != old.LeafASNBlock {
+		if err := sh.ResizeASN(ctx, FabricName, old.LeafASNBlock, prop.LeafASNBlock, fabricID, "Leaf"); err != nil {
+			return err
+		}
+	}
+	if prop.SpineASNBlock != old.SpineASNBlock {
+		if err := sh.ResizeASN(ctx, FabricName, old.SpineASNBlock, prop.SpineASNBlock, fabricID, "Spine"); err != nil {
+			return err
+		}
+	}
+	if prop.RackASNBlock != old.RackASNBlock {
+		if err := sh.ResizeASN(ctx, FabricName, old.RackASNBlock, prop.RackASNBlock, fabricID, "Rack"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func modifyUpdatedField(d *domain.FabricProperties, s *domain.FabricProperties) {
 	if len(s.P2PLinkRange) != 0 && s.P2PLinkRange != d.P2PLinkRange {
 		d.P2PLinkRange = s.P2PLinkRange
@@ -431,6 +428,29 @@ func modifyUpdatedField(d *domain.FabricProperties, s *domain.FabricProperties)
 	if len(s.RackPeerOvgGroup) != 0 && s.RackPeerOvgGroup != d.RackPeerOvgGroup {
 		d.RackPeerOvgGroup = s.RackPeerOvgGroup
 	}
+	if len(s.PoolWarningThreshold) != 0 && s.PoolWarningThreshold != d.PoolWarningThreshold {
+		d.PoolWarningThreshold = s.PoolWarningThreshold
+	}
+	modifyUpdatedBGPAuthFields(d, s)
+}
+
+//modifyUpdatedBGPAuthFields copies the requested BGP authentication fields, "none" removes the password
+func modifyUpdatedBGPAuthFields(d *domain.FabricProperties, s *domain.FabricProperties) {
+	update := func(d *string, s string) {
+		if len(s) == 0 {
+			return
+		}
+		if s == domain.BGPPasswordNone {
+			*d = ""
+			return
+		}
+		*d = s
+	}
+	update(&d.BGPAuthType, s.BGPAuthType)
+	update(&d.PeerGroupPassword, s.PeerGroupPassword)
+	update(&d.MctL2EvpnPassword, s.MctL2EvpnPassword)
+	update(&d.RackPeerEBGPGroupPassword, s.RackPeerEBGPGroupPassword)
+	update(&d.RackPeerOvgGroupPassword, s.RackPeerOvgGroupPassword)
 }
 
 func (sh *DeviceInteractor) validateOverlappingASNRange(ctx context.Context, prop *domain.FabricProperties) error {
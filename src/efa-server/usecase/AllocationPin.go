@@ -0,0 +1,598 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+//SetAllocationPin pins a value of the ASN, loopback or P2P pool to a device, or to an interface of the device
+//for the P2P pool. The device need not be registered yet. The value is taken out of the pool so that it is
+//not allocated to another device before the pinned device is configured.
+func (sh *DeviceInteractor) SetAllocationPin(ctx context.Context, FabricName string, DeviceIP string, PinType string,
+	Value string, InterfaceName string) (domain.AllocationPin, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Set Allocation Pin")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+	Pin := domain.AllocationPin{DeviceIP: DeviceIP, PinType: PinType, Value: Value, InterfaceName: InterfaceName}
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Pin, statusMsg, domain.ErrFabricNotFound
+	}
+	Pin.FabricID = Fabric.ID
+	FabricProperties, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric Properties for %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Pin, statusMsg, domain.ErrFabricInternalError
+	}
+	if net.ParseIP(DeviceIP).To4() == nil {
+		statusMsg := fmt.Sprintf("%s is not a valid Device IP Address", DeviceIP)
+		LOG.Errorln(statusMsg)
+		return Pin, statusMsg, domain.ErrFabricIncorrectValues
+	}
+	if err = sh.resolvePinPool(FabricName, FabricProperties, &Pin); err != nil {
+		LOG.Errorln(err)
+		return Pin, err.Error(), domain.ErrFabricIncorrectValues
+	}
+
+	Pins, err := sh.Db.GetAllocationPins(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the Allocation Pins of %s", FabricName)
+		LOG.Errorln(statusMsg, err)
+		return Pin, statusMsg, domain.ErrFabricInternalError
+	}
+	//A new value for the same device, type and interface replaces the pinned one
+	var Existing *domain.AllocationPin
+	OtherPins := make([]domain.AllocationPin, 0, len(Pins))
+	for iter := range Pins {
+		if isSamePinTarget(Pins[iter], Pin) {
+			Existing = &Pins[iter]
+			continue
+		}
+		OtherPins = append(OtherPins, Pins[iter])
+	}
+	if Existing != nil && Existing.Value == Pin.Value && Existing.PoolName == Pin.PoolName {
+		return *Existing, fmt.Sprintf("%s %s is already pinned to %s", PinType, Pin.Value, pinTarget(Pin)), nil
+	}
+
+	Holders, err := sh.getAllocationHolders(Fabric.ID)
+	if err != nil {
+		LOG.Errorln(err)
+		return Pin, err.Error(), domain.ErrFabricInternalError
+	}
+	if Conflicts := sh.pinConflicts(FabricName, Pin, OtherPins, Holders); len(Conflicts) != 0 {
+		LOG.Errorln(Conflicts[0])
+		return Pin, Conflicts[0], domain.ErrFabricIncorrectValues
+	}
+	//A value neither in the pool, nor allocated or pinned to a device that can share it, is not usable
+	if !sh.isInPool(Fabric.ID, Pin.PoolType, Pin.PoolName, Pin.PoolValue) &&
+		len(sh.pinAllocations(Fabric.ID, Pin, Holders)) == 0 && !isPinnedIn(OtherPins, Pin) {
+		statusMsg := fmt.Sprintf("%s %s is not available in the %s pool", PinType, Pin.Value, Pin.PoolName)
+		LOG.Errorln(statusMsg)
+		return Pin, statusMsg, domain.ErrFabricIncorrectValues
+	}
+
+	if Existing != nil {
+		Pin.ID = Existing.ID
+	}
+	if err = sh.Db.SaveAllocationPin(&Pin); err != nil {
+		statusMsg := fmt.Sprintf("Failed to save the %s pin of %s", PinType, pinTarget(Pin))
+		LOG.Errorln(statusMsg, err)
+		return Pin, statusMsg, domain.ErrFabricInternalError
+	}
+	if Existing != nil {
+		sh.releasePinnedValue(ctx, Fabric.ID, *Existing, Holders)
+	}
+	if err = sh.reservePinnedValue(Fabric.ID, Pin); err != nil {
+		LOG.Errorln(err)
+	}
+	statusMsg := fmt.Sprintf("%s %s pinned to %s", PinType, Pin.Value, pinTarget(Pin))
+	LOG.Infoln(statusMsg)
+	return Pin, statusMsg, nil
+}
+
+//ClearAllocationPins removes the values pinned to a device. All the pins of the device are removed when the type
+//is empty, and all its P2P pins when the interface is empty. The values which are not allocated are returned to the pools.
+func (sh *DeviceInteractor) ClearAllocationPins(ctx context.Context, FabricName string, DeviceIP string, PinType string,
+	InterfaceName string) ([]domain.AllocationPin, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Clear Allocation Pin")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+	Cleared := make([]domain.AllocationPin, 0)
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Cleared, statusMsg, domain.ErrFabricNotFound
+	}
+	Pins, err := sh.Db.GetAllocationPinsOnDevice(Fabric.ID, DeviceIP)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the Allocation Pins of %s", DeviceIP)
+		LOG.Errorln(statusMsg, err)
+		return Cleared, statusMsg, domain.ErrFabricInternalError
+	}
+	Holders, err := sh.getAllocationHolders(Fabric.ID)
+	if err != nil {
+		LOG.Errorln(err)
+		return Cleared, err.Error(), domain.ErrFabricInternalError
+	}
+	for _, Pin := range Pins {
+		if (PinType != "" && Pin.PinType != PinType) || (InterfaceName != "" && Pin.InterfaceName != InterfaceName) {
+			continue
+		}
+		if err = sh.Db.DeleteAllocationPin(&Pin); err != nil {
+			statusMsg := fmt.Sprintf("Failed to clear the %s pin of %s", Pin.PinType, pinTarget(Pin))
+			LOG.Errorln(statusMsg, err)
+			return Cleared, statusMsg, domain.ErrFabricInternalError
+		}
+		sh.releasePinnedValue(ctx, Fabric.ID, Pin, Holders)
+		Cleared = append(Cleared, Pin)
+	}
+	if len(Cleared) == 0 {
+		statusMsg := fmt.Sprintf("No Allocation Pin found for %s", DeviceIP)
+		LOG.Errorln(statusMsg)
+		return Cleared, statusMsg, domain.ErrDeviceNotFound
+	}
+	statusMsg := fmt.Sprintf("%d Allocation Pin(s) cleared for %s", len(Cleared), DeviceIP)
+	LOG.Infoln(statusMsg)
+	return Cleared, statusMsg, nil
+}
+
+//GetAllocationPins returns the values pinned to a device, or to all the devices of the fabric when DeviceIP is empty
+func (sh *DeviceInteractor) GetAllocationPins(ctx context.Context, FabricName string, DeviceIP string) ([]domain.AllocationPin, error) {
+	LOG := appcontext.Logger(ctx)
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		LOG.Printf("Unable to retrieve Fabric for %s", FabricName)
+		return []domain.AllocationPin{}, domain.ErrFabricNotFound
+	}
+	if DeviceIP == "" {
+		return sh.Db.GetAllocationPins(Fabric.ID)
+	}
+	return sh.Db.GetAllocationPinsOnDevice(Fabric.ID, DeviceIP)
+}
+
+//resolvePinPool sets the pool of the fabric holding the pinned value and the value as stored in the pool.
+//An ASN is pinned in the pool of the role of the device, or in the pool whose range holds the ASN
+//when the device is not registered yet.
+func (sh *DeviceInteractor) resolvePinPool(FabricName string, FabricProperties domain.FabricProperties,
+	Pin *domain.AllocationPin) error {
+	if Pin.PinType != domain.PinTypeP2P && Pin.InterfaceName != "" {
+		return fmt.Errorf("An interface can only be given for a %s pin", domain.PinTypeP2P)
+	}
+	switch Pin.PinType {
+	case domain.PinTypeASN:
+		asn, err := strconv.ParseUint(Pin.Value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s is not a valid ASN", Pin.Value)
+		}
+		Pin.Value, Pin.PoolType, Pin.PoolValue = strconv.FormatUint(asn, 10), domain.PoolTypeASN, asn
+		Role := ""
+		if Device, err := sh.Db.GetDevice(FabricName, Pin.DeviceIP); err == nil {
+			Role = Device.DeviceRole
+		}
+		for _, Pool := range fabricPools(FabricProperties) {
+			if Pool.PoolType != domain.PoolTypeASN || (Role != "" && Pool.PoolName != Role) {
+				continue
+			}
+			if asnMin, asnMax := GetASNMinMax(Pool.Range); asn >= asnMin && asn <= asnMax {
+				Pin.PoolName = Pool.PoolName
+				return nil
+			}
+			if Role != "" {
+				return fmt.Errorf("ASN %d is not in the %s ASN range %s", asn, Role, Pool.Range)
+			}
+		}
+		return fmt.Errorf("ASN %d is not in any ASN range of the fabric", asn)
+	case domain.PinTypeLoopback:
+		return resolveIPPin(Pin, domain.PoolTypeIP, "Loopback", FabricProperties.LoopBackIPRange)
+	case domain.PinTypeP2P:
+		if FabricProperties.FabricType == domain.NonCLOSFabricType ||
+			FabricProperties.P2PIPType != domain.P2PIpTypeNumbered {
+			return fmt.Errorf("P2P addresses can only be pinned when the P2P IP type is %s", domain.P2PIpTypeNumbered)
+		}
+		if Pin.InterfaceName == "" {
+			return fmt.Errorf("An interface is required for a %s pin", domain.PinTypeP2P)
+		}
+		return resolveIPPin(Pin, domain.PoolTypeIPPair, "P2P", FabricProperties.P2PLinkRange)
+	}
+	return fmt.Errorf("Pin type should be one of %s, %s or %s", domain.PinTypeASN, domain.PinTypeLoopback, domain.PinTypeP2P)
+}
+
+//resolveIPPin sets the pool of a pinned IP address, the address may be given along with its prefix length
+func resolveIPPin(Pin *domain.AllocationPin, PoolType string, PoolName string, IPRange string) error {
+	Address := Pin.Value
+	if ip, _, err := net.ParseCIDR(Address); err == nil {
+		Address = ip.String()
+	}
+	Value, err := ipToValue(Address)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid IP Address", Pin.Value)
+	}
+	_, Network, err := net.ParseCIDR(IPRange)
+	if err != nil || !Network.Contains(net.ParseIP(Address)) {
+		return fmt.Errorf("%s is not in the %s range %s", Address, PoolName, IPRange)
+	}
+	Pin.Value, Pin.PoolType, Pin.PoolName, Pin.PoolValue = valueToIP(Value), PoolType, PoolName, Value
+	if PoolType == domain.PoolTypeIPPair {
+		Pin.PoolValue = Value / 2
+	}
+	return nil
+}
+
+//pinConflicts returns the reasons why the pinned value cannot be held by the device, the value being pinned or
+//allocated to another device, or to another interface for the P2P pool. An ASN can be shared by the spines and
+//by the nodes of an MCT pair, and the two addresses of a P2P pair are pinned to the two ends of a link.
+func (sh *DeviceInteractor) pinConflicts(FabricName string, Pin domain.AllocationPin, Pins []domain.AllocationPin,
+	Holders allocationHolders) []string {
+	Conflicts := make([]string, 0)
+	Peers := sh.mctPeerIPs(FabricName, Pin.DeviceIP)
+	canShareASN := func(DeviceIP string) bool {
+		return DeviceIP == Pin.DeviceIP || Pin.PoolName == SpineRole || containsString(Peers, DeviceIP)
+	}
+
+	for _, Other := range Pins {
+		//The nodes of an MCT pair share their ASN, so they cannot be pinned to different ASNs
+		if Pin.PinType == domain.PinTypeASN && Other.PinType == domain.PinTypeASN &&
+			containsString(Peers, Other.DeviceIP) && Other.Value != Pin.Value {
+			Conflicts = append(Conflicts, fmt.Sprintf("ASN %s pinned to %s differs from ASN %s pinned to its MCT peer %s",
+				Pin.Value, Pin.DeviceIP, Other.Value, Other.DeviceIP))
+			continue
+		}
+		if Other.PoolType != Pin.PoolType || Other.PoolName != Pin.PoolName || Other.PoolValue != Pin.PoolValue {
+			continue
+		}
+		switch {
+		case Pin.PoolType == domain.PoolTypeASN && canShareASN(Other.DeviceIP):
+		case Pin.PoolType == domain.PoolTypeIPPair && Other.Value != Pin.Value:
+		default:
+			Conflicts = append(Conflicts, fmt.Sprintf("%s %s of %s is already pinned to %s", Pin.PinType, Pin.Value,
+				pinTarget(Pin), pinTarget(Other)))
+		}
+	}
+
+	Allocations := sh.pinAllocations(Pin.FabricID, Pin, Holders)
+	switch Pin.PoolType {
+	case domain.PoolTypeASN:
+		for _, Allocation := range Allocations {
+			if !canShareASN(Allocation.IPAddress) {
+				Conflicts = append(Conflicts, fmt.Sprintf("ASN %s of %s is allocated to %s", Pin.Value,
+					pinTarget(Pin), Allocation.IPAddress))
+			}
+		}
+	case domain.PoolTypeIP:
+		for _, Allocation := range Allocations {
+			if Allocation.IPAddress != Pin.DeviceIP {
+				Conflicts = append(Conflicts, fmt.Sprintf("%s %s of %s is allocated to %s %s", Pin.PinType, Pin.Value,
+					pinTarget(Pin), Allocation.IPAddress, Allocation.InterfaceName))
+			}
+		}
+	case domain.PoolTypeIPPair:
+		//The allocations of a pair come in twos, one for each end of the link
+		for iter := 0; iter+1 < len(Allocations); iter += 2 {
+			if !isPinnedInterface(Allocations[iter], Pin) && !isPinnedInterface(Allocations[iter+1], Pin) {
+				Conflicts = append(Conflicts, fmt.Sprintf("%s %s of %s is allocated to the link %s %s - %s %s", Pin.PinType,
+					Pin.Value, pinTarget(Pin), Allocations[iter].IPAddress, Allocations[iter].InterfaceName,
+					Allocations[iter+1].IPAddress, Allocations[iter+1].InterfaceName))
+			}
+		}
+	}
+	return Conflicts
+}
+
+//pinAllocations returns the devices and interfaces the pinned value of the pool is allocated to
+func (sh *DeviceInteractor) pinAllocations(FabricID uint, Pin domain.AllocationPin, Holders allocationHolders) []PoolAllocation {
+	Allocations := make([]PoolAllocation, 0)
+	switch Pin.PoolType {
+	case domain.PoolTypeASN:
+		UsedASNs, _ := sh.Db.GetUsedASNsOnRole(FabricID, Pin.PoolName)
+		for _, UsedASN := range UsedASNs {
+			if UsedASN.ASN == Pin.PoolValue {
+				Allocations = append(Allocations, PoolAllocation{Value: Pin.Value, IPAddress: Holders.Devices[UsedASN.DeviceID]})
+			}
+		}
+	case domain.PoolTypeIP:
+		UsedIPs, _ := sh.Db.GetUsedIPsOnType(FabricID, Pin.PoolName)
+		for _, UsedIP := range UsedIPs {
+			if UsedIP.IPAddress == Pin.Value {
+				Allocations = append(Allocations, PoolAllocation{Value: UsedIP.IPAddress,
+					IPAddress: Holders.Devices[UsedIP.DeviceID], InterfaceName: Holders.Interfaces[UsedIP.InterfaceID]})
+			}
+		}
+	case domain.PoolTypeIPPair:
+		UsedIPPairs, _ := sh.Db.GetUsedIPPairsOnType(FabricID, Pin.PoolName)
+		for _, UsedIPPair := range UsedIPPairs {
+			if Value, err := ipPairToValue(UsedIPPair.IPAddressOne, UsedIPPair.IPAddressTwo); err == nil && Value == Pin.PoolValue {
+				Allocations = append(Allocations,
+					PoolAllocation{Value: UsedIPPair.IPAddressOne, IPAddress: Holders.Devices[UsedIPPair.DeviceOneID],
+						InterfaceName: Holders.Interfaces[UsedIPPair.InterfaceOneID]},
+					PoolAllocation{Value: UsedIPPair.IPAddressTwo, IPAddress: Holders.Devices[UsedIPPair.DeviceTwoID],
+						InterfaceName: Holders.Interfaces[UsedIPPair.InterfaceTwoID]})
+			}
+		}
+	}
+	return Allocations
+}
+
+//reservePinnedValue takes the pinned value out of the pool. The last ASN of a role stays in the pool,
+//as it is shared by the devices once the pool is exhausted.
+func (sh *DeviceInteractor) reservePinnedValue(FabricID uint, Pin domain.AllocationPin) error {
+	if !sh.isInPool(FabricID, Pin.PoolType, Pin.PoolName, Pin.PoolValue) {
+		return nil
+	}
+	if Pin.PoolType == domain.PoolTypeASN {
+		if Size, err := sh.poolSize(FabricID, Pin.PoolType, Pin.PoolName); err != nil || Size <= 1 {
+			return err
+		}
+	}
+	return sh.removeFromPool(FabricID, Pin.PoolType, Pin.PoolName, Pin.PoolValue, Pin.PoolValue)
+}
+
+//releasePinnedValue returns the value of a cleared pin to the pool, unless the value is still pinned or allocated
+func (sh *DeviceInteractor) releasePinnedValue(ctx context.Context, FabricID uint, Pin domain.AllocationPin,
+	Holders allocationHolders) {
+	LOG := appcontext.Logger(ctx)
+	if sh.isPinned(FabricID, Pin.PoolType, Pin.PoolName, Pin.PoolValue) ||
+		len(sh.pinAllocations(FabricID, Pin, Holders)) != 0 {
+		return
+	}
+	LOG.Infof("Return %s %s to the %s pool", Pin.PinType, Pin.Value, Pin.PoolName)
+	if err := sh.addToPool(FabricID, Pin.PoolType, Pin.PoolName, Pin.PoolValue, Pin.PoolValue); err != nil {
+		LOG.Errorln(err)
+	}
+}
+
+//isPinned returns true if the value of the pool is pinned to a device, such a value is kept out of the pool when released
+func (sh *DeviceInteractor) isPinned(FabricID uint, PoolType string, PoolName string, Value uint64) bool {
+	pinCount, err := sh.Db.GetAllocationPinCountOnValue(FabricID, PoolType, PoolName, Value)
+	return err == nil && pinCount != 0
+}
+
+//getAllocationPin returns the value pinned to the device, or to the interface of the device for the P2P pool
+func (sh *DeviceInteractor) getAllocationPin(DeviceIP string, PinType string, InterfaceName string) (domain.AllocationPin, bool) {
+	Pins, err := sh.Db.GetAllocationPinsOnDevice(sh.FabricID, DeviceIP)
+	if err != nil {
+		return domain.AllocationPin{}, false
+	}
+	for _, Pin := range Pins {
+		if Pin.PinType == PinType && Pin.InterfaceName == InterfaceName {
+			return Pin, true
+		}
+	}
+	return domain.AllocationPin{}, false
+}
+
+//getPinnedASN returns the ASN pinned to the device, or to one of its MCT peers as the nodes of a pair share their ASN
+func (sh *DeviceInteractor) getPinnedASN(Device *domain.Device) (domain.AllocationPin, bool) {
+	if Pin, found := sh.getAllocationPin(Device.IPAddress, domain.PinTypeASN, ""); found {
+		return Pin, true
+	}
+	for _, PeerIP := range sh.mctPeerIPs(sh.FabricName, Device.IPAddress) {
+		if Pin, found := sh.getAllocationPin(PeerIP, domain.PinTypeASN, ""); found {
+			return Pin, true
+		}
+	}
+	return domain.AllocationPin{}, false
+}
+
+//computePinnedASN allocates the pinned ASN to the device, releasing the ASN previously allocated to it
+func (sh *DeviceInteractor) computePinnedASN(ctx context.Context, Device *domain.Device, DBSwitchConfig *domain.SwitchConfig,
+	OnSwitchConfig *domain.SwitchConfig, Pin domain.AllocationPin) error {
+	LOG := appcontext.Logger(ctx)
+	if Pin.PoolName != Device.DeviceRole {
+		return fmt.Errorf("ASN %s pinned to %s is not in the %s ASN range", Pin.Value, Pin.DeviceIP, Device.DeviceRole)
+	}
+	if DBSwitchConfig.LocalAS != "" && DBSwitchConfig.LocalAS != Pin.Value {
+		asn, _ := strconv.ParseUint(DBSwitchConfig.LocalAS, 10, 64)
+		sh.ReleaseASN(ctx, sh.FabricID, Device.ID, DBSwitchConfig.Role, asn)
+	}
+	if err := sh.makeUsedASNEntryForDevice(LOG, sh.FabricID, Device.ID, Pin.PoolValue, Device.DeviceRole); err != nil {
+		return err
+	}
+	OnSwitchConfig.LocalAS = Pin.Value
+	OnSwitchConfig.ASConfigType = pinConfigType(Device.LocalAs, Pin.Value)
+	LOG.Infoln("Compute ASN: Pinned ASN Allocated:", OnSwitchConfig.LocalAS, "Config Type:", OnSwitchConfig.ASConfigType)
+	return nil
+}
+
+//computePinnedLoopBackIP allocates the pinned Loopback IP to the device, releasing the IP previously allocated to it
+func (sh *DeviceInteractor) computePinnedLoopBackIP(ctx context.Context, Device *domain.Device, LoopBackOnDevice string,
+	LoopBackInDB string, InterfaceID uint, Pin domain.AllocationPin) (string, string, error) {
+	LOG := appcontext.Logger(ctx)
+	if LoopBackInDB != "" && LoopBackInDB != Pin.Value {
+		sh.ReleaseIP(ctx, sh.FabricID, Device.ID, Pin.PoolName, LoopBackInDB, InterfaceID)
+	}
+	if _, err := sh.Db.GetUsedIPOnDeviceInterfaceIDIPAddresssAndType(sh.FabricID, Device.ID, Pin.Value,
+		Pin.PoolName, InterfaceID); err != nil {
+		sh.removeFromPool(sh.FabricID, Pin.PoolType, Pin.PoolName, Pin.PoolValue, Pin.PoolValue)
+		usedEntry := domain.UsedIP{FabricID: sh.FabricID, DeviceID: Device.ID, IPAddress: Pin.Value,
+			IPType: Pin.PoolName, InterfaceID: InterfaceID}
+		if err := sh.Db.CreateUsedIPEntry(&usedEntry); err != nil {
+			return "", domain.ConfigNone, err
+		}
+	}
+	LoopBackIPConfigType := pinConfigType(LoopBackOnDevice, Pin.Value)
+	LOG.Infoln("Compute Loopback: Pinned Loopback Allocated:", Pin.Value, "Config Type:", LoopBackIPConfigType)
+	return Pin.Value, LoopBackIPConfigType, nil
+}
+
+//getPinnedIPPair returns the P2P address pinned to either end of the link, and whether it is pinned to the first end
+func (sh *DeviceInteractor) getPinnedIPPair(DeviceOneID uint, DeviceTwoID uint, InterfaceOneName string,
+	InterfaceTwoName string) (domain.AllocationPin, bool, bool) {
+	if DeviceOne, err := sh.Db.GetDeviceUsingDeviceID(sh.FabricID, DeviceOneID); err == nil {
+		if Pin, found := sh.getAllocationPin(DeviceOne.IPAddress, domain.PinTypeP2P, InterfaceOneName); found {
+			return Pin, true, true
+		}
+	}
+	if DeviceTwo, err := sh.Db.GetDeviceUsingDeviceID(sh.FabricID, DeviceTwoID); err == nil {
+		if Pin, found := sh.getAllocationPin(DeviceTwo.IPAddress, domain.PinTypeP2P, InterfaceTwoName); found {
+			return Pin, false, true
+		}
+	}
+	return domain.AllocationPin{}, false, false
+}
+
+//reservePinnedIPPair allocates the pinned P2P address to its end of the link and the other address of the pair
+//to the other end, releasing the pair previously allocated to the link
+func (sh *DeviceInteractor) reservePinnedIPPair(ctx context.Context, DeviceOneID uint, DeviceTwoID uint,
+	InterfaceOneID uint, InterfaceTwoID uint, Pin domain.AllocationPin, PinnedOne bool) (string, string, error) {
+	LOG := appcontext.Logger(ctx)
+	PinnedValue, _ := ipToValue(Pin.Value)
+	IPOne, IPTwo := Pin.Value, valueToIP(PinnedValue^1)
+	if !PinnedOne {
+		IPOne, IPTwo = IPTwo, IPOne
+	}
+
+	if OldOne, OldTwo, err := sh.GetAlreadyAllocatedIPPair(ctx, sh.FabricID, DeviceOneID, DeviceTwoID, Pin.PoolName,
+		InterfaceOneID, InterfaceTwoID); err == nil {
+		if OldOne == IPOne && OldTwo == IPTwo {
+			return IPOne, IPTwo, nil
+		}
+		sh.ReleaseIPPair(ctx, sh.FabricID, DeviceOneID, DeviceTwoID, Pin.PoolName, OldOne, OldTwo, InterfaceOneID, InterfaceTwoID)
+	}
+	sh.removeFromPool(sh.FabricID, Pin.PoolType, Pin.PoolName, Pin.PoolValue, Pin.PoolValue)
+	usedEntry := domain.UsedIPPair{FabricID: sh.FabricID, DeviceOneID: DeviceOneID, DeviceTwoID: DeviceTwoID,
+		IPAddressOne: IPOne, IPAddressTwo: IPTwo, IPType: Pin.PoolName, InterfaceOneID: InterfaceOneID,
+		InterfaceTwoID: InterfaceTwoID}
+	if err := sh.Db.CreateUsedIPPairEntry(&usedEntry); err != nil {
+		return "", "", err
+	}
+	LOG.Infof("Allocated pinned IP %s %s", IPOne, IPTwo)
+	return IPOne, IPTwo, nil
+}
+
+//validateAllocationPins returns the conflicts of the values pinned to the devices of the fabric
+func (sh *DeviceInteractor) validateAllocationPins(ctx context.Context, FabricName string) []string {
+	LOG := appcontext.Logger(ctx)
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		return nil
+	}
+	Pins, err := sh.Db.GetAllocationPins(Fabric.ID)
+	if err != nil || len(Pins) == 0 {
+		return nil
+	}
+	FabricProperties, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		return nil
+	}
+	Holders, err := sh.getAllocationHolders(Fabric.ID)
+	if err != nil {
+		return nil
+	}
+
+	var Conflicts []string
+	for iter, Pin := range Pins {
+		//The fabric settings or the role of the device may have changed since the value was pinned
+		Resolved := Pin
+		if err := sh.resolvePinPool(FabricName, FabricProperties, &Resolved); err != nil {
+			Conflicts = append(Conflicts, fmt.Sprintf("%s %s of %s: %s", Pin.PinType, Pin.Value, pinTarget(Pin), err))
+			continue
+		}
+		//Each pair of pins is compared once
+		Conflicts = append(Conflicts, sh.pinConflicts(FabricName, Resolved, Pins[:iter], Holders)...)
+		if Pin.PinType == domain.PinTypeP2P {
+			if Conflict := sh.validatePinnedLink(FabricName, Pin, Pins, Holders); Conflict != "" {
+				Conflicts = append(Conflicts, Conflict)
+			}
+		}
+	}
+	for _, Conflict := range Conflicts {
+		LOG.Errorln(Conflict)
+	}
+	return Conflicts
+}
+
+//validatePinnedLink checks that the P2P addresses pinned to the two ends of a discovered link form a pair
+func (sh *DeviceInteractor) validatePinnedLink(FabricName string, Pin domain.AllocationPin, Pins []domain.AllocationPin,
+	Holders allocationHolders) string {
+	Device, err := sh.Db.GetDevice(FabricName, Pin.DeviceIP)
+	if err != nil {
+		return ""
+	}
+	Neighbors, _ := sh.Db.GetLLDPNeighborsOnDevice(Pin.FabricID, Device.ID)
+	for _, Neighbor := range Neighbors {
+		if Neighbor.InterfaceOneName != Pin.InterfaceName || Neighbor.ConfigType == domain.ConfigDelete {
+			continue
+		}
+		for _, Other := range Pins {
+			if Other.PinType == domain.PinTypeP2P && Other.DeviceIP == Holders.Devices[Neighbor.DeviceTwoID] &&
+				Other.InterfaceName == Neighbor.InterfaceTwoName &&
+				(Other.PoolValue != Pin.PoolValue || Other.Value == Pin.Value) {
+				return fmt.Sprintf("p2p %s of %s and p2p %s of its neighbor %s are not the two addresses of a /31",
+					Pin.Value, pinTarget(Pin), Other.Value, pinTarget(Other))
+			}
+		}
+	}
+	return ""
+}
+
+//mctPeerIPs returns the IP Addresses of the MCT peers of a registered device
+func (sh *DeviceInteractor) mctPeerIPs(FabricName string, DeviceIP string) []string {
+	Peers := make([]string, 0)
+	Device, err := sh.Db.GetDevice(FabricName, DeviceIP)
+	if err != nil {
+		return Peers
+	}
+	Clusters, _ := sh.Db.GetMctClusters(Device.FabricID, Device.ID, []string{})
+	for _, Cluster := range Clusters {
+		Peers = append(Peers, Cluster.DeviceTwoMgmtIP)
+	}
+	return Peers
+}
+
+//pinConfigType returns the config type needed to move the switch from its current value to the pinned one
+func pinConfigType(OnSwitchValue string, PinnedValue string) string {
+	switch OnSwitchValue {
+	case PinnedValue:
+		return domain.ConfigNone
+	case "":
+		return domain.ConfigCreate
+	}
+	return domain.ConfigUpdate
+}
+
+//pinTarget returns the device, or the device and interface, a value is pinned to
+func pinTarget(Pin domain.AllocationPin) string {
+	if Pin.InterfaceName != "" {
+		return fmt.Sprintf("%s interface %s", Pin.DeviceIP, Pin.InterfaceName)
+	}
+	return Pin.DeviceIP
+}
+
+func isSamePinTarget(First domain.AllocationPin, Second domain.AllocationPin) bool {
+	return First.DeviceIP == Second.DeviceIP && First.PinType == Second.PinType && First.InterfaceName == Second.InterfaceName
+}
+
+func isPinnedIn(Pins []domain.AllocationPin, Pin domain.AllocationPin) bool {
+	for _, Other := range Pins {
+		if Other.PoolType == Pin.PoolType && Other.PoolName == Pin.PoolName && Other.PoolValue == Pin.PoolValue {
+			return true
+		}
+	}
+	return false
+}
+
+//isPinnedInterface returns true if the allocation is held by the device and interface the P2P address is pinned to
+func isPinnedInterface(Allocation PoolAllocation, Pin domain.AllocationPin) bool {
+	return Allocation.IPAddress == Pin.DeviceIP && (Allocation.InterfaceName == Pin.InterfaceName ||
+		strings.HasSuffix(Allocation.InterfaceName, " "+Pin.InterfaceName))
+}
+
+func containsString(Values []string, Value string) bool {
+	for _, Each := range Values {
+		if Each == Value {
+			return true
+		}
+	}
+	return false
+}
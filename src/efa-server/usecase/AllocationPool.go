@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"efa-server/domain"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+//The allocation pools store the available values as ranges rather than one row per value.
+//ASN pools store the ASN, IP pools store the IPv4 address as a number and IP pair pools
+//store the index of the pair, the pair at index N being the addresses 2N and 2N+1.
+
+//valueRange is a range of values of an allocation pool, Start and End are both inclusive
+type valueRange struct {
+	Start uint64
+	End   uint64
+}
+
+//addToPool makes the values from Start to End available in the pool,
+//merging them with the ranges they overlap or touch
+func (sh *DeviceInteractor) addToPool(FabricID uint, PoolType string, PoolName string, Start uint64, End uint64) error {
+	WindowStart, WindowEnd := Start, End
+	if WindowStart > 0 {
+		WindowStart--
+	}
+	if WindowEnd < math.MaxUint64 {
+		WindowEnd++
+	}
+	Ranges, err := sh.Db.GetAllocationRangesOnWindow(FabricID, PoolType, PoolName, WindowStart, WindowEnd)
+	if err != nil {
+		return err
+	}
+	if len(Ranges) == 0 {
+		Range := domain.AllocationRange{FabricID: FabricID, PoolType: PoolType, PoolName: PoolName,
+			StartValue: Start, EndValue: End}
+		return sh.Db.CreateAllocationRange(&Range)
+	}
+
+	//Grow the first range over the others and drop them
+	Merged := Ranges[0]
+	if Start < Merged.StartValue {
+		Merged.StartValue = Start
+	}
+	for _, Range := range Ranges {
+		if Range.EndValue > Merged.EndValue {
+			Merged.EndValue = Range.EndValue
+		}
+	}
+	if End > Merged.EndValue {
+		Merged.EndValue = End
+	}
+	for iter := 1; iter < len(Ranges); iter++ {
+		if err := sh.Db.DeleteAllocationRange(&Ranges[iter]); err != nil {
+			return err
+		}
+	}
+	return sh.Db.UpdateAllocationRange(&Merged)
+}
+
+//removeFromPool makes the values from Start to End unavailable in the pool,
+//trimming or splitting the ranges they overlap
+func (sh *DeviceInteractor) removeFromPool(FabricID uint, PoolType string, PoolName string, Start uint64, End uint64) error {
+	Ranges, err := sh.Db.GetAllocationRangesOnWindow(FabricID, PoolType, PoolName, Start, End)
+	if err != nil {
+		return err
+	}
+	for _, Range := range Ranges {
+		switch {
+		case Range.StartValue >= Start && Range.EndValue <= End:
+			err = sh.Db.DeleteAllocationRange(&Range)
+		case Range.StartValue < Start && Range.EndValue > End:
+			Tail := domain.AllocationRange{FabricID: FabricID, PoolType: PoolType, PoolName: PoolName,
+				StartValue: End + 1, EndValue: Range.EndValue}
+			Range.EndValue = Start - 1
+			if err = sh.Db.UpdateAllocationRange(&Range); err == nil {
+				err = sh.Db.CreateAllocationRange(&Tail)
+			}
+		case Range.StartValue < Start:
+			Range.EndValue = Start - 1
+			err = sh.Db.UpdateAllocationRange(&Range)
+		default:
+			Range.StartValue = End + 1
+			err = sh.Db.UpdateAllocationRange(&Range)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//isInPool returns true if the value is available in the pool
+func (sh *DeviceInteractor) isInPool(FabricID uint, PoolType string, PoolName string, Value uint64) bool {
+	Ranges, err := sh.Db.GetAllocationRangesOnWindow(FabricID, PoolType, PoolName, Value, Value)
+	return err == nil && len(Ranges) != 0
+}
+
+//nextInPool returns the lowest value available in the pool
+func (sh *DeviceInteractor) nextInPool(FabricID uint, PoolType string, PoolName string) (uint64, error) {
+	Ranges, err := sh.Db.GetAllocationRanges(FabricID, PoolType, PoolName)
+	if err != nil {
+		return 0, err
+	}
+	if len(Ranges) == 0 {
+		return 0, fmt.Errorf("%s pool %s is exhausted", PoolType, PoolName)
+	}
+	return Ranges[0].StartValue, nil
+}
+
+//poolSize returns the number of values available in the pool
+func (sh *DeviceInteractor) poolSize(FabricID uint, PoolType string, PoolName string) (uint64, error) {
+	Ranges, err := sh.Db.GetAllocationRanges(FabricID, PoolType, PoolName)
+	if err != nil {
+		return 0, err
+	}
+	var Size uint64
+	for _, Range := range Ranges {
+		Size += Range.EndValue - Range.StartValue + 1
+	}
+	return Size, nil
+}
+
+//resizePool moves the pool from the Old to the New ranges, the values only in the Old ranges are removed
+//and the values only in the New ranges are added. The values in both are left as they are,
+//so the allocations and the available values in the overlap are kept.
+func (sh *DeviceInteractor) resizePool(FabricID uint, PoolType string, PoolName string, Old []valueRange, New []valueRange) error {
+	for _, Range := range subtractRanges(Old, New) {
+		if err := sh.removeFromPool(FabricID, PoolType, PoolName, Range.Start, Range.End); err != nil {
+			return err
+		}
+	}
+	for _, Range := range subtractRanges(New, Old) {
+		if err := sh.addToPool(FabricID, PoolType, PoolName, Range.Start, Range.End); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//subtractRanges returns the values of the ranges A which are not in the ranges B,
+//both A and B are sorted and have no overlapping ranges
+func subtractRanges(A []valueRange, B []valueRange) []valueRange {
+	Result := make([]valueRange, 0)
+	next := 0
+	for _, Range := range A {
+		Start := Range.Start
+		for next < len(B) && B[next].End < Start {
+			next++
+		}
+		covered := false
+		for iter := next; iter < len(B) && B[iter].Start <= Range.End; iter++ {
+			if B[iter].Start > Start {
+				Result = append(Result, valueRange{Start, B[iter].Start - 1})
+			}
+			if B[iter].End >= Range.End {
+				covered = true
+				break
+			}
+			Start = B[iter].End + 1
+		}
+		if !covered {
+			Result = append(Result, valueRange{Start, Range.End})
+		}
+	}
+	return Result
+}
+
+//ipToValue returns the IPv4 address as a pool value
+func ipToValue(IPAddress string) (uint64, error) {
+	ip := net.ParseIP(IPAddress).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("%s is not a valid IPv4 address", IPAddress)
+	}
+	return uint64(binary.BigEndian.Uint32(ip)), nil
+}
+
+//valueToIP returns the IPv4 address of a pool value
+func valueToIP(Value uint64) string {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, uint32(Value))
+	return ip.String()
+}
+
+//ipPairToValue returns the pair of IPv4 addresses, given in any order, as a pool value.
+//The addresses form a pair only when they are the 2N and 2N+1 addresses.
+func ipPairToValue(IPAddressOne string, IPAddressTwo string) (uint64, error) {
+	One, err := ipToValue(IPAddressOne)
+	if err != nil {
+		return 0, err
+	}
+	Two, err := ipToValue(IPAddressTwo)
+	if err != nil {
+		return 0, err
+	}
+	if Two < One {
+		One, Two = Two, One
+	}
+	if One%2 != 0 || Two != One+1 {
+		return 0, fmt.Errorf("%s and %s are not an IP address pair", IPAddressOne, IPAddressTwo)
+	}
+	return One / 2, nil
+}
+
+//valueToIPPair returns the pair of IPv4 addresses of a pool value
+func valueToIPPair(Value uint64) (string, string) {
+	return valueToIP(2 * Value), valueToIP(2*Value + 1)
+}
+
+//networkRanges returns the ranges of the IPv4 addresses of the network, leaving out the addresses
+//for which skipped returns true. The skipped last octets are expected at the start and the end of each /24.
+func networkRanges(network string, skipped func(lastOctet uint64) bool) ([]valueRange, error) {
+	_, ipnet, err := net.ParseCIDR(network)
+	if err != nil {
+		return nil, err
+	}
+	ip := ipnet.IP.To4()
+	if ip == nil {
+		return nil, errors.New("only IPv4 ranges are supported")
+	}
+	ones, bits := ipnet.Mask.Size()
+	Start := int64(binary.BigEndian.Uint32(ip))
+	End := Start + (int64(1) << uint(bits-ones)) - 1
+
+	Ranges := make([]valueRange, 0)
+	if skipped == nil {
+		return append(Ranges, valueRange{uint64(Start), uint64(End)}), nil
+	}
+	for block := Start &^ 0xff; block <= End; block += 256 {
+		low, high := block, block+255
+		if low < Start {
+			low = Start
+		}
+		if high > End {
+			high = End
+		}
+		for low <= high && skipped(uint64(low&0xff)) {
+			low++
+		}
+		for high >= low && skipped(uint64(high&0xff)) {
+			high--
+		}
+		if low <= high {
+			Ranges = append(Ranges, valueRange{uint64(low), uint64(high)})
+		}
+	}
+	return Ranges, nil
+}
+
+//pairRanges returns the ranges of the IP pairs formed by the consecutive addresses of the ranges
+func pairRanges(Ranges []valueRange) []valueRange {
+	Pairs := make([]valueRange, 0, len(Ranges))
+	for _, Range := range Ranges {
+		Start := Range.Start
+		if Start%2 != 0 {
+			Start++
+		}
+		if Start > Range.End || Range.End-Start+1 < 2 {
+			continue
+		}
+		Count := (Range.End - Start + 1) / 2
+		Pairs = append(Pairs, valueRange{Start / 2, Start/2 + Count - 1})
+	}
+	return Pairs
+}
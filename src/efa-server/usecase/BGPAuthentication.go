@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"sort"
+)
+
+//bgpRotationOrder is the order in which the roles are re-keyed during BGP password rotation
+var bgpRotationOrder = map[string]int{SpineRole: 0, LeafRole: 1, RackRole: 2}
+
+//RotateBGPPasswords updates the BGP authentication settings of the fabric and re-keys the BGP sessions
+//of the configured devices. Devices are re-keyed one at a time, spines before leaves, so that a session
+//never has both sides changing at once. Requesting the current passwords again re-pushes them, which
+//resumes a rotation that failed part way
+func (sh *DeviceInteractor) RotateBGPPasswords(ctx context.Context, FabricName string,
+	BGPAuthRequest *domain.FabricProperties) (string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Rotate BGP Passwords")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	var Fabric domain.Fabric
+	var FabricProperties domain.FabricProperties
+	var err error
+
+	if Fabric, err = sh.Db.GetFabric(FabricName); err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricNotFound
+	}
+	if FabricProperties, err = sh.Db.GetFabricProperties(Fabric.ID); err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric Properties for %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+
+	var NewFabricProperties = FabricProperties
+	modifyUpdatedBGPAuthFields(&NewFabricProperties, BGPAuthRequest)
+	DeviceCount := sh.Db.GetDevicesCountInFabric(Fabric.ID)
+	if NewFabricProperties == FabricProperties && DeviceCount == 0 {
+		statusMsg := fmt.Sprintf("No BGP Authentication Update is Requested For Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricIncorrectValues
+	}
+
+	if NewFabricProperties != FabricProperties {
+		if err = sh.saveBGPAuthentication(ctx, FabricProperties, &NewFabricProperties); err != nil {
+			statusMsg := fmt.Sprintf("Failed to save BGP Authentication for %s", FabricName)
+			LOG.Errorln(statusMsg, err)
+			return statusMsg, domain.ErrFabricInternalError
+		}
+	}
+	if DeviceCount == 0 {
+		return "BGP Authentication updated", nil
+	}
+
+	if statusMsg, err := sh.rotateFabricBGPPasswords(ctx, FabricName); err != nil {
+		return statusMsg, err
+	}
+	return "BGP Authentication updated", nil
+}
+
+//rotateFabricBGPPasswords re-keys the BGP sessions of the configured devices with the passwords of the fabric settings
+func (sh *DeviceInteractor) rotateFabricBGPPasswords(ctx context.Context, FabricName string) (string, error) {
+	LOG := appcontext.Logger(ctx)
+	config, err := sh.GetActionRequestObject(ctx, FabricName, false)
+	if err != nil {
+		return err.Error(), domain.ErrFabricInternalError
+	}
+	sh.prepareBGPRotation(ctx, &config)
+
+	if Errors := sh.FabricAdapter.RotateBGPPasswords(ctx, config); len(Errors) != 0 {
+		statusMsg := fmt.Sprintf("BGP password rotation stopped at %s: %s. Retry the rotation once the device is reachable",
+			Errors[0].Host, Errors[0].Error)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+	return "", nil
+}
+
+func (sh *DeviceInteractor) saveBGPAuthentication(ctx context.Context, OldFabricProperties domain.FabricProperties,
+	FabricProperties *domain.FabricProperties) error {
+	RollBack := true
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return err
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	if err := sh.Db.UpdateFabricProperties(FabricProperties); err != nil {
+		return err
+	}
+	if err := sh.recordSettingChanges(ctx, OldFabricProperties.FabricID, OldFabricProperties, *FabricProperties); err != nil {
+		return err
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return nil
+}
+
+//prepareBGPRotation orders the switches for the rotation and collects the neighbours towards the MCT peers
+func (sh *DeviceInteractor) prepareBGPRotation(ctx context.Context, config *operation.ConfigFabricRequest) {
+	sort.SliceStable(config.Hosts, func(i, j int) bool {
+		return bgpRotationOrder[config.Hosts[i].Role] < bgpRotationOrder[config.Hosts[j].Role]
+	})
+
+	for iter := range config.Hosts {
+		sh.populateMctPeerAddresses(ctx, config.FabricName, &config.Hosts[iter])
+	}
+}
+
+//populateMctPeerAddresses collects the BGP neighbours of the switch towards its MCT peer
+func (sh *DeviceInteractor) populateMctPeerAddresses(ctx context.Context, FabricName string, host *operation.ConfigSwitch) {
+	Visited := make(map[string]bool)
+	addPeer := func(Address string) {
+		if len(Address) != 0 && !Visited[Address] {
+			Visited[Address] = true
+			host.MctPeerAddresses = append(host.MctPeerAddresses, Address)
+		}
+	}
+	if Device, err := sh.Db.GetDevice(FabricName, host.Host); err == nil {
+		MctNeighbors, _ := sh.GetMCTBGPSwitchConfigs(ctx, sh.FabricID, Device.ID)
+		for _, neigh := range MctNeighbors {
+			if neigh.ConfigType != domain.ConfigDelete {
+				addPeer(neigh.RemoteIPAddress)
+			}
+		}
+	}
+	for _, neigh := range host.BgpNeighbors {
+		if neigh.NeighborType == domain.MCTL3LBType && neigh.ConfigType != domain.ConfigDelete {
+			addPeer(neigh.NeighborAddress)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//cablingPortPrefix matches the interface type written before the port name of an ethernet interface,
+//such as "Ethernet 0/1" or "eth0/1"
+var cablingPortPrefix = regexp.MustCompile(`(?i)^(ethernet|eth)\s*`)
+
+//cablingPort is a port of a device in the cabling plan, or discovered by LLDP
+type cablingPort struct {
+	DeviceIP      string
+	InterfaceName string
+}
+
+func (Port cablingPort) String() string {
+	return Port.DeviceIP + " " + Port.InterfaceName
+}
+
+//UpdateCablingPlan replaces the cabling plan of the fabric. The links discovered by LLDP are validated
+//against the plan, an empty plan removes it.
+func (sh *DeviceInteractor) UpdateCablingPlan(ctx context.Context, FabricName string,
+	Links []domain.CablingLink) ([]domain.CablingLink, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Update Cabling Plan")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Links, statusMsg, domain.ErrFabricNotFound
+	}
+	Links, statusMsg := normalizeCablingPlan(Links)
+	if len(statusMsg) != 0 {
+		LOG.Errorln(statusMsg)
+		return Links, statusMsg, domain.ErrCablingPlanInvalid
+	}
+
+	RollBack := true
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return Links, "Unable to update the cabling plan", domain.ErrFabricInternalError
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	if err := sh.Db.DeleteCablingPlan(Fabric.ID); err != nil {
+		statusMsg := fmt.Sprintf("Unable to delete the cabling plan of Fabric %s", FabricName)
+		LOG.Errorln(statusMsg, err)
+		return Links, statusMsg, domain.ErrFabricInternalError
+	}
+	for iter := range Links {
+		Links[iter].FabricID = Fabric.ID
+		if err := sh.Db.CreateCablingLink(&Links[iter]); err != nil {
+			statusMsg := fmt.Sprintf("Unable to save the cabling plan of Fabric %s", FabricName)
+			LOG.Errorln(statusMsg, err)
+			return Links, statusMsg, domain.ErrFabricInternalError
+		}
+	}
+	RollBack = false
+	if len(Links) == 0 {
+		return Links, fmt.Sprintf("Cabling plan of Fabric %s deleted", FabricName), nil
+	}
+	return Links, fmt.Sprintf("Cabling plan of Fabric %s updated with %d links", FabricName, len(Links)), nil
+}
+
+//GetCablingPlan returns the cabling plan of the fabric
+func (sh *DeviceInteractor) GetCablingPlan(ctx context.Context, FabricName string) ([]domain.CablingLink, error) {
+	LOG := appcontext.Logger(ctx)
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		LOG.Printf("Unable to retrieve Fabric for %s", FabricName)
+		return []domain.CablingLink{}, domain.ErrFabricNotFound
+	}
+	return sh.Db.GetCablingPlan(Fabric.ID)
+}
+
+//normalizeCablingPlan strips the interface type from the port names and returns why the plan is invalid,
+//empty when it is valid. A port is cabled to a single port.
+func normalizeCablingPlan(Links []domain.CablingLink) ([]domain.CablingLink, string) {
+	Cabled := make(map[cablingPort]bool)
+	for iter := range Links {
+		Link := &Links[iter]
+		Link.DeviceOneIP, Link.DeviceTwoIP = strings.TrimSpace(Link.DeviceOneIP), strings.TrimSpace(Link.DeviceTwoIP)
+		Link.InterfaceOneName = normalizeCablingPort(Link.InterfaceOneName)
+		Link.InterfaceTwoName = normalizeCablingPort(Link.InterfaceTwoName)
+		for _, DeviceIP := range []string{Link.DeviceOneIP, Link.DeviceTwoIP} {
+			if net.ParseIP(DeviceIP).To4() == nil {
+				return Links, fmt.Sprintf("Link %d: %q is not a valid Device IP Address", iter+1, DeviceIP)
+			}
+		}
+		if Link.InterfaceOneName == "" || Link.InterfaceTwoName == "" {
+			return Links, fmt.Sprintf("Link %d: the interfaces of both the devices are required", iter+1)
+		}
+		if Link.DeviceOneIP == Link.DeviceTwoIP {
+			return Links, fmt.Sprintf("Link %d: Device %s is cabled to itself", iter+1, Link.DeviceOneIP)
+		}
+		for _, Port := range []cablingPort{{Link.DeviceOneIP, Link.InterfaceOneName},
+			{Link.DeviceTwoIP, Link.InterfaceTwoName}} {
+			if Cabled[Port] {
+				return Links, fmt.Sprintf("Link %d: %s is cabled more than once", iter+1, Port)
+			}
+			Cabled[Port] = true
+		}
+	}
+	return Links, ""
+}
+
+func normalizeCablingPort(InterfaceName string) string {
+	return cablingPortPrefix.ReplaceAllString(strings.TrimSpace(InterfaceName), "")
+}
+
+//validateCablingPlan compares the links discovered by LLDP with the cabling plan of the fabric.
+//A fabric without a cabling plan has no mismatch.
+func (sh *DeviceInteractor) validateCablingPlan(ctx context.Context, FabricName string) (domain.CablingMismatch, error) {
+	var Mismatch domain.CablingMismatch
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		return Mismatch, domain.ErrFabricNotFound
+	}
+	Plan, err := sh.Db.GetCablingPlan(Fabric.ID)
+	if err != nil || len(Plan) == 0 {
+		return Mismatch, err
+	}
+	Planned := make(map[cablingPort]cablingPort, 2*len(Plan))
+	for _, Link := range Plan {
+		One := cablingPort{Link.DeviceOneIP, Link.InterfaceOneName}
+		Two := cablingPort{Link.DeviceTwoIP, Link.InterfaceTwoName}
+		Planned[One], Planned[Two] = Two, One
+	}
+
+	Discovered, err := sh.discoveredLinks(Fabric.ID)
+	if err != nil {
+		return Mismatch, err
+	}
+	Connected := make(map[cablingPort]bool)
+	for _, Link := range Discovered {
+		One, Two := Link[0], Link[1]
+		Connected[One], Connected[Two] = true, true
+		PlannedOne, OnePlanned := Planned[One]
+		PlannedTwo, TwoPlanned := Planned[Two]
+		switch {
+		case OnePlanned && PlannedOne == Two:
+		case OnePlanned:
+			Mismatch.Miscabled = append(Mismatch.Miscabled,
+				fmt.Sprintf("%s is connected to %s, planned to %s", One, Two, PlannedOne))
+			if TwoPlanned {
+				Mismatch.Miscabled = append(Mismatch.Miscabled,
+					fmt.Sprintf("%s is connected to %s, planned to %s", Two, One, PlannedTwo))
+			}
+		case TwoPlanned:
+			Mismatch.Miscabled = append(Mismatch.Miscabled,
+				fmt.Sprintf("%s is connected to %s, planned to %s", Two, One, PlannedTwo))
+		default:
+			Mismatch.Unexpected = append(Mismatch.Unexpected, fmt.Sprintf("%s to %s is not in the cabling plan", One, Two))
+		}
+	}
+	//A planned link with a port connected elsewhere is already reported as miscabled
+	for _, Link := range Plan {
+		One := cablingPort{Link.DeviceOneIP, Link.InterfaceOneName}
+		Two := cablingPort{Link.DeviceTwoIP, Link.InterfaceTwoName}
+		if !Connected[One] && !Connected[Two] {
+			Mismatch.Missing = append(Mismatch.Missing, fmt.Sprintf("%s to %s is not connected", One, Two))
+		}
+	}
+	sort.Strings(Mismatch.Miscabled)
+	sort.Strings(Mismatch.Unexpected)
+	return Mismatch, nil
+}
+
+//discoveredLinks returns each link discovered by LLDP between the devices of the fabric once,
+//the links marked for deletion excluded
+func (sh *DeviceInteractor) discoveredLinks(FabricID uint) ([][2]cablingPort, error) {
+	Devices, err := sh.Db.GetDevicesInFabric(FabricID)
+	if err != nil {
+		return nil, err
+	}
+	DeviceIPs := make(map[uint]string, len(Devices))
+	for _, Device := range Devices {
+		DeviceIPs[Device.ID] = Device.IPAddress
+	}
+	Links := make([][2]cablingPort, 0)
+	Seen := make(map[[2]cablingPort]bool)
+	for _, Device := range Devices {
+		Neighbors, err := sh.Db.GetLLDPNeighborsOnDeviceExcludingMarkedForDeletion(FabricID, Device.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, Neighbor := range Neighbors {
+			Link := [2]cablingPort{{DeviceIPs[Neighbor.DeviceOneID], Neighbor.InterfaceOneName},
+				{DeviceIPs[Neighbor.DeviceTwoID], Neighbor.InterfaceTwoName}}
+			if Seen[Link] || Seen[[2]cablingPort{Link[1], Link[0]}] {
+				continue
+			}
+			Seen[Link] = true
+			Links = append(Links, Link)
+		}
+	}
+	return Links, nil
+}
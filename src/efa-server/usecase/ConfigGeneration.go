@@ -0,0 +1,869 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"sort"
+	"strconv"
+)
+
+//p2pPoolName and loopbackPoolName are the pools of the link and the loopback addresses
+const (
+	p2pPoolName      = "P2P"
+	loopbackPoolName = "Loopback"
+)
+
+//RevertFabricResponse describes the changes made by a revert to a configuration generation
+type RevertFabricResponse struct {
+	FabricName string
+	Generation uint
+	Changes    []domain.ConfigChange
+	Configure  ConfigureFabricResponse
+}
+
+//recordConfigGeneration stores the intended configuration of the fabric as a new generation,
+//linked to the execution of the configure
+func (sh *DeviceInteractor) recordConfigGeneration(ctx context.Context) (domain.ConfigGeneration, error) {
+	Generation := domain.ConfigGeneration{FabricID: sh.FabricID}
+	if ExecutionID, ok := ctx.Value(appcontext.RequestIDKey).(string); ok {
+		Generation.ExecutionID = ExecutionID
+	}
+	Intended, err := sh.intendedConfig()
+	if err != nil {
+		return Generation, err
+	}
+	Generation.Settings, Generation.Devices = Intended.Settings, Intended.Devices
+	return Generation, sh.Db.CreateConfigGeneration(&Generation)
+}
+
+//intendedConfig returns the intended configuration of the fabric: the fabric settings and the configuration
+//of the devices along with their settings, MCT clusters and EVPN neighbors. The configuration marked for
+//deletion is left out.
+func (sh *DeviceInteractor) intendedConfig() (domain.ConfigGeneration, error) {
+	var Intended domain.ConfigGeneration
+	Settings, err := sh.Db.GetFabricProperties(sh.FabricID)
+	if err != nil {
+		return Intended, err
+	}
+	Settings.ID, Settings.FabricID = 0, 0
+	Intended.Settings = &Settings
+
+	SwitchConfigs, err := sh.Db.GetSwitchConfigs(sh.FabricName)
+	if err != nil {
+		return Intended, err
+	}
+	Devices := make([]domain.DeviceIntendedConfig, 0, len(SwitchConfigs))
+	for _, SwitchConfig := range SwitchConfigs {
+		if SwitchConfig.FabricID != sh.FabricID {
+			continue
+		}
+		Device := domain.DeviceIntendedConfig{DeviceID: SwitchConfig.DeviceID, DeviceIP: SwitchConfig.DeviceIP,
+			Role: SwitchConfig.Role, LocalAS: SwitchConfig.LocalAS, LoopbackIP: SwitchConfig.LoopbackIP,
+			VTEPLoopbackIP: SwitchConfig.VTEPLoopbackIP}
+
+		Interfaces, err := sh.Db.GetInterfaceSwitchConfigsOnDeviceID(sh.FabricID, SwitchConfig.DeviceID)
+		if err != nil {
+			return Intended, err
+		}
+		Device.Interfaces = make([]domain.InterfaceSwitchConfig, 0, len(Interfaces))
+		for _, Interface := range Interfaces {
+			if Interface.ConfigType != domain.ConfigDelete {
+				Interface.ID, Interface.ConfigType = 0, ""
+				Device.Interfaces = append(Device.Interfaces, Interface)
+			}
+		}
+		sort.Slice(Device.Interfaces, func(i, j int) bool {
+			return interfaceKey(Device.Interfaces[i]) < interfaceKey(Device.Interfaces[j])
+		})
+
+		Neighbors, err := sh.Db.GetBGPSwitchConfigsOnDeviceID(sh.FabricID, SwitchConfig.DeviceID)
+		if err != nil {
+			return Intended, err
+		}
+		MCTNeighbors, err := sh.Db.GetMCTBGPSwitchConfigsOnDeviceID(sh.FabricID, SwitchConfig.DeviceID)
+		if err != nil {
+			return Intended, err
+		}
+		Device.Neighbors = make([]domain.RemoteNeighborSwitchConfig, 0, len(Neighbors)+len(MCTNeighbors))
+		for _, Neighbor := range append(Neighbors, MCTNeighbors...) {
+			if Neighbor.ConfigType != domain.ConfigDelete {
+				Neighbor.ID, Neighbor.ConfigType = 0, ""
+				Device.Neighbors = append(Device.Neighbors, Neighbor)
+			}
+		}
+		sort.Slice(Device.Neighbors, func(i, j int) bool {
+			return neighborKey(Device.Neighbors[i]) < neighborKey(Device.Neighbors[j])
+		})
+
+		//No overrides are stored for a device using the fabric settings
+		Device.Settings, _ = sh.Db.GetDeviceSettings(sh.FabricID, SwitchConfig.DeviceID)
+		Device.Settings.ID, Device.Settings.FabricID, Device.Settings.DeviceID = 0, 0, 0
+
+		Clusters, err := sh.Db.GetMctClusters(sh.FabricID, SwitchConfig.DeviceID,
+			[]string{domain.ConfigCreate, domain.ConfigUpdate, domain.ConfigNone})
+		if err != nil {
+			return Intended, err
+		}
+		Device.Clusters = make([]domain.MctClusterConfig, 0, len(Clusters))
+		for _, Cluster := range Clusters {
+			Cluster.ID, Cluster.ConfigType, Cluster.UpdatedAttributes = 0, "", 0
+			Device.Clusters = append(Device.Clusters, Cluster)
+		}
+		sort.Slice(Device.Clusters, func(i, j int) bool { return Device.Clusters[i].ClusterID < Device.Clusters[j].ClusterID })
+
+		EVPNNeighbors, err := sh.Db.GetRackEvpnConfigOnDeviceID(SwitchConfig.DeviceID)
+		if err != nil {
+			return Intended, err
+		}
+		Device.EVPNNeighbors = make([]domain.RackEvpnNeighbors, 0, len(EVPNNeighbors))
+		for _, Neighbor := range EVPNNeighbors {
+			if Neighbor.ConfigType != domain.ConfigDelete {
+				Neighbor.ID, Neighbor.ConfigType = 0, ""
+				Device.EVPNNeighbors = append(Device.EVPNNeighbors, Neighbor)
+			}
+		}
+		sort.Slice(Device.EVPNNeighbors, func(i, j int) bool {
+			return Device.EVPNNeighbors[i].EVPNAddress < Device.EVPNNeighbors[j].EVPNAddress
+		})
+		Devices = append(Devices, Device)
+	}
+	sort.Slice(Devices, func(i, j int) bool { return Devices[i].DeviceIP < Devices[j].DeviceIP })
+	Intended.Devices = Devices
+	return Intended, nil
+}
+
+//GetConfigGenerations returns the configuration generations of the fabric, the most recent first
+func (sh *DeviceInteractor) GetConfigGenerations(ctx context.Context, FabricName string) ([]domain.ConfigGeneration, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Fabric History")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return nil, statusMsg, domain.ErrFabricNotFound
+	}
+	Generations, err := sh.Db.GetConfigGenerations(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the configuration generations of %s", FabricName)
+		LOG.Errorln(statusMsg, err)
+		return nil, statusMsg, domain.ErrFabricInternalError
+	}
+	return Generations, "", nil
+}
+
+//DiffConfigGenerations returns the changes of the intended configuration from generation From to generation To
+func (sh *DeviceInteractor) DiffConfigGenerations(ctx context.Context, FabricName string, From uint, To uint) ([]domain.ConfigChange, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Fabric Diff")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return nil, statusMsg, domain.ErrFabricNotFound
+	}
+	Generations := make([]domain.ConfigGeneration, 0, 2)
+	for _, Number := range []uint{From, To} {
+		Generation, statusMsg, err := sh.getConfigGeneration(Fabric, Number)
+		if err != nil {
+			LOG.Errorln(statusMsg)
+			return nil, statusMsg, err
+		}
+		Generations = append(Generations, Generation)
+	}
+	return diffIntendedConfig(Generations[0], Generations[1]), "", nil
+}
+
+func (sh *DeviceInteractor) getConfigGeneration(Fabric domain.Fabric, Number uint) (domain.ConfigGeneration, string, error) {
+	Generation, err := sh.Db.GetConfigGeneration(Fabric.ID, Number)
+	if err == gorm.ErrRecordNotFound {
+		return Generation, fmt.Sprintf("Generation %d of fabric %s does not exist", Number, Fabric.Name),
+			domain.ErrGenerationNotFound
+	}
+	if err != nil {
+		return Generation, fmt.Sprintf("Unable to retrieve generation %d of fabric %s: %s", Number, Fabric.Name, err),
+			domain.ErrFabricInternalError
+	}
+	return Generation, "", nil
+}
+
+//RevertFabric moves the intended configuration of the fabric back to a generation and configures the switches
+//with the changes. The fabric settings, the device settings and the ASN, the loopback and the link addresses of
+//the generation are restored, the values are taken back from the pools. The devices and the links added since
+//the generation are kept, their configuration along with the MCT clusters and the overlay is computed again by
+//the configure.
+func (sh *DeviceInteractor) RevertFabric(ctx context.Context, FabricName string, Number uint, persist bool) (RevertFabricResponse, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Fabric Revert")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+	response := RevertFabricResponse{FabricName: FabricName, Generation: Number}
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return response, statusMsg, domain.ErrFabricNotFound
+	}
+	Generation, statusMsg, err := sh.getConfigGeneration(Fabric, Number)
+	if err != nil {
+		LOG.Errorln(statusMsg)
+		return response, statusMsg, err
+	}
+	sh.FabricID = Fabric.ID
+	sh.FabricName = FabricName
+	if sh.FabricProperties, err = sh.Db.GetFabricProperties(Fabric.ID); err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric Properties for %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return response, statusMsg, domain.ErrFabricInternalError
+	}
+	Current, err := sh.intendedConfig()
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the configuration of %s", FabricName)
+		LOG.Errorln(statusMsg, err)
+		return response, statusMsg, domain.ErrFabricInternalError
+	}
+
+	Target := revertTarget(Current, Generation)
+	response.Changes = diffIntendedConfig(Current, Target)
+	if len(response.Changes) == 0 {
+		return response, fmt.Sprintf("Fabric %s is already at generation %d", FabricName, Number), nil
+	}
+	if statusMsg := revertableChanges(Current.Devices, Target); statusMsg != "" {
+		LOG.Errorln(statusMsg)
+		return response, statusMsg, domain.ErrFabricIncorrectValues
+	}
+
+	if statusMsg, err := sh.applyConfigGeneration(ctx, Current, Target); err != nil {
+		LOG.Errorln(statusMsg, err)
+		return response, statusMsg, err
+	}
+
+	//The switches are configured with the changes, which records the configuration as the next generation
+	if response.Configure, err = sh.ConfigureFabric(ctx, FabricName, false, persist); err != nil {
+		statusMsg := fmt.Sprintf("Configuration of generation %d failed, configure the fabric to retry", Number)
+		LOG.Errorln(statusMsg, err)
+		return response, statusMsg, domain.ErrFabricInternalError
+	}
+
+	//The passwords of the configured BGP sessions are changed by a rotation, as for a BGP authentication update
+	if !sameBGPAuthentication(*Current.Settings, *Target.Settings) {
+		if statusMsg, err := sh.rotateFabricBGPPasswords(ctx, FabricName); err != nil {
+			return response, statusMsg, err
+		}
+	}
+	return response, fmt.Sprintf("Fabric %s reverted to generation %d", FabricName, Number), nil
+}
+
+//revertTarget returns the intended configuration a revert to the generation moves the fabric to: the values of
+//the generation on the settings and on the devices, interfaces and BGP neighbors still in the fabric, the current
+//configuration elsewhere. A device registered again since the generation is not reverted.
+func revertTarget(Current domain.ConfigGeneration, Generation domain.ConfigGeneration) domain.ConfigGeneration {
+	Target := domain.ConfigGeneration{FabricID: Current.FabricID, Generation: Generation.Generation,
+		Settings: Current.Settings}
+	//The generations recorded without the settings revert the values of the devices only
+	if Generation.Settings != nil {
+		Target.Settings = Generation.Settings
+	}
+	GenerationDevices := make(map[uint]domain.DeviceIntendedConfig)
+	for _, Device := range Generation.Devices {
+		GenerationDevices[Device.DeviceID] = Device
+	}
+
+	Target.Devices = make([]domain.DeviceIntendedConfig, 0, len(Current.Devices))
+	for _, Device := range Current.Devices {
+		Reverted, found := GenerationDevices[Device.DeviceID]
+		if !found {
+			Target.Devices = append(Target.Devices, Device)
+			continue
+		}
+		Device.LocalAS, Device.LoopbackIP, Device.VTEPLoopbackIP = Reverted.LocalAS, Reverted.LoopbackIP, Reverted.VTEPLoopbackIP
+		if Generation.Settings != nil {
+			Device.Settings = Reverted.Settings
+		}
+
+		RevertedInterfaces := make(map[string]domain.InterfaceSwitchConfig)
+		for _, Interface := range Reverted.Interfaces {
+			RevertedInterfaces[interfaceKey(Interface)] = Interface
+		}
+		Interfaces := make([]domain.InterfaceSwitchConfig, 0, len(Device.Interfaces))
+		for _, Interface := range Device.Interfaces {
+			if RevertedInterface, found := RevertedInterfaces[interfaceKey(Interface)]; found {
+				Interface = RevertedInterface
+			}
+			Interfaces = append(Interfaces, Interface)
+		}
+		Device.Interfaces = Interfaces
+
+		RevertedNeighbors := make(map[string]domain.RemoteNeighborSwitchConfig)
+		for _, Neighbor := range Reverted.Neighbors {
+			RevertedNeighbors[neighborKey(Neighbor)] = Neighbor
+		}
+		Neighbors := make([]domain.RemoteNeighborSwitchConfig, 0, len(Device.Neighbors))
+		for _, Neighbor := range Device.Neighbors {
+			if RevertedNeighbor, found := RevertedNeighbors[neighborKey(Neighbor)]; found {
+				Neighbor = RevertedNeighbor
+			}
+			Neighbors = append(Neighbors, Neighbor)
+		}
+		Device.Neighbors = Neighbors
+		Target.Devices = append(Target.Devices, Device)
+	}
+	return Target
+}
+
+//revertableChanges returns why the fabric can not be reverted to the target configuration
+func revertableChanges(Current []domain.DeviceIntendedConfig, Target domain.ConfigGeneration) string {
+	CurrentDevices := make(map[uint]domain.DeviceIntendedConfig)
+	for _, Device := range Current {
+		CurrentDevices[Device.DeviceID] = Device
+	}
+	for _, Device := range Target.Devices {
+		//The MCT neighbor addresses come from the MCT pool, which is not reverted
+		for _, Neighbor := range Device.Neighbors {
+			if Neighbor.EncapsulationType != domain.BGPEncapTypeForCluster {
+				continue
+			}
+			for _, CurrentNeighbor := range CurrentDevices[Device.DeviceID].Neighbors {
+				if neighborKey(CurrentNeighbor) == neighborKey(Neighbor) && !sameNeighbor(CurrentNeighbor, Neighbor) {
+					return fmt.Sprintf("MCT neighbor %s of device %s changed since generation %d and can not be reverted",
+						Neighbor.RemoteIPAddress, Device.DeviceIP, Target.Generation)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+//sameBGPAuthentication tells whether the BGP authentication settings are the same
+func sameBGPAuthentication(First domain.FabricProperties, Second domain.FabricProperties) bool {
+	return First.BGPAuthType == Second.BGPAuthType && First.PeerGroupPassword == Second.PeerGroupPassword &&
+		First.MctL2EvpnPassword == Second.MctL2EvpnPassword &&
+		First.RackPeerEBGPGroupPassword == Second.RackPeerEBGPGroupPassword &&
+		First.RackPeerOvgGroupPassword == Second.RackPeerOvgGroupPassword
+}
+
+//applyConfigGeneration saves the target configuration of a revert, marked to be pushed to the switches.
+//The values of the current configuration are released to the pools before the values of the target
+//are reserved, so that values swapped between devices can be reverted.
+func (sh *DeviceInteractor) applyConfigGeneration(ctx context.Context, Current domain.ConfigGeneration,
+	Target domain.ConfigGeneration) (string, error) {
+	RollBack := true
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return "Unable to start the revert", domain.ErrFabricInternalError
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	CurrentDevices := make(map[uint]domain.DeviceIntendedConfig)
+	for _, Device := range Current.Devices {
+		CurrentDevices[Device.DeviceID] = Device
+	}
+	Links, err := sh.revertedLinks(Current.Devices, Target.Devices)
+	if err != nil {
+		return "Unable to retrieve the links of the fabric", domain.ErrFabricInternalError
+	}
+
+	//Release the values of the current configuration
+	for _, Device := range Target.Devices {
+		if statusMsg, err := sh.releaseDeviceValues(ctx, CurrentDevices[Device.DeviceID], Device); err != nil {
+			return statusMsg, err
+		}
+	}
+	for _, Link := range Links {
+		if Link.Current[0].DonorType == "" {
+			sh.ReleaseIPPair(ctx, sh.FabricID, Link.Neighbor.DeviceOneID, Link.Neighbor.DeviceTwoID, p2pPoolName,
+				Link.Current[0].IPAddress, Link.Current[1].IPAddress, Link.Neighbor.InterfaceOneID, Link.Neighbor.InterfaceTwoID)
+		}
+	}
+
+	//Restore the fabric settings, the pools are resized to the ranges of the generation
+	if statusMsg, err := sh.restoreFabricSettings(ctx, *Target.Settings, Target.Generation); err != nil {
+		return statusMsg, err
+	}
+
+	//Reserve the values of the generation
+	VTEPLoopbacks := make(map[string]bool)
+	for _, Device := range Target.Devices {
+		if statusMsg, err := sh.reserveDeviceValues(ctx, CurrentDevices[Device.DeviceID], Device, VTEPLoopbacks); err != nil {
+			return statusMsg, err
+		}
+	}
+	for _, Link := range Links {
+		if Link.Reverted[0].DonorType == "" {
+			if err := sh.ReserveIPPair(ctx, sh.FabricID, Link.Neighbor.DeviceOneID, Link.Neighbor.DeviceTwoID, p2pPoolName,
+				Link.Reverted[0].IPAddress, Link.Reverted[1].IPAddress, Link.Neighbor.InterfaceOneID, Link.Neighbor.InterfaceTwoID); err != nil {
+				return fmt.Sprintf("Link addresses %s and %s of generation %d are no longer available in the P2P pool",
+					Link.Reverted[0].IPAddress, Link.Reverted[1].IPAddress, Target.Generation), domain.ErrFabricIncorrectValues
+			}
+		}
+		Link.Neighbor.InterfaceOneIP, Link.Neighbor.InterfaceTwoIP = Link.Reverted[0].IPAddress, Link.Reverted[1].IPAddress
+		if err := sh.Db.CreateLLDPNeighbor(&Link.Neighbor); err != nil {
+			return "Unable to save the link addresses", domain.ErrFabricInternalError
+		}
+	}
+
+	for _, Device := range Target.Devices {
+		if statusMsg, err := sh.saveRevertedConfig(CurrentDevices[Device.DeviceID], Device); err != nil {
+			return statusMsg, err
+		}
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return "", nil
+}
+
+//restoreFabricSettings saves the fabric settings of a generation, the change is recorded in the settings history
+func (sh *DeviceInteractor) restoreFabricSettings(ctx context.Context, Settings domain.FabricProperties,
+	Number uint) (string, error) {
+	Settings.ID, Settings.FabricID = sh.FabricProperties.ID, sh.FabricID
+	if Settings == sh.FabricProperties {
+		return "", nil
+	}
+	if err := sh.resizeASNPool(ctx, sh.FabricName, sh.FabricID, sh.FabricProperties, Settings); err != nil {
+		return fmt.Sprintf("Unable to restore the ASN blocks of generation %d: %s", Number, err.Error()),
+			domain.ErrFabricIncorrectValues
+	}
+	if err := sh.resizeIPPool(ctx, sh.FabricName, sh.FabricID, sh.FabricProperties, Settings); err != nil {
+		return fmt.Sprintf("Unable to restore the IP ranges of generation %d: %s", Number, err.Error()),
+			domain.ErrFabricIncorrectValues
+	}
+	if err := sh.Db.UpdateFabricProperties(&Settings); err != nil {
+		return fmt.Sprintf("Unable to restore the settings of generation %d", Number), domain.ErrFabricInternalError
+	}
+	if err := sh.recordSettingChanges(ctx, sh.FabricID, sh.FabricProperties, Settings); err != nil {
+		return fmt.Sprintf("Unable to record the settings of generation %d", Number), domain.ErrFabricInternalError
+	}
+	sh.FabricProperties = Settings
+	return "", nil
+}
+
+//revertedLink is a numbered or unnumbered link whose addresses differ in the generation
+type revertedLink struct {
+	Neighbor domain.LLDPNeighbor
+	Current  [2]domain.InterfaceSwitchConfig
+	Reverted [2]domain.InterfaceSwitchConfig
+}
+
+func (sh *DeviceInteractor) revertedLinks(Current []domain.DeviceIntendedConfig, Reverted []domain.DeviceIntendedConfig) ([]revertedLink, error) {
+	interfaceMap := func(Devices []domain.DeviceIntendedConfig) map[uint]domain.InterfaceSwitchConfig {
+		Interfaces := make(map[uint]domain.InterfaceSwitchConfig)
+		for _, Device := range Devices {
+			for _, Interface := range Device.Interfaces {
+				Interfaces[Interface.InterfaceID] = Interface
+			}
+		}
+		return Interfaces
+	}
+	CurrentInterfaces, RevertedInterfaces := interfaceMap(Current), interfaceMap(Reverted)
+
+	Links := make([]revertedLink, 0)
+	for _, Device := range Current {
+		Neighbors, err := sh.Db.GetLLDPNeighborsOnDeviceExcludingMarkedForDeletion(sh.FabricID, Device.DeviceID)
+		if err != nil {
+			return Links, err
+		}
+		for _, Neighbor := range Neighbors {
+			Link := revertedLink{Neighbor: Neighbor}
+			var found [4]bool
+			Link.Current[0], found[0] = CurrentInterfaces[Neighbor.InterfaceOneID]
+			Link.Current[1], found[1] = CurrentInterfaces[Neighbor.InterfaceTwoID]
+			Link.Reverted[0], found[2] = RevertedInterfaces[Neighbor.InterfaceOneID]
+			Link.Reverted[1], found[3] = RevertedInterfaces[Neighbor.InterfaceTwoID]
+			//The MCT links have no interface configuration
+			if !found[0] || !found[1] || !found[2] || !found[3] {
+				continue
+			}
+			if !sameInterface(Link.Current[0], Link.Reverted[0]) || !sameInterface(Link.Current[1], Link.Reverted[1]) {
+				Links = append(Links, Link)
+			}
+		}
+	}
+	return Links, nil
+}
+
+func (sh *DeviceInteractor) releaseDeviceValues(ctx context.Context, Current domain.DeviceIntendedConfig,
+	Reverted domain.DeviceIntendedConfig) (string, error) {
+	if Current.LocalAS != Reverted.LocalAS && Current.LocalAS != "" {
+		asn, _ := strconv.ParseUint(Current.LocalAS, 10, 64)
+		if err := sh.ReleaseASN(ctx, sh.FabricID, Current.DeviceID, Current.Role, asn); err != nil {
+			return fmt.Sprintf("Unable to release ASN %s of device %s", Current.LocalAS, Current.DeviceIP),
+				domain.ErrFabricInternalError
+		}
+	}
+	for _, Loopback := range sh.loopbacks(Current, Reverted) {
+		if Loopback.Current == Loopback.Reverted || Loopback.Current == "" {
+			continue
+		}
+		if err := sh.ReleaseIP(ctx, sh.FabricID, Current.DeviceID, loopbackPoolName, Loopback.Current, Loopback.InterfaceID); err != nil {
+			return fmt.Sprintf("Unable to release Loopback IP %s of device %s", Loopback.Current, Current.DeviceIP),
+				domain.ErrFabricInternalError
+		}
+	}
+	return "", nil
+}
+
+func (sh *DeviceInteractor) reserveDeviceValues(ctx context.Context, Current domain.DeviceIntendedConfig,
+	Reverted domain.DeviceIntendedConfig, VTEPLoopbacks map[string]bool) (string, error) {
+	LOG := appcontext.Logger(ctx)
+	if Current.LocalAS != Reverted.LocalAS && Reverted.LocalAS != "" {
+		asn, _ := strconv.ParseUint(Reverted.LocalAS, 10, 64)
+		//An ASN in use is shared by the MCT pair
+		if count, _ := sh.getASNCountInPool(LOG, sh.FabricID, asn, Reverted.Role); count == 0 &&
+			sh.getASNCountInUsedASN(LOG, sh.FabricID, asn, Reverted.Role) == 0 {
+			return fmt.Sprintf("ASN %s of device %s is no longer available in the %s ASN pool", Reverted.LocalAS,
+				Reverted.DeviceIP, Reverted.Role), domain.ErrFabricIncorrectValues
+		}
+		if err := sh.ReserveASN(ctx, sh.FabricID, Reverted.DeviceID, Reverted.Role, asn); err != nil {
+			return fmt.Sprintf("Unable to reserve ASN %s for device %s", Reverted.LocalAS, Reverted.DeviceIP),
+				domain.ErrFabricInternalError
+		}
+	}
+	for _, Loopback := range sh.loopbacks(Current, Reverted) {
+		if Loopback.Current == Loopback.Reverted || Loopback.Reverted == "" {
+			continue
+		}
+		err := sh.ReserveIP(ctx, sh.FabricID, Reverted.DeviceID, loopbackPoolName, Loopback.Reverted, Loopback.InterfaceID)
+		//The VTEP Loopback IP is shared by the MCT pair, it is reserved once
+		if err != nil && !(Loopback.VTEP && VTEPLoopbacks[Loopback.Reverted]) {
+			return fmt.Sprintf("Loopback IP %s of device %s is no longer available in the Loopback pool",
+				Loopback.Reverted, Reverted.DeviceIP), domain.ErrFabricIncorrectValues
+		}
+		if Loopback.VTEP {
+			VTEPLoopbacks[Loopback.Reverted] = true
+		}
+	}
+	return "", nil
+}
+
+//revertedLoopback is a loopback address of a device, with the interface holding it
+type revertedLoopback struct {
+	InterfaceID uint
+	VTEP        bool
+	Current     string
+	Reverted    string
+}
+
+func (sh *DeviceInteractor) loopbacks(Current domain.DeviceIntendedConfig, Reverted domain.DeviceIntendedConfig) []revertedLoopback {
+	Loopbacks := make([]revertedLoopback, 0, 2)
+	Loopback, _ := sh.createLoopbackIntrfaceIfNotExists(sh.FabricProperties.LoopBackPortNumber, Current.DeviceID)
+	Loopbacks = append(Loopbacks, revertedLoopback{InterfaceID: Loopback.ID, Current: Current.LoopbackIP,
+		Reverted: Reverted.LoopbackIP})
+	if Current.Role == LeafRole || Current.Role == RackRole {
+		VTEPLoopback, _ := sh.createLoopbackIntrfaceIfNotExists(sh.FabricProperties.VTEPLoopBackPortNumber, Current.DeviceID)
+		Loopbacks = append(Loopbacks, revertedLoopback{InterfaceID: VTEPLoopback.ID, VTEP: true,
+			Current: Current.VTEPLoopbackIP, Reverted: Reverted.VTEPLoopbackIP})
+	}
+	return Loopbacks
+}
+
+//saveRevertedConfig saves the configuration of the device in the generation, the changed values are marked
+//to be updated on the switch
+func (sh *DeviceInteractor) saveRevertedConfig(Current domain.DeviceIntendedConfig, Reverted domain.DeviceIntendedConfig) (string, error) {
+	if Current.Settings != Reverted.Settings {
+		DeviceSettings, _ := sh.Db.GetDeviceSettings(sh.FabricID, Reverted.DeviceID)
+		Settings := Reverted.Settings
+		Settings.ID, Settings.FabricID, Settings.DeviceID = DeviceSettings.ID, sh.FabricID, Reverted.DeviceID
+		if err := sh.Db.SaveDeviceSettings(&Settings); err != nil {
+			return fmt.Sprintf("Failed to save Device Settings for %s", Reverted.DeviceIP), domain.ErrFabricInternalError
+		}
+	}
+
+	SwitchConfig, err := sh.Db.GetSwitchConfigOnFabricIDAndDeviceID(sh.FabricID, Reverted.DeviceID)
+	if err != nil {
+		return fmt.Sprintf("Unable to retrieve the configuration of device %s", Reverted.DeviceIP), domain.ErrFabricInternalError
+	}
+	if SwitchConfig.LocalAS != Reverted.LocalAS {
+		SwitchConfig.LocalAS, SwitchConfig.ASConfigType = Reverted.LocalAS, domain.ConfigUpdate
+	}
+	if SwitchConfig.LoopbackIP != Reverted.LoopbackIP {
+		SwitchConfig.LoopbackIP, SwitchConfig.LoopbackIPConfigType = Reverted.LoopbackIP, domain.ConfigUpdate
+	}
+	if SwitchConfig.VTEPLoopbackIP != Reverted.VTEPLoopbackIP {
+		SwitchConfig.VTEPLoopbackIP, SwitchConfig.VTEPLoopbackIPConfigType = Reverted.VTEPLoopbackIP, domain.ConfigUpdate
+	}
+	if err := sh.Db.CreateSwitchConfig(&SwitchConfig); err != nil {
+		return fmt.Sprintf("Failed to save switch Config for %s", Reverted.DeviceIP), domain.ErrFabricInternalError
+	}
+
+	CurrentInterfaces, err := sh.Db.GetInterfaceSwitchConfigsOnDeviceID(sh.FabricID, Reverted.DeviceID)
+	if err != nil {
+		return fmt.Sprintf("Unable to retrieve the interfaces of device %s", Reverted.DeviceIP), domain.ErrFabricInternalError
+	}
+	for _, Interface := range Reverted.Interfaces {
+		for _, CurrentInterface := range CurrentInterfaces {
+			if CurrentInterface.ConfigType == domain.ConfigDelete || interfaceKey(CurrentInterface) != interfaceKey(Interface) ||
+				sameInterface(CurrentInterface, Interface) {
+				continue
+			}
+			Interface.ID, Interface.ConfigType = CurrentInterface.ID, domain.ConfigUpdate
+			if err := sh.Db.CreateInterfaceSwitchConfig(&Interface); err != nil {
+				return fmt.Sprintf("Failed to update Interface Config %s %s", Interface.IntType, Interface.IntName),
+					domain.ErrFabricInternalError
+			}
+		}
+	}
+
+	CurrentNeighbors, err := sh.Db.GetBGPSwitchConfigsOnDeviceID(sh.FabricID, Reverted.DeviceID)
+	if err != nil {
+		return fmt.Sprintf("Unable to retrieve the BGP neighbors of device %s", Reverted.DeviceIP), domain.ErrFabricInternalError
+	}
+	for _, Neighbor := range Reverted.Neighbors {
+		for _, CurrentNeighbor := range CurrentNeighbors {
+			if CurrentNeighbor.ConfigType == domain.ConfigDelete || neighborKey(CurrentNeighbor) != neighborKey(Neighbor) ||
+				sameNeighbor(CurrentNeighbor, Neighbor) {
+				continue
+			}
+			Neighbor.ID, Neighbor.ConfigType = CurrentNeighbor.ID, domain.ConfigUpdate
+			if err := sh.Db.CreateBGPSwitchConfig(&Neighbor); err != nil {
+				return fmt.Sprintf("Failed to update BGP neighbor %s of device %s", Neighbor.RemoteIPAddress,
+					Reverted.DeviceIP), domain.ErrFabricInternalError
+			}
+		}
+	}
+	return "", nil
+}
+
+//interfaceKey and neighborKey identify an interface and a BGP neighbor across the generations,
+//as they are identified when the configuration is generated
+func interfaceKey(Interface domain.InterfaceSwitchConfig) string {
+	return fmt.Sprintln(Interface.DeviceID, Interface.IntName, Interface.IntType)
+}
+
+func neighborKey(Neighbor domain.RemoteNeighborSwitchConfig) string {
+	return fmt.Sprintln(Neighbor.DeviceID, Neighbor.RemoteDeviceID, Neighbor.RemoteInterfaceID)
+}
+
+func sameInterface(First domain.InterfaceSwitchConfig, Second domain.InterfaceSwitchConfig) bool {
+	return First.DonorType == Second.DonorType && First.DonorName == Second.DonorName && First.IPAddress == Second.IPAddress
+}
+
+func sameNeighbor(First domain.RemoteNeighborSwitchConfig, Second domain.RemoteNeighborSwitchConfig) bool {
+	return First.RemoteIPAddress == Second.RemoteIPAddress && First.RemoteAS == Second.RemoteAS
+}
+
+//diffIntendedConfig returns the changes from the configuration From to the configuration To,
+//ordered by device, kind and name. The settings, the MCT clusters and the overlay are compared when
+//both configurations record them.
+func diffIntendedConfig(FromConfig domain.ConfigGeneration, ToConfig domain.ConfigGeneration) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	From, To := FromConfig.Devices, ToConfig.Devices
+	Recorded := FromConfig.Settings != nil && ToConfig.Settings != nil
+	if Recorded {
+		for _, Change := range settingChanges(*FromConfig.Settings, *ToConfig.Settings) {
+			Change.Old, Change.New = maskPassword(Change.Name, Change.Old), maskPassword(Change.Name, Change.New)
+			Changes = append(Changes, Change)
+		}
+	}
+	DeviceIPs := make(map[uint]string)
+	InterfaceNames := make(map[uint]string)
+	FromDevices := make(map[string]domain.DeviceIntendedConfig)
+	ToDevices := make(map[string]domain.DeviceIntendedConfig)
+	for _, Devices := range [][]domain.DeviceIntendedConfig{From, To} {
+		for _, Device := range Devices {
+			DeviceIPs[Device.DeviceID] = Device.DeviceIP
+			for _, Interface := range Device.Interfaces {
+				InterfaceNames[Interface.InterfaceID] = Interface.IntType + " " + Interface.IntName
+			}
+		}
+	}
+	for _, Device := range From {
+		FromDevices[Device.DeviceIP] = Device
+	}
+	for _, Device := range To {
+		ToDevices[Device.DeviceIP] = Device
+	}
+
+	for _, Device := range From {
+		if _, found := ToDevices[Device.DeviceIP]; !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: Device.DeviceIP, Kind: domain.ChangeKindDevice,
+				Change: domain.ChangeRemoved, Old: Device.Role})
+		}
+	}
+	for _, Device := range To {
+		Old, found := FromDevices[Device.DeviceIP]
+		if !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: Device.DeviceIP, Kind: domain.ChangeKindDevice,
+				Change: domain.ChangeAdded, New: Device.Role})
+			continue
+		}
+		for _, Value := range [][3]string{{"ASN", Old.LocalAS, Device.LocalAS}, {"Loopback", Old.LoopbackIP, Device.LoopbackIP},
+			{"VTEP Loopback", Old.VTEPLoopbackIP, Device.VTEPLoopbackIP}} {
+			if Value[1] != Value[2] {
+				Changes = append(Changes, domain.ConfigChange{DeviceIP: Device.DeviceIP, Kind: domain.ChangeKindDevice,
+					Name: Value[0], Change: domain.ChangeUpdated, Old: Value[1], New: Value[2]})
+			}
+		}
+		Changes = append(Changes, diffInterfaces(Device.DeviceIP, Old.Interfaces, Device.Interfaces)...)
+		Changes = append(Changes, diffNeighbors(Device.DeviceIP, DeviceIPs, InterfaceNames, Old.Neighbors, Device.Neighbors)...)
+		if Recorded {
+			//An empty override stands for the fabric setting
+			for _, Change := range fieldChanges(domain.ChangeKindSetting, Old.Settings, Device.Settings) {
+				Change.DeviceIP = Device.DeviceIP
+				Change.Old, Change.New = deviceSettingValue(Change.Old), deviceSettingValue(Change.New)
+				Changes = append(Changes, Change)
+			}
+			Changes = append(Changes, diffClusters(Device.DeviceIP, Old.Clusters, Device.Clusters)...)
+			Changes = append(Changes, diffEVPNNeighbors(Device.DeviceIP, DeviceIPs, Old.EVPNNeighbors, Device.EVPNNeighbors)...)
+		}
+	}
+
+	sort.SliceStable(Changes, func(i, j int) bool {
+		if Changes[i].DeviceIP != Changes[j].DeviceIP {
+			return Changes[i].DeviceIP < Changes[j].DeviceIP
+		}
+		return Changes[i].Kind < Changes[j].Kind
+	})
+	return Changes
+}
+
+func diffInterfaces(DeviceIP string, From []domain.InterfaceSwitchConfig, To []domain.InterfaceSwitchConfig) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	describe := func(Interface domain.InterfaceSwitchConfig) string {
+		if Interface.DonorType != "" {
+			return fmt.Sprintf("unnumbered %s %s", Interface.DonorType, Interface.DonorName)
+		}
+		return Interface.IPAddress
+	}
+	FromInterfaces := make(map[string]domain.InterfaceSwitchConfig)
+	for _, Interface := range From {
+		FromInterfaces[interfaceKey(Interface)] = Interface
+	}
+	ToInterfaces := make(map[string]domain.InterfaceSwitchConfig)
+	for _, Interface := range To {
+		ToInterfaces[interfaceKey(Interface)] = Interface
+		Name := Interface.IntType + " " + Interface.IntName
+		Old, found := FromInterfaces[interfaceKey(Interface)]
+		if !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindInterface, Name: Name,
+				Change: domain.ChangeAdded, New: describe(Interface)})
+		} else if !sameInterface(Old, Interface) {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindInterface, Name: Name,
+				Change: domain.ChangeUpdated, Old: describe(Old), New: describe(Interface)})
+		}
+	}
+	for _, Interface := range From {
+		if _, found := ToInterfaces[interfaceKey(Interface)]; !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindInterface,
+				Name: Interface.IntType + " " + Interface.IntName, Change: domain.ChangeRemoved, Old: describe(Interface)})
+		}
+	}
+	return Changes
+}
+
+func diffNeighbors(DeviceIP string, DeviceIPs map[uint]string, InterfaceNames map[uint]string, From []domain.RemoteNeighborSwitchConfig,
+	To []domain.RemoteNeighborSwitchConfig) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	describe := func(Neighbor domain.RemoteNeighborSwitchConfig) string {
+		return fmt.Sprintf("%s AS %s", Neighbor.RemoteIPAddress, Neighbor.RemoteAS)
+	}
+	//The neighbors are named by the remote device and interface, the MCT neighbors by the remote device
+	name := func(Neighbor domain.RemoteNeighborSwitchConfig) string {
+		if InterfaceName, found := InterfaceNames[Neighbor.RemoteInterfaceID]; found {
+			return DeviceIPs[Neighbor.RemoteDeviceID] + " " + InterfaceName
+		}
+		return DeviceIPs[Neighbor.RemoteDeviceID]
+	}
+	FromNeighbors := make(map[string]domain.RemoteNeighborSwitchConfig)
+	for _, Neighbor := range From {
+		FromNeighbors[neighborKey(Neighbor)] = Neighbor
+	}
+	ToNeighbors := make(map[string]domain.RemoteNeighborSwitchConfig)
+	for _, Neighbor := range To {
+		ToNeighbors[neighborKey(Neighbor)] = Neighbor
+		Old, found := FromNeighbors[neighborKey(Neighbor)]
+		if !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindNeighbor,
+				Name: name(Neighbor), Change: domain.ChangeAdded, New: describe(Neighbor)})
+		} else if !sameNeighbor(Old, Neighbor) {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindNeighbor,
+				Name: name(Neighbor), Change: domain.ChangeUpdated, Old: describe(Old), New: describe(Neighbor)})
+		}
+	}
+	for _, Neighbor := range From {
+		if _, found := ToNeighbors[neighborKey(Neighbor)]; !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindNeighbor,
+				Name: name(Neighbor), Change: domain.ChangeRemoved, Old: describe(Neighbor)})
+		}
+	}
+	return Changes
+}
+
+func deviceSettingValue(Value string) string {
+	if len(Value) == 0 {
+		return DeviceSettingsDefault
+	}
+	return Value
+}
+
+func diffClusters(DeviceIP string, From []domain.MctClusterConfig, To []domain.MctClusterConfig) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	describe := func(Cluster domain.MctClusterConfig) string {
+		return fmt.Sprintf("%s %s peer %s/%s control VLAN %s VE %s", Cluster.PeerInterfacetype, Cluster.PeerInterfaceName,
+			Cluster.PeerOneIP, Cluster.PeerTwoIP, Cluster.ControlVlan, Cluster.ControlVE)
+	}
+	FromClusters := make(map[uint16]domain.MctClusterConfig)
+	for _, Cluster := range From {
+		FromClusters[Cluster.ClusterID] = Cluster
+	}
+	ToClusters := make(map[uint16]domain.MctClusterConfig)
+	for _, Cluster := range To {
+		ToClusters[Cluster.ClusterID] = Cluster
+		Name := strconv.Itoa(int(Cluster.ClusterID))
+		Old, found := FromClusters[Cluster.ClusterID]
+		if !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindCluster, Name: Name,
+				Change: domain.ChangeAdded, New: describe(Cluster)})
+		} else if describe(Old) != describe(Cluster) {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindCluster, Name: Name,
+				Change: domain.ChangeUpdated, Old: describe(Old), New: describe(Cluster)})
+		}
+	}
+	for _, Cluster := range From {
+		if _, found := ToClusters[Cluster.ClusterID]; !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindCluster,
+				Name: strconv.Itoa(int(Cluster.ClusterID)), Change: domain.ChangeRemoved, Old: describe(Cluster)})
+		}
+	}
+	return Changes
+}
+
+func diffEVPNNeighbors(DeviceIP string, DeviceIPs map[uint]string, From []domain.RackEvpnNeighbors,
+	To []domain.RackEvpnNeighbors) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	describe := func(Neighbor domain.RackEvpnNeighbors) string {
+		return fmt.Sprintf("%s AS %s", Neighbor.EVPNAddress, Neighbor.RemoteAS)
+	}
+	FromNeighbors := make(map[uint]domain.RackEvpnNeighbors)
+	for _, Neighbor := range From {
+		FromNeighbors[Neighbor.RemoteDeviceID] = Neighbor
+	}
+	ToNeighbors := make(map[uint]domain.RackEvpnNeighbors)
+	for _, Neighbor := range To {
+		ToNeighbors[Neighbor.RemoteDeviceID] = Neighbor
+		Old, found := FromNeighbors[Neighbor.RemoteDeviceID]
+		if !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindEVPN,
+				Name: DeviceIPs[Neighbor.RemoteDeviceID], Change: domain.ChangeAdded, New: describe(Neighbor)})
+		} else if describe(Old) != describe(Neighbor) {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindEVPN,
+				Name: DeviceIPs[Neighbor.RemoteDeviceID], Change: domain.ChangeUpdated, Old: describe(Old), New: describe(Neighbor)})
+		}
+	}
+	for _, Neighbor := range From {
+		if _, found := ToNeighbors[Neighbor.RemoteDeviceID]; !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindEVPN,
+				Name: DeviceIPs[Neighbor.RemoteDeviceID], Change: domain.ChangeRemoved, Old: describe(Neighbor)})
+		}
+	}
+	return Changes
+}
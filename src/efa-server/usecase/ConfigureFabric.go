@@ -7,6 +7,7 @@ import (
 	"efa-server/domain/operation"
 	"efa-server/gateway/appcontext"
 	"efa-server/infra/device/actions"
+	"efa-server/infra/metrics"
 	"efa-server/infra/util"
 	"errors"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type result struct {
@@ -42,6 +44,14 @@ type ValidateFabricResponse struct {
 	MissingLinks    []string
 	SpineSpineLinks []string
 	LeafLeafLinks   []string
+	//PoolWarnings lists the allocation pools whose utilization reached the warning threshold
+	PoolWarnings []string
+	//PinConflicts lists the values pinned to the devices which cannot be honoured
+	PinConflicts []string
+	//Cabling lists the differences between the links discovered by LLDP and the cabling plan of the fabric
+	Cabling domain.CablingMismatch
+	//Preflight lists the findings of the pre-flight checks of the devices
+	Preflight []domain.PreflightFinding
 }
 
 //ConfigureFabricResponse is a response object which defines the success/error of "configure fabric" operation
@@ -50,11 +60,21 @@ type ConfigureFabricResponse struct {
 	FabricName string
 	FabricID   uint
 	Errors     []actions.OperationError
+	//PoolWarnings lists the allocation pools whose utilization reached the warning threshold
+	PoolWarnings []string
+	//Generation is the configuration generation stored by the configure
+	Generation uint
+	//Health is the health of the fabric checked once configured
+	Health *domain.FabricHealth
 }
 
 type stageFunction func(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse,
 	FabricName string, IPAddress string, UserName string, Password string, Role string)
 
+//discoveryStages names the stages of the discovery of the devices in the execution events, in their order
+var discoveryStages = []string{domain.StageDiscoveryInterfaces, domain.StageDiscoveryLLDP, domain.StageDiscoveryTopology,
+	domain.StageDiscoveryNeighbors}
+
 // CreateDevice either updates/creates device with given IPaddress, role, credentials and fabricID
 func (sh *DeviceInteractor) CreateDevice(FabricName string, IPAddress string, UserName string, Password string, Role string) (id uint, err error) {
 	err = nil
@@ -93,6 +113,12 @@ func (sh *DeviceInteractor) AddDevices(ctx context.Context, FabricName string, L
 	//If force is enabled clear up the configuration on devices specified in the IP address
 	existingIPaddress := append(existingLeafList, existingSpineList...)
 	LOG.Infoln("Existing Device List", existingIPaddress)
+
+	//Devices in maintenance mode are configured only when forced
+	if AddDeviceResponseList, err = sh.checkMaintenanceMode(ctx, FabricName,
+		sh.getUniqueList(ctx, existingIPaddress, ipaddress), force); err != nil {
+		return
+	}
 	if force {
 		LOG.Infoln("Force option enabled on Devices", ipaddress)
 
@@ -180,8 +206,8 @@ func (sh *DeviceInteractor) AddDevices(ctx context.Context, FabricName string, L
 	Password = ""
 	for index, stageFunction := range stageFunctions {
 		LOG.Println("Executing Stage ", index+1)
-		AddDeviceResponseList, err = sh.executeAddDeviceStage(ctx, FabricName, totalLeafList, totalSpineList, UserName,
-			Password, force, stageFunction)
+		AddDeviceResponseList, err = sh.executeAddDeviceStage(ctx, FabricName, discoveryStages[index], totalLeafList,
+			totalSpineList, UserName, Password, force, stageFunction)
 		if err != nil {
 			//Operation failed so Rollback the Database
 			RollBack = true
@@ -288,10 +314,12 @@ func (sh *DeviceInteractor) deviceAlreadyRegisteredWithDifferentRole(LeafIPAddre
 	return AddDeviceResponseList, overallError
 }
 
-func (sh *DeviceInteractor) executeAddDeviceStage(ctx context.Context, FabricName string, LeafIPaddressList []string,
-	SpineIPaddressList []string, UserName string, Password string, force bool, function stageFunction) ([]AddDeviceResponse, error) {
+func (sh *DeviceInteractor) executeAddDeviceStage(ctx context.Context, FabricName string, Stage string,
+	LeafIPaddressList []string, SpineIPaddressList []string, UserName string, Password string, force bool,
+	function stageFunction) ([]AddDeviceResponse, error) {
 
 	var fabricGate sync.WaitGroup
+	Progress := actions.StartStage(ctx, Stage, append(append([]string{}, SpineIPaddressList...), LeafIPaddressList...))
 
 	AddDeviceResponseList := make([]AddDeviceResponse, 0, len(SpineIPaddressList)+len(LeafIPaddressList))
 
@@ -317,6 +345,10 @@ func (sh *DeviceInteractor) executeAddDeviceStage(ctx context.Context, FabricNam
 		if len(result.Errors) > 0 {
 			overallOperationFailed = true
 		}
+		for _, err := range result.Errors {
+			Progress.Fail(actions.OperationError{Operation: Stage, Error: err, Host: result.IPAddress})
+		}
+		Progress.Succeed(result.IPAddress)
 
 		AddDeviceResponseList = append(AddDeviceResponseList, result)
 	}
@@ -328,6 +360,7 @@ func (sh *DeviceInteractor) executeAddDeviceStage(ctx context.Context, FabricNam
 
 func (sh *DeviceInteractor) addSingleDeviceFirstStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, IPAddress string, UserName string, Password string, Role string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddDeviceFirstStage", time.Now())
 
 	var buffer bytes.Buffer
 
@@ -346,6 +379,7 @@ func (sh *DeviceInteractor) addSingleDeviceFirstStage(ctx context.Context, fabri
 
 func (sh *DeviceInteractor) addSingleDeviceSecondStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, IPAddress string, UserName string, Password string, Role string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddDeviceSecondStage", time.Now())
 
 	var buffer bytes.Buffer
 
@@ -364,6 +398,7 @@ func (sh *DeviceInteractor) addSingleDeviceSecondStage(ctx context.Context, fabr
 
 func (sh *DeviceInteractor) addSingleDeviceThirdStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, IPAddress string, UserName string, Password string, Role string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddDeviceThirdStage", time.Now())
 
 	var buffer bytes.Buffer
 
@@ -382,6 +417,7 @@ func (sh *DeviceInteractor) addSingleDeviceThirdStage(ctx context.Context, fabri
 
 func (sh *DeviceInteractor) addSingleDeviceFourthStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, IPAddress string, UserName string, Password string, Role string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddDeviceFourthStage", time.Now())
 
 	var buffer bytes.Buffer
 
@@ -400,11 +436,29 @@ func (sh *DeviceInteractor) addSingleDeviceFourthStage(ctx context.Context, fabr
 
 //ValidateFabricTopology validates the topology of the CLOS IP Fabric.
 //e.g: Spine is not connected to another spine, MCT leaf not connected to another leaf, Spine not connected to leaf etc.
+//The allocation pools whose utilization reached the warning threshold and the conflicts of the values pinned
+//to the devices are reported along with the topology errors.
 func (sh *DeviceInteractor) ValidateFabricTopology(ctx context.Context, FabricName string) (ValidateFabricResponse, error) {
-
+	var FabricValidateResponse ValidateFabricResponse
+	var err error
 	if sh.FabricProperties.FabricType == domain.NonCLOSFabricType {
-		return sh.ValidateNonClosFabricTopology(ctx, FabricName)
+		FabricValidateResponse, err = sh.ValidateNonClosFabricTopology(ctx, FabricName)
+	} else {
+		FabricValidateResponse, err = sh.validateCLOSFabricTopology(ctx, FabricName)
+	}
+	if err == nil {
+		FabricValidateResponse.PoolWarnings = sh.getPoolWarnings(ctx, FabricName)
+		FabricValidateResponse.PinConflicts = sh.validateAllocationPins(ctx, FabricName)
+		if FabricValidateResponse.Cabling, err = sh.validateCablingPlan(ctx, FabricName); err != nil {
+			appcontext.Logger(ctx).Errorf("Failed to validate the cabling plan of Fabric %s: %s\n", FabricName, err)
+			return FabricValidateResponse, err
+		}
+		sh.notifyMissingLinks(ctx, FabricName, FabricValidateResponse.MissingLinks)
 	}
+	return FabricValidateResponse, err
+}
+
+func (sh *DeviceInteractor) validateCLOSFabricTopology(ctx context.Context, FabricName string) (ValidateFabricResponse, error) {
 	LOG := appcontext.Logger(ctx)
 	FabricValidateResponse := ValidateFabricResponse{FabricName: FabricName}
 	devices, err := sh.ListDevices(FabricName)
@@ -574,10 +628,19 @@ func (sh *DeviceInteractor) ConfigureFabric(ctx context.Context, FabricName stri
 
 	response := ConfigureFabricResponse{FabricName: FabricName}
 	config, err := sh.GetActionRequestObject(ctx, FabricName, force)
+	//Report the pools running out with the values allocated for this configuration, before pushing it
+	response.PoolWarnings = sh.getPoolWarnings(ctx, FabricName)
 	if err != nil {
 		return response, err
 	}
 
+	//Pushing the configuration would restore the traffic of the devices in maintenance mode
+	if Errors := maintenanceModeErrors(config); len(Errors) != 0 {
+		LOG.Errorln("Devices in maintenance mode, disable maintenance mode or use force")
+		response.Errors = Errors
+		return response, domain.ErrFabricDeviceInMaintenance
+	}
+
 	//Send the Config Object to Actions for configuring the switches
 	if Errors := sh.FabricAdapter.ConfigureFabric(ctx, config, force, persist); len(Errors) != 0 {
 		response.Errors = Errors
@@ -588,6 +651,16 @@ func (sh *DeviceInteractor) ConfigureFabric(ctx context.Context, FabricName stri
 		response.Errors = []actions.OperationError{actions.OperationError{Operation: "Clean up DB Failed", Error: err}}
 		return response, err
 	}
+	//The configuration pushed to the switches is kept as the next generation of the fabric
+	if Generation, err := sh.recordConfigGeneration(ctx); err != nil {
+		LOG.Errorf("Failed to record the configuration generation during Configure %s\n", err)
+	} else {
+		response.Generation = Generation.Generation
+	}
+	//An unhealthy fabric is reported with the response, the configuration was pushed to the switches
+	if Deferred, _ := ctx.Value(appcontext.DeferHealthCheck).(bool); !Deferred {
+		response.Health = sh.VerifyFabricHealth(ctx, FabricName)
+	}
 	//On Success backup the DB
 	if err := sh.Db.Backup(); err != nil {
 		LOG.Printf("Failed to backup DB during Configure %s\n", err)
@@ -760,10 +833,11 @@ func (sh *DeviceInteractor) prepareMctClusters(ctx context.Context, switchConfig
 					ClusterMember1.RemoteNodePeerLoopbackIP = sw.LoopbackIP
 					ClusterMember1.NodePeerLoopbackIP = switchConfigMap[Cluster.MCTNeighborDeviceID].LoopbackIP
 					ClusterMember1.NodePeerIP = Cluster.PeerOneIP
-					ClusterMember1.BFDEnable = config.FabricSettings.BFDEnable
-					ClusterMember1.BFDRx = config.FabricSettings.BFDRx
-					ClusterMember1.BFDTx = config.FabricSettings.BFDTx
-					ClusterMember1.BFDMultiplier = config.FabricSettings.BFDMultiplier
+					MemberOneSettings := sh.getDeviceFabricSettings(config.FabricSettings, Cluster.DeviceID)
+					ClusterMember1.BFDEnable = MemberOneSettings.BFDEnable
+					ClusterMember1.BFDRx = MemberOneSettings.BFDRx
+					ClusterMember1.BFDTx = MemberOneSettings.BFDTx
+					ClusterMember1.BFDMultiplier = MemberOneSettings.BFDMultiplier
 					ClusterMember1.RemoteNodePeerIP = fmt.Sprintf("%s%s", Cluster.PeerTwoIP, "/31")
 
 					ClusterMember2 = operation.ClusterMemberNode{}
@@ -779,10 +853,11 @@ func (sh *DeviceInteractor) prepareMctClusters(ctx context.Context, switchConfig
 					ClusterMember2.RemoteNodePeerLoopbackIP = switchConfigMap[Cluster.MCTNeighborDeviceID].LoopbackIP
 					ClusterMember2.NodePeerLoopbackIP = sw.LoopbackIP
 					ClusterMember2.NodePeerIP = Cluster.PeerTwoIP
-					ClusterMember2.BFDEnable = config.FabricSettings.BFDEnable
-					ClusterMember2.BFDRx = config.FabricSettings.BFDRx
-					ClusterMember2.BFDTx = config.FabricSettings.BFDTx
-					ClusterMember2.BFDMultiplier = config.FabricSettings.BFDMultiplier
+					MemberTwoSettings := sh.getDeviceFabricSettings(config.FabricSettings, Cluster.MCTNeighborDeviceID)
+					ClusterMember2.BFDEnable = MemberTwoSettings.BFDEnable
+					ClusterMember2.BFDRx = MemberTwoSettings.BFDRx
+					ClusterMember2.BFDTx = MemberTwoSettings.BFDTx
+					ClusterMember2.BFDMultiplier = MemberTwoSettings.BFDMultiplier
 					ClusterMember2.RemoteNodePeerIP = fmt.Sprintf("%s%s", Cluster.PeerOneIP, "/31")
 
 					ConfigCluster.OperationBitMap = Cluster.UpdatedAttributes
@@ -939,6 +1014,8 @@ func (sh *DeviceInteractor) populateHostDetails(ctx context.Context, host operat
 	//TODO Get away from two fields Device,Host
 	var err error
 	sw := switchConfigMap[Switch.ID]
+	//Per-device overrides take precedence over the fabric settings
+	FabricSettings := sh.getDeviceFabricSettings(config.FabricSettings, Switch.ID)
 	host.Device = Switch.IPAddress
 	host.Host = Switch.IPAddress
 	host.Fabric = config.FabricName
@@ -951,26 +1028,30 @@ func (sh *DeviceInteractor) populateHostDetails(ctx context.Context, host operat
 	host.Role = sw.Role
 	host.Model = Switch.Model
 	host.Principal = false
+	host.MaintenanceMode = Switch.MaintenanceMode
 
-	host.ConfigureOverlayGateway = config.FabricSettings.ConfigureOverlayGateway
-	host.LoopbackPortNumber = config.FabricSettings.LoopBackPortNumber
+	host.ConfigureOverlayGateway = FabricSettings.ConfigureOverlayGateway
+	host.LoopbackPortNumber = FabricSettings.LoopBackPortNumber
 
 	//BGP Fields
-	host.MaxPaths = config.FabricSettings.MaxPaths
-	host.Mtu = config.FabricSettings.MTU
-	host.IPMtu = config.FabricSettings.IPMTU
-	host.BgpMultihop = config.FabricSettings.BGPMultiHop
+	host.MaxPaths = FabricSettings.MaxPaths
+	host.Mtu = FabricSettings.MTU
+	host.IPMtu = FabricSettings.IPMTU
+	host.BgpMultihop = FabricSettings.BGPMultiHop
 	host.BgpLocalAsn = sw.LocalAS
-	host.SpinePeerGroup = config.FabricSettings.SpinePeerGroup
-	host.LeafPeerGroup = config.FabricSettings.LeafPeerGroup
+	host.SpinePeerGroup = FabricSettings.SpinePeerGroup
+	host.LeafPeerGroup = FabricSettings.LeafPeerGroup
 	host.SingleSpineAs = false
-	host.AllowasIn = config.FabricSettings.AllowASIn
-	host.BFDEnable = config.FabricSettings.BFDEnable
+	host.AllowasIn = FabricSettings.AllowASIn
+	host.BFDEnable = FabricSettings.BFDEnable
+	host.BGPAuthType = FabricSettings.BGPAuthType
 	//TODO -- NONCLOS --PeerGroup
 	if host.Role == LeafRole || host.Role == RackRole {
 		host.Network = sw.VTEPLoopbackIP + "/32"
 		host.PeerGroup = host.LeafPeerGroup
 		host.PeerGroupDescription = "To Spine"
+		host.PeerGroupPassword = FabricSettings.PeerGroupPassword
+		host.MctPeerPassword = FabricSettings.MctL2EvpnPassword
 		host.UnconfigureMCTBGPNeighbors, host.ConfigureMCTBGPNeighbors, err = sh.prepareMCTBGPNeighbors(ctx, &sw,
 			host, switchConfigMap, opcode, host.BFDEnable)
 		if err != nil {
@@ -981,6 +1062,7 @@ func (sh *DeviceInteractor) populateHostDetails(ctx context.Context, host operat
 	if host.Role == SpineRole {
 		host.PeerGroup = host.SpinePeerGroup
 		host.PeerGroupDescription = "To Leaf"
+		host.PeerGroupPassword = FabricSettings.PeerGroupPassword
 	}
 	host.BgpNeighbors = sh.prepareBGPNeighbors(ctx, &sw)
 
@@ -988,38 +1070,40 @@ func (sh *DeviceInteractor) populateHostDetails(ctx context.Context, host operat
 		evpnNeighbors := sh.prepareNONCLOSBGPEVPNNeighbors(ctx, &sw)
 		LOG.Infoln("EVPN Neighbors", sw.DeviceID, evpnNeighbors)
 		host.BgpNeighbors = append(host.BgpNeighbors, evpnNeighbors...)
-		host.EvpnPeerGroup = config.FabricSettings.RackPeerOvgGroup
+		host.EvpnPeerGroup = FabricSettings.RackPeerOvgGroup
 		host.EvpnPeerGroupDescription = "Rack Overlay EBGP Group"
-		host.PeerGroup = config.FabricSettings.RackPeerEBGPGroup
+		host.PeerGroup = FabricSettings.RackPeerEBGPGroup
 		host.PeerGroupDescription = "Rack Underlay EBGP Group"
+		host.PeerGroupPassword = FabricSettings.RackPeerEBGPGroupPassword
+		host.EvpnPeerGroupPassword = FabricSettings.RackPeerOvgGroupPassword
 
 		host.NonCLOSNetwork = sw.LoopbackIP + "/32"
 
 	}
 
 	//Interface Fields
-	host.P2PIPType = config.FabricSettings.P2PIPType
+	host.P2PIPType = FabricSettings.P2PIPType
 
-	host.P2pLinkRange = config.FabricSettings.P2PLinkRange
-	host.BfdMultiplier = config.FabricSettings.BFDMultiplier
-	host.BfdRx = config.FabricSettings.BFDRx
-	host.BfdTx = config.FabricSettings.BFDTx
+	host.P2pLinkRange = FabricSettings.P2PLinkRange
+	host.BfdMultiplier = FabricSettings.BFDMultiplier
+	host.BfdRx = FabricSettings.BFDRx
+	host.BfdTx = FabricSettings.BFDTx
 
 	host.Interfaces = sh.prepareInterfaceConfigs(ctx, &sw, config)
 
 	//ovg fields
-	host.VtepLoopbackPortNumber = config.FabricSettings.VTEPLoopBackPortNumber
-	host.VlanVniAutoMap = formatYesNo(config.FabricSettings.VNIAutoMap)
-	host.AnycastMac = config.FabricSettings.AnyCastMac
-	host.IPV6AnycastMac = config.FabricSettings.IPV6AnyCastMac
+	host.VtepLoopbackPortNumber = FabricSettings.VTEPLoopBackPortNumber
+	host.VlanVniAutoMap = formatYesNo(FabricSettings.VNIAutoMap)
+	host.AnycastMac = FabricSettings.AnyCastMac
+	host.IPV6AnycastMac = FabricSettings.IPV6AnyCastMac
 
 	//EVPN Fields
-	host.ArpAgingTimeout = config.FabricSettings.ArpAgingTimeout
-	host.MacAgingTimeout = config.FabricSettings.MacAgingTimeout
-	host.MacAgingConversationalTimeout = config.FabricSettings.MacAgingConversationalTimeout
-	host.MacMoveLimit = config.FabricSettings.MacMoveLimit
-	host.DuplicateMacTimer = config.FabricSettings.DuplicateMacTimer
-	host.DuplicateMaxTimerMaxCount = config.FabricSettings.DuplicateMaxTimerMaxCount
+	host.ArpAgingTimeout = FabricSettings.ArpAgingTimeout
+	host.MacAgingTimeout = FabricSettings.MacAgingTimeout
+	host.MacAgingConversationalTimeout = FabricSettings.MacAgingConversationalTimeout
+	host.MacMoveLimit = FabricSettings.MacMoveLimit
+	host.DuplicateMacTimer = FabricSettings.DuplicateMacTimer
+	host.DuplicateMaxTimerMaxCount = FabricSettings.DuplicateMaxTimerMaxCount
 
 	if len(host.Interfaces) > 0 {
 		config.Hosts = append(config.Hosts, host)
@@ -1089,6 +1173,8 @@ func (sh *DeviceInteractor) prepareMCTBGPNeighbors(ctx context.Context, switchCo
 		} else {
 			memberNode.NodePeerBFDEnabled = "No"
 		}
+		memberNode.NodePeerAuthType = host.BGPAuthType
+		memberNode.NodePeerPassword = host.MctPeerPassword
 		if bgp.ConfigType == domain.ConfigDelete || opcode == domain.ConfigDelete {
 			unconfigureMCTBGPNeighbors.DataPlaneClusterMemberNodes = append(unconfigureMCTBGPNeighbors.DataPlaneClusterMemberNodes, memberNode)
 		}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+)
+
+//BackupDatabase saves the database to a new backup while the application keeps running
+func (sh *DeviceInteractor) BackupDatabase(ctx context.Context) (domain.DatabaseBackup, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Backup Database")
+	LOG := appcontext.Logger(ctx)
+
+	Backup, err := sh.Db.CreateBackup()
+	if err != nil {
+		statusMsg := fmt.Sprintf("Database backup failed: %s", err.Error())
+		DEC(LOG).Errorln(statusMsg)
+		return Backup, statusMsg, domain.ErrFabricInternalError
+	}
+	statusMsg := fmt.Sprintf("Database saved to backup %s", Backup.Name)
+	LOG.Infoln(statusMsg)
+	return Backup, statusMsg, nil
+}
+
+//GetDatabaseBackups returns the backups of the database, the most recent first
+func (sh *DeviceInteractor) GetDatabaseBackups(ctx context.Context) ([]domain.DatabaseBackup, error) {
+	LOG := appcontext.Logger(ctx)
+	Backups, err := sh.Db.GetBackups()
+	if err != nil {
+		LOG.Errorln("Failed to list the database backups", err)
+		return Backups, err
+	}
+	return Backups, nil
+}
+
+//RestoreDatabase replaces the database with the backup. The backups of a newer schema are refused.
+//The operations on the database are stopped during the restore and restart on the restored data.
+func (sh *DeviceInteractor) RestoreDatabase(ctx context.Context, Name string) (domain.DatabaseBackup, string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Restore Database")
+	LOG := appcontext.Logger(ctx)
+
+	var Backup domain.DatabaseBackup
+	Backups, err := sh.Db.GetBackups()
+	if err != nil {
+		statusMsg := fmt.Sprintf("Failed to list the database backups: %s", err.Error())
+		DEC(LOG).Errorln(statusMsg)
+		return Backup, statusMsg, domain.ErrFabricInternalError
+	}
+	found := false
+	for _, Backup = range Backups {
+		if Backup.Name == Name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		statusMsg := fmt.Sprintf("Backup %s does not exist", Name)
+		DEC(LOG).Errorln(statusMsg)
+		return domain.DatabaseBackup{}, statusMsg, domain.ErrBackupNotFound
+	}
+	Status, err := sh.Db.GetSchemaMigrationStatus()
+	if err != nil {
+		statusMsg := fmt.Sprintf("Failed to read the database schema version: %s", err.Error())
+		DEC(LOG).Errorln(statusMsg)
+		return Backup, statusMsg, domain.ErrFabricInternalError
+	}
+	if Backup.SchemaVersion > Status.LatestVersion {
+		statusMsg := fmt.Sprintf("Backup %s has the schema version %d, newer than the version %d supported",
+			Name, Backup.SchemaVersion, Status.LatestVersion)
+		DEC(LOG).Errorln(statusMsg)
+		return Backup, statusMsg, domain.ErrFabricIncorrectValues
+	}
+
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if Backup, err = sh.Db.RestoreBackup(Name); err != nil {
+		statusMsg := fmt.Sprintf("Restore of backup %s failed: %s", Name, err.Error())
+		DEC(LOG).Errorln(statusMsg)
+		if err == domain.ErrBackupNotFound {
+			return Backup, statusMsg, err
+		}
+		return Backup, statusMsg, domain.ErrFabricInternalError
+	}
+	//The fabric cached by the previous operations belongs to the replaced data
+	sh.FabricID, sh.FabricName, sh.FabricProperties = 0, "", domain.FabricProperties{}
+
+	statusMsg := fmt.Sprintf("Database restored from backup %s", Name)
+	LOG.Infoln(statusMsg)
+	return Backup, statusMsg, nil
+}
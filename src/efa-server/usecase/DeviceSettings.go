@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+)
+
+//DeviceSettingsDefault is the value used to remove a per-device override,
+//so that the fabric-wide setting applies to the device again
+const DeviceSettingsDefault = "default"
+
+//UpdateDeviceSettings updates the per-device overrides of the fabric settings for a given device
+func (sh *DeviceInteractor) UpdateDeviceSettings(ctx context.Context, FabricName string, IPAddress string,
+	DeviceSettingsRequest *domain.DeviceSettings) (string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Update Device Settings")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	var Fabric domain.Fabric
+	var Device domain.Device
+	var err error
+	RollBack := true
+
+	if Fabric, err = sh.Db.GetFabric(FabricName); err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricNotFound
+	}
+	if Device, err = sh.Db.GetDevice(FabricName, IPAddress); err != nil {
+		statusMsg := fmt.Sprintf("Device %s is not registered with Fabric %s", IPAddress, FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrDeviceNotFound
+	}
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err = sh.Db.OpenTransaction(); err != nil {
+		return err.Error(), domain.ErrFabricInternalError
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	//No overrides present for the device yet, start with an empty set
+	DeviceSettings, _ := sh.Db.GetDeviceSettings(Fabric.ID, Device.ID)
+	DeviceSettings.FabricID = Fabric.ID
+	DeviceSettings.DeviceID = Device.ID
+
+	oldDeviceSettings := DeviceSettings
+	modifyUpdatedDeviceSettings(&DeviceSettings, DeviceSettingsRequest)
+	if DeviceSettings == oldDeviceSettings {
+		statusMsg := fmt.Sprintf("No Device Settings Update is Requested For Device %s", IPAddress)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricIncorrectValues
+	}
+
+	if err = sh.Db.SaveDeviceSettings(&DeviceSettings); err != nil {
+		statusMsg := fmt.Sprintf("Failed to save Device Settings for %s", IPAddress)
+		LOG.Errorln(statusMsg, err)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return "Device Settings updated", nil
+}
+
+//GetDeviceSettings returns the per-device overrides along with the fabric settings, for a given device
+func (sh *DeviceInteractor) GetDeviceSettings(ctx context.Context, FabricName string, IPAddress string) (
+	domain.DeviceSettings, domain.FabricProperties, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Get Device Settings")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	var Fabric domain.Fabric
+	var Device domain.Device
+	var FabricProperties domain.FabricProperties
+	var err error
+
+	if Fabric, err = sh.Db.GetFabric(FabricName); err != nil {
+		LOG.Errorf("Unable to retrieve Fabric %s", FabricName)
+		return domain.DeviceSettings{}, FabricProperties, domain.ErrFabricNotFound
+	}
+	if FabricProperties, err = sh.Db.GetFabricProperties(Fabric.ID); err != nil {
+		LOG.Errorf("Unable to retrieve Fabric Properties for %s", FabricName)
+		return domain.DeviceSettings{}, FabricProperties, domain.ErrFabricInternalError
+	}
+	if Device, err = sh.Db.GetDevice(FabricName, IPAddress); err != nil {
+		LOG.Errorf("Device %s is not registered with Fabric %s", IPAddress, FabricName)
+		return domain.DeviceSettings{}, FabricProperties, domain.ErrDeviceNotFound
+	}
+
+	//Absence of overrides is not an error, the fabric settings apply as is
+	DeviceSettings, _ := sh.Db.GetDeviceSettings(Fabric.ID, Device.ID)
+	return DeviceSettings, FabricProperties, nil
+}
+
+//getDeviceFabricSettings returns the fabric settings with the overrides of the device merged in
+func (sh *DeviceInteractor) getDeviceFabricSettings(FabricSettings domain.FabricProperties, DeviceID uint) domain.FabricProperties {
+	DeviceSettings, err := sh.Db.GetDeviceSettings(sh.FabricID, DeviceID)
+	if err != nil {
+		return FabricSettings
+	}
+	return MergeDeviceSettings(FabricSettings, DeviceSettings)
+}
+
+//MergeDeviceSettings overlays the per-device overrides on the fabric settings
+func MergeDeviceSettings(FabricSettings domain.FabricProperties, DeviceSettings domain.DeviceSettings) domain.FabricProperties {
+	override := func(d *string, s string) {
+		if len(s) != 0 {
+			*d = s
+		}
+	}
+	override(&FabricSettings.MTU, DeviceSettings.MTU)
+	override(&FabricSettings.IPMTU, DeviceSettings.IPMTU)
+	override(&FabricSettings.BFDEnable, DeviceSettings.BFDEnable)
+	override(&FabricSettings.BFDTx, DeviceSettings.BFDTx)
+	override(&FabricSettings.BFDRx, DeviceSettings.BFDRx)
+	override(&FabricSettings.BFDMultiplier, DeviceSettings.BFDMultiplier)
+	override(&FabricSettings.MaxPaths, DeviceSettings.MaxPaths)
+	override(&FabricSettings.AllowASIn, DeviceSettings.AllowASIn)
+	override(&FabricSettings.LeafPeerGroup, DeviceSettings.LeafPeerGroup)
+	override(&FabricSettings.SpinePeerGroup, DeviceSettings.SpinePeerGroup)
+	override(&FabricSettings.ArpAgingTimeout, DeviceSettings.ArpAgingTimeout)
+	override(&FabricSettings.MacAgingTimeout, DeviceSettings.MacAgingTimeout)
+	override(&FabricSettings.MacAgingConversationalTimeout, DeviceSettings.MacAgingConversationalTimeout)
+	override(&FabricSettings.MacMoveLimit, DeviceSettings.MacMoveLimit)
+	override(&FabricSettings.DuplicateMacTimer, DeviceSettings.DuplicateMacTimer)
+	override(&FabricSettings.DuplicateMaxTimerMaxCount, DeviceSettings.DuplicateMaxTimerMaxCount)
+	return FabricSettings
+}
+
+//modifyUpdatedDeviceSettings copies the requested fields, "default" clears the override
+func modifyUpdatedDeviceSettings(d *domain.DeviceSettings, s *domain.DeviceSettings) {
+	update := func(d *string, s string) {
+		if len(s) == 0 {
+			return
+		}
+		if s == DeviceSettingsDefault {
+			*d = ""
+			return
+		}
+		*d = s
+	}
+	update(&d.MTU, s.MTU)
+	update(&d.IPMTU, s.IPMTU)
+	update(&d.BFDEnable, s.BFDEnable)
+	update(&d.BFDTx, s.BFDTx)
+	update(&d.BFDRx, s.BFDRx)
+	update(&d.BFDMultiplier, s.BFDMultiplier)
+	update(&d.MaxPaths, s.MaxPaths)
+	update(&d.AllowASIn, s.AllowASIn)
+	update(&d.LeafPeerGroup, s.LeafPeerGroup)
+	update(&d.SpinePeerGroup, s.SpinePeerGroup)
+	update(&d.ArpAgingTimeout, s.ArpAgingTimeout)
+	update(&d.MacAgingTimeout, s.MacAgingTimeout)
+	update(&d.MacAgingConversationalTimeout, s.MacAgingConversationalTimeout)
+	update(&d.MacMoveLimit, s.MacMoveLimit)
+	update(&d.DuplicateMacTimer, s.DuplicateMacTimer)
+	update(&d.DuplicateMaxTimerMaxCount, s.DuplicateMaxTimerMaxCount)
+}
@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+//healthPollInterval is the interval between the health checks of a fabric while it settles after a configure
+const healthPollInterval = 5 * time.Second
+
+//expectedHealth is the state of a device expected from the configuration of the fabric
+type expectedHealth struct {
+	BgpNeighbors  []string
+	EvpnNeighbors []string
+	Bfd           bool
+	Mct           bool
+	Tunnels       []string
+	RouteCount    int
+}
+
+//FabricHealth collects the operational state of every device of the fabric and checks it against the
+//BGP and EVPN neighbors, the MCT clusters and the overlay gateway tunnels configured in the fabric
+func (sh *DeviceInteractor) FabricHealth(ctx context.Context, FabricName string) (domain.FabricHealth, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Fabric Health")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	Health := domain.FabricHealth{FabricName: FabricName, CheckedAt: time.Now()}
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Health, domain.ErrFabricNotFound
+	}
+	FabricProperties, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the settings of Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Health, domain.ErrFabricInternalError
+	}
+	Devices, err := sh.Db.GetDevicesInFabric(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Failed to fetch devices from %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return Health, domain.ErrFabricInternalError
+	}
+	sort.Slice(Devices, func(i, j int) bool { return Devices[i].IPAddress < Devices[j].IPAddress })
+
+	Request := operation.FabricHealthRequest{FabricName: FabricName,
+		Overlay: FabricProperties.ConfigureOverlayGateway == "Yes"}
+	for _, dev := range Devices {
+		Request.Hosts = append(Request.Hosts, operation.SwitchIdentity{Host: dev.IPAddress, Role: dev.DeviceRole,
+			UserName: dev.UserName, Password: dev.Password, Model: dev.Model})
+	}
+	Response, Errors := sh.FabricAdapter.FetchFabricHealth(ctx, Request)
+
+	States := make(map[string]operation.SwitchHealthResponse, len(Response.SwitchResponse))
+	for _, State := range Response.SwitchResponse {
+		States[State.Host] = State
+	}
+	Failures := make(map[string]string, len(Errors))
+	for _, Error := range Errors {
+		Failures[Error.Host] = Error.DeviceError().Message
+	}
+	Expected, err := sh.expectedFabricHealth(Fabric, FabricProperties, Devices)
+	if err != nil {
+		LOG.Errorln(err)
+		return Health, domain.ErrFabricInternalError
+	}
+
+	Health.Healthy = true
+	for _, dev := range Devices {
+		DeviceHealth := domain.DeviceHealth{IPAddress: dev.IPAddress, Role: dev.DeviceRole}
+		State, found := States[dev.IPAddress]
+		switch {
+		case Failures[dev.IPAddress] != "":
+			DeviceHealth.Checks = failedHealthChecks(Failures[dev.IPAddress])
+		case !found:
+			DeviceHealth.Checks = failedHealthChecks("No operational state was collected from the device")
+		default:
+			DeviceHealth.Checks = checkDeviceHealth(Expected[dev.ID], State)
+		}
+		DeviceHealth.Healthy = true
+		for _, Check := range DeviceHealth.Checks {
+			if Check.Status == domain.HealthFail {
+				DeviceHealth.Healthy = false
+			}
+		}
+		Health.Healthy = Health.Healthy && DeviceHealth.Healthy
+		Health.Devices = append(Health.Devices, DeviceHealth)
+	}
+	return Health, nil
+}
+
+//VerifyFabricHealth checks the health of the fabric until it is healthy or HealthSettleTimeout elapsed, the
+//protocols taking a while to converge once configured. The result of each device is published as the health stage.
+func (sh *DeviceInteractor) VerifyFabricHealth(ctx context.Context, FabricName string) *domain.FabricHealth {
+	LOG := appcontext.Logger(ctx)
+	Start := time.Now()
+	Health, err := sh.FabricHealth(ctx, FabricName)
+	for err == nil && !Health.Healthy && time.Since(Start)+healthPollInterval <= sh.HealthSettleTimeout {
+		time.Sleep(healthPollInterval)
+		Health, err = sh.FabricHealth(ctx, FabricName)
+	}
+	if err != nil {
+		LOG.Errorf("Failed to check the health of Fabric %s: %s\n", FabricName, err)
+		return nil
+	}
+
+	Devices := make([]string, 0, len(Health.Devices))
+	for _, Device := range Health.Devices {
+		Devices = append(Devices, Device.IPAddress)
+	}
+	Progress := actions.StartStage(ctx, domain.StageHealth, Devices)
+	for _, Device := range Health.Devices {
+		for _, Check := range Device.Checks {
+			if Check.Status == domain.HealthFail {
+				Progress.Fail(actions.OperationError{Operation: "Health " + Check.Name, Host: Device.IPAddress,
+					Error: errors.New(Check.Message)})
+			}
+		}
+	}
+	Progress.Finish()
+	if !Health.Healthy {
+		LOG.Warnf("Fabric %s is not healthy after the configure\n", FabricName)
+	}
+	return &Health
+}
+
+//expectedFabricHealth returns the state expected on each device, by the ID of the device
+func (sh *DeviceInteractor) expectedFabricHealth(Fabric domain.Fabric, FabricProperties domain.FabricProperties,
+	Devices []domain.Device) (map[uint]expectedHealth, error) {
+	SwitchConfigs, err := sh.Db.GetSwitchConfigs(Fabric.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch Device Configs from %s", Fabric.Name)
+	}
+	Loopbacks := make(map[string]bool)
+	VTEPs := make(map[uint]string)
+	for _, sw := range SwitchConfigs {
+		if sw.LoopbackIP != "" {
+			Loopbacks[sw.LoopbackIP] = true
+		}
+		if sw.VTEPLoopbackIP != "" && (sw.Role == LeafRole || sw.Role == RackRole) {
+			VTEPs[sw.DeviceID] = sw.VTEPLoopbackIP
+		}
+	}
+	Overlay := FabricProperties.ConfigureOverlayGateway == "Yes"
+
+	Expected := make(map[uint]expectedHealth, len(Devices))
+	for _, dev := range Devices {
+		var Device expectedHealth
+		Device.RouteCount = len(Loopbacks)
+		Device.Bfd = FabricProperties.BFDEnable == "Yes"
+
+		Neighbors, err := sh.Db.GetBGPSwitchConfigsOnDeviceID(Fabric.ID, dev.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch the BGP neighbors of Device %s", dev.IPAddress)
+		}
+		for _, Neighbor := range Neighbors {
+			if Neighbor.ConfigType != domain.ConfigDelete {
+				Device.BgpNeighbors = appendUnique(Device.BgpNeighbors, Neighbor.RemoteIPAddress)
+			}
+		}
+		if FabricProperties.FabricType == domain.NonCLOSFabricType {
+			EvpnNeighbors, err := sh.Db.GetRackEvpnConfigOnDeviceID(dev.ID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to fetch the EVPN neighbors of Device %s", dev.IPAddress)
+			}
+			for _, Neighbor := range EvpnNeighbors {
+				if Neighbor.ConfigType != domain.ConfigDelete {
+					Device.EvpnNeighbors = appendUnique(Device.EvpnNeighbors, Neighbor.EVPNAddress)
+				}
+			}
+		} else {
+			Device.EvpnNeighbors = append(Device.EvpnNeighbors, Device.BgpNeighbors...)
+		}
+		MctNeighbors, err := sh.Db.GetMCTBGPSwitchConfigsOnDeviceID(Fabric.ID, dev.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch the MCT neighbors of Device %s", dev.IPAddress)
+		}
+		for _, Neighbor := range MctNeighbors {
+			if Neighbor.ConfigType != domain.ConfigDelete {
+				Device.EvpnNeighbors = appendUnique(Device.EvpnNeighbors, Neighbor.RemoteIPAddress)
+			}
+		}
+
+		if dev.DeviceRole == LeafRole || dev.DeviceRole == RackRole {
+			Clusters, err := sh.Db.GetMctClusters(Fabric.ID, dev.ID, []string{})
+			if err != nil {
+				return nil, fmt.Errorf("Failed to fetch the MCT clusters of Device %s", dev.IPAddress)
+			}
+			for _, Cluster := range Clusters {
+				if Cluster.ConfigType != domain.ConfigDelete {
+					Device.Mct = true
+				}
+			}
+			if Overlay {
+				for DeviceID, VTEP := range VTEPs {
+					if DeviceID != dev.ID && VTEP != VTEPs[dev.ID] {
+						Device.Tunnels = appendUnique(Device.Tunnels, VTEP)
+					}
+				}
+				sort.Strings(Device.Tunnels)
+			}
+		}
+		Expected[dev.ID] = Device
+	}
+	return Expected, nil
+}
+
+//checkDeviceHealth returns the result of each health check of a device
+func checkDeviceHealth(Expected expectedHealth, State operation.SwitchHealthResponse) []domain.HealthCheck {
+	Checks := make([]domain.HealthCheck, 0, len(domain.HealthChecks))
+	for _, Name := range domain.HealthChecks {
+		var Check domain.HealthCheck
+		switch {
+		case State.Errors[Name] != "":
+			Check = domain.HealthCheck{Status: domain.HealthFail, Message: State.Errors[Name]}
+		case Name == domain.HealthCheckBGP:
+			Check = checkBgpNeighbors(Expected.BgpNeighbors, State.BgpNeighbors)
+		case Name == domain.HealthCheckEVPN:
+			Check = checkBgpNeighbors(Expected.EvpnNeighbors, State.EvpnNeighbors)
+		case Name == domain.HealthCheckBFD:
+			Check = checkBfdSessions(Expected, State.BfdSessions)
+		case Name == domain.HealthCheckMCT:
+			Check = checkMctCluster(Expected, State)
+		case Name == domain.HealthCheckOverlay:
+			Check = checkTunnels(Expected.Tunnels, State.Tunnels)
+		case Name == domain.HealthCheckRoutes:
+			Check = checkRouteCount(Expected.RouteCount, State.RouteCount)
+		}
+		Check.Name = Name
+		Checks = append(Checks, Check)
+	}
+	return Checks
+}
+
+//failedHealthChecks returns every health check failed with the same reason
+func failedHealthChecks(Message string) []domain.HealthCheck {
+	Checks := make([]domain.HealthCheck, 0, len(domain.HealthChecks))
+	for _, Name := range domain.HealthChecks {
+		Checks = append(Checks, domain.HealthCheck{Name: Name, Status: domain.HealthFail, Message: Message})
+	}
+	return Checks
+}
+
+func checkBgpNeighbors(Expected []string, Neighbors []operation.ConfigBgpNeighbor) domain.HealthCheck {
+	if len(Expected) == 0 {
+		return domain.HealthCheck{Status: domain.HealthSkipped, Message: "No neighbor is configured"}
+	}
+	States := make(map[string]string, len(Neighbors))
+	for _, Neighbor := range Neighbors {
+		States[Neighbor.NeighborAddress] = Neighbor.State
+	}
+	var Failures []string
+	for _, Address := range Expected {
+		State, found := States[Address]
+		switch {
+		case !found:
+			Failures = append(Failures, fmt.Sprintf("neighbor %s is missing", Address))
+		case !strings.HasPrefix(strings.ToUpper(State), "ESTAB"):
+			Failures = append(Failures, fmt.Sprintf("neighbor %s is %s", Address, State))
+		}
+	}
+	if len(Failures) != 0 {
+		return domain.HealthCheck{Status: domain.HealthFail, Message: strings.Join(Failures, ", ")}
+	}
+	return domain.HealthCheck{Status: domain.HealthPass,
+		Message: fmt.Sprintf("%d of %d neighbors established", len(Expected), len(Expected))}
+}
+
+func checkBfdSessions(Expected expectedHealth, Sessions []operation.BfdSession) domain.HealthCheck {
+	if !Expected.Bfd || len(Expected.BgpNeighbors) == 0 {
+		return domain.HealthCheck{Status: domain.HealthSkipped, Message: "BFD is not enabled"}
+	}
+	States := make(map[string]string, len(Sessions))
+	for _, Session := range Sessions {
+		States[Session.NeighborAddress] = Session.State
+	}
+	var Failures []string
+	for _, Address := range Expected.BgpNeighbors {
+		State, found := States[Address]
+		switch {
+		case !found:
+			Failures = append(Failures, fmt.Sprintf("session to %s is missing", Address))
+		case !strings.EqualFold(State, "up"):
+			Failures = append(Failures, fmt.Sprintf("session to %s is %s", Address, State))
+		}
+	}
+	if len(Failures) != 0 {
+		return domain.HealthCheck{Status: domain.HealthFail, Message: strings.Join(Failures, ", ")}
+	}
+	return domain.HealthCheck{Status: domain.HealthPass,
+		Message: fmt.Sprintf("%d of %d sessions up", len(Expected.BgpNeighbors), len(Expected.BgpNeighbors))}
+}
+
+func checkMctCluster(Expected expectedHealth, State operation.SwitchHealthResponse) domain.HealthCheck {
+	if !Expected.Mct {
+		return domain.HealthCheck{Status: domain.HealthSkipped, Message: "No MCT cluster is configured"}
+	}
+	var Failures []string
+	if State.MgmtCluster.DisconnectedMemberNodeCount != "0" {
+		Failures = append(Failures, fmt.Sprintf("%s management cluster nodes are disconnected",
+			State.MgmtCluster.DisconnectedMemberNodeCount))
+	}
+	if !strings.EqualFold(State.Cluster.PeerState, "up") {
+		Failures = append(Failures, fmt.Sprintf("MCT peer %s is %s", State.Cluster.PeerIP, State.Cluster.PeerState))
+	}
+	if len(Failures) != 0 {
+		return domain.HealthCheck{Status: domain.HealthFail, Message: strings.Join(Failures, ", ")}
+	}
+	return domain.HealthCheck{Status: domain.HealthPass, Message: fmt.Sprintf("MCT peer %s is up", State.Cluster.PeerIP)}
+}
+
+func checkTunnels(Expected []string, Tunnels []operation.TunnelStatus) domain.HealthCheck {
+	if len(Expected) == 0 {
+		return domain.HealthCheck{Status: domain.HealthSkipped, Message: "No tunnel is expected"}
+	}
+	States := make(map[string]string, len(Tunnels))
+	for _, Tunnel := range Tunnels {
+		States[Tunnel.Destination] = Tunnel.OperState
+	}
+	var Failures []string
+	for _, VTEP := range Expected {
+		State, found := States[VTEP]
+		switch {
+		case !found:
+			Failures = append(Failures, fmt.Sprintf("tunnel to %s is missing", VTEP))
+		case !strings.EqualFold(State, "up"):
+			Failures = append(Failures, fmt.Sprintf("tunnel to %s is %s", VTEP, State))
+		}
+	}
+	if len(Failures) != 0 {
+		return domain.HealthCheck{Status: domain.HealthFail, Message: strings.Join(Failures, ", ")}
+	}
+	return domain.HealthCheck{Status: domain.HealthPass,
+		Message: fmt.Sprintf("%d of %d tunnels up", len(Expected), len(Expected))}
+}
+
+func checkRouteCount(Expected int, RouteCount int) domain.HealthCheck {
+	if RouteCount < Expected {
+		return domain.HealthCheck{Status: domain.HealthFail,
+			Message: fmt.Sprintf("%d routes, expected at least the %d loopbacks of the fabric", RouteCount, Expected)}
+	}
+	return domain.HealthCheck{Status: domain.HealthPass, Message: fmt.Sprintf("%d routes", RouteCount)}
+}
+
+func appendUnique(Values []string, Value string) []string {
+	if containsString(Values, Value) {
+		return Values
+	}
+	return append(Values, Value)
+}
@@ -115,6 +115,12 @@ func (sh *DeviceInteractor) computeLoopBackIP(ctx context.Context, Device *domai
 	LoopBackIPToConfigure := ""
 	LoopBackIPConfigType := domain.ConfigNone
 	LOG.Infoln("Compute Loopback", "LoopBack On Switch:", LoopBackOnDevice, ",ASN in DB:", LoopBackInDB, "Neighbor LoopbackIP:", NeighborVTEPLoopBack)
+	//A Loopback IP pinned by the operator takes precedence, the VTEP Loopback is shared by the MCT pair and is not pinned
+	if LoopBackPortNumber != sh.FabricProperties.VTEPLoopBackPortNumber {
+		if Pin, found := sh.getAllocationPin(Device.IPAddress, domain.PinTypeLoopback, ""); found {
+			return sh.computePinnedLoopBackIP(ctx, Device, LoopBackOnDevice, LoopBackInDB, InterfaceID, Pin)
+		}
+	}
 	if (Device.DeviceRole == LeafRole || Device.DeviceRole == RackRole) && LoopBackPortNumber == sh.FabricProperties.VTEPLoopBackPortNumber {
 		if NeighborVTEPLoopBack != "" {
 			LOG.Infoln("MCT Neighbor Device Found With NeighborVTEPLoopBack ", NeighborVTEPLoopBack)
@@ -223,6 +229,10 @@ func (sh *DeviceInteractor) computeASN(ctx context.Context, Device *domain.Devic
 	if CurrentRole == RackRole {
 		asnBlock = FabricProperties.RackASNBlock
 	}
+	//An ASN pinned by the operator takes precedence over the ASN on the switch and the one allocated
+	if Pin, found := sh.getPinnedASN(Device); found {
+		return sh.computePinnedASN(ctx, Device, DBSwitchConfig, OnSwitchConfig, Pin)
+	}
 	if Device.DeviceRole == LeafRole || Device.DeviceRole == RackRole {
 		if NeighborAsn != "" {
 			LOG.Infoln("MCT Neighbor Device Found With ASN ", NeighborAsn)
@@ -803,6 +813,13 @@ func (sh *DeviceInteractor) reserveOrObtainIPPair(ctx context.Context, DeviceOne
 	AllocatedOneIP := ""
 	AllocatedTwoIP := ""
 
+	//An address pinned by the operator to either end of the link takes precedence
+	if IPtype == "P2P" {
+		if Pin, PinnedOne, found := sh.getPinnedIPPair(DeviceOneID, DeviceTwoID, InterfaceOneName, InterfaceTwoName); found {
+			return sh.reservePinnedIPPair(ctx, DeviceOneID, DeviceTwoID, InterfaceOneID, InterfaceTwoID, Pin, PinnedOne)
+		}
+	}
+
 	intfOne, intOneerr := sh.Db.GetInterface(sh.FabricID, DeviceOneID, InterfaceOneType, InterfaceOneName)
 	intfTwo, intTwoerr := sh.Db.GetInterface(sh.FabricID, DeviceTwoID, InterfaceTwoType, InterfaceTwoName)
 	//fmt.Println("Int One",InterfaceOneType,InterfaceOneName,InterfaceOneID,intfOne.IPAddress)
@@ -6,64 +6,34 @@ import (
 	"efa-server/gateway/appcontext"
 	"errors"
 	"fmt"
-	"net"
-
-	"strings"
 )
 
-func (sh *DeviceInteractor) skipInvalidIPPairs(ip net.IP) bool {
-	var ret = false
-
-	if strings.HasSuffix(ip.String(), ".0") {
-		ret = true
-	}
-	if strings.HasSuffix(ip.String(), ".1") {
-		ret = true
-	}
-
-	if strings.HasSuffix(ip.String(), ".254") {
-		ret = true
+//ipPairPoolSkip returns the last octets left out of an IP pair pool, the network, the broadcast,
+//the .1 and the .254 addresses
+func ipPairPoolSkip(skip bool) func(uint64) bool {
+	if !skip {
+		return nil
 	}
-	if strings.HasSuffix(ip.String(), ".255") {
-		ret = true
+	return func(lastOctet uint64) bool {
+		return lastOctet == 0 || lastOctet == 1 || lastOctet == 254 || lastOctet == 255
 	}
-
-	return ret
 }
 
-//PopulateIPPairs populates the IPPairPool withe IP address from the network string
+//PopulateIPPairs adds the pairs of consecutive IP addresses of the network to the IPPairPool
 func (sh *DeviceInteractor) PopulateIPPairs(ctx context.Context, FabricName string, fabricID uint, network string, IPType string, skip bool) error {
 	LOG := appcontext.Logger(ctx)
 
 	LOG.Infof("Populate IP Pool(%s) of type %s", network, IPType)
 
-	ip, ipnet, err := net.ParseCIDR(network)
+	Ranges, err := networkRanges(network, ipPairPoolSkip(skip))
 	if err != nil {
 		statusMsg := fmt.Sprintf("IP Pool Range %s provided is invalid for fabric %s type %s:%s", network,
 			FabricName, IPType, err.Error())
 		LOG.Errorln(statusMsg)
 		return errors.New(statusMsg)
 	}
-	//Prepare a list of IP Address
-	IPAddressList := make([]string, 0)
-	for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); sh.inc(ip) {
-		if skip {
-			if sh.skipInvalidIPPairs(ip) {
-				LOG.Debugf("Skiping IP %s for fabric %s type %s", ip.String(), FabricName, IPType)
-				continue
-			}
-		}
-		IPAddressList = append(IPAddressList, ip.String())
-	}
-	//Populate Pooltable with IPAddress list
-	length := len(IPAddressList) / 2
-	for i := 0; i < length; i++ {
-		a := 2 * i
-		b := a + 1
-		IPEntry := domain.IPPairAllocationPool{FabricID: fabricID,
-			IPAddressOne: IPAddressList[a], IPAddressTwo: IPAddressList[b], IPType: IPType}
-		err := sh.Db.CreateIPPairEntry(&IPEntry)
-		if err != nil {
+	for _, Range := range pairRanges(Ranges) {
+		if err := sh.addToPool(fabricID, domain.PoolTypeIPPair, IPType, Range.Start, Range.End); err != nil {
 			statusMsg := fmt.Sprintf("IP Pair Pool %s initialization failed for fabric %s type %s:%s", network,
 				FabricName, IPType, err.Error())
 			LOG.Errorln(statusMsg)
@@ -74,6 +44,35 @@ func (sh *DeviceInteractor) PopulateIPPairs(ctx context.Context, FabricName stri
 	return nil
 }
 
+//ResizeIPPairs moves the IPPairPool from the old to the new network, keeping the allocated
+//IP address pairs and the pairs available in both networks. An empty network has no pairs.
+func (sh *DeviceInteractor) ResizeIPPairs(ctx context.Context, FabricName string, fabricID uint, OldNetwork string,
+	NewNetwork string, IPType string, skip bool) error {
+	LOG := appcontext.Logger(ctx)
+
+	LOG.Infof("Resize IP Pool(%s to %s) of type %s", OldNetwork, NewNetwork, IPType)
+	OldRanges := make([]valueRange, 0)
+	if len(OldNetwork) != 0 {
+		OldRanges, _ = networkRanges(OldNetwork, ipPairPoolSkip(skip))
+	}
+	NewRanges := make([]valueRange, 0)
+	if len(NewNetwork) != 0 {
+		var err error
+		if NewRanges, err = networkRanges(NewNetwork, ipPairPoolSkip(skip)); err != nil {
+			statusMsg := fmt.Sprintf("IP Pool Range %s provided is invalid for fabric %s type %s:%s", NewNetwork,
+				FabricName, IPType, err.Error())
+			LOG.Errorln(statusMsg)
+			return errors.New(statusMsg)
+		}
+	}
+	if err := sh.resizePool(fabricID, domain.PoolTypeIPPair, IPType, pairRanges(OldRanges), pairRanges(NewRanges)); err != nil {
+		statusMsg := fmt.Sprintf("IP Pair Pool resize failed for fabric %s type %s:%s", FabricName, IPType, err.Error())
+		LOG.Errorln(statusMsg)
+		return errors.New(statusMsg)
+	}
+	return nil
+}
+
 //GetAlreadyAllocatedIPPair returns the Pair of IP Address allocated to the interfaces specified by the interfaceID'ss
 func (sh *DeviceInteractor) GetAlreadyAllocatedIPPair(ctx context.Context, FabricID uint, DeviceOneID uint, DeviceTwoID uint,
 	IPType string, InterfaceOneID uint, InterfaceTwoID uint) (string, string, error) {
@@ -157,16 +156,29 @@ func (sh *DeviceInteractor) ReserveIPPair(ctx context.Context, FabricID uint, De
 //Count of Zero indicates that the entry is not in the Pool
 func (sh *DeviceInteractor) GetIPPairCountInPool(ctx context.Context, FabricID uint, ipaddressOne string, ipaddressTwo string, IPType string) (int64, domain.IPPairAllocationPool) {
 	LOG := appcontext.Logger(ctx)
+	var ipCount int64
+	IPEntry := domain.IPPairAllocationPool{FabricID: FabricID, IPType: IPType}
 
-	ipCount, IPEntry, err := sh.Db.GetIPPairEntryAndCountOnIPAddressAndType(FabricID, ipaddressOne, ipaddressTwo, IPType)
-
-	if err != nil {
+	if Value, err := ipPairToValue(ipaddressOne, ipaddressTwo); err != nil {
 		LOG.Infoln("No Entry")
+	} else if sh.isInPool(FabricID, domain.PoolTypeIPPair, IPType, Value) {
+		ipCount = 1
+		IPEntry.IPAddressOne, IPEntry.IPAddressTwo = valueToIPPair(Value)
 	}
 	LOG.Infof("IP Pool: Count of IP (%s %s) for IPType %s %d", ipaddressOne, ipaddressTwo, IPType, ipCount)
 	return ipCount, IPEntry
 }
 
+//GetIPPairCountInPoolOnEitherIP returns the count of entries in the pool holding the IP address
+func (sh *DeviceInteractor) GetIPPairCountInPoolOnEitherIP(ctx context.Context, FabricID uint, ipaddress string, IPType string) (int64, domain.IPPairAllocationPool) {
+	Value, err := ipToValue(ipaddress)
+	if err != nil {
+		return 0, domain.IPPairAllocationPool{FabricID: FabricID, IPType: IPType}
+	}
+	ipaddressOne, ipaddressTwo := valueToIPPair(Value / 2)
+	return sh.GetIPPairCountInPool(ctx, FabricID, ipaddressOne, ipaddressTwo, IPType)
+}
+
 func (sh *DeviceInteractor) removeUsedIPPairEntry(ctx context.Context, FabricID uint, DeviceOneID uint, DeviceTwoID uint, ipaddressOne string,
 	ipaddressTwo string, IPType string, InterfaceOneID uint, InterfaceTwoID uint) error {
 	LOG := appcontext.Logger(ctx)
@@ -183,10 +195,15 @@ func (sh *DeviceInteractor) removeUsedIPPairEntry(ctx context.Context, FabricID
 func (sh *DeviceInteractor) returnIPPairToPool(ctx context.Context, FabricID uint, IPType string, ipaddressOne string, ipaddressTwo string) {
 	LOG := appcontext.Logger(ctx)
 	LOG.Infof("Add IP back in Pool  for IPType %s IP (%s %s)", IPType, ipaddressOne, ipaddressTwo)
-	IPEntry := domain.IPPairAllocationPool{FabricID: FabricID,
-		IPAddressOne: ipaddressOne, IPAddressTwo: ipaddressTwo, IPType: IPType}
-
-	err := sh.Db.CreateIPPairEntry(&IPEntry)
+	Value, err := ipPairToValue(ipaddressOne, ipaddressTwo)
+	//A pinned IP pair stays reserved for the link it is pinned to
+	if err == nil && sh.isPinned(FabricID, domain.PoolTypeIPPair, IPType, Value) {
+		LOG.Infof("IP (%s %s) is pinned, keep it out of the Pool for IPType %s", ipaddressOne, ipaddressTwo, IPType)
+		return
+	}
+	if err == nil {
+		err = sh.addToPool(FabricID, domain.PoolTypeIPPair, IPType, Value, Value)
+	}
 	if err != nil {
 		LOG.Infoln(err)
 	}
@@ -195,23 +212,33 @@ func (sh *DeviceInteractor) returnIPPairToPool(ctx context.Context, FabricID uin
 func (sh *DeviceInteractor) getNextIPPairFromPool(ctx context.Context, FabricID uint, IPType string) (domain.IPPairAllocationPool, error) {
 	LOG := appcontext.Logger(ctx)
 	LOG.Infof("Fetch next IP from Pool for IPType %s", IPType)
-	var IPEntry domain.IPPairAllocationPool
-	IPEntry, err := sh.Db.GetNextIPPairEntryOnType(FabricID, IPType)
+	IPEntry := domain.IPPairAllocationPool{FabricID: FabricID, IPType: IPType}
+	Value, err := sh.nextInPool(FabricID, domain.PoolTypeIPPair, IPType)
 	if err != nil {
 		statusMsg := fmt.Sprintf("IP Exhausted for %d", FabricID)
 		LOG.Println(statusMsg)
 		return IPEntry, errors.New(statusMsg)
 	}
+	IPEntry.IPAddressOne, IPEntry.IPAddressTwo = valueToIPPair(Value)
 	LOG.Infof("Value of next IP from Pool for IPType %s IP (%s %s)", IPType, IPEntry.IPAddressOne, IPEntry.IPAddressTwo)
 	return IPEntry, nil
 }
 
+//deleteIPPairEntry removes the pair of IP addresses from the pool
+func (sh *DeviceInteractor) deleteIPPairEntry(IPEntry *domain.IPPairAllocationPool) error {
+	Value, err := ipPairToValue(IPEntry.IPAddressOne, IPEntry.IPAddressTwo)
+	if err != nil {
+		return err
+	}
+	return sh.removeFromPool(IPEntry.FabricID, domain.PoolTypeIPPair, IPEntry.IPType, Value, Value)
+}
+
 func (sh *DeviceInteractor) moveFromIPPairPoolToUsedIPPair(ctx context.Context, FabricID uint, DeviceOneID uint, DeviceTwoID uint, IPType string, InterfaceOneID uint, InterfaceTwoID uint,
 	IPEntry domain.IPPairAllocationPool) {
 	LOG := appcontext.Logger(ctx)
 
 	LOG.Infof("Delete  IP from Allocation Table for IPType %s", IPEntry.IPType)
-	if err := sh.Db.DeleteIPPairEntry(&IPEntry); err != nil {
+	if err := sh.deleteIPPairEntry(&IPEntry); err != nil {
 		LOG.Println(err)
 	}
 
@@ -228,7 +255,7 @@ func (sh *DeviceInteractor) moveReservedFromIPPairPoolToUsedIPPair(ctx context.C
 	LOG := appcontext.Logger(ctx)
 
 	LOG.Infof("Delete IP from allocation table for IPType %s", IPEntry.IPType)
-	if err := sh.Db.DeleteIPPairEntry(&IPEntry); err != nil {
+	if err := sh.deleteIPPairEntry(&IPEntry); err != nil {
 		LOG.Println(err)
 	}
 
@@ -8,16 +8,15 @@ import (
 	"fmt"
 	"github.com/jinzhu/gorm"
 	"net"
-	"strings"
 )
 
-//PopulateIP populates IPAllocationPool instances
+//PopulateIP adds the IP addresses of the network to the IPAllocationPool
 func (sh *DeviceInteractor) PopulateIP(ctx context.Context, FabricName string,
 	fabricID uint, network string, IPType string, skip bool) error {
 	LOG := appcontext.Logger(ctx)
 
 	LOG.Infof("Populate IP Pool(%s) of type %s", network, IPType)
-	ip, ipnet, err := net.ParseCIDR(network)
+	Ranges, err := networkRanges(network, ipPoolSkip(IPType, skip))
 	if err != nil {
 		statusMsg := fmt.Sprintf("IP Pool Range %s provided is invalid for fabric %s type %s:%s", network,
 			FabricName, IPType, err.Error())
@@ -25,23 +24,8 @@ func (sh *DeviceInteractor) PopulateIP(ctx context.Context, FabricName string,
 		return errors.New(statusMsg)
 	}
 
-	for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); sh.inc(ip) {
-		if skip && strings.HasSuffix(ip.String(), ".0") {
-			continue
-		}
-		if skip && strings.HasSuffix(ip.String(), ".254") {
-			continue
-		}
-		if skip && strings.HasSuffix(ip.String(), ".255") {
-			continue
-		}
-		if skip && IPType == "MCTLink" && strings.HasSuffix(ip.String(), ".1") {
-			continue
-		}
-		IPEntry := domain.IPAllocationPool{FabricID: fabricID,
-			IPAddress: ip.String(), IPType: IPType}
-		err := sh.Db.CreateIPEntry(&IPEntry)
-		if err != nil {
+	for _, Range := range Ranges {
+		if err := sh.addToPool(fabricID, domain.PoolTypeIP, IPType, Range.Start, Range.End); err != nil {
 			LOG.Println(err)
 			statusMsg := fmt.Sprintf("IP Pool Initialization Failed for type %s", IPType)
 			LOG.Infoln(statusMsg)
@@ -52,6 +36,41 @@ func (sh *DeviceInteractor) PopulateIP(ctx context.Context, FabricName string,
 	return nil
 }
 
+//ResizeIP moves the IPAllocationPool from the old to the new network, keeping the allocated
+//IP addresses and the IP addresses available in both networks
+func (sh *DeviceInteractor) ResizeIP(ctx context.Context, FabricName string,
+	fabricID uint, OldNetwork string, NewNetwork string, IPType string, skip bool) error {
+	LOG := appcontext.Logger(ctx)
+
+	LOG.Infof("Resize IP Pool(%s to %s) of type %s", OldNetwork, NewNetwork, IPType)
+	OldRanges, _ := networkRanges(OldNetwork, ipPoolSkip(IPType, skip))
+	NewRanges, err := networkRanges(NewNetwork, ipPoolSkip(IPType, skip))
+	if err != nil {
+		statusMsg := fmt.Sprintf("IP Pool Range %s provided is invalid for fabric %s type %s:%s", NewNetwork,
+			FabricName, IPType, err.Error())
+		LOG.Errorln(statusMsg)
+		return errors.New(statusMsg)
+	}
+	if err := sh.resizePool(fabricID, domain.PoolTypeIP, IPType, OldRanges, NewRanges); err != nil {
+		LOG.Println(err)
+		statusMsg := fmt.Sprintf("IP Pool Resize Failed for type %s", IPType)
+		LOG.Infoln(statusMsg)
+		return errors.New(statusMsg)
+	}
+	return nil
+}
+
+//ipPoolSkip returns the last octets left out of an IP pool, the network, the broadcast and .254 addresses
+//and the .1 address of the MCT link addresses
+func ipPoolSkip(IPType string, skip bool) func(uint64) bool {
+	if !skip {
+		return nil
+	}
+	return func(lastOctet uint64) bool {
+		return lastOctet == 0 || lastOctet == 254 || lastOctet == 255 || (IPType == "MCTLink" && lastOctet == 1)
+	}
+}
+
 func (sh *DeviceInteractor) inc(ip net.IP) {
 	for j := len(ip) - 1; j >= 0; j-- {
 		ip[j]++
@@ -132,10 +151,13 @@ func (sh *DeviceInteractor) ReserveIP(ctx context.Context, FabricID uint, Device
 //GetIPCountInPool gets the count of IP from the pool, for a given IP address and IPType
 func (sh *DeviceInteractor) GetIPCountInPool(ctx context.Context, FabricID uint, ipaddress string, IPType string) (int64, domain.IPAllocationPool) {
 	LOG := appcontext.Logger(ctx)
-	ipCount, IPEntry, err := sh.Db.GetIPEntryAndCountOnIPAddressAndType(FabricID, ipaddress, IPType)
+	var ipCount int64
+	IPEntry := domain.IPAllocationPool{FabricID: FabricID, IPAddress: ipaddress, IPType: IPType}
 
-	if err != nil {
+	if Value, err := ipToValue(ipaddress); err != nil {
 		LOG.Infoln("No Entry")
+	} else if sh.isInPool(FabricID, domain.PoolTypeIP, IPType, Value) {
+		ipCount = 1
 	}
 	LOG.Infof("IP Pool: Count of  IP %s for IPType  %s %d", ipaddress, IPType, ipCount)
 	return ipCount, IPEntry
@@ -162,10 +184,15 @@ func (sh *DeviceInteractor) returnIPToPool(ctx context.Context, FabricID uint, I
 	LOG := appcontext.Logger(ctx)
 
 	LOG.Infof("Add IP %s back in Pool for IPType %s", ipAddress, IPType)
-	IPEntry := domain.IPAllocationPool{FabricID: FabricID,
-		IPAddress: ipAddress, IPType: IPType}
-
-	err := sh.Db.CreateIPEntry(&IPEntry)
+	Value, err := ipToValue(ipAddress)
+	//A pinned IP stays reserved for the device it is pinned to
+	if err == nil && sh.isPinned(FabricID, domain.PoolTypeIP, IPType, Value) {
+		LOG.Infof("IP %s is pinned, keep it out of the Pool for IPType %s", ipAddress, IPType)
+		return
+	}
+	if err == nil {
+		err = sh.addToPool(FabricID, domain.PoolTypeIP, IPType, Value, Value)
+	}
 	if err != nil {
 		LOG.Infoln(err)
 	}
@@ -175,13 +202,14 @@ func (sh *DeviceInteractor) returnIPToPool(ctx context.Context, FabricID uint, I
 func (sh *DeviceInteractor) getNextIPFromPool(ctx context.Context, FabricID uint, IPType string) (domain.IPAllocationPool, error) {
 	LOG := appcontext.Logger(ctx)
 	LOG.Infof("Fetch next IP from Pool for  IPType %s", IPType)
-	var IPEntry domain.IPAllocationPool
-	IPEntry, err := sh.Db.GetNextIPEntryOnType(FabricID, IPType)
+	IPEntry := domain.IPAllocationPool{FabricID: FabricID, IPType: IPType}
+	Value, err := sh.nextInPool(FabricID, domain.PoolTypeIP, IPType)
 	if err != nil {
 		statusMsg := fmt.Sprintf("IP Exhausted for %d", FabricID)
 		LOG.Println(statusMsg)
 		return IPEntry, errors.New(statusMsg)
 	}
+	IPEntry.IPAddress = valueToIP(Value)
 	LOG.Infof("Value of next IP from Pool for IPType %s IP %s", IPType, IPEntry.IPAddress)
 	return IPEntry, nil
 }
@@ -190,7 +218,11 @@ func (sh *DeviceInteractor) moveFromIPPoolToUsedIP(ctx context.Context, FabricID
 	IPEntry domain.IPAllocationPool) {
 	LOG := appcontext.Logger(ctx)
 	LOG.Infof("Delete IP from allocation table IPType %s", IPEntry.IPType)
-	if err := sh.Db.DeleteIPEntry(&IPEntry); err != nil {
+	Value, err := ipToValue(IPEntry.IPAddress)
+	if err == nil {
+		err = sh.removeFromPool(FabricID, domain.PoolTypeIP, IPEntry.IPType, Value, Value)
+	}
+	if err != nil {
 		LOG.Println(err)
 	}
 
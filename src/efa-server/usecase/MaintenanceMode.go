@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	"errors"
+	"fmt"
+)
+
+//UpdateMaintenanceMode drains the traffic from a device and records it as being in maintenance mode,
+//or restores the traffic and takes it out of maintenance mode. The mode is recorded only once the
+//BGP sessions of the device have converged, so a failed request can simply be retried
+func (sh *DeviceInteractor) UpdateMaintenanceMode(ctx context.Context, FabricName string, IPAddress string,
+	Enable bool) (string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Update Maintenance Mode")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	var Fabric domain.Fabric
+	var Device domain.Device
+	var err error
+
+	if Fabric, err = sh.Db.GetFabric(FabricName); err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricNotFound
+	}
+	if Device, err = sh.Db.GetDevice(FabricName, IPAddress); err != nil {
+		statusMsg := fmt.Sprintf("Device %s is not registered with Fabric %s", IPAddress, FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrDeviceNotFound
+	}
+
+	//Draining both the nodes of an MCT pair would isolate the hosts behind them
+	if Enable {
+		for _, Peer := range sh.getMctPeerDevices(ctx, Fabric.ID, Device.ID) {
+			if Peer.MaintenanceMode {
+				statusMsg := fmt.Sprintf("MCT peer %s of Device %s is already in maintenance mode", Peer.IPAddress, IPAddress)
+				LOG.Errorln(statusMsg)
+				return statusMsg, domain.ErrFabricIncorrectValues
+			}
+		}
+	}
+
+	config, err := sh.GetActionRequestObject(ctx, FabricName, false)
+	if err != nil {
+		return err.Error(), domain.ErrFabricInternalError
+	}
+	Hosts := make([]operation.ConfigSwitch, 0, 1)
+	for _, host := range config.Hosts {
+		if host.Host == IPAddress {
+			sh.populateMctPeerAddresses(ctx, FabricName, &host)
+			Hosts = append(Hosts, host)
+		}
+	}
+	if len(Hosts) == 0 {
+		statusMsg := fmt.Sprintf("Device %s is not configured in Fabric %s", IPAddress, FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricIncorrectValues
+	}
+	config.Hosts = Hosts
+
+	if Errors := sh.FabricAdapter.ConfigureMaintenanceMode(ctx, config, Enable); len(Errors) != 0 {
+		statusMsg := fmt.Sprintf("Operation[%s] failed on Device %s: %s", Errors[0].Operation, IPAddress, Errors[0].Error)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+
+	if err = sh.saveMaintenanceMode(ctx, &Device, Enable); err != nil {
+		statusMsg := fmt.Sprintf("Failed to save Maintenance Mode for %s", IPAddress)
+		LOG.Errorln(statusMsg, err)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+	if Enable {
+		return fmt.Sprintf("Maintenance Mode enabled on Device %s", IPAddress), nil
+	}
+	return fmt.Sprintf("Maintenance Mode disabled on Device %s", IPAddress), nil
+}
+
+func (sh *DeviceInteractor) saveMaintenanceMode(ctx context.Context, Device *domain.Device, Enable bool) error {
+	RollBack := true
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return err
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	Device.MaintenanceMode = Enable
+	if err := sh.Db.SaveDevice(Device); err != nil {
+		return err
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return nil
+}
+
+//getMctPeerDevices returns the devices which form an MCT pair with the given device
+func (sh *DeviceInteractor) getMctPeerDevices(ctx context.Context, FabricID uint, DeviceID uint) []domain.Device {
+	Peers := make([]domain.Device, 0)
+	MctNeighbors, _ := sh.GetMCTBGPSwitchConfigs(ctx, FabricID, DeviceID)
+	for _, neigh := range MctNeighbors {
+		if Peer, err := sh.Db.GetDeviceUsingDeviceID(FabricID, neigh.RemoteDeviceID); err == nil {
+			Peers = append(Peers, Peer)
+		}
+	}
+	return Peers
+}
+
+//checkMaintenanceMode refuses the configuration of the devices which are in maintenance mode. When forced the
+//devices are taken out of maintenance mode instead, as the configuration pushed to them restores their traffic
+func (sh *DeviceInteractor) checkMaintenanceMode(ctx context.Context, FabricName string, IPAddressList []string,
+	force bool) ([]AddDeviceResponse, error) {
+	LOG := appcontext.Logger(ctx)
+	AddDeviceResponseList := make([]AddDeviceResponse, 0)
+
+	for _, IPAddress := range IPAddressList {
+		Device, err := sh.Db.GetDevice(FabricName, IPAddress)
+		if err != nil || !Device.MaintenanceMode {
+			continue
+		}
+		if force {
+			LOG.Infof("Force option enabled, Device %s is taken out of maintenance mode", IPAddress)
+			if err = sh.saveMaintenanceMode(ctx, &Device, false); err != nil {
+				statusMsg := fmt.Sprintf("Failed to save Maintenance Mode for %s", IPAddress)
+				LOG.Errorln(statusMsg, err)
+				return []AddDeviceResponse{AddDeviceResponse{FabricName: FabricName, IPAddress: IPAddress,
+					Role: Device.DeviceRole, Errors: []error{errors.New(statusMsg)}}}, domain.ErrFabricInternalError
+			}
+			continue
+		}
+		statusMsg := fmt.Sprintf("Device %s is in maintenance mode, disable maintenance mode or use force", IPAddress)
+		LOG.Errorln(statusMsg)
+		AddDeviceResponseList = append(AddDeviceResponseList, AddDeviceResponse{FabricName: FabricName,
+			IPAddress: IPAddress, Role: Device.DeviceRole, Errors: []error{errors.New(statusMsg)}})
+	}
+	if len(AddDeviceResponseList) != 0 {
+		return AddDeviceResponseList, domain.ErrFabricDeviceInMaintenance
+	}
+	return AddDeviceResponseList, nil
+}
+
+//maintenanceModeErrors returns an error for each switch to be configured which is in maintenance mode
+func maintenanceModeErrors(config operation.ConfigFabricRequest) []actions.OperationError {
+	Errors := make([]actions.OperationError, 0)
+	for _, host := range config.Hosts {
+		if host.MaintenanceMode {
+			Errors = append(Errors, actions.OperationError{Operation: "Maintenance Mode", Host: host.Host,
+				Error: fmt.Errorf("Device %s is in maintenance mode, disable maintenance mode or use force", host.Host)})
+		}
+	}
+	return Errors
+}
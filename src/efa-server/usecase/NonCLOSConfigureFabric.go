@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	"efa-server/infra/metrics"
+	"time"
 )
 import "context"
 import (
@@ -29,6 +32,11 @@ type Rack struct {
 	IP2 string
 }
 
+//address returns the address of the rack in the responses of its stages
+func (Rack Rack) address() string {
+	return fmt.Sprintln(Rack.IP1, ",", Rack.IP2)
+}
+
 //DeviceSwitchConfigMapTable Device and Switch Config Details
 type DeviceSwitchConfigMapTable struct {
 	Device domain.Device
@@ -57,6 +65,15 @@ func (sh *DeviceInteractor) AddRacks(ctx context.Context, FabricName string, Rac
 	//Fetch the existing Pairs already registered
 	existingRack, err := sh.fetchRegisteredRacks(ctx, FabricName)
 
+	//Devices in maintenance mode are configured only when forced
+	rackIPAddress := make([]string, 0)
+	for _, rack := range append(existingRack, RackList...) {
+		rackIPAddress = append(rackIPAddress, rack.IP1, rack.IP2)
+	}
+	if addDeviceResponse, err = sh.checkMaintenanceMode(ctx, FabricName, rackIPAddress, force); err != nil {
+		return
+	}
+
 	//If force is enabled clear up the configuration on devices specified in the IP address
 	if force {
 		LOG.Infoln("Force option enabled on Racks", RackList)
@@ -179,8 +196,8 @@ func (sh *DeviceInteractor) AddRacks(ctx context.Context, FabricName string, Rac
 	Password = ""
 	for index, stageFunction := range stageFunctions {
 		LOG.Println("Executing Stage ", index+1)
-		addDeviceResponse, err = sh.executeAddRackStage(ctx, FabricName, totalPairList, UserName,
-			Password, force, stageFunction)
+		addDeviceResponse, err = sh.executeAddRackStage(ctx, FabricName, discoveryStages[index], totalPairList,
+			UserName, Password, force, stageFunction)
 		if err != nil {
 			//Operation failed so Rollback the Database
 			RollBack = true
@@ -295,10 +312,18 @@ func (sh *DeviceInteractor) pairsAlreadyRegisteredWithDifferentRack(RackList []R
 	return AddDeviceResponseList, overallError
 }
 
-func (sh *DeviceInteractor) executeAddRackStage(ctx context.Context, FabricName string, RackList []Rack,
+func (sh *DeviceInteractor) executeAddRackStage(ctx context.Context, FabricName string, Stage string, RackList []Rack,
 	UserName string, Password string, force bool, function rackstageFunction) ([]AddDeviceResponse, error) {
 
 	var fabricGate sync.WaitGroup
+	//The progress is reported per device of the racks
+	Racks := make(map[string]Rack, len(RackList))
+	Devices := make([]string, 0, 2*len(RackList))
+	for _, rack := range RackList {
+		Racks[rack.address()] = rack
+		Devices = append(Devices, rack.IP1, rack.IP2)
+	}
+	Progress := actions.StartStage(ctx, Stage, Devices)
 
 	AddDeviceResponseList := make([]AddDeviceResponse, 0, len(RackList))
 
@@ -321,6 +346,14 @@ func (sh *DeviceInteractor) executeAddRackStage(ctx context.Context, FabricName
 			overallOperationFailed = true
 		}
 
+		rack := Racks[result.IPAddress]
+		for _, err := range result.Errors {
+			Progress.Fail(actions.OperationError{Operation: Stage, Error: err, Host: rack.IP1})
+			Progress.Fail(actions.OperationError{Operation: Stage, Error: err, Host: rack.IP2})
+		}
+		Progress.Succeed(rack.IP1)
+		Progress.Succeed(rack.IP2)
+
 		AddDeviceResponseList = append(AddDeviceResponseList, result)
 	}
 	if overallOperationFailed {
@@ -331,11 +364,12 @@ func (sh *DeviceInteractor) executeAddRackStage(ctx context.Context, FabricName
 
 func (sh *DeviceInteractor) addSingleRackFirstStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, Rack Rack, UserName string, Password string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddRackFirstStage", time.Now())
 
 	var buffer bytes.Buffer
 
 	err := sh.AddRackFirstStage(ctx, FabricName, Rack.IP1, Rack.IP2, UserName, Password)
-	IPAddress := fmt.Sprintln(Rack.IP1, ",", Rack.IP2)
+	IPAddress := Rack.address()
 	Response := AddDeviceResponse{IPAddress: IPAddress, FabricName: FabricName, FabricID: sh.FabricID, Role: RackRole}
 	if err != nil {
 		buffer.WriteString(fmt.Sprintf("Addition of %s device with ip-address = %s [Failed]\n", RackRole, IPAddress))
@@ -349,11 +383,12 @@ func (sh *DeviceInteractor) addSingleRackFirstStage(ctx context.Context, fabricG
 }
 func (sh *DeviceInteractor) addSingleRackSecondStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, Rack Rack, UserName string, Password string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddRackSecondStage", time.Now())
 
 	var buffer bytes.Buffer
 
 	err := sh.AddRackSecondStage(ctx, FabricName, Rack.IP1, Rack.IP2, UserName, Password)
-	IPAddress := fmt.Sprintln(Rack.IP1, ",", Rack.IP2)
+	IPAddress := Rack.address()
 	Response := AddDeviceResponse{IPAddress: IPAddress, FabricName: FabricName, FabricID: sh.FabricID, Role: RackRole}
 	if err != nil {
 		buffer.WriteString(fmt.Sprintf("Addition of %s device with ip-address = %s [Failed]\n", RackRole, IPAddress))
@@ -367,11 +402,12 @@ func (sh *DeviceInteractor) addSingleRackSecondStage(ctx context.Context, fabric
 }
 func (sh *DeviceInteractor) addSingleRackThirdStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, Rack Rack, UserName string, Password string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddRackThirdStage", time.Now())
 
 	var buffer bytes.Buffer
 
 	err := sh.AddRackThirdStage(ctx, FabricName, Rack.IP1, Rack.IP2, UserName, Password)
-	IPAddress := fmt.Sprintln(Rack.IP1, ",", Rack.IP2)
+	IPAddress := Rack.address()
 	Response := AddDeviceResponse{IPAddress: IPAddress, FabricName: FabricName, FabricID: sh.FabricID, Role: RackRole}
 	if err != nil {
 		buffer.WriteString(fmt.Sprintf("Addition of %s device with ip-address = %s [Failed]\n", RackRole, IPAddress))
@@ -386,11 +422,12 @@ func (sh *DeviceInteractor) addSingleRackThirdStage(ctx context.Context, fabricG
 
 func (sh *DeviceInteractor) addSingleRackFourthStage(ctx context.Context, fabricGate *sync.WaitGroup, ResultChannel chan AddDeviceResponse, FabricName string, Rack Rack, UserName string, Password string) {
 	defer fabricGate.Done()
+	defer metrics.ObserveDeviceAction("AddRackFourthStage", time.Now())
 
 	var buffer bytes.Buffer
 
 	err := sh.AddRackFourthStage(ctx, FabricName, Rack.IP1, Rack.IP2, UserName, Password)
-	IPAddress := fmt.Sprintln(Rack.IP1, ",", Rack.IP2)
+	IPAddress := Rack.address()
 	Response := AddDeviceResponse{IPAddress: IPAddress, FabricName: FabricName, FabricID: sh.FabricID, Role: RackRole}
 	if err != nil {
 		buffer.WriteString(fmt.Sprintf("Addition of %s device with ip-address = %s [Failed]\n", RackRole, IPAddress))
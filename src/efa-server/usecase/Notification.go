@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	Interactor "efa-server/usecase/interactorinterface"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//AddNotificationSubscription validates and records a webhook endpoint receiving the events of the server.
+//A subscription without events receives all of them.
+func (sh *DeviceInteractor) AddNotificationSubscription(ctx context.Context,
+	Subscription domain.NotificationSubscription) (domain.NotificationSubscription, string, error) {
+	LOG := appcontext.Logger(ctx)
+
+	if statusMsg := validateNotificationSubscription(Subscription); len(statusMsg) != 0 {
+		LOG.Errorln(statusMsg)
+		return Subscription, statusMsg, domain.ErrSubscriptionInvalid
+	}
+	Subscription.CreatedAt = time.Now()
+	if err := sh.Db.CreateNotificationSubscription(&Subscription); err != nil {
+		statusMsg := fmt.Sprintf("Failed to record the notification subscription: %s", err)
+		LOG.Errorln(statusMsg)
+		return Subscription, statusMsg, err
+	}
+	return Subscription, "", nil
+}
+
+//validateNotificationSubscription returns why the subscription is invalid, empty when it is valid
+func validateNotificationSubscription(Subscription domain.NotificationSubscription) string {
+	URL, err := url.Parse(Subscription.URL)
+	if err != nil || (URL.Scheme != "http" && URL.Scheme != "https") || len(URL.Host) == 0 {
+		return fmt.Sprintf("Invalid URL %q, an http or https URL is expected", Subscription.URL)
+	}
+	for _, Event := range Subscription.Events {
+		if !isNotificationEvent(Event) {
+			return fmt.Sprintf("Unknown event %q, the events are %s", Event,
+				strings.Join(domain.NotificationEvents, ", "))
+		}
+	}
+	if Subscription.Retries > domain.MaxNotificationRetries {
+		return fmt.Sprintf("Invalid retries %d, at most %d retries are allowed", Subscription.Retries,
+			domain.MaxNotificationRetries)
+	}
+	return ""
+}
+
+func isNotificationEvent(Event string) bool {
+	for _, Known := range domain.NotificationEvents {
+		if Event == Known {
+			return true
+		}
+	}
+	return false
+}
+
+//GetNotificationSubscriptions returns the notification subscriptions
+func (sh *DeviceInteractor) GetNotificationSubscriptions(ctx context.Context) ([]domain.NotificationSubscription, string, error) {
+	Subscriptions, err := sh.Db.GetNotificationSubscriptions()
+	if err != nil {
+		statusMsg := fmt.Sprintf("Failed to fetch the notification subscriptions: %s", err)
+		appcontext.Logger(ctx).Errorln(statusMsg)
+		return Subscriptions, statusMsg, err
+	}
+	return Subscriptions, "", nil
+}
+
+//getNotificationSubscription returns the notification subscription of the given ID
+func (sh *DeviceInteractor) getNotificationSubscription(ctx context.Context, ID uint) (domain.NotificationSubscription, string, error) {
+	Subscription, err := sh.Db.GetNotificationSubscription(ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Failed to fetch the notification subscription %d: %s", ID, err)
+		if gorm.IsRecordNotFoundError(err) {
+			statusMsg, err = fmt.Sprintf("Notification subscription %d does not exist", ID), domain.ErrSubscriptionNotFound
+		}
+		appcontext.Logger(ctx).Errorln(statusMsg)
+		return Subscription, statusMsg, err
+	}
+	return Subscription, "", nil
+}
+
+//DeleteNotificationSubscription deletes the notification subscription of the given ID
+func (sh *DeviceInteractor) DeleteNotificationSubscription(ctx context.Context, ID uint) (domain.NotificationSubscription, string, error) {
+	Subscription, statusMsg, err := sh.getNotificationSubscription(ctx, ID)
+	if err != nil {
+		return Subscription, statusMsg, err
+	}
+	if err = sh.Db.DeleteNotificationSubscription(ID); err != nil {
+		statusMsg = fmt.Sprintf("Failed to delete the notification subscription %d: %s", ID, err)
+		appcontext.Logger(ctx).Errorln(statusMsg)
+		return Subscription, statusMsg, err
+	}
+	return Subscription, "", nil
+}
+
+//TestNotificationSubscription posts a test event to the endpoint of the subscription and waits for the endpoint.
+//The event is not retried.
+func (sh *DeviceInteractor) TestNotificationSubscription(ctx context.Context, ID uint) (domain.NotificationEvent, string, error) {
+	Event := newNotificationEvent(ctx, domain.EventNotificationTest)
+	Event.Message = "Test notification"
+	Subscription, statusMsg, err := sh.getNotificationSubscription(ctx, ID)
+	if err != nil {
+		return Event, statusMsg, err
+	}
+	if sh.Notifier == nil {
+		return Event, "Notifications are not enabled", domain.ErrNotificationDeliveryFailed
+	}
+	Subscription.Retries = 0
+	if err = sh.Notifier.Deliver(ctx, Subscription, Event); err != nil {
+		statusMsg = fmt.Sprintf("Delivery to %s failed: %s", Subscription.URL, err)
+		appcontext.Logger(ctx).Errorln(statusMsg)
+		return Event, statusMsg, domain.ErrNotificationDeliveryFailed
+	}
+	return Event, "", nil
+}
+
+//newNotificationEvent returns an event of the request of the context
+func newNotificationEvent(ctx context.Context, Event string) domain.NotificationEvent {
+	ExecutionID, _ := ctx.Value(appcontext.RequestIDKey).(string)
+	return domain.NotificationEvent{ID: uuid.New().String(), Event: Event, Time: time.Now(), ExecutionID: ExecutionID}
+}
+
+//notify delivers the event in the background to the subscriptions subscribing to it
+func (sh *DeviceInteractor) notify(ctx context.Context, Event domain.NotificationEvent) {
+	if sh.Notifier == nil {
+		return
+	}
+	LOG := appcontext.Logger(ctx)
+	Subscriptions, err := sh.Db.GetNotificationSubscriptions()
+	if err != nil {
+		LOG.Errorln("Failed to fetch the notification subscriptions", err)
+		return
+	}
+	for _, Subscription := range Subscriptions {
+		if !Subscription.Subscribes(Event.Event) {
+			continue
+		}
+		go func(Subscription domain.NotificationSubscription) {
+			if err := sh.Notifier.Deliver(ctx, Subscription, Event); err != nil {
+				LOG.Errorf("Delivery of %s event %s to %s failed: %s", Event.Event, Event.ID, Subscription.URL, err)
+			}
+		}(Subscription)
+	}
+}
+
+//NotifyExecution notifies the start, the completion or the failure of an execution, with the errors of the devices
+func (sh *DeviceInteractor) NotifyExecution(ctx context.Context, Event string, Execution domain.ExecutionLog,
+	FabricName string, Errors []domain.DeviceError) {
+	Notification := newNotificationEvent(ctx, Event)
+	Notification.ExecutionID, Notification.Command, Notification.Status = Execution.UUID, Execution.Command, Execution.Status
+	Notification.Fabric, Notification.Errors = FabricName, Errors
+	sh.notify(ctx, Notification)
+}
+
+//notifyMissingLinks notifies the links found missing by the validation of a fabric
+func (sh *DeviceInteractor) notifyMissingLinks(ctx context.Context, FabricName string, MissingLinks []string) {
+	if len(MissingLinks) == 0 {
+		return
+	}
+	Event := newNotificationEvent(ctx, domain.EventValidationMissingLinks)
+	Event.Fabric, Event.MissingLinks = FabricName, MissingLinks
+	Event.Message = fmt.Sprintf("%d links are missing", len(MissingLinks))
+	sh.notify(ctx, Event)
+}
+
+//NotifyCredentialFailures returns the device adapter factory notifying the failures to log into the devices
+func (sh *DeviceInteractor) NotifyCredentialFailures(
+	Factory func(ctx context.Context, IPAddress string, UserName string, Password string) (Interactor.DeviceAdapter, error),
+) func(ctx context.Context, IPAddress string, UserName string, Password string) (Interactor.DeviceAdapter, error) {
+	return func(ctx context.Context, IPAddress string, UserName string, Password string) (Interactor.DeviceAdapter, error) {
+		Adapter, err := Factory(ctx, IPAddress, UserName, Password)
+		if CredentialsError, ok := err.(*domain.DeviceCredentialsError); ok {
+			Event := newNotificationEvent(ctx, domain.EventDeviceCredentialsFailed)
+			Event.Device, Event.Message = CredentialsError.Device, CredentialsError.Error()
+			Event.Errors = []domain.DeviceError{{Device: CredentialsError.Device, Operation: "Login",
+				Message: fmt.Sprintf("Login as %s failed: %s", UserName, CredentialsError.Error())}}
+			sh.notify(ctx, Event)
+		}
+		return Adapter, err
+	}
+}
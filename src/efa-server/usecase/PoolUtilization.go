@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+//DefaultPoolWarningThreshold is the pool utilization in percent reported when the fabric setting is not set
+const DefaultPoolWarningThreshold = 80
+
+//PoolAllocation describes a value of a pool held by an interface of a device
+type PoolAllocation struct {
+	Value         string
+	IPAddress     string
+	InterfaceName string
+}
+
+//PoolUtilization describes how much of an allocation pool is used
+type PoolUtilization struct {
+	PoolType string
+	PoolName string
+	//Range configured for the pool in the fabric settings
+	Range string
+	Used  uint64
+	Free  uint64
+	//Utilization in percent of the values of the pool
+	Utilization uint64
+	Allocations []PoolAllocation
+}
+
+//PoolUtilizationResponse is a response object which describes the utilization of the allocation pools of a fabric
+type PoolUtilizationResponse struct {
+	FabricName string
+	FabricID   uint
+	Threshold  uint64
+	Pools      []PoolUtilization
+	//Warnings lists the pools whose utilization reached the threshold
+	Warnings []string
+}
+
+//poolDefinition describes an allocation pool of the fabric and the setting holding its range
+type poolDefinition struct {
+	PoolType string
+	PoolName string
+	Range    string
+}
+
+//fabricPools returns the allocation pools used by the fabric type
+func fabricPools(prop domain.FabricProperties) []poolDefinition {
+	if prop.FabricType == domain.NonCLOSFabricType {
+		return []poolDefinition{
+			{domain.PoolTypeASN, RackRole, prop.RackASNBlock},
+			{domain.PoolTypeIP, "Loopback", prop.LoopBackIPRange},
+			{domain.PoolTypeIPPair, domain.RackL3LoopBackPoolName, prop.MCTL3LBIPRange},
+			{domain.PoolTypeIPPair, domain.MCTPoolName, prop.MCTLinkIPRange},
+		}
+	}
+	Pools := []poolDefinition{
+		{domain.PoolTypeASN, SpineRole, prop.SpineASNBlock},
+		{domain.PoolTypeASN, LeafRole, prop.LeafASNBlock},
+		{domain.PoolTypeIP, "Loopback", prop.LoopBackIPRange},
+	}
+	//P2P Pool holds IP Pairs only if the type is Numbered
+	if prop.P2PIPType == domain.P2PIpTypeNumbered {
+		Pools = append(Pools, poolDefinition{domain.PoolTypeIPPair, "P2P", prop.P2PLinkRange})
+	}
+	return append(Pools, poolDefinition{domain.PoolTypeIPPair, domain.MCTPoolName, prop.MCTLinkIPRange})
+}
+
+//poolWarningThreshold returns the threshold of the fabric settings, or the default if it is not set
+func poolWarningThreshold(prop domain.FabricProperties) uint64 {
+	Threshold, err := strconv.ParseUint(prop.PoolWarningThreshold, 10, 64)
+	if err != nil || Threshold == 0 || Threshold > 100 {
+		return DefaultPoolWarningThreshold
+	}
+	return Threshold
+}
+
+//GetPoolUtilization returns the range, the used and free counts and the allocations of each pool of the fabric
+func (sh *DeviceInteractor) GetPoolUtilization(ctx context.Context, FabricName string) (PoolUtilizationResponse, error) {
+	LOG := appcontext.Logger(ctx)
+	Response := PoolUtilizationResponse{FabricName: FabricName}
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		LOG.Printf("Unable to retrieve Fabric for %s", FabricName)
+		return Response, domain.ErrFabricNotFound
+	}
+	Response.FabricID = Fabric.ID
+	FabricProperties, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric Properties for %s", FabricName)
+		LOG.Println(statusMsg)
+		return Response, errors.New(statusMsg)
+	}
+	Response.Threshold = poolWarningThreshold(FabricProperties)
+
+	Holders, err := sh.getAllocationHolders(Fabric.ID)
+	if err != nil {
+		return Response, err
+	}
+
+	Response.Pools = make([]PoolUtilization, 0)
+	Response.Warnings = make([]string, 0)
+	for _, Pool := range fabricPools(FabricProperties) {
+		Utilization, err := sh.getPoolUtilization(Fabric.ID, Pool, Holders)
+		if err != nil {
+			LOG.Println(err)
+			return Response, err
+		}
+		Response.Pools = append(Response.Pools, Utilization)
+		if Warning := poolWarning(Utilization, Response.Threshold); Warning != "" {
+			Response.Warnings = append(Response.Warnings, Warning)
+		}
+	}
+	return Response, nil
+}
+
+//GetPoolUtilizationMetrics returns the utilization of the pools of a fabric for the metrics. Only the DBMutex is
+//taken, for the pools not to be read in the middle of a transaction: a scrape does not wait for the switches
+//a configure is pushing to, as it would on the RestLock.
+func (sh *DeviceInteractor) GetPoolUtilizationMetrics(ctx context.Context, FabricName string) (PoolUtilizationResponse, error) {
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	return sh.GetPoolUtilization(ctx, FabricName)
+}
+
+//poolWarning returns the warning for a pool whose utilization reached the threshold.
+//A pool of a single value, such as the Spine ASN, is shared by all the devices and is never reported.
+func poolWarning(Pool PoolUtilization, Threshold uint64) string {
+	if Pool.Used+Pool.Free <= 1 {
+		return ""
+	}
+	if Pool.Free == 0 {
+		return fmt.Sprintf("%s pool %s is exhausted, all %d values are used", Pool.PoolType, Pool.PoolName, Pool.Used)
+	}
+	if Pool.Utilization < Threshold {
+		return ""
+	}
+	return fmt.Sprintf("%s pool %s is %d%% used, %d of %d values are free", Pool.PoolType, Pool.PoolName,
+		Pool.Utilization, Pool.Free, Pool.Used+Pool.Free)
+}
+
+//getPoolWarnings returns the warnings of the pools of the fabric whose utilization reached the threshold,
+//failures to compute the utilization are logged and not reported as they must not stop the operation
+func (sh *DeviceInteractor) getPoolWarnings(ctx context.Context, FabricName string) []string {
+	LOG := appcontext.Logger(ctx)
+	Response, err := sh.GetPoolUtilization(ctx, FabricName)
+	if err != nil {
+		LOG.Infof("Unable to compute the pool utilization of %s: %s", FabricName, err)
+		return nil
+	}
+	if len(Response.Warnings) == 0 {
+		return nil
+	}
+	for _, Warning := range Response.Warnings {
+		LOG.Warnln(Warning)
+	}
+	return Response.Warnings
+}
+
+//allocationHolders maps the devices and interfaces holding the allocations to their names
+type allocationHolders struct {
+	Devices    map[uint]string
+	Interfaces map[uint]string
+}
+
+func (sh *DeviceInteractor) getAllocationHolders(FabricID uint) (allocationHolders, error) {
+	Holders := allocationHolders{Devices: make(map[uint]string), Interfaces: make(map[uint]string)}
+	Devices, err := sh.Db.GetDevicesInFabric(FabricID)
+	if err != nil {
+		return Holders, err
+	}
+	for _, Device := range Devices {
+		Holders.Devices[Device.ID] = Device.IPAddress
+		Interfaces, err := sh.Db.GetInterfacesonDevice(FabricID, Device.ID)
+		if err != nil {
+			return Holders, err
+		}
+		for _, Interface := range Interfaces {
+			Holders.Interfaces[Interface.ID] = Interface.IntType + " " + Interface.IntName
+		}
+	}
+	return Holders, nil
+}
+
+//getPoolUtilization returns the utilization of a pool, a value held by several devices is counted once
+func (sh *DeviceInteractor) getPoolUtilization(FabricID uint, Pool poolDefinition,
+	Holders allocationHolders) (PoolUtilization, error) {
+	Utilization := PoolUtilization{PoolType: Pool.PoolType, PoolName: Pool.PoolName, Range: Pool.Range,
+		Allocations: make([]PoolAllocation, 0)}
+	Values := make(map[string]bool)
+	//The last ASN of a role stays in the pool once allocated, so that it can be shared
+	var SharedASNs uint64
+
+	switch Pool.PoolType {
+	case domain.PoolTypeASN:
+		UsedASNs, err := sh.Db.GetUsedASNsOnRole(FabricID, Pool.PoolName)
+		if err != nil {
+			return Utilization, err
+		}
+		for _, UsedASN := range UsedASNs {
+			Value := strconv.FormatUint(UsedASN.ASN, 10)
+			if !Values[Value] && sh.isInPool(FabricID, domain.PoolTypeASN, Pool.PoolName, UsedASN.ASN) {
+				SharedASNs++
+			}
+			Values[Value] = true
+			Utilization.Allocations = append(Utilization.Allocations,
+				PoolAllocation{Value: Value, IPAddress: Holders.Devices[UsedASN.DeviceID]})
+		}
+	case domain.PoolTypeIP:
+		UsedIPs, err := sh.Db.GetUsedIPsOnType(FabricID, Pool.PoolName)
+		if err != nil {
+			return Utilization, err
+		}
+		for _, UsedIP := range UsedIPs {
+			Values[UsedIP.IPAddress] = true
+			Utilization.Allocations = append(Utilization.Allocations,
+				PoolAllocation{Value: UsedIP.IPAddress, IPAddress: Holders.Devices[UsedIP.DeviceID],
+					InterfaceName: Holders.Interfaces[UsedIP.InterfaceID]})
+		}
+	case domain.PoolTypeIPPair:
+		UsedIPPairs, err := sh.Db.GetUsedIPPairsOnType(FabricID, Pool.PoolName)
+		if err != nil {
+			return Utilization, err
+		}
+		for _, UsedIPPair := range UsedIPPairs {
+			Values[UsedIPPair.IPAddressOne+","+UsedIPPair.IPAddressTwo] = true
+			Utilization.Allocations = append(Utilization.Allocations,
+				PoolAllocation{Value: UsedIPPair.IPAddressOne, IPAddress: Holders.Devices[UsedIPPair.DeviceOneID],
+					InterfaceName: Holders.Interfaces[UsedIPPair.InterfaceOneID]},
+				PoolAllocation{Value: UsedIPPair.IPAddressTwo, IPAddress: Holders.Devices[UsedIPPair.DeviceTwoID],
+					InterfaceName: Holders.Interfaces[UsedIPPair.InterfaceTwoID]})
+		}
+	}
+
+	Free, err := sh.poolSize(FabricID, Pool.PoolType, Pool.PoolName)
+	if err != nil {
+		return Utilization, err
+	}
+	Utilization.Used = uint64(len(Values))
+	Utilization.Free = Free - SharedASNs
+	if Total := Utilization.Used + Utilization.Free; Total != 0 {
+		Utilization.Utilization = Utilization.Used * 100 / Total
+	}
+	return Utilization, nil
+}
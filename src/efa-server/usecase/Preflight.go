@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//preflightDevice is the state of a device collected by the pre-flight checks
+type preflightDevice struct {
+	Device  domain.Device
+	Detail  domain.DeviceDetail
+	LocalAS string
+	//Interfaces and Speeds are keyed by the type and the name of the interface
+	Interfaces map[string]domain.Interface
+	Speeds     map[string]int
+	//Mtus are the MTU configured on the ethernet interfaces keyed by their name, the other interfaces run
+	//SystemMtu, the MTU configure sets on the device
+	Mtus      map[string]int
+	SystemMtu int
+}
+
+func preflightInterfaceKey(InterfaceType string, InterfaceName string) string {
+	return InterfaceType + " " + InterfaceName
+}
+
+//ValidateFabric validates the topology of the fabric and runs the pre-flight checks.
+//The errors of the overridden checks are reported but no longer fail the validation.
+func (sh *DeviceInteractor) ValidateFabric(ctx context.Context, FabricName string,
+	Overrides []string) (ValidateFabricResponse, error) {
+	for _, Check := range Overrides {
+		if !containsString(domain.PreflightChecks, Check) {
+			appcontext.Logger(ctx).Errorf("Unknown pre-flight check %s, the checks are %s\n", Check,
+				strings.Join(domain.PreflightChecks, ", "))
+			return ValidateFabricResponse{}, domain.ErrPreflightOverrideInvalid
+		}
+	}
+	FabricValidateResponse, err := sh.ValidateFabricTopology(ctx, FabricName)
+	if err != nil {
+		return FabricValidateResponse, err
+	}
+	if FabricValidateResponse.Preflight, err = sh.preflightChecks(ctx, FabricName); err != nil {
+		appcontext.Logger(ctx).Errorf("Failed to run the pre-flight checks of Fabric %s: %s\n", FabricName, err)
+		return FabricValidateResponse, err
+	}
+	for iter := range FabricValidateResponse.Preflight {
+		Finding := &FabricValidateResponse.Preflight[iter]
+		Finding.Overridden = containsString(Overrides, Finding.Check)
+	}
+	return FabricValidateResponse, nil
+}
+
+//preflightChecks collects the state of the devices of the fabric and reports the link speeds and MTUs, the firmware,
+//the shut down interfaces and the configuration of the devices which configure would replace
+func (sh *DeviceInteractor) preflightChecks(ctx context.Context, FabricName string) ([]domain.PreflightFinding, error) {
+	LOG := appcontext.Logger(ctx)
+	Findings := make([]domain.PreflightFinding, 0)
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		return Findings, domain.ErrFabricNotFound
+	}
+	FabricProperties, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		return Findings, err
+	}
+	Devices, err := sh.Db.GetDevicesInFabric(Fabric.ID)
+	if err != nil {
+		return Findings, err
+	}
+	sort.Slice(Devices, func(i, j int) bool { return Devices[i].IPAddress < Devices[j].IPAddress })
+
+	Links := make([]domain.LLDPNeighbor, 0)
+	for _, Device := range Devices {
+		Neighbors, err := sh.Db.GetLLDPNeighborsOnDeviceExcludingMarkedForDeletion(Fabric.ID, Device.ID)
+		if err != nil {
+			return Findings, err
+		}
+		Links = append(Links, Neighbors...)
+	}
+
+	//The state of the devices is collected concurrently, one session per device
+	var deviceGate sync.WaitGroup
+	Collected := make([]*preflightDevice, len(Devices))
+	Failures := make([]error, len(Devices))
+	for iter := range Devices {
+		deviceGate.Add(1)
+		go func(iter int) {
+			defer deviceGate.Done()
+			Collected[iter], Failures[iter] = sh.collectPreflightDevice(ctx, Devices[iter], FabricProperties.ControlVE,
+				Links)
+		}(iter)
+	}
+	deviceGate.Wait()
+
+	States := make(map[uint]*preflightDevice, len(Devices))
+	for iter, Device := range Devices {
+		if Failures[iter] != nil {
+			LOG.Errorf("Pre-flight checks of %s failed: %s\n", Device.IPAddress, Failures[iter])
+			Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckConnection,
+				Severity: domain.PreflightError, Device: Device.IPAddress,
+				Message: fmt.Sprintf("Unable to collect the state of the device: %s", Failures[iter])})
+			continue
+		}
+		State := Collected[iter]
+		DeviceSettings, _ := sh.Db.GetDeviceSettings(Fabric.ID, Device.ID)
+		//An MTU which is not set is not compared
+		State.SystemMtu, _ = strconv.Atoi(MergeDeviceSettings(FabricProperties, DeviceSettings).MTU)
+		States[Device.ID] = State
+	}
+
+	Findings = append(Findings, checkPreflightLinks(Links, States)...)
+	Findings = append(Findings, checkPreflightSpineFirmware(Devices, States)...)
+	MctFindings, err := sh.checkPreflightMct(ctx, Fabric.ID, Devices, States)
+	if err != nil {
+		return Findings, err
+	}
+	Findings = append(Findings, MctFindings...)
+	for _, Device := range Devices {
+		State, found := States[Device.ID]
+		if !found {
+			continue
+		}
+		DeviceFindings, err := sh.checkPreflightConfig(Fabric.ID, State)
+		if err != nil {
+			return Findings, err
+		}
+		Findings = append(Findings, DeviceFindings...)
+	}
+
+	sort.SliceStable(Findings, func(i, j int) bool {
+		if Findings[i].Severity != Findings[j].Severity {
+			return Findings[i].Severity == domain.PreflightError
+		}
+		return Findings[i].Check < Findings[j].Check
+	})
+	return Findings, nil
+}
+
+//collectPreflightDevice fetches the firmware, the local AS, the interfaces, the MTU configured on the interfaces
+//and the speed of the interfaces of the links of the device
+func (sh *DeviceInteractor) collectPreflightDevice(ctx context.Context, Device domain.Device, ControlVE string,
+	Links []domain.LLDPNeighbor) (*preflightDevice, error) {
+	DeviceAdapter, err := sh.DeviceAdapterFactory(ctx, Device.IPAddress, Device.UserName, Device.Password)
+	if err != nil {
+		return nil, err
+	}
+	defer DeviceAdapter.CloseConnection(ctx)
+
+	State := &preflightDevice{Device: Device, Interfaces: make(map[string]domain.Interface),
+		Speeds: make(map[string]int), Mtus: make(map[string]int)}
+	if State.Detail, err = DeviceAdapter.GetDeviceDetail(Device.FabricID, Device.ID, Device.IPAddress); err != nil {
+		return nil, err
+	}
+	if State.LocalAS, err = DeviceAdapter.GetASN(Device.FabricID, Device.ID); err != nil {
+		return nil, err
+	}
+	Interfaces, err := DeviceAdapter.GetInterfaces(Device.FabricID, Device.ID, ControlVE)
+	if err != nil {
+		return nil, err
+	}
+	for _, Interface := range Interfaces {
+		State.Interfaces[preflightInterfaceKey(Interface.IntType, Interface.IntName)] = Interface
+	}
+	if State.Mtus, err = DeviceAdapter.GetInterfaceMtus(); err != nil {
+		return nil, err
+	}
+	for _, Link := range Links {
+		InterfaceType, InterfaceName := Link.InterfaceOneType, Link.InterfaceOneName
+		if Link.DeviceTwoID == Device.ID {
+			InterfaceType, InterfaceName = Link.InterfaceTwoType, Link.InterfaceTwoName
+		} else if Link.DeviceOneID != Device.ID {
+			continue
+		}
+		//A speed which cannot be fetched is not compared
+		if Speed, err := DeviceAdapter.GetInterfaceSpeed(InterfaceType, InterfaceName); err == nil {
+			State.Speeds[preflightInterfaceKey(InterfaceType, InterfaceName)] = Speed
+		}
+	}
+	return State, nil
+}
+
+//mtuOf returns the MTU of the interface of a link end and whether it is configured on the interface
+func mtuOf(State *preflightDevice, InterfaceType string, InterfaceName string) (int, bool) {
+	if Mtu, found := State.Mtus[InterfaceName]; found && InterfaceType == domain.IntfTypeEthernet {
+		return Mtu, true
+	}
+	return State.SystemMtu, false
+}
+
+//checkPreflightLinks reports the links whose ends run at different speeds or MTUs, the interfaces of the links
+//whose configured MTU differs from the MTU setting, and the interfaces of the links which are shut down
+func checkPreflightLinks(Links []domain.LLDPNeighbor, States map[uint]*preflightDevice) []domain.PreflightFinding {
+	Findings := make([]domain.PreflightFinding, 0)
+	Checked := make(map[string]bool)
+	for _, Link := range Links {
+		One, OneFound := States[Link.DeviceOneID]
+		Two, TwoFound := States[Link.DeviceTwoID]
+		if !OneFound || !TwoFound {
+			continue
+		}
+		OneKey := preflightInterfaceKey(Link.InterfaceOneType, Link.InterfaceOneName)
+		TwoKey := preflightInterfaceKey(Link.InterfaceTwoType, Link.InterfaceTwoName)
+		OnePort := One.Device.IPAddress + " " + Link.InterfaceOneName
+		TwoPort := Two.Device.IPAddress + " " + Link.InterfaceTwoName
+		//The links between two devices are stored from both devices
+		if Checked[OnePort+TwoPort] || Checked[TwoPort+OnePort] {
+			continue
+		}
+		Checked[OnePort+TwoPort] = true
+
+		OneSpeed, TwoSpeed := One.Speeds[OneKey], Two.Speeds[TwoKey]
+		if OneSpeed != 0 && TwoSpeed != 0 && OneSpeed != TwoSpeed {
+			Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckSpeed,
+				Severity: domain.PreflightError, Device: One.Device.IPAddress, Interface: Link.InterfaceOneName,
+				Message: fmt.Sprintf("%s runs at %d, %s at %d", OnePort, OneSpeed, TwoPort, TwoSpeed)})
+		}
+		OneMtu, OneConfigured := mtuOf(One, Link.InterfaceOneType, Link.InterfaceOneName)
+		TwoMtu, TwoConfigured := mtuOf(Two, Link.InterfaceTwoType, Link.InterfaceTwoName)
+		if OneMtu != 0 && TwoMtu != 0 && OneMtu != TwoMtu {
+			Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckMTU,
+				Severity: domain.PreflightError, Device: One.Device.IPAddress, Interface: Link.InterfaceOneName,
+				Message: fmt.Sprintf("%s has MTU %d, %s MTU %d", OnePort, OneMtu, TwoPort, TwoMtu)})
+		} else {
+			//The ends agree, but the MTU configured on them is kept by configure in place of the MTU setting
+			for _, End := range []struct {
+				State      *preflightDevice
+				Name       string
+				Mtu        int
+				Configured bool
+			}{{One, Link.InterfaceOneName, OneMtu, OneConfigured}, {Two, Link.InterfaceTwoName, TwoMtu, TwoConfigured}} {
+				if End.Configured && End.State.SystemMtu != 0 && End.Mtu != End.State.SystemMtu {
+					Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckMTU,
+						Severity: domain.PreflightWarning, Device: End.State.Device.IPAddress, Interface: End.Name,
+						Message: fmt.Sprintf("%s %s has MTU %d configured, the MTU setting is %d",
+							End.State.Device.IPAddress, End.Name, End.Mtu, End.State.SystemMtu)})
+				}
+			}
+		}
+		for _, End := range []struct {
+			State *preflightDevice
+			Key   string
+			Name  string
+		}{{One, OneKey, Link.InterfaceOneName}, {Two, TwoKey, Link.InterfaceTwoName}} {
+			if strings.EqualFold(End.State.Interfaces[End.Key].ConfigState, "down") {
+				Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckAdminDown,
+					Severity: domain.PreflightWarning, Device: End.State.Device.IPAddress, Interface: End.Name,
+					Message: fmt.Sprintf("%s %s is shut down", End.State.Device.IPAddress, End.Name)})
+			}
+		}
+	}
+	return Findings
+}
+
+//modelOf returns the model of the device, the one discovered when the device was added when it cannot be fetched
+func modelOf(State *preflightDevice) string {
+	if len(State.Detail.Model) != 0 {
+		return State.Detail.Model
+	}
+	return State.Device.Model
+}
+
+//firmwareOf returns the firmware running on the device, the one discovered when the device was added
+//when it cannot be fetched
+func firmwareOf(State *preflightDevice) string {
+	if len(State.Detail.FirmwareVersion) != 0 {
+		return State.Detail.FirmwareVersion
+	}
+	return State.Device.FirmwareVersion
+}
+
+//checkPreflightSpineFirmware warns when the spines do not all run the same firmware
+func checkPreflightSpineFirmware(Devices []domain.Device, States map[uint]*preflightDevice) []domain.PreflightFinding {
+	Firmwares := make([]string, 0)
+	Spines := make([]string, 0)
+	for _, Device := range Devices {
+		State, found := States[Device.ID]
+		if !found || Device.DeviceRole != SpineRole {
+			continue
+		}
+		Firmwares = appendUnique(Firmwares, firmwareOf(State))
+		Spines = append(Spines, fmt.Sprintf("%s runs %s", Device.IPAddress, firmwareOf(State)))
+	}
+	if len(Firmwares) < 2 {
+		return []domain.PreflightFinding{}
+	}
+	return []domain.PreflightFinding{{Check: domain.PreflightCheckFirmware, Severity: domain.PreflightWarning,
+		Message: fmt.Sprintf("The spines run different firmware: %s", strings.Join(Spines, ", "))}}
+}
+
+//checkPreflightMct reports the MCT pairs whose devices are not compatible
+func (sh *DeviceInteractor) checkPreflightMct(ctx context.Context, FabricID uint, Devices []domain.Device,
+	States map[uint]*preflightDevice) ([]domain.PreflightFinding, error) {
+	Findings := make([]domain.PreflightFinding, 0)
+	Checked := make(map[[2]uint]bool)
+	for _, Device := range Devices {
+		Clusters, err := sh.Db.GetMctClusters(FabricID, Device.ID, []string{})
+		if err != nil {
+			return Findings, err
+		}
+		for _, Cluster := range Clusters {
+			One, OneFound := States[Cluster.DeviceID]
+			Two, TwoFound := States[Cluster.MCTNeighborDeviceID]
+			if !OneFound || !TwoFound || Checked[[2]uint{Cluster.MCTNeighborDeviceID, Cluster.DeviceID}] {
+				continue
+			}
+			Checked[[2]uint{Cluster.DeviceID, Cluster.MCTNeighborDeviceID}] = true
+			if !sh.FabricAdapter.IsMCTLeavesCompatible(ctx, modelOf(One), modelOf(Two)) {
+				Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckFirmware,
+					Severity: domain.PreflightError, Device: One.Device.IPAddress,
+					Message: fmt.Sprintf("The MCT pair %s and %s is not compatible, the models are %s and %s",
+						One.Device.IPAddress, Two.Device.IPAddress, modelOf(One), modelOf(Two))})
+			}
+		}
+	}
+	return Findings, nil
+}
+
+//checkPreflightConfig reports the IP addresses of the interfaces and the local AS of the device which configure
+//would replace
+func (sh *DeviceInteractor) checkPreflightConfig(FabricID uint, State *preflightDevice) ([]domain.PreflightFinding, error) {
+	Findings := make([]domain.PreflightFinding, 0)
+	Device := State.Device
+
+	//The devices whose configuration is not generated yet have nothing to compare with
+	if SwitchConfig, err := sh.Db.GetSwitchConfigOnFabricIDAndDeviceID(FabricID, Device.ID); err == nil {
+		if len(State.LocalAS) != 0 && len(SwitchConfig.LocalAS) != 0 && State.LocalAS != SwitchConfig.LocalAS {
+			Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckLocalAS,
+				Severity: domain.PreflightError, Device: Device.IPAddress,
+				Message: fmt.Sprintf("%s router bgp has local-as %s, configure replaces it with %s", Device.IPAddress,
+					State.LocalAS, SwitchConfig.LocalAS)})
+		}
+	}
+
+	InterfaceConfigs, err := sh.Db.GetInterfaceSwitchConfigsOnDeviceID(FabricID, Device.ID)
+	if err != nil {
+		return Findings, err
+	}
+	for _, Config := range InterfaceConfigs {
+		if Config.IntType != domain.IntfTypeEthernet || Config.ConfigType == domain.ConfigDelete {
+			continue
+		}
+		Configured := State.Interfaces[preflightInterfaceKey(Config.IntType, Config.IntName)].IPAddress
+		if len(Configured) == 0 {
+			continue
+		}
+		Planned := "ip unnumbered " + Config.DonorType + " " + Config.DonorName
+		if len(Config.DonorType) == 0 {
+			Planned = Config.IPAddress + "/31"
+			if ConfiguredIP, _, err := net.ParseCIDR(Configured); err == nil && ConfiguredIP.String() == Config.IPAddress {
+				continue
+			}
+		}
+		Findings = append(Findings, domain.PreflightFinding{Check: domain.PreflightCheckIPConfig,
+			Severity: domain.PreflightError, Device: Device.IPAddress, Interface: Config.IntName,
+			Message: fmt.Sprintf("%s %s has IP address %s, configure replaces it with %s", Device.IPAddress,
+				Config.IntName, Configured, Planned)})
+	}
+	return Findings, nil
+}
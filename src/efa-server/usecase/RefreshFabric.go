@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"efa-server/infra/device/actions"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//RefreshFabricResponse describes the links which changed since the last discovery of the fabric
+type RefreshFabricResponse struct {
+	FabricName   string
+	AddedLinks   []string
+	RemovedLinks []string
+	MovedLinks   []string
+	Errors       []actions.OperationError
+}
+
+//linkChanges holds the links added and removed by a refresh, keyed by linkKey
+type linkChanges struct {
+	Added   map[string]domain.LLDPNeighbor
+	Removed map[string]domain.LLDPNeighbor
+}
+
+func (response *RefreshFabricResponse) addError(Operation string, Host string, statusMsg string) {
+	response.Errors = append(response.Errors, actions.OperationError{Operation: Operation,
+		Error: errors.New(statusMsg), Host: Host})
+}
+
+//RefreshFabric re-reads the interfaces and LLDP of the devices and reports the links added, removed or moved
+//since the last discovery. P2P IP pairs are allocated and released for the changed links, and only the interface
+//and BGP neighbor configs on the two ends of each changed link are pushed. When IPAddress is set only the links
+//of that device are refreshed.
+func (sh *DeviceInteractor) RefreshFabric(ctx context.Context, FabricName string, IPAddress string) (RefreshFabricResponse, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Refresh Fabric")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+	response := RefreshFabricResponse{FabricName: FabricName, AddedLinks: []string{}, RemovedLinks: []string{},
+		MovedLinks: []string{}}
+
+	if _, err := sh.Db.GetFabric(FabricName); err != nil {
+		statusMsg := fmt.Sprintf("Fabric %s does not exist", FabricName)
+		LOG.Errorln(statusMsg)
+		response.addError("Refresh Fabric", IPAddress, statusMsg)
+		return response, domain.ErrFabricNotFound
+	}
+	if _, err := sh.fetchFabricDetails(ctx, FabricName, []string{}); err != nil {
+		response.addError("Refresh Fabric", IPAddress, err.Error())
+		return response, domain.ErrFabricInternalError
+	}
+	if sh.FabricProperties.FabricType == domain.NonCLOSFabricType {
+		statusMsg := fmt.Sprintf("Refresh is not supported on %s fabric %s", domain.NonCLOSFabricType, FabricName)
+		LOG.Errorln(statusMsg)
+		response.addError("Refresh Fabric", IPAddress, statusMsg)
+		return response, domain.ErrFabricIncorrectValues
+	}
+
+	var Device *domain.Device
+	if len(IPAddress) != 0 {
+		dev, err := sh.Db.GetDevice(FabricName, IPAddress)
+		if err != nil {
+			statusMsg := fmt.Sprintf("Device %s is not in fabric %s", IPAddress, FabricName)
+			LOG.Errorln(statusMsg)
+			response.addError("Refresh Fabric", IPAddress, statusMsg)
+			return response, domain.ErrDeviceNotFound
+		}
+		Device = &dev
+	}
+	if sh.Db.GetDevicesCountInFabric(sh.FabricID) == 0 {
+		statusMsg := fmt.Sprintf("No devices are in the fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		response.addError("Refresh Fabric", IPAddress, statusMsg)
+		return response, domain.ErrFabricIncorrectValues
+	}
+
+	Changes, Errors, err := sh.refreshTopology(ctx, FabricName, Device)
+	if err != nil {
+		response.Errors = Errors
+		return response, domain.ErrFabricInternalError
+	}
+	sh.describeLinkChanges(ctx, &response, Changes)
+	if len(Changes.Added) == 0 && len(Changes.Removed) == 0 {
+		LOG.Infoln("No link changes found")
+		return response, nil
+	}
+
+	config, err := sh.GetActionRequestObject(ctx, FabricName, false)
+	if err != nil {
+		response.addError("Refresh Fabric", IPAddress, err.Error())
+		return response, domain.ErrFabricInternalError
+	}
+	sh.filterRefreshConfig(ctx, &config, Changes)
+
+	//The discovered changes stay pending on failure, so that the next configure pushes them
+	if Errors := sh.FabricAdapter.ConfigureLinks(ctx, config, true); len(Errors) != 0 {
+		response.Errors = Errors
+		return response, domain.ErrFabricInternalError
+	}
+
+	if err := sh.settleLinkChanges(ctx, Changes); err != nil {
+		response.addError("Clean up DB Failed", IPAddress, err.Error())
+		return response, domain.ErrFabricInternalError
+	}
+	//On Success backup the DB
+	if err := sh.Db.Backup(); err != nil {
+		LOG.Printf("Failed to backup DB during Refresh %s\n", err)
+	}
+	return response, nil
+}
+
+//refreshTopology re-discovers the interfaces and LLDP of the devices and rebuilds their neighbor relationships and
+//interface configs. With a Device only the device and the devices cabled to it, before or after the refresh, are
+//re-discovered.
+func (sh *DeviceInteractor) refreshTopology(ctx context.Context, FabricName string,
+	Device *domain.Device) (linkChanges, []actions.OperationError, error) {
+	LOG := appcontext.Logger(ctx)
+	var Changes linkChanges
+	Errors := make([]actions.OperationError, 0)
+
+	Devices, err := sh.Db.GetDevicesInFabric(sh.FabricID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Failed to fetch devices from %s", FabricName)
+		Errors = append(Errors, actions.OperationError{Operation: "Refresh Topology", Error: errors.New(statusMsg)})
+		return Changes, Errors, errors.New(statusMsg)
+	}
+	OldLinks := sh.getFabricLinks(Devices)
+
+	RollBack := true
+	if err := sh.Db.OpenTransaction(); err != nil {
+		Errors = append(Errors, actions.OperationError{Operation: "Refresh Topology", Error: err})
+		return Changes, Errors, err
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	//First is the index of the first of the functions in the discovery stages
+	runStages := func(Scope []domain.Device, First int, functions ...stageFunction) error {
+		LeafList, SpineList := make([]string, 0), make([]string, 0)
+		for _, dev := range Scope {
+			if dev.DeviceRole == SpineRole {
+				SpineList = append(SpineList, dev.IPAddress)
+			} else {
+				LeafList = append(LeafList, dev.IPAddress)
+			}
+		}
+		for index, function := range functions {
+			Responses, err := sh.executeAddDeviceStage(ctx, FabricName, discoveryStages[First+index], LeafList,
+				SpineList, "", "", false, function)
+			for _, Response := range Responses {
+				for _, rerr := range Response.Errors {
+					Errors = append(Errors, actions.OperationError{Operation: "Refresh Topology", Error: rerr,
+						Host: Response.IPAddress})
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	Scope := Devices
+	if Device != nil {
+		//The links of a device are built from the LLDP of the devices at the other end, so the old and new
+		//peers of the device are re-discovered along with it
+		if err := runStages([]domain.Device{*Device}, 0, sh.addSingleDeviceFirstStage, sh.addSingleDeviceSecondStage); err != nil {
+			return Changes, Errors, err
+		}
+		Peers := sh.getRefreshPeers(ctx, Device, OldLinks)
+		if err := runStages(Peers, 0, sh.addSingleDeviceFirstStage, sh.addSingleDeviceSecondStage); err != nil {
+			return Changes, Errors, err
+		}
+		Scope = append([]domain.Device{*Device}, Peers...)
+		LOG.Infoln("Refresh scope", len(Scope), "devices")
+	} else {
+		if err := runStages(Scope, 0, sh.addSingleDeviceFirstStage, sh.addSingleDeviceSecondStage); err != nil {
+			return Changes, Errors, err
+		}
+	}
+	if err := runStages(Scope, 2, sh.addSingleDeviceThirdStage, sh.addSingleDeviceFourthStage); err != nil {
+		return Changes, Errors, err
+	}
+
+	NewLinks := sh.getFabricLinks(Devices)
+	Changes.Added = make(map[string]domain.LLDPNeighbor)
+	Changes.Removed = make(map[string]domain.LLDPNeighbor)
+	for key, link := range NewLinks {
+		if _, ok := OldLinks[key]; !ok {
+			Changes.Added[key] = link
+		}
+	}
+	for key, link := range OldLinks {
+		if _, ok := NewLinks[key]; !ok {
+			Changes.Removed[key] = link
+		}
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return Changes, Errors, nil
+}
+
+//getRefreshPeers returns the devices cabled to the device, as recorded in the DB and as seen by its fresh LLDP
+func (sh *DeviceInteractor) getRefreshPeers(ctx context.Context, Device *domain.Device,
+	Links map[string]domain.LLDPNeighbor) []domain.Device {
+	PeerIDs := make(map[uint]bool)
+	for _, link := range Links {
+		if link.DeviceOneID == Device.ID {
+			PeerIDs[link.DeviceTwoID] = true
+		}
+		if link.DeviceTwoID == Device.ID {
+			PeerIDs[link.DeviceOneID] = true
+		}
+	}
+	LLDPs, _ := sh.Db.GetLLDPsonDevice(sh.FabricID, Device.ID)
+	for _, lldp := range LLDPs {
+		if Interface, err := sh.Db.GetInterfaceOnMac(lldp.RemoteIntMac, sh.FabricID); err == nil {
+			PeerIDs[Interface.DeviceID] = true
+		}
+	}
+	delete(PeerIDs, Device.ID)
+
+	Peers := make([]domain.Device, 0, len(PeerIDs))
+	for ID := range PeerIDs {
+		if Peer, err := sh.Db.GetDeviceUsingDeviceID(sh.FabricID, ID); err == nil {
+			Peers = append(Peers, Peer)
+		}
+	}
+	return Peers
+}
+
+//getFabricLinks returns the links of the devices which are not marked for delete, keyed by linkKey
+func (sh *DeviceInteractor) getFabricLinks(Devices []domain.Device) map[string]domain.LLDPNeighbor {
+	Links := make(map[string]domain.LLDPNeighbor)
+	for _, dev := range Devices {
+		Neighbors, _ := sh.Db.GetLLDPNeighborsOnEitherDevice(sh.FabricID, dev.ID)
+		for _, neighbor := range Neighbors {
+			if neighbor.ConfigType != domain.ConfigDelete {
+				Links[linkKey(neighbor)] = neighbor
+			}
+		}
+	}
+	return Links
+}
+
+//linkKey identifies a link independently of the direction in which it was discovered
+func linkKey(Neighbor domain.LLDPNeighbor) string {
+	if Neighbor.InterfaceOneID > Neighbor.InterfaceTwoID {
+		return fmt.Sprint(Neighbor.InterfaceTwoID, "-", Neighbor.InterfaceOneID)
+	}
+	return fmt.Sprint(Neighbor.InterfaceOneID, "-", Neighbor.InterfaceTwoID)
+}
+
+//describeLinkChanges fills the response with the changed links. A removed and an added link which share an
+//interface are reported as a moved link.
+func (sh *DeviceInteractor) describeLinkChanges(ctx context.Context, response *RefreshFabricResponse, Changes linkChanges) {
+	DeviceMap, _ := sh.prepareMapDeviceIDToDevice(ctx)
+	end := func(DeviceID uint, IntType string, IntName string) string {
+		return fmt.Sprintf("%s %s %s", DeviceMap[DeviceID].IPAddress, IntType, IntName)
+	}
+	link := func(Neighbor domain.LLDPNeighbor) string {
+		return fmt.Sprintf("%s - %s", end(Neighbor.DeviceOneID, Neighbor.InterfaceOneType, Neighbor.InterfaceOneName),
+			end(Neighbor.DeviceTwoID, Neighbor.InterfaceTwoType, Neighbor.InterfaceTwoName))
+	}
+
+	Moved := make(map[string]bool)
+	for addedKey, added := range Changes.Added {
+		for removedKey, removed := range Changes.Removed {
+			if Moved[removedKey] {
+				continue
+			}
+			var Fixed, From, To string
+			switch {
+			case added.InterfaceOneID == removed.InterfaceOneID:
+				Fixed = end(added.DeviceOneID, added.InterfaceOneType, added.InterfaceOneName)
+				From = end(removed.DeviceTwoID, removed.InterfaceTwoType, removed.InterfaceTwoName)
+				To = end(added.DeviceTwoID, added.InterfaceTwoType, added.InterfaceTwoName)
+			case added.InterfaceOneID == removed.InterfaceTwoID:
+				Fixed = end(added.DeviceOneID, added.InterfaceOneType, added.InterfaceOneName)
+				From = end(removed.DeviceOneID, removed.InterfaceOneType, removed.InterfaceOneName)
+				To = end(added.DeviceTwoID, added.InterfaceTwoType, added.InterfaceTwoName)
+			case added.InterfaceTwoID == removed.InterfaceOneID:
+				Fixed = end(added.DeviceTwoID, added.InterfaceTwoType, added.InterfaceTwoName)
+				From = end(removed.DeviceTwoID, removed.InterfaceTwoType, removed.InterfaceTwoName)
+				To = end(added.DeviceOneID, added.InterfaceOneType, added.InterfaceOneName)
+			case added.InterfaceTwoID == removed.InterfaceTwoID:
+				Fixed = end(added.DeviceTwoID, added.InterfaceTwoType, added.InterfaceTwoName)
+				From = end(removed.DeviceOneID, removed.InterfaceOneType, removed.InterfaceOneName)
+				To = end(added.DeviceOneID, added.InterfaceOneType, added.InterfaceOneName)
+			default:
+				continue
+			}
+			Moved[addedKey] = true
+			Moved[removedKey] = true
+			response.MovedLinks = append(response.MovedLinks, fmt.Sprintf("%s moved from %s to %s", Fixed, From, To))
+			break
+		}
+	}
+	for key, added := range Changes.Added {
+		if !Moved[key] {
+			response.AddedLinks = append(response.AddedLinks, link(added))
+		}
+	}
+	for key, removed := range Changes.Removed {
+		if !Moved[key] {
+			response.RemovedLinks = append(response.RemovedLinks, link(removed))
+		}
+	}
+	sort.Strings(response.AddedLinks)
+	sort.Strings(response.RemovedLinks)
+	sort.Strings(response.MovedLinks)
+}
+
+//filterRefreshConfig keeps only the devices at the ends of the changed links, and on them only the pending
+//interface and BGP neighbor configs of the changed links
+func (sh *DeviceInteractor) filterRefreshConfig(ctx context.Context, config *operation.ConfigFabricRequest,
+	Changes linkChanges) {
+	DeviceMap, _ := sh.prepareMapDeviceIDToDevice(ctx)
+	//Changed interfaces and BGP neighbor addresses keyed by device IP
+	Interfaces := make(map[string]map[string]bool)
+	Neighbors := make(map[string]map[string]bool)
+	InterfaceIDs := make(map[uint]bool)
+	addInterface := func(DeviceID uint, InterfaceID uint, IntType string, IntName string) {
+		IPAddress := DeviceMap[DeviceID].IPAddress
+		if Interfaces[IPAddress] == nil {
+			Interfaces[IPAddress] = make(map[string]bool)
+			Neighbors[IPAddress] = make(map[string]bool)
+		}
+		Interfaces[IPAddress][IntType+" "+IntName] = true
+		InterfaceIDs[InterfaceID] = true
+	}
+	for _, Links := range []map[string]domain.LLDPNeighbor{Changes.Added, Changes.Removed} {
+		for _, link := range Links {
+			addInterface(link.DeviceOneID, link.InterfaceOneID, link.InterfaceOneType, link.InterfaceOneName)
+			addInterface(link.DeviceTwoID, link.InterfaceTwoID, link.InterfaceTwoType, link.InterfaceTwoName)
+		}
+	}
+	for ID, dev := range DeviceMap {
+		if Neighbors[dev.IPAddress] == nil {
+			continue
+		}
+		BGPConfigs, _ := sh.Db.GetBGPSwitchConfigsOnDeviceID(sh.FabricID, ID)
+		for _, bgp := range BGPConfigs {
+			if InterfaceIDs[bgp.RemoteInterfaceID] {
+				Neighbors[dev.IPAddress][bgp.RemoteIPAddress] = true
+			}
+		}
+	}
+
+	Hosts := make([]operation.ConfigSwitch, 0)
+	for _, host := range config.Hosts {
+		if Interfaces[host.Host] == nil {
+			continue
+		}
+		HostInterfaces := make([]operation.ConfigInterface, 0)
+		for _, intf := range host.Interfaces {
+			if intf.ConfigType != domain.ConfigNone && Interfaces[host.Host][intf.InterfaceType+" "+intf.InterfaceName] {
+				HostInterfaces = append(HostInterfaces, intf)
+			}
+		}
+		HostNeighbors := make([]operation.ConfigBgpNeighbor, 0)
+		for _, neigh := range host.BgpNeighbors {
+			if neigh.ConfigType != domain.ConfigNone && Neighbors[host.Host][neigh.NeighborAddress] {
+				HostNeighbors = append(HostNeighbors, neigh)
+			}
+		}
+		host.Interfaces = HostInterfaces
+		host.BgpNeighbors = HostNeighbors
+		Hosts = append(Hosts, host)
+	}
+	config.Hosts = Hosts
+	config.MctCluster = make(map[uint][]operation.ConfigCluster)
+}
+
+//settleLinkChanges marks the pushed configs of the added links as configured and drops the removed links
+func (sh *DeviceInteractor) settleLinkChanges(ctx context.Context, Changes linkChanges) error {
+	RollBack := true
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return err
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	InterfaceIDs := make([]uint, 0)
+	for _, link := range Changes.Added {
+		InterfaceIDs = append(InterfaceIDs, link.InterfaceOneID, link.InterfaceTwoID)
+	}
+	//The LLDP of the devices and links left out of the refresh stays pending for the next configure
+	LinkDeviceIDs := make([]uint, 0)
+	LinkInterfaceIDs := make([]uint, 0)
+	for _, Links := range []map[string]domain.LLDPNeighbor{Changes.Added, Changes.Removed} {
+		for _, link := range Links {
+			LinkDeviceIDs = append(LinkDeviceIDs, link.DeviceOneID, link.DeviceTwoID)
+			LinkInterfaceIDs = append(LinkInterfaceIDs, link.InterfaceOneID, link.InterfaceTwoID)
+		}
+	}
+	if len(InterfaceIDs) != 0 {
+		if err := sh.Db.UpdateConfigTypeForInterfaceSwitchConfigsOnIntefaceIDs(sh.FabricID, InterfaceIDs,
+			domain.ConfigNone); err != nil {
+			return err
+		}
+		if err := sh.Db.UpdateConfigTypeForBGPSwitchConfigsOnIntefaceID(sh.FabricID, InterfaceIDs,
+			domain.ConfigNone); err != nil {
+			return err
+		}
+	}
+	if err := sh.Db.UpdateLLDPConfigTypeOnLinks(sh.FabricID, LinkDeviceIDs, LinkInterfaceIDs,
+		[]string{domain.ConfigCreate, domain.ConfigUpdate}, domain.ConfigNone); err != nil {
+		return err
+	}
+	if err := sh.Db.DeleteLLDPMarkedForDeleteOnLinks(sh.FabricID, LinkDeviceIDs, LinkInterfaceIDs); err != nil {
+		return err
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return nil
+}
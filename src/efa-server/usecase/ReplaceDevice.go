@@ -0,0 +1,406 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	Interactor "efa-server/usecase/interactorinterface"
+	"fmt"
+	"strings"
+)
+
+//replacementDetails holds the assets discovered on the replacement switch
+type replacementDetails struct {
+	Name            string
+	FirmwareVersion string
+	Interfaces      []domain.Interface
+	LLDPS           []domain.LLDP
+}
+
+//ReplaceDevice replaces a switch of the fabric with a new unit reachable on a different management IP Address.
+//The new unit takes over the device of the old unit, so that the ASN, Loopback and P2P allocations and all
+//the configs of the old unit move along with it and nothing else in the fabric changes. The new unit must be
+//of the same model and must be cabled to the same neighbour interfaces as the old unit
+func (sh *DeviceInteractor) ReplaceDevice(ctx context.Context, FabricName string, OldIPAddress string,
+	NewIPAddress string, UserName string, Password string) (string, error) {
+	ctx = context.WithValue(ctx, appcontext.UseCaseName, "Replace Device")
+	ctx = context.WithValue(ctx, appcontext.FabricName, FabricName)
+	LOG := appcontext.Logger(ctx)
+
+	var Fabric domain.Fabric
+	var Device domain.Device
+	var err error
+
+	if Fabric, err = sh.Db.GetFabric(FabricName); err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve Fabric %s", FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricNotFound
+	}
+	if Device, err = sh.Db.GetDevice(FabricName, OldIPAddress); err != nil {
+		statusMsg := fmt.Sprintf("Device %s is not registered with Fabric %s", OldIPAddress, FabricName)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrDeviceNotFound
+	}
+	if _, err = sh.Db.GetDeviceInAnyFabric(NewIPAddress); err == nil {
+		statusMsg := fmt.Sprintf("Device %s is already registered", NewIPAddress)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricIncorrectValues
+	}
+
+	//Discover the replacement switch
+	Device.IPAddress = NewIPAddress
+	sh.EvaluateCredentials(UserName, Password, &Device)
+	Replacement, statusMsg, err := sh.discoverReplacement(ctx, Fabric.ID, &Device)
+	if err != nil {
+		LOG.Errorln(statusMsg)
+		return statusMsg, err
+	}
+
+	//Retrieve the links of the old switch, the replacement must be cabled the same way
+	Links, err := sh.Db.GetLLDPNeighborsOnEitherDevice(Fabric.ID, Device.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the links of Device %s", OldIPAddress)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+	if statusMsg, err := sh.verifyReplacementLinks(ctx, Fabric.ID, Device.ID, Links, Replacement.LLDPS); err != nil {
+		LOG.Errorln(statusMsg)
+		return statusMsg, err
+	}
+
+	Device.Name = Replacement.Name
+	Device.FirmwareVersion = Replacement.FirmwareVersion
+	ReplayClusters, ReplayPorts := sh.getMctClustersForReplay(ctx, Fabric.ID, Device.ID)
+	if err = sh.saveReplacement(ctx, FabricName, OldIPAddress, &Device, &Replacement,
+		ReplayClusters, ReplayPorts); err != nil {
+		statusMsg := fmt.Sprintf("Failed to move Device %s to %s: %s", OldIPAddress, NewIPAddress, err.Error())
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+	LOG.Infof("Device %s moved to %s", OldIPAddress, NewIPAddress)
+
+	//Push the config of the old switch to the replacement
+	config, err := sh.GetActionRequestObject(ctx, FabricName, false)
+	if err != nil {
+		return err.Error(), domain.ErrFabricInternalError
+	}
+	filterReplacementConfig(&config, NewIPAddress)
+	if Errors := sh.FabricAdapter.ConfigureFabric(ctx, config, false, true); len(Errors) != 0 {
+		statusMsg := fmt.Sprintf("Device %s replaced by %s, but Operation[%s] failed: %s. Run fabric configure to retry",
+			OldIPAddress, NewIPAddress, Errors[0].Operation, Errors[0].Error)
+		LOG.Errorln(statusMsg)
+		return statusMsg, domain.ErrFabricInternalError
+	}
+	if err = sh.saveMctClustersConfigType(ctx, ReplayClusters, ReplayPorts, domain.ConfigNone); err != nil {
+		LOG.Errorln("Failed to reset MCT Cluster config type", err)
+	}
+	//The replacement of a device in maintenance mode is drained as well, the config pushed restored its traffic
+	if Device.MaintenanceMode {
+		if Message, err := sh.UpdateMaintenanceMode(ctx, FabricName, NewIPAddress, true); err != nil {
+			statusMsg := fmt.Sprintf("Device %s replaced by %s, but the replacement is not drained: %s. "+
+				"Run device maintenance enable to retry", OldIPAddress, NewIPAddress, Message)
+			LOG.Errorln(statusMsg)
+			return statusMsg, err
+		}
+	}
+
+	//On Success backup the DB
+	if err := sh.Db.Backup(); err != nil {
+		LOG.Printf("Failed to backup DB during Replace %s\n", err)
+	}
+	return fmt.Sprintf("Device %s replaced by %s", OldIPAddress, NewIPAddress), nil
+}
+
+//discoverReplacement fetches the details, interfaces and LLDP neighbours of the replacement switch
+func (sh *DeviceInteractor) discoverReplacement(ctx context.Context, FabricID uint,
+	Device *domain.Device) (replacementDetails, string, error) {
+	var Replacement replacementDetails
+	var DeviceAdapter Interactor.DeviceAdapter
+	var err error
+
+	if DeviceAdapter, err = sh.DeviceAdapterFactory(ctx, Device.IPAddress, Device.UserName, Device.Password); err != nil {
+		return Replacement, fmt.Sprintf("Switch %s connection Failed : %s", Device.IPAddress, err.Error()),
+			domain.ErrFabricIncorrectValues
+	}
+	defer DeviceAdapter.CloseConnection(ctx)
+
+	DeviceDetail, err := DeviceAdapter.GetDeviceDetail(FabricID, Device.ID, Device.IPAddress)
+	if err != nil {
+		return Replacement, fmt.Sprintf("Unable to fetch device details(Model and Firmware Version) for %s",
+			Device.IPAddress), domain.ErrFabricInternalError
+	}
+	if DeviceDetail.Model != Device.Model {
+		return Replacement, fmt.Sprintf("Model %s of Device %s does not match Model %s of the replaced Device",
+			DeviceDetail.Model, Device.IPAddress, Device.Model), domain.ErrFabricIncorrectValues
+	}
+	if err = DeviceAdapter.CheckSupportedFirmware(Device.IPAddress); err != nil {
+		return Replacement, fmt.Sprintf("Unsupported firmware version for %s : %s", Device.IPAddress,
+			DeviceDetail.FirmwareVersion), domain.ErrFabricIncorrectValues
+	}
+	Replacement.FirmwareVersion = DeviceDetail.FirmwareVersion
+
+	if Replacement.Name, err = DeviceAdapter.GetSwitchHostName(Device.IPAddress); err != nil {
+		return Replacement, fmt.Sprintf("HostName fetch for switch %s Failed", Device.IPAddress),
+			domain.ErrFabricInternalError
+	}
+	if Replacement.Interfaces, err = DeviceAdapter.GetInterfaces(FabricID, Device.ID, sh.FabricProperties.ControlVE); err != nil {
+		return Replacement, fmt.Sprintf("Interface fetch for switch %s Failed", Device.IPAddress),
+			domain.ErrFabricInternalError
+	}
+	if Replacement.LLDPS, err = DeviceAdapter.GetLLDPs(FabricID, Device.ID); err != nil {
+		return Replacement, fmt.Sprintf("Failed to fetch LLDP for %s", Device.IPAddress),
+			domain.ErrFabricInternalError
+	}
+
+	//Enable the fabric links on the replacement, as it is done when a device is added
+	Device.Interfaces = Replacement.Interfaces
+	if _, err := sh.enableInterfaces(ctx, Device, DeviceAdapter); err != nil {
+		return Replacement, fmt.Sprintf("Enable Interfaces on switch %s Failed", Device.IPAddress),
+			domain.ErrFabricInternalError
+	}
+	return Replacement, "", nil
+}
+
+//verifyReplacementLinks checks that every link of the replaced switch is seen by LLDP on the same
+//interface of the replacement, towards the same interface of the same neighbour
+func (sh *DeviceInteractor) verifyReplacementLinks(ctx context.Context, FabricID uint, DeviceID uint,
+	Links []domain.LLDPNeighbor, LLDPS []domain.LLDP) (string, error) {
+	Seen := make(map[string]string)
+	for _, lldp := range LLDPS {
+		Seen[fmt.Sprintln(lldp.LocalIntType, lldp.LocalIntName)] = lldp.RemoteIntMac
+	}
+
+	Missing := make([]string, 0)
+	for _, Link := range Links {
+		if Link.ConfigType == domain.ConfigDelete {
+			continue
+		}
+		LocalType, LocalName := Link.InterfaceOneType, Link.InterfaceOneName
+		RemoteDeviceID, RemoteType, RemoteName := Link.DeviceTwoID, Link.InterfaceTwoType, Link.InterfaceTwoName
+		if Link.DeviceTwoID == DeviceID {
+			LocalType, LocalName = Link.InterfaceTwoType, Link.InterfaceTwoName
+			RemoteDeviceID, RemoteType, RemoteName = Link.DeviceOneID, Link.InterfaceOneType, Link.InterfaceOneName
+		}
+		RemoteInterface, err := sh.Db.GetInterface(FabricID, RemoteDeviceID, RemoteType, RemoteName)
+		if err != nil {
+			return fmt.Sprintf("Unable to retrieve Interface %s %s of the neighbour", RemoteType, RemoteName),
+				domain.ErrFabricInternalError
+		}
+		if Mac, ok := Seen[fmt.Sprintln(LocalType, LocalName)]; !ok || Mac != RemoteInterface.Mac {
+			Missing = append(Missing, fmt.Sprintf("%s %s", LocalType, LocalName))
+		}
+	}
+	if len(Missing) != 0 {
+		return fmt.Sprintf("LLDP neighbors of the replacement do not match the links of the replaced Device on %s",
+			strings.Join(Missing, ", ")), domain.ErrFabricIncorrectValues
+	}
+	return "", nil
+}
+
+//getMctClustersForReplay returns the configured MCT clusters of the device and their member ports,
+//which have to be pushed again to bring up the cluster on the replacement
+func (sh *DeviceInteractor) getMctClustersForReplay(ctx context.Context, FabricID uint,
+	DeviceID uint) ([]domain.MctClusterConfig, []domain.MCTMemberPorts) {
+	Clusters, _ := sh.Db.GetMctClusters(FabricID, DeviceID, []string{domain.ConfigNone})
+	Ports := make([]domain.MCTMemberPorts, 0)
+	for _, Cluster := range Clusters {
+		PeerClusters, _ := sh.Db.GetMctClustersWithBothDevices(FabricID, Cluster.MCTNeighborDeviceID, DeviceID,
+			[]string{domain.ConfigNone})
+		Clusters = append(Clusters, PeerClusters...)
+
+		MyPorts, _ := sh.Db.GetMctMemberPortsConfig(FabricID, DeviceID, Cluster.MCTNeighborDeviceID,
+			[]string{domain.ConfigNone})
+		PeerPorts, _ := sh.Db.GetMctMemberPortsConfig(FabricID, Cluster.MCTNeighborDeviceID, DeviceID,
+			[]string{domain.ConfigNone})
+		Ports = append(append(Ports, MyPorts...), PeerPorts...)
+	}
+	return Clusters, Ports
+}
+
+//saveReplacement moves the device, and the assets referring to its management IP Address, to the replacement
+func (sh *DeviceInteractor) saveReplacement(ctx context.Context, FabricName string, OldIPAddress string,
+	Device *domain.Device, Replacement *replacementDetails, ReplayClusters []domain.MctClusterConfig,
+	ReplayPorts []domain.MCTMemberPorts) error {
+	RollBack := true
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return err
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	Device.IsPasswordEncrypted = false
+	if err := sh.Db.SaveDevice(Device); err != nil {
+		return err
+	}
+	if err := sh.moveReplacementAssets(Device, Replacement); err != nil {
+		return err
+	}
+
+	if SwitchConfig, err := sh.Db.GetSwitchConfigOnFabricIDAndDeviceID(Device.FabricID, Device.ID); err == nil {
+		SwitchConfig.DeviceIP = Device.IPAddress
+		if err := sh.Db.CreateSwitchConfig(&SwitchConfig); err != nil {
+			return err
+		}
+	}
+	if Rack, err := sh.Db.GetRackbyIP(FabricName, OldIPAddress); err == nil {
+		if Rack.DeviceOneIP == OldIPAddress {
+			Rack.DeviceOneIP = Device.IPAddress
+		}
+		if Rack.DeviceTwoIP == OldIPAddress {
+			Rack.DeviceTwoIP = Device.IPAddress
+		}
+		if err := sh.Db.SaveRack(&Rack); err != nil {
+			return err
+		}
+	}
+	Clusters, err := sh.Db.GetMctClusterConfigWithDeviceIP(OldIPAddress)
+	if err != nil {
+		return err
+	}
+	for _, Cluster := range Clusters {
+		replaceMgmtIP(&Cluster, OldIPAddress, Device.IPAddress)
+		if err := sh.Db.CreateMctClusters(&Cluster); err != nil {
+			return err
+		}
+	}
+	for iter := range ReplayClusters {
+		replaceMgmtIP(&ReplayClusters[iter], OldIPAddress, Device.IPAddress)
+	}
+	if err := sh.setMctClustersConfigType(ReplayClusters, ReplayPorts, domain.ConfigCreate); err != nil {
+		return err
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return nil
+}
+
+//moveReplacementAssets updates the interfaces and LLDP data of the device with the ones of the replacement.
+//The interfaces are updated in place, so that the links and the P2P allocations referring to them are kept
+func (sh *DeviceInteractor) moveReplacementAssets(Device *domain.Device, Replacement *replacementDetails) error {
+	NewInterfaces := make(map[string]domain.Interface)
+	for _, Interface := range Replacement.Interfaces {
+		NewInterfaces[fmt.Sprintln(Interface.IntType, Interface.IntName)] = Interface
+	}
+	OldInterfaces, err := sh.Db.GetInterfacesonDevice(Device.FabricID, Device.ID)
+	if err != nil {
+		return err
+	}
+	for _, Interface := range OldInterfaces {
+		NewInterface, ok := NewInterfaces[fmt.Sprintln(Interface.IntType, Interface.IntName)]
+		if !ok || NewInterface.Mac == Interface.Mac {
+			continue
+		}
+		//Neighbours refer to the interface by its Mac
+		if RemoteLLDP, err := sh.Db.GetLLDPOnRemoteMacExcludingMarkedForDeletion(Interface.Mac,
+			Device.FabricID); err == nil && len(Interface.Mac) != 0 {
+			RemoteLLDP.RemoteIntMac = NewInterface.Mac
+			RemoteLLDP.RemoteManagementAddress = Device.IPAddress
+			RemoteLLDP.RemoteSystemName = Device.Name
+			if err := sh.Db.CreateLLDP(&RemoteLLDP); err != nil {
+				return err
+			}
+		}
+		Interface.Mac = NewInterface.Mac
+		if err := sh.Db.CreateInterface(&Interface); err != nil {
+			return err
+		}
+	}
+
+	NewLLDPS := make(map[string]domain.LLDP)
+	for _, lldp := range Replacement.LLDPS {
+		NewLLDPS[fmt.Sprintln(lldp.LocalIntType, lldp.LocalIntName)] = lldp
+	}
+	OldLLDPS, err := sh.Db.GetLLDPsonDevice(Device.FabricID, Device.ID)
+	if err != nil {
+		return err
+	}
+	for _, lldp := range OldLLDPS {
+		if NewLLDP, ok := NewLLDPS[fmt.Sprintln(lldp.LocalIntType, lldp.LocalIntName)]; ok {
+			lldp.LocalIntMac = NewLLDP.LocalIntMac
+			if err := sh.Db.CreateLLDP(&lldp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//setMctClustersConfigType sets the config type of the MCT clusters and of their member ports
+func (sh *DeviceInteractor) setMctClustersConfigType(Clusters []domain.MctClusterConfig,
+	Ports []domain.MCTMemberPorts, ConfigType string) error {
+	for iter := range Clusters {
+		Clusters[iter].ConfigType = ConfigType
+		if err := sh.Db.CreateMctClusters(&Clusters[iter]); err != nil {
+			return err
+		}
+	}
+	for _, Port := range Ports {
+		if err := sh.Db.CreateMctClustersMembers([]domain.MCTMemberPorts{Port}, Port.ClusterID, ConfigType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sh *DeviceInteractor) saveMctClustersConfigType(ctx context.Context, Clusters []domain.MctClusterConfig,
+	Ports []domain.MCTMemberPorts, ConfigType string) error {
+	if len(Clusters) == 0 && len(Ports) == 0 {
+		return nil
+	}
+	RollBack := true
+
+	//Start Transaction
+	sh.DBMutex.Lock()
+	defer sh.DBMutex.Unlock()
+	if err := sh.Db.OpenTransaction(); err != nil {
+		return err
+	}
+	defer sh.CloseTransaction(ctx, &RollBack)
+
+	if err := sh.setMctClustersConfigType(Clusters, Ports, ConfigType); err != nil {
+		return err
+	}
+
+	//Operation is Success, Set RollBack to False
+	RollBack = false
+	return nil
+}
+
+//filterReplacementConfig restricts the config to the replacement switch and its MCT clusters
+func filterReplacementConfig(config *operation.ConfigFabricRequest, IPAddress string) {
+	Hosts := make([]operation.ConfigSwitch, 0, 1)
+	for _, host := range config.Hosts {
+		if host.Host == IPAddress {
+			Hosts = append(Hosts, host)
+		}
+	}
+	config.Hosts = Hosts
+
+	MctCluster := make(map[uint][]operation.ConfigCluster)
+	for opcode, Clusters := range config.MctCluster {
+		for _, Cluster := range Clusters {
+			for _, Member := range Cluster.ClusterMemberNodes {
+				if Member.NodeMgmtIP == IPAddress {
+					MctCluster[opcode] = append(MctCluster[opcode], Cluster)
+					break
+				}
+			}
+		}
+	}
+	config.MctCluster = MctCluster
+}
+
+//replaceMgmtIP replaces the management IP Address of a member of the MCT cluster
+func replaceMgmtIP(Cluster *domain.MctClusterConfig, OldIPAddress string, NewIPAddress string) {
+	if Cluster.DeviceOneMgmtIP == OldIPAddress {
+		Cluster.DeviceOneMgmtIP = NewIPAddress
+	}
+	if Cluster.DeviceTwoMgmtIP == OldIPAddress {
+		Cluster.DeviceTwoMgmtIP = NewIPAddress
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+)
+
+//GetSchemaMigrationStatus returns the version of the database schema and whether each migration is applied
+func (sh *DeviceInteractor) GetSchemaMigrationStatus(ctx context.Context) (domain.SchemaMigrationStatus, error) {
+	LOG := appcontext.Logger(ctx)
+	Status, err := sh.Db.GetSchemaMigrationStatus()
+	if err != nil {
+		LOG.Errorln("Failed to read the database migrations", err)
+		return Status, err
+	}
+	return Status, nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//UnknownUserName is recorded for the settings changes whose request does not name the user
+const UnknownUserName = "unknown"
+
+//recordSettingChanges records each fabric setting changed from Old to New, with the user and the execution
+//of the request. It is called within the transaction saving the settings.
+func (sh *DeviceInteractor) recordSettingChanges(ctx context.Context, FabricID uint, Old domain.FabricProperties,
+	New domain.FabricProperties) error {
+	UserName, ok := ctx.Value(appcontext.UserName).(string)
+	if !ok || len(UserName) == 0 {
+		UserName = UnknownUserName
+	}
+	ExecutionID, _ := ctx.Value(appcontext.RequestIDKey).(string)
+	ChangedAt := time.Now()
+	for _, Setting := range settingChanges(Old, New) {
+		Change := domain.FabricSettingChange{FabricID: FabricID, Setting: Setting.Name, OldValue: Setting.Old,
+			NewValue: Setting.New, UserName: UserName, ExecutionID: ExecutionID, ChangedAt: ChangedAt}
+		if err := sh.Db.CreateFabricSettingChange(&Change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//GetFabricSettingsHistory returns the changes of the fabric settings, the most recent first
+func (sh *DeviceInteractor) GetFabricSettingsHistory(ctx context.Context, FabricName string) ([]domain.FabricSettingChange, string, error) {
+	LOG := appcontext.Logger(ctx)
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		return nil, fmt.Sprintf("Unable to retrieve Fabric %s", FabricName), domain.ErrFabricNotFound
+	}
+	Changes, err := sh.Db.GetFabricSettingChanges(Fabric.ID)
+	if err != nil {
+		statusMsg := fmt.Sprintf("Unable to retrieve the settings history of %s", FabricName)
+		LOG.Errorln(statusMsg, err)
+		return nil, statusMsg, domain.ErrFabricInternalError
+	}
+	return Changes, "", nil
+}
+
+//PrepareFabricSettingsRollback returns the settings update restoring the fabric settings as they were right after
+//the change ToID, along with the settings it changes. The update is validated and saved as any settings update,
+//which records the rollback in the history.
+func (sh *DeviceInteractor) PrepareFabricSettingsRollback(ctx context.Context, FabricName string,
+	ToID uint) (domain.FabricProperties, []domain.ConfigChange, string, error) {
+	var Rollback domain.FabricProperties
+	Changes, statusMsg, err := sh.GetFabricSettingsHistory(ctx, FabricName)
+	if err != nil {
+		return Rollback, nil, statusMsg, err
+	}
+	found := false
+	//The changes are the most recent first, the oldest change after ToID holds the value to restore
+	Restore := make(map[string]string)
+	for _, Change := range Changes {
+		if Change.ID == ToID {
+			found = true
+		}
+		if Change.ID > ToID {
+			Restore[Change.Setting] = Change.OldValue
+		}
+	}
+	if !found {
+		return Rollback, nil, fmt.Sprintf("Fabric %s has no settings change %d", FabricName, ToID),
+			domain.ErrSettingChangeNotFound
+	}
+
+	Fabric, _ := sh.Db.GetFabric(FabricName)
+	Current, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		return Rollback, nil, fmt.Sprintf("Unable to retrieve Fabric Properties for %s", FabricName),
+			domain.ErrFabricInternalError
+	}
+
+	Settings := make([]domain.ConfigChange, 0)
+	CurrentValue := reflect.ValueOf(Current)
+	RollbackValue := reflect.ValueOf(&Rollback).Elem()
+	for i := 0; i < CurrentValue.NumField(); i++ {
+		Name := CurrentValue.Type().Field(i).Name
+		Value, ok := Restore[Name]
+		if !ok || Value == CurrentValue.Field(i).String() {
+			continue
+		}
+		//An empty value is not an update, a password is removed with "none"
+		if len(Value) == 0 {
+			if !strings.Contains(Name, "Password") {
+				return Rollback, nil, fmt.Sprintf("%s cannot be rolled back to an empty value", Name),
+					domain.ErrFabricIncorrectValues
+			}
+			Value = domain.BGPPasswordNone
+		}
+		RollbackValue.Field(i).SetString(Value)
+		Settings = append(Settings, domain.ConfigChange{Kind: domain.ChangeKindSetting, Name: Name,
+			Change: domain.ChangeUpdated, Old: CurrentValue.Field(i).String(), New: Restore[Name]})
+	}
+	if len(Settings) == 0 {
+		return Rollback, Settings, fmt.Sprintf("Fabric %s settings are already as of change %d", FabricName, ToID),
+			domain.ErrFabricIncorrectValues
+	}
+	return Rollback, Settings, "", nil
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"efa-server/domain"
+	"efa-server/domain/operation"
+	"efa-server/gateway/appcontext"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//SettingsPreview describes the impact of a fabric settings update on the configuration of the devices
+type SettingsPreview struct {
+	FabricName string
+	//Settings lists the fabric settings changed by the update
+	Settings []domain.ConfigChange
+	//Changes lists the differences of the configuration pushed to the devices by the next configure
+	Changes []domain.ConfigChange
+	//Reallocations lists the values held by the devices which are outside the updated ranges
+	Reallocations []PoolReallocation
+	//Blocked is set when the update itself would be refused
+	Blocked string
+}
+
+//PoolReallocation describes a value of a pool which has to be reallocated after a range change
+type PoolReallocation struct {
+	PoolType      string
+	PoolName      string
+	Range         string
+	Value         string
+	IPAddress     string
+	InterfaceName string
+}
+
+//switchFieldsNotPreviewed are the fields of the device configuration which do not depend on the fabric settings
+var switchFieldsNotPreviewed = map[string]bool{
+	"Host": true, "Device": true, "UserName": true, "Password": true, "Fabric": true, "Model": true,
+	"Role": true, "Chassis": true,
+}
+
+//PreviewFabricProperties computes the changes of the device configurations and the pool reallocations caused by
+//a fabric settings update, without saving the settings
+func (sh *DeviceInteractor) PreviewFabricProperties(ctx context.Context, FabricName string,
+	FabricUpdateRequest *domain.FabricProperties) (SettingsPreview, string, error) {
+	LOG := appcontext.Logger(ctx)
+	Preview := SettingsPreview{FabricName: FabricName}
+
+	Fabric, err := sh.Db.GetFabric(FabricName)
+	if err != nil {
+		return Preview, fmt.Sprintf("Unable to retrieve Fabric %s", FabricName), domain.ErrFabricNotFound
+	}
+	OldProperties, err := sh.Db.GetFabricProperties(Fabric.ID)
+	if err != nil {
+		return Preview, fmt.Sprintf("Unable to retrieve Fabric Properties for %s", FabricName), domain.ErrFabricInternalError
+	}
+	NewProperties := OldProperties
+	modifyUpdatedField(&NewProperties, FabricUpdateRequest)
+	if NewProperties == OldProperties {
+		return Preview, fmt.Sprintf("No Fabric Property Update is Requested For Fabric %s", FabricName),
+			domain.ErrFabricIncorrectValues
+	}
+	if err = sh.validateFabricProperties(ctx, &NewProperties); err != nil {
+		return Preview, err.Error(), domain.ErrFabricIncorrectValues
+	}
+	if deviceCount := sh.Db.GetDevicesCountInFabric(Fabric.ID); deviceCount != 0 {
+		Preview.Blocked = fmt.Sprintf("%s: fabric is already active and cannot be updated", FabricName)
+	}
+
+	sh.FabricID = Fabric.ID
+	sh.FabricName = FabricName
+	Preview.Settings = settingChanges(OldProperties, NewProperties)
+	for iter := range Preview.Settings {
+		Preview.Settings[iter].Old = maskPassword(Preview.Settings[iter].Name, Preview.Settings[iter].Old)
+		Preview.Settings[iter].New = maskPassword(Preview.Settings[iter].Name, Preview.Settings[iter].New)
+	}
+
+	OldConfig := operation.ConfigFabricRequest{FabricName: FabricName, FabricSettings: OldProperties}
+	NewConfig := operation.ConfigFabricRequest{FabricName: FabricName, FabricSettings: NewProperties}
+	if err = sh.prepareConfiguration(ctx, &OldConfig); err != nil {
+		return Preview, err.Error(), domain.ErrFabricInternalError
+	}
+	if err = sh.prepareConfiguration(ctx, &NewConfig); err != nil {
+		return Preview, err.Error(), domain.ErrFabricInternalError
+	}
+	Preview.Changes = diffConfigSwitches(OldConfig.Hosts, NewConfig.Hosts)
+
+	if Preview.Reallocations, err = sh.poolReallocations(Fabric.ID, OldProperties, NewProperties); err != nil {
+		LOG.Errorln(err)
+		return Preview, fmt.Sprintf("Unable to compute the pool reallocations of %s", FabricName),
+			domain.ErrFabricInternalError
+	}
+
+	statusMsg := fmt.Sprintf("%d settings change the configuration of %d devices", len(Preview.Settings),
+		countChangedDevices(Preview.Changes))
+	return Preview, statusMsg, nil
+}
+
+func countChangedDevices(Changes []domain.ConfigChange) int {
+	Devices := make(map[string]bool)
+	for _, Change := range Changes {
+		Devices[Change.DeviceIP] = true
+	}
+	return len(Devices)
+}
+
+//previewValue returns the value of a field as displayed by the preview, passwords are masked
+func previewValue(Name string, Value reflect.Value) string {
+	var Text string
+	switch Value.Kind() {
+	case reflect.String:
+		Text = Value.String()
+	case reflect.Bool:
+		Text = strconv.FormatBool(Value.Bool())
+	}
+	return maskPassword(Name, Text)
+}
+
+//maskPassword hides the value of a password field, an empty password and "none" are displayed as is
+func maskPassword(Name string, Value string) string {
+	if strings.Contains(Name, "Password") && len(Value) != 0 && Value != domain.BGPPasswordNone {
+		return "******"
+	}
+	return Value
+}
+
+//settingChanges returns the fabric settings which differ, named by the keys of the settings update
+func settingChanges(Old domain.FabricProperties, New domain.FabricProperties) []domain.ConfigChange {
+	return fieldChanges(domain.ChangeKindSetting, Old, New)
+}
+
+//fieldChanges returns the string fields which differ between the structs Old and New, as changes of the kind
+func fieldChanges(Kind string, Old interface{}, New interface{}) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	OldValue := reflect.ValueOf(Old)
+	NewValue := reflect.ValueOf(New)
+	Type := OldValue.Type()
+	for i := 0; i < Type.NumField(); i++ {
+		Field := Type.Field(i)
+		if Field.Type.Kind() != reflect.String || OldValue.Field(i).String() == NewValue.Field(i).String() {
+			continue
+		}
+		Changes = append(Changes, domain.ConfigChange{Kind: Kind, Name: Field.Name,
+			Change: domain.ChangeUpdated, Old: OldValue.Field(i).String(), New: NewValue.Field(i).String()})
+	}
+	return Changes
+}
+
+//diffConfigSwitches returns the changes from the device configurations From to the device configurations To
+func diffConfigSwitches(From []operation.ConfigSwitch, To []operation.ConfigSwitch) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	FromHosts := make(map[string]operation.ConfigSwitch)
+	for _, Host := range From {
+		FromHosts[Host.Host] = Host
+	}
+	for _, Host := range To {
+		Old, found := FromHosts[Host.Host]
+		if !found {
+			continue
+		}
+		OldValue := reflect.ValueOf(Old)
+		NewValue := reflect.ValueOf(Host)
+		Type := OldValue.Type()
+		for i := 0; i < Type.NumField(); i++ {
+			Field := Type.Field(i)
+			if switchFieldsNotPreviewed[Field.Name] {
+				continue
+			}
+			if Kind := Field.Type.Kind(); Kind != reflect.String && Kind != reflect.Bool {
+				continue
+			}
+			if OldValue.Field(i).Interface() == NewValue.Field(i).Interface() {
+				continue
+			}
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: Host.Host, Kind: domain.ChangeKindDevice,
+				Name: Field.Name, Change: domain.ChangeUpdated, Old: previewValue(Field.Name, OldValue.Field(i)),
+				New: previewValue(Field.Name, NewValue.Field(i))})
+		}
+		Changes = append(Changes, diffConfigInterfaces(Host.Host, Old.Interfaces, Host.Interfaces)...)
+	}
+	return Changes
+}
+
+func diffConfigInterfaces(DeviceIP string, From []operation.ConfigInterface,
+	To []operation.ConfigInterface) []domain.ConfigChange {
+	Changes := make([]domain.ConfigChange, 0)
+	describe := func(Interface operation.ConfigInterface) string {
+		if Interface.Donor != "" {
+			return fmt.Sprintf("unnumbered %s %s", Interface.Donor, Interface.DonorPort)
+		}
+		return Interface.IP
+	}
+	name := func(Interface operation.ConfigInterface) string {
+		return Interface.InterfaceType + " " + Interface.InterfaceName
+	}
+	FromInterfaces := make(map[string]operation.ConfigInterface)
+	for _, Interface := range From {
+		FromInterfaces[name(Interface)] = Interface
+	}
+	ToInterfaces := make(map[string]operation.ConfigInterface)
+	for _, Interface := range To {
+		ToInterfaces[name(Interface)] = Interface
+		Old, found := FromInterfaces[name(Interface)]
+		if !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindInterface,
+				Name: name(Interface), Change: domain.ChangeAdded, New: describe(Interface)})
+		} else if describe(Old) != describe(Interface) {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindInterface,
+				Name: name(Interface), Change: domain.ChangeUpdated, Old: describe(Old), New: describe(Interface)})
+		}
+	}
+	for _, Interface := range From {
+		if _, found := ToInterfaces[name(Interface)]; !found {
+			Changes = append(Changes, domain.ConfigChange{DeviceIP: DeviceIP, Kind: domain.ChangeKindInterface,
+				Name: name(Interface), Change: domain.ChangeRemoved, Old: describe(Interface)})
+		}
+	}
+	return Changes
+}
+
+//poolReallocations returns the allocations of the pools whose range changed which are outside the new range,
+//all the allocations of a pool no longer used by the fabric have to be reallocated
+func (sh *DeviceInteractor) poolReallocations(FabricID uint, Old domain.FabricProperties,
+	New domain.FabricProperties) ([]PoolReallocation, error) {
+	Reallocations := make([]PoolReallocation, 0)
+	NewPools := make(map[string]poolDefinition)
+	for _, Pool := range fabricPools(New) {
+		NewPools[Pool.PoolType+" "+Pool.PoolName] = Pool
+	}
+	var Holders allocationHolders
+	for _, Pool := range fabricPools(Old) {
+		NewPool, found := NewPools[Pool.PoolType+" "+Pool.PoolName]
+		if found && NewPool.Range == Pool.Range {
+			continue
+		}
+		if Holders.Devices == nil {
+			var err error
+			if Holders, err = sh.getAllocationHolders(FabricID); err != nil {
+				return Reallocations, err
+			}
+		}
+		Utilization, err := sh.getPoolUtilization(FabricID, Pool, Holders)
+		if err != nil {
+			return Reallocations, err
+		}
+		for _, Allocation := range Utilization.Allocations {
+			if found && inPoolRange(Pool.PoolType, NewPool.Range, Allocation.Value) {
+				continue
+			}
+			Reallocations = append(Reallocations, PoolReallocation{PoolType: Pool.PoolType, PoolName: Pool.PoolName,
+				Range: NewPool.Range, Value: Allocation.Value, IPAddress: Allocation.IPAddress,
+				InterfaceName: Allocation.InterfaceName})
+		}
+	}
+	return Reallocations, nil
+}
+
+//inPoolRange checks whether the value of a pool is within the range of the setting
+func inPoolRange(PoolType string, Range string, Value string) bool {
+	if PoolType == domain.PoolTypeASN {
+		ASN, err := strconv.ParseUint(Value, 10, 64)
+		if err != nil {
+			return false
+		}
+		Min, Max := GetASNMinMax(Range)
+		return ASN >= Min && ASN <= Max
+	}
+	_, Network, err := net.ParseCIDR(Range)
+	if err != nil {
+		return false
+	}
+	return Network.Contains(net.ParseIP(strings.Split(Value, "/")[0]))
+}
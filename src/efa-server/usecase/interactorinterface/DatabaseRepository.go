@@ -12,6 +12,10 @@ type DatabaseRepository interface {
 	RollBackTransaction() error
 
 	Backup() error
+	GetSchemaMigrationStatus() (domain.SchemaMigrationStatus, error)
+	CreateBackup() (domain.DatabaseBackup, error)
+	GetBackups() ([]domain.DatabaseBackup, error)
+	RestoreBackup(Name string) (domain.DatabaseBackup, error)
 
 	//Fabric Operations
 	GetFabric(FabricName string) (domain.Fabric, error)
@@ -32,6 +36,9 @@ type DatabaseRepository interface {
 	GetDevicesInFabricMatching(FabricID uint, device []string) ([]domain.Device, error)
 	GetDevicesInFabricNotMatching(FabricID uint, device []string) ([]domain.Device, error)
 
+	GetDeviceSettings(FabricID uint, DeviceID uint) (domain.DeviceSettings, error)
+	SaveDeviceSettings(DeviceSettings *domain.DeviceSettings) error
+
 	GetRack(FabricName string, IP1 string, IP2 string) (domain.Rack, error)
 	GetRackbyIP(FabricName string, IP string) (domain.Rack, error)
 	GetRackAll(FabricName string) ([]domain.Rack, error)
@@ -62,47 +69,55 @@ type DatabaseRepository interface {
 	GetLLDPOnRemoteMacExcludingMarkedForDeletion(RemoteMac string, FabricID uint) (domain.LLDP, error)
 	UpdateLLDPConfigType(FabricID uint, QueryconfigTypes []string, configType string) error
 	DeleteLLDPMarkedForDelete(FabricID uint) error
+	UpdateLLDPConfigTypeOnLinks(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint, QueryconfigTypes []string,
+		configType string) error
+	DeleteLLDPMarkedForDeleteOnLinks(FabricID uint, DeviceIDs []uint, InterfaceIDs []uint) error
+
+	//Allocation Pools
+	CreateAllocationRange(Range *domain.AllocationRange) error
+	UpdateAllocationRange(Range *domain.AllocationRange) error
+	DeleteAllocationRange(Range *domain.AllocationRange) error
+	GetAllocationRanges(FabricID uint, PoolType string, PoolName string) ([]domain.AllocationRange, error)
+	GetAllocationRangesOnWindow(FabricID uint, PoolType string, PoolName string, StartValue uint64, EndValue uint64) ([]domain.AllocationRange, error)
+	GetAllocationRangeCountOnValue(FabricID uint, PoolType string, Value uint64) (int64, error)
+
+	//Allocation Pins
+	SaveAllocationPin(Pin *domain.AllocationPin) error
+	DeleteAllocationPin(Pin *domain.AllocationPin) error
+	GetAllocationPins(FabricID uint) ([]domain.AllocationPin, error)
+	GetAllocationPinsOnDevice(FabricID uint, DeviceIP string) ([]domain.AllocationPin, error)
+	GetAllocationPinCountOnValue(FabricID uint, PoolType string, PoolName string, Value uint64) (int64, error)
+
+	//Cabling Plan
+	CreateCablingLink(Link *domain.CablingLink) error
+	DeleteCablingPlan(FabricID uint) error
+	GetCablingPlan(FabricID uint) ([]domain.CablingLink, error)
 
 	//ASN Pool
-	CreateASN(ASN *domain.ASNAllocationPool) error
-	DeleteASNPool() error
 	DeleteUsedASNPool() error
-	DeleteASN(ASN *domain.ASNAllocationPool) error
-	GetNextASNForRole(FabricID uint, role string) (domain.ASNAllocationPool, error)
-	GetASNCountOnASN(FabricID uint, asn uint64) (int64, error)
-	GetASNCountOnRole(FabricID uint, role string) (int64, error)
-	GetASNAndCountOnASNAndRole(FabricID uint, asn uint64, role string) (int64, domain.ASNAllocationPool)
 	CreateUsedASN(UsedASN *domain.UsedASN) error
 	DeleteUsedASN(UsedASN *domain.UsedASN) error
 	GetUsedASNOnASNAndDeviceAndRole(FabricID uint, DeviceID uint, asn uint64, role string) (domain.UsedASN, error)
 	GetUsedASNCountOnASNAndDevice(FabricID uint, asn uint64, DeviceID uint) (int64, error)
 	GetUsedASNCountOnASNAndRole(FabricID uint, asn uint64, role string) (int64, error)
+	GetUsedASNsOnRole(FabricID uint, role string) ([]domain.UsedASN, error)
 
 	//IP Pool
-	CreateIPEntry(IPEntry *domain.IPAllocationPool) error
-	DeleteIPEntry(IPEntry *domain.IPAllocationPool) error
-	DeleteIPPool() error
-	GetIPEntryAndCountOnIPAddressAndType(FabricID uint, ipaddress string, IPType string) (int64, domain.IPAllocationPool, error)
-	GetNextIPEntryOnType(FabricID uint, IPType string) (domain.IPAllocationPool, error)
 	GetUsedIPOnDeviceInterfaceIDIPAddresssAndType(FabricID uint, DeviceID uint, ipaddress string, IPType string, InterfaceID uint) (domain.UsedIP, error)
 	GetUsedIPOnDeviceInterfaceIDAndType(FabricID uint, DeviceID uint, IPType string, InterfaceID uint) (domain.UsedIP, error)
 	CreateUsedIPEntry(UsedIPEntry *domain.UsedIP) error
 	DeleteUsedIPEntry(UsedIPEntry *domain.UsedIP) error
 	DeleteUsedIPPool() error
+	GetUsedIPsOnType(FabricID uint, IPType string) ([]domain.UsedIP, error)
 
 	//IP Pair Pool
-	CreateIPPairEntry(IPEntry *domain.IPPairAllocationPool) error
-	DeleteIPPairEntry(IPEntry *domain.IPPairAllocationPool) error
-	DeleteIPPairPool() error
-	GetIPPairEntryAndCountOnIPAddressAndType(FabricID uint, ipaddressOne string, ipaddressTwo string, IPType string) (int64, domain.IPPairAllocationPool, error)
-	GetIPPairEntryAndCountOnEitherIPAddressAndType(FabricID uint, ipaddress string, IPType string) (int64, domain.IPPairAllocationPool, error)
-	GetNextIPPairEntryOnType(FabricID uint, IPType string) (domain.IPPairAllocationPool, error)
 	GetUsedIPPairOnDeviceInterfaceIDIPAddresssAndType(FabricID uint, DeviceOneID uint, DeviceTwoID uint, ipaddressOne string, ipaddressTwo string, IPType string,
 		InterfaceOneID uint, InterfaceTwoID uint) (domain.UsedIPPair, error)
 	GetUsedIPPairOnDeviceInterfaceIDAndType(FabricID uint, DeviceOneID uint, DeviceTwoID uint, IPType string, InterfaceOneID uint, InterfaceTwoID uint) (domain.UsedIPPair, error)
 	CreateUsedIPPairEntry(UsedIPEntry *domain.UsedIPPair) error
 	DeleteUsedIPPairEntry(UsedIPEntry *domain.UsedIPPair) error
 	DeleteUsedIPPairPool() error
+	GetUsedIPPairsOnType(FabricID uint, IPType string) ([]domain.UsedIPPair, error)
 
 	GetLLDPNeighbor(FabricID uint, DeviceOneID uint,
 		DeviceTwoID uint, InterfaceOneID uint, InterfaceTwoID uint) (domain.LLDPNeighbor, error)
@@ -163,6 +178,18 @@ type DatabaseRepository interface {
 	UpdateBGPSwitchConfigsConfigType(FabricID uint, QueryconfigTypes []string, configType string) error
 	UpdateBGPSwitchConfigsOnInterfaceIDConfigType(FabricID uint, InterfaceID uint, configType string) error
 
+	CreateConfigGeneration(Generation *domain.ConfigGeneration) error
+	GetConfigGenerations(FabricID uint) ([]domain.ConfigGeneration, error)
+	GetConfigGeneration(FabricID uint, Generation uint) (domain.ConfigGeneration, error)
+
+	CreateFabricSettingChange(Change *domain.FabricSettingChange) error
+	GetFabricSettingChanges(FabricID uint) ([]domain.FabricSettingChange, error)
+
+	CreateNotificationSubscription(Subscription *domain.NotificationSubscription) error
+	GetNotificationSubscriptions() ([]domain.NotificationSubscription, error)
+	GetNotificationSubscription(ID uint) (domain.NotificationSubscription, error)
+	DeleteNotificationSubscription(ID uint) error
+
 	CreateExecutionLog(ExecutionLog *domain.ExecutionLog) error
 	GetExecutionLogList(limit int, status string) ([]domain.ExecutionLog, error)
 	GetExecutionLogByUUID(string) (domain.ExecutionLog, error)
@@ -28,6 +28,9 @@ type DeviceAdapter interface {
 	//GetInterfaceSpeed  fetches the Interface speed from the Device
 	GetInterfaceSpeed(InterfaceType string, InterfaceName string) (int, error)
 
+	//GetInterfaceMtus fetches the MTU configured on the ethernet interfaces of the Device, keyed by interface name
+	GetInterfaceMtus() (map[string]int, error)
+
 	//GetInterfaceVe fetches the VE interface from the switch
 	GetInterfaceVe(name string) (map[string]string, error)
 
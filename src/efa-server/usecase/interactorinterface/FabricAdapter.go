@@ -10,7 +10,11 @@ import (
 type FabricAdapter interface {
 	ConfigureDeConfigureMctClusters(ctx context.Context, operation uint, config []operation.ConfigCluster, force bool) []actions.OperationError
 	ConfigureFabric(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError
+	RotateBGPPasswords(ctx context.Context, config operation.ConfigFabricRequest) []actions.OperationError
+	ConfigureMaintenanceMode(ctx context.Context, config operation.ConfigFabricRequest, enable bool) []actions.OperationError
+	ConfigureLinks(ctx context.Context, config operation.ConfigFabricRequest, persist bool) []actions.OperationError
 	FetchFabricConfiguration(ctx context.Context, FabricRequest operation.FabricFetchRequest) (operation.FabricFetchResponse, error)
+	FetchFabricHealth(ctx context.Context, FabricRequest operation.FabricHealthRequest) (operation.FabricHealthResponse, []actions.OperationError)
 	ClearConfig(ctx context.Context, ClearFabricEquest operation.ClearFabricRequest) error
 	CleanupDevicesInFabric(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError
 	CleanupDevicesInNonCLOSFabric(ctx context.Context, config operation.ConfigFabricRequest, force bool, persist bool) []actions.OperationError
@@ -0,0 +1,12 @@
+package interactorinterface
+
+import (
+	"context"
+	"efa-server/domain"
+)
+
+//Notifier is an interface to the delivery of the events to the endpoints of the subscriptions
+type Notifier interface {
+	//Deliver posts the event to the endpoint of the subscription, retrying as many times as set by the subscription
+	Deliver(ctx context.Context, Subscription domain.NotificationSubscription, Event domain.NotificationEvent) error
+}
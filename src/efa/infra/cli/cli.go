@@ -2,12 +2,17 @@ package cli
 
 import (
 	"efa/infra/cli/commands"
+	"efa/infra/cli/commands/contexts"
+	"efa/infra/cli/commands/db"
 	"efa/infra/cli/commands/debug"
 	"efa/infra/cli/commands/device"
 	"efa/infra/cli/commands/execution"
 	"efa/infra/cli/commands/fabric"
+	"efa/infra/cli/commands/notification"
+	"efa/infra/cli/utils"
 	"efa/infra/constants"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 //StartCLI Starts the CLI Environment
@@ -15,17 +20,39 @@ func StartCLI() {
 
 	rootCmd := GetRootCommand()
 
-	rootCmd.Execute()
+	if err := rootCmd.Execute(); err != nil {
+		if exitError, ok := err.(*utils.ExitError); ok {
+			os.Exit(exitError.Code)
+		}
+		os.Exit(utils.ExitFailure)
+	}
 }
 
 //GetRootCommand provides access to all Root commands
 func GetRootCommand() *cobra.Command {
-	var rootCmd = &cobra.Command{Use: constants.ApplicationName}
+	var rootCmd = &cobra.Command{Use: constants.ApplicationName, PersistentPreRunE: checkFlags}
+	rootCmd.PersistentFlags().StringVar(&utils.ServerFlag, "server", "", "URL of the efa-server, overrides the server of the context")
+	rootCmd.PersistentFlags().StringVar(&utils.ContextFlag, "context", "", "Context used instead of the current context, see \"efa context list\"")
 	rootCmd.AddCommand(fabric.NewGroupCmd())
 	rootCmd.AddCommand(execution.NewGroupCmd())
 	rootCmd.AddCommand(debug.NewGroupCmd())
 	rootCmd.AddCommand(commands.ShowVersionCommand)
 	rootCmd.AddCommand(commands.SupportSaveCommand)
 	rootCmd.AddCommand(device.NewGroupCmd())
+	rootCmd.AddCommand(db.NewGroupCmd())
+	rootCmd.AddCommand(contexts.NewGroupCmd())
+	rootCmd.AddCommand(notification.NewGroupCmd())
 	return rootCmd
 }
+
+//checkFlags rejects an unknown context, an invalid server or output format before any command reaches the server
+func checkFlags(cmd *cobra.Command, args []string) error {
+	if err := utils.ValidateOutput(); err != nil {
+		return err
+	}
+	Current, err := utils.CurrentContext()
+	if err != nil {
+		return err
+	}
+	return utils.ValidateServer(Current.Server)
+}
@@ -0,0 +1,168 @@
+package contexts
+
+import (
+	"efa/infra/cli/utils"
+	"errors"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var setContext utils.Context
+
+//ListCommand provides command to list the contexts of the contexts file
+var ListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "Display the contexts, the current one being marked with *",
+	RunE:  utils.TimedRunE(runContextList),
+}
+
+//UseCommand provides command to select the context used by the commands
+var UseCommand = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Select the context used by the commands",
+	Args:  cobra.ExactArgs(1),
+	RunE:  utils.TimedRunE(runContextUse),
+}
+
+//SetCommand provides command to add or update a context
+var SetCommand = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Add a context or update the given fields of a context",
+	Args:  cobra.ExactArgs(1),
+	RunE:  utils.TimedRunE(runContextSet),
+}
+
+//DeleteCommand provides command to remove a context
+var DeleteCommand = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Remove a context",
+	Args:  cobra.ExactArgs(1),
+	RunE:  utils.TimedRunE(runContextDelete),
+}
+
+func init() {
+	utils.AddOutputFlag(ListCommand)
+	SetCommand.Flags().StringVar(&setContext.Server, "server", "", "URL of the efa-server, like http://<host>:8081")
+	SetCommand.Flags().StringVar(&setContext.Fabric, "fabric", "", "Fabric used by the commands")
+	SetCommand.Flags().StringVar(&setContext.Username, "username", "", "Username of the devices added by the commands")
+	SetCommand.Flags().StringVar(&setContext.Password, "password", "", "Password of the devices added by the commands")
+}
+
+func runContextList(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	Config, err := utils.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if utils.IsStructuredOutput() {
+		//The device passwords are never displayed
+		for iter := range Config.Contexts {
+			Config.Contexts[iter].Password = ""
+		}
+		return utils.PrintModel(Config)
+	}
+	if len(Config.Contexts) == 0 {
+		fmt.Printf("No contexts in %s\n", utils.ConfigFile())
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Current", "Name", "Server", "Fabric", "Username"})
+	for _, Context := range Config.Contexts {
+		Current := ""
+		if Context.Name == Config.CurrentContext {
+			Current = "*"
+		}
+		table.Append([]string{Current, Context.Name, Context.Server, Context.Fabric, Context.Username})
+	}
+	table.Render()
+	return nil
+}
+
+func runContextUse(cmd *cobra.Command, args []string) error {
+	Config, err := utils.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if _, found := Config.Find(args[0]); !found {
+		return fmt.Errorf("Context %s not found in %s", args[0], utils.ConfigFile())
+	}
+	Config.CurrentContext = args[0]
+	if err = Config.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Switched to context %s\n", args[0])
+	return nil
+}
+
+func runContextSet(cmd *cobra.Command, args []string) error {
+	Config, err := utils.LoadConfig()
+	if err != nil {
+		return err
+	}
+	Context, found := Config.Find(args[0])
+	if !found {
+		if len(setContext.Server) == 0 {
+			return errors.New("Required flag \"server\" for a new context")
+		}
+		Config.Contexts = append(Config.Contexts, utils.Context{Name: args[0]})
+		Context = &Config.Contexts[len(Config.Contexts)-1]
+	}
+	if len(setContext.Server) != 0 {
+		if err = utils.ValidateServer(setContext.Server); err != nil {
+			return err
+		}
+		Context.Server = setContext.Server
+	}
+	if cmd.Flags().Changed("fabric") {
+		Context.Fabric = setContext.Fabric
+	}
+	if cmd.Flags().Changed("username") {
+		Context.Username = setContext.Username
+	}
+	if cmd.Flags().Changed("password") {
+		Context.Password = setContext.Password
+	}
+	if len(Config.CurrentContext) == 0 {
+		Config.CurrentContext = args[0]
+	}
+	if err = Config.Save(); err != nil {
+		return err
+	}
+	if found {
+		fmt.Printf("Context %s updated\n", args[0])
+	} else {
+		fmt.Printf("Context %s added\n", args[0])
+	}
+	return nil
+}
+
+func runContextDelete(cmd *cobra.Command, args []string) error {
+	Config, err := utils.LoadConfig()
+	if err != nil {
+		return err
+	}
+	Contexts := make([]utils.Context, 0, len(Config.Contexts))
+	for _, Context := range Config.Contexts {
+		if Context.Name != args[0] {
+			Contexts = append(Contexts, Context)
+		}
+	}
+	if len(Contexts) == len(Config.Contexts) {
+		return fmt.Errorf("Context %s not found in %s", args[0], utils.ConfigFile())
+	}
+	Config.Contexts = Contexts
+	if Config.CurrentContext == args[0] {
+		Config.CurrentContext = ""
+	}
+	if err = Config.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Context %s deleted\n", args[0])
+	return nil
+}
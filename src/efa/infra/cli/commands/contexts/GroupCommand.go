@@ -0,0 +1,22 @@
+package contexts
+
+import (
+	"efa/infra/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Context commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Context commands, a context names an efa-server with its fabric and device credentials",
+		//The contexts are managed even when the current context is not valid
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return utils.ValidateOutput() },
+	}
+	cmd.AddCommand(ListCommand)
+	cmd.AddCommand(UseCommand)
+	cmd.AddCommand(SetCommand)
+	cmd.AddCommand(DeleteCommand)
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	"efa/infra/constants"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+//BackupCommand provides command to save the database to a new backup
+var BackupCommand = &cobra.Command{
+	Use:   "backup",
+	Short: "Save the database to a new backup",
+	RunE:  utils.TimedRunE(runBackup),
+}
+
+//BackupListCommand provides command to list the backups of the database
+var BackupListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "Display the backups of the database, the most recent first",
+	RunE:  utils.TimedRunE(runBackupList),
+}
+
+func init() {
+	BackupCommand.AddCommand(BackupListCommand)
+	utils.AddOutputFlag(BackupListCommand)
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		cmd.Help()
+		return nil
+	}
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	Backup, _, err := api.DatabaseApi.CreateDatabaseBackup(context.Background())
+	if err != nil {
+		handleDatabaseErrorResponse("Backup", err)
+		return nil
+	}
+	fmt.Printf("Backup %s [Success]\n", Backup.Name)
+	return nil
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DatabaseApi.GetDatabaseBackups(context.Background())
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleDatabaseErrorResponse("Backup List", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Name", "Created At", "Schema Version", "Size"})
+	table.SetRowLine(true)
+	for _, Backup := range response.Backups {
+		table.Append([]string{Backup.Name, Backup.CreatedAt.Local().Format(constants.DefaultTimeFormat),
+			fmt.Sprintf("%d", Backup.SchemaVersion), fmt.Sprintf("%d", Backup.Size)})
+	}
+	table.Render()
+	return nil
+}
+
+func handleDatabaseErrorResponse(Operation string, errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	//Body Contains the Error Obect in JSON
+	fmt.Printf("%s [Failed]\n", Operation)
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -0,0 +1,19 @@
+package db
+
+import (
+	"efa/infra/cli/commands/db/migrate"
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Database commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Database commands",
+	}
+	cmd.AddCommand(BackupCommand)
+	cmd.AddCommand(RestoreCommand)
+	cmd.AddCommand(migrate.NewGroupCmd())
+
+	return cmd
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+//RestoreCommand provides command to replace the database with a backup
+var RestoreCommand = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Replace the database with a backup, the operations are stopped during the restore",
+	Long: "Replace the database with a backup listed by \"db backup list\". The database is backed up " +
+		"before the restore and the restored schema is migrated to the version of the application.",
+	RunE: utils.TimedRunE(runRestore),
+}
+
+func init() {
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		fmt.Println("The name of the backup should be the only argument.")
+		cmd.Help()
+		return nil
+	}
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DatabaseApi.RestoreDatabaseBackup(context.Background(), args[0])
+	if err != nil {
+		handleDatabaseErrorResponse("Restore", err)
+		return nil
+	}
+	fmt.Println("Restore [Success]")
+	fmt.Println(response.Message)
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrate
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Database schema migration commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Database schema migration commands",
+	}
+	cmd.AddCommand(StatusCommand)
+
+	return cmd
+}
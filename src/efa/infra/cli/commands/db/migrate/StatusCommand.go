@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	"efa/infra/constants"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+//StatusCommand provides command to display the version of the database schema and the applied migrations
+var StatusCommand = &cobra.Command{
+	Use:   "status",
+	Short: "Display the version of the database schema and the state of each migration",
+	RunE:  utils.TimedRunE(runMigrateStatus),
+}
+
+func init() {
+	utils.AddOutputFlag(StatusCommand)
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DatabaseApi.GetDatabaseMigrations(context.Background())
+	if err != nil {
+		return utils.RequestError(err, handleMigrateStatusErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	fmt.Printf("Schema Version: %d\n", response.CurrentVersion)
+	fmt.Printf("Latest Version: %d\n", response.LatestVersion)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Version", "Description", "Status", "Applied At"})
+	table.SetRowLine(true)
+	for _, Migration := range response.Migrations {
+		Status, AppliedAt := "Pending", ""
+		if Migration.Applied {
+			Status, AppliedAt = "Applied", Migration.AppliedAt.Format(constants.DefaultTimeFormat)
+		}
+		if Migration.Version > response.LatestVersion {
+			//Applied by a newer release of the application
+			Status = "Unknown"
+		}
+		table.Append([]string{fmt.Sprintf("%d", Migration.Version), Migration.Description, Status, AppliedAt})
+	}
+	table.Render()
+	return nil
+}
+
+func handleMigrateStatusErrorResponse(errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	//Body Contains the Error Obect in JSON
+	fmt.Println("Migrate Status [Failed]")
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -12,7 +12,6 @@ import (
 	"context"
 	"errors"
 
-	"encoding/json"
 )
 
 var (
@@ -68,9 +67,12 @@ func runClearFabric(cmd *cobra.Command, args []string) error {
 	ClearRequest.Username = username
 	ClearRequest.Password = password
 
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
-	_, _, err := api.ClearConfigApi.ClearConfig(context.Background(), map[string]interface{}{"switches": ClearRequest})
+	_, _, err = api.ClearConfigApi.ClearConfig(context.Background(), map[string]interface{}{"switches": ClearRequest})
 	if err != nil {
 		handleClearResponse(err)
 	} else {
@@ -87,17 +89,6 @@ func handleClearResponse(errorObject error) {
 	if utils.IsServerConnectionError(errorObject) {
 		return
 	}
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-
-	if len(errorMessageList) == 2 {
-		var ErrorModel openAPI.ErrorModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &ErrorModel)
-		if err == nil {
-			fmt.Println(ErrorModel.Message)
-		}
-	} else {
-		//Generic Error, Just print it
-		fmt.Println("\t" + errorObject.Error())
-	}
+	utils.PrintErrorModel(errorObject)
 
 }
@@ -0,0 +1,59 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+//AllocationClearCommand provides command to clear the values pinned to a device
+var AllocationClearCommand = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the ASN, Loopback or P2P IPs pinned to a device",
+	RunE:  utils.TimedRunE(runAllocationClear),
+}
+
+func init() {
+	AllocationClearCommand.Flags().StringVar(&allocationDevice, "device", "", "Device IP Address")
+	AllocationClearCommand.Flags().StringVar(&allocationType, "type", "",
+		"Type of the pins to be cleared [asn|loopback|p2p], all the pins of the device when omitted")
+	AllocationClearCommand.Flags().StringVar(&allocationInterface, "interface", "",
+		"Ethernet interface whose P2P pin is cleared, all the P2P pins of the device when omitted")
+	AllocationClearCommand.MarkFlagRequired("device")
+}
+
+func runAllocationClear(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	PinRequest := openAPI.AllocationPinRequest{
+		FabricName:    utils.FabricName(),
+		IpAddress:     allocationDevice,
+		Type_:         allocationType,
+		InterfaceName: allocationInterface,
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DeviceAllocationApi.DeleteDeviceAllocation(context.Background(),
+		map[string]interface{}{"allocation": PinRequest})
+	if err != nil {
+		fmt.Println("Device Allocation Clear [Failed]")
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		utils.PrintErrorModel(err)
+		return nil
+	}
+	fmt.Println(response.Message)
+	fmt.Println("Device Allocation Clear [Success]")
+	return nil
+}
@@ -0,0 +1,24 @@
+package device
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	allocationDevice    string
+	allocationType      string
+	allocationValue     string
+	allocationInterface string
+)
+
+//AllocationGroupCmd provides grouping for the commands pinning pool values to devices
+func AllocationGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allocation",
+		Short: "Commands to pin ASNs, Loopback and P2P IPs to devices ahead of their discovery",
+	}
+	cmd.AddCommand(AllocationSetCommand)
+	cmd.AddCommand(AllocationShowCommand)
+	cmd.AddCommand(AllocationClearCommand)
+	return cmd
+}
@@ -0,0 +1,66 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+//AllocationSetCommand provides command to pin an ASN, a Loopback IP or a P2P IP to a device
+var AllocationSetCommand = &cobra.Command{
+	Use:   "set",
+	Short: "Pin an ASN, a Loopback IP or a P2P IP to a device",
+	RunE:  utils.TimedRunE(runAllocationSet),
+}
+
+func init() {
+	AllocationSetCommand.Flags().StringVar(&allocationDevice, "device", "", "Device IP Address")
+	AllocationSetCommand.Flags().StringVar(&allocationType, "type", "", "Type of the pinned value [asn|loopback|p2p]")
+	AllocationSetCommand.Flags().StringVar(&allocationValue, "value", "", "ASN, Loopback IP or P2P IP to be pinned")
+	AllocationSetCommand.Flags().StringVar(&allocationInterface, "interface", "",
+		"Ethernet interface the P2P IP is pinned to, e.g. 0/1")
+	AllocationSetCommand.MarkFlagRequired("device")
+	AllocationSetCommand.MarkFlagRequired("type")
+	AllocationSetCommand.MarkFlagRequired("value")
+}
+
+func runAllocationSet(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	if allocationType == "p2p" && len(allocationInterface) == 0 {
+		fmt.Println("--interface is required to pin a P2P IP")
+		return nil
+	}
+
+	PinRequest := openAPI.AllocationPinRequest{
+		FabricName:    utils.FabricName(),
+		IpAddress:     allocationDevice,
+		Type_:         allocationType,
+		Value:         allocationValue,
+		InterfaceName: allocationInterface,
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DeviceAllocationApi.UpdateDeviceAllocation(context.Background(),
+		map[string]interface{}{"allocation": PinRequest})
+	if err != nil {
+		fmt.Println("Device Allocation Set [Failed]")
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		utils.PrintErrorModel(err)
+		return nil
+	}
+	fmt.Println(response.Message)
+	fmt.Println("Device Allocation Set [Success]")
+	return nil
+}
@@ -0,0 +1,58 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+//AllocationShowCommand provides command to display the values pinned to the devices
+var AllocationShowCommand = &cobra.Command{
+	Use:   "show",
+	Short: "Display the ASN, Loopback and P2P IPs pinned to the devices",
+	RunE:  utils.TimedRunE(runAllocationShow),
+}
+
+func init() {
+	AllocationShowCommand.Flags().StringVar(&allocationDevice, "device", "",
+		"Device IP Address, all the devices of the fabric when omitted")
+	utils.AddOutputFlag(AllocationShowCommand)
+}
+
+func runAllocationShow(cmd *cobra.Command, args []string) error {
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	Optionals := map[string]interface{}{}
+	if len(allocationDevice) != 0 {
+		Optionals["ipAddress"] = allocationDevice
+	}
+	response, _, err := api.DeviceAllocationApi.GetDeviceAllocation(context.Background(), utils.FabricName(), Optionals)
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleDeviceErrorResponse("Device Allocation Show", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	if len(response.Pins) == 0 {
+		fmt.Println("No Allocation Pins")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Device", "Type", "Interface", "Pool", "Value"})
+	table.SetRowLine(true)
+	for _, Pin := range response.Pins {
+		table.Append([]string{Pin.IpAddress, Pin.Type_, Pin.InterfaceName, Pin.PoolName, Pin.Value})
+	}
+	table.Render()
+	return nil
+}
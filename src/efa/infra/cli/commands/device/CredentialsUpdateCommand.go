@@ -53,7 +53,10 @@ func updateSwitchCredentials(cmd *cobra.Command, args []string) error {
 	UpdateSwitchesParams.Username = username
 	UpdateSwitchesParams.Password = password
 
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
 
 	//First Add Switches to the Fabric
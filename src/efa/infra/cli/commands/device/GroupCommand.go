@@ -11,5 +11,9 @@ func NewGroupCmd() *cobra.Command {
 		Short: "Device commands",
 	}
 	cmd.AddCommand(CredentialsGroupCmd())
+	cmd.AddCommand(SettingsGroupCmd())
+	cmd.AddCommand(MaintenanceGroupCmd())
+	cmd.AddCommand(ReplaceCommand)
+	cmd.AddCommand(AllocationGroupCmd())
 	return cmd
 }
@@ -0,0 +1,74 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var maintenanceDevice string
+
+//MaintenanceGroupCmd provides grouping for device maintenance mode commands
+func MaintenanceGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Commands to drain a device into maintenance mode and restore it",
+	}
+	cmd.AddCommand(MaintenanceEnableCommand)
+	cmd.AddCommand(MaintenanceDisableCommand)
+	return cmd
+}
+
+//MaintenanceEnableCommand provides command to drain the traffic of a device and put it into maintenance mode
+var MaintenanceEnableCommand = &cobra.Command{
+	Use:   "enable",
+	Short: "Shut the BGP sessions of the device and put it into maintenance mode",
+	RunE: utils.TimedRunE(func(cmd *cobra.Command, args []string) error {
+		return updateMaintenanceMode(args, true)
+	}),
+}
+
+//MaintenanceDisableCommand provides command to restore the traffic of a device and take it out of maintenance mode
+var MaintenanceDisableCommand = &cobra.Command{
+	Use:   "disable",
+	Short: "Restore the BGP sessions of the device and take it out of maintenance mode",
+	RunE: utils.TimedRunE(func(cmd *cobra.Command, args []string) error {
+		return updateMaintenanceMode(args, false)
+	}),
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{MaintenanceEnableCommand, MaintenanceDisableCommand} {
+		cmd.Flags().StringVar(&maintenanceDevice, "device", "", "Device IP Address")
+		cmd.MarkFlagRequired("device")
+	}
+}
+
+func updateMaintenanceMode(args []string, enable bool) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DeviceMaintenanceApi.UpdateDeviceMaintenance(context.Background(), utils.FabricName(),
+		maintenanceDevice, enable)
+	if err != nil {
+		fmt.Println("Maintenance Mode Update [Failed]")
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		utils.PrintErrorModel(err)
+		return nil
+	}
+	fmt.Println(response.Message)
+	fmt.Println("Maintenance Mode Update [Success]")
+	return nil
+}
@@ -0,0 +1,71 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replaceOldDevice string
+	replaceNewDevice string
+	replaceUsername  string
+	replacePassword  string
+)
+
+//ReplaceCommand provides command to replace a device of the fabric with a new unit
+var ReplaceCommand = &cobra.Command{
+	Use:   "replace",
+	Short: "Replace a device with a new unit, keeping its allocations and configs",
+	RunE:  utils.TimedRunE(replaceDevice),
+}
+
+func init() {
+	ReplaceCommand.Flags().StringVar(&replaceOldDevice, "old", "", "IP Address of the device being replaced")
+	ReplaceCommand.Flags().StringVar(&replaceNewDevice, "new", "", "IP Address of the replacement device")
+	ReplaceCommand.Flags().StringVar(&replaceUsername, "username", "", "Username for the replacement device")
+	ReplaceCommand.Flags().StringVar(&replacePassword, "password", "", "Password for the replacement device")
+	ReplaceCommand.MarkFlagRequired("old")
+	ReplaceCommand.MarkFlagRequired("new")
+}
+
+func replaceDevice(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	if replaceUsername == "root" {
+		fmt.Println("\"root\" user cannot be used to manage switches.")
+		return nil
+	}
+
+	ReplaceRequest := openAPI.DeviceReplaceRequest{
+		FabricName:   utils.FabricName(),
+		OldIpAddress: replaceOldDevice,
+		NewIpAddress: replaceNewDevice,
+		Username:     replaceUsername,
+		Password:     replacePassword,
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DeviceReplaceApi.ReplaceDevice(context.Background(),
+		map[string]interface{}{"deviceReplace": ReplaceRequest})
+	if err != nil {
+		fmt.Println("Device Replace [Failed]")
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		utils.PrintErrorModel(err)
+		return nil
+	}
+	fmt.Println(response.Message)
+	fmt.Println("Device Replace [Success]")
+	return nil
+}
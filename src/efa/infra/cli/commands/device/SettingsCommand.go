@@ -0,0 +1,16 @@
+package device
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//SettingsGroupCmd provides grouping for per-device settings commands
+func SettingsGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settings",
+		Short: "Commands to manage per-device overrides of the fabric settings",
+	}
+	cmd.AddCommand(SettingsUpdateCommand)
+	cmd.AddCommand(SettingsShowCommand)
+	return cmd
+}
@@ -0,0 +1,86 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+//SettingsShowCommand provides command to display the fabric settings of a device along with its overrides
+var SettingsShowCommand = &cobra.Command{
+	Use:   "show",
+	Short: "Display fabric settings and overrides for a device",
+	RunE:  utils.TimedRunE(runDeviceSettingsShow),
+}
+
+func init() {
+	SettingsShowCommand.Flags().StringVar(&settingsDevice, "device", "", "Device IP Address")
+	SettingsShowCommand.MarkFlagRequired("device")
+	utils.AddOutputFlag(SettingsShowCommand)
+}
+
+//deviceSettingsDisplayNames lists the overridable settings in display order
+var deviceSettingsDisplayNames = [][2]string{
+	{"MTU", "MTU"},
+	{"IPMTU", "IPMTU"},
+	{"BFDEnable", "BFD Enable"},
+	{"BFDTx", "BFD Tx"},
+	{"BFDRx", "BFD Rx"},
+	{"BFDMultiplier", "BFD Multiplier"},
+	{"MaxPaths", "MaxPaths"},
+	{"AllowASIn", "AllowAsIn"},
+	{"LeafPeerGroup", "Leaf PeerGroup"},
+	{"SpinePeerGroup", "Spine PeerGroup"},
+	{"ArpAgingTimeout", "ARP Aging Timeout"},
+	{"MacAgingTimeout", "MAC Aging Timeout"},
+	{"MacAgingConversationalTimeout", "MAC Aging Conversational Timeout"},
+	{"MacMoveLimit", "MAC Move Limit"},
+	{"DuplicateMacTimer", "Duplicate MAC Timer"},
+	{"DuplicateMaxTimerMaxCount", "Duplicate MAC Timer MAX Count"},
+}
+
+func runDeviceSettingsShow(cmd *cobra.Command, args []string) error {
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.DeviceSettingsApi.GetDeviceSettings(context.Background(), utils.FabricName(), settingsDevice)
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleDeviceErrorResponse("Device settings Show", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Name", "Fabric Value", "Device Value", "Effective Value"})
+	table.SetRowLine(true)
+	for _, setting := range deviceSettingsDisplayNames {
+		FabricValue := response.FabricSettings[setting[0]]
+		DeviceValue := response.DeviceSettings[setting[0]]
+		EffectiveValue := FabricValue
+		if len(DeviceValue) != 0 {
+			EffectiveValue = DeviceValue
+		}
+		table.Append([]string{setting[1], FabricValue, DeviceValue, EffectiveValue})
+	}
+	fmt.Printf("Device: %s\n", settingsDevice)
+	table.Render()
+	return nil
+}
+
+func handleDeviceErrorResponse(Operation string, errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	fmt.Printf("%s [Failed]\n", Operation)
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -0,0 +1,110 @@
+package device
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+	"reflect"
+)
+
+//DeviceSettings holds the fabric settings which can be overridden per device
+type DeviceSettings struct {
+	MTU                           string
+	IPMTU                         string
+	BFDEnable                     string
+	BFDTx                         string
+	BFDRx                         string
+	BFDMultiplier                 string
+	MaxPaths                      string
+	AllowASIn                     string
+	LeafPeerGroup                 string
+	SpinePeerGroup                string
+	ArpAgingTimeout               string
+	MacAgingTimeout               string
+	MacAgingConversationalTimeout string
+	MacMoveLimit                  string
+	DuplicateMacTimer             string
+	DuplicateMaxTimerMaxCount     string
+}
+
+var (
+	settingsDevice        string
+	deviceSettingsRequest DeviceSettings
+)
+
+//SettingsUpdateCommand provides command to override the fabric settings for a device
+var SettingsUpdateCommand = &cobra.Command{
+	Use:   "update",
+	Short: "Override fabric settings for a device. Use \"default\" to remove an override.",
+	RunE:  utils.TimedRunE(runDeviceSettingsUpdate),
+}
+
+func init() {
+	SettingsUpdateCommand.Flags().SortFlags = false
+	SettingsUpdateCommand.Flags().StringVar(&settingsDevice, "device", "", "Device IP Address")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.MTU, "mtu", "", "The MTU size in bytes <Number:1548-9216>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.IPMTU, "ip-mtu", "", "For SLX IPV4/IPV6 MTU size in bytes <Number:1300-9194>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.BFDEnable, "bfd-enable", "", "BFD enabled <STRING Yes/No>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.BFDTx, "bfd-tx", "", "BFD desired min transmit interval in milliseconds <NUMBER: 50-30000>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.BFDRx, "bfd-rx", "", "BFD desired min receive interval in milliseconds <NUMBER: 50-30000>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.BFDMultiplier, "bfd-multiplier", "", "BFD detection time multiplier <NUMBER: 3-50>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.MaxPaths, "max-paths", "", "Forward packets over multiple paths<Number:1-64>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.AllowASIn, "allow-as-in", "", "Disables the AS_PATH check of the routes learned from the AS<Number:1-10>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.LeafPeerGroup, "leaf-peer-group", "", "Leaf Peer Group Name <WORD: 1-63>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.SpinePeerGroup, "spine-peer-group", "", "Spine Peer Group Name <WORD: 1-63>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.ArpAgingTimeout, "arp-aging-timeout", "", "Determines how long an ARP entry stays in cache <NUMBER: 60-100000>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.MacAgingTimeout, "mac-aging-timeout", "", "MAC Aging Timeout <NUMBER: 0|60-86400>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.MacAgingConversationalTimeout, "mac-aging-conversation-timeout", "", "MAC Conversational Aging time in seconds<NUMBER: 0|60-100000>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.MacMoveLimit, "mac-move-limit", "", "MAC move detect limit <NUMBER: 5-500>")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.DuplicateMacTimer, "duplicate-mac-timer", "", "Duplicate Mac Timer")
+	SettingsUpdateCommand.Flags().StringVar(&deviceSettingsRequest.DuplicateMaxTimerMaxCount, "duplicate-mac-timer-max-count", "", "Duplicate Mac Timer Max Count")
+	SettingsUpdateCommand.MarkFlagRequired("device")
+}
+
+//PrepareDeviceSettingsRequest prepares the Device Setting Request
+func (DeviceUpdate *DeviceSettings) PrepareDeviceSettingsRequest(DeviceSetting *openAPI.DeviceSettings) {
+	val := reflect.ValueOf(DeviceUpdate).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		var DeviceParameter openAPI.FabricParameter
+		DeviceParameter.Key = val.Type().Field(i).Name
+		DeviceParameter.Value = val.Field(i).String()
+		DeviceSetting.Keyval = append(DeviceSetting.Keyval, DeviceParameter)
+	}
+}
+
+func runDeviceSettingsUpdate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	if deviceSettingsRequest == (DeviceSettings{}) {
+		fmt.Println("No Device Settings Update is Requested")
+		return nil
+	}
+	var DeviceSetting openAPI.DeviceSettings
+	DeviceSetting.FabricName = utils.FabricName()
+	DeviceSetting.DeviceIp = settingsDevice
+	deviceSettingsRequest.PrepareDeviceSettingsRequest(&DeviceSetting)
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	_, _, err = api.DeviceSettingsApi.UpdateDeviceSettings(context.Background(),
+		map[string]interface{}{"deviceSettings": DeviceSetting})
+	if err != nil {
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		fmt.Printf("%s Device settings Update Failed\n", settingsDevice)
+		fmt.Printf("Reason: \n")
+		utils.PrintErrorModel(err)
+		return nil
+	}
+	fmt.Printf("%s Device settings Update Successful\n", settingsDevice)
+	return nil
+}
@@ -27,6 +27,7 @@ func init() {
 	ShowCommand.Flags().Int32Var(&limit, "limit", 10, "Limit the number of executions to be listed. Value \"0\" will list all the executions.")
 	ShowCommand.Flags().StringVar(&status, "status", "all", "Filter the executions based on the status(failed/succeeded/all)")
 	ShowCommand.Flags().StringVar(&exID, "id", "", "Filter the executions based on execution id. \"limit\" and \"status\" flags are ignored when \"id\" flag is given.")
+	utils.AddOutputFlag(ShowCommand)
 }
 
 func runExecutionList(cmd *cobra.Command, args []string) error {
@@ -37,7 +38,10 @@ func runExecutionList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 	//Get base configuration
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
 	if status != "all" && status != "failed" && status != "succeeded" {
 		fmt.Println("Wrong value for the flag \"status\".")
@@ -50,11 +54,10 @@ func runExecutionList(cmd *cobra.Command, args []string) error {
 		ExecutionsResponse, _, err := api.ExecutionListApi.ExecutionList(context.Background(), limit, map[string]interface{}{"status": status})
 
 		if err != nil {
-			if utils.IsServerConnectionError(err) {
-				return nil
-			}
-			fmt.Println(err.Error())
-			return nil
+			return utils.RequestError(err, handleExecutionErrorResponse)
+		}
+		if utils.IsStructuredOutput() {
+			return utils.PrintModel(ExecutionsResponse)
 		}
 
 		//Render using Tables
@@ -74,11 +77,10 @@ func runExecutionList(cmd *cobra.Command, args []string) error {
 		ExecutionDetails, _, err := api.ExecutionGetApi.ExecutionGet(context.Background(), exID)
 
 		if err != nil {
-			if utils.IsServerConnectionError(err) {
-				return nil
-			}
-			fmt.Println(err.Error())
-			return nil
+			return utils.RequestError(err, handleExecutionErrorResponse)
+		}
+		if utils.IsStructuredOutput() {
+			return utils.PrintModel(ExecutionDetails)
 		}
 
 		tab := new(tabwriter.Writer)
@@ -96,3 +98,10 @@ func runExecutionList(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func handleExecutionErrorResponse(errorObject error) {
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	fmt.Println(errorObject.Error())
+}
@@ -0,0 +1,228 @@
+package fabric
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"sort"
+	"strings"
+)
+
+var (
+	applyFile    string
+	applyDryRun  bool
+	applyPersist bool
+	applyForce   bool
+	//applyPreflightOverride lists the pre-flight checks whose errors do not fail the validation
+	applyPreflightOverride string
+)
+
+//ApplyCommand provides command to bring the IP Fabric to a fabric spec
+var ApplyCommand = &cobra.Command{
+	Use:   "apply",
+	Short: "Update the settings and the devices of the IP Fabric to match a fabric spec file",
+	Long: "Update the settings and the devices of the IP Fabric to match a fabric spec file. The fabric settings " +
+		"can be updated only once the devices are removed, a spec keeping devices of the fabric while changing the " +
+		"settings is refused. The passwords are masked by the server, so a spec changing a password already set " +
+		"is not detected: use \"efa fabric bgp-auth rotate\" to change the BGP passwords of the fabric.",
+	RunE: utils.TimedRunE(runFabricApply),
+}
+
+func init() {
+	ApplyCommand.Flags().StringVarP(&applyFile, "file", "f", "", "Fabric spec file, YAML or JSON")
+	ApplyCommand.Flags().BoolVar(&applyDryRun, "dry-run", false, "Display the changes without applying them")
+	ApplyCommand.Flags().BoolVar(&applyPersist, "persist", false, "Persist the configuration on the devices")
+	ApplyCommand.Flags().BoolVar(&applyForce, "force", false, "Force the configuration on the devices")
+	ApplyCommand.Flags().StringVar(&applyPreflightOverride, "preflight-override", "", "Comma separated pre-flight checks whose errors do not fail the validation, among "+
+		"connection, speed, mtu, firmware, admin-down, ip-config and local-as")
+	ApplyCommand.MarkFlagRequired("file")
+}
+
+//runFabricApply compares the spec with the fabric and drives the existing REST calls in order:
+//removal of the devices, settings update, addition of the devices, per-device settings, validate and configure
+func runFabricApply(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	Spec, err := loadFabricSpec(applyFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+	State, err := getFabricState(api, Spec.Fabric)
+	if err != nil {
+		handleShowErrorResponse(err)
+		return nil
+	}
+	Plan, err := planFabricSpec(Spec, State)
+	if err != nil {
+		return err
+	}
+	if Plan.empty() {
+		fmt.Printf("Fabric %s is up to date with %s\n", Spec.Fabric, applyFile)
+		return nil
+	}
+	printFabricPlan(Plan)
+	if applyDryRun {
+		return nil
+	}
+
+	var Username, Password string
+	if Plan.adds() {
+		Username = Spec.Credentials.Username
+		if len(Username) == 0 {
+			//The spec does not reference the credentials, those of the context are used
+			Username, Password = utils.ContextCredentials()
+			if len(Username) == 0 || len(Password) == 0 {
+				return errors.New("The spec credentials or the context should name the username and the password of the devices to be added")
+			}
+		} else if Password, err = Spec.Credentials.password(); err != nil {
+			return err
+		}
+		if Username == "root" {
+			return errors.New("\"root\" user cannot be used to manage switches.")
+		}
+	}
+
+	if len(Plan.RemoveDevices) != 0 || len(Plan.RemoveRacks) != 0 {
+		DelSwitchReq := openAPI.DeleteSwitchesRequest{Switches: Plan.RemoveDevices, DeviceCleanup: true, Persist: applyPersist}
+		for _, Rack := range Plan.RemoveRacks {
+			DelSwitchReq.Racks = append(DelSwitchReq.Racks, openAPI.Rack{RackDevices: Rack})
+		}
+		SwitchesdataResponse, _, err := api.SwitchesApi.DeleteSwitches(context.Background(),
+			map[string]interface{}{"switches": DelSwitchReq})
+		if err != nil {
+			handleDeleteSwitchesErrorResponse(err)
+			return nil
+		}
+		handleDeleteSwitchesResponse(&SwitchesdataResponse)
+	}
+
+	if len(Plan.Settings) != 0 {
+		FabricSetting := openAPI.FabricSettings{Name: Spec.Fabric}
+		for _, Setting := range Plan.Settings {
+			FabricSetting.Keyval = append(FabricSetting.Keyval, openAPI.FabricParameter{Key: Setting.Name, Value: Setting.New})
+		}
+		if _, _, err = api.FabricApi.UpdateFabric(context.Background(),
+			map[string]interface{}{"fabricSettings": FabricSetting}); err != nil {
+			handleApplySettingsErrorResponse("Update Fabric Settings", err)
+			return nil
+		}
+		fmt.Println("Update Fabric Settings [Success]")
+	}
+
+	if Plan.adds() {
+		NewSwitches := openAPI.NewSwitches{Fabric: Spec.Fabric, SpineIpAddress: Plan.AddSpines,
+			LeafIpAddress: Plan.AddLeaves, Username: Username, Password: Password, Force: applyForce}
+		for _, Rack := range Plan.AddRacks {
+			NewSwitches.Racks = append(NewSwitches.Racks, openAPI.Rack{RackDevices: Rack})
+		}
+		SwitchesdataResponse, _, err := api.SwitchesApi.CreateSwitches(context.Background(),
+			map[string]interface{}{"switches": NewSwitches})
+		if err != nil {
+			handleAddSwitchesErrorResponse(err)
+			return nil
+		}
+		handleAddSwitchesResponse(&SwitchesdataResponse)
+	}
+
+	Devices := make([]string, 0, len(Plan.DeviceSettings))
+	for IP := range Plan.DeviceSettings {
+		Devices = append(Devices, IP)
+	}
+	sort.Strings(Devices)
+	for _, IP := range Devices {
+		DeviceSetting := openAPI.DeviceSettings{FabricName: Spec.Fabric, DeviceIp: IP}
+		for _, Change := range Plan.DeviceSettings[IP] {
+			Value := Change.New
+			if len(Value) == 0 {
+				Value = "default"
+			}
+			DeviceSetting.Keyval = append(DeviceSetting.Keyval, openAPI.FabricParameter{Key: Change.Name, Value: Value})
+		}
+		if _, _, err = api.DeviceSettingsApi.UpdateDeviceSettings(context.Background(),
+			map[string]interface{}{"deviceSettings": DeviceSetting}); err != nil {
+			handleApplySettingsErrorResponse(fmt.Sprintf("Update Device %s Settings", IP), err)
+			return nil
+		}
+		fmt.Printf("Update Device %s Settings [Success]\n", IP)
+	}
+
+	//Removing devices alone does not require a configure, as with "efa fabric deconfigure", and the
+	//settings cannot be updated once the fabric has devices
+	if !Plan.adds() && len(Plan.DeviceSettings) == 0 {
+		return nil
+	}
+	fmt.Println("")
+	FabricValidateResponse, _, err := api.FabricValidationApi.ValidateFabric(context.Background(), Spec.Fabric,
+		map[string]interface{}{"preflightOverride": applyPreflightOverride})
+	if err != nil {
+		handleValidateErrorResponse(err)
+		return nil
+	}
+	if err = handleValidateResponse(&FabricValidateResponse, "Failed"); err != nil {
+		return nil
+	}
+
+	fmt.Println("")
+	ConfigureFabricResponse, _, err := api.ConfigureFabricApi.ConfigureFabric(context.Background(), Spec.Fabric,
+		map[string]interface{}{"persist": applyPersist, "force": applyForce})
+	if err != nil {
+		handleConfigureErrorResponse(err)
+		return nil
+	}
+	handleConfigureResponse(&ConfigureFabricResponse)
+	return nil
+}
+
+//printFabricPlan displays the changes of the settings and the devices to be applied
+func printFabricPlan(Plan fabricPlan) {
+	Changes := make([]openAPI.ConfigChange, 0)
+	for _, Setting := range Plan.Settings {
+		if specPasswordSettings[Setting.Name] && Setting.New != "none" {
+			Setting.New = "******"
+		}
+		Changes = append(Changes, Setting)
+	}
+	for _, IP := range Plan.RemoveDevices {
+		Changes = append(Changes, openAPI.ConfigChange{Device: IP, Kind: "Device", Change: "Removed"})
+	}
+	for _, Rack := range Plan.RemoveRacks {
+		Changes = append(Changes, openAPI.ConfigChange{Device: strings.Join(Rack, ","), Kind: "Rack", Change: "Removed"})
+	}
+	for _, IP := range Plan.AddSpines {
+		Changes = append(Changes, openAPI.ConfigChange{Device: IP, Kind: "Device", Name: "Spine", Change: "Added"})
+	}
+	for _, IP := range Plan.AddLeaves {
+		Changes = append(Changes, openAPI.ConfigChange{Device: IP, Kind: "Device", Name: "Leaf", Change: "Added"})
+	}
+	for _, Rack := range Plan.AddRacks {
+		Changes = append(Changes, openAPI.ConfigChange{Device: strings.Join(Rack, ","), Kind: "Rack", Change: "Added"})
+	}
+	Devices := make([]string, 0, len(Plan.DeviceSettings))
+	for IP := range Plan.DeviceSettings {
+		Devices = append(Devices, IP)
+	}
+	sort.Strings(Devices)
+	for _, IP := range Devices {
+		Changes = append(Changes, Plan.DeviceSettings[IP]...)
+	}
+	printConfigChanges(Changes)
+}
+
+func handleApplySettingsErrorResponse(Operation string, errorObject error) {
+	fmt.Printf("%s [Failed]\n", Operation)
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -2,11 +2,8 @@ package fabric
 
 import (
 	"context"
-	"efa/infra/cli/commands/fabric/settings"
 	"efa/infra/cli/utils"
-	"efa/infra/constants"
 	openAPI "efa/infra/rest/generated/client"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
@@ -22,6 +19,9 @@ var (
 	password       string
 	force          bool
 	persist        bool
+	progress       bool
+	//preflightOverride lists the pre-flight checks whose errors do not fail the validation
+	preflightOverride string
 )
 
 //ConfigureSwitchCommand provides command to add/update devices in fabric
@@ -32,26 +32,16 @@ var ConfigureSwitchCommand = &cobra.Command{
 }
 
 func init() {
-	fabricName := constants.DefaultFabric
-	cfg := openAPI.NewConfiguration()
-	api := openAPI.NewAPIClient(cfg)
-
-	response, _, _ := api.FabricApi.GetFabric(context.Background(), fabricName)
-	FabricProperties, err := settings.CreateFromMap(response.FabricSettings)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	if FabricProperties.FabricType == utils.CLOSFabricType {
-		ConfigureSwitchCommand.Flags().StringVar(&spineIPaddress, "spine", "", "Comma separated list of spine IP Address/Hostnames")
-		ConfigureSwitchCommand.Flags().StringVar(&leafIPaddress, "leaf", "", "Comma separated list of spine IP Address/Hostnames")
-	} else {
-		ConfigureSwitchCommand.Flags().StringArrayVar(&rackIPaddress, "rack", []string{}, "Comma separated address/host-names for non-clos fabric")
-	}
-	ConfigureSwitchCommand.Flags().StringVar(&username, "username", "", "Username for the list of devices")
-	ConfigureSwitchCommand.Flags().StringVar(&password, "password", "", "Password for the list of devices")
+	ConfigureSwitchCommand.Flags().StringVar(&spineIPaddress, "spine", "", "Comma separated list of spine IP Address/Hostnames for clos fabric")
+	ConfigureSwitchCommand.Flags().StringVar(&leafIPaddress, "leaf", "", "Comma separated list of leaf IP Address/Hostnames for clos fabric")
+	ConfigureSwitchCommand.Flags().StringArrayVar(&rackIPaddress, "rack", []string{}, "Comma separated address/host-names for non-clos fabric")
+	ConfigureSwitchCommand.Flags().StringVar(&username, "username", "", "Username for the list of devices, defaults to the username of the context")
+	ConfigureSwitchCommand.Flags().StringVar(&password, "password", "", "Password for the list of devices, defaults to the password of the context")
 	ConfigureSwitchCommand.Flags().BoolVar(&force, "force", false, "Force the configuration on the devices")
 	ConfigureSwitchCommand.Flags().BoolVar(&persist, "persist", false, "Persist the configuration on the devices")
+	ConfigureSwitchCommand.Flags().BoolVar(&progress, "progress", true, "Display the progress of the devices while they are added and configured")
+	ConfigureSwitchCommand.Flags().StringVar(&preflightOverride, "preflight-override", "", "Comma separated pre-flight checks whose errors do not fail the validation, among "+
+		"connection, speed, mtu, firmware, admin-down, ip-config and local-as")
 }
 
 //runAddSwitch is implemented using three Rest CALLs.
@@ -65,21 +55,31 @@ func runAddSwitch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if username == "root" {
+	//The credentials of the context are used for the devices to be added only
+	Username, Password := username, password
+	if len(Username) == 0 && len(Password) == 0 &&
+		(len(spineIPaddress) != 0 || len(leafIPaddress) != 0 || len(rackIPaddress) != 0) {
+		Username, Password = utils.ContextCredentials()
+	}
+
+	if Username == "root" {
 		fmt.Println("\"root\" user cannot be used to manage switches.")
 		return nil
 	}
 
 	//Using the Default Fabric Name
-	NewSwitches := openAPI.NewSwitches{Fabric: constants.DefaultFabric}
+	NewSwitches := openAPI.NewSwitches{Fabric: utils.FabricName()}
 
-	if (len(username) == 0 && len(password) != 0) || (len(username) != 0 && len(password) == 0) {
+	if (len(Username) == 0 && len(Password) != 0) || (len(Username) != 0 && len(Password) == 0) {
 		return errors.New("Required both flags \"username\" and \"password\"")
 	}
 
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
-	response, _, err := api.FabricApi.GetFabric(context.Background(), constants.DefaultFabric)
+	response, _, err := api.FabricApi.GetFabric(context.Background(), utils.FabricName())
 	if err != nil {
 		handleConfigShowErrorResponse(err)
 		return nil
@@ -89,7 +89,7 @@ func runAddSwitch(cmd *cobra.Command, args []string) error {
 		if len(spineIPaddress) > 0 || len(leafIPaddress) > 0 {
 			return errors.New("Spine and Leaf address should be provided only for CLOS fabric")
 		}
-		if len(rackIPaddress) == 0 && len(username) != 0 {
+		if len(rackIPaddress) == 0 && len(Username) != 0 {
 			return errors.New("Device Credentials to be provided with Device IP address")
 		}
 		// Input Racks cannot be more than 4
@@ -124,7 +124,7 @@ func runAddSwitch(cmd *cobra.Command, args []string) error {
 		if len(leafIPaddress) > 0 {
 			NewSwitches.LeafIpAddress = strings.Split(leafIPaddress, ",")
 		}
-		if len(leafIPaddress) == 0 && len(spineIPaddress) == 0 && len(username) != 0 {
+		if len(leafIPaddress) == 0 && len(spineIPaddress) == 0 && len(Username) != 0 {
 			return errors.New("Device Credentials to be provided with Device IP address")
 		}
 		if !utils.IsValidIPs(NewSwitches.SpineIpAddress) {
@@ -135,13 +135,16 @@ func runAddSwitch(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	NewSwitches.Username = username
-	NewSwitches.Password = password
+	NewSwitches.Username = Username
+	NewSwitches.Password = Password
 	NewSwitches.Force = force
 
 	//First Add Switches to the Fabric
-	SwitchesdataResponse, _, err := api.SwitchesApi.CreateSwitches(context.Background(),
-		map[string]interface{}{"switches": NewSwitches})
+	var SwitchesdataResponse openAPI.SwitchesdataResponse
+	utils.WithProgress(cfg, progress, func() {
+		SwitchesdataResponse, _, err = api.SwitchesApi.CreateSwitches(context.Background(),
+			map[string]interface{}{"switches": NewSwitches})
+	})
 	//Stop further processing if Add devices has any error
 	if err != nil {
 		//Handle error for Create Switches
@@ -153,9 +156,10 @@ func runAddSwitch(cmd *cobra.Command, args []string) error {
 	fmt.Println("")
 
 	//Second Send Request for Validating the fabric
-	FabricValidateResponse, _, err := api.FabricValidationApi.ValidateFabric(context.Background(), NewSwitches.Fabric)
+	FabricValidateResponse, _, err := api.FabricValidationApi.ValidateFabric(context.Background(), NewSwitches.Fabric,
+		map[string]interface{}{"preflightOverride": preflightOverride})
 	if err != nil {
-		fmt.Println(err)
+		handleValidateErrorResponse(err)
 		return nil
 	}
 
@@ -166,8 +170,11 @@ func runAddSwitch(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("")
 	//Third send Request for  Configure the Fabric
-	ConfigureFabricResponse, _, err := api.ConfigureFabricApi.ConfigureFabric(context.Background(), NewSwitches.Fabric,
-		map[string]interface{}{"persist": persist, "force": force})
+	var ConfigureFabricResponse openAPI.ConfigureFabricResponse
+	utils.WithProgress(cfg, progress, func() {
+		ConfigureFabricResponse, _, err = api.ConfigureFabricApi.ConfigureFabric(context.Background(),
+			NewSwitches.Fabric, map[string]interface{}{"persist": persist, "force": force})
+	})
 	if err != nil {
 		//Handle Configure Error Response
 		handleConfigureErrorResponse(err)
@@ -190,35 +197,29 @@ func handleAddSwitchesErrorResponse(errorObject error) {
 	if utils.IsServerConnectionError(errorObject) {
 		return
 	}
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-	if len(errorMessageList) == 2 {
-		var StatusModelList []openAPI.DeviceStatusModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &StatusModelList)
-		if err == nil {
-			for _, errorResponse := range StatusModelList {
-
-				if len(errorResponse.Error_) > 0 {
-					//For clear config
-					if errorResponse.IpAddress == "" {
-						for _, errorResponse := range errorResponse.Error_ {
-							fmt.Printf("\t%s\n", errorResponse.Message)
-						}
-					} else {
-						fmt.Printf("\tAddition of %s device with ip-address = %s [Failed]\n", errorResponse.Role, errorResponse.IpAddress)
-						for _, errorResponse := range errorResponse.Error_ {
-							fmt.Println("\t" + errorResponse.Message)
-						}
-					}
-				} else {
-					fmt.Printf("\tAddition of %s device with ip-address = %s [Succeeded]\n", errorResponse.Role, errorResponse.IpAddress)
+	ErrorModel, ok := utils.ErrorModelOf(errorObject)
+	if !ok || len(ErrorModel.Devices) == 0 {
+		utils.PrintErrorModel(errorObject)
+		return
+	}
+	for _, errorResponse := range ErrorModel.Devices {
+
+		if len(errorResponse.Error_) > 0 {
+			//For clear config
+			if errorResponse.IpAddress == "" {
+				for _, errorResponse := range errorResponse.Error_ {
+					fmt.Printf("\t%s\n", errorResponse.Message)
+				}
+			} else {
+				fmt.Printf("\tAddition of %s device with ip-address = %s [Failed]\n", errorResponse.Role, errorResponse.IpAddress)
+				for _, errorResponse := range errorResponse.Error_ {
+					fmt.Println("\t" + errorResponse.Message)
 				}
 			}
+		} else {
+			fmt.Printf("\tAddition of %s device with ip-address = %s [Succeeded]\n", errorResponse.Role, errorResponse.IpAddress)
 		}
-	} else {
-		//Generic Error, Just print it
-		fmt.Println("\t" + errorObject.Error())
 	}
-
 }
 func handleAddSwitchesResponse(SwitchesdataResponse *openAPI.SwitchesdataResponse) {
 	//Fetch the  Responses for Add Switches  and Display
@@ -229,10 +230,20 @@ func handleAddSwitchesResponse(SwitchesdataResponse *openAPI.SwitchesdataRespons
 
 }
 
+func handleValidateErrorResponse(errorObject error) {
+	fmt.Println("Validate Fabric [Failed]")
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
+
 func handleValidateResponse(FabricValidateResponse *openAPI.FabricValidateResponse, errorType string) error {
 	if len(FabricValidateResponse.MissingLinks) > 0 || len(FabricValidateResponse.SpineSpineLinks) > 0 ||
 		FabricValidateResponse.MissingLeaves || FabricValidateResponse.MissingSpines ||
-		len(FabricValidateResponse.LeafLeafLinks) > 0 {
+		len(FabricValidateResponse.LeafLeafLinks) > 0 || len(FabricValidateResponse.PinConflicts) > 0 ||
+		len(FabricValidateResponse.MiscabledLinks) > 0 || len(FabricValidateResponse.MissingPlannedLinks) > 0 ||
+		len(FabricValidateResponse.UnexpectedLinks) > 0 || hasPreflightErrors(FabricValidateResponse.PreflightFindings) {
 		fmt.Printf("Validate Fabric [%s]\n", errorType)
 		if len(FabricValidateResponse.MissingLinks) > 0 {
 			fmt.Println("\t" + "Missing Links")
@@ -258,38 +269,114 @@ func handleValidateResponse(FabricValidateResponse *openAPI.FabricValidateRespon
 		if FabricValidateResponse.MissingLeaves {
 			fmt.Println("\tNo Leaf Devices")
 		}
+		if len(FabricValidateResponse.PinConflicts) > 0 {
+			fmt.Println("\t" + "Allocation Pin Conflicts")
+			for _, Conflict := range FabricValidateResponse.PinConflicts {
+				fmt.Println("\t" + Conflict)
+			}
+		}
+		if len(FabricValidateResponse.MiscabledLinks) > 0 {
+			fmt.Println("\t" + "Miscabled Links")
+			for _, links := range FabricValidateResponse.MiscabledLinks {
+				fmt.Println("\t" + links)
+			}
+		}
+		if len(FabricValidateResponse.MissingPlannedLinks) > 0 {
+			fmt.Println("\t" + "Missing Planned Links")
+			for _, links := range FabricValidateResponse.MissingPlannedLinks {
+				fmt.Println("\t" + links)
+			}
+		}
+		if len(FabricValidateResponse.UnexpectedLinks) > 0 {
+			fmt.Println("\t" + "Unexpected Links")
+			for _, links := range FabricValidateResponse.UnexpectedLinks {
+				fmt.Println("\t" + links)
+			}
+		}
+		printPreflightFindings(FabricValidateResponse.PreflightFindings)
+		printPoolWarnings(FabricValidateResponse.PoolWarnings)
 		return errors.New("Fabric Validation Failed")
 	}
 
 	fmt.Println("Validate Fabric [Success]")
+	printPreflightFindings(FabricValidateResponse.PreflightFindings)
+	printPoolWarnings(FabricValidateResponse.PoolWarnings)
 
 	return nil
 }
 
-func handleConfigureErrorResponse(errorObject error) {
-	//Generated code sends the message as an error string, so parsing output from string object
-	fmt.Println("Configure Fabric [Failed]")
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-	if len(errorMessageList) == 2 {
-		var StatusModelList []openAPI.DeviceStatusModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &StatusModelList)
-		if err == nil {
-			for _, errorResponse := range StatusModelList {
-				fmt.Printf("\tConfiguration of device with ip-address = %s [Failed]\n", errorResponse.IpAddress)
-				for _, errorResponse := range errorResponse.Error_ {
-					fmt.Println("\t" + errorResponse.Message)
+//hasPreflightErrors returns whether a pre-flight check whose errors are not overridden reported an error
+func hasPreflightErrors(Findings []openAPI.PreflightFinding) bool {
+	for _, Finding := range Findings {
+		if Finding.Severity == "error" && !Finding.Overridden {
+			return true
+		}
+	}
+	return false
+}
 
-				}
+//printPreflightFindings displays the errors and the warnings of the pre-flight checks, the overridden errors
+//do not fail the validation
+func printPreflightFindings(Findings []openAPI.PreflightFinding) {
+	for _, Severity := range []struct {
+		Name  string
+		Title string
+	}{{"error", "Pre-flight Errors"}, {"warning", "Pre-flight Warnings"}} {
+		Printed := false
+		for _, Finding := range Findings {
+			if Finding.Severity != Severity.Name {
+				continue
+			}
+			if !Printed {
+				fmt.Println("\t" + Severity.Title)
+				Printed = true
+			}
+			if Finding.Overridden {
+				fmt.Printf("\t[%s] %s (overridden)\n", Finding.Check, Finding.Message)
+			} else {
+				fmt.Printf("\t[%s] %s\n", Finding.Check, Finding.Message)
 			}
 		}
-	} else {
-		//Generic Error
-		fmt.Println("\t" + errorObject.Error())
 	}
+}
 
+//printPoolWarnings displays the allocation pools whose utilization reached the warning threshold
+func printPoolWarnings(PoolWarnings []string) {
+	if len(PoolWarnings) == 0 {
+		return
+	}
+	fmt.Println("	Pool Utilization Warnings")
+	for _, Warning := range PoolWarnings {
+		fmt.Println("	" + Warning)
+	}
+}
+
+func handleConfigureErrorResponse(errorObject error) {
+	fmt.Println("Configure Fabric [Failed]")
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	ErrorModel, ok := utils.ErrorModelOf(errorObject)
+	if !ok || len(ErrorModel.Errors) == 0 {
+		utils.PrintErrorModel(errorObject)
+		return
+	}
+	for _, errorResponse := range ErrorModel.Errors {
+		fmt.Printf("\tConfiguration of device with ip-address = %s [Failed]\n", errorResponse.Device)
+		fmt.Println("\t" + errorResponse.Message)
+	}
+	fmt.Printf("Execution ID: %s\n", ErrorModel.ExecutionId)
 }
 
 func handleConfigureResponse(ConfigureFabricResponse *openAPI.ConfigureFabricResponse) error {
 	fmt.Println("Configure Fabric [Success]")
+	if ConfigureFabricResponse.Generation != 0 {
+		fmt.Printf("\tStored as configuration generation %d\n", ConfigureFabricResponse.Generation)
+	}
+	printPoolWarnings(ConfigureFabricResponse.PoolWarnings)
+	//The configuration is pushed even when the devices are not healthy once configured
+	if ConfigureFabricResponse.Health != nil {
+		printFabricHealth(*ConfigureFabricResponse.Health)
+	}
 	return nil
 }
@@ -2,11 +2,8 @@ package fabric
 
 import (
 	"context"
-	"efa/infra/cli/commands/fabric/settings"
 	"efa/infra/cli/utils"
-	"efa/infra/constants"
 	openAPI "efa/infra/rest/generated/client"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
@@ -28,21 +25,8 @@ var DeconfigureSwitchCommand = &cobra.Command{
 }
 
 func init() {
-
-	fabricName := constants.DefaultFabric
-	cfg := openAPI.NewConfiguration()
-	api := openAPI.NewAPIClient(cfg)
-	response, _, _ := api.FabricApi.GetFabric(context.Background(), fabricName)
-	FabricProperties, err := settings.CreateFromMap(response.FabricSettings)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	if FabricProperties.FabricType == utils.CLOSFabricType {
-		DeconfigureSwitchCommand.Flags().StringVar(&deleteIPAddress, "device", "", "Comma separated list of IP Address/Hostnames of devices")
-	} else {
-		DeconfigureSwitchCommand.Flags().StringArrayVar(&deleteRack, "rack", []string{}, "Comma separated addresses/host-names for non-clos fabric")
-	}
+	DeconfigureSwitchCommand.Flags().StringVar(&deleteIPAddress, "device", "", "Comma separated list of IP Address/Hostnames of devices for clos fabric")
+	DeconfigureSwitchCommand.Flags().StringArrayVar(&deleteRack, "rack", []string{}, "Comma separated addresses/host-names for non-clos fabric")
 	DeconfigureSwitchCommand.Flags().BoolVar(&nodevCleanUp, "no-device-cleanup", false, "Do not cleanup the configurations on the devices")
 	DeconfigureSwitchCommand.Flags().BoolVar(&delpersist, "persist", false, "Persist the configuration on the devices")
 }
@@ -54,15 +38,21 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
-	response, _, err := api.FabricApi.GetFabric(context.Background(), constants.DefaultFabric)
+	response, _, err := api.FabricApi.GetFabric(context.Background(), utils.FabricName())
 	if err != nil {
 		handleConfigShowErrorResponse(err)
 		return nil
 	}
 
-	cfg = openAPI.NewConfiguration()
+	cfg, err = utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api = openAPI.NewAPIClient(cfg)
 	DelSwitchReq := openAPI.DeleteSwitchesRequest{}
 	if response.FabricSettings["FabricType"] == utils.NonCLOSFabricType {
@@ -127,9 +117,9 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		if devCleanUp {
 			//Validation Routine called for NonCLOSFabricType
 			//Second Send Request for Validating the fabric
-			FabricValidateResponse, _, err := api.FabricValidationApi.ValidateFabric(context.Background(), constants.DefaultFabric)
+			FabricValidateResponse, _, err := api.FabricValidationApi.ValidateFabric(context.Background(), utils.FabricName(), nil)
 			if err != nil {
-				fmt.Println(err)
+				handleValidateErrorResponse(err)
 				return nil
 			}
 
@@ -150,27 +140,22 @@ func handleDeleteSwitchesErrorResponse(errorObject error) {
 	if utils.IsServerConnectionError(errorObject) {
 		return
 	}
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-	if len(errorMessageList) == 2 {
-		var StatusModelList []openAPI.DeviceStatusModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &StatusModelList)
-		if err == nil {
-			for _, errorResponse := range StatusModelList {
-
-				if len(errorResponse.Error_) > 0 {
-					fmt.Printf("\tDeletion of %s device(s) with ip-address = %s [Failed]", errorResponse.Role, errorResponse.IpAddress)
-					fmt.Println("\n\tErrors:")
-					for _, errorResponse := range errorResponse.Error_ {
-						fmt.Println("\t" + errorResponse.Message)
-					}
-				} else {
-					fmt.Printf("\tDeletion of %s device(s) with ip-address = %s [Succeeded]\n", errorResponse.Role, errorResponse.IpAddress)
-				}
+	ErrorModel, ok := utils.ErrorModelOf(errorObject)
+	if !ok || len(ErrorModel.Devices) == 0 {
+		utils.PrintErrorModel(errorObject)
+		return
+	}
+	for _, errorResponse := range ErrorModel.Devices {
+
+		if len(errorResponse.Error_) > 0 {
+			fmt.Printf("\tDeletion of %s device(s) with ip-address = %s [Failed]", errorResponse.Role, errorResponse.IpAddress)
+			fmt.Println("\n\tErrors:")
+			for _, errorResponse := range errorResponse.Error_ {
+				fmt.Println("\t" + errorResponse.Message)
 			}
+		} else {
+			fmt.Printf("\tDeletion of %s device(s) with ip-address = %s [Succeeded]\n", errorResponse.Role, errorResponse.IpAddress)
 		}
-	} else {
-		//Generic Error, Just print it
-		fmt.Println("\t" + errorObject.Error())
 	}
 }
 
@@ -0,0 +1,94 @@
+package fabric
+
+import (
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"sort"
+)
+
+var exportFile string
+
+//ExportCommand provides command to write the fabric spec of the IP Fabric
+var ExportCommand = &cobra.Command{
+	Use:   "export",
+	Short: "Display the fabric spec of the IP Fabric, to be used with \"efa fabric apply\"",
+	RunE:  utils.TimedRunE(runFabricExport),
+}
+
+func init() {
+	ExportCommand.Flags().StringVarP(&exportFile, "file", "f", "", "Write the fabric spec to the file instead of displaying it")
+}
+
+func runFabricExport(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	FabricName := utils.FabricName()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+	State, err := getFabricState(api, FabricName)
+	if err != nil {
+		handleShowErrorResponse(err)
+		return nil
+	}
+
+	data, err := yaml.Marshal(exportFabricSpec(FabricName, State))
+	if err != nil {
+		return err
+	}
+	if len(exportFile) == 0 {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err = ioutil.WriteFile(exportFile, data, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Fabric %s spec written to %s\n", FabricName, exportFile)
+	return nil
+}
+
+//exportFabricSpec returns the spec of the fabric. The passwords are masked by the server and are not
+//exported, and the credentials of the devices are left to be referenced.
+func exportFabricSpec(FabricName string, State fabricState) FabricSpec {
+	Spec := FabricSpec{Fabric: FabricName, Settings: make(map[string]string), Racks: State.Racks}
+	for Name, Value := range State.Settings {
+		if specIgnoredSettings[Name] || specPasswordSettings[Name] {
+			continue
+		}
+		Spec.Settings[Name] = Value
+	}
+	for IP, Role := range State.Roles {
+		switch Role {
+		case "Spine":
+			Spec.Spines = append(Spec.Spines, IP)
+		case "Leaf":
+			Spec.Leaves = append(Spec.Leaves, IP)
+		}
+	}
+	sort.Strings(Spec.Spines)
+	sort.Strings(Spec.Leaves)
+	for IP, DeviceSettings := range State.DeviceSettings {
+		for Name, Value := range DeviceSettings {
+			if len(Value) == 0 {
+				continue
+			}
+			if Spec.Devices == nil {
+				Spec.Devices = make(map[string]map[string]string)
+			}
+			if Spec.Devices[IP] == nil {
+				Spec.Devices[IP] = make(map[string]string)
+			}
+			Spec.Devices[IP][Name] = Value
+		}
+	}
+	return Spec
+}
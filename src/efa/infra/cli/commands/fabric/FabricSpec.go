@@ -0,0 +1,327 @@
+package fabric
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+//FabricSpec is the declarative description of a fabric, read from a YAML or JSON file
+type FabricSpec struct {
+	Fabric string `yaml:"fabric"`
+	//Settings are keyed by the names displayed by "efa fabric setting show"
+	Settings    map[string]string `yaml:"settings,omitempty"`
+	Credentials SpecCredentials   `yaml:"credentials,omitempty"`
+	Spines      []string          `yaml:"spines,omitempty"`
+	Leaves      []string          `yaml:"leaves,omitempty"`
+	Racks       [][]string        `yaml:"racks,omitempty"`
+	//Devices holds the per-device overrides of the fabric settings, keyed by the device IP address
+	Devices map[string]map[string]string `yaml:"devices,omitempty"`
+}
+
+//SpecCredentials references the credentials of the devices, the password itself is never in the spec
+type SpecCredentials struct {
+	Username     string `yaml:"username,omitempty"`
+	PasswordEnv  string `yaml:"password-env,omitempty"`
+	PasswordFile string `yaml:"password-file,omitempty"`
+}
+
+//fabricPlan holds the requests bringing the fabric to its spec
+type fabricPlan struct {
+	Settings       []openAPI.ConfigChange
+	RemoveDevices  []string
+	RemoveRacks    [][]string
+	AddSpines      []string
+	AddLeaves      []string
+	AddRacks       [][]string
+	DeviceSettings map[string][]openAPI.ConfigChange
+}
+
+//fabricState is the live state of the fabric the spec is compared with
+type fabricState struct {
+	Settings map[string]string
+	//Roles of the devices keyed by IP address
+	Roles map[string]string
+	//Racks lists the device pairs of a non-CLOS fabric
+	Racks          [][]string
+	DeviceSettings map[string]map[string]string
+}
+
+var specPasswordSettings = map[string]bool{"PeerGroupPassword": true, "MctL2EvpnPassword": true,
+	"RackPeerEBGPGroupPassword": true, "RackPeerOvgGroupPassword": true}
+
+//specIgnoredSettings are displayed by "efa fabric setting show" but are not settings
+var specIgnoredSettings = map[string]bool{"ID": true, "FabricID": true}
+
+//loadFabricSpec reads a fabric spec, JSON being a subset of YAML both are parsed the same way
+func loadFabricSpec(FileName string) (FabricSpec, error) {
+	var Spec FabricSpec
+	data, err := ioutil.ReadFile(FileName)
+	if err != nil {
+		return Spec, err
+	}
+	if err = yaml.UnmarshalStrict(data, &Spec); err != nil {
+		return Spec, fmt.Errorf("Invalid fabric spec %s: %s", FileName, err)
+	}
+	if len(Spec.Fabric) == 0 {
+		Spec.Fabric = utils.FabricName()
+	}
+	return Spec, nil
+}
+
+//password resolves the password reference of the credentials
+func (Credentials SpecCredentials) password() (string, error) {
+	if len(Credentials.PasswordEnv) != 0 {
+		if Password, ok := os.LookupEnv(Credentials.PasswordEnv); ok {
+			return Password, nil
+		}
+		return "", fmt.Errorf("Environment variable %s of the device password is not set", Credentials.PasswordEnv)
+	}
+	if len(Credentials.PasswordFile) != 0 {
+		data, err := ioutil.ReadFile(Credentials.PasswordFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return "", errors.New("The credentials should reference the device password with password-env or password-file")
+}
+
+//getFabricState reads the settings, the devices and the per-device overrides of the fabric
+func getFabricState(api *openAPI.APIClient, FabricName string) (fabricState, error) {
+	State := fabricState{Roles: make(map[string]string), DeviceSettings: make(map[string]map[string]string)}
+	FabricResponse, _, err := api.FabricApi.GetFabric(context.Background(), FabricName)
+	if err != nil {
+		return State, err
+	}
+	State.Settings = FabricResponse.FabricSettings
+
+	SwitchesResponse, _, err := api.SwitchesApi.GetSwitches(context.Background(), FabricName)
+	if err != nil {
+		return State, err
+	}
+	RackNames := make([]string, 0)
+	Racks := make(map[string][]string)
+	for _, Switch := range SwitchesResponse.Items {
+		State.Roles[Switch.IpAddress] = Switch.Role
+		if len(Switch.Rack) != 0 {
+			if _, found := Racks[Switch.Rack]; !found {
+				RackNames = append(RackNames, Switch.Rack)
+			}
+			Racks[Switch.Rack] = append(Racks[Switch.Rack], Switch.IpAddress)
+		}
+		DeviceResponse, _, err := api.DeviceSettingsApi.GetDeviceSettings(context.Background(), FabricName, Switch.IpAddress)
+		if err != nil {
+			return State, err
+		}
+		State.DeviceSettings[Switch.IpAddress] = DeviceResponse.DeviceSettings
+	}
+	sort.Strings(RackNames)
+	for _, RackName := range RackNames {
+		State.Racks = append(State.Racks, Racks[RackName])
+	}
+	return State, nil
+}
+
+//planFabricSpec computes the requests bringing the fabric from its live state to the spec
+func planFabricSpec(Spec FabricSpec, State fabricState) (fabricPlan, error) {
+	Plan := fabricPlan{DeviceSettings: make(map[string][]openAPI.ConfigChange)}
+
+	SettingNames := make([]string, 0, len(Spec.Settings))
+	for Name := range Spec.Settings {
+		SettingNames = append(SettingNames, Name)
+	}
+	sort.Strings(SettingNames)
+	for _, Name := range SettingNames {
+		Value := Spec.Settings[Name]
+		Current, found := State.Settings[Name]
+		if !found || specIgnoredSettings[Name] {
+			return Plan, fmt.Errorf("Unknown fabric setting %s", Name)
+		}
+		if specPasswordSettings[Name] {
+			//The passwords are masked by the server, only setting or removing a password is detected
+			if (Value == "" || Value == "none") == (Current == "" || Current == "none") {
+				continue
+			}
+			if Value == "" {
+				Value = "none"
+			}
+		} else if Value == Current {
+			continue
+		}
+		Plan.Settings = append(Plan.Settings, openAPI.ConfigChange{Kind: "Setting", Name: Name, Change: "Updated",
+			Old: Current, New: Value})
+	}
+
+	FabricType := State.Settings["FabricType"]
+	if Value, found := Spec.Settings["FabricType"]; found {
+		FabricType = Value
+	}
+	SpecDevices := make(map[string]bool)
+	if FabricType == utils.NonCLOSFabricType {
+		if len(Spec.Spines) != 0 || len(Spec.Leaves) != 0 {
+			return Plan, errors.New("Spine and Leaf address should be provided only for CLOS fabric")
+		}
+		if err := planRacks(Spec, State, &Plan, SpecDevices); err != nil {
+			return Plan, err
+		}
+	} else {
+		if len(Spec.Racks) != 0 {
+			return Plan, errors.New("Rack address should be provided only for NON-CLOS fabric")
+		}
+		for _, Devices := range []struct {
+			Role string
+			IPs  []string
+			Add  *[]string
+		}{{"Spine", Spec.Spines, &Plan.AddSpines}, {"Leaf", Spec.Leaves, &Plan.AddLeaves}} {
+			if !utils.IsValidIPs(Devices.IPs) {
+				return Plan, fmt.Errorf("Some of the %s IP's are invalid", strings.ToLower(Devices.Role))
+			}
+			for _, IP := range Devices.IPs {
+				if SpecDevices[IP] {
+					return Plan, fmt.Errorf("Device %s is listed more than once", IP)
+				}
+				SpecDevices[IP] = true
+				Role, found := State.Roles[IP]
+				if !found {
+					*Devices.Add = append(*Devices.Add, IP)
+				} else if Role != Devices.Role {
+					return Plan, fmt.Errorf("Device %s is a %s of the fabric, remove it from the spec and apply before adding it as a %s",
+						IP, Role, Devices.Role)
+				}
+			}
+		}
+		for IP := range State.Roles {
+			if !SpecDevices[IP] {
+				Plan.RemoveDevices = append(Plan.RemoveDevices, IP)
+			}
+		}
+		sort.Strings(Plan.RemoveDevices)
+	}
+
+	//The server refuses the settings update of a fabric with devices, the settings are updated once the
+	//devices missing from the spec are removed
+	if len(Plan.Settings) != 0 {
+		Kept := make([]string, 0)
+		for IP := range SpecDevices {
+			if _, found := State.Roles[IP]; found {
+				Kept = append(Kept, IP)
+			}
+		}
+		if len(Kept) != 0 {
+			sort.Strings(Kept)
+			return Plan, fmt.Errorf("The settings of fabric %s cannot be updated while it has devices, the spec keeps %s. "+
+				"Remove the devices from the spec and apply before updating the settings, or use \"efa fabric bgp-auth rotate\" "+
+				"for the BGP authentication", Spec.Fabric, strings.Join(Kept, ", "))
+		}
+	}
+
+	for IP := range Spec.Devices {
+		if !SpecDevices[IP] {
+			return Plan, fmt.Errorf("Device %s has settings but is not a device of the spec", IP)
+		}
+	}
+	for IP := range SpecDevices {
+		Overrides := Spec.Devices[IP]
+		Current, found := State.DeviceSettings[IP]
+		if !found {
+			//The device is added, its overrides are all set once it is in the fabric
+			Current = make(map[string]string)
+		}
+		Names := make([]string, 0)
+		for Name := range Overrides {
+			Names = append(Names, Name)
+		}
+		for Name := range Current {
+			if _, listed := Overrides[Name]; !listed {
+				Names = append(Names, Name)
+			}
+		}
+		sort.Strings(Names)
+		for _, Name := range Names {
+			if _, overridable := Current[Name]; found && !overridable {
+				return Plan, fmt.Errorf("Setting %s of device %s cannot be overridden per device", Name, IP)
+			}
+			if Overrides[Name] == Current[Name] {
+				continue
+			}
+			Change := openAPI.ConfigChange{Device: IP, Kind: "Device Setting", Name: Name, Change: "Updated",
+				Old: Current[Name], New: Overrides[Name]}
+			if len(Overrides[Name]) == 0 {
+				Change.Change = "Removed"
+			} else if len(Current[Name]) == 0 {
+				Change.Change = "Added"
+			}
+			Plan.DeviceSettings[IP] = append(Plan.DeviceSettings[IP], Change)
+		}
+	}
+	return Plan, nil
+}
+
+//planRacks computes the rack pairs added and removed, a pair is identified by its two devices
+func planRacks(Spec FabricSpec, State fabricState, Plan *fabricPlan, SpecDevices map[string]bool) error {
+	pairKey := func(Rack []string) string {
+		Pair := append([]string{}, Rack...)
+		sort.Strings(Pair)
+		return strings.Join(Pair, ",")
+	}
+	LiveRacks := make(map[string]bool)
+	LiveDevices := make(map[string]string)
+	for _, Rack := range State.Racks {
+		LiveRacks[pairKey(Rack)] = true
+		for _, IP := range Rack {
+			LiveDevices[IP] = pairKey(Rack)
+		}
+	}
+	SpecRacks := make(map[string]bool)
+	for _, Rack := range Spec.Racks {
+		if len(Rack) != 2 {
+			return errors.New("Rack must contain pair of IP Addresses")
+		}
+		if !utils.IsValidIPs(Rack) {
+			return errors.New("Some of the rack IP's are invalid")
+		}
+		Key := pairKey(Rack)
+		for _, IP := range Rack {
+			if SpecDevices[IP] {
+				return errors.New("Rack IPs must be unique")
+			}
+			SpecDevices[IP] = true
+			if Live, found := LiveDevices[IP]; found && Live != Key {
+				return fmt.Errorf("Device %s is in the rack %s of the fabric, remove the rack from the spec and apply before pairing it again",
+					IP, Live)
+			}
+		}
+		SpecRacks[Key] = true
+		if !LiveRacks[Key] {
+			Plan.AddRacks = append(Plan.AddRacks, Rack)
+		}
+	}
+	if len(Spec.Racks) > 4 {
+		return errors.New("Only 4 Rack Pairs are supported")
+	}
+	for _, Rack := range State.Racks {
+		if !SpecRacks[pairKey(Rack)] {
+			Plan.RemoveRacks = append(Plan.RemoveRacks, Rack)
+		}
+	}
+	return nil
+}
+
+//empty checks whether the fabric already is as of the spec
+func (Plan fabricPlan) empty() bool {
+	return len(Plan.Settings) == 0 && len(Plan.RemoveDevices) == 0 && len(Plan.RemoveRacks) == 0 &&
+		len(Plan.AddSpines) == 0 && len(Plan.AddLeaves) == 0 && len(Plan.AddRacks) == 0 && len(Plan.DeviceSettings) == 0
+}
+
+//adds checks whether the plan adds devices to the fabric
+func (Plan fabricPlan) adds() bool {
+	return len(Plan.AddSpines) != 0 || len(Plan.AddLeaves) != 0 || len(Plan.AddRacks) != 0
+}
@@ -1,6 +1,9 @@
 package fabric
 
 import (
+	"efa/infra/cli/commands/fabric/bgpauth"
+	"efa/infra/cli/commands/fabric/cabling"
+	"efa/infra/cli/commands/fabric/pools"
 	"efa/infra/cli/commands/fabric/settings"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +17,17 @@ func NewGroupCmd() *cobra.Command {
 	cmd.AddCommand(ConfigureSwitchCommand)
 	cmd.AddCommand(DeconfigureSwitchCommand)
 	cmd.AddCommand(settings.NewGroupCmd())
+	cmd.AddCommand(bgpauth.NewGroupCmd())
+	cmd.AddCommand(pools.NewGroupCmd())
+	cmd.AddCommand(cabling.NewGroupCmd())
 	cmd.AddCommand(ShowFabricConfigCommand)
 	cmd.AddCommand(ShowFabricCommand)
+	cmd.AddCommand(RefreshFabricCommand)
+	cmd.AddCommand(HealthCommand)
+	cmd.AddCommand(HistoryCommand)
+	cmd.AddCommand(DiffCommand)
+	cmd.AddCommand(RevertCommand)
+	cmd.AddCommand(ApplyCommand)
+	cmd.AddCommand(ExportCommand)
 	return cmd
 }
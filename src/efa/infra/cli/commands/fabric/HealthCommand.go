@@ -0,0 +1,104 @@
+package fabric
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	"efa/infra/constants"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+//healthChecks are the health checks of a device, in the columns of the health matrix
+var healthChecks = []string{"bgp", "evpn", "bfd", "mct", "overlay", "routes"}
+
+//HealthCommand provides command to check the operational state of the devices of the fabric
+var HealthCommand = &cobra.Command{
+	Use:   "health",
+	Short: "Check the BGP, EVPN, BFD, MCT, overlay and route state of each device of the IP Fabric",
+	RunE:  utils.TimedRunE(runFabricHealth),
+}
+
+func init() {
+	utils.AddOutputFlag(HealthCommand)
+}
+
+func runFabricHealth(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricHealthApi.GetFabricHealth(context.Background(), utils.FabricName())
+	if err != nil {
+		return utils.RequestError(err, handleHealthErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		if err := utils.PrintModel(response); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Checked at %s\n", response.CheckedAt.Local().Format(constants.DefaultTimeFormat))
+		printFabricHealth(response)
+	}
+	if !response.Healthy {
+		return &utils.ExitError{Code: utils.ExitFailure}
+	}
+	return nil
+}
+
+//printFabricHealth displays the result of each health check of each device, followed by the failures
+func printFabricHealth(Health openAPI.FabricHealthResponse) {
+	if Health.Healthy {
+		fmt.Println("Fabric Health [Healthy]")
+	} else {
+		fmt.Println("Fabric Health [Unhealthy]")
+	}
+	if len(Health.Devices) == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader(append([]string{"Device", "Role"}, healthChecks...))
+	var Failures []string
+	for _, Device := range Health.Devices {
+		Results := make(map[string]string, len(Device.Checks))
+		for _, Check := range Device.Checks {
+			Results[Check.Name] = Check.Status
+			if Check.Status == "fail" {
+				Failures = append(Failures, fmt.Sprintf("\t%s %s: %s", Device.IpAddress, Check.Name, Check.Message))
+			}
+		}
+		Row := []string{Device.IpAddress, Device.Role}
+		for _, Check := range healthChecks {
+			switch Results[Check] {
+			case "pass", "fail":
+				Row = append(Row, strings.ToUpper(Results[Check]))
+			default:
+				Row = append(Row, "-")
+			}
+		}
+		table.Append(Row)
+	}
+	table.Render()
+	for _, Failure := range Failures {
+		fmt.Println(Failure)
+	}
+}
+
+func handleHealthErrorResponse(errorObject error) {
+	fmt.Println("Fabric Health [Failed]")
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -0,0 +1,164 @@
+package fabric
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	"efa/infra/constants"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+	"strconv"
+)
+
+var revertPersist bool
+
+//HistoryCommand provides command to list the configuration generations of the fabric
+var HistoryCommand = &cobra.Command{
+	Use:   "history",
+	Short: "Display the configuration generations stored by the configures of the IP Fabric",
+	RunE:  utils.TimedRunE(runFabricHistory),
+}
+
+//DiffCommand provides command to display the changes between two configuration generations
+var DiffCommand = &cobra.Command{
+	Use:   "diff <generation> <generation>",
+	Short: "Display the changes of the intended configuration between two configuration generations",
+	Args:  cobra.ExactArgs(2),
+	RunE:  utils.TimedRunE(runFabricDiff),
+}
+
+//RevertCommand provides command to configure the IP Fabric with a previous configuration generation
+var RevertCommand = &cobra.Command{
+	Use:   "revert <generation>",
+	Short: "Revert the settings, ASNs, loopbacks and link addresses of the IP Fabric to a configuration generation",
+	Args:  cobra.ExactArgs(1),
+	RunE:  utils.TimedRunE(runFabricRevert),
+}
+
+func init() {
+	RevertCommand.Flags().BoolVar(&revertPersist, "persist", false, "Persist the configuration on the devices")
+	utils.AddOutputFlag(HistoryCommand)
+	utils.AddOutputFlag(DiffCommand)
+}
+
+func runFabricHistory(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricHistoryApi.GetFabricHistory(context.Background(), utils.FabricName())
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleHistoryErrorResponse("Fabric History", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	if len(response.Generations) == 0 {
+		fmt.Println("No configuration generations found, configure the fabric first")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Generation", "Created At", "Execution ID", "Devices"})
+	for _, Generation := range response.Generations {
+		table.Append([]string{fmt.Sprintf("%d", Generation.Generation),
+			Generation.CreatedAt.Local().Format(constants.DefaultTimeFormat), Generation.ExecutionId,
+			fmt.Sprintf("%d", Generation.Devices)})
+	}
+	table.Render()
+	return nil
+}
+
+func runFabricDiff(cmd *cobra.Command, args []string) error {
+	From, errFrom := strconv.ParseInt(args[0], 10, 32)
+	To, errTo := strconv.ParseInt(args[1], 10, 32)
+	if errFrom != nil || errTo != nil {
+		fmt.Println("Generations should be numbers, see \"efa fabric history\"")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricHistoryApi.GetFabricDiff(context.Background(), utils.FabricName(),
+		int32(From), int32(To))
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleHistoryErrorResponse("Fabric Diff", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	if len(response.Changes) == 0 {
+		fmt.Printf("No changes between generation %d and %d\n", From, To)
+		return nil
+	}
+	printConfigChanges(response.Changes)
+	return nil
+}
+
+func runFabricRevert(cmd *cobra.Command, args []string) error {
+	Generation, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil {
+		fmt.Println("Generation should be a number, see \"efa fabric history\"")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricHistoryApi.RevertFabric(context.Background(), utils.FabricName(),
+		int32(Generation), revertPersist)
+	if err != nil {
+		handleHistoryErrorResponse("Revert Fabric", err)
+		return nil
+	}
+
+	if len(response.Changes) != 0 {
+		printConfigChanges(response.Changes)
+	}
+	fmt.Printf("Revert Fabric to generation %d [Success]\n", response.Generation)
+	if response.Configure != nil {
+		if response.Configure.Generation != 0 {
+			fmt.Printf("\tStored as configuration generation %d\n", response.Configure.Generation)
+		}
+		printPoolWarnings(response.Configure.PoolWarnings)
+	}
+	return nil
+}
+
+func printConfigChanges(Changes []openAPI.ConfigChange) {
+	//Render using Tables
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Device", "Kind", "Name", "Change", "Old", "New"})
+	for _, Change := range Changes {
+		table.Append([]string{Change.Device, Change.Kind, Change.Name, Change.Change, Change.Old, Change.New})
+	}
+	table.Render()
+}
+
+func handleHistoryErrorResponse(Operation string, errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	fmt.Printf("%s [Failed]\n", Operation)
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
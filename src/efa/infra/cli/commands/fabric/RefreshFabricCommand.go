@@ -0,0 +1,68 @@
+package fabric
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var refreshDevice string
+
+//RefreshFabricCommand provides command to re-discover the links of the fabric and configure the changed links
+var RefreshFabricCommand = &cobra.Command{
+	Use:   "refresh",
+	Short: "Re-discover the links of the IP Fabric and configure the links which changed",
+	RunE:  utils.TimedRunE(runFabricRefresh),
+}
+
+func init() {
+	RefreshFabricCommand.Flags().StringVar(&refreshDevice, "device", "", "Device IP Address, all the devices are refreshed when not specified")
+}
+
+func runFabricRefresh(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricRefreshApi.RefreshFabric(context.Background(), utils.FabricName(), refreshDevice)
+	if err != nil {
+		fmt.Println("Refresh Fabric [Failed]")
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		utils.PrintErrorModel(err)
+		return nil
+	}
+
+	if len(response.AddedLinks)+len(response.RemovedLinks)+len(response.MovedLinks) == 0 {
+		fmt.Println("No link changes found")
+	} else {
+		//Render using Tables
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetHeader([]string{"Change", "Link"})
+		for _, link := range response.AddedLinks {
+			table.Append([]string{"Added", link})
+		}
+		for _, link := range response.RemovedLinks {
+			table.Append([]string{"Removed", link})
+		}
+		for _, link := range response.MovedLinks {
+			table.Append([]string{"Moved", link})
+		}
+		table.Render()
+	}
+	fmt.Println("Refresh Fabric [Success]")
+	return nil
+}
@@ -3,14 +3,11 @@ package fabric
 import (
 	"context"
 	"efa/infra/cli/utils"
-	"efa/infra/constants"
 	openAPI "efa/infra/rest/generated/client"
-	"encoding/json"
 	"fmt"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"os"
-	"strings"
 )
 
 //ShowFabricCommand provides command to fetch devices in a Fabric
@@ -21,42 +18,46 @@ var ShowFabricCommand = &cobra.Command{
 }
 
 func init() {
-
+	utils.AddOutputFlag(ShowFabricCommand)
 }
 
 func runFabricShow(cmd *cobra.Command, args []string) error {
 
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
 
-	fabricResponse, _, err := api.FabricApi.GetFabric(context.Background(), constants.DefaultFabric)
+	fabricResponse, _, err := api.FabricApi.GetFabric(context.Background(), utils.FabricName())
 	if err != nil {
-		handleShowErrorResponse(err)
-		return nil
+		return utils.RequestError(err, handleShowErrorResponse)
 	}
 
-	ShowResponse, _, err := api.SwitchesApi.GetSwitches(context.Background(), constants.DefaultFabric)
+	ShowResponse, _, err := api.SwitchesApi.GetSwitches(context.Background(), utils.FabricName())
 	if err != nil {
-		handleShowErrorResponse(err)
-		return nil
+		return utils.RequestError(err, handleShowErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(ShowResponse)
 	}
 
 	//Render using Tables
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	if fabricResponse.FabricSettings["FabricType"] == utils.CLOSFabricType {
-		table.SetHeader([]string{"IP Address", "Role", "Model", "Firmware"})
+		table.SetHeader([]string{"IP Address", "Role", "Model", "Firmware", "State"})
 
 		for _, switchRespsonse := range ShowResponse.Items {
 			row := []string{switchRespsonse.IpAddress, switchRespsonse.Role,
-				switchRespsonse.Model, switchRespsonse.Firmware}
+				switchRespsonse.Model, switchRespsonse.Firmware, switchRespsonse.State}
 			table.Append(row)
 		}
 	} else {
-		table.SetHeader([]string{"IP Address", "Rack", "Model", "Firmware"})
+		table.SetHeader([]string{"IP Address", "Rack", "Model", "Firmware", "State"})
 		for _, switchRespsonse := range ShowResponse.Items {
 			row := []string{switchRespsonse.IpAddress, switchRespsonse.Rack,
-				switchRespsonse.Model, switchRespsonse.Firmware}
+				switchRespsonse.Model, switchRespsonse.Firmware, switchRespsonse.State}
 			table.Append(row)
 		}
 	}
@@ -72,16 +73,6 @@ func handleShowErrorResponse(errorObject error) {
 	if utils.IsServerConnectionError(errorObject) {
 		return
 	}
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-	if len(errorMessageList) == 2 {
-		var ErrorModel openAPI.ErrorModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &ErrorModel)
-		if err == nil {
-			fmt.Println(ErrorModel.Message)
-		}
-	} else {
-		//Generic Error, Just print it
-		fmt.Println("\t" + errorObject.Error())
-	}
+	utils.PrintErrorModel(errorObject)
 
 }
@@ -2,14 +2,11 @@ package fabric
 
 import (
 	"context"
-	"efa/infra/cli/commands/fabric/settings"
 	"efa/infra/cli/utils"
-	"efa/infra/constants"
 	openAPI "efa/infra/rest/generated/client"
 	"encoding/json"
 	"fmt"
 	"github.com/spf13/cobra"
-	"strings"
 )
 
 var (
@@ -29,20 +26,8 @@ func prettyPrint(v interface{}) {
 }
 
 func init() {
-	role = "all"
-	fabricName := constants.DefaultFabric
-	cfg := openAPI.NewConfiguration()
-	api := openAPI.NewAPIClient(cfg)
-
-	response, _, _ := api.FabricApi.GetFabric(context.Background(), fabricName)
-	FabricProperties, err := settings.CreateFromMap(response.FabricSettings)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	if FabricProperties.FabricType == utils.CLOSFabricType {
-		ShowFabricConfigCommand.Flags().StringVar(&role, "device-role", "all", "Filter the config based on device-role(spine/leaf/all)")
-	}
+	ShowFabricConfigCommand.Flags().StringVar(&role, "device-role", "all", "Filter the config based on device-role(spine/leaf/all) for clos fabric")
+	utils.AddOutputFlag(ShowFabricConfigCommand)
 }
 
 func runFabricConfigShow(cmd *cobra.Command, args []string) error {
@@ -53,12 +38,17 @@ func runFabricConfigShow(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	cfg := openAPI.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPI.NewAPIClient(cfg)
-	ConfigShowResponse, _, err := api.ConfigShowApi.ConfigShow(context.Background(), constants.DefaultFabric, role)
+	ConfigShowResponse, _, err := api.ConfigShowApi.ConfigShow(context.Background(), utils.FabricName(), role)
 	if err != nil {
-		handleConfigShowErrorResponse(err)
-		return nil
+		return utils.RequestError(err, handleConfigShowErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(ConfigShowResponse)
 	}
 	fmt.Println(ConfigShowResponse)
 	return nil
@@ -71,16 +61,6 @@ func handleConfigShowErrorResponse(errorObject error) {
 	if utils.IsServerConnectionError(errorObject) {
 		return
 	}
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-	if len(errorMessageList) == 2 {
-		var ErrorModel openAPI.ErrorModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &ErrorModel)
-		if err == nil {
-			fmt.Println(ErrorModel.Message)
-		}
-	} else {
-		//Generic Error, Just print it
-		fmt.Println("\t" + errorObject.Error())
-	}
+	utils.PrintErrorModel(errorObject)
 
 }
@@ -0,0 +1,16 @@
+package bgpauth
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Fabric BGP Authentication commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bgp-auth",
+		Short: "IP Fabric BGP authentication commands",
+	}
+	cmd.AddCommand(RotateCommand)
+
+	return cmd
+}
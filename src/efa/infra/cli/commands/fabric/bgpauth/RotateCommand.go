@@ -0,0 +1,82 @@
+package bgpauth
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPIClient "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bgpAuthRequest BGPAuthentication
+)
+
+//BGPAuthentication as structure, field names are the Fabric Setting keys
+type BGPAuthentication struct {
+	BGPAuthType               string
+	PeerGroupPassword         string
+	MctL2EvpnPassword         string
+	RackPeerEBGPGroupPassword string
+	RackPeerOvgGroupPassword  string
+}
+
+//RotateCommand provides command for changing the BGP passwords of a configured Fabric
+var RotateCommand = &cobra.Command{
+	Use:   "rotate",
+	Short: "Update the BGP authentication and re-key the BGP sessions one device at a time.",
+	RunE:  utils.TimedRunE(runBGPAuthRotate),
+}
+
+func init() {
+	RotateCommand.Flags().SortFlags = false
+	RotateCommand.Flags().StringVar(&bgpAuthRequest.BGPAuthType, "bgp-auth-type", "", "BGP Authentication Type <STRING md5>")
+	RotateCommand.Flags().StringVar(&bgpAuthRequest.PeerGroupPassword, "peer-group-password", "", "Leaf/Spine Peer Group Password <WORD: 1-63>, none to remove")
+	RotateCommand.Flags().StringVar(&bgpAuthRequest.MctL2EvpnPassword, "mct-l2evpn-password", "", "MCT L2EVPN Neighbor Password <WORD: 1-63>, none to remove")
+	RotateCommand.Flags().StringVar(&bgpAuthRequest.RackPeerEBGPGroupPassword, "rack-peer-ebgp-group-password", "", "Rack Peer eBgp Group Password <WORD: 1-63>, none to remove")
+	RotateCommand.Flags().StringVar(&bgpAuthRequest.RackPeerOvgGroupPassword, "rack-peer-overlay-evpn-group-password", "", "Rack Peer Overlay Evpn Group Password <WORD: 1-63>, none to remove")
+}
+
+func (BGPAuth *BGPAuthentication) prepareFabricSettingsRequest(FabricSetting *openAPIClient.FabricSettings) {
+	for Key, Value := range map[string]string{
+		"BGPAuthType":               BGPAuth.BGPAuthType,
+		"PeerGroupPassword":         BGPAuth.PeerGroupPassword,
+		"MctL2EvpnPassword":         BGPAuth.MctL2EvpnPassword,
+		"RackPeerEBGPGroupPassword": BGPAuth.RackPeerEBGPGroupPassword,
+		"RackPeerOvgGroupPassword":  BGPAuth.RackPeerOvgGroupPassword,
+	} {
+		if len(Value) != 0 {
+			FabricSetting.Keyval = append(FabricSetting.Keyval, openAPIClient.FabricParameter{Key: Key, Value: Value})
+		}
+	}
+}
+
+func runBGPAuthRotate(cmd *cobra.Command, args []string) error {
+	if bgpAuthRequest == (BGPAuthentication{}) {
+		fmt.Println("No BGP Authentication Update is Requested")
+		return nil
+	}
+	var FabricSetting openAPIClient.FabricSettings
+	FabricSetting.Name = utils.FabricName()
+	bgpAuthRequest.prepareFabricSettingsRequest(&FabricSetting)
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPIClient.NewAPIClient(cfg)
+	data := make(map[string]interface{})
+	data["fabricSettings"] = FabricSetting
+
+	_, _, err = api.FabricApi.RotateFabricBgpAuth(context.Background(), data)
+	if err != nil {
+		if utils.IsServerConnectionError(err) {
+			return nil
+		}
+		fmt.Printf("%s BGP Authentication Update Failed\n", FabricSetting.Name)
+		fmt.Printf("Reason: \n")
+		utils.PrintErrorModel(err)
+	} else {
+		fmt.Printf("%s BGP Authentication Update Successful\n", FabricSetting.Name)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package cabling
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+//DeleteCommand provides command to delete the cabling plan of the fabric
+var DeleteCommand = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete the cabling plan of the IP Fabric",
+	RunE:  utils.TimedRunE(runCablingDelete),
+}
+
+func runCablingDelete(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricCablingApi.DeleteCablingPlan(context.Background(), utils.FabricName())
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleCablingErrorResponse("Delete", err) })
+	}
+	fmt.Println(response.Message)
+	fmt.Println("Cabling Plan Delete [Success]")
+	return nil
+}
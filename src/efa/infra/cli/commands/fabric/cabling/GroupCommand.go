@@ -0,0 +1,30 @@
+package cabling
+
+import (
+	"efa/infra/cli/utils"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Fabric cabling plan commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cabling",
+		Short: "IP Fabric cabling plan commands, validate checks the links discovered by LLDP against the plan",
+	}
+	cmd.AddCommand(UploadCommand)
+	cmd.AddCommand(ShowCommand)
+	cmd.AddCommand(DeleteCommand)
+
+	return cmd
+}
+
+func handleCablingErrorResponse(Operation string, errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	//Body Contains the Error Obect in JSON
+	fmt.Printf("Cabling Plan %s [Failed]\n", Operation)
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
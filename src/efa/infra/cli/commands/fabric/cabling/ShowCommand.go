@@ -0,0 +1,52 @@
+package cabling
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+//ShowCommand provides command to display the cabling plan of the fabric
+var ShowCommand = &cobra.Command{
+	Use:   "show",
+	Short: "Display the links of the cabling plan of the IP Fabric",
+	RunE:  utils.TimedRunE(runCablingShow),
+}
+
+func init() {
+	utils.AddOutputFlag(ShowCommand)
+}
+
+func runCablingShow(cmd *cobra.Command, args []string) error {
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricCablingApi.GetCablingPlan(context.Background(), utils.FabricName())
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleCablingErrorResponse("Show", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	if len(response.Links) == 0 {
+		fmt.Println("No Cabling Plan")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Device One", "Interface One", "Device Two", "Interface Two"})
+	table.SetRowLine(true)
+	for _, Link := range response.Links {
+		table.Append([]string{Link.DeviceOne, Link.InterfaceOne, Link.DeviceTwo, Link.InterfaceTwo})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,128 @@
+package cabling
+
+import (
+	"bytes"
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+var uploadFile string
+
+//UploadCommand provides command to replace the cabling plan of the fabric with the links of a file
+var UploadCommand = &cobra.Command{
+	Use:   "upload",
+	Short: "Replace the cabling plan of the IP Fabric with the links of a CSV or JSON file",
+	Long: `Replace the cabling plan of the IP Fabric with the links of a CSV or JSON file.
+
+A CSV file holds a link per line, "device_one,interface_one,device_two,interface_two", the header line is optional.
+A JSON file holds the links as {"links": [{"device_one": "10.24.39.224", "interface_one": "0/1",
+"device_two": "10.24.39.225", "interface_two": "0/49"}]}, or their array alone.`,
+	RunE: utils.TimedRunE(runCablingUpload),
+}
+
+func init() {
+	UploadCommand.Flags().StringVarP(&uploadFile, "file", "f", "", "Cabling plan file, CSV or JSON")
+	UploadCommand.MarkFlagRequired("file")
+	utils.AddOutputFlag(UploadCommand)
+}
+
+func runCablingUpload(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	Links, err := loadCablingPlan(uploadFile)
+	if err != nil {
+		fmt.Println(err)
+		return &utils.ExitError{Code: utils.ExitFailure}
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	PlanRequest := openAPI.CablingPlanRequest{FabricName: utils.FabricName(), Links: Links}
+	response, _, err := api.FabricCablingApi.UpdateCablingPlan(context.Background(),
+		map[string]interface{}{"cablingPlan": PlanRequest})
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleCablingErrorResponse("Upload", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+	fmt.Println(response.Message)
+	fmt.Println("Cabling Plan Upload [Success]")
+	return nil
+}
+
+//loadCablingPlan reads the links of a cabling plan file, a file starting with "{" or "[" is read as JSON
+func loadCablingPlan(FileName string) ([]openAPI.CablingLink, error) {
+	data, err := ioutil.ReadFile(FileName)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) || bytes.HasPrefix(data, []byte("[")) {
+		return parseCablingPlanJSON(FileName, data)
+	}
+	return parseCablingPlanCSV(FileName, data)
+}
+
+func parseCablingPlanJSON(FileName string, data []byte) ([]openAPI.CablingLink, error) {
+	var Plan struct {
+		Links []openAPI.CablingLink `json:"links"`
+	}
+	var err error
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = decodeCablingPlanJSON(data, &Plan.Links)
+	} else {
+		err = decodeCablingPlanJSON(data, &Plan)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid cabling plan %s: %s", FileName, err)
+	}
+	return Plan.Links, nil
+}
+
+func decodeCablingPlanJSON(data []byte, Plan interface{}) error {
+	Decoder := json.NewDecoder(bytes.NewReader(data))
+	Decoder.DisallowUnknownFields()
+	return Decoder.Decode(Plan)
+}
+
+//parseCablingPlanCSV reads a link per record, the lines starting with "#" are comments
+func parseCablingPlanCSV(FileName string, data []byte) ([]openAPI.CablingLink, error) {
+	Reader := csv.NewReader(bytes.NewReader(data))
+	Reader.Comment = '#'
+	Reader.FieldsPerRecord = 4
+	Reader.TrimLeadingSpace = true
+	Links := make([]openAPI.CablingLink, 0)
+	for {
+		Record, err := Reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cabling plan %s: %s", FileName, err)
+		}
+		for iter := range Record {
+			Record[iter] = strings.TrimSpace(Record[iter])
+		}
+		if len(Links) == 0 && strings.EqualFold(Record[0], "device_one") {
+			continue
+		}
+		Links = append(Links, openAPI.CablingLink{DeviceOne: Record[0], InterfaceOne: Record[1],
+			DeviceTwo: Record[2], InterfaceTwo: Record[3]})
+	}
+	return Links, nil
+}
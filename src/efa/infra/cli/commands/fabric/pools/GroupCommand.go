@@ -0,0 +1,16 @@
+package pools
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Fabric allocation pool commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pools",
+		Short: "IP Fabric ASN and IP allocation pool commands",
+	}
+	cmd.AddCommand(ShowCommand)
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+package pools
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var allocations bool
+
+//ShowCommand provides command to display the utilization of the fabric allocation pools
+var ShowCommand = &cobra.Command{
+	Use:   "show",
+	Short: "Display the range, used and free count of the ASN and IP pools",
+	RunE:  utils.TimedRunE(runPoolsShow),
+}
+
+func init() {
+	ShowCommand.Flags().BoolVar(&allocations, "allocations", false, "List the devices and interfaces holding each allocation")
+	utils.AddOutputFlag(ShowCommand)
+}
+
+func runPoolsShow(cmd *cobra.Command, args []string) error {
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.FabricPoolsApi.GetFabricPools(context.Background(), utils.FabricName())
+	if err != nil {
+		return utils.RequestError(err, handlePoolsShowErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Type", "Name", "Range", "Used", "Free", "Utilization"})
+	table.SetRowLine(true)
+	for _, Pool := range response.Pools {
+		table.Append([]string{Pool.PoolType, Pool.PoolName, Pool.Range, fmt.Sprintf("%d", Pool.Used),
+			fmt.Sprintf("%d", Pool.Free), fmt.Sprintf("%d%%", Pool.Utilization)})
+	}
+	table.Render()
+
+	if allocations {
+		for _, Pool := range response.Pools {
+			if len(Pool.Allocations) == 0 {
+				continue
+			}
+			fmt.Printf("\n%s Pool %s\n", Pool.PoolType, Pool.PoolName)
+			allocationTable := tablewriter.NewWriter(os.Stdout)
+			allocationTable.SetAlignment(tablewriter.ALIGN_LEFT)
+			allocationTable.SetHeader([]string{"Value", "Device", "Interface"})
+			allocationTable.SetRowLine(true)
+			for _, Allocation := range Pool.Allocations {
+				allocationTable.Append([]string{Allocation.Value, Allocation.DeviceIp, Allocation.InterfaceName})
+			}
+			allocationTable.Render()
+		}
+	}
+
+	if len(response.PoolWarnings) > 0 {
+		fmt.Printf("\nPools above the warning threshold of %d%%\n", response.Threshold)
+		for _, Warning := range response.PoolWarnings {
+			fmt.Println("\t" + Warning)
+		}
+	}
+	return nil
+}
+
+func handlePoolsShowErrorResponse(errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	//Body Contains the Error Obect in JSON
+	fmt.Println("Pools Show [Failed]")
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -12,6 +12,8 @@ func NewGroupCmd() *cobra.Command {
 	}
 	cmd.AddCommand(ShowCommand)
 	cmd.AddCommand(UpdateCommand)
+	cmd.AddCommand(HistoryCommand)
+	cmd.AddCommand(RollbackCommand)
 
 	return cmd
 }
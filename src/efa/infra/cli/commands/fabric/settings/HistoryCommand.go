@@ -0,0 +1,107 @@
+package settings
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	"efa/infra/constants"
+	openAPIClient "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var rollbackTo int32
+
+//HistoryCommand provides command to list the changes of the fabric settings
+var HistoryCommand = &cobra.Command{
+	Use:   "history",
+	Short: "Display the changes of the fabric settings with the user and the time of each change",
+	RunE:  utils.TimedRunE(runFabricSettingsHistory),
+}
+
+//RollbackCommand provides command to restore the fabric settings as of a change of the settings history
+var RollbackCommand = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the fabric settings as they were right after a change of the settings history",
+	RunE:  utils.TimedRunE(runFabricSettingsRollback),
+}
+
+func init() {
+	RollbackCommand.Flags().Int32Var(&rollbackTo, "to", 0, "ID of the settings change to roll back to, see \"efa fabric setting history\"")
+	RollbackCommand.MarkFlagRequired("to")
+	utils.AddOutputFlag(HistoryCommand)
+}
+
+func runFabricSettingsHistory(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPIClient.NewAPIClient(cfg)
+
+	response, _, err := api.FabricSettingsHistoryApi.GetFabricSettingsHistory(context.Background(), utils.FabricName())
+	if err != nil {
+		return utils.RequestError(err, handleFabricSettingsHistoryErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	if len(response.Changes) == 0 {
+		fmt.Println("The fabric settings were never changed")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"ID", "Changed At", "User", "Setting", "Old", "New", "Execution ID"})
+	for _, Change := range response.Changes {
+		table.Append([]string{fmt.Sprintf("%d", Change.Id), Change.ChangedAt.Local().Format(constants.DefaultTimeFormat),
+			Change.User, Change.Setting, Change.OldValue, Change.NewValue, Change.ExecutionId})
+	}
+	table.Render()
+	return nil
+}
+
+func handleFabricSettingsHistoryErrorResponse(errorObject error) {
+	fmt.Println("Fabric settings History [Failed]")
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
+
+func runFabricSettingsRollback(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPIClient.NewAPIClient(cfg)
+
+	response, _, err := api.FabricSettingsHistoryApi.RollbackFabricSettings(context.Background(),
+		utils.FabricName(), rollbackTo)
+	if err != nil {
+		handleFabricSettingsErrorResponse("Fabric settings Rollback", err)
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Setting", "Old", "New"})
+	for _, Setting := range response.Settings {
+		table.Append([]string{Setting.Name, Setting.Old, Setting.New})
+	}
+	table.Render()
+	fmt.Printf("%s Fabric settings Rollback to change %d Successful\n", response.FabricName, response.To)
+	return nil
+}
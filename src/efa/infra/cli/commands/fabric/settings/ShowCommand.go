@@ -3,15 +3,12 @@ package settings
 import (
 	"context"
 	"efa/infra/cli/utils"
-	"efa/infra/constants"
 	openAPIClient "efa/infra/rest/generated/client"
-	"encoding/json"
 	"fmt"
 	"github.com/mitchellh/mapstructure"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"os"
-	"strings"
 )
 
 var (
@@ -28,24 +25,30 @@ var ShowCommand = &cobra.Command{
 
 func init() {
 	ShowCommand.Flags().BoolVar(&advanced, "advanced", false, "List advanced Fabric parameters.")
+	utils.AddOutputFlag(ShowCommand)
 }
 
 func runFabricShow(cmd *cobra.Command, args []string) error {
 
-	fabricName = constants.DefaultFabric
+	fabricName = utils.FabricName()
 	var err error
-	cfg := openAPIClient.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPIClient.NewAPIClient(cfg)
 
 	response, _, err := api.FabricApi.GetFabric(context.Background(), fabricName)
 	if err != nil {
-		handleConfigShowErrorResponse(err)
-		return nil
+		return utils.RequestError(err, handleConfigShowErrorResponse)
+	}
+	if utils.IsStructuredOutput() {
+		//The model holds all the settings, "--advanced" only selects the rows of the table
+		return utils.PrintModel(response)
 	}
 	FabricProperties, err := CreateFromMap(response.FabricSettings)
 	if err != nil {
-		handleConfigShowErrorResponse(err)
-		return nil
+		return utils.RequestError(err, handleConfigShowErrorResponse)
 	}
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
@@ -97,6 +100,14 @@ func runFabricShow(cmd *cobra.Command, args []string) error {
 			table.Append([]string{"Rack Peer EBGP Group", FabricProperties.RackPeerEBGPGroup})
 			table.Append([]string{"Rack Peer Overlay Evpn Group", FabricProperties.RackPeerOvgGroup})
 		}
+		table.Append([]string{"BGP Auth Type", FabricProperties.BGPAuthType})
+		if FabricProperties.FabricType == utils.CLOSFabricType {
+			table.Append([]string{"PeerGroup Password", FabricProperties.PeerGroupPassword})
+		} else {
+			table.Append([]string{"Rack Peer EBGP Group Password", FabricProperties.RackPeerEBGPGroupPassword})
+			table.Append([]string{"Rack Peer Overlay Evpn Group Password", FabricProperties.RackPeerOvgGroupPassword})
+		}
+		table.Append([]string{"MCT L2EVPN Password", FabricProperties.MctL2EvpnPassword})
 		table.Append([]string{"MCT Link IP Range", FabricProperties.MCTLinkIPRange})
 		table.Append([]string{"MCT PortChannel", FabricProperties.MctPortChannel})
 		table.Append([]string{"Routing MCT PortChannel", FabricProperties.RoutingMctPortChannel})
@@ -104,6 +115,7 @@ func runFabricShow(cmd *cobra.Command, args []string) error {
 		table.Append([]string{"Control VE", FabricProperties.ControlVE})
 		//Unused May be we should completly Remove them may be they are copied from Legacy EWC Code
 		table.Append([]string{"VNI Auto Map", FabricProperties.VNIAutoMap})
+		table.Append([]string{"Pool Warning Threshold", FabricProperties.PoolWarningThreshold})
 	}
 
 	table.Render()
@@ -117,17 +129,7 @@ func handleConfigShowErrorResponse(errorObject error) {
 	if utils.IsServerConnectionError(errorObject) {
 		return
 	}
-	errorMessageList := strings.Split(errorObject.Error(), "Body:")
-	if len(errorMessageList) == 2 {
-		var ErrorModel openAPIClient.ErrorModel
-		err := json.Unmarshal([]byte(errorMessageList[1]), &ErrorModel)
-		if err == nil {
-			fmt.Println(ErrorModel.Message)
-		}
-	} else {
-		//Generic Error, Just print it
-		fmt.Println("\t" + errorObject.Error())
-	}
+	utils.PrintErrorModel(errorObject)
 
 }
 
@@ -140,9 +142,12 @@ func CreateFromMap(m map[string]string) (FabricProperties, error) {
 
 // GetFabricShowOut featch all fabric setting and returns in a Table data structure
 func GetFabricShowOut(table *tablewriter.Table) error {
-	fabricName = constants.DefaultFabric
+	fabricName = utils.FabricName()
 	var err error
-	cfg := openAPIClient.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPIClient.NewAPIClient(cfg)
 
 	response, _, err := api.FabricApi.GetFabric(context.Background(), fabricName)
@@ -3,17 +3,17 @@ package settings
 import (
 	"context"
 	"efa/infra/cli/utils"
-	"efa/infra/constants"
 	openAPIClient "efa/infra/rest/generated/client"
-	"encoding/json"
 	"fmt"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"os"
 	"reflect"
-	"strings"
 )
 
 var (
 	fabricUpdateRequest FabricProperties
+	fabricUpdatePreview bool
 )
 
 //FabricProperties as structure
@@ -72,6 +72,15 @@ type FabricProperties struct {
 	// NON ClOS Fields
 	RackPeerEBGPGroup string `json:"rack_peer_ebgp_group"`
 	RackPeerOvgGroup  string `json:"rack_overlay_evpn_group"`
+
+	//BGP Authentication Fields
+	BGPAuthType               string `json:"bgp_auth_type"`
+	PeerGroupPassword         string `json:"peer_group_password"`
+	MctL2EvpnPassword         string `json:"mct_l2evpn_password"`
+	RackPeerEBGPGroupPassword string `json:"rack_peer_ebgp_group_password"`
+	RackPeerOvgGroupPassword  string `json:"rack_overlay_evpn_group_password"`
+
+	PoolWarningThreshold string `json:"pool_warning_threshold"`
 }
 
 //UpdateCommand provides command for updating Fabric Properties
@@ -82,36 +91,24 @@ var UpdateCommand = &cobra.Command{
 }
 
 func init() {
-
-	fabricName = constants.DefaultFabric
-	cfg := openAPIClient.NewConfiguration()
-	api := openAPIClient.NewAPIClient(cfg)
-
-	response, _, _ := api.FabricApi.GetFabric(context.Background(), fabricName)
-	FabricProperties, err := CreateFromMap(response.FabricSettings)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
 	UpdateCommand.Flags().SortFlags = false
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.P2PLinkRange, "p2p-link-range", "", "Range Of IP Address.")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.LoopBackIPRange, "loopback-ip-range", "", "Range Of IP Address")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.LoopBackPortNumber, "loopback-port-number", "", "Loopback Port Number <NUMBER: 1-255>")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.VTEPLoopBackPortNumber, "vtep-loopback-port-number", "", "VTEP Loopback Port Number <NUMBER: 1-255>")
-	if FabricProperties.FabricType == utils.CLOSFabricType {
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.LeafPeerGroup, "leaf-peer-group", "", "Leaf Peer Group Name <WORD: 1-63>")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.SpinePeerGroup, "spine-peer-group", "", "Spine Peer Group Name <WORD: 1-63>")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.SpineASNBlock, "spine-asn-block", "", "Spine ASN Range Separated -;Or Single AS"+"")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.LeafASNBlock, "leaf-asn-block", "", "Leaf ASN Range Separated -")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.ConfigureOverlayGateway, "configure-overlay-gateway", "", "ConfigureOverlayGateway Enabled Yes/No")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.P2PIPType, "p2p-ip-type", "", "IP Type numbered/unnumbered")
-	} else {
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MCTL3LBIPRange, "l3-backup-ip-range", "", "Range Of IP Address")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackASNBlock, "rack-asn-block", "", "Rack ASN Range Separated -")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackPeerEBGPGroup, "rack-peer-ebgp-group", "", "Rack Peer eBgp Group Name <WORD: 1-63>")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackPeerOvgGroup, "rack-peer-overlay-evpn-group", "", "Rack Peer Overlay Evpn Group Name <WORD: 1-63>")
-	}
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.LeafPeerGroup, "leaf-peer-group", "", "Leaf Peer Group Name <WORD: 1-63> for clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.SpinePeerGroup, "spine-peer-group", "", "Spine Peer Group Name <WORD: 1-63> for clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.SpineASNBlock, "spine-asn-block", "", "Spine ASN Range Separated -;Or Single AS"+" for clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.LeafASNBlock, "leaf-asn-block", "", "Leaf ASN Range Separated - for clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.ConfigureOverlayGateway, "configure-overlay-gateway", "", "ConfigureOverlayGateway Enabled Yes/No for clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.P2PIPType, "p2p-ip-type", "", "IP Type numbered/unnumbered for clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.PeerGroupPassword, "peer-group-password", "", "Leaf/Spine Peer Group Password <WORD: 1-63> for clos fabric, none to remove")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MCTL3LBIPRange, "l3-backup-ip-range", "", "Range Of IP Address for non-clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackASNBlock, "rack-asn-block", "", "Rack ASN Range Separated - for non-clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackPeerEBGPGroup, "rack-peer-ebgp-group", "", "Rack Peer eBgp Group Name <WORD: 1-63> for non-clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackPeerOvgGroup, "rack-peer-overlay-evpn-group", "", "Rack Peer Overlay Evpn Group Name <WORD: 1-63> for non-clos fabric")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackPeerEBGPGroupPassword, "rack-peer-ebgp-group-password", "", "Rack Peer eBgp Group Password <WORD: 1-63> for non-clos fabric, none to remove")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RackPeerOvgGroupPassword, "rack-peer-overlay-evpn-group-password", "", "Rack Peer Overlay Evpn Group Password <WORD: 1-63> for non-clos fabric, none to remove")
 
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.AnyCastMac, "anycast-mac-address", "", "IPV4 ANY CAST MAC address.mac address HHHH.HHHH.HHHH")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.IPV6AnyCastMac, "ipv6-anycast-mac-address", "", "IPV6 ANY CAST MAC address.mac address HHHH.HHHH.HHHH")
@@ -122,16 +119,16 @@ func init() {
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.DuplicateMacTimer, "duplicate-mac-timer", "", "Duplicate Mac Timer")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.DuplicateMaxTimerMaxCount, "duplicate-mac-timer-max-count", "", "Duplicate Mac Timer Max Count")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDEnable, "bfd-enable", "", "BFD enabled <STRING Yes/No>")
-	if FabricProperties.BFDEnable == "Yes" {
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDTx, "bfd-tx", "", "BFD desired min transmit interval in milliseconds <NUMBER: 50-30000>")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDRx, "bfd-rx", "", "BFD desired min receive interval in milliseconds <NUMBER: 50-30000>")
-		UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDMultiplier, "bfd-multiplier", "", "BFD detection time multiplier <NUMBER: 3-50> ")
-	}
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDTx, "bfd-tx", "", "BFD desired min transmit interval in milliseconds <NUMBER: 50-30000>, when BFD is enabled")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDRx, "bfd-rx", "", "BFD desired min receive interval in milliseconds <NUMBER: 50-30000>, when BFD is enabled")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BFDMultiplier, "bfd-multiplier", "", "BFD detection time multiplier <NUMBER: 3-50>, when BFD is enabled")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BGPMultiHop, "bgp-multihop", "", "Allow EBGP neighbors not on directly connected networks <Number:1-255> ")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MaxPaths, "max-paths", "", "Forward packets over multiple paths<Number:1-64>")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.AllowASIn, "allow-as-in", "", "Disables the AS_PATH check of the routes learned from the AS<Number:1-10> ")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MTU, "mtu", "", "The MTU size in bytes <Number:1548-9216>")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.IPMTU, "ip-mtu", "", "For SLX IPV4/IPV6 MTU size in bytes <Number:1300-9194>")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.BGPAuthType, "bgp-auth-type", "", "BGP Authentication Type <STRING md5>")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MctL2EvpnPassword, "mct-l2evpn-password", "", "MCT L2EVPN Neighbor Password <WORD: 1-63>, none to remove")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MCTLinkIPRange, "mctlink-ip-range", "", "Range Of IP Address")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.MctPortChannel, "mct-port-channel", "", "Portchannel interface number <NUMBER: 1-1024>")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.RoutingMctPortChannel, "routing-mct-port-channel", "", "Portchannel interface number <NUMBER: 1-64>")
@@ -139,6 +136,8 @@ func init() {
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.ControlVE, "control-ve", "", "vlan number <NUMBER: 1-4090>")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.VNIAutoMap, "vni-auto-map", "", "VNI Auto Map <STRING Yes/No>")
 	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.FabricType, "fabric-type", "", "Fabric Type <STRING clos/non-clos>")
+	UpdateCommand.Flags().StringVar(&fabricUpdateRequest.PoolWarningThreshold, "pool-warning-threshold", "", "Pool utilization in percent reported by validate and configure <NUMBER: 1-100>")
+	UpdateCommand.Flags().BoolVar(&fabricUpdatePreview, "preview", false, "Display the configuration changes of the devices and the pool reallocations without updating the settings")
 }
 
 //PrepareFabricSettingsRequest prepares the Fabric Setting Request
@@ -160,34 +159,80 @@ func runFabricUpdate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 	var FabricSetting openAPIClient.FabricSettings
-	FabricSetting.Name = constants.DefaultFabric
+	FabricSetting.Name = utils.FabricName()
 	fabricUpdateRequest.PrepareFabricSettingsRequest(&FabricSetting)
-	cfg := openAPIClient.NewConfiguration()
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
 	api := openAPIClient.NewAPIClient(cfg)
 	data := make(map[string]interface{})
 	data["fabricSettings"] = FabricSetting
 
-	FabricUpdateResponse, _, err := api.FabricApi.UpdateFabric(context.Background(), data)
-	if err != nil {
-		if utils.IsServerConnectionError(err) {
-			return nil
-		}
-		var FabricdataErrorResp openAPIClient.FabricdataErrorResponse
-		status := strings.Split(err.Error(), "Body:")
-		jerr := json.Unmarshal([]byte(status[1]), &FabricdataErrorResp)
-		if jerr != nil {
-			fmt.Println("Error While Decoding Server Response")
+	if fabricUpdatePreview {
+		FabricPreviewResponse, _, err := api.FabricApi.PreviewFabric(context.Background(), data)
+		if err != nil {
+			handleFabricSettingsErrorResponse("Fabric settings Preview", err)
 			return nil
 		}
-		//fmt.Printf("%s\n", status[0])
-		fmt.Printf("%s Fabric settings Update Failed\n", FabricdataErrorResp.FabricName)
-		fmt.Printf("Reason: \n")
-		for _, val := range FabricdataErrorResp.FabricSettings {
-			fmt.Printf("\t%s\n", val)
-		}
+		printFabricPreview(FabricPreviewResponse)
+		return nil
+	}
+
+	FabricUpdateResponse, _, err := api.FabricApi.UpdateFabric(context.Background(), data)
+	if err != nil {
+		handleFabricSettingsErrorResponse("Fabric settings Update", err)
 	} else {
 		fmt.Printf("%s Fabric Update Successful\n", FabricSetting.Name)
 		fmt.Printf("FabricId: %d\n", FabricUpdateResponse.FabricId)
 	}
 	return nil
 }
+
+func handleFabricSettingsErrorResponse(Operation string, err error) {
+	if utils.IsServerConnectionError(err) {
+		return
+	}
+	fmt.Printf("%s %s Failed\n", utils.FabricName(), Operation)
+	fmt.Printf("Reason: \n")
+	utils.PrintErrorModel(err)
+}
+
+//printFabricPreview displays the settings changed, the changes of the device configurations and the pool reallocations
+func printFabricPreview(Preview openAPIClient.FabricPreviewResponse) {
+	fmt.Printf("%s Fabric settings Preview, the settings are not updated\n", Preview.FabricName)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Setting", "Old", "New"})
+	for _, Setting := range Preview.Settings {
+		table.Append([]string{Setting.Name, Setting.Old, Setting.New})
+	}
+	table.Render()
+
+	if len(Preview.Changes) == 0 {
+		fmt.Println("No configuration changes on the devices")
+	} else {
+		table = tablewriter.NewWriter(os.Stdout)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetHeader([]string{"Device", "Kind", "Name", "Change", "Old", "New"})
+		for _, Change := range Preview.Changes {
+			table.Append([]string{Change.Device, Change.Kind, Change.Name, Change.Change, Change.Old, Change.New})
+		}
+		table.Render()
+	}
+
+	if len(Preview.Reallocations) != 0 {
+		fmt.Println("Values to be reallocated:")
+		table = tablewriter.NewWriter(os.Stdout)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetHeader([]string{"Pool Type", "Pool Name", "New Range", "Value", "Device", "Interface"})
+		for _, Reallocation := range Preview.Reallocations {
+			table.Append([]string{Reallocation.PoolType, Reallocation.PoolName, Reallocation.Range, Reallocation.Value,
+				Reallocation.DeviceIp, Reallocation.InterfaceName})
+		}
+		table.Render()
+	}
+	if Preview.Blocked != "" {
+		fmt.Printf("Note: %s\n", Preview.Blocked)
+	}
+}
@@ -0,0 +1,19 @@
+package notification
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewGroupCmd provides grouping of Notification commands
+func NewGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notification",
+		Short: "Notification commands, the webhook endpoints subscribed to the events of the server",
+	}
+	cmd.AddCommand(SubscribeCommand)
+	cmd.AddCommand(ListCommand)
+	cmd.AddCommand(DeleteCommand)
+	cmd.AddCommand(TestCommand)
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+package notification
+
+import (
+	"context"
+	"efa/infra/cli/utils"
+	"efa/infra/constants"
+	openAPI "efa/infra/rest/generated/client"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+var (
+	subscriptionURL     string
+	subscriptionSecret  string
+	subscriptionEvents  []string
+	subscriptionRetries int32
+	subscriptionID      int32
+)
+
+//SubscribeCommand provides command to subscribe a webhook endpoint to the events of the server
+var SubscribeCommand = &cobra.Command{
+	Use:   "subscribe",
+	Short: "Subscribe a webhook endpoint to the events of the server",
+	Long: "Subscribe a webhook endpoint to the events of the server. The events are posted as JSON to the URL, " +
+		"signed with the secret in the X-Efa-Signature header when there is one. The events are " +
+		strings.Join(events, ", ") + ", all of them when no event is given.",
+	RunE: utils.TimedRunE(runSubscribe),
+}
+
+//ListCommand provides command to list the notification subscriptions
+var ListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "Display the webhook endpoints subscribed to the events of the server",
+	RunE:  utils.TimedRunE(runList),
+}
+
+//DeleteCommand provides command to delete a notification subscription
+var DeleteCommand = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a notification subscription",
+	RunE:  utils.TimedRunE(runDelete),
+}
+
+//TestCommand provides command to post a test event to the endpoint of a subscription
+var TestCommand = &cobra.Command{
+	Use:   "test",
+	Short: "Post a test event to the endpoint of a notification subscription",
+	RunE:  utils.TimedRunE(runTest),
+}
+
+//events lists the events a subscription can subscribe to
+var events = []string{"execution.started", "execution.completed", "execution.failed", "validation.missing_links",
+	"device.credentials_failed"}
+
+func init() {
+	SubscribeCommand.Flags().StringVar(&subscriptionURL, "url", "", "http or https URL the events are posted to")
+	SubscribeCommand.Flags().StringVar(&subscriptionSecret, "secret", "", "Secret signing the events with HMAC-SHA256")
+	SubscribeCommand.Flags().StringSliceVar(&subscriptionEvents, "event", nil, "Event of the subscription, repeat for several events")
+	SubscribeCommand.Flags().Int32Var(&subscriptionRetries, "retries", 3, "Retries of a failed delivery, at most 10")
+	SubscribeCommand.MarkFlagRequired("url")
+	DeleteCommand.Flags().Int32Var(&subscriptionID, "id", 0, "ID of the subscription, see \"efa notification list\"")
+	DeleteCommand.MarkFlagRequired("id")
+	TestCommand.Flags().Int32Var(&subscriptionID, "id", 0, "ID of the subscription, see \"efa notification list\"")
+	TestCommand.MarkFlagRequired("id")
+	utils.AddOutputFlag(ListCommand)
+}
+
+func runSubscribe(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	Request := openAPI.NotificationSubscriptionRequest{Url: subscriptionURL, Secret: subscriptionSecret,
+		Events: subscriptionEvents, Retries: subscriptionRetries}
+	Subscription, _, err := api.NotificationApi.CreateNotificationSubscription(context.Background(),
+		map[string]interface{}{"subscription": Request})
+	if err != nil {
+		handleNotificationErrorResponse("Notification Subscribe", err)
+		return nil
+	}
+	printSubscriptions([]openAPI.NotificationSubscription{Subscription})
+	fmt.Printf("Notification Subscribe %d [Success]\n", Subscription.Id)
+	return nil
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	response, _, err := api.NotificationApi.GetNotificationSubscriptions(context.Background())
+	if err != nil {
+		return utils.RequestError(err, func(err error) { handleNotificationErrorResponse("Notification List", err) })
+	}
+	if utils.IsStructuredOutput() {
+		return utils.PrintModel(response)
+	}
+
+	if len(response.Subscriptions) == 0 {
+		fmt.Println("No notification subscriptions")
+		return nil
+	}
+	printSubscriptions(response.Subscriptions)
+	return nil
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	Subscription, _, err := api.NotificationApi.DeleteNotificationSubscription(context.Background(), subscriptionID)
+	if err != nil {
+		handleNotificationErrorResponse("Notification Delete", err)
+		return nil
+	}
+	fmt.Printf("Notification Delete %d %s [Success]\n", Subscription.Id, Subscription.Url)
+	return nil
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		fmt.Println("Additional arguments passed to the command.")
+		return nil
+	}
+	cfg, err := utils.NewAPIConfiguration()
+	if err != nil {
+		return err
+	}
+	api := openAPI.NewAPIClient(cfg)
+
+	Event, _, err := api.NotificationApi.TestNotificationSubscription(context.Background(), subscriptionID)
+	if err != nil {
+		handleNotificationErrorResponse("Notification Test", err)
+		return nil
+	}
+	fmt.Printf("Notification Test %d, event %s [Success]\n", subscriptionID, Event.Id)
+	return nil
+}
+
+func printSubscriptions(Subscriptions []openAPI.NotificationSubscription) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"ID", "URL", "Events", "Signed", "Retries", "Created At"})
+	table.SetRowLine(true)
+	for _, Subscription := range Subscriptions {
+		Events := strings.Join(Subscription.Events, "\n")
+		if len(Events) == 0 {
+			Events = "all"
+		}
+		table.Append([]string{fmt.Sprintf("%d", Subscription.Id), Subscription.Url, Events,
+			fmt.Sprintf("%t", Subscription.Signed), fmt.Sprintf("%d", Subscription.Retries),
+			Subscription.CreatedAt.Local().Format(constants.DefaultTimeFormat)})
+	}
+	table.Render()
+}
+
+func handleNotificationErrorResponse(Operation string, errorObject error) {
+	//OpenAPI Generated code sends the message as an error string, so parsing output from string object
+	//Body Contains the Error Obect in JSON
+	fmt.Printf("%s [Failed]\n", Operation)
+	if utils.IsServerConnectionError(errorObject) {
+		return
+	}
+	utils.PrintErrorModel(errorObject)
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"efa/infra/constants"
+	openAPIClient "efa/infra/rest/generated/client"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Context names an efa-server, the fabric and the device credentials used by the commands
+type Context struct {
+	Name     string `yaml:"name" json:"name"`
+	Server   string `yaml:"server" json:"server"`
+	Fabric   string `yaml:"fabric,omitempty" json:"fabric,omitempty"`
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+//Config is the contexts file of the CLI
+type Config struct {
+	CurrentContext string    `yaml:"current-context,omitempty" json:"current-context,omitempty"`
+	Contexts       []Context `yaml:"contexts,omitempty" json:"contexts,omitempty"`
+}
+
+var (
+	//ServerFlag and ContextFlag are set by the global --server and --context flags
+	ServerFlag  string
+	ContextFlag string
+)
+
+//ConfigFile returns the location of the contexts file
+func ConfigFile() string {
+	if File := os.Getenv(constants.ConfigFileEnv); len(File) != 0 {
+		return File
+	}
+	Home, err := os.UserHomeDir()
+	if err != nil {
+		return constants.ConfigFileName
+	}
+	return filepath.Join(Home, constants.ConfigFileName)
+}
+
+//LoadConfig reads the contexts file, a missing file has no contexts
+func LoadConfig() (Config, error) {
+	var Config Config
+	data, err := ioutil.ReadFile(ConfigFile())
+	if os.IsNotExist(err) {
+		return Config, nil
+	}
+	if err != nil {
+		return Config, err
+	}
+	if err = yaml.UnmarshalStrict(data, &Config); err != nil {
+		return Config, fmt.Errorf("Invalid contexts file %s: %s", ConfigFile(), err)
+	}
+	return Config, nil
+}
+
+//Save writes the contexts file, readable by the user only as it may hold device passwords
+func (Config *Config) Save() error {
+	data, err := yaml.Marshal(Config)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(ConfigFile()), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ConfigFile(), data, 0600)
+}
+
+//Find returns the context of the name
+func (Config *Config) Find(Name string) (*Context, bool) {
+	for iter := range Config.Contexts {
+		if Config.Contexts[iter].Name == Name {
+			return &Config.Contexts[iter], true
+		}
+	}
+	return nil, false
+}
+
+//CurrentContext returns the context used by the commands: the one named by --context, else the current
+//context of the contexts file. --server overrides the server of the context.
+func CurrentContext() (Context, error) {
+	Current := Context{Server: constants.DefaultServer, Fabric: constants.DefaultFabric}
+	Config, err := LoadConfig()
+	if err != nil {
+		return Current, err
+	}
+	Name := ContextFlag
+	if len(Name) == 0 {
+		Name = Config.CurrentContext
+	}
+	if len(Name) != 0 {
+		Context, found := Config.Find(Name)
+		if !found {
+			return Current, fmt.Errorf("Context %s not found in %s", Name, ConfigFile())
+		}
+		Current = *Context
+		if len(Current.Fabric) == 0 {
+			Current.Fabric = constants.DefaultFabric
+		}
+	}
+	if len(ServerFlag) != 0 {
+		Current.Server = ServerFlag
+	}
+	return Current, nil
+}
+
+//ContextCredentials returns the device credentials of the current context
+func ContextCredentials() (string, string) {
+	Current, err := CurrentContext()
+	if err != nil {
+		return "", ""
+	}
+	return Current.Username, Current.Password
+}
+
+//ValidateServer checks the URL of an efa-server
+func ValidateServer(Server string) error {
+	if !strings.HasPrefix(Server, "http://") && !strings.HasPrefix(Server, "https://") {
+		return errors.New("The server should be an URL like http://<host>:8081")
+	}
+	return nil
+}
+
+//NewAPIConfiguration returns the client configuration targeting the server of the current context.
+//The context is read when a command runs, never when the binary starts. An unreadable or invalid contexts
+//file is an error, only a missing one targets the default server.
+func NewAPIConfiguration() (*openAPIClient.Configuration, error) {
+	cfg := openAPIClient.NewConfiguration()
+	AddUserHeader(cfg)
+	Current, err := CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	cfg.BasePath = strings.TrimSuffix(strings.TrimSuffix(Current.Server, "/"), "/v1") + "/v1"
+	return cfg, nil
+}
+
+//FabricName returns the fabric of the current context
+func FabricName() string {
+	Current, err := CurrentContext()
+	if err != nil {
+		return constants.DefaultFabric
+	}
+	return Current.Fabric
+}
@@ -1,22 +1,64 @@
 package utils
 
 import (
+	openAPI "efa/infra/rest/generated/client"
 	"fmt"
 	"net"
 	"net/url"
+	"strings"
 )
 
 //IsServerConnectionError identifies if its a server connection error
 func IsServerConnectionError(error error) bool {
+	if isDialError(error) {
+		fmt.Println("Error: Server unreachable. Please make sure the server is operational and reachable.")
+		return true
+	}
+	return false
+}
+
+func isDialError(error error) bool {
 	urlError, _ := error.(*url.Error)
 	if urlError != nil {
 		op, _ := urlError.Err.(*net.OpError)
-		if op != nil {
-			if op.Op == "dial" {
-				fmt.Println("Error: Server unreachable. Please make sure the server is operational and reachable.")
-				return true
-			}
+		if op != nil && op.Op == "dial" {
+			return true
 		}
 	}
 	return false
 }
+
+//ErrorModelOf returns the error model of a failed REST request, false when the server returned none
+func ErrorModelOf(error error) (openAPI.ExtendedErrorModel, bool) {
+	SwaggerError, ok := error.(openAPI.GenericSwaggerError)
+	if !ok {
+		return openAPI.ExtendedErrorModel{}, false
+	}
+	ErrorModel, ok := SwaggerError.Model().(openAPI.ExtendedErrorModel)
+	return ErrorModel, ok
+}
+
+//PrintErrorModel displays the error model of a failed REST request: the message, the errors of each
+//setting or device and the execution ID. The error itself is displayed when the server returned no error model.
+func PrintErrorModel(error error) {
+	ErrorModel, ok := ErrorModelOf(error)
+	if !ok {
+		fmt.Println("\t" + error.Error())
+		return
+	}
+	fmt.Println(ErrorModel.Message)
+	for _, Error := range ErrorModel.Errors {
+		Subject := Error.Field
+		if len(Subject) == 0 {
+			Subject = Error.Device
+		}
+		if len(Subject) == 0 {
+			fmt.Printf("\t%s\n", strings.TrimRight(Error.Message, "\n"))
+		} else {
+			fmt.Printf("\t%s: %s\n", Subject, strings.TrimRight(Error.Message, "\n"))
+		}
+	}
+	if len(ErrorModel.ExecutionId) != 0 {
+		fmt.Printf("Execution ID: %s\n", ErrorModel.ExecutionId)
+	}
+}
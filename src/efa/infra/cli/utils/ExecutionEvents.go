@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"efa/infra/constants"
+	openAPI "efa/infra/rest/generated/client"
+	"encoding/json"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ExecutionEventsDrain is how long the events of an execution are still read once its request returned
+var ExecutionEventsDrain = 5 * time.Second
+
+//NewExecutionID returns a random UUID identifying the execution of a request
+func NewExecutionID() string {
+	ID := make([]byte, 16)
+	rand.Read(ID)
+	ID[6] = ID[6]&0x0f | 0x40
+	ID[8] = ID[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", ID[0:4], ID[4:6], ID[6:8], ID[8:10], ID[10:])
+}
+
+//WithProgress runs the request and renders the progress events of its execution live, followed by the summary
+//of the devices. The request is run alone when the progress is not enabled.
+func WithProgress(cfg *openAPI.Configuration, Enabled bool, Request func()) {
+	if !Enabled {
+		Request()
+		return
+	}
+	ExecutionID := NewExecutionID()
+	cfg.AddDefaultHeader(constants.ExecutionIDHeader, ExecutionID)
+	ctx, cancel := context.WithCancel(context.Background())
+	Progress := NewProgressTable(os.Stdout)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		StreamExecutionEvents(ctx, cfg, ExecutionID, Progress.Render)
+	}()
+
+	Request()
+
+	//The execution ends once its response is sent, its last events are read within the drain
+	select {
+	case <-done:
+	case <-time.After(ExecutionEventsDrain):
+	}
+	cancel()
+	<-done
+	delete(cfg.DefaultHeader, constants.ExecutionIDHeader)
+	Progress.Summary()
+}
+
+//StreamExecutionEvents reads the events of an execution from the Server-Sent Events of the server until the
+//execution ends or the context is done. The stream is opened again while the server does not know the
+//execution yet.
+func StreamExecutionEvents(ctx context.Context, cfg *openAPI.Configuration, ExecutionID string,
+	Handle func(openAPI.ExecutionEvent)) error {
+	Client := cfg.HTTPClient
+	if Client == nil {
+		Client = http.DefaultClient
+	}
+	for {
+		Request, err := http.NewRequest(http.MethodGet, cfg.BasePath+"/execution/"+ExecutionID+"/events", nil)
+		if err != nil {
+			return err
+		}
+		for Header, Value := range cfg.DefaultHeader {
+			Request.Header.Set(Header, Value)
+		}
+		Request.Header.Set("Accept", "text/event-stream")
+		Response, err := Client.Do(Request.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if Response.StatusCode == http.StatusNotFound {
+			Response.Body.Close()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+		defer Response.Body.Close()
+		if Response.StatusCode != http.StatusOK {
+			return fmt.Errorf("Streaming the events of the execution %s failed: %s", ExecutionID, Response.Status)
+		}
+		return readServerSentEvents(Response.Body, Handle)
+	}
+}
+
+//readServerSentEvents decodes the data of each event of the stream as an ExecutionEvent
+func readServerSentEvents(Body io.Reader, Handle func(openAPI.ExecutionEvent)) error {
+	Scanner := bufio.NewScanner(Body)
+	Scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var Data []string
+	for Scanner.Scan() {
+		Line := Scanner.Text()
+		if strings.HasPrefix(Line, "data:") {
+			Data = append(Data, strings.TrimPrefix(strings.TrimPrefix(Line, "data:"), " "))
+			continue
+		}
+		if len(Line) != 0 || len(Data) == 0 {
+			continue
+		}
+		var Event openAPI.ExecutionEvent
+		if err := json.Unmarshal([]byte(strings.Join(Data, "\n")), &Event); err == nil {
+			Handle(Event)
+		}
+		Data = nil
+	}
+	return Scanner.Err()
+}
+
+//ProgressTable renders the progress events of an execution as the rows of a table, and the last state of each
+//device as a summary
+type ProgressTable struct {
+	out     io.Writer
+	mutex   sync.Mutex
+	header  bool
+	devices []string
+	last    map[string]openAPI.ExecutionEvent
+	errors  map[string][]string
+}
+
+//NewProgressTable returns a ProgressTable writing to out
+func NewProgressTable(out io.Writer) *ProgressTable {
+	return &ProgressTable{out: out, last: make(map[string]openAPI.ExecutionEvent), errors: make(map[string][]string)}
+}
+
+const progressRowFormat = "%-8s  %-15s  %-20s  %-20s  %-9s  %s\n"
+
+//Render writes the row of a device event
+func (Table *ProgressTable) Render(Event openAPI.ExecutionEvent) {
+	if Event.Type_ != "device" {
+		return
+	}
+	Table.mutex.Lock()
+	defer Table.mutex.Unlock()
+	if !Table.header {
+		Table.header = true
+		fmt.Fprintf(Table.out, progressRowFormat, "Time", "Device", "Stage", "Step", "Status", "Message")
+	}
+	fmt.Fprintf(Table.out, progressRowFormat, Event.Time.Local().Format("15:04:05"), Event.Device, Event.Stage,
+		Event.Step, Event.Status, Event.Message)
+
+	if _, found := Table.last[Event.Device]; !found {
+		Table.devices = append(Table.devices, Event.Device)
+	}
+	//A failed stage remains the state of the device
+	if Table.last[Event.Device].Status != "failed" {
+		Table.last[Event.Device] = Event
+	}
+	for _, Error := range Event.Errors {
+		Table.errors[Event.Device] = append(Table.errors[Event.Device], Error.Message)
+	}
+}
+
+//Summary writes the last stage and status of each device
+func (Table *ProgressTable) Summary() {
+	Table.mutex.Lock()
+	defer Table.mutex.Unlock()
+	if len(Table.devices) == 0 {
+		return
+	}
+	fmt.Fprintln(Table.out, "")
+	table := tablewriter.NewWriter(Table.out)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Device", "Stage", "Status", "Errors"})
+	table.SetRowLine(true)
+	for _, Device := range Table.devices {
+		Event := Table.last[Device]
+		table.Append([]string{Device, Event.Stage, Event.Status, strings.Join(Table.errors[Device], "\n")})
+	}
+	table.Render()
+	fmt.Fprintln(Table.out, "")
+}
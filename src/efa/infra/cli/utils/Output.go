@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"io"
+	"os"
+)
+
+const (
+	//TableOutput renders the responses as tables, the default
+	TableOutput = "table"
+	//JSONOutput displays the REST models as JSON
+	JSONOutput = "json"
+	//YAMLOutput displays the REST models as YAML
+	YAMLOutput = "yaml"
+)
+
+//Exit codes of the CLI, any error not listed exits with ExitFailure
+const (
+	ExitFailure           = 1
+	ExitServerError       = 2
+	ExitServerUnreachable = 3
+)
+
+//OutputFormat is set by the --output flag of the show and list commands
+var OutputFormat = TableOutput
+
+//ExitError is returned by a command whose failure is already displayed, the CLI exits with its code
+type ExitError struct {
+	Code int
+}
+
+func (err *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", err.Code)
+}
+
+//AddOutputFlag adds the --output flag to a show or list command
+func AddOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&OutputFormat, "output", "o", TableOutput, "Output format <table|json|yaml>")
+}
+
+//ValidateOutput checks the value of the --output flag
+func ValidateOutput() error {
+	if OutputFormat != TableOutput && OutputFormat != JSONOutput && OutputFormat != YAMLOutput {
+		return errors.New("Wrong value for the flag \"output\", expected table, json or yaml")
+	}
+	return nil
+}
+
+//IsStructuredOutput checks whether the command displays the REST models instead of tables
+func IsStructuredOutput() bool {
+	return OutputFormat == JSONOutput || OutputFormat == YAMLOutput
+}
+
+//PrintModel displays a REST model as JSON or YAML, with the field names of the REST API
+func PrintModel(Model interface{}) error {
+	return printModel(os.Stdout, Model)
+}
+
+func printModel(w io.Writer, Model interface{}) error {
+	data, err := json.MarshalIndent(Model, "", "  ")
+	if err != nil {
+		return err
+	}
+	if OutputFormat == YAMLOutput {
+		//JSON being YAML, the document is decoded as such to keep the JSON field names
+		var Document interface{}
+		if err = yaml.Unmarshal(data, &Document); err != nil {
+			return err
+		}
+		if data, err = yaml.Marshal(Document); err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+//RequestError displays the failure of a REST request and returns the error the command exits with.
+//The table output is displayed by display, the JSON and YAML outputs display the error model of
+//the server on stderr.
+func RequestError(err error, display func(error)) error {
+	Code := ExitServerError
+	if isDialError(err) {
+		Code = ExitServerUnreachable
+	}
+	if !IsStructuredOutput() {
+		display(err)
+		return &ExitError{Code: Code}
+	}
+	var Model interface{} = map[string]interface{}{"message": err.Error()}
+	if ErrorModel, ok := ErrorModelOf(err); ok {
+		Model = ErrorModel
+	}
+	printModel(os.Stderr, Model)
+	return &ExitError{Code: Code}
+}
@@ -14,9 +14,17 @@ func TimedRunE(f RunE) RunE {
 	return func(cmd *cobra.Command, args []string) error {
 
 		defer func(t time.Time) {
-			fmt.Printf("--- Time Elapsed: %v ---\n", time.Since(t))
+			//The JSON and YAML outputs are left parseable
+			if !IsStructuredOutput() {
+				fmt.Printf("--- Time Elapsed: %v ---\n", time.Since(t))
+			}
 		}(time.Now())
 
-		return f(cmd, args)
+		err := f(cmd, args)
+		if _, displayed := err.(*ExitError); displayed {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		return err
 	}
 }
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"efa/infra/constants"
+	openAPIClient "efa/infra/rest/generated/client"
+	"os"
+	"os/user"
+)
+
+//AddUserHeader names the user running the command in the requests, the server records it in the settings history
+func AddUserHeader(cfg *openAPIClient.Configuration) {
+	if current, err := user.Current(); err == nil {
+		cfg.AddDefaultHeader(constants.UserNameHeader, current.Username)
+	} else if name := os.Getenv("USER"); name != "" {
+		cfg.AddDefaultHeader(constants.UserNameHeader, name)
+	}
+}
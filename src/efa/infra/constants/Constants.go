@@ -11,5 +11,15 @@ const (
 	AppInfoLocation   = "/var/" + ApplicationName + "/" + ApplicationName + "_appinfo.txt"
 	LogPathToArchove  = "/var/log/" + ApplicationName + "/"
 	DBLocation        = "/var/" + ApplicationName + "/" + ApplicationName + ".db"
+	//UserNameHeader carries the name of the user running the efa command
+	UserNameHeader = "X-Efa-User"
+	//ExecutionIDHeader carries the execution ID chosen by the command, to stream the events of the execution
+	ExecutionIDHeader = "X-Efa-Execution-Id"
+	//DefaultServer is the efa-server targeted without a context or --server
+	DefaultServer = "http://localhost:8081"
+	//ConfigFileEnv names the environment variable overriding the location of the contexts file
+	ConfigFileEnv = "EFACONFIG"
+	//ConfigFileName is the contexts file in the home directory of the user
+	ConfigFileName = "." + ApplicationName + "/config.yaml"
 	// TODO We might have to include the build number and version string here, instead of fetching from the server
 )
@@ -0,0 +1,29 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type AllocationPin struct {
+
+	// Management IP Address of the device
+	IpAddress string `json:"ip_address,omitempty"`
+
+	// Type of the pinned value
+	Type_ string `json:"type,omitempty"`
+
+	// Ethernet interface of the device the P2P IP is pinned to
+	InterfaceName string `json:"interface_name,omitempty"`
+
+	// Name of the pool the value is reserved in
+	PoolName string `json:"pool_name,omitempty"`
+
+	// Pinned value
+	Value string `json:"value,omitempty"`
+}
@@ -0,0 +1,29 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type AllocationPinRequest struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name"`
+
+	// Management IP Address of the device
+	IpAddress string `json:"ip_address"`
+
+	// Type of the pinned value
+	Type_ string `json:"type,omitempty"`
+
+	// ASN, Loopback IP or P2P IP to be pinned
+	Value string `json:"value,omitempty"`
+
+	// Ethernet interface of the device the P2P IP is pinned to
+	InterfaceName string `json:"interface_name,omitempty"`
+}
@@ -47,10 +47,22 @@ type APIClient struct {
 	ClearConfigApi	*ClearConfigApiService
 	ConfigShowApi	*ConfigShowApiService
 	ConfigureFabricApi	*ConfigureFabricApiService
+	DatabaseApi	*DatabaseApiService
+	DeviceAllocationApi	*DeviceAllocationApiService
+	DeviceMaintenanceApi	*DeviceMaintenanceApiService
+	DeviceReplaceApi	*DeviceReplaceApiService
+	DeviceSettingsApi	*DeviceSettingsApiService
 	ExecutionGetApi	*ExecutionGetApiService
 	ExecutionListApi	*ExecutionListApiService
 	FabricApi	*FabricApiService
+	FabricCablingApi	*FabricCablingApiService
+	FabricHealthApi	*FabricHealthApiService
+	FabricHistoryApi	*FabricHistoryApiService
+	FabricPoolsApi	*FabricPoolsApiService
+	FabricRefreshApi	*FabricRefreshApiService
+	FabricSettingsHistoryApi	*FabricSettingsHistoryApiService
 	FabricValidationApi	*FabricValidationApiService
+	NotificationApi	*NotificationApiService
 	SupportSaveApi	*SupportSaveApiService
 	SwitchApi	*SwitchApiService
 	SwitchesApi	*SwitchesApiService
@@ -75,10 +87,22 @@ func NewAPIClient(cfg *Configuration) *APIClient {
 	c.ClearConfigApi = (*ClearConfigApiService)(&c.common)
 	c.ConfigShowApi = (*ConfigShowApiService)(&c.common)
 	c.ConfigureFabricApi = (*ConfigureFabricApiService)(&c.common)
+	c.DatabaseApi = (*DatabaseApiService)(&c.common)
+	c.DeviceAllocationApi = (*DeviceAllocationApiService)(&c.common)
+	c.DeviceMaintenanceApi = (*DeviceMaintenanceApiService)(&c.common)
+	c.DeviceReplaceApi = (*DeviceReplaceApiService)(&c.common)
+	c.DeviceSettingsApi = (*DeviceSettingsApiService)(&c.common)
 	c.ExecutionGetApi = (*ExecutionGetApiService)(&c.common)
 	c.ExecutionListApi = (*ExecutionListApiService)(&c.common)
 	c.FabricApi = (*FabricApiService)(&c.common)
+	c.FabricCablingApi = (*FabricCablingApiService)(&c.common)
+	c.FabricHealthApi = (*FabricHealthApiService)(&c.common)
+	c.FabricHistoryApi = (*FabricHistoryApiService)(&c.common)
+	c.FabricPoolsApi = (*FabricPoolsApiService)(&c.common)
+	c.FabricRefreshApi = (*FabricRefreshApiService)(&c.common)
+	c.FabricSettingsHistoryApi = (*FabricSettingsHistoryApiService)(&c.common)
 	c.FabricValidationApi = (*FabricValidationApiService)(&c.common)
+	c.NotificationApi = (*NotificationApiService)(&c.common)
 	c.SupportSaveApi = (*SupportSaveApiService)(&c.common)
 	c.SwitchApi = (*SwitchApiService)(&c.common)
 	c.SwitchesApi = (*SwitchesApiService)(&c.common)
@@ -341,6 +365,41 @@ func reportError(format string, a ...interface{}) (error) {
 	return fmt.Errorf(format, a...)
 }
 
+// GenericSwaggerError Provides access to the body, error and model on returned errors.
+type GenericSwaggerError struct {
+	body  []byte
+	error string
+	model interface{}
+}
+
+// Error returns non-empty string if there was an error.
+func (e GenericSwaggerError) Error() string {
+	return e.error
+}
+
+// Body returns the raw bytes of the response
+func (e GenericSwaggerError) Body() []byte {
+	return e.body
+}
+
+// Model returns the unpacked ExtendedErrorModel of the response, nil when the body is not one
+func (e GenericSwaggerError) Model() interface{} {
+	return e.model
+}
+
+// newGenericSwaggerError returns the error of a failed response, the body being decoded as an ExtendedErrorModel
+func newGenericSwaggerError(status string, body []byte) GenericSwaggerError {
+	newErr := GenericSwaggerError{
+		body:  body,
+		error: fmt.Sprintf("Status: %v, Body: %s", status, body),
+	}
+	var v ExtendedErrorModel
+	if err := json.Unmarshal(body, &v); err == nil && len(v.Message) != 0 {
+		newErr.model = v
+	}
+	return newErr
+}
+
 // Set request body from an interface{}
 func setBody(body interface{}, contentType string) (bodyBuf *bytes.Buffer, err error) {
 	if bodyBuf == nil {
@@ -0,0 +1,32 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type ConfigChange struct {
+
+	// Management IP Address of the device
+	Device string `json:"device,omitempty"`
+
+	// Kind of the changed configuration
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the changed configuration
+	Name string `json:"name,omitempty"`
+
+	// Type of the change
+	Change string `json:"change,omitempty"`
+
+	// Value in the generation compared from
+	Old string `json:"old,omitempty"`
+
+	// Value in the generation compared to
+	New string `json:"new,omitempty"`
+}
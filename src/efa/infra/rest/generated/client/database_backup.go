@@ -0,0 +1,30 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type DatabaseBackup struct {
+
+	// Name of the backup
+	Name string `json:"name,omitempty"`
+
+	// Version of the database schema saved in the backup
+	SchemaVersion int32 `json:"schema_version,omitempty"`
+
+	// Time the backup was taken
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Size of the backup in bytes
+	Size int64 `json:"size,omitempty"`
+}
@@ -0,0 +1,22 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type DatabaseMigrationsResponse struct {
+
+	// Version of the last migration applied to the database
+	CurrentVersion int32 `json:"current_version,omitempty"`
+
+	// Version of the schema expected by the application
+	LatestVersion int32 `json:"latest_version,omitempty"`
+
+	Migrations []DatabaseMigration `json:"migrations,omitempty"`
+}
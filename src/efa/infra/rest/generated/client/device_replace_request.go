@@ -0,0 +1,29 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type DeviceReplaceRequest struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name"`
+
+	// Management IP Address of the device being replaced
+	OldIpAddress string `json:"old_ip_address"`
+
+	// Management IP Address of the replacement device
+	NewIpAddress string `json:"new_ip_address"`
+
+	// Username of the replacement device
+	Username string `json:"username,omitempty"`
+
+	// Password of the replacement device
+	Password string `json:"password,omitempty"`
+}
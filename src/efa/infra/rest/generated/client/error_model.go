@@ -14,5 +14,18 @@ type ErrorModel struct {
 
 	Message string `json:"message"`
 
+	// HTTP status of the failure
 	Code int32 `json:"code"`
+
+	// Machine-readable code of the failure
+	ErrorCode string `json:"error_code,omitempty"`
+
+	// Request parameter or setting which failed
+	Field string `json:"field,omitempty"`
+
+	// IP address of the device which failed
+	Device string `json:"device,omitempty"`
+
+	// ID of the execution of the request
+	ExecutionId string `json:"execution_id,omitempty"`
 }
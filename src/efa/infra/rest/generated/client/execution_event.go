@@ -0,0 +1,51 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type ExecutionEvent struct {
+
+	// Order of the event in the execution, starting at 1
+	Sequence int64 `json:"sequence,omitempty"`
+
+	// ID of the execution
+	ExecutionId string `json:"execution_id,omitempty"`
+
+	// Time of the event
+	Time time.Time `json:"time,omitempty"`
+
+	// execution for the start and the end of the execution, device for the progress of a device
+	Type_ string `json:"type,omitempty"`
+
+	// Command of the execution
+	Command string `json:"command,omitempty"`
+
+	// IP address of the device
+	Device string `json:"device,omitempty"`
+
+	// Stage of the device, discovery:interfaces, discovery:lldp, discovery:topology, discovery:neighbors, switch configure, mct cluster, overlay or persist
+	Stage string `json:"stage,omitempty"`
+
+	// Action of the stage the device is running
+	Step string `json:"step,omitempty"`
+
+	// running, succeeded or failed
+	Status string `json:"status,omitempty"`
+
+	// Description of the event
+	Message string `json:"message,omitempty"`
+
+	// Failures of the devices
+	Errors []NotificationDeviceError `json:"errors,omitempty"`
+}
@@ -0,0 +1,25 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type FabricPoolsResponse struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name,omitempty"`
+
+	// Utilization in percent above which a pool is reported
+	Threshold int32 `json:"threshold,omitempty"`
+
+	Pools []PoolUtilization `json:"pools,omitempty"`
+
+	// Pools whose utilization reached the threshold
+	PoolWarnings []string `json:"pool_warnings,omitempty"`
+}
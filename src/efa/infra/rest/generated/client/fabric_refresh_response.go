@@ -0,0 +1,26 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type FabricRefreshResponse struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name,omitempty"`
+
+	// Links discovered since the last discovery
+	AddedLinks []string `json:"added_links,omitempty"`
+
+	// Links no longer discovered
+	RemovedLinks []string `json:"removed_links,omitempty"`
+
+	// Interfaces which are now cabled to a different neighbor
+	MovedLinks []string `json:"moved_links,omitempty"`
+}
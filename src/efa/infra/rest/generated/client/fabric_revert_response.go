@@ -0,0 +1,25 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type FabricRevertResponse struct {
+
+	// Name of the fabric
+	FabricName string `json:"fabric_name,omitempty"`
+
+	// Configuration generation the fabric was reverted to
+	Generation int32 `json:"generation,omitempty"`
+
+	// Changes applied to the intended configuration
+	Changes []ConfigChange `json:"changes,omitempty"`
+
+	Configure *ConfigureFabricResponse `json:"configure,omitempty"`
+}
@@ -0,0 +1,39 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type FabricSettingChange struct {
+
+	// ID of the change, used to roll back to it
+	Id int32 `json:"id,omitempty"`
+
+	// Name of the changed setting
+	Setting string `json:"setting,omitempty"`
+
+	// Value before the change
+	OldValue string `json:"old_value,omitempty"`
+
+	// Value after the change
+	NewValue string `json:"new_value,omitempty"`
+
+	// User who requested the change
+	User string `json:"user,omitempty"`
+
+	// ID of the execution of the request which changed the setting
+	ExecutionId string `json:"execution_id,omitempty"`
+
+	// Time of the change
+	ChangedAt time.Time `json:"changed_at,omitempty"`
+}
@@ -33,4 +33,22 @@ type FabricValidateResponse struct {
 	MissingLinks []string `json:"missing_links,omitempty"`
 
 	ConfigurationDrifts *interface{} `json:"configuration_drifts,omitempty"`
+
+	// Allocation pools whose utilization reached the warning threshold
+	PoolWarnings []string `json:"pool_warnings,omitempty"`
+
+	// Values pinned to the devices which cannot be honoured
+	PinConflicts []string `json:"pin_conflicts,omitempty"`
+
+	// Ports connected to another port than the one of the cabling plan
+	MiscabledLinks []string `json:"miscabled_links,omitempty"`
+
+	// Links of the cabling plan neither of whose ports is connected
+	MissingPlannedLinks []string `json:"missing_planned_links,omitempty"`
+
+	// Links discovered by LLDP which are not in the cabling plan
+	UnexpectedLinks []string `json:"unexpected_links,omitempty"`
+
+	// Findings of the pre-flight checks of the devices
+	PreflightFindings []PreflightFinding `json:"preflight_findings,omitempty"`
 }
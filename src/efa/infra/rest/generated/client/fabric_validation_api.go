@@ -31,8 +31,10 @@ type FabricValidationApiService service
  Validate Fabric settings, cabling between switches and potentially configurations on switches for IP Fabric formation
  * @param ctx context.Context for authentication, logging, tracing, etc.
  @param fabricName Name of the fabric to validate
+ @param optional (nil or map[string]interface{}) with one or more of:
+     @param "preflightOverride" (string) Comma separated pre-flight checks whose errors do not fail the validation, among connection, speed, mtu, firmware, admin-down, ip-config and local-as
  @return FabricValidateResponse*/
-func (a *FabricValidationApiService) ValidateFabric(ctx context.Context, fabricName string) (FabricValidateResponse,  *http.Response, error) {
+func (a *FabricValidationApiService) ValidateFabric(ctx context.Context, fabricName string, localVarOptionals map[string]interface{}) (FabricValidateResponse,  *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody interface{}
@@ -48,8 +50,14 @@ func (a *FabricValidationApiService) ValidateFabric(ctx context.Context, fabricN
 	localVarQueryParams := url.Values{}
 	localVarFormParams := url.Values{}
 
+	if err := typeCheckParameter(localVarOptionals["preflightOverride"], "string", "preflightOverride"); err != nil {
+		return successPayload, nil, err
+	}
 
 	localVarQueryParams.Add("fabric_name", parameterToString(fabricName, ""))
+	if localVarTempParam, localVarOk := localVarOptionals["preflightOverride"].(string); localVarOk {
+		localVarQueryParams.Add("preflight_override", parameterToString(localVarTempParam, ""))
+	}
 	// to determine the Content-Type header
 	localVarHttpContentTypes := []string{  }
 
@@ -80,7 +88,7 @@ func (a *FabricValidationApiService) ValidateFabric(ctx context.Context, fabricN
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
 		bodyBytes, _ := ioutil.ReadAll(localVarHttpResponse.Body)
-		return successPayload, localVarHttpResponse, reportError("Status: %v, Body: %s", localVarHttpResponse.Status, bodyBytes)
+		return successPayload, localVarHttpResponse, newGenericSwaggerError(localVarHttpResponse.Status, bodyBytes)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
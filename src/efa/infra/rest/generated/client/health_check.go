@@ -0,0 +1,23 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type HealthCheck struct {
+
+	// Name of the health check
+	Name string `json:"name,omitempty"`
+
+	// Result of the health check
+	Status string `json:"status,omitempty"`
+
+	// Failure of the health check, or the state checked when it passed
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,51 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type NotificationEvent struct {
+
+	// ID of the event, the X-Efa-Delivery header carries it on each retry
+	Id string `json:"id,omitempty"`
+
+	// Name of the event, the X-Efa-Event header carries it
+	Event string `json:"event,omitempty"`
+
+	// Time of the event
+	Time time.Time `json:"time,omitempty"`
+
+	// Name of the fabric
+	Fabric string `json:"fabric,omitempty"`
+
+	// ID of the execution
+	ExecutionId string `json:"execution_id,omitempty"`
+
+	// Command of the execution
+	Command string `json:"command,omitempty"`
+
+	// Status of the execution
+	Status string `json:"status,omitempty"`
+
+	// IP address of the device the event is about
+	Device string `json:"device,omitempty"`
+
+	// Description of the event
+	Message string `json:"message,omitempty"`
+
+	// Links found missing by the validation
+	MissingLinks []string `json:"missing_links,omitempty"`
+
+	// Failures of the devices
+	Errors []NotificationDeviceError `json:"errors,omitempty"`
+}
@@ -0,0 +1,36 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+import (
+	"time"
+)
+
+type NotificationSubscription struct {
+
+	// ID of the subscription
+	Id int32 `json:"id,omitempty"`
+
+	// URL the events are posted to
+	Url string `json:"url,omitempty"`
+
+	// Events of the subscription, all the events when empty
+	Events []string `json:"events,omitempty"`
+
+	// Whether the events are signed with a secret
+	Signed bool `json:"signed,omitempty"`
+
+	// Retries of a failed delivery
+	Retries int32 `json:"retries,omitempty"`
+
+	// Time of the subscription
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
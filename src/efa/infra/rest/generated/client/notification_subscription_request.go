@@ -0,0 +1,26 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type NotificationSubscriptionRequest struct {
+
+	// http or https URL the events are posted to
+	Url string `json:"url,omitempty"`
+
+	// Secret signing the events, the X-Efa-Signature header carries sha256= followed by the hex HMAC-SHA256 of the body
+	Secret string `json:"secret,omitempty"`
+
+	// Events of the subscription, all the events when empty
+	Events []string `json:"events,omitempty"`
+
+	// Retries of a failed delivery, at most 10
+	Retries int32 `json:"retries,omitempty"`
+}
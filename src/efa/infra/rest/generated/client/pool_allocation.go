@@ -0,0 +1,23 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type PoolAllocation struct {
+
+	// Allocated ASN or IP Address
+	Value string `json:"value,omitempty"`
+
+	// Management IP Address of the device holding the value
+	DeviceIp string `json:"device_ip,omitempty"`
+
+	// Interface holding the value
+	InterfaceName string `json:"interface_name,omitempty"`
+}
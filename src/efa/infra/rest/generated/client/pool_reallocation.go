@@ -0,0 +1,32 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type PoolReallocation struct {
+
+	// Type of the pool
+	PoolType string `json:"pool_type,omitempty"`
+
+	// Name of the pool
+	PoolName string `json:"pool_name,omitempty"`
+
+	// Updated range of the pool, empty when the pool is no longer used
+	Range string `json:"range,omitempty"`
+
+	// ASN or IP Address to be reallocated
+	Value string `json:"value,omitempty"`
+
+	// Management IP Address of the device holding the value
+	DeviceIp string `json:"device_ip,omitempty"`
+
+	// Interface holding the value
+	InterfaceName string `json:"interface_name,omitempty"`
+}
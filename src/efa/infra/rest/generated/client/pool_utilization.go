@@ -0,0 +1,34 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type PoolUtilization struct {
+
+	// Type of the pool
+	PoolType string `json:"pool_type,omitempty"`
+
+	// Name of the pool
+	PoolName string `json:"pool_name,omitempty"`
+
+	// Range of the pool in the fabric settings
+	Range string `json:"range,omitempty"`
+
+	// Number of values allocated from the pool
+	Used int64 `json:"used,omitempty"`
+
+	// Number of values available in the pool
+	Free int64 `json:"free,omitempty"`
+
+	// Utilization of the pool in percent
+	Utilization int32 `json:"utilization,omitempty"`
+
+	Allocations []PoolAllocation `json:"allocations,omitempty"`
+}
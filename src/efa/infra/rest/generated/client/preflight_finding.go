@@ -0,0 +1,32 @@
+/*
+ * Simplified IP Fabric
+ *
+ * This is the spec that defines the API provided by the application to register devices to a fabric, configure fabric parameters, validate all the devices in the fabric and configure switches for IP Fabric with/without overlay
+ *
+ * API version: 1.0
+ * Contact: support@extremenetworks.com
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package swagger
+
+type PreflightFinding struct {
+
+	// Pre-flight check which reported the finding
+	Check string `json:"check,omitempty"`
+
+	// An error fails the validation unless its check is overridden
+	Severity string `json:"severity,omitempty"`
+
+	// Management IP Address of the device, empty when the finding is not about a single device
+	Device string `json:"device,omitempty"`
+
+	// Ethernet interface of the device, empty when the finding is not about a single interface
+	Interface string `json:"interface,omitempty"`
+
+	// Description of the finding
+	Message string `json:"message,omitempty"`
+
+	// The check of the finding is overridden
+	Overridden bool `json:"overridden,omitempty"`
+}
@@ -2,6 +2,10 @@ package integration
 
 import (
 	"efa/infra/cli"
+	"efa/infra/cli/utils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"testing"
 
@@ -16,10 +20,11 @@ func TestTopHelp(t *testing.T) {
 	output, err := executeCommand(rootCmd, "--help")
 
 	assert.Nil(t, err)
-	assert.Contains(t, output, "fabric      Fabric commands")
-	assert.Contains(t, output, "execution   Execution commands")
-	assert.Contains(t, output, "debug       Debug commands")
-	assert.Contains(t, output, "help        Help about any command")
+	assert.Contains(t, output, "fabric       Fabric commands")
+	assert.Contains(t, output, "execution    Execution commands")
+	assert.Contains(t, output, "debug        Debug commands")
+	assert.Contains(t, output, "help         Help about any command")
+	assert.Contains(t, output, "notification Notification commands")
 
 }
 
@@ -32,6 +37,7 @@ func TestFabricHelp(t *testing.T) {
 	assert.Contains(t, output, "deconfigure Deconfigure IP Fabric from the device")
 	assert.Contains(t, output, "setting     IP Fabric setting commands")
 	assert.Contains(t, output, "show-config Display IP Fabric config")
+	assert.Contains(t, output, "apply       Update the settings and the devices of the IP Fabric to match a fabric spec file")
 }
 
 func TestExecutionHelp(t *testing.T) {
@@ -41,6 +47,10 @@ func TestExecutionHelp(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Contains(t, output, constants.ApplicationName+" execution [command] --help")
 
+	//The command is looked up rather than run with --help, which would stay set for the next runs
+	ShowCommand, _, err := rootCmd.Find([]string{"execution", "show"})
+	assert.Nil(t, err)
+	assert.NotNil(t, ShowCommand.Flags().Lookup("output"))
 }
 func TestFabricSettingsHelp(t *testing.T) {
 	rootCmd := cli.GetRootCommand()
@@ -66,3 +76,91 @@ func TestDebugHelp(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Contains(t, output, "clear-config Clear configuration from device")
 }
+
+func TestContextHelp(t *testing.T) {
+	rootCmd := cli.GetRootCommand()
+	output, err := executeCommand(rootCmd, "context", "--help")
+
+	assert.Nil(t, err)
+	assert.Contains(t, output, "use         Select the context used by the commands")
+	assert.Contains(t, output, "--server string")
+	assert.Contains(t, output, "--context string")
+}
+
+func TestConfigureHelpWithoutServer(t *testing.T) {
+	rootCmd := cli.GetRootCommand()
+	output, err := executeCommand(rootCmd, "fabric", "configure", "--server", "http://127.0.0.1:1", "--help")
+
+	assert.Nil(t, err)
+	assert.Contains(t, output, "--spine string")
+	assert.Contains(t, output, "--rack stringArray")
+}
+
+func TestContextSetUse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "efa-context")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	os.Setenv(constants.ConfigFileEnv, filepath.Join(dir, "config.yaml"))
+	defer os.Unsetenv(constants.ConfigFileEnv)
+
+	_, err = executeCommand(cli.GetRootCommand(), "context", "set", "lab", "--server", "http://lab:8081", "--fabric", "lab")
+	assert.Nil(t, err)
+	_, err = executeCommand(cli.GetRootCommand(), "context", "set", "prod", "--server", "http://prod:8081", "--fabric", "prod")
+	assert.Nil(t, err)
+	_, err = executeCommand(cli.GetRootCommand(), "context", "use", "prod")
+	assert.Nil(t, err)
+
+	Current, err := utils.CurrentContext()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://prod:8081", Current.Server)
+	assert.Equal(t, "prod", Current.Fabric)
+	cfg, err := utils.NewAPIConfiguration()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://prod:8081/v1", cfg.BasePath)
+
+	_, err = executeCommand(cli.GetRootCommand(), "context", "use", "staging")
+	assert.NotNil(t, err)
+	_, err = executeCommand(cli.GetRootCommand(), "version", "--context", "staging")
+	assert.NotNil(t, err)
+	utils.ContextFlag = ""
+}
+
+func TestContextInvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "efa-context")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	os.Setenv(constants.ConfigFileEnv, filepath.Join(dir, "config.yaml"))
+	defer os.Unsetenv(constants.ConfigFileEnv)
+
+	cfg, err := utils.NewAPIConfiguration()
+	assert.Nil(t, err)
+	assert.Equal(t, constants.DefaultServer+"/v1", cfg.BasePath)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte("current-context: [prod"), 0600))
+	cfg, err = utils.NewAPIConfiguration()
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "Invalid contexts file")
+}
+
+func TestOutputFlag(t *testing.T) {
+	defer func() { utils.OutputFormat, utils.ServerFlag = utils.TableOutput, "" }()
+
+	_, err := executeCommand(cli.GetRootCommand(), "fabric", "show", "--output", "xml")
+	assert.NotNil(t, err)
+
+	//Nothing listens on the port, the command exits as the server is unreachable
+	_, err = executeCommand(cli.GetRootCommand(), "fabric", "show", "--server", "http://127.0.0.1:1", "--output", "json")
+	exitError, ok := err.(*utils.ExitError)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, utils.ExitServerUnreachable, exitError.Code)
+	}
+
+	utils.OutputFormat = utils.TableOutput
+	_, err = executeCommand(cli.GetRootCommand(), "execution", "show", "--server", "http://127.0.0.1:1")
+	exitError, ok = err.(*utils.ExitError)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, utils.ExitServerUnreachable, exitError.Code)
+	}
+}